@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleAnnotateCommand(args []string) error {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	author := fs.String("author", "", "Who is annotating (default $USER)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("annotate requires a beat ID and a note")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Annotate(fs.Arg(0), strings.Join(fs.Args()[1:], " "), *author)
+}
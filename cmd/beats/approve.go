@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleApproveCommand(args []string) error {
+	fs := flag.NewFlagSet("approve", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	all := fs.Bool("all", false, "Approve every staged robot commit")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	var ids []string
+	for _, arg := range fs.Args() {
+		ids = append(ids, splitIDs(arg)...)
+	}
+	return cli.NewHumanCLI(jsonStore).Approve(ids, *all, *robot)
+}
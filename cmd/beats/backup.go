@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const backupUsage = `usage: bt backup [create|list|verify] [--robot]`
+
+func handleBackupCommand(args []string) error {
+	subcmd := "create"
+	if len(args) > 0 && (args[0] == "create" || args[0] == "list" || args[0] == "verify") {
+		subcmd, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("backup "+subcmd, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unknown backup subcommand: %s\n%s", fs.Arg(0), backupUsage)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+	switch subcmd {
+	case "list":
+		return humanCLI.BackupList(*robot)
+	case "verify":
+		return humanCLI.BackupVerify(*robot)
+	default:
+		return humanCLI.BackupCreate(*robot)
+	}
+}
+
+func handleRestoreCommand(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	at := fs.String("at", "", "Moment to restore to (ISO8601 or relative: yesterday, 3h ago)")
+	dryRun := fs.Bool("dry-run", false, "Rebuild and verify without replacing the store")
+	force := fs.Bool("force", false, "Skip the confirmation prompt")
+	robot := fs.Bool("robot", false, "Output JSON (implies --force)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *at == "" {
+		return fmt.Errorf("usage: bt restore --at <timestamp> [--dry-run] [--force] [--robot]")
+	}
+	when, err := clock.ParseRelative(*at)
+	if err != nil {
+		return fmt.Errorf("invalid --at: %w", err)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Restore(cli.RestoreOptions{
+		At:     when,
+		DryRun: *dryRun,
+		Force:  *force,
+		JSON:   *robot,
+	})
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const beadsUsage = `usage: bt beads sync [--dry-run] [--robot]
+       bt beads refresh [--robot]
+       bt beads remap [--dry-run] [--robot] <old-id> <new-id>`
+
+func handleBeadsCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("beads requires a command\n%s", beadsUsage)
+	}
+	sub, rest := args[0], args[1:]
+
+	fs := flag.NewFlagSet("beads "+sub, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	dryRun := fs.Bool("dry-run", false, "Report changes without writing them")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+
+	switch sub {
+	case "sync":
+		return humanCLI.BeadsSync(cli.BeadsSyncOptions{DryRun: *dryRun, JSON: *robot})
+	case "refresh":
+		return humanCLI.BeadsRefresh(*robot)
+	case "remap":
+		if fs.NArg() != 2 {
+			return fmt.Errorf("remap requires the old and the new bead ID\n%s", beadsUsage)
+		}
+		return humanCLI.BeadsRemap(fs.Arg(0), fs.Arg(1), *dryRun, *robot)
+	default:
+		return fmt.Errorf("unknown beads command: %s\n%s", sub, beadsUsage)
+	}
+}
+
+func handleCoverageCommand(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	by := fs.String("by", "month", "Trend period: month or week")
+	periods := fs.Int("periods", 6, "Trend periods to show")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Coverage(cli.CoverageOptions{
+		By:      *by,
+		Periods: *periods,
+		JSON:    *robot,
+	})
+}
+
+func handlePromoteCommand(args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	title := fs.String("title", "", "Title of the new bead")
+	description := fs.String("description", "", "Opening paragraph, above the seed beats")
+	beadType := fs.String("type", "epic", "Bead type (epic, task, feature...)")
+	id := fs.String("id", "", "Link to this existing bead instead of creating one")
+	markdown := fs.Bool("markdown", false, "Write a markdown draft instead of calling the provider")
+	output := fs.String("o", "", "Markdown draft file (default: stdout)")
+	dryRun := fs.Bool("dry-run", false, "Show the draft without creating or linking")
+
+	// Beat IDs may come before or after the flags
+	var beatIDs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		beatIDs = append(beatIDs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Promote(beatIDs, cli.PromoteOptions{
+		Title:       *title,
+		Description: *description,
+		Type:        *beadType,
+		ID:          *id,
+		Markdown:    *markdown,
+		Output:      *output,
+		DryRun:      *dryRun,
+	})
+}
+
+func handleLineageCommand(args []string) error {
+	fs := flag.NewFlagSet("lineage", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	robot := fs.Bool("robot", false, "Output JSON")
+
+	// The bead ID may come before or after the flags
+	var beadID string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		if beadID != "" {
+			return fmt.Errorf("usage: bt lineage <bead-id> [--robot]")
+		}
+		beadID = fs.Arg(0)
+		args = fs.Args()[1:]
+	}
+	if beadID == "" {
+		return fmt.Errorf("usage: bt lineage <bead-id> [--robot]")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Lineage(beadID, *robot)
+}
+
+func handleMapCommand(args []string) error {
+	fs := flag.NewFlagSet("map", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	format := fs.String("format", "mermaid", "Output format: mermaid or dot")
+	maxBeats := fs.Int("max", 5, "Seed beats drawn per bead")
+	output := fs.String("o", "", "Write to a file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Map(cli.MapOptions{
+		Format:   *format,
+		MaxBeats: *maxBeats,
+		Output:   *output,
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleByProjectCommand(args []string) error {
+	fs := flag.NewFlagSet("by-project", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	waldDir := fs.String("wald", "", "Only this WALD directory and its subdirectories")
+	max := fs.Int("max", 3, "Recent beats shown per directory")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).ByProject(cli.ByProjectOptions{
+		Wald: *waldDir,
+		Max:  *max,
+		JSON: *robot,
+	})
+}
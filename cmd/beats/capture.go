@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const captureUsage = `usage: bt capture x <url> [note]
+       bt capture hn [--comments N] <item-url-or-id> [note]
+       bt capture pdf <path-or-url> [note]
+       bt capture arxiv <id-or-url> [note]
+       bt capture github-stars [--dry-run] <user>
+       bt capture github-issue <owner/repo#N> [note]
+       bt capture podcast [--episode Q] [--no-transcribe] <feed-or-episode-url> [note]`
+
+func handleCaptureCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("capture requires a source\n%s", captureUsage)
+	}
+	source, rest := args[0], args[1:]
+
+	fs := flag.NewFlagSet("capture "+source, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	comments := fs.Int("comments", 0, "Top-level comments to include (hn)")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing (github-stars)")
+	episode := fs.String("episode", "", "Episode GUID, URL or title words (podcast feeds; default newest)")
+	noTranscribe := fs.Bool("no-transcribe", false, "Don't run Whisper when the feed has no transcript (podcast)")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("capture %s requires an argument\n%s", source, captureUsage)
+	}
+	target := fs.Arg(0)
+	note := strings.Join(fs.Args()[1:], " ")
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+
+	switch source {
+	case "x", "twitter":
+		return humanCLI.CaptureX(target, note)
+	case "hn":
+		return humanCLI.CaptureHN(target, *comments, note)
+	case "pdf":
+		return humanCLI.CapturePDF(target, note)
+	case "arxiv":
+		return humanCLI.CaptureArxiv(target, note)
+	case "github-stars":
+		return humanCLI.CaptureGitHubStars(target, *dryRun)
+	case "github-issue", "github-pr":
+		return humanCLI.CaptureGitHubIssue(target, note)
+	case "podcast":
+		return humanCLI.CapturePodcast(target, *episode, note, !*noTranscribe)
+	default:
+		return fmt.Errorf("unknown capture source: %s\n%s", source, captureUsage)
+	}
+}
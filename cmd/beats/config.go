@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/config"
+)
+
+const configUsage = `usage: bt config show [--robot]
+       bt config path`
+
+func handleConfigCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("config requires a subcommand\n%s", configUsage)
+	}
+	fs := flag.NewFlagSet("config "+args[0], flag.ExitOnError)
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "show":
+		return cli.ConfigShow(*robot)
+	case "path":
+		fmt.Println(config.Path())
+		return nil
+	default:
+		return fmt.Errorf("unknown config subcommand: %s\n%s", args[0], configUsage)
+	}
+}
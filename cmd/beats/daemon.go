@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/daemon"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleDaemonCommand(args []string) error {
+	subcmd := "run"
+	if len(args) > 0 && args[0] == "status" {
+		subcmd, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	interval := fs.Duration("interval", 2*time.Second, "How often to check beats.jsonl for changes")
+	noEmbed := fs.Bool("no-embed", false, "Only maintain the SQLite index")
+	model := fs.String("model", "", "Embedding model (default: embed_model in the config)")
+	socket := fs.Bool("socket", false, "Be the single writer: other bt processes forward writes to .beats/daemon.sock")
+	metricsAddr := fs.String("metrics-addr", "", "Serve /healthz and /metrics on this address (e.g. 127.0.0.1:9477)")
+	digestDaily := fs.Bool("digest", false, "Store a digest beat for each finished day")
+	noSnapshot := fs.Bool("no-snapshot", false, "Do not append a daily snapshot of store metrics to .beats/metrics.jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	if subcmd == "status" {
+		if pid, ok := daemon.RunningPID(jsonStore.Dir()); ok {
+			fmt.Printf("Daemon running (pid %d) for %s\n", pid, jsonStore.Dir())
+			if daemon.Serving(jsonStore.Dir()) {
+				fmt.Printf("Accepting writes on %s\n", jsonStore.SocketPath())
+			}
+		} else {
+			fmt.Printf("No daemon running for %s\n", jsonStore.Dir())
+		}
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "beats daemon: ", log.LstdFlags)
+	var daily func()
+	if *digestDaily || !*noSnapshot {
+		humanCLI := cli.NewHumanCLI(jsonStore)
+		daily = func() {
+			if !*noSnapshot {
+				if _, err := cli.SaveStatsSnapshot(jsonStore); err != nil {
+					logger.Printf("stats snapshot failed: %v", err)
+				}
+			}
+			if !*digestDaily {
+				return
+			}
+			digest, err := humanCLI.DigestYesterday()
+			switch {
+			case err != nil:
+				logger.Printf("digest failed: %v", err)
+			case digest != nil && digest.Skipped == "":
+				logger.Printf("digest: stored %s for %s (%d beats)", digest.ID, digest.Day, len(digest.Sources))
+			}
+		}
+	}
+	d := daemon.New(jsonStore, daemon.Options{
+		Interval: *interval,
+		Embed:    !*noEmbed,
+		Model:    *model,
+		Socket:   *socket,
+		Metrics:  *metricsAddr,
+		Daily:    daily,
+		Location: cli.DisplayLocation,
+	}, logger)
+	return d.Run(ctx)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleDigestCommand(args []string) error {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	date := fs.String("date", "", "Day to digest: YYYY-MM-DD, today or yesterday (default: yesterday)")
+	since := fs.String("since", "", "Digest every finished day since this date (e.g. 7d, 2026-01-01)")
+	commit := fs.Bool("commit", false, "Store the digests as beats (default: preview)")
+	minBeats := fs.Int("min", 2, "Skip days with fewer beats")
+	format := fs.String("format", "text", "Output format: text or html")
+	output := fs.String("o", "", "Write the HTML page to a file (default: stdout)")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *date != "" && *since != "" {
+		return fmt.Errorf("use --date or --since, not both")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Digest(cli.DigestOptions{
+		Date:     *date,
+		Since:    *since,
+		Commit:   *commit,
+		MinBeats: *minBeats,
+		Format:   *format,
+		Output:   *output,
+		JSON:     *robot,
+	})
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleDoctorCommand(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	fix := fs.Bool("fix", false, "Move malformed lines to .beats/quarantine.jsonl")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Doctor(cli.DoctorOptions{Fix: *fix, JSON: *robot})
+}
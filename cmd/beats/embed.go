@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleEmbedCommand(args []string) error {
+	fs := flag.NewFlagSet("embed", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	model := fs.String("model", "", "Ollama embedding model (default: the store's model)")
+	batch := fs.Int("batch", 32, "Beats per embedding request")
+	rate := fs.Float64("rate", 0, "Maximum beats embedded per second (0 = unlimited)")
+	migrate := fs.String("migrate", "", "Re-embed all beats with a new model and switch over")
+	keepOld := fs.Bool("keep-old", false, "Keep the previous index after --migrate")
+	compact := fs.Bool("compact", false, "Rewrite the index keeping only current beats")
+	export := fs.String("export", "", "Export vectors to a .jsonl, .npy or .parquet file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Embed(cli.EmbedOptions{
+		Model:     *model,
+		BatchSize: *batch,
+		Rate:      *rate,
+		Migrate:   *migrate,
+		KeepOld:   *keepOld,
+		Compact:   *compact,
+		Export:    *export,
+	})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleEntitiesCommand(args []string) error {
+	if len(args) == 0 || args[0] != "graph" {
+		return fmt.Errorf("usage: bt entities graph [--format mermaid|json] [--window 30d] [--min N] [--max N] [-o file]")
+	}
+	fs := flag.NewFlagSet("entities graph", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	format := fs.String("format", "mermaid", "Output format: mermaid or json")
+	window := fs.String("window", "30d", "Pairs first seen within this window are emerging")
+	minCount := fs.Int("min", 2, "Leave out pairs named together in fewer beats")
+	maxPairs := fs.Int("max", 40, "Most pairs to show")
+	output := fs.String("o", "", "Write to a file (default: stdout)")
+	robot := fs.Bool("robot", false, "Output JSON (same as --format json)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *robot {
+		*format = "json"
+	}
+	w, err := cli.ParseWindow(*window)
+	if err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).EntityGraph(cli.EntityGraphOptions{
+		Window:   w,
+		MinCount: *minCount,
+		Max:      *maxPairs,
+		Format:   *format,
+		Output:   *output,
+	})
+}
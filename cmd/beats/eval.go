@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const evalUsage = `usage: bt eval search --cases <cases.yaml> [--k N] [--modes keyword,semantic,hybrid] [--robot]`
+
+func handleEvalCommand(args []string) error {
+	if len(args) == 0 || args[0] != "search" {
+		return fmt.Errorf("eval requires a subcommand\n%s", evalUsage)
+	}
+	fs := flag.NewFlagSet("eval search", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	casesPath := fs.String("cases", "", "YAML file mapping queries to the beat IDs they should find")
+	k := fs.Int("k", 0, fmt.Sprintf("Rank cutoff for precision and recall (default: the file's k, else %d)", cli.DefaultEvalK))
+	modes := fs.String("modes", strings.Join(cli.EvalModes, ","), "Retrieval modes to compare")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *casesPath == "" {
+		return fmt.Errorf("--cases is required\n%s", evalUsage)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	var list []string
+	for _, m := range strings.Split(*modes, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			list = append(list, m)
+		}
+	}
+	return cli.NewHumanCLI(jsonStore).EvalSearch(*casesPath, *k, list, *robot)
+}
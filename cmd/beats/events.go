@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const eventsUsage = `usage: bt events replay --since <time> --target <hook|webhook> [--url URL] [--event NAME] [--dry-run] [--robot]`
+
+func handleEventsCommand(args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return fmt.Errorf("%s", eventsUsage)
+	}
+	fs := flag.NewFlagSet("events replay", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	since := fs.String("since", "", "Replay events from this moment (ISO8601 or relative: yesterday, 3d ago)")
+	target := fs.String("target", "", "Where to send them: hook (the on_event script) or webhook")
+	url := fs.String("url", "", "Webhook URL (default: the on_event url in hooks.json)")
+	event := fs.String("event", "", "Only these events, comma-separated: beat_added, beat_linked")
+	dryRun := fs.Bool("dry-run", false, "List the events without sending them")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *since == "" || *target == "" || fs.NArg() > 0 {
+		return fmt.Errorf("%s", eventsUsage)
+	}
+	when, err := clock.ParseRelative(*since)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	var events []string
+	for _, e := range strings.Split(*event, ",") {
+		switch e = strings.TrimSpace(e); e {
+		case "":
+		case hooks.EventBeatAdded, hooks.EventBeatLinked:
+			events = append(events, e)
+		default:
+			return fmt.Errorf("unknown event %q (use %s or %s)", e, hooks.EventBeatAdded, hooks.EventBeatLinked)
+		}
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).EventsReplay(cli.EventsReplayOptions{
+		Since:  when,
+		Target: *target,
+		URL:    *url,
+		Events: events,
+		DryRun: *dryRun,
+		JSON:   *robot,
+	})
+}
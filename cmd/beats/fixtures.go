@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/fixtures"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleFixturesCommand(args []string) error {
+	fs := flag.NewFlagSet("fixtures", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory (must be empty)")
+	seed := fs.Int64("seed", 1, "Random seed; the same seed gives the same store")
+	count := fs.Int("count", fixtures.DefaultCount, "Beats to generate")
+	days := fs.Int("days", fixtures.DefaultDays, "Days to spread the beats over")
+	start := fs.String("start", fixtures.DefaultStart.Format("2006-01-02"), "First day, YYYY-MM-DD (UTC)")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	startDay, err := time.Parse("2006-01-02", *start)
+	if err != nil {
+		return fmt.Errorf("invalid --start %q (use YYYY-MM-DD)", *start)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Fixtures(fixtures.Options{
+		Seed:  *seed,
+		Count: *count,
+		Start: startDay,
+		Days:  *days,
+	}, *robot)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleGCCommand(args []string) error {
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	grace := fs.Duration("grace", 24*time.Hour, "Keep unreferenced blobs newer than this")
+	dryRun := fs.Bool("dry-run", false, "Report without removing")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).GC(cli.GCOptions{Grace: *grace, DryRun: *dryRun}, *robot)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/importer"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleImportObsidianCommand(args []string) error {
+	fs := flag.NewFlagSet("import-obsidian", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	folders := multiFlag{}
+	fs.Var(&folders, "folder", "Only import notes under this vault folder (repeatable)")
+	tags := multiFlag{}
+	fs.Var(&tags, "tag", "Only import notes with this tag (repeatable)")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bt import-obsidian [--folder F] [--tag T] [--dry-run] <vault>")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportObsidian(fs.Arg(0), importer.ObsidianOptions{
+		Folders: folders,
+		Tags:    tags,
+	}, *dryRun)
+}
+
+func handleImportMarkdownCommand(args []string) error {
+	fs := flag.NewFlagSet("import-md", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	recursive := fs.Bool("recursive", false, "Include subdirectories")
+	splitHeadings := fs.Bool("split-headings", false, "One beat per ## section instead of per file")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bt import-md [--recursive] [--split-headings] [--dry-run] <dir>")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportMarkdown(fs.Arg(0), importer.MarkdownOptions{
+		Recursive:     *recursive,
+		SplitHeadings: *splitHeadings,
+	}, *dryRun)
+}
+
+// handleImportOutlineCommand handles import-logseq and import-roam.
+func handleImportOutlineCommand(cmd string, args []string) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	blocks := fs.Bool("blocks", false, "One beat per top-level block instead of per page")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		if cmd == "import-roam" {
+			return fmt.Errorf("usage: bt import-roam [--blocks] [--dry-run] <export.json>")
+		}
+		return fmt.Errorf("usage: bt import-logseq [--blocks] [--dry-run] <graph-dir>")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+	opts := importer.OutlineOptions{Blocks: *blocks}
+	if cmd == "import-roam" {
+		return humanCLI.ImportRoam(fs.Arg(0), opts, *dryRun)
+	}
+	return humanCLI.ImportLogseq(fs.Arg(0), opts, *dryRun)
+}
+
+func handleImportAppleNotesCommand(args []string) error {
+	fs := flag.NewFlagSet("import-apple-notes", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	folder := fs.String("folder", "", "Only import this Notes folder")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportAppleNotes(importer.AppleNotesOptions{Folder: *folder}, *dryRun)
+}
+
+func handleImportGitCommand(args []string) error {
+	fs := flag.NewFlagSet("import-git", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	since := fs.String("since", "", "Only commits after this date (anything git understands)")
+	until := fs.String("until", "", "Only commits before this date")
+	author := fs.String("author", "", "Only commits by this author")
+	max := fs.Int("max", 0, "Only the most recent N commits")
+	summarize := fs.Bool("summarize", false, "One LLM-summarized beat per day instead of one per commit")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	repo := "."
+	if fs.NArg() > 0 {
+		repo = fs.Arg(0)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportGit(repo, cli.ImportGitOptions{
+		GitLogOptions: capture.GitLogOptions{
+			Since:  *since,
+			Until:  *until,
+			Author: *author,
+			Max:    *max,
+		},
+		Summarize: *summarize,
+		DryRun:    *dryRun,
+	})
+}
+
+func handleImportICalCommand(args []string) error {
+	fs := flag.NewFlagSet("import-ical", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	since := fs.String("since", "", "Only meetings on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "Only meetings before this date (default: now)")
+	allDay := fs.Bool("all-day", false, "Include all-day events")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bt import-ical [--since D] [--until D] [--all-day] [--dry-run] <ics-file-or-url>")
+	}
+
+	opts := importer.ICalOptions{AllDay: *allDay}
+	if *since != "" {
+		t, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since date: %s", *since)
+		}
+		opts.Since = t
+	}
+	if *until != "" {
+		t, err := time.ParseInLocation("2006-01-02", *until, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --until date: %s", *until)
+		}
+		if t.Before(clock.Now()) {
+			opts.Until = t
+		}
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportICal(fs.Arg(0), opts, *dryRun)
+}
+
+func handleImportNotionCommand(args []string) error {
+	fs := flag.NewFlagSet("import-notion", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	title := fs.String("title", "", "Column for the title (\"-\" for none)")
+	content := fs.String("content", "", "Column(s) for the content, comma separated")
+	date := fs.String("date", "", "Column for the creation date")
+	tags := fs.String("tags", "", "Column with comma separated tags")
+	url := fs.String("url", "", "Column with a URL")
+	label := fs.String("impetus", "", "Impetus label (default \"Notion note\")")
+	interactive := fs.Bool("interactive", false, "Confirm the column mapping of each database")
+	dryRun := fs.Bool("dry-run", false, "Preview without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bt import-notion [--title C] [--content C] [--date C] [--tags C] [--url C] [--interactive] [--dry-run] <export.zip|file.csv>")
+	}
+
+	opts := importer.NotionOptions{
+		Mapping: importer.ColumnMapping{Title: *title, Content: *content, Date: *date, Tags: *tags, URL: *url},
+		Impetus: *label,
+	}
+	in := bufio.NewReader(os.Stdin)
+	opts.Ask = func(database string, headers []string, m importer.ColumnMapping) importer.ColumnMapping {
+		fmt.Printf("%s columns: %s\n", database, strings.Join(headers, ", "))
+		fields := []struct {
+			name  string
+			value *string
+		}{{"title", &m.Title}, {"content", &m.Content}, {"date", &m.Date}, {"tags", &m.Tags}, {"url", &m.URL}}
+		for _, f := range fields {
+			if !*interactive {
+				if *f.value != "" {
+					fmt.Printf("  %-8s <- %s\n", f.name, *f.value)
+				}
+				continue
+			}
+			fmt.Printf("  %-8s [%s]: ", f.name, *f.value)
+			line, _ := in.ReadString('\n')
+			if line = strings.TrimSpace(line); line != "" {
+				*f.value = line // "-" leaves the field unmapped
+			}
+		}
+		return m
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).ImportNotion(fs.Arg(0), opts, *dryRun)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleIngestCommand(args []string) error {
+	fs := flag.NewFlagSet("ingest", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	title := fs.String("title", "", "Document title (default: its first heading or line)")
+	impetus := fs.String("impetus", "", "Impetus label for every chunk (default: inferred per chunk)")
+	maxChars := fs.Int("max", 2000, "Most characters per chunk")
+	yes := fs.Bool("yes", false, "Commit without asking")
+	dryRun := fs.Bool("dry-run", false, "Show the chunks, store nothing")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: bt ingest [--title T] [--max 2000] [--yes] [--dry-run] <file|->")
+	}
+
+	var in io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Ingest(in, cli.IngestOptions{
+		Title:    *title,
+		Impetus:  *impetus,
+		MaxChars: *maxChars,
+		Yes:      *yes,
+		DryRun:   *dryRun,
+		JSON:     *robot,
+	})
+}
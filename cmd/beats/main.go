@@ -7,8 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bierlingm/beats/internal/beat"
 	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
 	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/profile"
 	"github.com/bierlingm/beats/internal/store"
 )
 
@@ -31,7 +35,24 @@ func main() {
 }
 
 func run() error {
-	args := os.Args[1:]
+	args, utc := stripUTCFlag(os.Args[1:])
+	applyTimezones(utc)
+	args, profiling := stripProfileFlag(args)
+	if profiling {
+		profile.Enable()
+		start := time.Now()
+		defer func() { profile.Report(os.Stderr, time.Since(start)) }()
+	}
+	args, fixedTime := stripFixedTimeFlag(args)
+	if fixedTime != "" {
+		t, err := clock.Parse(fixedTime)
+		if err != nil {
+			return fmt.Errorf("invalid --fixed-time: %w", err)
+		}
+		clock.Set(t)
+		// Hook scripts calling bt see the same time
+		os.Setenv(clock.FakeNowEnvVar, t.Format(time.RFC3339Nano))
+	}
 
 	// Check for robot commands first (they're flags, not subcommands)
 	if len(args) > 0 && strings.HasPrefix(args[0], "--robot-") {
@@ -60,72 +81,175 @@ func run() error {
 	cmd := args[0]
 	cmdArgs := args[1:]
 
-	// Handle prime command separately (no store needed initially)
 	if cmd == "prime" {
-		beatsDir := ""
-		for i, arg := range cmdArgs {
-			if arg == "--dir" && i+1 < len(cmdArgs) {
-				beatsDir = cmdArgs[i+1]
-				break
-			}
-		}
-		return handlePrimeCommand(beatsDir)
+		return handlePrimeCommand(cmdArgs)
+	}
+	if cmd == "pack" {
+		return handlePackCommand(cmdArgs)
 	}
 
 	return handleHumanCommand(cmd, cmdArgs)
 }
 
+// stripUTCFlag removes --utc, which any command accepts, from args.
+func stripUTCFlag(args []string) ([]string, bool) {
+	out := args[:0:0]
+	utc := false
+	for _, arg := range args {
+		if arg == "--utc" || arg == "-utc" {
+			utc = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out, utc
+}
+
+// stripFixedTimeFlag removes --fixed-time <ts> (or --fixed-time=<ts>), which
+// any command accepts, from args.
+func stripFixedTimeFlag(args []string) ([]string, string) {
+	out := args[:0:0]
+	fixed := ""
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case (arg == "--fixed-time" || arg == "-fixed-time") && i+1 < len(args):
+			fixed = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--fixed-time=") || strings.HasPrefix(arg, "-fixed-time="):
+			fixed = arg[strings.Index(arg, "=")+1:]
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out, fixed
+}
+
+// stripProfileFlag removes --profile, which any command accepts, from args.
+func stripProfileFlag(args []string) ([]string, bool) {
+	out := args[:0:0]
+	found := false
+	for _, arg := range args {
+		if arg == "--profile" || arg == "-profile" {
+			found = true
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out, found
+}
+
+// applyTimezones sets the display time zone and the zone new IDs are dated
+// in from the config. A bad setting is reported and left at its default.
+func applyTimezones(utc bool) {
+	cfg := config.Get()
+	if loc, err := cfg.Location(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		cli.DisplayLocation = loc
+	}
+	if loc, err := cfg.IDLocation(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		beat.IDLocation = loc
+	}
+	if utc {
+		cli.DisplayLocation = time.UTC
+	}
+}
+
 func handleRobotCommand(cmd string, args []string) error {
 	// Parse optional --dir flag for robot commands
 	robotFlags := flag.NewFlagSet("robot", flag.ExitOnError)
 	beatsDir := robotFlags.String("dir", "", "Beats directory")
+	storeSel := robotFlags.String("store", "", "Registered store name or path")
 	if err := robotFlags.Parse(args); err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
-	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	dir := *beatsDir
+	if *storeSel != "" {
+		reg, err := store.LoadRegistry()
+		if err != nil {
+			return err
+		}
+		s, err := reg.Lookup(*storeSel)
+		if err != nil {
+			return err
+		}
+		dir = s.Path
+	}
+
+	jsonStore, err := store.NewJSONLStore(dir)
 	if err != nil {
 		return fmt.Errorf("failed to initialize store: %w", err)
 	}
 
 	cli.SetJSONOutput(os.Stdout)
 	robotCLI := cli.NewRobotCLI(jsonStore)
+	defer robotCLI.Close()
+	input := robotCLI.Input(os.Stdin) // Honors timeout_ms
 
 	switch cmd {
 	case "--robot-help":
 		return robotCLI.Help()
 	case "--robot-propose-beat":
-		return robotCLI.ProposeBeat(os.Stdin)
+		return robotCLI.ProposeBeat(input)
 	case "--robot-commit-beat":
-		return robotCLI.CommitBeat(os.Stdin)
+		return robotCLI.CommitBeat(input)
 	case "--robot-search":
-		return robotCLI.Search(os.Stdin)
+		return robotCLI.Search(input)
+	case "--robot-person-brief":
+		return robotCLI.PersonBrief(input)
 	case "--robot-brief":
-		return robotCLI.Brief(os.Stdin)
+		return robotCLI.Brief(input)
 	case "--robot-context-for-bead":
-		return robotCLI.ContextForBead(os.Stdin)
+		return robotCLI.ContextForBead(input)
 	case "--robot-map-beats-to-beads":
-		return robotCLI.MapBeatsToBeads(os.Stdin)
+		return robotCLI.MapBeatsToBeads(input)
 	case "--robot-diff":
-		return robotCLI.Diff(os.Stdin)
+		return robotCLI.Diff(input)
 	case "--robot-link-beat":
-		return robotCLI.LinkBeat(os.Stdin)
+		return robotCLI.LinkBeat(input)
+	case "--robot-annotate":
+		return robotCLI.Annotate(input)
 	case "--robot-synthesis-status":
 		return robotCLI.SynthesisStatus()
 	case "--robot-synthesis-clear":
 		return robotCLI.SynthesisClear()
+	case "--robot-suggest-links":
+		return robotCLI.SuggestLinks(input)
+	case "--robot-register-beads":
+		return robotCLI.RegisterBeads(input)
+	case "--robot-synthesis-history":
+		return robotCLI.SynthesisHistory()
+	case "--robot-synthesis-respond":
+		return robotCLI.SynthesisRespond(input)
 	case "--robot-context":
-		return robotCLI.Context(os.Stdin)
+		return robotCLI.Context(input)
 	case "--robot-edit":
-		return robotCLI.Edit(os.Stdin)
+		return robotCLI.Edit(input)
 	case "--robot-amend":
-		return robotCLI.Amend(os.Stdin)
+		return robotCLI.Amend(input)
 	case "--robot-import":
-		return robotCLI.Import(os.Stdin)
+		return robotCLI.Import(input)
 	case "--robot-export":
-		return robotCLI.Export(os.Stdin)
+		return robotCLI.Export(input)
 	case "--robot-redate":
-		return robotCLI.Redate(os.Stdin)
+		return robotCLI.Redate(input)
+	case "--robot-attention":
+		return robotCLI.Attention()
+	case "--robot-ripe":
+		return robotCLI.Ripe()
+	case "--robot-questions":
+		return robotCLI.Questions()
+	case "--robot-pending":
+		return robotCLI.Pending()
+	case "--robot-orientation":
+		return robotCLI.Orientation()
+	case "--robot-stats":
+		return robotCLI.Stats()
+	case "--robot-sample":
+		return robotCLI.Sample(input)
 	default:
 		return fmt.Errorf("unknown robot command: %s", cmd)
 	}
@@ -134,13 +258,14 @@ func handleRobotCommand(cmd string, args []string) error {
 func handleExportCommand(args []string) error {
 	fs := flag.NewFlagSet("export", flag.ExitOnError)
 	beatsDir := fs.String("dir", "", "Beats directory")
-	exportFormat := fs.String("format", "jsonl", "Output format: json, jsonl, csv")
+	exportFormat := fs.String("format", "jsonl", "Output format: json, jsonl, csv, jsonld")
 	exportSince := fs.String("since", "", "Filter by created_at >= datetime")
 	exportUntil := fs.String("until", "", "Filter by created_at <= datetime")
 	exportImpetus := fs.String("impetus", "", "Filter by impetus label (substring match)")
 	exportQuery := fs.String("query", "", "Filter by content (substring match)")
 	exportOutput := fs.String("output", "", "Output file (default: stdout)")
 	exportOutputShort := fs.String("o", "", "Output file (short)")
+	exportBase := fs.String("base", "", "IRI prefix for beat nodes with --format jsonld (default urn:beats:)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -163,6 +288,7 @@ func handleExportCommand(args []string) error {
 		Impetus: *exportImpetus,
 		Query:   *exportQuery,
 		Output:  output,
+		Base:    *exportBase,
 	})
 }
 
@@ -171,23 +297,162 @@ func handleHumanCommand(cmd string, args []string) error {
 	if cmd == "export" {
 		return handleExportCommand(args)
 	}
+	if cmd == "synthesis" {
+		return handleSynthesisCommand(args)
+	}
+	if cmd == "embed" {
+		return handleEmbedCommand(args)
+	}
+	if cmd == "daemon" {
+		return handleDaemonCommand(args)
+	}
+	if cmd == "topics" {
+		return handleTopicsCommand(args)
+	}
+	if cmd == "digest" {
+		return handleDigestCommand(args)
+	}
+	if cmd == "fixtures" {
+		return handleFixturesCommand(args)
+	}
+	if cmd == "weekly" {
+		return handleWeeklyCommand(args)
+	}
+	if cmd == "stats" {
+		return handleStatsCommand(args)
+	}
+	if cmd == "entities" {
+		return handleEntitiesCommand(args)
+	}
+	if cmd == "capture" {
+		return handleCaptureCommand(args)
+	}
+	if cmd == "watch" {
+		return handleWatchCommand(args)
+	}
+	if cmd == "serve-capture" {
+		return handleServeCaptureCommand(args)
+	}
+	if cmd == "import-obsidian" {
+		return handleImportObsidianCommand(args)
+	}
+	if cmd == "import-md" {
+		return handleImportMarkdownCommand(args)
+	}
+	if cmd == "import-logseq" || cmd == "import-roam" {
+		return handleImportOutlineCommand(cmd, args)
+	}
+	if cmd == "import-apple-notes" {
+		return handleImportAppleNotesCommand(args)
+	}
+	if cmd == "import-git" {
+		return handleImportGitCommand(args)
+	}
+	if cmd == "import-ical" {
+		return handleImportICalCommand(args)
+	}
+	if cmd == "import-notion" {
+		return handleImportNotionCommand(args)
+	}
+	if cmd == "beads" {
+		return handleBeadsCommand(args)
+	}
+	if cmd == "coverage" {
+		return handleCoverageCommand(args)
+	}
+	if cmd == "promote" {
+		return handlePromoteCommand(args)
+	}
+	if cmd == "lineage" {
+		return handleLineageCommand(args)
+	}
+	if cmd == "map" {
+		return handleMapCommand(args)
+	}
+	if cmd == "migrate" {
+		return handleMigrateCommand(args)
+	}
+	if cmd == "by-project" {
+		return handleByProjectCommand(args)
+	}
+	if cmd == "config" {
+		return handleConfigCommand(args)
+	}
+	if cmd == "backup" {
+		return handleBackupCommand(args)
+	}
+	if cmd == "restore" {
+		return handleRestoreCommand(args)
+	}
+	if cmd == "doctor" {
+		return handleDoctorCommand(args)
+	}
+	if cmd == "setup" {
+		return handleSetupCommand(args)
+	}
+	if cmd == "status" {
+		return handleStatusCommand(args)
+	}
+	if cmd == "stores" {
+		return handleStoresCommand(args)
+	}
+	if cmd == "diff-stores" {
+		return handleDiffStoresCommand(args)
+	}
+	if cmd == "eval" {
+		return handleEvalCommand(args)
+	}
+	if cmd == "annotate" {
+		return handleAnnotateCommand(args)
+	}
+	if cmd == "person" {
+		return handlePersonCommand(args)
+	}
+	if cmd == "questions" {
+		return handleQuestionsCommand(args)
+	}
+	if cmd == "review" {
+		return handleReviewCommand(args)
+	}
+	if cmd == "approve" {
+		return handleApproveCommand(args)
+	}
+	if cmd == "gc" {
+		return handleGCCommand(args)
+	}
+	if cmd == "relabel" {
+		return handleRelabelCommand(args)
+	}
+	if cmd == "events" {
+		return handleEventsCommand(args)
+	}
+	if cmd == "ingest" {
+		return handleIngestCommand(args)
+	}
 
 	// Create flag set for subcommand
 	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
 	beatsDir := fs.String("dir", "", "Beats directory")
+	storeSel := fs.String("store", "", "Registered store name or path, a comma-separated list, or 'all' (list and search)")
 	impetusLabel := fs.String("impetus", "", "Impetus label for 'add' command")
 	maxResults := fs.Int("max", 20, "Maximum results for 'search' command")
-	force := fs.Bool("force", false, "Skip confirmation for delete")
+	force := fs.Bool("force", false, "Skip confirmation for delete; link unknown bead IDs")
+	relation := fs.String("relation", "", "Bead link relation: seed (default), evidence, blocks, retrospective")
 	targetDir := fs.String("to", "", "Target directory for move command")
 	searchAll := fs.Bool("all", false, "Search across all projects")
 	rootDir := fs.String("root", "", "Root directory for cross-project operations")
 	sessionFilter := fs.String("session", "", "Filter by session ID (use 'current' for FACTORY_SESSION_ID)")
+	waldFilter := fs.String("wald", "", "Only beats filed under this WALD directory (path or WALD.yaml entry)")
 	dryRun := fs.Bool("dry-run", false, "Show what would be done without making changes")
 	limit := fs.Int("limit", 10, "Maximum results per category for context command")
+	asOf := fs.String("as-of", "", "Show the store as it was at this moment (list, show, search)")
 
 	// Quick capture flags
 	webURL := fs.String("web", "", "Capture from web URL")
 	webURLShort := fs.String("w", "", "Capture from web URL (short)")
+	snapshot := fs.Bool("snapshot", false, "Archive the web page as HTML and markdown attachments")
+	redact := fs.Bool("redact", false, "Mask secrets and personal data found in the beat")
+	extract := fs.String("extract", "", "Entity extraction for add: heuristic, llm or off (default: entity_extraction setting)")
 	githubRef := fs.String("github", "", "GitHub reference (owner/repo)")
 	githubRefShort := fs.String("g", "", "GitHub reference (short)")
 	twitterURL := fs.String("twitter", "", "X/Twitter URL")
@@ -199,9 +464,11 @@ func handleHumanCommand(cmd string, args []string) error {
 	dateStr := fs.String("date", "", "Backdate beat (ISO8601 or relative: yesterday, 3d ago)")
 	dateStrShort := fs.String("d", "", "Backdate beat (short)")
 	searchSemantic := fs.Bool("semantic", false, "Use semantic search")
+	includeAttachments := fs.Bool("include-attachments", false, "Also search the text of attachments (page text, PDFs, transcripts)")
+	rawFTS := fs.Bool("raw-fts", false, "Pass the query to SQLite FTS5 untouched (NEAR, OR, prefix*, column filters)")
+	minScore := fs.Float64("min-score", -1, "Minimum similarity for semantic search (default: scoring.json)")
 	robotOutput := fs.Bool("robot", false, "Output JSON (for context command)")
-	consolidate := fs.Bool("consolidate", false, "Consolidate scattered .beats/ into global store")
-	cleanup := fs.Bool("cleanup", false, "Remove old .beats/ directories after migration verification")
+	contextDir := fs.String("context", "", "WALD directory to file the beat under (default: inferred)")
 
 	// Edit command flags
 	editContent := fs.String("content", "", "New content for beat (edit command)")
@@ -218,13 +485,70 @@ func handleHumanCommand(cmd string, args []string) error {
 		return err
 	}
 
-	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	cmdArgs := fs.Args()
+	dir := *beatsDir
+	if *storeSel != "" {
+		if dir != "" {
+			return fmt.Errorf("--store cannot be combined with --dir")
+		}
+		reg, err := store.LoadRegistry()
+		if err != nil {
+			return err
+		}
+		selected, err := reg.Select(*storeSel)
+		if err != nil {
+			return err
+		}
+		if len(selected) > 1 {
+			if *asOf != "" {
+				return fmt.Errorf("--as-of works on one store")
+			}
+			switch {
+			case cmd == "list":
+				return cli.FederatedList(selected, *sessionFilter, *waldFilter, *robotOutput)
+			case cmd == "search" && !*searchSemantic && !*searchAll && !*includeAttachments && !*rawFTS:
+				if len(cmdArgs) == 0 {
+					return fmt.Errorf("search requires query argument")
+				}
+				return cli.FederatedSearch(selected, strings.Join(cmdArgs, " "), *maxResults, *sessionFilter, *waldFilter, *robotOutput)
+			default:
+				return fmt.Errorf("%s works on one store; only list and search take several", cmd)
+			}
+		}
+		dir = selected[0].Path
+	} else if dir == "" && cmd == "add" {
+		// Inside a registered project, capture to its store
+		cwd, _ := os.Getwd()
+		routed, err := store.RouteDir(cwd)
+		if err != nil {
+			return err
+		}
+		dir = routed
+	}
+
+	jsonStore, err := store.NewJSONLStore(dir)
 	if err != nil {
 		return fmt.Errorf("failed to initialize store: %w", err)
 	}
 
+	if *asOf != "" {
+		switch {
+		case cmd != "list" && cmd != "show" && cmd != "search":
+			return fmt.Errorf("--as-of only applies to list, show and search")
+		case *searchSemantic || *searchAll || *includeAttachments || *rawFTS:
+			return fmt.Errorf("--as-of cannot be combined with --semantic, --all, --include-attachments or --raw-fts")
+		}
+		at, err := clock.ParseRelative(*asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of: %w", err)
+		}
+		if jsonStore, err = jsonStore.AsOf(at); err != nil {
+			return err
+		}
+		fmt.Printf("As of %s\n\n", at.In(cli.DisplayLocation).Format("2006-01-02 15:04:05 MST"))
+	}
+
 	humanCLI := cli.NewHumanCLI(jsonStore)
-	cmdArgs := fs.Args()
 
 	switch cmd {
 	case "add":
@@ -251,7 +575,7 @@ func handleHumanCommand(cmd string, args []string) error {
 		}
 		var parsedDate *time.Time
 		if dateFlagVal != "" {
-			t, err := cli.ParseRelativeDate(dateFlagVal)
+			t, err := clock.ParseRelative(dateFlagVal)
 			if err != nil {
 				return fmt.Errorf("invalid date: %w", err)
 			}
@@ -270,13 +594,17 @@ func handleHumanCommand(cmd string, args []string) error {
 			WebURL:       web,
 			GitHubRef:    github,
 			TwitterURL:   twitter,
+			Snapshot:     *snapshot,
 			Coaching:     isCoaching,
 			Session:      isSession,
 			Date:         parsedDate,
+			Context:      *contextDir,
+			Redact:       *redact,
+			Extract:      *extract,
 		})
 
 	case "list":
-		return humanCLI.List(*sessionFilter)
+		return humanCLI.List(*sessionFilter, *waldFilter)
 
 	case "show":
 		if len(cmdArgs) == 0 {
@@ -289,17 +617,30 @@ func handleHumanCommand(cmd string, args []string) error {
 			return fmt.Errorf("search requires query argument")
 		}
 		query := strings.Join(cmdArgs, " ")
+		if (*includeAttachments || *rawFTS) && (*searchSemantic || *searchAll) {
+			return fmt.Errorf("--include-attachments and --raw-fts cannot be combined with --semantic or --all")
+		}
 		if *searchSemantic {
-			return humanCLI.SemanticSearch(query, *maxResults)
+			return humanCLI.SemanticSearch(query, *maxResults, *minScore, *waldFilter)
 		}
 		if *searchAll {
+			if *waldFilter != "" {
+				return fmt.Errorf("--wald cannot be combined with --all")
+			}
 			root := *rootDir
 			if root == "" {
 				root = cli.GetDefaultRoot()
 			}
 			return humanCLI.SearchAll(root, query, *maxResults)
 		}
-		return humanCLI.Search(query, *maxResults, *sessionFilter)
+		search := humanCLI.Search
+		if *rawFTS {
+			search = humanCLI.SearchRawFTS
+		}
+		if err := search(query, *maxResults, *sessionFilter, *waldFilter); err != nil || !*includeAttachments {
+			return err
+		}
+		return humanCLI.SearchAttachments(query, *maxResults, *sessionFilter, *waldFilter, *rawFTS)
 
 	case "projects":
 		root := *rootDir
@@ -314,7 +655,7 @@ func handleHumanCommand(cmd string, args []string) error {
 		}
 		beatID := cmdArgs[0]
 		beadIDs := cmdArgs[1:]
-		return humanCLI.Link(beatID, beadIDs)
+		return humanCLI.Link(beatID, beadIDs, *relation, *force)
 
 	case "delete", "rm":
 		if len(cmdArgs) == 0 {
@@ -332,7 +673,7 @@ func handleHumanCommand(cmd string, args []string) error {
 		return humanCLI.Move(cmdArgs[0], *targetDir)
 
 	case "hooks":
-		return handleHooksCommand(jsonStore.Dir(), cmdArgs)
+		return handleHooksCommand(humanCLI, jsonStore.Dir(), cmdArgs)
 
 	case "where":
 		// Show which .beats directory is being used
@@ -356,15 +697,6 @@ func handleHumanCommand(cmd string, args []string) error {
 	case "backfill-context":
 		return humanCLI.BackfillContext(*dryRun)
 
-	case "migrate":
-		if !*consolidate && !*cleanup {
-			return fmt.Errorf("migrate requires --consolidate or --cleanup flag")
-		}
-		if *cleanup {
-			return humanCLI.MigrateCleanup(cli.MigrateOptions{DryRun: *dryRun, Force: *force, Cleanup: true})
-		}
-		return humanCLI.MigrateConsolidate(cli.MigrateOptions{DryRun: *dryRun})
-
 	case "context":
 		path := ""
 		if len(cmdArgs) > 0 {
@@ -388,6 +720,8 @@ func handleHumanCommand(cmd string, args []string) error {
 			RmRefs:   rmRef,
 			AddBeads: addBead,
 			RmBeads:  rmBead,
+			Relation: *relation,
+			Force:    *force,
 		})
 
 	case "amend":
@@ -403,6 +737,8 @@ func handleHumanCommand(cmd string, args []string) error {
 			RmRefs:   rmRef,
 			AddBeads: addBead,
 			RmBeads:  rmBead,
+			Relation: *relation,
+			Force:    *force,
 		})
 
 	case "redate":
@@ -413,13 +749,14 @@ func handleHumanCommand(cmd string, args []string) error {
 
 	case "export":
 		exportFs := flag.NewFlagSet("export", flag.ExitOnError)
-		exportFormat := exportFs.String("format", "jsonl", "Output format: json, jsonl, csv")
+		exportFormat := exportFs.String("format", "jsonl", "Output format: json, jsonl, csv, jsonld")
 		exportSince := exportFs.String("since", "", "Filter by created_at >= datetime")
 		exportUntil := exportFs.String("until", "", "Filter by created_at <= datetime")
 		exportImpetus := exportFs.String("impetus", "", "Filter by impetus label (substring match)")
 		exportQuery := exportFs.String("query", "", "Filter by content (substring match)")
 		exportOutput := exportFs.String("output", "", "Output file (default: stdout)")
 		exportOutputShort := exportFs.String("o", "", "Output file (short)")
+		exportBase := exportFs.String("base", "", "IRI prefix for beat nodes with --format jsonld (default urn:beats:)")
 		if err := exportFs.Parse(cmdArgs); err != nil {
 			return err
 		}
@@ -434,6 +771,7 @@ func handleHumanCommand(cmd string, args []string) error {
 			Impetus: *exportImpetus,
 			Query:   *exportQuery,
 			Output:  output,
+			Base:    *exportBase,
 		})
 
 	case "import":
@@ -461,9 +799,9 @@ func handleHumanCommand(cmd string, args []string) error {
 	}
 }
 
-func handleHooksCommand(beatsDir string, args []string) error {
+func handleHooksCommand(humanCLI *cli.HumanCLI, beatsDir string, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("hooks requires a subcommand: init, status, clear, session-end, configure")
+		return fmt.Errorf("hooks requires a subcommand: init, status, show, enable, disable, clear, session-end, configure, install-git")
 	}
 
 	subcmd := args[0]
@@ -477,18 +815,79 @@ func handleHooksCommand(beatsDir string, args []string) error {
 		return nil
 
 	case "status":
-		req, err := hooks.GetSynthesisRequest(beatsDir)
+		status, err := hooks.GetStatus(beatsDir)
 		if err != nil {
+			return fmt.Errorf("failed to read hook status: %w", err)
+		}
+		if !status.ConfigFileExists && !status.GlobalFileExists {
+			fmt.Printf("No hooks config at %s (run 'beats hooks init').\n\n", status.ConfigFile)
+		} else if status.GlobalFileExists {
+			fmt.Printf("Global hooks: %s (this store's %s wins)\n\n", status.GlobalFile, hooks.HooksConfigFile)
+		}
+		fmt.Println("Hooks:")
+		for _, h := range status.Hooks {
+			state := "disabled"
+			if h.Enabled {
+				state = "enabled"
+			}
+			fmt.Printf("  %-17s %-9s %s\n", h.Name, state, h.Detail)
+		}
+		fmt.Printf("\nBeats since last synthesis: %d / %d", status.BeatsSinceLast, status.Threshold)
+		if status.BeatsUntilTrigger > 0 {
+			fmt.Printf(" (%d more to trigger)", status.BeatsUntilTrigger)
+		}
+		fmt.Println()
+		if status.LastSynthesisAt != nil {
+			fmt.Printf("Last synthesis: %s\n", status.LastSynthesisAt.In(cli.DisplayLocation).Format("2006-01-02 15:04:05"))
+		}
+
+		req := status.PendingSynthesis
+		if req == nil {
 			fmt.Println("No synthesis pending.")
 			return nil
 		}
-		fmt.Printf("Synthesis triggered at: %s\n", req.TriggeredAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("\nSynthesis triggered at: %s\n", req.TriggeredAt.In(cli.DisplayLocation).Format("2006-01-02 15:04:05"))
 		fmt.Printf("Beats since last synthesis: %d\n", req.BeatsSinceLast)
 		fmt.Printf("Total beats: %d\n", req.TotalBeats)
 		fmt.Printf("Recent beats to review: %d\n", len(req.RecentBeats))
 		fmt.Println("\nUse 'beats hooks clear' after processing, or --robot-synthesis-status for full details.")
 		return nil
 
+	case "show":
+		showFs := flag.NewFlagSet("hooks show", flag.ExitOnError)
+		effective := showFs.Bool("effective", false, "Show the global and store config merged")
+		robot := showFs.Bool("robot", false, "Output JSON")
+		if err := showFs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return humanCLI.HooksShow(*effective, *robot)
+
+	case "enable", "disable":
+		toggleFs := flag.NewFlagSet("hooks "+subcmd, flag.ExitOnError)
+		global := toggleFs.Bool("global", false, "Change the global hooks.json shared by every store")
+		if err := toggleFs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if toggleFs.NArg() < 1 {
+			return fmt.Errorf("hooks %s requires a hook name: %s", subcmd, strings.Join(hooks.HookNames, ", "))
+		}
+		name, enabled := toggleFs.Arg(0), subcmd == "enable"
+		var err error
+		if *global {
+			err = hooks.SetGlobalHookEnabled(name, enabled)
+		} else {
+			err = hooks.SetHookEnabled(beatsDir, name, enabled)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s hook: %w", subcmd, err)
+		}
+		if *global {
+			fmt.Printf("Hook %s %sd globally (%s).\n", name, subcmd, hooks.GlobalHooksPath())
+		} else {
+			fmt.Printf("Hook %s %sd.\n", name, subcmd)
+		}
+		return nil
+
 	case "clear":
 		if err := hooks.ClearSynthesisNeeded(beatsDir); err != nil {
 			return fmt.Errorf("failed to clear synthesis: %w", err)
@@ -504,8 +903,37 @@ func handleHooksCommand(beatsDir string, args []string) error {
 	case "configure":
 		return hooks.ShowConfig(beatsDir)
 
+	case "install-git":
+		repoDir := "."
+		if len(args) > 1 {
+			repoDir = args[1]
+		}
+		beatsBin, err := os.Executable()
+		if err != nil {
+			beatsBin = "beats"
+		}
+		hookPath, existed, err := hooks.InstallGitHook(repoDir, beatsDir, beatsBin)
+		if err != nil {
+			return fmt.Errorf("failed to install git hook: %w", err)
+		}
+		if existed {
+			fmt.Printf("Git hook already installed at %s\n", hookPath)
+			return nil
+		}
+		fmt.Printf("Installed post-commit hook at %s\n", hookPath)
+		fmt.Println("Each commit will now be captured as a \"Code change\" beat.")
+		return nil
+
+	case "git-commit":
+		// Invoked by the post-commit hook from the repository's working tree
+		rev := ""
+		if len(args) > 1 {
+			rev = args[1]
+		}
+		return humanCLI.CaptureGitCommit(".", rev)
+
 	default:
-		return fmt.Errorf("unknown hooks subcommand: %s (use: init, status, clear, session-end, configure)", subcmd)
+		return fmt.Errorf("unknown hooks subcommand: %s (use: init, status, enable, disable, clear, session-end, configure, install-git)", subcmd)
 	}
 }
 
@@ -518,28 +946,118 @@ USAGE:
 
 HUMAN COMMANDS:
   prime                  Output context for AI session injection
+    --format md|json     Markdown block (default) or JSON
+  pack --topic "X"       Bundle a topic's context to paste into a fresh LLM session
+    --budget 8000        Token budget for the whole pack
+    --out FILE           Write to a file (default: stdout)
+    --format F           md (default), json or html (a standalone page)
+    --summarize          Have the LLM write the brief
+    --provider NAME      Apply that provider's redaction profile
   add "content"          Add a new beat with the given content
     --impetus "label"    Optional impetus label
     -d, --date DATE      Backdate beat (ISO8601 or relative: yesterday, 3d ago)
     -w, --web URL        Capture from web URL with title extraction
+    --snapshot           With -w, archive the page as HTML and markdown
+    --redact             Mask API keys, emails, phone numbers and custom patterns
+    --extract <mode>     Entity extraction: heuristic, llm (typed entities and relations) or off
     -g, --github ref     Capture GitHub repo (owner/repo)
     -x, --twitter URL    Capture X/Twitter link
     -c, --coaching       Mark as coaching insight
     -s, --session-insight Mark as session insight
+    --context <dir>      WALD directory for the beat (default: inferred from cwd,
+                         session workspace or content)
+    --store <name>       Store to write to (default: the registered project store
+                         containing cwd, else the global store)
 
   list                   List all beats
+    --wald <dir>         Only beats filed under a WALD directory (path or WALD.yaml entry)
+    --store <names>      Registered stores, comma-separated, or 'all'
+    --robot              Output JSON (with --store)
+    --as-of <time>       The store as it was then, rebuilt from backups and the journal
+                         (before the oldest backup: empty if it is, else an error)
+
+  by-project             Beats grouped by WALD directory, busiest first
+    --wald <dir>         Only this directory and its subdirectories
+    --max N              Recent beats shown per directory (default: 3)
+    --robot              Output JSON
 
-  show <beat-id>         Show details of a specific beat
+  show <beat-id>         Show details of a specific beat (any command taking a beat
+                         ID also takes a unique prefix, a part like 1204-001, or @N)
+    --as-of <time>       The beat as it was then
 
   search "query"         Search beats by content/impetus
     --max N              Maximum results (default 20)
     --all                Search across all projects
     --root <path>        Root directory for --all (default: ~/werk or BEATS_ROOT)
+    --semantic           Rank by embedding similarity (TF-IDF when offline)
+    --min-score N        Minimum similarity for --semantic (default: scoring.json)
+    --include-attachments  Also search attachment text (page text, PDFs, transcripts)
+    --raw-fts            Query is FTS5 syntax, passed through untouched
+    --wald <dir>         Only beats filed under a WALD directory
+    --store <names>      Registered stores, comma-separated, or 'all'
+    --as-of <time>       Search the store as it was then (keyword search only)
+    --robot              Output JSON (with --store)
 
   projects               List all beats projects
     --root <path>        Root directory to scan (default: ~/werk or BEATS_ROOT)
 
-  link <beat-id> <bead-id>...  Link a beat to one or more beads
+  link <beat-id> <bead-id>...  Link a beat to one or more beads (checked via .beats/beads.json)
+    --relation <rel>     seed (default), evidence, blocks or retrospective
+    --force              Link bead IDs the provider does not know
+
+  annotate <beat-id> <note>  Add a note to a beat without changing its content
+    --author <name>      Who is annotating (default $USER)
+
+  person "Name"          Meeting prep: beats about a person, commitments, open threads
+    --max N              Commitments and open threads to list (default 10)
+    --robot              Output JSON
+
+  questions              Open loops: questions with no follow-up beat or linked bead
+    --min-age N          Only questions at least N days old
+    --max N              Oldest questions to list (default all)
+    --robot              Output JSON
+
+  review --pending       List captures the quota hook held for review
+    --channel <name>     Only captures from this channel; limits all as well
+    --accept <ids>|all   Store these pending captures (comma-separated)
+    --drop <ids>|all     Discard these pending captures
+    --robot              Output JSON
+
+  approve <id>...        Store robot commits staged by the approval hook
+    --all                Approve every staged commit
+    --robot              Output JSON
+
+  beads sync             Record closed beads on linked beats; list beats whose beads all closed
+    --dry-run            Report without writing
+    --robot              Output JSON
+
+  beads refresh          Cache every bead from the provider in .beats/beads_cache.json
+
+  beads remap <old> <new>  Rename a linked bead in every beat, recorded in the journal
+    --dry-run            List the beats without writing
+    --robot              Output JSON
+
+  promote <beat-id>...   Draft a bead/epic from beats and link them to it
+    --title "..."        Title of the new bead (required)
+    --description "..."  Opening paragraph, above the seed beats
+    --type <type>        Bead type (default: epic)
+    --markdown           Write a markdown draft instead of calling the provider
+    -o <file>            Markdown draft file (default: stdout)
+    --id <bead-id>       Link to an existing bead instead of creating one
+    --dry-run            Show the draft without creating or linking
+
+  coverage               Linked vs orphaned beats, beats per bead, open beads without beats
+    --by month|week      Trend period (default: month)
+    --periods N          Trend periods to show (default: 6)
+    --robot              Output JSON
+
+  lineage <bead-id>      Seed beats, syntheses, promotion and later beats of a bead, as a tree
+    --robot              Output JSON
+
+  map                    Diagram of beads grouped by epic with their seed beats
+    --format mermaid|dot Output format (default: mermaid)
+    --max N              Seed beats drawn per bead (default: 5)
+    -o <file>            Write to a file (default: stdout)
 
   delete <beat-id>       Delete a beat (alias: rm)
     --force              Skip confirmation prompt
@@ -549,6 +1067,86 @@ HUMAN COMMANDS:
 
   where                  Show which .beats directory is being used
 
+  backup [create]        Snapshot beats.jsonl into .beats/backups/
+  backup list            List backups
+  backup verify          Check every backup against its checksum and beat count
+    --robot              Output JSON
+
+  restore --at <time>    Rebuild the store as of a moment from backups and the journal,
+                         verify it and swap it in (the current store is backed up first;
+                         before the oldest backup: empty if it is, else an error)
+    --dry-run            Rebuild and verify only
+    --force              Skip confirmation
+    --robot              Output JSON (implies --force)
+
+  gc                     Remove attachments in .beats/blobs/ that no beat refers to
+    --grace <dur>        Keep unreferenced blobs newer than this (default 24h)
+    --dry-run            Report without removing
+    --robot              Output JSON
+
+  relabel                List beats with generic impetus labels
+    --llm                Have the LLM choose 3 to 7 word labels and apply them
+    --filter impetus:"L" Relabel beats with label L instead
+    --limit N            At most N beats
+    --batch 20           Beats per LLM prompt
+    --dry-run            Show the new labels without applying them
+    --robot              Output JSON
+
+  setup                  Guided first configuration: store, Ollama and models,
+                         session_end and synthesis hooks, importing notes
+    --yes                Take every default without asking
+    --project <dir>      Where a per-project store goes (default: current directory)
+
+  doctor                 Check beats.jsonl for malformed lines and duplicate IDs
+    --fix                Move malformed lines to .beats/quarantine.jsonl
+    --robot              Output JSON
+
+  status                 Store, index, embeddings, Ollama, hooks, bead provider,
+                         daemon and synthesis at a glance
+    --robot              Output JSON
+
+  stores list            Global and registered project stores, with beat counts
+    --robot              Output JSON
+  stores add <path>      Register a project store (.beats dir or its project dir)
+    --name <name>        Store name (default: the project directory's name)
+  stores remove <name>   Unregister a store; its beats are left in place
+  stores discover        Register every .beats store found under a root
+    --root <path>        Directory to scan (default: BEATS_ROOT or ~/werk)
+    --dry-run            List without registering
+  stores diff <a> <b>    Beats only in one store, or differing under the same ID
+    --check              Fail unless the stores hold the same beats
+    --robot              Output JSON
+  diff-stores <dir> <dir>
+                         The same for two store directories, such as a store and
+                         its backup: missing beats, divergent versions, link changes
+    --check              Fail unless the stores hold the same beats
+    --robot              Output JSON
+
+  config show            Effective settings (store, root, Ollama URL and models) and
+                         where each comes from: environment, config file or default
+    --robot              Output JSON
+  config path            Print the config file location
+
+  fixtures               Fill an empty store with generated beats, the same for the same seed
+    --seed 1             Random seed
+    --count 50           Beats to generate
+    --days 30            Days to spread them over
+    --start 2025-01-01   First day (UTC)
+    --robot              Output JSON
+
+  migrate consolidate    Merge scattered per-project .beats/ stores into the global store
+    --root <paths>       Directory to scan; repeatable or a path list (default: BEATS_ROOT)
+    --to <directory>     Global store (default: migrate.json, then the current store)
+    --dry-run            Report without writing
+    --robot              Output a JSON summary; progress goes to stderr
+
+  migrate cleanup        Verify consolidation and archive the old stores
+    --force              Archive them (even when not every beat was migrated)
+    --root, --to, --dry-run, --robot as for consolidate
+
+  migrate backend [files|jsonl]  Convert the store to one JSON file per beat, or back
+                                 (no argument: show the backend in use)
+
   edit <beat-id>         Edit an existing beat
     --content "text"     Replace content
     --impetus "label"    Replace impetus label
@@ -563,12 +1161,13 @@ HUMAN COMMANDS:
   redate <id> <date>     Change beat date (convenience for edit --date)
 
   export                 Export beats to file or stdout
-    --format F           Output format: json, jsonl, csv (default: jsonl)
+    --format F           Output format: json, jsonl, csv, jsonld (default: jsonl)
     --since DATE         Filter by created_at >= date
     --until DATE         Filter by created_at <= date
     --impetus "label"    Filter by impetus (substring)
     --query "text"       Filter by content (substring)
     -o, --output FILE    Write to file (default: stdout)
+    --base IRI           Prefix for beat node IRIs in JSON-LD (default: urn:beats:)
 
   import <file>          Import beats from JSON/JSONL (use - for stdin)
     --format F           Input format: json, jsonl (auto-detect)
@@ -576,9 +1175,170 @@ HUMAN COMMANDS:
     --source "label"     Set impetus.meta.source on imported beats
     --dry-run            Preview without writing
 
+  import-obsidian <vault>  Import notes from an Obsidian vault (re-runs skip imported notes)
+    --folder F           Only notes under this folder (repeatable)
+    --tag T              Only notes with this tag (repeatable)
+    --dry-run            Preview without writing
+
+  import-md <dir>        Import a folder of markdown notes, dated by file modification time
+    --recursive          Include subdirectories
+    --split-headings     One beat per ## section instead of per file
+    --dry-run            Preview without writing
+
+  import-logseq <graph>  Import Logseq pages and journals
+  import-roam <file>     Import a Roam Research JSON export
+    --blocks             One beat per top-level block instead of per page
+    --dry-run            Preview without writing
+
+  import-apple-notes     Import notes from the macOS Notes app (creation dates kept)
+    --folder X           Only this Notes folder
+    --dry-run            Preview without writing
+
+  import-git [repo]      Import commit history as "Code change" beats (skips captured commits)
+    --since DATE         Only commits after DATE (e.g. 2024-01-01, "3 months ago")
+    --until DATE         Only commits before DATE
+    --author NAME        Only commits by this author
+    --max N              Only the most recent N commits
+    --summarize          One beat per day, summarized by the session_end Ollama model
+    --dry-run            Preview without writing
+
+  import-ical <ics-or-url>  Import past meetings from an .ics file, webcal feed or CalDAV calendar
+    --since DATE         Only meetings on or after DATE (YYYY-MM-DD)
+    --until DATE         Only meetings before DATE (default: now)
+    --all-day            Include all-day events
+    --dry-run            Preview without writing
+
+  import-notion <zip|csv>  Import a Notion export or CSV, one beat per row or page
+    --title/--content/--date/--tags/--url COL  Column mapping (guessed from headers otherwise)
+    --impetus "label"    Impetus label (default "Notion note")
+    --interactive        Confirm each database's column mapping
+    --dry-run            Preview without writing
+
+  embed                  Compute missing embeddings via Ollama (resumable)
+    --model NAME         Embedding model for a new store (default: embed_model in the config)
+    --batch N            Beats per request (default 32)
+    --rate N             Max beats per second (default unlimited)
+    --migrate NAME       Re-embed everything with a new model, verify, then switch
+    --keep-old           Keep the previous index as embeddings.prev.*
+    --compact            Drop vectors of deleted/re-embedded beats, report space reclaimed
+    --export FILE        Export vectors with beat IDs (.parquet, .npy or .jsonl)
+
+  daemon                 Keep the SQLite index and embeddings in sync in the background
+    --interval 2s        How often to check beats.jsonl for changes
+    --no-embed           Only maintain the SQLite index
+    --socket             Be the single writer: other bt processes forward writes
+                         to .beats/daemon.sock (BEATS_NO_DAEMON=1 bypasses it)
+    --metrics-addr ADDR  Serve GET /healthz and /metrics (Prometheus) on ADDR
+    --digest             Store yesterday's digest beat once a day
+    --no-snapshot        Skip the daily store metrics snapshot (.beats/metrics.jsonl)
+  daemon status          Show whether a daemon is running
+
+  capture x <url> [note] Unroll an X/Twitter thread into a beat (pass the last post)
+  capture hn <id|url>    Capture a Hacker News story and its linked article
+    --comments N         Include N top-level comments
+  capture pdf <path|url> Extract a PDF's text into a beat and attach the document
+  capture arxiv <id>     Capture an arXiv paper's abstract and PDF text
+  capture github-stars <user>  One beat per repo starred since the last sync
+    --dry-run            Preview without writing
+  capture github-issue <owner/repo#N>  Capture an issue or PR's title, body and labels
+  capture podcast <url>  Capture an episode from a feed, episode page or audio URL
+    --episode Q          Episode GUID, URL or title words (default: newest)
+    --no-transcribe      Skip Whisper when the feed links no transcript
+
+  ingest <file|->        Chunk a long document or transcript into beats under a source beat
+    --title T            Document title (default: its first heading or line)
+    --impetus "label"    Label for every chunk (default: inferred per chunk)
+    --max 2000           Most characters per chunk
+    --yes                Commit without asking
+    --dry-run            Show the chunks, store nothing
+    --robot              Output JSON
+
+  watch dir <path>       Ingest markdown/text files dropped into a folder, then archive them
+    --interval 5s        How often to scan the folder
+    --archive DIR        Where ingested files go (default <path>/archive)
+    --once               Ingest what is there now and exit
+  watch sessions         Summarize every ended Factory session into a beat (all workspaces)
+    --idle 10m           Sessions untouched this long are considered ended
+    --workers 4          Workspaces summarized concurrently
+    --once               Process the backlog and exit
+    --dir <path>         Keep every session beat in this store (default: routed by workspace)
+  watch beads            Fire the on_bead_changed hook when beads appear or change status
+    --interval 5m        How often to poll the bead provider
+    --once               Compare with the bead cache once and exit
+
+  serve-capture          Capture endpoint for a bookmarklet, extension or iOS Shortcut (POST /capture)
+    --addr ADDR          Listen address (default 127.0.0.1:7777)
+    --token T            Bearer token (default .beats/serve_token, generated)
+                         Also serves POST /capture/webhook/<name> from .beats/webhooks.json,
+                         GET /healthz, GET /metrics (Prometheus) and an RSS feed of digests
+                         and weekly reviews at GET /feed/digests.xml?token=T
+                         GET /beats and POST /beats/<id>/links; serve_tokens in the config
+                         file adds tokens scoped to read, capture or link
+
+  topics                 Cluster embeddings into emerging, steady and fading themes
+    --window 30d         Compare this window with the one before it
+    --threshold N        Minimum similarity to join a topic (default: scoring.json)
+    --robot              Output JSON
+
+  entities graph         Which entities are named together, as Mermaid or JSON
+    --format mermaid     mermaid or json (--robot)
+    --window 30d         Pairs first seen within it are marked emerging
+    --min 2              Leave out pairs seen in fewer beats
+    --max 40             Most pairs to show
+    -o FILE              Write to a file
+
+  stats                  Capture counts, streaks and beats per day
+    --days 30            Days of daily counts to show
+    --snapshot           Append store metrics to .beats/metrics.jsonl
+    --trend              Compare the last --days with the period before
+    --by-source          Beats, bead links and citations per capture channel
+    --robot              Output JSON
+
+  eval search            Precision, recall and MRR of keyword, semantic and hybrid search
+    --cases FILE         YAML cases: queries and the beat IDs they should find
+    --k 10               Rank cutoff (default: the file's k, else 10)
+    --modes LIST         Modes to compare (default keyword,semantic,hybrid)
+    --robot              Output JSON
+
+  digest                 Summarize a day's beats with the LLM into one digest beat (preview)
+    --date D             YYYY-MM-DD, today or yesterday (default: yesterday)
+    --since 7d           Every finished day since a date
+    --commit             Store each digest as a beat linking its sources
+    --min 2              Skip days with fewer beats
+    --format html        Render the digests as a standalone HTML page
+    -o FILE              Write the HTML page to a file (default: stdout)
+    --robot              Output JSON
+
+  weekly                 Write an LLM retrospective of a week's beats and store it as a beat
+    --week 2025-W49      ISO week (default: this week)
+    -o FILE              Review file (default .beats/reviews/<week>.md or .html)
+    --format md|html     Review file format (default: md)
+    --no-beat            Only write the review file
+    --robot              Output JSON
+
   hooks init             Initialize hooks config (enables synthesis triggers)
-  hooks status           Check if synthesis is pending
+  hooks status           Show enabled hooks, synthesis counter and pending request
+  hooks show             Show the global and store hooks.json
+    --effective          Merged, with where each section comes from
+    --robot              Output JSON
+  hooks enable <hook>    Enable a hook (see 'hooks status' for names)
+  hooks disable <hook>   Disable a hook
+    --global             In the global hooks.json next to config.json, for every store
   hooks clear            Clear pending synthesis request
+  hooks install-git [repo]  Capture every git commit as a "Code change" beat
+  events replay          Re-send beat_added/beat_linked events from the journal
+    --since <time>       From this moment (ISO8601 or relative: 3d ago)
+    --target <t>         hook (the on_event script) or webhook
+    --url URL            Webhook URL (default: on_event url in hooks.json)
+    --event NAME         Only beat_added or beat_linked
+    --dry-run            List the events without sending them
+    --robot              Output JSON
+
+  synthesis history      List archived synthesis requests
+    --limit N            Maximum entries (default 20)
+  synthesis show <id>    Show an archived synthesis and its response
+  synthesis respond [file|-]  Record the response to the pending synthesis
+    --id <id>            Respond to a specific synthesis
 
 ROBOT COMMANDS (JSON in/out via stdin/stdout):
   --robot-help                   Show robot command schemas
@@ -586,21 +1346,38 @@ ROBOT COMMANDS (JSON in/out via stdin/stdout):
   --robot-commit-beat            Commit a proposed beat
   --robot-search                 Search beats
   --robot-brief                  Generate thematic brief
+  --robot-person-brief           Beats about a person, for meeting prep
   --robot-context-for-bead       Get context for a bead
+  --robot-context                Token-budgeted context for a question
   --robot-map-beats-to-beads     Suggest beat-to-bead mappings
   --robot-diff                   Get changes since timestamp
   --robot-link-beat              Link a beat to beads
+  --robot-annotate               Add a note to a beat
   --robot-synthesis-status       Get synthesis status (JSON)
   --robot-synthesis-clear        Clear synthesis request
+  --robot-suggest-links          Suggest bead links by similarity
+  --robot-register-beads         Store an agent's bead inventory in the bead cache
+  --robot-synthesis-history      List archived syntheses
+  --robot-synthesis-respond      Record a synthesis response
+  --robot-attention              Topics active in the last 72h
+  --robot-ripe                   Old unlinked beats with the most signal
+  --robot-questions              Open questions with no follow-up
+  --robot-pending                Captures held for review (quota and approval hooks)
+  --robot-orientation            Direction of recent attention
+  --robot-stats                  Capture counts and streaks
+  --robot-sample                 Random or stratified sample of beats (seeded)
 
 OPTIONS:
   --dir <path>           Beats directory (default: auto-discover .beats)
+  --utc                  Show times in UTC instead of the configured timezone
+  --fixed-time TS        Freeze the clock at TS (RFC 3339, YYYY-MM-DD or Unix seconds), like BEATS_FAKE_NOW
+  --profile              Report on stderr where the command spent its time
   --version              Show version
   --help                 Show this help
 
 DIRECTORY RESOLUTION:
-  bt walks up from the current directory to find the nearest .beats folder
-  (like git finds .git). Set BEATS_DIR environment variable to override.
+  All beats go to one global store: BEATS_DIR, else "store" in the config
+  file (bt config path), else ~/werk/.beats. --dir overrides it per command.
 
 EXAMPLES:
   # Add a beat
@@ -614,6 +1391,7 @@ EXAMPLES:
   # Search
   bt search "coaching"
   bt search --max 5 "commitment"
+  bt search --include-attachments "pricing"
 
   # Cross-project search
   bt search --all "deployment"
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const migrateUsage = `usage: bt migrate consolidate [--root <paths>]... [--to <dir>] [--dry-run] [--robot]
+       bt migrate cleanup [--root <paths>]... [--to <dir>] [--dry-run] [--force] [--robot]
+       bt migrate backend [--dir <path>] [--robot] [files|jsonl]`
+
+func handleMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate requires a subcommand\n%s", migrateUsage)
+	}
+	// Older releases spelled the subcommands as flags.
+	action, rest := args[0], args[1:]
+	switch action {
+	case "--consolidate", "-consolidate":
+		action = "consolidate"
+	case "--cleanup", "-cleanup":
+		action = "cleanup"
+	}
+
+	if action == "backend" {
+		return handleMigrateBackend(rest)
+	}
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	roots := multiFlag{}
+	fs.Var(&roots, "root", "Directory to scan for .beats/ (repeatable, or a path list)")
+	to := fs.String("to", "", "Global store to consolidate into")
+	dryRun := fs.Bool("dry-run", false, "Report without writing")
+	force := fs.Bool("force", false, "Archive old stores (cleanup), even if not all beats migrated")
+	robot := fs.Bool("robot", false, "Output a JSON summary; progress goes to stderr")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument: %s\n%s", fs.Arg(0), migrateUsage)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+
+	opts := cli.MigrateOptions{
+		DryRun:      *dryRun,
+		Force:       *force,
+		GlobalStore: *to,
+		JSON:        *robot,
+	}
+	for _, root := range roots {
+		opts.Roots = append(opts.Roots, filepath.SplitList(root)...)
+	}
+
+	switch action {
+	case "consolidate":
+		return humanCLI.MigrateConsolidate(opts)
+	case "cleanup":
+		opts.Cleanup = true
+		return humanCLI.MigrateCleanup(opts)
+	default:
+		return fmt.Errorf("unknown migrate subcommand: %s\n%s", action, migrateUsage)
+	}
+}
+
+// handleMigrateBackend converts the store between beats.jsonl and one file
+// per beat.
+func handleMigrateBackend(args []string) error {
+	fs := flag.NewFlagSet("migrate backend", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).MigrateBackend(fs.Arg(0), *robot)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handlePackCommand(args []string) error {
+	fs := flag.NewFlagSet("pack", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	topic := fs.String("topic", "", "Topic to pack context for")
+	budget := fs.Int("budget", 8000, "Token budget for the whole pack")
+	out := fs.String("out", "", "Write to a file (default: stdout)")
+	outShort := fs.String("o", "", "Write to a file (short)")
+	format := fs.String("format", cli.PrimeMarkdown, "Output format: md, json or html")
+	robot := fs.Bool("robot", false, "Output JSON (same as --format json)")
+	summarize := fs.Bool("summarize", false, "Have the LLM write the brief")
+	provider := fs.String("provider", "", "LLM provider the pack is for, choosing its redaction profile")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *topic == "" {
+		*topic = strings.Join(fs.Args(), " ")
+	} else if fs.NArg() > 0 {
+		return fmt.Errorf("usage: bt pack --topic \"X\" [--budget 8000] [--out context.md]")
+	}
+	if *out == "" {
+		*out = *outShort
+	}
+	if *robot {
+		*format = cli.PrimeJSON
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Pack(cli.PackOptions{
+		Topic:     *topic,
+		Budget:    *budget,
+		Out:       *out,
+		Format:    *format,
+		Summarize: *summarize,
+		Provider:  *provider,
+	})
+}
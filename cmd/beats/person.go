@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handlePersonCommand(args []string) error {
+	fs := flag.NewFlagSet("person", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	threads := fs.Int("max", cli.DefaultPersonThreads, "Commitments and open threads to list")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("person requires a name, e.g. bt person \"Jane Doe\"")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Person(cli.PersonBriefOptions{
+		Name:    strings.Join(fs.Args(), " "),
+		Threads: *threads,
+	}, *robot)
+}
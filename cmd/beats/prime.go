@@ -1,143 +1,44 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"os/exec"
-	"strings"
-)
-
-func handlePrimeCommand(beatsDir string) error {
-	var output strings.Builder
-	output.WriteString("# Beats Context\n\n")
-	output.WriteString("> Run `bt prime` after new session when .beats/ detected\n\n")
-
-	// Get activating topics
-	attention, err := runBtvRobot("--robot-attention", beatsDir)
-	if err == nil {
-		writeActivatingTopics(&output, attention)
-	}
-
-	// Get ripe beats
-	ripe, err := runBtvRobot("--robot-ripe", beatsDir)
-	if err == nil {
-		writeRipeBeats(&output, ripe)
-	}
-
-	// Get orientation
-	orientation, err := runBtvRobot("--robot-orientation", beatsDir)
-	if err == nil {
-		writeOrientation(&output, orientation)
-	}
-
-	// Quick commands
-	output.WriteString("## Quick Commands\n")
-	output.WriteString("- `bt add \"insight\"` — capture\n")
-	output.WriteString("- `bt add -s \"note\"` — session-tagged\n")
-	output.WriteString("- `btv` — launch TUI\n")
 
-	fmt.Print(output.String())
-	return nil
-}
-
-func runBtvRobot(cmd string, beatsDir string) (map[string]interface{}, error) {
-	args := []string{cmd}
-	if beatsDir != "" {
-		args = append(args, "--dir", beatsDir)
-	}
-
-	c := exec.Command("btv", args...)
-	var stdout, stderr bytes.Buffer
-	c.Stdout = &stdout
-	c.Stderr = &stderr
-
-	if err := c.Run(); err != nil {
-		return nil, fmt.Errorf("btv %s failed: %w", cmd, err)
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse btv output: %w", err)
-	}
-	return result, nil
-}
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
 
-func writeActivatingTopics(out *strings.Builder, data map[string]interface{}) {
-	activations, ok := data["activations"].([]interface{})
-	if !ok || len(activations) == 0 {
-		return
+func handlePrimeCommand(args []string) error {
+	fs := flag.NewFlagSet("prime", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	storeSel := fs.String("store", "", "Registered store name or path")
+	format := fs.String("format", cli.PrimeMarkdown, "Output format: md or json")
+	robot := fs.Bool("robot", false, "Output JSON (same as --format json)")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-
-	out.WriteString("## Activating Topics (72h)\n")
-	for _, a := range activations {
-		act, ok := a.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		cluster := getString(act, "ClusterName")
-		count := getInt(act, "BeatCount")
-		if cluster == "" || count == 0 {
-			continue
-		}
-		out.WriteString(fmt.Sprintf("- **%s** (%d beats)\n", cluster, count))
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: bt prime [--format md|json] [--dir <path>] [--store <name>]")
 	}
-	out.WriteString("\n")
-}
-
-func writeRipeBeats(out *strings.Builder, data map[string]interface{}) {
-	beats, ok := data["beats"].([]interface{})
-	if !ok || len(beats) == 0 {
-		return
+	if *robot {
+		*format = cli.PrimeJSON
 	}
 
-	out.WriteString("## Ripe Beats\n")
-	max := 10
-	if len(beats) < max {
-		max = len(beats)
-	}
-	for i := 0; i < max; i++ {
-		b, ok := beats[i].(map[string]interface{})
-		if !ok {
-			continue
+	dir := *beatsDir
+	if *storeSel != "" {
+		reg, err := store.LoadRegistry()
+		if err != nil {
+			return err
 		}
-		id := getString(b, "id")
-		preview := getString(b, "preview")
-		if len(preview) > 60 {
-			preview = preview[:60] + "..."
+		s, err := reg.Lookup(*storeSel)
+		if err != nil {
+			return err
 		}
-		out.WriteString(fmt.Sprintf("- %s: \"%s\"\n", id, preview))
-	}
-	out.WriteString("\n")
-}
-
-func writeOrientation(out *strings.Builder, data map[string]interface{}) {
-	direction := getString(data, "direction")
-	summary := getString(data, "summary")
-	if direction == "" && summary == "" {
-		return
-	}
-
-	out.WriteString("## Attention Direction\n")
-	if direction != "" {
-		out.WriteString(fmt.Sprintf("%s\n", direction))
+		dir = s.Path
 	}
-	if summary != "" {
-		out.WriteString(fmt.Sprintf("%s\n", summary))
-	}
-	out.WriteString("\n")
-}
-
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key].(string); ok {
-		return v
-	}
-	return ""
-}
-
-func getInt(m map[string]interface{}, key string) int {
-	if v, ok := m[key].(float64); ok {
-		return int(v)
+	jsonStore, err := store.NewJSONLStore(dir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
 	}
-	return 0
+	return cli.NewHumanCLI(jsonStore).Prime(*format)
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleQuestionsCommand(args []string) error {
+	fs := flag.NewFlagSet("questions", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	minAge := fs.Int("min-age", 0, "Only questions at least this many days old")
+	maxResults := fs.Int("max", 0, "Oldest questions to list (0 for all)")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Questions(cli.QuestionsOptions{
+		MinAge: time.Duration(*minAge) * 24 * time.Hour,
+		Max:    *maxResults,
+	}, *robot)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleRelabelCommand(args []string) error {
+	fs := flag.NewFlagSet("relabel", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	llm := fs.Bool("llm", false, "Have the LLM choose the labels (default: list the beats)")
+	filter := fs.String("filter", "", `Relabel beats with this label instead of generic ones, e.g. impetus:"Extracted from raw input"`)
+	limit := fs.Int("limit", 0, "Relabel at most this many beats (0 for all)")
+	batch := fs.Int("batch", cli.DefaultRelabelBatch, "Beats per LLM prompt")
+	dryRun := fs.Bool("dry-run", false, "Show the new labels without applying them")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: bt relabel [--llm] [--filter impetus:\"Label\"] [--limit N] [--dry-run]")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Relabel(cli.RelabelOptions{
+		LLM:    *llm,
+		Filter: *filter,
+		Limit:  *limit,
+		Batch:  *batch,
+		DryRun: *dryRun,
+		JSON:   *robot,
+	})
+}
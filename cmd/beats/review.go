@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleReviewCommand(args []string) error {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	pending := fs.Bool("pending", false, "Review captures held by the quota hook")
+	accept := fs.String("accept", "", "Comma-separated pending IDs to store, or all")
+	drop := fs.String("drop", "", "Comma-separated pending IDs to discard, or all")
+	channel := fs.String("channel", "", "Only captures from this channel (web, webhook, robot, capture...)")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*pending {
+		return fmt.Errorf("usage: bt review --pending [--channel <channel>] [--accept <ids>|all] [--drop <ids>|all]")
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Review(cli.ReviewOptions{
+		Accept:  splitIDs(*accept),
+		Drop:    splitIDs(*drop),
+		Channel: *channel,
+	}, *robot)
+}
+
+// splitIDs splits a comma-separated flag value, ignoring blanks.
+func splitIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
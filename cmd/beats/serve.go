@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleServeCaptureCommand(args []string) error {
+	fs := flag.NewFlagSet("serve-capture", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	addr := fs.String("addr", "127.0.0.1:7777", "Listen address")
+	token := fs.String("token", os.Getenv("BEATS_CAPTURE_TOKEN"), "Bearer token (default: .beats/serve_token, generated on first run)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := log.New(os.Stderr, "beats serve: ", log.LstdFlags)
+	return cli.NewHumanCLI(jsonStore).ServeCapture(ctx, cli.ServeOptions{Addr: *addr, Token: *token}, logger)
+}
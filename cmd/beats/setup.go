@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+)
+
+func handleSetupCommand(args []string) error {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Take every default without asking")
+	project := fs.String("project", "", "Project directory for a per-project store (default: the current directory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: bt setup [--yes] [--project <dir>]")
+	}
+	return cli.Setup(cli.SetupOptions{Yes: *yes, Project: *project})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleStatsCommand(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	days := fs.Int("days", cli.DefaultStatsDays, "Show daily counts for this many days; with --trend, the period compared")
+	snapshot := fs.Bool("snapshot", false, "Append a snapshot of store metrics to .beats/metrics.jsonl")
+	trend := fs.Bool("trend", false, "Compare the last --days with the period before")
+	bySource := fs.Bool("by-source", false, "Beats and signal per capture channel")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+	switch {
+	case *snapshot && *trend, *bySource && (*snapshot || *trend):
+		return fmt.Errorf("--snapshot, --trend and --by-source cannot be combined")
+	case *bySource:
+		return humanCLI.StatsByChannel(*days, *robot)
+	case *snapshot:
+		return humanCLI.StatsSnapshot(*robot)
+	case *trend:
+		return humanCLI.Trends(*days, *robot)
+	}
+	return humanCLI.Stats(*days, *robot)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const storesUsage = `usage: bt stores list [--robot]
+       bt stores add <path> [--name <name>]
+       bt stores remove <name>
+       bt stores discover [--root <path>] [--dry-run]
+       bt stores diff <store> <store> [--check] [--robot]`
+
+func handleStoresCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("stores requires a subcommand\n%s", storesUsage)
+	}
+	fs := flag.NewFlagSet("stores "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "Store name (default: the project directory's name)")
+	rootDir := fs.String("root", "", "Directory to scan for .beats stores (default: the werk root)")
+	dryRun := fs.Bool("dry-run", false, "List the stores discover would register")
+	check := fs.Bool("check", false, "Fail unless the stores hold the same beats")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	rest := fs.Args()
+
+	if args[0] == "list" {
+		return cli.StoresList(*robot)
+	}
+
+	reg, err := store.LoadRegistry()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		if len(rest) != 1 {
+			return fmt.Errorf("stores add requires a path\n%s", storesUsage)
+		}
+		entry, err := reg.Add(*name, rest[0])
+		if err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Registered %s: %s (captures from %s)\n", entry.Name, entry.Path, entry.Project)
+		return nil
+
+	case "remove", "rm":
+		if len(rest) != 1 {
+			return fmt.Errorf("stores remove requires a store name\n%s", storesUsage)
+		}
+		if err := reg.Remove(rest[0]); err != nil {
+			return err
+		}
+		if err := reg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Unregistered %s (its beats are left in place)\n", rest[0])
+		return nil
+
+	case "discover":
+		root := *rootDir
+		if root == "" {
+			root = cli.GetDefaultRoot()
+		}
+		found, err := store.DiscoverBeatsProjects(root)
+		if err != nil {
+			return fmt.Errorf("failed to discover stores: %w", err)
+		}
+		added := 0
+		for _, path := range found {
+			if isRegistered(reg, path) {
+				continue
+			}
+			entry, err := reg.Add("", path)
+			if err != nil {
+				fmt.Printf("  skipped %s: %v\n", path, err)
+				continue
+			}
+			fmt.Printf("  %s: %s\n", entry.Name, entry.Path)
+			added++
+		}
+		if *dryRun {
+			fmt.Printf("Would register %d store(s) found under %s\n", added, root)
+			return nil
+		}
+		if added > 0 {
+			if err := reg.Save(); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Registered %d store(s) found under %s\n", added, root)
+		return nil
+
+	case "diff":
+		if len(rest) != 2 {
+			return fmt.Errorf("stores diff requires two stores\n%s", storesUsage)
+		}
+		a, err := reg.Lookup(rest[0])
+		if err != nil {
+			return err
+		}
+		b, err := reg.Lookup(rest[1])
+		if err != nil {
+			return err
+		}
+		return cli.StoresDiff(*a, *b, *robot, *check)
+
+	default:
+		return fmt.Errorf("unknown stores subcommand: %s\n%s", args[0], storesUsage)
+	}
+}
+
+const diffStoresUsage = "usage: bt diff-stores [--check] [--robot] <dir> <dir>"
+
+// handleDiffStoresCommand compares two stores given by directory, such as a
+// store and its backup or the two ends of a sync, calling them a and b.
+// Registered store names work too.
+func handleDiffStoresCommand(args []string) error {
+	fs := flag.NewFlagSet("diff-stores", flag.ExitOnError)
+	check := fs.Bool("check", false, "Fail unless the stores hold the same beats")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff-stores requires two store directories\n%s", diffStoresUsage)
+	}
+	reg, err := store.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	var entries [2]store.StoreEntry
+	for i, ref := range fs.Args() {
+		entry, err := reg.Lookup(ref)
+		if err != nil {
+			return fmt.Errorf("%s is not a beats store", ref)
+		}
+		entries[i] = *entry
+		entries[i].Name = []string{"a", "b"}[i]
+	}
+	return cli.StoresDiff(entries[0], entries[1], *robot, *check)
+}
+
+// isRegistered reports whether path is the global store or a registered one.
+func isRegistered(reg *store.Registry, path string) bool {
+	for _, s := range reg.All() {
+		if filepath.Clean(s.Path) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
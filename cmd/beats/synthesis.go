@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleSynthesisCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("synthesis requires a subcommand: history, show, respond")
+	}
+
+	subcmd := args[0]
+	fs := flag.NewFlagSet("synthesis "+subcmd, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	limit := fs.Int("limit", 20, "Maximum syntheses to list")
+	id := fs.String("id", "", "Synthesis ID (default: pending or most recent unanswered)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	dir := jsonStore.Dir()
+
+	switch subcmd {
+	case "history":
+		records, err := hooks.ListSyntheses(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read synthesis archive: %w", err)
+		}
+		if len(records) == 0 {
+			fmt.Println("No syntheses archived yet.")
+			return nil
+		}
+		if *limit > 0 && len(records) > *limit {
+			records = records[:*limit]
+		}
+		fmt.Printf("%d synthesis request(s):\n\n", len(records))
+		for _, r := range records {
+			status := "pending response"
+			if r.RespondedAt != nil {
+				status = "answered " + r.RespondedAt.In(cli.DisplayLocation).Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %s  %3d beats  %s\n", r.ID, r.Request.BeatsSinceLast, status)
+		}
+		return nil
+
+	case "show":
+		target := *id
+		if target == "" && fs.NArg() > 0 {
+			target = fs.Arg(0)
+		}
+		if target == "" {
+			return fmt.Errorf("show requires a synthesis ID")
+		}
+		r, err := hooks.GetSynthesis(dir, target)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ID:           %s\n", r.ID)
+		fmt.Printf("Triggered:    %s\n", r.Request.TriggeredAt.In(cli.DisplayLocation).Format("2006-01-02 15:04:05"))
+		fmt.Printf("Beats since:  %d (of %d total)\n", r.Request.BeatsSinceLast, r.Request.TotalBeats)
+		fmt.Printf("\nBeats reviewed:\n")
+		for _, b := range r.Request.RecentBeats {
+			fmt.Printf("  - %s  %s\n", b.ID, b.Impetus.Label)
+		}
+		if r.RespondedAt != nil {
+			fmt.Printf("\nResponse (%s):\n%s\n", r.RespondedAt.In(cli.DisplayLocation).Format("2006-01-02 15:04:05"), r.Response)
+		} else {
+			fmt.Println("\nNo response recorded. Use 'beats synthesis respond <file>' after processing.")
+		}
+		return nil
+
+	case "respond":
+		var data []byte
+		source := "-"
+		if fs.NArg() > 0 {
+			source = fs.Arg(0)
+		}
+		if source == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(source)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		response := strings.TrimSpace(string(data))
+		if response == "" {
+			return fmt.Errorf("response is empty")
+		}
+		r, err := hooks.RecordSynthesisResponse(dir, *id, response)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded response for %s\n", r.ID)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown synthesis subcommand: %s (use: history, show, respond)", subcmd)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleTopicsCommand(args []string) error {
+	fs := flag.NewFlagSet("topics", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	window := fs.String("window", "30d", "Window to compare against the one before it (e.g. 30d, 2w, 72h)")
+	threshold := fs.Float64("threshold", 0, "Minimum similarity to join a topic (default: scoring.json)")
+	minBeats := fs.Int("min", 2, "Smallest topic to report")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	w, err := cli.ParseWindow(*window)
+	if err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return cli.NewHumanCLI(jsonStore).Topics(cli.TopicsOptions{
+		Window:    w,
+		Threshold: *threshold,
+		MinBeats:  *minBeats,
+		JSON:      *robot,
+	})
+}
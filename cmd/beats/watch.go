@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+const watchUsage = `usage: bt watch dir [--interval 5s] [--archive archive] [--once] <path>
+       bt watch sessions [--interval 30s] [--idle 10m] [--workers 4] [--once]
+       bt watch beads [--interval 5m] [--once]`
+
+func handleWatchCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("watch requires a target\n%s", watchUsage)
+	}
+	target, rest := args[0], args[1:]
+
+	fs := flag.NewFlagSet("watch "+target, flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	interval := fs.Duration("interval", 0, "How often to scan (default 5s for dir, 30s for sessions, 5m for beads)")
+	archive := fs.String("archive", "archive", "Subfolder (or absolute path) for ingested files")
+	idle := fs.Duration("idle", 10*time.Minute, "Sessions untouched this long are considered ended")
+	workers := fs.Int("workers", 4, "Workspaces summarized concurrently")
+	once := fs.Bool("once", false, "Process what is there now and exit")
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	humanCLI := cli.NewHumanCLI(jsonStore)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := log.New(os.Stderr, "beats watch: ", log.LstdFlags)
+
+	switch target {
+	case "dir":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("watch dir requires a path\n%s", watchUsage)
+		}
+		return humanCLI.WatchDir(ctx, fs.Arg(0), cli.WatchDirOptions{
+			Interval: *interval,
+			Archive:  *archive,
+			Once:     *once,
+		}, logger)
+	case "sessions":
+		return humanCLI.WatchSessions(ctx, cli.WatchSessionsOptions{
+			Interval: *interval,
+			Idle:     *idle,
+			Workers:  *workers,
+			Once:     *once,
+			Route:    *beatsDir == "", // Like bt add, --dir keeps every beat in one store
+		}, logger)
+	case "beads":
+		return humanCLI.WatchBeads(ctx, cli.WatchBeadsOptions{
+			Interval: *interval,
+			Once:     *once,
+		}, logger)
+	default:
+		return fmt.Errorf("unknown watch target: %s\n%s", target, watchUsage)
+	}
+}
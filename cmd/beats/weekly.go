@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/cli"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func handleWeeklyCommand(args []string) error {
+	fs := flag.NewFlagSet("weekly", flag.ExitOnError)
+	beatsDir := fs.String("dir", "", "Beats directory")
+	week := fs.String("week", "", "ISO week to review, e.g. 2025-W49 (default: this week)")
+	output := fs.String("o", "", "Review file (default .beats/reviews/<week>.md or .html)")
+	format := fs.String("format", "md", "Review file format: md or html")
+	noBeat := fs.Bool("no-beat", false, "Only write the review file, store no beat")
+	robot := fs.Bool("robot", false, "Output JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jsonStore, err := store.NewJSONLStore(*beatsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	return cli.NewHumanCLI(jsonStore).Weekly(cli.WeeklyOptions{
+		Week:   *week,
+		Output: *output,
+		Format: *format,
+		NoBeat: *noBeat,
+		JSON:   *robot,
+	})
+}
@@ -0,0 +1,190 @@
+// Package beads holds the local inventory of beads (actionable work items)
+// that beats can link to.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheFile is the bead inventory inside the .beats directory.
+const CacheFile = "beads_cache.json"
+
+// Bead is an actionable work item known to this store.
+type Bead struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ClosedAt    string `json:"closed_at,omitempty"`  // RFC 3339, when the source records it
+	Type        string `json:"issue_type,omitempty"` // epic, task, bug... when the source records it
+	Parent      string `json:"parent,omitempty"`     // ID of the epic or parent issue
+}
+
+// UnmarshalJSON reads a bead as cached or as the bd CLI and JSONL store
+// write it, where the parent is a parent-child dependency or the prefix of
+// a hierarchical ID (bd-a3f8.1 is a child of bd-a3f8).
+func (b *Bead) UnmarshalJSON(data []byte) error {
+	type plain Bead
+	var raw struct {
+		plain
+		Dependencies []struct {
+			IssueID     string `json:"issue_id"`
+			DependsOnID string `json:"depends_on_id"`
+			Type        string `json:"type"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*b = Bead(raw.plain)
+	for _, dep := range raw.Dependencies {
+		if b.Parent == "" && dep.Type == "parent-child" && (dep.IssueID == "" || dep.IssueID == b.ID) {
+			b.Parent = dep.DependsOnID
+		}
+	}
+	if i := strings.LastIndexByte(b.ID, '.'); b.Parent == "" && i > 0 && isDigits(b.ID[i+1:]) {
+		b.Parent = b.ID[:i]
+	}
+	return nil
+}
+
+// Closed reports whether the bead's work is finished.
+func (b Bead) Closed() bool {
+	return b.Status == "closed"
+}
+
+// Text is the text embedded for a bead: title plus description.
+func (b Bead) Text() string {
+	if b.Description == "" {
+		return b.Title
+	}
+	return b.Title + "\n" + b.Description
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// LoadCache reads the bead inventory. A missing file is an empty inventory.
+func LoadCache(beatsDir string) ([]Bead, error) {
+	data, err := os.ReadFile(filepath.Join(beatsDir, CacheFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Bead
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CacheTime returns when the bead inventory was last written, or the zero
+// time when there is none.
+func CacheTime(beatsDir string) time.Time {
+	info, err := os.Stat(filepath.Join(beatsDir, CacheFile))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// SaveCache writes the bead inventory.
+func SaveCache(beatsDir string, list []Bead) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(beatsDir, CacheFile), data, 0644)
+}
+
+// Register adds beads reported by an outside system to the inventory, for
+// stores whose beads no provider can list. A bead already cached is
+// replaced by the registered one; with replace, beads not registered are
+// dropped. It returns the inventory and how many beads were new.
+func Register(beatsDir string, registered []Bead, replace bool) ([]Bead, int, error) {
+	seen := make(map[string]bool, len(registered))
+	for i, b := range registered {
+		if strings.TrimSpace(b.ID) == "" {
+			return nil, 0, fmt.Errorf("bead %d has no id", i+1)
+		}
+		if seen[b.ID] {
+			return nil, 0, fmt.Errorf("bead %s is listed twice", b.ID)
+		}
+		seen[b.ID] = true
+	}
+
+	var list []Bead
+	if !replace {
+		cached, err := LoadCache(beatsDir)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = cached
+	}
+	index := make(map[string]int, len(list))
+	for i, b := range list {
+		index[b.ID] = i
+	}
+	added := 0
+	for _, b := range registered {
+		if i, ok := index[b.ID]; ok {
+			list[i] = b
+			continue
+		}
+		index[b.ID] = len(list)
+		list = append(list, b)
+		added++
+	}
+	if list == nil {
+		list = []Bead{}
+	}
+	return list, added, SaveCache(beatsDir, list)
+}
+
+// Kinds of bead change reported by Diff.
+const (
+	ChangeCreated = "created" // A bead that was not in the previous inventory
+	ChangeStatus  = "status"  // A known bead whose status changed
+)
+
+// Change is a difference between two bead inventories.
+type Change struct {
+	Kind           string `json:"kind"`
+	Bead           Bead   `json:"bead"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// Diff reports the beads in cur that are new or changed status since prev,
+// in cur's order. Beads missing from cur are not reported: providers list a
+// bounded window, so absence does not mean deletion.
+func Diff(prev, cur []Bead) []Change {
+	before := make(map[string]Bead, len(prev))
+	for _, b := range prev {
+		before[b.ID] = b
+	}
+	var changes []Change
+	for _, b := range cur {
+		old, ok := before[b.ID]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: ChangeCreated, Bead: b})
+		case old.Status != b.Status:
+			changes = append(changes, Change{Kind: ChangeStatus, Bead: b, PreviousStatus: old.Status})
+		}
+	}
+	return changes
+}
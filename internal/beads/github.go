@@ -0,0 +1,157 @@
+package beads
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GitHubConfig treats the issues of a repository as beads. Token falls back
+// to the GITHUB_TOKEN environment variable.
+type GitHubConfig struct {
+	Repo   string `json:"repo,omitempty"` // owner/repo; lets "#12" and "12" stand for its issues
+	Token  string `json:"token,omitempty"`
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// Matches owner/repo#N, #N, N and issue/pull URLs.
+var githubBeadRe = regexp.MustCompile(`^(?:https?://github\.com/([\w.-]+/[\w.-]+)/(?:issues|pull)/|([\w.-]+/[\w.-]+)#|#)?(\d+)$`)
+
+// GitHubProvider resolves beads as GitHub issues, with canonical IDs of the
+// form owner/repo#N.
+type GitHubProvider struct {
+	cfg GitHubConfig
+}
+
+func newGitHubProvider(cfg GitHubConfig) (*GitHubProvider, error) {
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.github.com"
+	}
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	return &GitHubProvider{cfg: cfg}, nil
+}
+
+// Name implements Provider.
+func (p *GitHubProvider) Name() string {
+	if p.cfg.Repo != "" {
+		return "GitHub " + p.cfg.Repo
+	}
+	return "GitHub"
+}
+
+func (p *GitHubProvider) headers() map[string]string {
+	h := map[string]string{"Accept": "application/vnd.github+json"}
+	if p.cfg.Token != "" {
+		h["Authorization"] = "Bearer " + p.cfg.Token
+	}
+	return h
+}
+
+// parse splits an ID into repo and issue number.
+func (p *GitHubProvider) parse(id string) (string, string, error) {
+	m := githubBeadRe.FindStringSubmatch(strings.TrimSpace(id))
+	if m == nil {
+		return "", "", errNotFound
+	}
+	repo := m[1] + m[2]
+	if repo == "" {
+		repo = p.cfg.Repo
+	}
+	if repo == "" {
+		return "", "", fmt.Errorf("%s needs a repository (owner/repo#N, or github.repo in %s)", id, ConfigFile)
+	}
+	return repo, m[3], nil
+}
+
+type githubBeadIssue struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	State       string `json:"state"`
+	HTMLURL     string `json:"html_url"`
+	ClosedAt    string `json:"closed_at"`
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+}
+
+func (i githubBeadIssue) bead(repo string) Bead {
+	return Bead{
+		ID:          fmt.Sprintf("%s#%d", repo, i.Number),
+		Title:       i.Title,
+		Description: i.Body,
+		Status:      i.State, // open or closed
+		URL:         i.HTMLURL,
+		ClosedAt:    i.ClosedAt,
+	}
+}
+
+// Lookup implements Provider.
+func (p *GitHubProvider) Lookup(ids []string) (map[string]Bead, error) {
+	return lookupEach(ids, func(id string) (Bead, error) {
+		repo, number, err := p.parse(id)
+		if err != nil {
+			return Bead{}, err
+		}
+		var issue githubBeadIssue
+		if err := trackerRequest(http.MethodGet, p.cfg.APIURL+"/repos/"+repo+"/issues/"+number, p.headers(), nil, &issue); err != nil {
+			return Bead{}, err
+		}
+		return issue.bead(repo), nil
+	})
+}
+
+// List implements Provider: the repository's issues, pull requests excluded.
+func (p *GitHubProvider) List() ([]Bead, error) {
+	if p.cfg.Repo == "" {
+		return nil, fmt.Errorf("listing GitHub issues needs github.repo in %s", ConfigFile)
+	}
+	var list []Bead
+	for page := 1; len(list) < maxListed; page++ {
+		var issues []githubBeadIssue
+		url := fmt.Sprintf("%s/repos/%s/issues?state=all&per_page=100&page=%d", p.cfg.APIURL, p.cfg.Repo, page)
+		if err := trackerRequest(http.MethodGet, url, p.headers(), nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.PullRequest == nil {
+				list = append(list, issue.bead(p.cfg.Repo))
+			}
+		}
+		if len(issues) < 100 {
+			break
+		}
+	}
+	return list, nil
+}
+
+// Comment implements Commenter.
+func (p *GitHubProvider) Comment(id, body string) error {
+	repo, number, err := p.parse(id)
+	if err != nil {
+		return err
+	}
+	return trackerRequest(http.MethodPost, p.cfg.APIURL+"/repos/"+repo+"/issues/"+number+"/comments",
+		p.headers(), map[string]string{"body": body}, nil)
+}
+
+// Create implements Creator. The draft type becomes a label.
+func (p *GitHubProvider) Create(d Draft) (Bead, error) {
+	if p.cfg.Repo == "" {
+		return Bead{}, fmt.Errorf("creating GitHub issues needs github.repo in %s", ConfigFile)
+	}
+	body := map[string]interface{}{"title": d.Title, "body": d.Description}
+	if d.Type != "" {
+		body["labels"] = []string{d.Type}
+	}
+	var issue githubBeadIssue
+	if err := trackerRequest(http.MethodPost, p.cfg.APIURL+"/repos/"+p.cfg.Repo+"/issues", p.headers(), body, &issue); err != nil {
+		return Bead{}, err
+	}
+	return issue.bead(p.cfg.Repo), nil
+}
@@ -0,0 +1,199 @@
+package beads
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// JiraConfig treats Jira issues as beads. With Email set, Token is a Jira
+// Cloud API token (basic auth); without it, a personal access token
+// (bearer auth, Jira Server/Data Center). Token falls back to JIRA_API_TOKEN
+// and Email to JIRA_EMAIL.
+type JiraConfig struct {
+	URL     string `json:"url,omitempty"` // e.g. https://example.atlassian.net
+	Email   string `json:"email,omitempty"`
+	Token   string `json:"token,omitempty"`
+	Project string `json:"project,omitempty"` // Project key to list
+}
+
+var jiraKeyRe = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-\d+$`)
+
+// JiraProvider resolves beads as Jira issues by key (PROJ-123).
+type JiraProvider struct {
+	cfg JiraConfig
+}
+
+func newJiraProvider(cfg JiraConfig) (*JiraProvider, error) {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("jira provider needs jira.url in %s", ConfigFile)
+	}
+	if cfg.Email == "" {
+		cfg.Email = os.Getenv("JIRA_EMAIL")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("JIRA_API_TOKEN")
+	}
+	return &JiraProvider{cfg: cfg}, nil
+}
+
+// Name implements Provider.
+func (p *JiraProvider) Name() string { return "Jira" }
+
+func (p *JiraProvider) headers() map[string]string {
+	h := map[string]string{}
+	switch {
+	case p.cfg.Token == "":
+	case p.cfg.Email != "":
+		h["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(p.cfg.Email+":"+p.cfg.Token))
+	default:
+		h["Authorization"] = "Bearer " + p.cfg.Token
+	}
+	return h
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary        string      `json:"summary"`
+		ResolutionDate string      `json:"resolutiondate"`
+		Description    interface{} `json:"description"` // String in API v2, a document in v3
+		Status         struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Parent *struct {
+			Key string `json:"key"`
+		} `json:"parent"`
+	} `json:"fields"`
+}
+
+func (p *JiraProvider) bead(i jiraIssue) Bead {
+	status := "open"
+	switch i.Fields.Status.StatusCategory.Key {
+	case "done":
+		status = "closed"
+	case "indeterminate":
+		status = "in_progress"
+	}
+	desc, _ := i.Fields.Description.(string)
+	b := Bead{
+		ID:          i.Key,
+		Title:       i.Fields.Summary,
+		Description: desc,
+		Status:      status,
+		URL:         p.cfg.URL + "/browse/" + i.Key,
+		ClosedAt:    jiraTime(i.Fields.ResolutionDate),
+		Type:        strings.ToLower(i.Fields.IssueType.Name),
+	}
+	if i.Fields.Parent != nil {
+		b.Parent = i.Fields.Parent.Key
+	}
+	return b
+}
+
+// Lookup implements Provider.
+func (p *JiraProvider) Lookup(ids []string) (map[string]Bead, error) {
+	return lookupEach(ids, func(id string) (Bead, error) {
+		key := strings.ToUpper(strings.TrimSpace(id))
+		if !jiraKeyRe.MatchString(key) {
+			return Bead{}, errNotFound
+		}
+		var issue jiraIssue
+		if err := trackerRequest(http.MethodGet, p.cfg.URL+"/rest/api/2/issue/"+key+"?fields=summary,description,status,resolutiondate,issuetype,parent",
+			p.headers(), nil, &issue); err != nil {
+			return Bead{}, err
+		}
+		return p.bead(issue), nil
+	})
+}
+
+// List implements Provider: the project's issues, most recently updated first.
+func (p *JiraProvider) List() ([]Bead, error) {
+	if p.cfg.Project == "" {
+		return nil, fmt.Errorf("listing Jira issues needs jira.project in %s", ConfigFile)
+	}
+	jql := fmt.Sprintf("project = %q ORDER BY updated DESC", p.cfg.Project)
+	var list []Bead
+	for len(list) < maxListed {
+		var page struct {
+			Total  int         `json:"total"`
+			Issues []jiraIssue `json:"issues"`
+		}
+		q := url.Values{
+			"jql":        {jql},
+			"fields":     {"summary,description,status,resolutiondate,issuetype,parent"},
+			"startAt":    {fmt.Sprint(len(list))},
+			"maxResults": {"100"},
+		}
+		if err := trackerRequest(http.MethodGet, p.cfg.URL+"/rest/api/2/search?"+q.Encode(), p.headers(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page.Issues {
+			list = append(list, p.bead(issue))
+		}
+		if len(page.Issues) == 0 || len(list) >= page.Total {
+			break
+		}
+	}
+	return list, nil
+}
+
+// Comment implements Commenter.
+func (p *JiraProvider) Comment(id, body string) error {
+	key := strings.ToUpper(strings.TrimSpace(id))
+	return trackerRequest(http.MethodPost, p.cfg.URL+"/rest/api/2/issue/"+key+"/comment",
+		p.headers(), map[string]string{"body": body}, nil)
+}
+
+// jiraTime converts Jira's timestamps (2024-01-15T10:00:00.000+0000) to
+// RFC 3339.
+func jiraTime(s string) string {
+	t, err := time.Parse("2006-01-02T15:04:05.000-0700", s)
+	if err != nil {
+		return s
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Create implements Creator in the configured project. The draft type is
+// the issue type name (epic becomes Epic; Task when empty).
+func (p *JiraProvider) Create(d Draft) (Bead, error) {
+	if p.cfg.Project == "" {
+		return Bead{}, fmt.Errorf("creating Jira issues needs jira.project in %s", ConfigFile)
+	}
+	issueType := "Task"
+	if d.Type != "" {
+		issueType = strings.ToUpper(d.Type[:1]) + d.Type[1:]
+	}
+	body := map[string]interface{}{"fields": map[string]interface{}{
+		"project":     map[string]string{"key": p.cfg.Project},
+		"summary":     d.Title,
+		"description": d.Description,
+		"issuetype":   map[string]string{"name": issueType},
+	}}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := trackerRequest(http.MethodPost, p.cfg.URL+"/rest/api/2/issue", p.headers(), body, &created); err != nil {
+		return Bead{}, err
+	}
+	return Bead{
+		ID:          created.Key,
+		Title:       d.Title,
+		Description: d.Description,
+		Status:      "open",
+		URL:         p.cfg.URL + "/browse/" + created.Key,
+	}, nil
+}
@@ -0,0 +1,208 @@
+package beads
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// LinearConfig treats Linear issues as beads. APIKey falls back to the
+// LINEAR_API_KEY environment variable.
+type LinearConfig struct {
+	APIKey string `json:"api_key,omitempty"`
+	Team   string `json:"team,omitempty"` // Team key (e.g. ENG) to list; all teams when empty
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// LinearProvider resolves beads as Linear issues by identifier (ENG-123).
+type LinearProvider struct {
+	cfg LinearConfig
+}
+
+func newLinearProvider(cfg LinearConfig) (*LinearProvider, error) {
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.linear.app/graphql"
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv("LINEAR_API_KEY")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("linear provider needs an API key (linear.api_key in %s or LINEAR_API_KEY)", ConfigFile)
+	}
+	return &LinearProvider{cfg: cfg}, nil
+}
+
+// Name implements Provider.
+func (p *LinearProvider) Name() string { return "Linear" }
+
+type linearIssue struct {
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	CompletedAt string `json:"completedAt"`
+	CanceledAt  string `json:"canceledAt"`
+	State       struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Parent *struct {
+		Identifier string `json:"identifier"`
+	} `json:"parent"`
+}
+
+func (i linearIssue) bead() Bead {
+	status := "open"
+	switch i.State.Type {
+	case "completed", "canceled":
+		status = "closed"
+	case "started":
+		status = "in_progress"
+	}
+	b := Bead{
+		ID:          i.Identifier,
+		Title:       i.Title,
+		Description: i.Description,
+		Status:      status,
+		URL:         i.URL,
+		ClosedAt:    firstNonEmpty(i.CompletedAt, i.CanceledAt),
+	}
+	if i.Parent != nil {
+		b.Parent = i.Parent.Identifier
+	}
+	return b
+}
+
+const linearIssueFields = `identifier title description url completedAt canceledAt state { name type } parent { identifier }`
+
+// query runs a GraphQL request. Linear reports unknown issues as errors
+// with status 200, which become errNotFound.
+func (p *LinearProvider) query(query string, vars map[string]interface{}, out interface{}) error {
+	var resp struct {
+		Data   interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	err := trackerRequest(http.MethodPost, p.cfg.APIURL, map[string]string{"Authorization": p.cfg.APIKey},
+		map[string]interface{}{"query": query, "variables": vars}, &resp)
+	if err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msg := resp.Errors[0].Message
+		if strings.Contains(strings.ToLower(msg), "not found") {
+			return errNotFound
+		}
+		return fmt.Errorf("linear: %s", msg)
+	}
+	return nil
+}
+
+// Lookup implements Provider.
+func (p *LinearProvider) Lookup(ids []string) (map[string]Bead, error) {
+	return lookupEach(ids, func(id string) (Bead, error) {
+		var data struct {
+			Issue *linearIssue `json:"issue"`
+		}
+		if err := p.query(`query($id: String!) { issue(id: $id) { `+linearIssueFields+` } }`,
+			map[string]interface{}{"id": strings.TrimSpace(id)}, &data); err != nil {
+			return Bead{}, err
+		}
+		if data.Issue == nil {
+			return Bead{}, errNotFound
+		}
+		return data.Issue.bead(), nil
+	})
+}
+
+// List implements Provider.
+func (p *LinearProvider) List() ([]Bead, error) {
+	var list []Bead
+	var after interface{}
+	filter := map[string]interface{}{}
+	if p.cfg.Team != "" {
+		filter["team"] = map[string]interface{}{"key": map[string]interface{}{"eq": p.cfg.Team}}
+	}
+	for len(list) < maxListed {
+		var data struct {
+			Issues struct {
+				Nodes    []linearIssue `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"issues"`
+		}
+		err := p.query(`query($filter: IssueFilter, $after: String) { issues(first: 100, after: $after, filter: $filter) { nodes { `+
+			linearIssueFields+` } pageInfo { hasNextPage endCursor } } }`,
+			map[string]interface{}{"filter": filter, "after": after}, &data)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range data.Issues.Nodes {
+			list = append(list, issue.bead())
+		}
+		if !data.Issues.PageInfo.HasNextPage {
+			break
+		}
+		after = data.Issues.PageInfo.EndCursor
+	}
+	return list, nil
+}
+
+// Comment implements Commenter.
+func (p *LinearProvider) Comment(id, body string) error {
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	err := p.query(`mutation($id: String!, $body: String!) { commentCreate(input: {issueId: $id, body: $body}) { success } }`,
+		map[string]interface{}{"id": id, "body": body}, &data)
+	if err == nil && !data.CommentCreate.Success {
+		err = fmt.Errorf("linear: comment on %s was not created", id)
+	}
+	return err
+}
+
+// Create implements Creator in the configured team. Linear has no issue
+// types, so the draft type is not sent.
+func (p *LinearProvider) Create(d Draft) (Bead, error) {
+	if p.cfg.Team == "" {
+		return Bead{}, fmt.Errorf("creating Linear issues needs linear.team in %s", ConfigFile)
+	}
+	var teams struct {
+		Teams struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	if err := p.query(`query($key: String!) { teams(filter: {key: {eq: $key}}) { nodes { id } } }`,
+		map[string]interface{}{"key": p.cfg.Team}, &teams); err != nil {
+		return Bead{}, err
+	}
+	if len(teams.Teams.Nodes) == 0 {
+		return Bead{}, fmt.Errorf("linear: team %s not found", p.cfg.Team)
+	}
+
+	var data struct {
+		IssueCreate struct {
+			Success bool        `json:"success"`
+			Issue   linearIssue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	err := p.query(`mutation($team: String!, $title: String!, $desc: String) { issueCreate(input: {teamId: $team, title: $title, description: $desc}) { success issue { `+
+		linearIssueFields+` } } }`,
+		map[string]interface{}{"team": teams.Teams.Nodes[0].ID, "title": d.Title, "desc": d.Description}, &data)
+	if err != nil {
+		return Bead{}, err
+	}
+	if !data.IssueCreate.Success {
+		return Bead{}, fmt.Errorf("linear: issue was not created")
+	}
+	return data.IssueCreate.Issue.bead(), nil
+}
@@ -0,0 +1,456 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConfigFile configures bead resolution, relative to the beats directory.
+const ConfigFile = "beads.json"
+
+// cliTimeout bounds a single call to the beads CLI.
+const cliTimeout = 10 * time.Second
+
+// cacheMaxAge is how long CachedInventory trusts beads_cache.json before
+// listing the provider again.
+const cacheMaxAge = time.Hour
+
+// Config selects where bead IDs are resolved.
+type Config struct {
+	// Provider is "auto" (default), "cli", "jsonl", "cache", "github",
+	// "linear", "jira" or "none". Auto uses the beads CLI when installed,
+	// else a .beads/*.jsonl store found from the working directory, else
+	// beads_cache.json.
+	Provider string   `json:"provider,omitempty"`
+	Command  string   `json:"command,omitempty"`   // CLI binary (default bd, then beads)
+	Dir      string   `json:"dir,omitempty"`       // Working directory for the CLI / where to look for .beads
+	Path     string   `json:"path,omitempty"`      // JSONL store (default: .beads/*.jsonl under Dir)
+	ListArgs []string `json:"list_args,omitempty"` // Default ["list", "--json"]
+
+	GitHub GitHubConfig `json:"github,omitempty"`
+	Linear LinearConfig `json:"linear,omitempty"`
+	Jira   JiraConfig   `json:"jira,omitempty"`
+	// CommentBack posts a note on a bead when a beat is linked to it, for
+	// providers that support comments.
+	CommentBack bool `json:"comment_back,omitempty"`
+}
+
+// LoadConfig reads beads.json. A missing file means auto resolution.
+func LoadConfig(beatsDir string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(beatsDir, ConfigFile))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid %s: %w", ConfigFile, err)
+	}
+	return cfg, nil
+}
+
+// Provider looks beads up in the system that owns them: the beads CLI or
+// store, or an issue tracker whose issues act as beads.
+type Provider interface {
+	// Name describes the source, for messages.
+	Name() string
+	// Lookup returns the beads that exist among ids, keyed by the ID as
+	// given; Bead.ID is the provider's canonical form. Unknown IDs are absent.
+	Lookup(ids []string) (map[string]Bead, error)
+	// List returns every bead the source knows.
+	List() ([]Bead, error)
+}
+
+// Commenter is implemented by providers that can comment on a bead.
+type Commenter interface {
+	Comment(id, body string) error
+}
+
+// Draft is a bead to be created, e.g. an epic promoted from beats.
+type Draft struct {
+	Title       string
+	Description string
+	Type        string // epic, task, feature...; providers map it to their own
+}
+
+// Creator is implemented by providers that can create beads.
+type Creator interface {
+	Create(d Draft) (Bead, error)
+}
+
+// NewProvider returns the provider configured for a beats directory, or nil
+// when resolution is disabled or nothing to resolve against was found.
+func NewProvider(beatsDir string) (Provider, error) {
+	cfg, err := LoadConfig(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Open(beatsDir)
+}
+
+// Refresh lists every bead from p and stores them in beads_cache.json, so
+// mapping, suggestions and titles work offline.
+func Refresh(beatsDir string, p Provider) ([]Bead, error) {
+	list, err := p.List()
+	if err != nil {
+		return nil, err
+	}
+	if _, isCache := p.(CacheProvider); !isCache {
+		if err := SaveCache(beatsDir, list); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Inventory returns every bead known for a beats directory and where the
+// list came from: the provider, refreshing the cache on the way, or the
+// cache when the provider is unavailable. The provider's error is returned
+// alongside a cached list.
+func Inventory(beatsDir string) ([]Bead, string, error) {
+	p, err := NewProvider(beatsDir)
+	if err == nil && p != nil {
+		list, lerr := Refresh(beatsDir, p)
+		if lerr == nil {
+			return list, p.Name(), nil
+		}
+		err = fmt.Errorf("%s: %w", p.Name(), lerr)
+	}
+	list, cerr := LoadCache(beatsDir)
+	if cerr != nil || len(list) == 0 {
+		return nil, "", err
+	}
+	return list, CacheFile, err
+}
+
+// CachedInventory is Inventory for callers that run often: a cache written
+// within the last hour is used as is, and only an empty or older one is
+// rebuilt from the provider.
+func CachedInventory(beatsDir string) ([]Bead, string, error) {
+	if time.Since(CacheTime(beatsDir)) < cacheMaxAge {
+		if list, err := LoadCache(beatsDir); err == nil && len(list) > 0 {
+			return list, CacheFile, nil
+		}
+	}
+	return Inventory(beatsDir)
+}
+
+// Open builds the provider cfg describes.
+func (cfg Config) Open(beatsDir string) (Provider, error) {
+	switch cfg.Provider {
+	case "none":
+		return nil, nil
+	case "cache":
+		return CacheProvider{BeatsDir: beatsDir}, nil
+	case "cli":
+		command := cfg.command()
+		if command == "" {
+			return nil, fmt.Errorf("beads CLI not found (set \"command\" in %s)", ConfigFile)
+		}
+		return CLIProvider{Command: command, Dir: cfg.Dir, ListArgs: cfg.ListArgs}, nil
+	case "github":
+		return newGitHubProvider(cfg.GitHub)
+	case "linear":
+		return newLinearProvider(cfg.Linear)
+	case "jira":
+		return newJiraProvider(cfg.Jira)
+	case "jsonl":
+		path := cfg.Path
+		if path == "" {
+			path = FindStore(cfg.Dir)
+		}
+		if path == "" {
+			return nil, fmt.Errorf("no .beads/*.jsonl store found (set \"path\" in %s)", ConfigFile)
+		}
+		return JSONLProvider{Path: path}, nil
+	case "", "auto":
+		if command := cfg.command(); command != "" {
+			return CLIProvider{Command: command, Dir: cfg.Dir, ListArgs: cfg.ListArgs}, nil
+		}
+		if path := firstNonEmpty(cfg.Path, FindStore(cfg.Dir)); path != "" {
+			return JSONLProvider{Path: path}, nil
+		}
+		if list, _ := LoadCache(beatsDir); len(list) > 0 {
+			return CacheProvider{BeatsDir: beatsDir}, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q in %s (auto, cli, jsonl, cache, github, linear, jira or none)", cfg.Provider, ConfigFile)
+	}
+}
+
+func (cfg Config) command() string {
+	if cfg.Command != "" {
+		if path, err := exec.LookPath(cfg.Command); err == nil {
+			return path
+		}
+		return ""
+	}
+	for _, name := range []string{"bd", "beads"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// FindStore looks for a beads JSONL store in dir's .beads directory or any
+// parent's, the way the beads CLI finds its database.
+func FindStore(dir string) string {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	dir, _ = filepath.Abs(dir)
+	for {
+		for _, name := range []string{"issues.jsonl", "beads.jsonl"} {
+			path := filepath.Join(dir, ".beads", name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// CLIProvider shells out to the beads CLI (`bd show <id> --json`, `bd list --json`).
+type CLIProvider struct {
+	Command  string
+	Dir      string
+	ListArgs []string
+}
+
+// Name implements Provider.
+func (r CLIProvider) Name() string { return filepath.Base(r.Command) }
+
+func (r CLIProvider) run(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cliTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, r.Command, args...)
+	cmd.Dir = r.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(string(out))
+		}
+		return out, &cliError{err: err, msg: msg}
+	}
+	return out, nil
+}
+
+type cliError struct {
+	err error
+	msg string
+}
+
+func (e *cliError) Error() string {
+	if e.msg == "" {
+		return e.err.Error()
+	}
+	return e.err.Error() + ": " + e.msg
+}
+
+func (e *cliError) notFound() bool {
+	msg := strings.ToLower(e.msg)
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "no issue") || strings.Contains(msg, "does not exist")
+}
+
+// Lookup implements Provider with one `show` call per ID.
+func (r CLIProvider) Lookup(ids []string) (map[string]Bead, error) {
+	found := make(map[string]Bead)
+	for _, id := range ids {
+		out, err := r.run("show", id, "--json")
+		var cerr *cliError
+		if errors.As(err, &cerr) && cerr.notFound() {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s show %s: %w", r.Name(), id, err)
+		}
+		list, err := parseBeadsJSON(out)
+		if err != nil {
+			return nil, fmt.Errorf("%s show %s: %w", r.Name(), id, err)
+		}
+		for _, b := range list {
+			if b.ID == id {
+				found[id] = b
+			}
+		}
+	}
+	return found, nil
+}
+
+// List implements Provider.
+func (r CLIProvider) List() ([]Bead, error) {
+	args := r.ListArgs
+	if len(args) == 0 {
+		args = []string{"list", "--json"}
+	}
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", r.Name(), strings.Join(args, " "), err)
+	}
+	return parseBeadsJSON(out)
+}
+
+// Create implements Creator with `create --json`.
+func (r CLIProvider) Create(d Draft) (Bead, error) {
+	args := []string{"create", d.Title, "--description", d.Description}
+	if d.Type != "" {
+		args = append(args, "--type", d.Type)
+	}
+	out, err := r.run(append(args, "--json")...)
+	if err != nil {
+		return Bead{}, fmt.Errorf("%s create: %w", r.Name(), err)
+	}
+	list, err := parseBeadsJSON(out)
+	if err != nil || len(list) == 0 || list[0].ID == "" {
+		return Bead{}, fmt.Errorf("%s create: unexpected output %q", r.Name(), truncateOutput(out))
+	}
+	return list[0], nil
+}
+
+func truncateOutput(out []byte) string {
+	s := strings.TrimSpace(string(out))
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+// parseBeadsJSON accepts the CLI's output: a single issue or a list.
+func parseBeadsJSON(data []byte) ([]Bead, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	if data[0] == '{' {
+		var b Bead
+		if err := json.Unmarshal(data, &b); err != nil {
+			return nil, err
+		}
+		return []Bead{b}, nil
+	}
+	var list []Bead
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// JSONLProvider reads a beads JSONL store directly, for machines without
+// the CLI. Later lines for an ID win, as in the store's own import.
+type JSONLProvider struct {
+	Path string
+}
+
+// Name implements Provider.
+func (r JSONLProvider) Name() string { return r.Path }
+
+// List implements Provider.
+func (r JSONLProvider) List() ([]Bead, error) {
+	f, err := os.Open(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var order []string
+	byID := make(map[string]Bead)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var b Bead
+		if json.Unmarshal(line, &b) != nil || b.ID == "" {
+			continue
+		}
+		if _, seen := byID[b.ID]; !seen {
+			order = append(order, b.ID)
+		}
+		byID[b.ID] = b
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	list := make([]Bead, 0, len(order))
+	for _, id := range order {
+		if b := byID[id]; b.Status != "tombstone" && b.Status != "deleted" {
+			list = append(list, b)
+		}
+	}
+	return list, nil
+}
+
+// Lookup implements Provider.
+func (r JSONLProvider) Lookup(ids []string) (map[string]Bead, error) {
+	list, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	return lookupIn(list, ids), nil
+}
+
+// CacheProvider resolves against beads_cache.json.
+type CacheProvider struct {
+	BeatsDir string
+}
+
+// Name implements Provider.
+func (r CacheProvider) Name() string { return CacheFile }
+
+// List implements Provider.
+func (r CacheProvider) List() ([]Bead, error) { return LoadCache(r.BeatsDir) }
+
+// Lookup implements Provider.
+func (r CacheProvider) Lookup(ids []string) (map[string]Bead, error) {
+	list, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	return lookupIn(list, ids), nil
+}
+
+func lookupIn(list []Bead, ids []string) map[string]Bead {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	found := make(map[string]Bead)
+	for _, b := range list {
+		if want[b.ID] {
+			found[b.ID] = b
+		}
+	}
+	return found
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,244 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLProvider(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store := filepath.Join(root, ".beads", "issues.jsonl")
+	lines := `{"id":"bd-1","title":"Old title","status":"open"}
+{"id":"bd-2","title":"Ship v2","status":"closed","priority":1}
+not json
+{"id":"bd-3","title":"Gone","status":"tombstone"}
+{"id":"bd-1","title":"Fix login","status":"in_progress"}
+`
+	if err := os.WriteFile(store, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Found from a nested working directory, like the beads CLI does
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindStore(nested); got != store {
+		t.Fatalf("FindStore = %q, want %q", got, store)
+	}
+
+	r := JSONLProvider{Path: store}
+	list, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Title != "Fix login" || list[0].Status != "in_progress" {
+		t.Errorf("List() = %+v, want bd-1 (latest line) and bd-2", list)
+	}
+
+	found, err := r.Lookup([]string{"bd-1", "bd-3", "bd-9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found["bd-1"].Title != "Fix login" {
+		t.Errorf("Lookup = %+v, want only bd-1", found)
+	}
+}
+
+func TestCLIProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the CLI")
+	}
+	script := filepath.Join(t.TempDir(), "bd")
+	body := `#!/bin/sh
+case "$1 $2" in
+"show bd-1") echo '{"id":"bd-1","title":"Fix login","status":"open"}' ;;
+"show bd-2") echo '[{"id":"bd-2","title":"Ship v2","status":"closed"}]' ;;
+"show bd-x") echo "database locked" >&2; exit 1 ;;
+"show "*) echo "Error: issue $2 not found" >&2; exit 1 ;;
+"list --json") echo '[{"id":"bd-1","title":"Fix login"},{"id":"bd-2","title":"Ship v2"}]' ;;
+esac
+`
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{Provider: "cli", Command: script}
+	r, err := cfg.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := r.Lookup([]string{"bd-1", "bd-2", "bd-9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found["bd-1"].Title != "Fix login" || found["bd-2"].Status != "closed" {
+		t.Errorf("Lookup = %+v", found)
+	}
+	if _, err := r.Lookup([]string{"bd-x"}); err == nil {
+		t.Error("Lookup with a failing CLI succeeded, want error")
+	}
+
+	list, err := r.List()
+	if err != nil || len(list) != 2 {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+}
+
+func TestCachedInventory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the CLI")
+	}
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	script := filepath.Join(bin, "bd")
+	body := `#!/bin/sh
+echo "$*" >> ` + calls + `
+[ -e ` + filepath.Join(bin, "down") + ` ] && exit 1
+echo '[{"id":"bd-1","title":"Fix login","status":"open"},{"id":"bd-2","title":"Ship v2","status":"open"}]'
+`
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFile), []byte(`{"provider": "cli", "command": "`+script+`"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	listed := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "\n")
+	}
+	age := func(d time.Duration) {
+		old := time.Now().Add(-d)
+		if err := os.Chtimes(filepath.Join(dir, CacheFile), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An empty cache is filled from the CLI.
+	list, source, err := CachedInventory(dir)
+	if err != nil || source != "bd" || len(list) != 2 || listed() != 1 {
+		t.Fatalf("empty cache: %d beads from %q (%d listings), %v", len(list), source, listed(), err)
+	}
+
+	// A fresh cache is used without running the CLI, even when it differs.
+	if err := SaveCache(dir, []Bead{{ID: "bd-1", Title: "Cached title"}}); err != nil {
+		t.Fatal(err)
+	}
+	age(time.Minute)
+	list, source, err = CachedInventory(dir)
+	if err != nil || source != CacheFile || len(list) != 1 || list[0].Title != "Cached title" || listed() != 1 {
+		t.Errorf("fresh cache: %+v from %q (%d listings), %v", list, source, listed(), err)
+	}
+
+	// A stale one is rebuilt.
+	age(2 * cacheMaxAge)
+	list, source, err = CachedInventory(dir)
+	if err != nil || source != "bd" || len(list) != 2 || listed() != 2 {
+		t.Errorf("stale cache: %+v from %q (%d listings), %v", list, source, listed(), err)
+	}
+	if cached, _ := LoadCache(dir); len(cached) != 2 || cached[0].Title != "Fix login" {
+		t.Errorf("rebuilt cache = %+v", cached)
+	}
+	if since := time.Since(CacheTime(dir)); since > time.Minute {
+		t.Errorf("rebuilt cache written %s ago", since)
+	}
+
+	// A stale cache still answers when the CLI fails, with its error.
+	if err := os.WriteFile(filepath.Join(bin, "down"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	age(2 * cacheMaxAge)
+	list, source, err = CachedInventory(dir)
+	if err == nil || source != CacheFile || len(list) != 2 {
+		t.Errorf("CLI down: %d beads from %q, %v; want the cache and an error", len(list), source, err)
+	}
+}
+
+func TestConfigOpen(t *testing.T) {
+	dir := t.TempDir()
+	if r, err := (Config{Provider: "none"}).Open(dir); r != nil || err != nil {
+		t.Errorf("none = %v, %v", r, err)
+	}
+	if _, err := (Config{Provider: "bogus"}).Open(dir); err == nil {
+		t.Error("unknown provider succeeded, want error")
+	}
+	if _, err := (Config{Provider: "cli", Command: "no-such-beads-cli"}).Open(dir); err == nil {
+		t.Error("missing CLI succeeded, want error")
+	}
+	if r, _ := (Config{Provider: "cache"}).Open(dir); r == nil || r.Name() != CacheFile {
+		t.Errorf("cache = %v", r)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := []Bead{{ID: "bd-1", Status: "open"}, {ID: "bd-2", Status: "open"}, {ID: "bd-3", Status: "open"}}
+	cur := []Bead{{ID: "bd-1", Status: "open"}, {ID: "bd-2", Status: "closed"}, {ID: "bd-4", Status: "open"}}
+
+	changes := Diff(prev, cur)
+	if len(changes) != 2 {
+		t.Fatalf("Diff = %+v, want 2 changes", changes)
+	}
+	if c := changes[0]; c.Kind != ChangeStatus || c.Bead.ID != "bd-2" || c.PreviousStatus != "open" {
+		t.Errorf("changes[0] = %+v", c)
+	}
+	if c := changes[1]; c.Kind != ChangeCreated || c.Bead.ID != "bd-4" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	dir := t.TempDir()
+	if err := SaveCache(dir, []Bead{{ID: "bd-1", Title: "Old", Status: "open"}, {ID: "bd-2", Title: "Kept"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, added, err := Register(dir, []Bead{{ID: "bd-1", Title: "Renamed", Status: "closed"}, {ID: "bd-3", Title: "New"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 || len(list) != 3 || list[0].Title != "Renamed" || list[2].ID != "bd-3" {
+		t.Errorf("Register = %+v, %d added", list, added)
+	}
+	if cached, _ := LoadCache(dir); len(cached) != 3 {
+		t.Errorf("cache = %+v, want 3 beads", cached)
+	}
+
+	if list, _, _ := Register(dir, []Bead{{ID: "bd-3", Title: "New"}}, true); len(list) != 1 {
+		t.Errorf("Register(replace) = %+v, want only bd-3", list)
+	}
+	if _, _, err := Register(dir, []Bead{{Title: "No ID"}}, false); err == nil {
+		t.Error("bead without an ID accepted")
+	}
+	if _, _, err := Register(dir, []Bead{{ID: "bd-4"}, {ID: "bd-4"}}, false); err == nil {
+		t.Error("duplicate bead accepted")
+	}
+}
+
+func TestBeadParent(t *testing.T) {
+	list, err := parseBeadsJSON([]byte(`[
+{"id":"bd-7","title":"Sync queue","issue_type":"task","dependencies":[{"issue_id":"bd-7","depends_on_id":"bd-2","type":"blocks"},{"issue_id":"bd-7","depends_on_id":"bd-5","type":"parent-child"}]},
+{"id":"bd-a3f8.1","title":"Child"},
+{"id":"acme/app.js#12","title":"Issue"},
+{"id":"bd-9","title":"Cached","parent":"bd-5"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"bd-5", "bd-a3f8", "", "bd-5"}
+	for i, b := range list {
+		if b.Parent != want[i] {
+			t.Errorf("%s parent = %q, want %q", b.ID, b.Parent, want[i])
+		}
+	}
+	if list[0].Type != "task" {
+		t.Errorf("type = %q, want task", list[0].Type)
+	}
+}
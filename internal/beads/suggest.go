@@ -0,0 +1,125 @@
+package beads
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bierlingm/beats/internal/embeddings"
+)
+
+// EmbeddingsFile caches bead embeddings, keyed by bead ID and content hash.
+const EmbeddingsFile = "bead_embeddings.json"
+
+// Suggestion is a bead a beat could be linked to.
+type Suggestion struct {
+	BeadID     string  `json:"bead_id"`
+	Title      string  `json:"title"`
+	Confidence float64 `json:"confidence"`
+}
+
+type cachedVector struct {
+	Hash   string    `json:"hash"`
+	Vector []float64 `json:"vector"`
+}
+
+type embeddingCache struct {
+	Model   string                  `json:"model"`
+	Entries map[string]cachedVector `json:"entries"`
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
+
+func loadEmbeddingCache(beatsDir, model string) *embeddingCache {
+	cache := &embeddingCache{Model: model, Entries: map[string]cachedVector{}}
+	data, err := os.ReadFile(filepath.Join(beatsDir, EmbeddingsFile))
+	if err != nil {
+		return cache
+	}
+	var loaded embeddingCache
+	if json.Unmarshal(data, &loaded) != nil || loaded.Model != model || loaded.Entries == nil {
+		return cache // Different model: vectors are not comparable
+	}
+	return &loaded
+}
+
+func (c *embeddingCache) save(beatsDir string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(beatsDir, EmbeddingsFile), data, 0644)
+}
+
+// Embeddings returns a vector per bead, embedding only beads that are new or
+// whose title/description changed since they were last cached. When
+// embedding fails part way, as when ctx runs out before a large bead list is
+// done, the vectors so far are cached and returned with the error, so the
+// next call picks up where this one stopped.
+func Embeddings(ctx context.Context, beatsDir string, list []Bead, ollama *embeddings.OllamaClient) (map[string][]float64, error) {
+	cache := loadEmbeddingCache(beatsDir, ollama.Model())
+	vectors := make(map[string][]float64, len(list))
+	dirty := false
+	var err error
+
+	for _, b := range list {
+		text := b.Text()
+		hash := contentHash(text)
+		if cached, ok := cache.Entries[b.ID]; ok && cached.Hash == hash {
+			vectors[b.ID] = cached.Vector
+			continue
+		}
+		if err != nil {
+			continue // Keep collecting cached vectors
+		}
+		var vec []float64
+		if vec, err = ollama.GetEmbedding(ctx, text); err != nil {
+			continue
+		}
+		cache.Entries[b.ID] = cachedVector{Hash: hash, Vector: vec}
+		vectors[b.ID] = vec
+		dirty = true
+	}
+
+	if dirty {
+		_ = cache.save(beatsDir)
+	}
+	return vectors, err
+}
+
+// Suggest ranks beads by similarity to a beat embedding, keeping those at or
+// above threshold. Beads already linked are skipped.
+func Suggest(beatVector []float64, list []Bead, vectors map[string][]float64, threshold float64, limit int, exclude []string) []Suggestion {
+	skip := make(map[string]bool, len(exclude))
+	for _, id := range exclude {
+		skip[id] = true
+	}
+
+	var out []Suggestion
+	for _, b := range list {
+		if skip[b.ID] || b.Status == "closed" {
+			continue
+		}
+		vec, ok := vectors[b.ID]
+		if !ok {
+			continue
+		}
+		if sim := embeddings.CosineSimilarity(beatVector, vec); sim >= threshold {
+			out = append(out, Suggestion{BeadID: b.ID, Title: b.Title, Confidence: sim})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Confidence > out[j].Confidence
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
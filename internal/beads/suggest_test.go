@@ -0,0 +1,196 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/embeddings"
+)
+
+// fakeOllama serves embeddings computed by embed and returns a client for
+// it using model. It records every text embedded; embed returning nil fails
+// the request.
+func fakeOllama(t *testing.T, model string, embed func(text string) []float64) (*embeddings.OllamaClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var embedded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			fmt.Fprintf(w, `{"models": [{"name": %q}]}`, model)
+		case "/api/embeddings":
+			var req struct{ Prompt string }
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			vec := embed(req.Prompt)
+			if vec == nil {
+				http.Error(w, "cannot embed", http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			embedded = append(embedded, req.Prompt)
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string][]float64{"embedding": vec})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("BEATS_OLLAMA_URL", server.URL)
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	client := embeddings.NewOllamaClient()
+	client.SetModel(model)
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), embedded...)
+	}
+}
+
+// topicVector embeds text by whether it is about login, search or billing.
+func topicVector(text string) []float64 {
+	text = strings.ToLower(text)
+	vec := make([]float64, 3)
+	for i, topic := range []string{"login", "search", "billing"} {
+		if strings.Contains(text, topic) {
+			vec[i] = 1
+		}
+	}
+	return vec
+}
+
+var suggestBeads = []Bead{
+	{ID: "bd-1", Title: "Fix login", Description: "Sessions expire during login", Status: "open"},
+	{ID: "bd-2", Title: "Search ranking", Status: "open"},
+	{ID: "bd-3", Title: "Billing export", Status: "open"},
+	{ID: "bd-4", Title: "Old login bug", Status: "closed"},
+}
+
+func TestEmbeddingsCache(t *testing.T) {
+	ollama, embedded := fakeOllama(t, "test-embed", topicVector)
+	dir := t.TempDir()
+
+	vectors, err := Embeddings(context.Background(), dir, suggestBeads, ollama)
+	if err != nil || len(vectors) != len(suggestBeads) || len(embedded()) != len(suggestBeads) {
+		t.Fatalf("first run: %d vectors, %d embedded, %v", len(vectors), len(embedded()), err)
+	}
+
+	// Only a bead whose text changed is embedded again.
+	changed := append([]Bead(nil), suggestBeads...)
+	changed[1].Description = "Rank search results by recency"
+	if _, err := Embeddings(context.Background(), dir, changed, ollama); err != nil {
+		t.Fatal(err)
+	}
+	if got := embedded()[len(suggestBeads):]; len(got) != 1 || got[0] != changed[1].Text() {
+		t.Errorf("second run embedded %q, want only the changed bead", got)
+	}
+
+	// Another model's vectors are not comparable, so all are redone.
+	ollama.SetModel("other-embed")
+	if _, err := Embeddings(context.Background(), dir, changed, ollama); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(embedded()); n != 2*len(suggestBeads)+1 {
+		t.Errorf("after a model change %d texts embedded in all, want %d", n, 2*len(suggestBeads)+1)
+	}
+}
+
+func TestEmbeddingsPartial(t *testing.T) {
+	fail := "Billing export"
+	ollama, embedded := fakeOllama(t, "test-embed", func(text string) []float64 {
+		if text == fail {
+			return nil
+		}
+		return topicVector(text)
+	})
+	dir := t.TempDir()
+
+	// A failure keeps what was embedded before it, cached and returned.
+	vectors, err := Embeddings(context.Background(), dir, suggestBeads, ollama)
+	if err == nil {
+		t.Fatal("no error from a failed embedding")
+	}
+	if len(vectors) != 2 || vectors["bd-1"] == nil || vectors["bd-2"] == nil {
+		t.Errorf("vectors after failure = %v, want bd-1 and bd-2", vectors)
+	}
+
+	// The next call embeds only what is left.
+	fail = ""
+	vectors, err = Embeddings(context.Background(), dir, suggestBeads, ollama)
+	if err != nil || len(vectors) != len(suggestBeads) {
+		t.Fatalf("resumed: %d vectors, %v", len(vectors), err)
+	}
+	if got := embedded(); len(got) != len(suggestBeads) {
+		t.Errorf("embedded %q, want each bead once", got)
+	}
+}
+
+func TestEmbeddingsTimeout(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	ollama, _ := fakeOllama(t, "test-embed", func(text string) []float64 {
+		mu.Lock()
+		calls++
+		slow := calls > 1
+		mu.Unlock()
+		if slow {
+			time.Sleep(500 * time.Millisecond)
+		}
+		return topicVector(text)
+	})
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	vectors, err := Embeddings(ctx, dir, suggestBeads, ollama)
+	if err == nil || len(vectors) != 1 || vectors["bd-1"] == nil {
+		t.Fatalf("on timeout: vectors %v, err %v; want bd-1 and an error", vectors, err)
+	}
+	if cache := loadEmbeddingCache(dir, "test-embed"); len(cache.Entries) != 1 {
+		t.Errorf("cache after timeout = %v, want bd-1 saved", cache.Entries)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	vectors := map[string][]float64{}
+	for _, b := range suggestBeads {
+		vectors[b.ID] = topicVector(b.Text())
+	}
+	beatVector := topicVector("login is slow and search is broken")
+	ids := func(list []Suggestion) []string {
+		var out []string
+		for _, s := range list {
+			out = append(out, s.BeadID)
+		}
+		return out
+	}
+
+	// Closed beads are never suggested; the best match comes first.
+	got := Suggest(beatVector, suggestBeads, vectors, 0.5, 0, nil)
+	if strings.Join(ids(got), ",") != "bd-1,bd-2" || got[0].Confidence < got[1].Confidence || got[0].Title != "Fix login" {
+		t.Errorf("suggestions = %+v, want bd-1 then bd-2", got)
+	}
+	if got := Suggest(beatVector, suggestBeads, vectors, 0.5, 1, nil); len(got) != 1 {
+		t.Errorf("limit 1 gave %+v", got)
+	}
+	if got := Suggest(beatVector, suggestBeads, vectors, 0.5, 0, []string{"bd-1"}); strings.Join(ids(got), ",") != "bd-2" {
+		t.Errorf("with bd-1 linked = %v, want bd-2", ids(got))
+	}
+	if got := Suggest(beatVector, suggestBeads, vectors, 0.99, 0, nil); len(got) != 0 {
+		t.Errorf("threshold 0.99 gave %+v", got)
+	}
+
+	// A bead without a vector yet is left out rather than guessed.
+	delete(vectors, "bd-2")
+	if got := Suggest(beatVector, suggestBeads, vectors, 0.5, 0, nil); strings.Join(ids(got), ",") != "bd-1" {
+		t.Errorf("without bd-2's vector = %v", ids(got))
+	}
+}
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// errNotFound marks a bead the tracker does not have.
+var errNotFound = errors.New("not found")
+
+// maxListed bounds how many issues List fetches from a tracker.
+const maxListed = 500
+
+var httpClient = &http.Client{Timeout: 20 * time.Second}
+
+// trackerRequest sends a JSON request and decodes a JSON response into out.
+// A 404 is errNotFound; other failures carry the tracker's message.
+func trackerRequest(method, url string, headers map[string]string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+	if resp.StatusCode >= 300 {
+		msg := string(bytes.TrimSpace(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return fmt.Errorf("%s %s: status %d: %s", method, url, resp.StatusCode, msg)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// lookupEach resolves ids one request at a time, skipping unknown ones.
+func lookupEach(ids []string, get func(id string) (Bead, error)) (map[string]Bead, error) {
+	found := make(map[string]Bead)
+	for _, id := range ids {
+		b, err := get(id)
+		if errors.Is(err, errNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found[id] = b
+	}
+	return found, nil
+}
@@ -0,0 +1,187 @@
+package beads
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHubProvider(t *testing.T) {
+	var comment string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/acme/app/issues/12":
+			_, _ = io.WriteString(w, `{"number":12,"title":"Fix login","state":"closed","html_url":"https://github.com/acme/app/issues/12"}`)
+		case "GET /repos/acme/app/issues":
+			_, _ = io.WriteString(w, `[{"number":12,"title":"Fix login","state":"closed"},{"number":13,"title":"A PR","state":"open","pull_request":{"url":"x"}}]`)
+		case "POST /repos/acme/app/issues":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["title"] != "SSO epic" || !strings.Contains(body["body"].(string), "beat-1") {
+				t.Errorf("create body = %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"number":14,"title":"SSO epic","state":"open"}`)
+		case "POST /repos/acme/app/issues/12/comments":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			comment = body["body"]
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := Config{Provider: "github", GitHub: GitHubConfig{Repo: "acme/app", Token: "tok", APIURL: srv.URL}}.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := p.Lookup([]string{"#12", "acme/app#12", "https://github.com/acme/app/issues/12", "99", "not-an-issue"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 3 {
+		t.Fatalf("Lookup found %d, want 3: %v", len(found), found)
+	}
+	b := found["#12"]
+	if b.ID != "acme/app#12" || b.Title != "Fix login" || b.Status != "closed" || b.URL == "" {
+		t.Errorf("Lookup(#12) = %+v", b)
+	}
+
+	list, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != "acme/app#12" {
+		t.Errorf("List = %+v, want only the issue", list)
+	}
+
+	if err := p.(Commenter).Comment("acme/app#12", "Narrative context available: beat-1"); err != nil {
+		t.Fatal(err)
+	}
+	if comment != "Narrative context available: beat-1" {
+		t.Errorf("comment = %q", comment)
+	}
+
+	created, err := p.(Creator).Create(Draft{Title: "SSO epic", Description: "From beat-1", Type: "epic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "acme/app#14" {
+		t.Errorf("Create ID = %q, want acme/app#14", created.ID)
+	}
+}
+
+func TestLinearProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.Contains(req.Query, "commentCreate"):
+			_, _ = io.WriteString(w, `{"data":{"commentCreate":{"success":true}}}`)
+		case strings.Contains(req.Query, "issues("):
+			_, _ = io.WriteString(w, `{"data":{"issues":{"nodes":[{"identifier":"ENG-1","title":"Onboarding","state":{"type":"started"}}],"pageInfo":{"hasNextPage":false}}}}`)
+		case req.Variables["id"] == "ENG-1":
+			_, _ = io.WriteString(w, `{"data":{"issue":{"identifier":"ENG-1","title":"Onboarding","url":"https://linear.app/x/issue/ENG-1","state":{"type":"completed"}}}}`)
+		default:
+			_, _ = io.WriteString(w, `{"data":null,"errors":[{"message":"Entity not found: Issue"}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := Config{Provider: "linear", Linear: LinearConfig{APIKey: "key", APIURL: srv.URL}}.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := p.Lookup([]string{"ENG-1", "ENG-404"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found["ENG-1"].Status != "closed" {
+		t.Fatalf("Lookup = %+v", found)
+	}
+	list, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Status != "in_progress" {
+		t.Errorf("List = %+v", list)
+	}
+	if err := p.(Commenter).Comment("ENG-1", "hi"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJiraProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "tok" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/issue/OPS-7":
+			_, _ = io.WriteString(w, `{"key":"OPS-7","fields":{"summary":"Rotate keys","description":"Quarterly","status":{"statusCategory":{"key":"indeterminate"}}}}`)
+		case "GET /rest/api/2/search":
+			if jql := r.URL.Query().Get("jql"); !strings.Contains(jql, `project = "OPS"`) {
+				t.Errorf("jql = %q", jql)
+			}
+			_, _ = io.WriteString(w, `{"total":1,"issues":[{"key":"OPS-7","fields":{"summary":"Rotate keys","status":{"statusCategory":{"key":"done"}}}}]}`)
+		case "POST /rest/api/2/issue":
+			var body struct {
+				Fields struct {
+					Project   map[string]string `json:"project"`
+					IssueType map[string]string `json:"issuetype"`
+				} `json:"fields"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Fields.Project["key"] != "OPS" || body.Fields.IssueType["name"] != "Epic" {
+				t.Errorf("create fields = %+v", body.Fields)
+			}
+			_, _ = io.WriteString(w, `{"key":"OPS-9"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := Config{Provider: "jira", Jira: JiraConfig{URL: srv.URL, Email: "me@example.com", Token: "tok", Project: "OPS"}}.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := p.Lookup([]string{"ops-7", "OPS-8", "bd-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := found["ops-7"]
+	if len(found) != 1 || !ok || b.ID != "OPS-7" || b.Status != "in_progress" || b.URL != srv.URL+"/browse/OPS-7" {
+		t.Fatalf("Lookup = %+v", found)
+	}
+	list, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Status != "closed" {
+		t.Errorf("List = %+v", list)
+	}
+
+	created, err := p.(Creator).Create(Draft{Title: "Key rotation", Type: "epic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "OPS-9" || created.URL != srv.URL+"/browse/OPS-9" {
+		t.Errorf("Create = %+v", created)
+	}
+}
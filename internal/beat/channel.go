@@ -0,0 +1,53 @@
+package beat
+
+// ChannelMeta is the impetus meta key recording the capture channel: the
+// path a beat came in by, as opposed to "source", which names where its
+// content came from (a URL, an importer, a feed).
+const ChannelMeta = "channel"
+
+// Capture channels.
+const (
+	ChannelManual    = "manual"    // bt add
+	ChannelRobot     = "robot"     // --robot-commit-beat
+	ChannelSession   = "session"   // The session-end hook and the session watcher
+	ChannelWeb       = "web"       // serve-capture: the bookmarklet, share targets and other HTTP clients
+	ChannelWebhook   = "webhook"   // serve-capture webhook endpoints, such as a chat bot
+	ChannelCapture   = "capture"   // bt capture: posts, papers, issues, episodes and commits
+	ChannelDrop      = "drop"      // Files left in a watched drop directory
+	ChannelImport    = "import"    // Importers: notes apps, calendars, git history
+	ChannelGenerated = "generated" // Beats bt writes itself, such as digests and weekly reviews
+	ChannelAPI       = "api"       // Go programs embedding the store through pkg/beats
+	ChannelUnknown   = "unknown"   // Captured before channels were recorded, with nothing to tell
+)
+
+// Channel returns the beat's capture channel. Beats captured before
+// channels were recorded get one inferred from their meta where it is
+// unambiguous, and ChannelUnknown otherwise.
+func (b *Beat) Channel() string {
+	meta := b.Impetus.Meta
+	switch {
+	case meta[ChannelMeta] != "":
+		return meta[ChannelMeta]
+	case meta["kind"] == "digest" || meta["kind"] == "weekly_review":
+		return ChannelGenerated
+	case meta["webhook"] != "":
+		return ChannelWebhook
+	case b.Impetus.Label == "Session" && meta["session_id"] != "":
+		return ChannelSession
+	case meta["source_id"] != "":
+		return ChannelImport
+	}
+	return ChannelUnknown
+}
+
+// SetChannel records the channel a proposed beat came in by, unless it
+// already has one (a capture accepted from review keeps its own).
+func (p *ProposedBeat) SetChannel(channel string) {
+	if channel == "" || p.Impetus.Meta[ChannelMeta] != "" {
+		return
+	}
+	if p.Impetus.Meta == nil {
+		p.Impetus.Meta = make(map[string]string)
+	}
+	p.Impetus.Meta[ChannelMeta] = channel
+}
@@ -0,0 +1,51 @@
+package beat
+
+import "regexp"
+
+// CitationSubtype marks a beat reference parsed from a [[beat-id]] citation
+// in the content, as opposed to one added by hand or by a generator.
+const CitationSubtype = "citation"
+
+var citationPattern = regexp.MustCompile(`\[\[(beat-\d{8}-\d{3,})\]\]`)
+
+// Citations returns the beat IDs cited as [[beat-20251204-001]] in content,
+// once each, in order.
+func Citations(content string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, m := range citationPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+// LinkCitations brings the citation references in refs in line with the
+// citations in content: cited beats gain a beat reference and citations
+// that were removed lose theirs. Other references are kept as they are.
+func LinkCitations(refs []Reference, content string) []Reference {
+	cited := Citations(content)
+	want := make(map[string]bool, len(cited))
+	for _, id := range cited {
+		want[id] = true
+	}
+	out := make([]Reference, 0, len(refs)+len(cited))
+	have := make(map[string]bool)
+	for _, r := range refs {
+		if r.Kind == "beat" {
+			if r.Subtype == CitationSubtype && !want[r.Locator] {
+				continue
+			}
+			have[r.Locator] = true
+		}
+		out = append(out, r)
+	}
+	for _, id := range cited {
+		if !have[id] {
+			out = append(out, Reference{Kind: "beat", Subtype: CitationSubtype, Locator: id})
+		}
+	}
+	return out
+}
@@ -0,0 +1,279 @@
+package beat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Relations a beat can have to a bead.
+const (
+	RelationSeed          = "seed"          // The beat motivated the bead (default)
+	RelationEvidence      = "evidence"      // The beat supports or informs the bead
+	RelationBlocks        = "blocks"        // The beat records something blocking the bead
+	RelationRetrospective = "retrospective" // The beat reflects on the bead after the fact
+)
+
+// Relations lists the valid link relations.
+var Relations = []string{RelationSeed, RelationEvidence, RelationBlocks, RelationRetrospective}
+
+// ValidRelation reports whether r is a known relation.
+func ValidRelation(r string) bool {
+	for _, v := range Relations {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// BeadLink links a beat to a bead.
+type BeadLink struct {
+	BeadID    string    `json:"bead_id"`
+	Relation  string    `json:"relation"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// BeadLinks is a beat's links to beads, at most one per bead.
+type BeadLinks []BeadLink
+
+// UnmarshalJSON accepts link objects as well as the older plain array of
+// bead IDs, which become seed links without a timestamp.
+func (l *BeadLinks) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*l = nil
+		return nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	links := make(BeadLinks, 0, len(raw))
+	for _, item := range raw {
+		var link BeadLink
+		if len(item) > 0 && item[0] == '"' {
+			if err := json.Unmarshal(item, &link.BeadID); err != nil {
+				return err
+			}
+		} else if err := json.Unmarshal(item, &link); err != nil {
+			return fmt.Errorf("invalid bead link: %w", err)
+		}
+		if link.Relation == "" {
+			link.Relation = RelationSeed
+		}
+		links = append(links, link)
+	}
+	*l = links
+	return nil
+}
+
+// NewBeadLinks links each of ids with the same relation, time and author.
+func NewBeadLinks(ids []string, relation, by string, at time.Time) BeadLinks {
+	links := make(BeadLinks, 0, len(ids))
+	for _, id := range ids {
+		links.Add(BeadLink{BeadID: id, Relation: relation, CreatedAt: at, CreatedBy: by})
+	}
+	return links
+}
+
+// IDs returns the linked bead IDs in link order.
+func (l BeadLinks) IDs() []string {
+	ids := make([]string, len(l))
+	for i, link := range l {
+		ids[i] = link.BeadID
+	}
+	return ids
+}
+
+// Get returns the link to a bead.
+func (l BeadLinks) Get(id string) (BeadLink, bool) {
+	for _, link := range l {
+		if link.BeadID == id {
+			return link, true
+		}
+	}
+	return BeadLink{}, false
+}
+
+// Has reports whether the bead is linked.
+func (l BeadLinks) Has(id string) bool {
+	_, ok := l.Get(id)
+	return ok
+}
+
+// Add links a bead, defaulting the relation to seed. An existing link to
+// the bead keeps its history and only takes the new relation, if one is
+// given; Add reports whether anything changed.
+func (l *BeadLinks) Add(link BeadLink) bool {
+	for i := range *l {
+		if (*l)[i].BeadID == link.BeadID {
+			if link.Relation == "" || (*l)[i].Relation == link.Relation {
+				return false
+			}
+			(*l)[i].Relation = link.Relation
+			return true
+		}
+	}
+	if link.Relation == "" {
+		link.Relation = RelationSeed
+	}
+	*l = append(*l, link)
+	return true
+}
+
+// Remove unlinks the given beads and returns how many were linked.
+func (l *BeadLinks) Remove(ids ...string) int {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	kept := (*l)[:0]
+	removed := 0
+	for _, link := range *l {
+		if drop[link.BeadID] {
+			removed++
+			continue
+		}
+		kept = append(kept, link)
+	}
+	*l = kept
+	return removed
+}
+
+// Rename moves the link to bead from over to bead to, keeping its relation
+// and history. If to is linked already, that link is kept and the one to
+// from dropped. It reports whether from was linked.
+func (l *BeadLinks) Rename(from, to string) bool {
+	i := -1
+	for j, link := range *l {
+		if link.BeadID == from {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return false
+	}
+	if l.Has(to) {
+		l.Remove(from)
+		return true
+	}
+	(*l)[i].BeadID = to
+	return true
+}
+
+// RenameBead points the beat at bead to wherever it names bead from: its
+// link (see BeadLinks.Rename), and impetus meta about the bead, namely
+// bead_*:<id> keys such as bead_closed and bead_unlinked, and promoted_to.
+// It reports whether anything changed.
+func (b *Beat) RenameBead(from, to string) bool {
+	changed := b.LinkedBeads.Rename(from, to)
+	var keys []string
+	for k := range b.Impetus.Meta {
+		if strings.HasPrefix(k, "bead_") && strings.HasSuffix(k, ":"+from) {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		b.Impetus.Meta[strings.TrimSuffix(k, from)+to] = b.Impetus.Meta[k]
+		delete(b.Impetus.Meta, k)
+		changed = true
+	}
+	if b.Impetus.Meta["promoted_to"] == from {
+		b.Impetus.Meta["promoted_to"] = to
+		changed = true
+	}
+	return changed
+}
+
+// UnlinkedMetaPrefix prefixes the impetus meta key recording when a bead
+// was unlinked from a beat (bead_unlinked:<id> = RFC 3339 time).
+const UnlinkedMetaPrefix = "bead_unlinked:"
+
+// UnlinkBeads removes the beat's links to ids and records when each linked
+// one was removed, so diffs can report lost links. It returns how many were
+// linked.
+func (b *Beat) UnlinkBeads(at time.Time, ids ...string) int {
+	stamp := at.UTC().Format(time.RFC3339)
+	removed := 0
+	for _, id := range ids {
+		if b.LinkedBeads.Remove(id) == 0 {
+			continue
+		}
+		if b.Impetus.Meta == nil {
+			b.Impetus.Meta = make(map[string]string)
+		}
+		b.Impetus.Meta[UnlinkedMetaPrefix+id] = stamp
+		removed++
+	}
+	return removed
+}
+
+// LinkEvent is a beat linked to or unlinked from a bead.
+type LinkEvent struct {
+	BeatID   string    `json:"beat_id"`
+	Relation string    `json:"relation,omitempty"`
+	At       time.Time `json:"at"`
+	By       string    `json:"by,omitempty"`
+}
+
+// BeadLinkChange summarizes how a bead's beat links changed.
+type BeadLinkChange struct {
+	BeadID      string      `json:"bead_id"`
+	Gained      []LinkEvent `json:"gained"`
+	Lost        []LinkEvent `json:"lost"`
+	LinkedBeats int         `json:"linked_beats"` // Beats linked now
+}
+
+// BeadLinkChanges reports, per bead, the links created and removed after
+// since, from link timestamps and bead_unlinked meta. Links without a
+// timestamp (from before links were typed) are never reported as gained.
+func BeadLinkChanges(beats []Beat, since time.Time) []BeadLinkChange {
+	byBead := make(map[string]*BeadLinkChange)
+	change := func(id string) *BeadLinkChange {
+		c := byBead[id]
+		if c == nil {
+			c = &BeadLinkChange{BeadID: id, Gained: []LinkEvent{}, Lost: []LinkEvent{}}
+			byBead[id] = c
+		}
+		return c
+	}
+	linked := make(map[string]int)
+	for _, b := range beats {
+		for _, link := range b.LinkedBeads {
+			linked[link.BeadID]++
+			if link.CreatedAt.After(since) {
+				c := change(link.BeadID)
+				c.Gained = append(c.Gained, LinkEvent{BeatID: b.ID, Relation: link.Relation, At: link.CreatedAt, By: link.CreatedBy})
+			}
+		}
+		for key, value := range b.Impetus.Meta {
+			id, ok := strings.CutPrefix(key, UnlinkedMetaPrefix)
+			if !ok || b.LinkedBeads.Has(id) {
+				continue
+			}
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil || !at.After(since) {
+				continue
+			}
+			c := change(id)
+			c.Lost = append(c.Lost, LinkEvent{BeatID: b.ID, At: at})
+		}
+	}
+
+	changes := make([]BeadLinkChange, 0, len(byBead))
+	for id, c := range byBead {
+		c.LinkedBeats = linked[id]
+		for _, events := range [][]LinkEvent{c.Gained, c.Lost} {
+			sort.Slice(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+		}
+		changes = append(changes, *c)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].BeadID < changes[j].BeadID })
+	return changes
+}
@@ -2,7 +2,10 @@ package beat
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
 )
 
 // Beat is a minimally structured, AI-indexable narrative unit.
@@ -13,13 +16,23 @@ type Beat struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
-	Impetus     Impetus     `json:"impetus"`
-	Content     string      `json:"content"`
-	References  []Reference `json:"references,omitempty"`
-	Entities    []Entity    `json:"entities,omitempty"`
-	LinkedBeads []string    `json:"linked_beads,omitempty"`
-	SessionID   string      `json:"session_id,omitempty"`
-	Context     *Context    `json:"context,omitempty"`
+	Impetus     Impetus      `json:"impetus"`
+	Content     string       `json:"content"`
+	References  []Reference  `json:"references,omitempty"`
+	Entities    []Entity     `json:"entities,omitempty"`
+	Relations   []Relation   `json:"relations,omitempty"`
+	LinkedBeads BeadLinks    `json:"linked_beads,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	SessionID   string       `json:"session_id,omitempty"`
+	Context     *Context     `json:"context,omitempty"`
+}
+
+// Annotation is a later judgment on a beat, such as "this turned out wrong",
+// layered onto the original capture instead of editing it.
+type Annotation struct {
+	Note      string    `json:"note"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Context captures the WALD directory context where the beat was captured.
@@ -31,6 +44,16 @@ type Context struct {
 	Confidence      float64 `json:"confidence,omitempty"`       // Confidence score 0-1
 }
 
+// InWALD reports whether the beat was filed under a WALD directory or one
+// of its subdirectories.
+func (b *Beat) InWALD(dir string) bool {
+	if b.Context == nil || b.Context.WALDDirectory == "" {
+		return false
+	}
+	dir = strings.TrimSuffix(dir, "/")
+	return b.Context.WALDDirectory == dir || strings.HasPrefix(b.Context.WALDDirectory, dir+"/")
+}
+
 // Impetus captures the origin/motivation for recording a beat.
 type Impetus struct {
 	Label string            `json:"label"`
@@ -54,9 +77,17 @@ type Entity struct {
 	Meta     map[string]string `json:"meta,omitempty"`
 }
 
+// Relation is a typed link between two of a beat's entities, by label, such
+// as {"from": "Ada", "to": "Acme", "type": "works_at"}.
+type Relation struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
 // NewBeat creates a new Beat with auto-generated ID and timestamps.
 func NewBeat(content string, impetus Impetus) *Beat {
-	now := time.Now().UTC()
+	now := clock.Now().UTC()
 	return &Beat{
 		ID:          GenerateID(now),
 		CreatedAt:   now,
@@ -65,19 +96,28 @@ func NewBeat(content string, impetus Impetus) *Beat {
 		Content:     content,
 		References:  []Reference{},
 		Entities:    []Entity{},
-		LinkedBeads: []string{},
+		LinkedBeads: BeadLinks{},
 	}
 }
 
+// IDLocation is the time zone whose calendar day new IDs carry. It is UTC
+// unless id_dates is set to local in the config.
+var IDLocation = time.UTC
+
+// IDDate returns the YYYYMMDD part of the ID of a beat created at t.
+func IDDate(t time.Time) string {
+	return t.In(IDLocation).Format("20060102")
+}
+
 // GenerateID creates a beat ID in the format: beat-YYYYMMDD-NNN
 // The NNN suffix should be unique within the day; caller must ensure uniqueness.
 func GenerateID(t time.Time) string {
-	return fmt.Sprintf("beat-%s-%03d", t.Format("20060102"), 1)
+	return fmt.Sprintf("beat-%s-%03d", IDDate(t), 1)
 }
 
 // GenerateIDWithSequence creates a beat ID with a specific sequence number.
 func GenerateIDWithSequence(t time.Time, seq int) string {
-	return fmt.Sprintf("beat-%s-%03d", t.Format("20060102"), seq)
+	return fmt.Sprintf("beat-%s-%03d", IDDate(t), seq)
 }
 
 // ProposedBeat is a beat without ID/timestamps, used for robot-commit-beat input.
@@ -86,13 +126,15 @@ type ProposedBeat struct {
 	Impetus     Impetus     `json:"impetus"`
 	References  []Reference `json:"references,omitempty"`
 	Entities    []Entity    `json:"entities,omitempty"`
+	Relations   []Relation  `json:"relations,omitempty"`
 	LinkedBeads []string    `json:"linked_beads,omitempty"`
 	CreatedAt   *time.Time  `json:"created_at,omitempty"`
+	Context     *Context    `json:"context,omitempty"` // Set to assign a WALD directory; inferred otherwise
 }
 
 // ToBeat converts a ProposedBeat to a full Beat with ID and timestamps.
 func (p *ProposedBeat) ToBeat(seq int) *Beat {
-	t := time.Now().UTC()
+	t := clock.Now().UTC()
 	if p.CreatedAt != nil {
 		t = p.CreatedAt.UTC()
 	}
@@ -104,7 +146,9 @@ func (p *ProposedBeat) ToBeat(seq int) *Beat {
 		Content:     p.Content,
 		References:  p.References,
 		Entities:    p.Entities,
-		LinkedBeads: p.LinkedBeads,
+		Relations:   p.Relations,
+		LinkedBeads: NewBeadLinks(p.LinkedBeads, RelationSeed, "", clock.Now().UTC()),
+		Context:     p.Context,
 	}
 }
 
@@ -123,9 +167,23 @@ type BriefOutput struct {
 }
 
 // ContextForBeadOutput is the output of --robot-context-for-bead.
+// SeedBeats are confirmed context: beats explicitly linked to the bead.
 type ContextForBeadOutput struct {
 	BeadID    string `json:"bead_id"`
 	SeedBeats []Beat `json:"seed_beats"`
+
+	// Filled when expansion was requested.
+	SuggestedBeats []SuggestedBeat `json:"suggested_beats,omitempty"`
+	ExpandQuery    string          `json:"expand_query,omitempty"`
+	ExpandMode     string          `json:"expand_mode,omitempty"` // semantic or tfidf
+}
+
+// SuggestedBeat is an unlinked beat similar to a bead, offered as possible
+// context.
+type SuggestedBeat struct {
+	Beat
+	Context string  `json:"context"` // Always "suggested"; linked beats are "confirmed"
+	Score   float64 `json:"score"`
 }
 
 // ProposedEpic represents a suggested new epic derived from beats.
@@ -155,4 +213,6 @@ type DiffOutput struct {
 	ModifiedBeats      []Beat   `json:"modified_beats"`
 	BeatsLinkedToBeads []Beat   `json:"beats_linked_to_beads"`
 	DeletedIDs         []string `json:"deleted_ids"`
+	// BeadChanges lists beads that gained or lost links, when requested.
+	BeadChanges []BeadLinkChange `json:"bead_changes,omitzero"`
 }
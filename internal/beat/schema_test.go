@@ -1,6 +1,7 @@
 package beat
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -61,6 +62,24 @@ func TestGenerateIDWithSequence(t *testing.T) {
 	}
 }
 
+func TestGenerateIDLocalDates(t *testing.T) {
+	// 23:30 in New York is already the next day in UTC
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	evening := time.Date(2025, 12, 11, 23, 30, 0, 0, ny).UTC()
+	if got := GenerateID(evening); got != "beat-20251212-001" {
+		t.Errorf("GenerateID() with UTC dates = %q, want the UTC day", got)
+	}
+
+	IDLocation = ny
+	defer func() { IDLocation = time.UTC }()
+	if got := GenerateID(evening); got != "beat-20251211-001" {
+		t.Errorf("GenerateID() with local dates = %q, want the New York day", got)
+	}
+}
+
 func TestProposedBeat_ToBeat(t *testing.T) {
 	proposed := &ProposedBeat{
 		Content:     "proposed content",
@@ -101,3 +120,95 @@ func TestProposedBeat_ToBeatWithCreatedAt(t *testing.T) {
 		t.Errorf("CreatedAt = %v, want %v", b.CreatedAt, customTime)
 	}
 }
+
+func TestBeadLinksUnmarshal(t *testing.T) {
+	var b Beat
+	data := `{"id":"beat-20250101-001","linked_beads":["bd-1",{"bead_id":"bd-2","relation":"evidence","created_at":"2025-01-02T10:00:00Z","created_by":"agent"}]}`
+	if err := json.Unmarshal([]byte(data), &b); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.LinkedBeads) != 2 {
+		t.Fatalf("LinkedBeads = %+v", b.LinkedBeads)
+	}
+	if l := b.LinkedBeads[0]; l.BeadID != "bd-1" || l.Relation != RelationSeed || !l.CreatedAt.IsZero() {
+		t.Errorf("legacy link = %+v", l)
+	}
+	if l := b.LinkedBeads[1]; l.Relation != RelationEvidence || l.CreatedBy != "agent" || l.CreatedAt.IsZero() {
+		t.Errorf("typed link = %+v", l)
+	}
+
+	out, err := json.Marshal(b.LinkedBeads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"bead_id":"bd-1","relation":"seed"},{"bead_id":"bd-2","relation":"evidence","created_at":"2025-01-02T10:00:00Z","created_by":"agent"}]`; string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+}
+
+func TestBeadLinksAddRemove(t *testing.T) {
+	var links BeadLinks
+	if !links.Add(BeadLink{BeadID: "bd-1"}) || links[0].Relation != RelationSeed {
+		t.Fatalf("Add new = %+v", links)
+	}
+	if links.Add(BeadLink{BeadID: "bd-1"}) {
+		t.Error("Add without relation changed an existing link")
+	}
+	if !links.Add(BeadLink{BeadID: "bd-1", Relation: RelationBlocks}) || len(links) != 1 || links[0].Relation != RelationBlocks {
+		t.Errorf("Add relation = %+v", links)
+	}
+	links.Add(BeadLink{BeadID: "bd-2"})
+	if n := links.Remove("bd-1", "bd-9"); n != 1 || len(links) != 1 || links[0].BeadID != "bd-2" {
+		t.Errorf("Remove = %d, %+v", n, links)
+	}
+}
+
+func TestBeadLinkChanges(t *testing.T) {
+	since := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	before, after := since.Add(-time.Hour), since.Add(time.Hour)
+
+	a := Beat{ID: "beat-a", LinkedBeads: BeadLinks{
+		{BeadID: "bd-1", Relation: RelationSeed, CreatedAt: before},
+		{BeadID: "bd-2", Relation: RelationEvidence, CreatedAt: after, CreatedBy: "robot"},
+		{BeadID: "bd-3", Relation: RelationSeed}, // Legacy link, no timestamp
+	}}
+	b := Beat{ID: "beat-b", LinkedBeads: BeadLinks{{BeadID: "bd-1", Relation: RelationSeed, CreatedAt: before}}}
+	b.UnlinkBeads(after, "bd-1", "bd-9")
+	if len(b.LinkedBeads) != 0 || b.Impetus.Meta[UnlinkedMetaPrefix+"bd-1"] == "" {
+		t.Fatalf("UnlinkBeads left %+v, meta %v", b.LinkedBeads, b.Impetus.Meta)
+	}
+	if _, ok := b.Impetus.Meta[UnlinkedMetaPrefix+"bd-9"]; ok {
+		t.Error("UnlinkBeads recorded a bead that was not linked")
+	}
+
+	changes := BeadLinkChanges([]Beat{a, b}, since)
+	if len(changes) != 2 {
+		t.Fatalf("BeadLinkChanges = %+v, want bd-1 and bd-2", changes)
+	}
+	if c := changes[0]; c.BeadID != "bd-1" || len(c.Gained) != 0 || len(c.Lost) != 1 || c.Lost[0].BeatID != "beat-b" || c.LinkedBeats != 1 {
+		t.Errorf("bd-1 = %+v", c)
+	}
+	if c := changes[1]; c.BeadID != "bd-2" || len(c.Gained) != 1 || c.Gained[0].Relation != RelationEvidence || c.Gained[0].By != "robot" {
+		t.Errorf("bd-2 = %+v", c)
+	}
+}
+
+func TestLinkCitations(t *testing.T) {
+	refs := []Reference{
+		{Kind: "url", Locator: "https://example.com"},
+		{Kind: "beat", Subtype: CitationSubtype, Locator: "beat-20251201-001"}, // No longer cited
+		{Kind: "beat", Locator: "beat-20251203-002"},                           // Added by hand
+	}
+	content := "Builds on [[beat-20251204-001]] and [[beat-20251203-002]], again [[beat-20251204-001]]; [[not-a-beat]]"
+
+	got := LinkCitations(refs, content)
+	want := []Reference{refs[0], refs[2], {Kind: "beat", Subtype: CitationSubtype, Locator: "beat-20251204-001"}}
+	if len(got) != len(want) {
+		t.Fatalf("LinkCitations = %+v; want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Kind != want[i].Kind || got[i].Subtype != want[i].Subtype || got[i].Locator != want[i].Locator {
+			t.Errorf("reference %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package brief renders the synthesis prompt for a topic: the beats that
+// match it, cut to a token budget and laid out by a built-in or named
+// template.
+package brief
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/tokens"
+	"github.com/bierlingm/beats/internal/wald"
+)
+
+// Input is the input for --robot-brief.
+type Input struct {
+	Topic     string   `json:"topic"`
+	Audience  string   `json:"audience,omitempty"`
+	MaxBeats  int      `json:"max_beats,omitempty"`
+	Wald      string   `json:"wald,omitempty"`       // Only beats filed under this WALD directory
+	MaxTokens *int     `json:"max_tokens,omitempty"` // Prompt token budget; 0 for none (default prompt_tokens)
+	Template  string   `json:"template,omitempty"`   // Named template in .beats/templates/briefs
+	Sections  []string `json:"sections,omitempty"`   // Sections the brief asks for, replacing the default
+	AsOf      string   `json:"as_of,omitempty"`      // Brief from the store as it was then
+	Provider  string   `json:"provider,omitempty"`   // LLM provider the brief is for, choosing its redaction profile
+
+	// Location is the zone templates show dates in (default: the configured
+	// time zone).
+	Location *time.Location `json:"-"`
+}
+
+// location is the zone templates show dates in.
+func (in Input) location() *time.Location {
+	if in.Location != nil {
+		return in.Location
+	}
+	if loc, err := config.Get().Location(); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Output is the output for --robot-brief.
+type Output struct {
+	Topic           string       `json:"topic"`
+	Audience        string       `json:"audience"`
+	Template        string       `json:"template,omitempty"`
+	BeatsUsed       []string     `json:"beats_used"`
+	BeatsData       []beat.Beat  `json:"beats_data"`
+	BriefPrompt     string       `json:"brief_prompt"`
+	EstimatedTokens int          `json:"estimated_tokens"`
+	TokenBudget     int          `json:"token_budget"`
+	BeatsOmitted    []string     `json:"beats_omitted,omitempty"` // Matches left out to fit the budget
+	Redaction       *redact.Info `json:"redaction,omitempty"`
+}
+
+// Error is a step of Build that failed, with the message robot output
+// reports it under.
+type Error struct {
+	Msg string
+	Err error
+}
+
+func (e *Error) Error() string { return e.Msg + ": " + e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// PromptBudget is the token budget for a prompt: maxTokens when given, else
+// the prompt_tokens setting.
+func PromptBudget(maxTokens *int) int {
+	if maxTokens != nil {
+		return max(*maxTokens, 0)
+	}
+	return config.Get().PromptBudget()
+}
+
+// Build searches s for the beats on a topic and renders the synthesis
+// prompt for them within the token budget.
+func Build(s *store.JSONLStore, in Input) (*Output, error) {
+	if in.Topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if in.AsOf != "" {
+		t, err := clock.ParseRelative(in.AsOf)
+		if err == nil {
+			s, err = s.AsOf(t)
+		}
+		if err != nil {
+			return nil, &Error{Msg: "failed to rebuild the store", Err: err}
+		}
+	}
+
+	audience := in.Audience
+	if audience == "" {
+		audience = "human"
+	}
+
+	maxBeats := in.MaxBeats
+	if maxBeats <= 0 {
+		maxBeats = 30
+	}
+
+	limit := maxBeats
+	if in.Wald != "" {
+		limit = 0 // All matches, narrowed to the directory below
+	}
+	results, err := s.Search(in.Topic, limit)
+	if err != nil {
+		return nil, &Error{Msg: "search failed", Err: err}
+	}
+	if in.Wald != "" {
+		if results, err = inWALD(s, results, in.Wald, maxBeats); err != nil {
+			return nil, &Error{Msg: "failed to read beats", Err: err}
+		}
+	}
+
+	// Get full beat data
+	beatIDs := make([]string, len(results))
+	for i, r := range results {
+		beatIDs[i] = r.ID
+	}
+	beatsData, err := s.GetByIDs(beatIDs)
+	if err != nil {
+		return nil, &Error{Msg: "failed to get beats", Err: err}
+	}
+
+	// Build beat summaries for prompt, keeping the best matches when they
+	// don't all fit the token budget
+	rank := make(map[string]int, len(beatIDs))
+	for i, id := range beatIDs {
+		rank[id] = i
+	}
+	beatSummaries := make([]string, len(beatsData))
+	priority := make([]int, len(beatsData))
+	for i, b := range beatsData {
+		beatSummaries[i] = fmt.Sprintf("- [%s] (%s) %s", b.ID, b.Impetus.Label, truncate(b.Content, 200))
+		priority[i] = i
+	}
+	sort.SliceStable(priority, func(i, j int) bool { return rank[beatsData[priority[i]].ID] < rank[beatsData[priority[j]].ID] })
+
+	audienceGuidance := "Write for a human reader - clear, concise, actionable."
+	if audience == "LLM" {
+		audienceGuidance = "Write for an LLM agent - structured, machine-parseable, include metadata."
+	}
+
+	tmpl, err := loadTemplate(s.Dir(), in.Template, in.location())
+	if err != nil {
+		return nil, &Error{Msg: "failed to load brief template", Err: err}
+	}
+	sections := in.Sections
+	if len(sections) == 0 {
+		sections = DefaultSections
+	}
+	budget := PromptBudget(in.MaxTokens)
+	build := func(used []beat.Beat, lines []string) (string, error) {
+		return render(tmpl, Data{
+			Topic:            in.Topic,
+			Audience:         audience,
+			AudienceGuidance: audienceGuidance,
+			Found:            len(beatsData),
+			Beats:            used,
+			Lines:            lines,
+			Summaries:        strings.Join(lines, "\n"),
+			Sections:         sections,
+		})
+	}
+	linesBudget := 0
+	if budget > 0 {
+		frame, err := build(nil, nil)
+		if err != nil {
+			return nil, &Error{Msg: "failed to render brief", Err: err}
+		}
+		linesBudget = max(budget-tokens.Estimate(frame), 1)
+	}
+	sel := tokens.Fit(beatSummaries, priority, linesBudget)
+	used := make([]beat.Beat, 0, len(sel.Kept))
+	for _, i := range sel.Kept {
+		used = append(used, beatsData[i])
+	}
+	kept := make(map[int]bool, len(sel.Kept))
+	for _, i := range sel.Kept {
+		kept[i] = true
+	}
+	usedIDs := make([]string, 0, len(sel.Kept))
+	var omitted []string
+	for _, i := range priority {
+		if kept[i] {
+			usedIDs = append(usedIDs, beatsData[i].ID)
+		} else {
+			omitted = append(omitted, beatsData[i].ID)
+		}
+	}
+	prompt, err := build(used, sel.Lines)
+	if err != nil {
+		return nil, &Error{Msg: "failed to render brief", Err: err}
+	}
+	r, redaction, err := redact.ForOutput(in.Provider, beatsData)
+	if err != nil {
+		return nil, &Error{Msg: "failed to load redaction profile", Err: err}
+	}
+	if r != nil {
+		prompt = r.Text(prompt)
+		for i := range used {
+			used[i] = r.Beat(used[i])
+		}
+		redaction.Replaced = r.Replaced
+	}
+
+	return &Output{
+		Topic:           in.Topic,
+		Audience:        audience,
+		Template:        in.Template,
+		BeatsUsed:       usedIDs,
+		BeatsData:       used,
+		BriefPrompt:     prompt,
+		EstimatedTokens: tokens.Estimate(prompt),
+		TokenBudget:     budget,
+		BeatsOmitted:    omitted,
+		Redaction:       redaction,
+	}, nil
+}
+
+// inWALD keeps the first max results filed under a WALD directory.
+func inWALD(s *store.JSONLStore, results []beat.SearchResult, dir string, max int) ([]beat.SearchResult, error) {
+	all, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	dir = wald.ResolveDir(dir)
+	in := make(map[string]bool)
+	for _, b := range all {
+		if b.InWALD(dir) {
+			in[b.ID] = true
+		}
+	}
+	kept := []beat.SearchResult{}
+	for _, r := range results {
+		if in[r.ID] && len(kept) < max {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
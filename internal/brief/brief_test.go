@@ -0,0 +1,67 @@
+package brief
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestBriefTemplates(t *testing.T) {
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if err := s.AppendBulk([]*beat.Beat{
+		{ID: "beat-20260302-001", CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Call"}, Content: "Acme wants the pricing proposal by Friday"},
+		{ID: "beat-20260302-002", CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Note"}, Content: "Unrelated note about gardening"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(s.Dir(), TemplatesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "Client update on {{.Topic}} ({{.Found}} beats)\n{{range .Beats}}* {{date .CreatedAt}} {{.Content}} [{{.ID}}]\n{{end}}{{range .Sections}}## {{.}}\n{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "client-update.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	brief := func(input string) (*Output, error) {
+		t.Helper()
+		in := Input{Location: time.UTC}
+		if err := json.Unmarshal([]byte(input), &in); err != nil {
+			t.Fatal(err)
+		}
+		return Build(s, in)
+	}
+
+	got, err := brief(`{"topic":"pricing","template":"client-update","sections":["Wins","Risks"]}`)
+	want := "Client update on pricing (1 beats)\n* 2026-03-02 Acme wants the pricing proposal by Friday [beat-20260302-001]\n## Wins\n## Risks\n"
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BriefPrompt != want || got.Template != "client-update" {
+		t.Errorf("brief_prompt = %q; want %q", got.BriefPrompt, want)
+	}
+
+	if got, err = brief(`{"topic":"pricing","sections":["DECISIONS: What was decided"]}`); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got.BriefPrompt, "BRIEF STRUCTURE:\n1. DECISIONS: What was decided\n\nKeep") {
+		t.Errorf("built-in brief with sections:\n%s", got.BriefPrompt)
+	}
+
+	if _, err := brief(`{"topic":"pricing","template":"decision-log"}`); err == nil || !strings.Contains(err.Error(), "available: client-update") {
+		t.Errorf("missing template: %v", err)
+	}
+	if _, err := brief(`{"topic":"pricing","template":"../secrets"}`); err == nil || !strings.Contains(err.Error(), "invalid template name") {
+		t.Errorf("path in template name: %v", err)
+	}
+}
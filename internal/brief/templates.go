@@ -0,0 +1,122 @@
+package brief
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// TemplatesDir holds named brief templates, <name>.tmpl, under the
+// beats directory.
+var TemplatesDir = filepath.Join("templates", "briefs")
+
+// DefaultSections is the structure the built-in brief asks for.
+var DefaultSections = []string{
+	"EXECUTIVE SUMMARY: 2-3 sentences capturing the core insight",
+	"KEY THEMES: Major patterns or clusters in this material",
+	"TIMELINE: How thinking evolved (if applicable)",
+	"OPEN QUESTIONS: Unresolved items or areas needing exploration",
+	"ACTION ITEMS: Concrete next steps that emerge from this material",
+	"CONNECTIONS: Links to other topics, beads, or external resources",
+}
+
+// builtin is the built-in --robot-brief prompt.
+const builtin = `Generate a thematic brief on: {{.Topic}}
+
+RELEVANT BEATS ({{.Found}} found):
+{{.Summaries}}
+
+AUDIENCE: {{.Audience}}
+{{.AudienceGuidance}}
+
+BRIEF STRUCTURE:
+{{range $i, $s := .Sections}}{{add1 $i}}. {{$s}}
+{{end}}
+Keep the brief focused and actionable. Cite beat IDs when referencing specific insights.`
+
+// Data is what a brief template is executed with.
+type Data struct {
+	Topic            string
+	Audience         string
+	AudienceGuidance string
+	Found            int         // Beats matching the topic
+	Beats            []beat.Beat // Beats that fit the token budget
+	Lines            []string    // One summary line per beat in Beats
+	Summaries        string      // Lines joined by newlines
+	Sections         []string    // The brief's sections, from the input or the default
+}
+
+// funcs are what templates can call; dates are shown in loc.
+func funcs(loc *time.Location) template.FuncMap {
+	return template.FuncMap{
+		"add1":     func(i int) int { return i + 1 },
+		"truncate": truncate,
+		"date":     func(t time.Time) string { return t.In(loc).Format("2006-01-02") },
+		"join":     strings.Join,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"trim":     strings.TrimSpace,
+	}
+}
+
+// loadTemplate parses the named template from the beats directory, or
+// the built-in one when name is empty.
+func loadTemplate(beatsDir, name string, loc *time.Location) (*template.Template, error) {
+	if name == "" {
+		return template.New("brief").Funcs(funcs(loc)).Parse(builtin)
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(beatsDir, TemplatesDir, name+".tmpl"))
+	if os.IsNotExist(err) {
+		available := templateNames(beatsDir)
+		if len(available) == 0 {
+			return nil, fmt.Errorf("no brief template %q: add %s.tmpl to %s", name, name, filepath.Join(beatsDir, TemplatesDir))
+		}
+		return nil, fmt.Errorf("no brief template %q (available: %s)", name, strings.Join(available, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(name).Funcs(funcs(loc)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid brief template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// templateNames lists the templates in the beats directory.
+func templateNames(beatsDir string) []string {
+	matches, _ := filepath.Glob(filepath.Join(beatsDir, TemplatesDir, "*.tmpl"))
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = strings.TrimSuffix(filepath.Base(m), ".tmpl")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// render executes a brief template.
+func render(t *template.Template, data Data) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("brief template %s: %w", t.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// truncate collapses whitespace and cuts s to maxLen.
+func truncate(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
@@ -0,0 +1,131 @@
+package capture
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Matches new-style (2301.01234v2) and old-style (hep-th/9901001) identifiers,
+// bare or inside an abs/pdf URL.
+var arxivIDRegex = regexp.MustCompile(`^(?:arxiv:|https?://(?:www\.|export\.)?arxiv\.org/(?:abs|pdf)/)?(\d{4}\.\d{4,5}(?:v\d+)?|[a-z\-]+(?:\.[A-Z]{2})?/\d{7}(?:v\d+)?)(?:\.pdf)?/?$`)
+
+// ArxivPaper is an arXiv paper's metadata plus the text of its PDF.
+type ArxivPaper struct {
+	ID        string
+	Title     string
+	Authors   []string
+	Abstract  string
+	Published time.Time
+	AbsURL    string
+	PDFURL    string
+	PDF       []byte       // Raw document, empty if the download failed
+	Document  *PDFDocument // Extracted text, nil if the download or parse failed
+}
+
+// Content renders the paper as beat content.
+func (p *ArxivPaper) Content() string {
+	var b strings.Builder
+	b.WriteString(p.Title)
+	if len(p.Authors) > 0 {
+		b.WriteString("\n" + strings.Join(p.Authors, ", "))
+	}
+	if p.Abstract != "" {
+		b.WriteString("\n\n" + p.Abstract)
+	}
+	b.WriteString("\n\n" + p.AbsURL)
+	return b.String()
+}
+
+// ParseArxivID accepts an arXiv identifier or an abs/pdf URL.
+func ParseArxivID(ref string) (string, error) {
+	m := arxivIDRegex.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return "", fmt.Errorf("not an arXiv identifier: %s", ref)
+	}
+	return m[1], nil
+}
+
+type arxivFeed struct {
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Summary   string `xml:"summary"`
+		Published string `xml:"published"`
+		Authors   []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Links []struct {
+			Href  string `xml:"href,attr"`
+			Type  string `xml:"type,attr"`
+			Title string `xml:"title,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// CaptureArxivPaper fetches a paper's metadata from the arXiv API and
+// downloads and extracts its PDF. A failed download still returns the metadata.
+func CaptureArxivPaper(ref string, cfg ArxivConfig) (*ArxivPaper, error) {
+	id, err := ParseArxivID(ref)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultConfig().Arxiv.APIURL
+	}
+
+	resp, err := httpClient.Get(cfg.APIURL + "?id_list=" + url.QueryEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("fetching arXiv %s: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching arXiv %s: status %d", id, resp.StatusCode)
+	}
+
+	var feed arxivFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decoding arXiv %s: %w", id, err)
+	}
+	// Unknown IDs come back as an entry without a title
+	if len(feed.Entries) == 0 || strings.TrimSpace(feed.Entries[0].Title) == "" {
+		return nil, fmt.Errorf("arXiv paper %s not found", id)
+	}
+	entry := feed.Entries[0]
+
+	paper := &ArxivPaper{
+		ID:       id,
+		Title:    strings.Join(strings.Fields(entry.Title), " "),
+		Abstract: strings.Join(strings.Fields(entry.Summary), " "),
+		AbsURL:   strings.TrimSpace(entry.ID),
+	}
+	if paper.AbsURL == "" {
+		paper.AbsURL = "https://arxiv.org/abs/" + id
+	}
+	for _, a := range entry.Authors {
+		paper.Authors = append(paper.Authors, strings.TrimSpace(a.Name))
+	}
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(entry.Published)); err == nil {
+		paper.Published = t.UTC()
+	}
+	for _, l := range entry.Links {
+		if l.Title == "pdf" || l.Type == "application/pdf" {
+			paper.PDFURL = l.Href
+		}
+	}
+	if paper.PDFURL == "" {
+		paper.PDFURL = strings.Replace(paper.AbsURL, "/abs/", "/pdf/", 1)
+	}
+
+	if data, err := ReadPDF(paper.PDFURL); err == nil {
+		paper.PDF = data
+		if doc, err := ExtractPDF(data); err == nil {
+			paper.Document = doc
+		}
+	}
+	return paper, nil
+}
@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFile holds capture source settings, relative to the beats directory.
+const ConfigFile = "capture.json"
+
+// Config configures the `bt capture` sources.
+type Config struct {
+	X       XConfig       `json:"x"`
+	HN      HNConfig      `json:"hn"`
+	Arxiv   ArxivConfig   `json:"arxiv"`
+	GitHub  GitHubConfig  `json:"github"`
+	Podcast PodcastConfig `json:"podcast"`
+	// Snapshots saves every captured page as HTML and markdown attachments
+	// (per capture with --snapshot otherwise).
+	Snapshots SnapshotConfig `json:"snapshots"`
+	// Pipeline turns off enrichment stages for every capture into the store.
+	Pipeline PipelineConfig `json:"pipeline"`
+}
+
+// PipelineConfig configures the stages a proposed beat passes through on
+// its way into the store, whichever way it was captured.
+type PipelineConfig struct {
+	Disabled []string `json:"disabled,omitempty"` // Stage names, e.g. urls or context
+}
+
+// SnapshotConfig configures archival snapshots of captured URLs.
+type SnapshotConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// XConfig configures X/Twitter thread capture.
+type XConfig struct {
+	// Fetcher is a command that receives the post URL as its last argument
+	// and prints an XThread as JSON; it replaces the syndication endpoint.
+	Fetcher        string `json:"fetcher,omitempty"`
+	SyndicationURL string `json:"syndication_url,omitempty"`
+	MaxPosts       int    `json:"max_posts,omitempty"`
+}
+
+// HNConfig configures Hacker News capture.
+type HNConfig struct {
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// ArxivConfig configures arXiv paper capture.
+type ArxivConfig struct {
+	APIURL string `json:"api_url,omitempty"`
+}
+
+// GitHubConfig configures GitHub stars and issue capture. Token falls back
+// to the GITHUB_TOKEN environment variable.
+type GitHubConfig struct {
+	APIURL string `json:"api_url,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// PodcastConfig configures podcast episode capture. Whisper is a command
+// that receives the audio file as its last argument and prints the
+// transcript; without it the openai-whisper CLI is used when installed.
+type PodcastConfig struct {
+	Whisper      string `json:"whisper,omitempty"`
+	WhisperModel string `json:"whisper_model,omitempty"`
+}
+
+// DefaultConfig returns the built-in capture settings.
+func DefaultConfig() Config {
+	return Config{
+		X: XConfig{
+			SyndicationURL: "https://cdn.syndication.twimg.com/tweet-result",
+			MaxPosts:       25,
+		},
+		HN: HNConfig{
+			APIURL: "https://hacker-news.firebaseio.com/v0",
+		},
+		Arxiv: ArxivConfig{
+			APIURL: "https://export.arxiv.org/api/query",
+		},
+		GitHub: GitHubConfig{
+			APIURL: "https://api.github.com",
+		},
+		Podcast: PodcastConfig{
+			WhisperModel: "base",
+		},
+	}
+}
+
+// LoadConfig reads capture.json, using defaults for missing fields.
+func LoadConfig(beatsDir string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(filepath.Join(beatsDir, ConfigFile))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DefaultConfig(), fmt.Errorf("invalid %s: %w", ConfigFile, err)
+	}
+	return cfg, nil
+}
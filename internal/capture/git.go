@@ -0,0 +1,203 @@
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// GitCommit represents a commit captured from a local git repository.
+type GitCommit struct {
+	Repo     string // Repository name (base name of the top-level directory)
+	RepoPath string // Absolute path of the repository top-level directory
+	Remote   string // origin URL, if configured
+	Branch   string
+	Hash     string
+	Author   string
+	Date     time.Time
+	Subject  string
+	Body     string
+	Files    []string
+}
+
+// maxCommitFiles caps how many changed files are listed in a commit beat.
+const maxCommitFiles = 20
+
+// CaptureGitCommit reads a single commit (default HEAD) from the repository at repoDir.
+func CaptureGitCommit(repoDir, rev string) (*GitCommit, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	top, err := runGit(repoDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	// Fields are separated by NUL so multi-line bodies survive intact
+	out, err := runGit(repoDir, "show", "-s", "--format=%H%x00%an%x00%aI%x00%s%x00%b", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+	parts := strings.SplitN(out, "\x00", 5)
+	if len(parts) < 5 {
+		return nil, fmt.Errorf("unexpected git output for %s", rev)
+	}
+
+	date, err := time.Parse(time.RFC3339, parts[2])
+	if err != nil {
+		date = clock.Now()
+	}
+
+	commit := &GitCommit{
+		Repo:     filepath.Base(top),
+		RepoPath: top,
+		Hash:     parts[0],
+		Author:   parts[1],
+		Date:     date.UTC(),
+		Subject:  parts[3],
+		Body:     strings.TrimSpace(parts[4]),
+	}
+
+	if files, err := runGit(repoDir, "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commit.Hash); err == nil && files != "" {
+		commit.Files = strings.Split(files, "\n")
+	}
+	if branch, err := runGit(repoDir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		commit.Branch = branch
+	}
+	if remote, err := runGit(repoDir, "config", "--get", "remote.origin.url"); err == nil {
+		commit.Remote = remote
+	}
+
+	return commit, nil
+}
+
+// GitLogOptions filters the commits read by GitLog.
+type GitLogOptions struct {
+	Since  string // Any date git understands ("2024-01-01", "3 months ago")
+	Until  string
+	Author string
+	Rev    string // Revision range (default HEAD)
+	Max    int    // Most recent N commits (0 for all)
+}
+
+// GitLog reads non-merge commits, oldest first, with their changed files.
+func GitLog(repoDir string, opts GitLogOptions) ([]*GitCommit, error) {
+	top, err := runGit(repoDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	// Each record starts with RS; fields are NUL separated, then the file list
+	args := []string{"log", "--no-merges", "--reverse", "--name-only", "--format=%x1e%H%x00%an%x00%aI%x00%s%x00%b%x00"}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Max > 0 {
+		// --reverse applies after limiting, so this keeps the newest N
+		args = append(args, fmt.Sprintf("--max-count=%d", opts.Max))
+	}
+	rev := opts.Rev
+	if rev == "" {
+		rev = "HEAD"
+	}
+	args = append(args, rev, "--")
+
+	out, err := runGit(repoDir, args...)
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	branch, _ := runGit(repoDir, "rev-parse", "--abbrev-ref", "HEAD")
+	remote, _ := runGit(repoDir, "config", "--get", "remote.origin.url")
+
+	var commits []*GitCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		parts := strings.SplitN(record, "\x00", 6)
+		if len(parts) < 6 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil {
+			continue
+		}
+		commit := &GitCommit{
+			Repo:     filepath.Base(top),
+			RepoPath: top,
+			Remote:   remote,
+			Branch:   branch,
+			Hash:     parts[0],
+			Author:   parts[1],
+			Date:     date.UTC(),
+			Subject:  parts[3],
+			Body:     strings.TrimSpace(parts[4]),
+		}
+		for _, f := range strings.Split(strings.TrimSpace(parts[5]), "\n") {
+			if f != "" {
+				commit.Files = append(commit.Files, f)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (g *GitCommit) ShortHash() string {
+	if len(g.Hash) > 7 {
+		return g.Hash[:7]
+	}
+	return g.Hash
+}
+
+// Content renders the commit as beat content: message, repo and changed files.
+func (g *GitCommit) Content() string {
+	var sb strings.Builder
+	sb.WriteString(g.Subject)
+	if g.Body != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(g.Body)
+	}
+	fmt.Fprintf(&sb, "\n\n%s@%s", g.Repo, g.ShortHash())
+	if g.Branch != "" && g.Branch != "HEAD" {
+		fmt.Fprintf(&sb, " (%s)", g.Branch)
+	}
+
+	if len(g.Files) > 0 {
+		sb.WriteString("\n\nFiles changed:")
+		for i, f := range g.Files {
+			if i == maxCommitFiles {
+				fmt.Fprintf(&sb, "\n- ... and %d more", len(g.Files)-maxCommitFiles)
+				break
+			}
+			sb.WriteString("\n- " + f)
+		}
+	}
+	return sb.String()
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func gitCommitAt(t *testing.T, dir, date, file, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(msg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", file}, {"commit", "-q", "-m", msg}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=Ada", "GIT_COMMITTER_EMAIL=ada@example.com", "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestGitLog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	gitCommitAt(t, dir, "2024-01-01T10:00:00Z", "a.txt", "First commit\n\nWith a body")
+	gitCommitAt(t, dir, "2024-02-01T10:00:00Z", "b.txt", "Second commit")
+	gitCommitAt(t, dir, "2024-03-01T10:00:00Z", "c.txt", "Third commit")
+
+	commits, err := GitLog(dir, GitLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("got %d commits, want 3", len(commits))
+	}
+	first := commits[0]
+	if first.Subject != "First commit" || first.Body != "With a body" || first.Author != "Ada" {
+		t.Errorf("first = %+v", first)
+	}
+	if !reflect.DeepEqual(first.Files, []string{"a.txt"}) || first.Date.Format("2006-01-02") != "2024-01-01" {
+		t.Errorf("files = %v, date = %v", first.Files, first.Date)
+	}
+
+	since, _ := GitLog(dir, GitLogOptions{Since: "2024-01-15"})
+	if len(since) != 2 || since[0].Subject != "Second commit" {
+		t.Errorf("--since returned %d commits", len(since))
+	}
+	newest, _ := GitLog(dir, GitLogOptions{Max: 1})
+	if len(newest) != 1 || newest[0].Subject != "Third commit" {
+		t.Errorf("--max 1 = %+v, want the newest commit", newest)
+	}
+}
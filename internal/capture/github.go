@@ -4,6 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -77,3 +82,264 @@ func CaptureFromGitHub(ref string, additionalContent string) (*GitHubCapture, er
 
 	return capture, nil
 }
+
+var githubIssueRegex = regexp.MustCompile(`^(?:https?://github\.com/)?([\w.-]+)/([\w.-]+)(?:#|/issues/|/pull/)(\d+)/?$`)
+
+// GitHubStar is a repository starred by a user.
+type GitHubStar struct {
+	FullName    string
+	Description string
+	URL         string
+	Language    string
+	Topics      []string
+	Stars       int
+	StarredAt   time.Time
+}
+
+// Content renders the starred repository as beat content.
+func (s *GitHubStar) Content() string {
+	var b strings.Builder
+	b.WriteString(s.FullName)
+	if s.Description != "" {
+		b.WriteString(" - " + s.Description)
+	}
+	b.WriteString("\n\n")
+	if s.Language != "" {
+		b.WriteString(s.Language + ", ")
+	}
+	fmt.Fprintf(&b, "⭐ %d", s.Stars)
+	if len(s.Topics) > 0 {
+		b.WriteString("\nTopics: " + strings.Join(s.Topics, ", "))
+	}
+	b.WriteString("\n" + s.URL)
+	return b.String()
+}
+
+// GitHubIssue is an issue or pull request.
+type GitHubIssue struct {
+	Owner       string
+	Repo        string
+	Number      int
+	Title       string
+	Body        string
+	State       string // open or closed
+	Labels      []string
+	Author      string
+	Assignees   []string
+	URL         string
+	PullRequest bool
+	Comments    int
+	CreatedAt   time.Time
+	ClosedAt    *time.Time
+}
+
+// FullName is owner/repo.
+func (i *GitHubIssue) FullName() string {
+	return i.Owner + "/" + i.Repo
+}
+
+// Kind is "issue" or "pull request".
+func (i *GitHubIssue) Kind() string {
+	if i.PullRequest {
+		return "pull request"
+	}
+	return "issue"
+}
+
+// githubBodyLimit bounds the issue body copied into beat content.
+const githubBodyLimit = 4000
+
+// Content renders the issue as beat content.
+func (i *GitHubIssue) Content() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s#%d: %s", i.FullName(), i.Number, i.Title)
+	fmt.Fprintf(&b, "\n\n%s %s by @%s, %d comments", strings.ToUpper(i.State[:1])+i.State[1:], i.Kind(), i.Author, i.Comments)
+	if len(i.Labels) > 0 {
+		b.WriteString("\nLabels: " + strings.Join(i.Labels, ", "))
+	}
+	if len(i.Assignees) > 0 {
+		b.WriteString("\nAssignees: @" + strings.Join(i.Assignees, ", @"))
+	}
+	if body := strings.TrimSpace(i.Body); body != "" {
+		// Keep the markdown line structure; only cut very long bodies
+		if len(body) > githubBodyLimit {
+			cut := strings.LastIndex(body[:githubBodyLimit], "\n")
+			if cut < githubBodyLimit/2 {
+				cut = githubBodyLimit
+			}
+			body = strings.TrimSpace(body[:cut]) + "\n..."
+		}
+		b.WriteString("\n\n" + body)
+	}
+	b.WriteString("\n\n" + i.URL)
+	return b.String()
+}
+
+// ParseGitHubIssueRef accepts owner/repo#N or an issue or pull request URL.
+func ParseGitHubIssueRef(ref string) (owner, repo string, number int, err error) {
+	m := githubIssueRegex.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return "", "", 0, fmt.Errorf("not a GitHub issue reference (use owner/repo#N): %s", ref)
+	}
+	number, _ = strconv.Atoi(m[3])
+	return m[1], m[2], number, nil
+}
+
+// githubGet fetches an API path, authenticating with the configured token or
+// GITHUB_TOKEN when set (unauthenticated requests are rate limited).
+func githubGet(cfg GitHubConfig, path, accept string, v interface{}) error {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = DefaultConfig().GitHub.APIURL
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(apiURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	if accept == "" {
+		accept = "application/vnd.github+json"
+	}
+	req.Header.Set("Accept", accept)
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("fetching %s: not found", path)
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		return fmt.Errorf("fetching %s: status %d (rate limited? set GITHUB_TOKEN)", path, resp.StatusCode)
+	default:
+		return fmt.Errorf("fetching %s: status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// githubStarPages bounds how many pages of 100 stars one sync reads.
+const githubStarPages = 50
+
+// FetchGitHubStars returns repositories a user starred after since (zero for
+// all), oldest first. The API lists newest stars first, so paging stops at
+// the first star that is not newer than since.
+func FetchGitHubStars(user string, since time.Time, cfg GitHubConfig) ([]GitHubStar, error) {
+	if user == "" || strings.Contains(user, "/") {
+		return nil, fmt.Errorf("invalid GitHub user: %q", user)
+	}
+
+	var stars []GitHubStar
+	for page := 1; page <= githubStarPages; page++ {
+		var items []struct {
+			StarredAt time.Time `json:"starred_at"`
+			Repo      struct {
+				FullName    string   `json:"full_name"`
+				Description string   `json:"description"`
+				HTMLURL     string   `json:"html_url"`
+				Language    string   `json:"language"`
+				Topics      []string `json:"topics"`
+				Stars       int      `json:"stargazers_count"`
+			} `json:"repo"`
+		}
+		path := fmt.Sprintf("/users/%s/starred?per_page=100&sort=created&direction=desc&page=%d", url.PathEscape(user), page)
+		// The star+json media type adds starred_at and nests the repository
+		if err := githubGet(cfg, path, "application/vnd.github.star+json", &items); err != nil {
+			return nil, err
+		}
+
+		done := len(items) < 100
+		for _, item := range items {
+			if !since.IsZero() && !item.StarredAt.After(since) {
+				done = true
+				break
+			}
+			stars = append(stars, GitHubStar{
+				FullName:    item.Repo.FullName,
+				Description: item.Repo.Description,
+				URL:         item.Repo.HTMLURL,
+				Language:    item.Repo.Language,
+				Topics:      item.Repo.Topics,
+				Stars:       item.Repo.Stars,
+				StarredAt:   item.StarredAt.UTC(),
+			})
+		}
+		if done {
+			break
+		}
+	}
+
+	sort.SliceStable(stars, func(i, j int) bool { return stars[i].StarredAt.Before(stars[j].StarredAt) })
+	return stars, nil
+}
+
+// CaptureGitHubIssue fetches an issue or pull request by owner/repo#N or URL.
+func CaptureGitHubIssue(ref string, cfg GitHubConfig) (*GitHubIssue, error) {
+	owner, repo, number, err := ParseGitHubIssueRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Number      int        `json:"number"`
+		Title       string     `json:"title"`
+		Body        string     `json:"body"`
+		State       string     `json:"state"`
+		HTMLURL     string     `json:"html_url"`
+		Comments    int        `json:"comments"`
+		CreatedAt   time.Time  `json:"created_at"`
+		ClosedAt    *time.Time `json:"closed_at"`
+		PullRequest *struct{}  `json:"pull_request"`
+		User        struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Assignees []struct {
+			Login string `json:"login"`
+		} `json:"assignees"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d", url.PathEscape(owner), url.PathEscape(repo), number)
+	if err := githubGet(cfg, path, "", &data); err != nil {
+		return nil, err
+	}
+
+	issue := &GitHubIssue{
+		Owner:       owner,
+		Repo:        repo,
+		Number:      data.Number,
+		Title:       data.Title,
+		Body:        strings.ReplaceAll(data.Body, "\r\n", "\n"),
+		State:       data.State,
+		Author:      data.User.Login,
+		URL:         data.HTMLURL,
+		PullRequest: data.PullRequest != nil,
+		Comments:    data.Comments,
+		CreatedAt:   data.CreatedAt.UTC(),
+		ClosedAt:    data.ClosedAt,
+	}
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	if issue.URL == "" {
+		issue.URL = fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number)
+	}
+	for _, l := range data.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	for _, a := range data.Assignees {
+		issue.Assignees = append(issue.Assignees, a.Login)
+	}
+	return issue, nil
+}
@@ -0,0 +1,87 @@
+package capture
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGitHubIssueRef(t *testing.T) {
+	for _, ref := range []string{"golang/go#123", "https://github.com/golang/go/issues/123", "https://github.com/golang/go/pull/123"} {
+		owner, repo, n, err := ParseGitHubIssueRef(ref)
+		if err != nil || owner != "golang" || repo != "go" || n != 123 {
+			t.Errorf("ParseGitHubIssueRef(%q) = %q, %q, %d, %v", ref, owner, repo, n, err)
+		}
+	}
+	if _, _, _, err := ParseGitHubIssueRef("golang/go"); err == nil {
+		t.Error("ParseGitHubIssueRef(without number) succeeded, want error")
+	}
+}
+
+func TestFetchGitHubStars(t *testing.T) {
+	var accept, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/ada/starred" {
+			http.NotFound(w, r)
+			return
+		}
+		accept, auth = r.Header.Get("Accept"), r.Header.Get("Authorization")
+		// Newest first, as the API returns them
+		fmt.Fprint(w, `[
+  {"starred_at":"2024-03-02T10:00:00Z","repo":{"full_name":"a/new","description":"New","html_url":"https://github.com/a/new","language":"Go","topics":["cli"],"stargazers_count":5}},
+  {"starred_at":"2024-03-01T10:00:00Z","repo":{"full_name":"a/mid","html_url":"https://github.com/a/mid"}},
+  {"starred_at":"2024-02-01T10:00:00Z","repo":{"full_name":"a/old","html_url":"https://github.com/a/old"}}
+]`)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GITHUB_TOKEN", "tok")
+	since := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	stars, err := FetchGitHubStars("ada", since, GitHubConfig{APIURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "application/vnd.github.star+json" || auth != "Bearer tok" {
+		t.Errorf("headers: accept %q, auth %q", accept, auth)
+	}
+	if len(stars) != 2 || stars[0].FullName != "a/mid" || stars[1].FullName != "a/new" {
+		t.Fatalf("stars = %+v, want a/mid then a/new", stars)
+	}
+	if content := stars[1].Content(); !strings.Contains(content, "a/new - New") || !strings.Contains(content, "Topics: cli") {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestCaptureGitHubIssue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/golang/go/issues/7" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"number":7,"title":"Add generics","body":"We should.\r\nSoon.","state":"closed",
+ "html_url":"https://github.com/golang/go/issues/7","comments":3,"created_at":"2020-01-01T00:00:00Z",
+ "closed_at":"2022-03-15T00:00:00Z","user":{"login":"rsc"},"labels":[{"name":"Proposal"},{"name":"generics"}]}`)
+	}))
+	defer srv.Close()
+
+	issue, err := CaptureGitHubIssue("golang/go#7", GitHubConfig{APIURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Title != "Add generics" || issue.State != "closed" || issue.PullRequest || issue.ClosedAt == nil {
+		t.Errorf("issue = %+v", issue)
+	}
+	content := issue.Content()
+	for _, want := range []string{"golang/go#7: Add generics", "Closed issue by @rsc, 3 comments", "Labels: Proposal, generics", "We should.\nSoon."} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q:\n%s", want, content)
+		}
+	}
+
+	if _, err := CaptureGitHubIssue("golang/go#8", GitHubConfig{APIURL: srv.URL}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing issue error = %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var hnItemRegex = regexp.MustCompile(`^(?:https?://news\.ycombinator\.com/item\?id=)?(\d+)$`)
+
+// HNComment is a top-level comment on a story.
+type HNComment struct {
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// HNStory is a Hacker News story with its linked article, if any.
+type HNStory struct {
+	ID        int
+	Title     string
+	URL       string // Linked article; empty for Ask/Show HN text posts
+	Text      string // Body of text posts
+	Author    string
+	Score     int
+	Comments  int // Total comment count
+	CreatedAt time.Time
+	Top       []HNComment
+	Article   *WebCapture // Linked article, captured via the web path
+}
+
+// DiscussionURL is the story's page on news.ycombinator.com.
+func (s *HNStory) DiscussionURL() string {
+	return fmt.Sprintf("https://news.ycombinator.com/item?id=%d", s.ID)
+}
+
+// Content renders the story as beat content.
+func (s *HNStory) Content() string {
+	var b strings.Builder
+	b.WriteString(s.Title)
+	fmt.Fprintf(&b, "\n\n%d points by %s, %d comments", s.Score, s.Author, s.Comments)
+	if s.Article != nil && s.Article.Excerpt != "" {
+		b.WriteString("\n\n" + s.Article.Excerpt)
+	}
+	if s.Text != "" {
+		b.WriteString("\n\n" + s.Text)
+	}
+	for _, c := range s.Top {
+		fmt.Fprintf(&b, "\n\n> %s: %s", c.Author, clip(c.Text, 300))
+	}
+	if s.URL != "" {
+		b.WriteString("\n\n" + s.URL)
+	}
+	b.WriteString("\n\n" + s.DiscussionURL())
+	return b.String()
+}
+
+// ParseHNItemID accepts an item ID or a news.ycombinator.com item URL.
+func ParseHNItemID(ref string) (string, error) {
+	m := hnItemRegex.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return "", fmt.Errorf("not a Hacker News item: %s", ref)
+	}
+	return m[1], nil
+}
+
+type hnItem struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Text        string `json:"text"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Kids        []int  `json:"kids"`
+	Deleted     bool   `json:"deleted"`
+	Dead        bool   `json:"dead"`
+}
+
+func fetchHNItem(apiURL string, id string) (*hnItem, error) {
+	resp, err := httpClient.Get(fmt.Sprintf("%s/item/%s.json", strings.TrimSuffix(apiURL, "/"), id))
+	if err != nil {
+		return nil, fmt.Errorf("fetching HN item %s: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching HN item %s: status %d", id, resp.StatusCode)
+	}
+	var item hnItem
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		return nil, fmt.Errorf("decoding HN item %s: %w", id, err)
+	}
+	if item.ID == 0 {
+		return nil, fmt.Errorf("HN item %s not found", id)
+	}
+	return &item, nil
+}
+
+// CaptureHNStory fetches a story, up to maxComments top-level comments, and
+// the linked article through CaptureFromURL.
+func CaptureHNStory(ref string, maxComments int, cfg HNConfig) (*HNStory, error) {
+	id, err := ParseHNItemID(ref)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultConfig().HN.APIURL
+	}
+
+	item, err := fetchHNItem(cfg.APIURL, id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Type != "story" && item.Type != "job" && item.Type != "poll" {
+		return nil, fmt.Errorf("HN item %s is a %s, not a story", id, item.Type)
+	}
+
+	story := &HNStory{
+		ID:        item.ID,
+		Title:     item.Title,
+		URL:       item.URL,
+		Text:      htmlToText(item.Text),
+		Author:    item.By,
+		Score:     item.Score,
+		Comments:  item.Descendants,
+		CreatedAt: time.Unix(item.Time, 0).UTC(),
+	}
+
+	for _, kid := range item.Kids {
+		if len(story.Top) >= maxComments {
+			break
+		}
+		c, err := fetchHNItem(cfg.APIURL, fmt.Sprint(kid))
+		if err != nil || c.Deleted || c.Dead || c.Text == "" {
+			continue
+		}
+		story.Top = append(story.Top, HNComment{Author: c.By, Text: htmlToText(c.Text)})
+	}
+
+	if story.URL != "" {
+		if article, err := CaptureFromURL(story.URL, ""); err == nil {
+			story.Article = article
+		}
+	}
+	return story, nil
+}
+
+// htmlToText converts an HTML fragment (HN item text) to plain paragraphs.
+func htmlToText(s string) string {
+	if s == "" {
+		return ""
+	}
+	return paragraphs(parseHTML(s))
+}
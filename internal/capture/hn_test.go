@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseHNItemID(t *testing.T) {
+	for _, ref := range []string{"8863", "https://news.ycombinator.com/item?id=8863"} {
+		if id, err := ParseHNItemID(ref); err != nil || id != "8863" {
+			t.Errorf("ParseHNItemID(%q) = %q, %v", ref, id, err)
+		}
+	}
+	if _, err := ParseHNItemID("https://example.com/item?id=1"); err == nil {
+		t.Error("ParseHNItemID(other site) succeeded, want error")
+	}
+}
+
+func TestCaptureHNStory(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/item/1.json":
+			fmt.Fprintf(w, `{"id":1,"type":"story","by":"pg","time":1700000000,"title":"My YC app","url":"%s/article","score":42,"descendants":3,"kids":[2,3,4]}`, srv.URL)
+		case "/item/2.json":
+			fmt.Fprint(w, `{"id":2,"type":"comment","by":"dang","text":"First<p>Second &amp; more"}`)
+		case "/item/3.json":
+			fmt.Fprint(w, `{"id":3,"type":"comment","deleted":true}`)
+		case "/item/4.json":
+			fmt.Fprint(w, `{"id":4,"type":"comment","by":"tptacek","text":"Another"}`)
+		case "/article":
+			fmt.Fprint(w, `<html><head><title>Article</title><meta name="description" content="An article about apps."></head><body><p>Body text of the article that is long enough to count.</p></body></html>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	story, err := CaptureHNStory("1", 2, HNConfig{APIURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if story.Title != "My YC app" || story.Score != 42 || story.Author != "pg" {
+		t.Errorf("story = %+v", story)
+	}
+	// The deleted comment is skipped, not counted
+	if len(story.Top) != 2 || story.Top[0].Text != "First\n\nSecond & more" || story.Top[1].Author != "tptacek" {
+		t.Errorf("comments = %+v", story.Top)
+	}
+	if story.Article == nil || story.Article.Excerpt != "An article about apps." {
+		t.Fatalf("article = %+v", story.Article)
+	}
+
+	content := story.Content()
+	for _, want := range []string{"My YC app", "42 points by pg, 3 comments", "An article about apps.", "> dang: First", "item?id=1"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q:\n%s", want, content)
+		}
+	}
+}
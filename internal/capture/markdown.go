@@ -0,0 +1,183 @@
+package capture
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var blankLinesRe = regexp.MustCompile(`\n{3,}`)
+
+// Markdown renders the article's main content as markdown, resolving
+// relative links and images against base. It is empty when no main content
+// was found.
+func (a *Article) Markdown(base string) string {
+	if a.main == nil {
+		return ""
+	}
+	baseURL, _ := url.Parse(base)
+	m := &mdWriter{base: baseURL}
+	m.block(a.main, "")
+	out := blankLinesRe.ReplaceAllString(m.b.String(), "\n\n")
+	return strings.TrimSpace(out)
+}
+
+type mdWriter struct {
+	b    strings.Builder
+	base *url.URL
+}
+
+func (m *mdWriter) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if m.base == nil || ref == "" || strings.HasPrefix(ref, "#") {
+		return ref
+	}
+	u, err := m.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// block renders n's children, starting each block element on its own
+// paragraph; prefix is prepended to every line (blockquotes, list nesting).
+func (m *mdWriter) block(n *node, prefix string) {
+	var para strings.Builder
+	flush := func() {
+		text := strings.Join(strings.Fields(para.String()), " ")
+		para.Reset()
+		if text != "" {
+			m.b.WriteString(prefix + text + "\n\n")
+		}
+	}
+
+	for _, c := range n.children {
+		switch c.tag {
+		case "":
+			para.WriteString(c.text)
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			flush()
+			if text := m.inline(c); text != "" {
+				m.b.WriteString(prefix + strings.Repeat("#", int(c.tag[1]-'0')) + " " + text + "\n\n")
+			}
+		case "p", "div", "section", "article", "main", "figure", "table", "tr", "dl", "dd", "dt", "td", "th", "figcaption":
+			flush()
+			m.block(c, prefix)
+		case "ul", "ol":
+			flush()
+			m.list(c, prefix, c.tag == "ol")
+		case "li":
+			flush() // Stray item outside a list
+			m.b.WriteString(prefix + "- " + m.inline(c) + "\n")
+		case "blockquote":
+			flush()
+			m.block(c, prefix+"> ")
+		case "pre":
+			flush()
+			var code strings.Builder
+			c.walkRaw(&code)
+			m.b.WriteString(prefix + "```\n" + strings.Trim(code.String(), "\n") + "\n" + prefix + "```\n\n")
+		case "hr":
+			flush()
+			m.b.WriteString(prefix + "---\n\n")
+		case "br":
+			para.WriteString("\n")
+		default:
+			m.span(&para, c)
+		}
+	}
+	flush()
+}
+
+func (m *mdWriter) list(n *node, prefix string, ordered bool) {
+	i := 0
+	for _, c := range n.children {
+		if c.tag != "li" {
+			continue
+		}
+		i++
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(i) + ". "
+		}
+		var text strings.Builder
+		var nested []*node
+		for _, gc := range c.children {
+			if gc.tag == "ul" || gc.tag == "ol" {
+				nested = append(nested, gc)
+				continue
+			}
+			m.span(&text, gc)
+		}
+		m.b.WriteString(prefix + marker + strings.Join(strings.Fields(text.String()), " ") + "\n")
+		for _, l := range nested {
+			m.list(l, prefix+"  ", l.tag == "ol")
+		}
+	}
+	m.b.WriteString("\n")
+}
+
+// inline renders n's children as text-level content, on one line.
+func (m *mdWriter) inline(n *node) string {
+	var b strings.Builder
+	for _, c := range n.children {
+		m.span(&b, c)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// span renders a single node with emphasis, code, links and images.
+func (m *mdWriter) span(b *strings.Builder, n *node) {
+	switch n.tag {
+	case "":
+		b.WriteString(n.text)
+	case "a":
+		text := m.inline(n)
+		href := m.resolve(n.attrs["href"])
+		if href == "" || strings.HasPrefix(href, "javascript:") || text == "" {
+			b.WriteString(text)
+		} else {
+			b.WriteString("[" + text + "](" + href + ")")
+		}
+	case "img":
+		if src := m.resolve(n.attrs["src"]); src != "" {
+			b.WriteString("![" + n.attrs["alt"] + "](" + src + ")")
+		}
+	case "strong", "b":
+		if text := m.inline(n); text != "" {
+			b.WriteString(" **" + text + "** ")
+		}
+	case "em", "i":
+		if text := m.inline(n); text != "" {
+			b.WriteString(" _" + text + "_ ")
+		}
+	case "code":
+		if text := n.innerText(); text != "" {
+			b.WriteString("`" + text + "`")
+		}
+	case "br":
+		b.WriteString(" ")
+	default:
+		for _, c := range n.children {
+			m.span(b, c)
+		}
+		if blockTags[n.tag] {
+			b.WriteString(" ")
+		}
+	}
+}
+
+// walkRaw writes text with whitespace preserved (for <pre>).
+func (n *node) walkRaw(b *strings.Builder) {
+	if n.tag == "" {
+		b.WriteString(n.text)
+		return
+	}
+	if n.tag == "br" {
+		b.WriteString("\n")
+	}
+	for _, c := range n.children {
+		c.walkRaw(b)
+	}
+}
@@ -0,0 +1,39 @@
+package capture
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArticleMarkdown(t *testing.T) {
+	page := `<html><head><title>Notes</title></head><body>
+<nav><a href="/">Home</a></nav>
+<article>
+  <h2>Shipping costs</h2>
+  <p>Users abandon checkout when <strong>shipping costs</strong> surprise them, see <a href="/study">the study</a> for details.</p>
+  <ul><li>Show an <em>estimate</em> early</li><li>Explain delivery pricing on the product page</li></ul>
+  <blockquote><p>Trust matters more than price for first-time buyers.</p></blockquote>
+  <pre><code>total = price + shipping</code></pre>
+  <p>An image: <img src="img/chart.png" alt="chart"> and some <code>inline code</code> for good measure.</p>
+</article>
+</body></html>`
+
+	md := ExtractArticle(page).Markdown("https://example.com/blog/post")
+	for _, want := range []string{
+		"## Shipping costs",
+		"**shipping costs**",
+		"[the study](https://example.com/study)",
+		"- Show an _estimate_ early",
+		"> Trust matters more than price",
+		"```\ntotal = price + shipping\n```",
+		"![chart](https://example.com/blog/img/chart.png)",
+		"`inline code`",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Home") {
+		t.Errorf("Markdown includes navigation:\n%s", md)
+	}
+}
@@ -0,0 +1,172 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Note is a markdown or text file parsed for ingestion as a beat.
+type Note struct {
+	Title   string            // Filename without extension or date prefix, dashes as spaces
+	Impetus string            // Front matter impetus, if any
+	Content string            // Body without front matter
+	Date    *time.Time        // Front matter date, or a YYYY-MM-DD filename prefix
+	Tags    []string          // Front matter tags
+	Beads   []string          // Front matter beads / linked_beads
+	URL     string            // Front matter url / source
+	Meta    map[string]string // Remaining scalar front matter fields
+}
+
+// ErrEmptyNote is returned by ParseNote for notes without body text.
+var ErrEmptyNote = errors.New("note has no content")
+
+var (
+	datePrefixRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})[-_ ]*`)
+	noteDateFormats = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+)
+
+// IsNoteFile reports whether a file name looks like a markdown or text note.
+func IsNoteFile(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~") {
+		return false // Hidden files and editor/sync temporaries
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown", ".txt":
+		return true
+	}
+	return false
+}
+
+// SplitFrontMatter separates a leading YAML front matter block from the body.
+func SplitFrontMatter(data []byte) (map[string]interface{}, string, error) {
+	text := strings.TrimPrefix(string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))), "\ufeff")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, text, nil
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return nil, text, nil
+	}
+	block := text[4 : 4+end]
+	body := strings.TrimPrefix(text[4+end+4:], "\n")
+
+	props := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(block), &props); err != nil {
+		return nil, text, fmt.Errorf("invalid front matter: %w", err)
+	}
+	return props, body, nil
+}
+
+// ParseNote reads a note file: the filename gives the title (and a date when it
+// starts with YYYY-MM-DD), the body the content, and front matter adds impetus,
+// date, tags, beads and url.
+func ParseNote(name string, data []byte) (*Note, error) {
+	props, body, err := SplitFrontMatter(data)
+	if err != nil {
+		return nil, err
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	note := &Note{
+		Content: strings.TrimSpace(body),
+		Meta:    map[string]string{},
+	}
+	if m := datePrefixRegex.FindStringSubmatch(stem); m != nil {
+		if t, err := time.Parse("2006-01-02", m[1]); err == nil {
+			note.Date = &t
+			stem = stem[len(m[0]):]
+		}
+	}
+	note.Title = strings.Join(strings.Fields(strings.NewReplacer("-", " ", "_", " ").Replace(stem)), " ")
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := props[key]
+		switch strings.ToLower(key) {
+		case "impetus":
+			note.Impetus = scalarString(value)
+		case "date", "created", "created_at":
+			t, err := parseNoteDate(value)
+			if err != nil {
+				return nil, err
+			}
+			note.Date = &t
+		case "tags", "tag":
+			note.Tags = append(note.Tags, stringList(value)...)
+		case "beads", "bead", "linked_beads":
+			note.Beads = append(note.Beads, stringList(value)...)
+		case "url", "source":
+			note.URL = scalarString(value)
+		default:
+			if s := scalarString(value); s != "" {
+				note.Meta[key] = s
+			}
+		}
+	}
+
+	if note.Content == "" {
+		return nil, fmt.Errorf("%s: %w", name, ErrEmptyNote)
+	}
+	return note, nil
+}
+
+func parseNoteDate(value interface{}) (time.Time, error) {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC(), nil
+	}
+	s := scalarString(value)
+	for _, format := range noteDateFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized front matter date: %q", s)
+}
+
+// scalarString renders a YAML scalar; lists and maps yield "".
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []interface{}, map[string]interface{}:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// stringList accepts a YAML list or a comma/space separated string.
+func stringList(value interface{}) []string {
+	var out []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s := strings.TrimPrefix(scalarString(item), "#"); s != "" {
+				out = append(out, s)
+			}
+		}
+	case string:
+		for _, s := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if s = strings.TrimPrefix(s, "#"); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseNoteFilename(t *testing.T) {
+	note, err := ParseNote("2026-03-01-call_with-ana.md", []byte("Talked about pricing.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Title != "call with ana" || note.Impetus != "" || note.Content != "Talked about pricing." {
+		t.Errorf("note = %+v", note)
+	}
+	if note.Date == nil || note.Date.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("date = %v, want 2026-03-01 from filename", note.Date)
+	}
+}
+
+func TestParseNoteFrontMatter(t *testing.T) {
+	data := []byte("---\r\nimpetus: Podcast\r\ndate: 2026-02-10T08:30:00Z\r\ntags: [pricing, \"#growth\"]\r\nbeads: bd-12\r\nurl: https://example.com/ep1\r\nmood: curious\r\n---\r\n\r\nThe host argued for usage pricing.\r\n")
+	note, err := ParseNote("whatever.md", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Impetus != "Podcast" || note.Title != "whatever" || note.Content != "The host argued for usage pricing." {
+		t.Errorf("note = %+v", note)
+	}
+	if want := time.Date(2026, 2, 10, 8, 30, 0, 0, time.UTC); note.Date == nil || !note.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", note.Date, want)
+	}
+	if !reflect.DeepEqual(note.Tags, []string{"pricing", "growth"}) || !reflect.DeepEqual(note.Beads, []string{"bd-12"}) {
+		t.Errorf("tags = %v, beads = %v", note.Tags, note.Beads)
+	}
+	if note.URL != "https://example.com/ep1" || note.Meta["mood"] != "curious" {
+		t.Errorf("url = %q, meta = %v", note.URL, note.Meta)
+	}
+}
+
+func TestParseNoteErrors(t *testing.T) {
+	if _, err := ParseNote("empty.md", []byte("---\nimpetus: x\n---\n\n")); err == nil {
+		t.Error("ParseNote(empty body) succeeded, want error")
+	}
+	if _, err := ParseNote("bad.md", []byte("---\ndate: someday\n---\nbody")); err == nil {
+		t.Error("ParseNote(bad date) succeeded, want error")
+	}
+}
+
+func TestIsNoteFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"idea.md": true, "Idea.TXT": true, "x.markdown": true,
+		".hidden.md": false, "~lock.md": false, "photo.jpg": false,
+	} {
+		if got := IsNoteFile(name); got != want {
+			t.Errorf("IsNoteFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
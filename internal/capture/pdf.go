@@ -0,0 +1,754 @@
+package capture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// PDFDocument is the text and metadata extracted from a PDF.
+type PDFDocument struct {
+	Title  string
+	Author string
+	Pages  int
+	Text   string // Page texts separated by blank lines
+}
+
+// Excerpt returns roughly the first n words of the document text.
+func (d *PDFDocument) Excerpt(n int) string {
+	words := strings.Fields(d.Text)
+	if len(words) <= n {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:n], " ") + "..."
+}
+
+// maxPDFSize bounds downloaded and local documents.
+const maxPDFSize = 50 << 20
+
+// ReadPDF loads a PDF from a local path or an http(s) URL.
+func ReadPDF(pathOrURL string) ([]byte, error) {
+	if !strings.HasPrefix(pathOrURL, "http://") && !strings.HasPrefix(pathOrURL, "https://") {
+		info, err := os.Stat(pathOrURL)
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > maxPDFSize {
+			return nil, fmt.Errorf("%s is larger than %d MB", pathOrURL, maxPDFSize>>20)
+		}
+		return os.ReadFile(pathOrURL)
+	}
+
+	resp, err := httpClient.Get(pathOrURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", pathOrURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", pathOrURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", pathOrURL, err)
+	}
+	if len(data) > maxPDFSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", pathOrURL, maxPDFSize>>20)
+	}
+	return data, nil
+}
+
+// Best-effort extraction: it handles the unencrypted, Flate-compressed PDFs
+// produced by LaTeX, browsers and office suites, not scanned images.
+
+type pdfRef struct{ num, gen int }
+
+type pdfName string
+
+type pdfObject struct {
+	value  interface{}
+	stream []byte // Decoded stream data, nil if none or undecodable
+}
+
+type pdfFile struct {
+	objects map[int]*pdfObject
+	cmaps   map[int]*toUnicode // Parsed ToUnicode maps by object number
+}
+
+var objHeaderRegex = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// ExtractPDF parses a PDF and extracts its text in page order.
+func ExtractPDF(data []byte) (*PDFDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\r\n\t "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+
+	f := &pdfFile{objects: make(map[int]*pdfObject), cmaps: make(map[int]*toUnicode)}
+	f.readObjects(data)
+
+	var trailer map[string]interface{}
+	if i := bytes.LastIndex(data, []byte("trailer")); i >= 0 {
+		p := &pdfParser{data: data, pos: i + len("trailer")}
+		trailer, _ = p.value().(map[string]interface{})
+	}
+	if trailer == nil {
+		// PDF 1.5+: the trailer lives in a cross-reference stream
+		for _, o := range f.objects {
+			if d, ok := o.value.(map[string]interface{}); ok && d["Type"] == pdfName("XRef") {
+				trailer = d
+			}
+		}
+	}
+	if trailer != nil && trailer["Encrypt"] != nil {
+		return nil, fmt.Errorf("encrypted PDFs are not supported")
+	}
+
+	doc := &PDFDocument{}
+	if trailer != nil {
+		if info, ok := f.resolve(trailer["Info"]).(map[string]interface{}); ok {
+			doc.Title = pdfText(f.resolve(info["Title"]))
+			doc.Author = pdfText(f.resolve(info["Author"]))
+		}
+	}
+
+	var root map[string]interface{}
+	if trailer != nil {
+		root, _ = f.resolve(trailer["Root"]).(map[string]interface{})
+	}
+	if root == nil {
+		for _, o := range f.objects {
+			if d, ok := o.value.(map[string]interface{}); ok && d["Type"] == pdfName("Catalog") {
+				root = d
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no document catalog found")
+	}
+
+	var pages []string
+	f.walkPages(f.resolve(root["Pages"]), nil, 0, func(page map[string]interface{}, resources map[string]interface{}) {
+		pages = append(pages, f.pageText(page, resources))
+	})
+	doc.Pages = len(pages)
+
+	var texts []string
+	for _, p := range pages {
+		if p = strings.TrimSpace(p); p != "" {
+			texts = append(texts, p)
+		}
+	}
+	doc.Text = strings.Join(texts, "\n\n")
+	return doc, nil
+}
+
+// readObjects indexes every indirect object, including those packed in
+// compressed object streams.
+func (f *pdfFile) readObjects(data []byte) {
+	for _, m := range objHeaderRegex.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		p := &pdfParser{data: data, pos: m[1]}
+		obj := &pdfObject{value: p.value()}
+		if dict, ok := obj.value.(map[string]interface{}); ok {
+			p.skipSpace()
+			if bytes.HasPrefix(data[p.pos:], []byte("stream")) {
+				obj.stream = f.readStream(data, p.pos+len("stream"), dict)
+			}
+		}
+		f.objects[num] = obj
+	}
+
+	for _, o := range f.objects {
+		dict, ok := o.value.(map[string]interface{})
+		if !ok || dict["Type"] != pdfName("ObjStm") || o.stream == nil {
+			continue
+		}
+		n, _ := f.resolve(dict["N"]).(float64)
+		first, _ := f.resolve(dict["First"]).(float64)
+		header := &pdfParser{data: o.stream}
+		for i := 0; i < int(n); i++ {
+			num, _ := header.value().(float64)
+			offset, _ := header.value().(float64)
+			if int(first)+int(offset) >= len(o.stream) {
+				break
+			}
+			p := &pdfParser{data: o.stream, pos: int(first) + int(offset)}
+			if _, exists := f.objects[int(num)]; !exists {
+				f.objects[int(num)] = &pdfObject{value: p.value()}
+			}
+		}
+	}
+}
+
+func (f *pdfFile) readStream(data []byte, start int, dict map[string]interface{}) []byte {
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+	end := -1
+	if length, ok := dict["Length"].(float64); ok && start+int(length) <= len(data) {
+		end = start + int(length)
+	} else if i := bytes.Index(data[start:], []byte("endstream")); i >= 0 {
+		end = start + i
+	}
+	if end < 0 {
+		return nil
+	}
+	raw := data[start:end]
+
+	var filters []interface{}
+	switch v := dict["Filter"].(type) {
+	case pdfName:
+		filters = []interface{}{v}
+	case []interface{}:
+		filters = v
+	}
+	for _, filter := range filters {
+		if filter != pdfName("FlateDecode") {
+			return nil // Images and other encodings carry no text
+		}
+		r, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil
+		}
+		decoded, err := io.ReadAll(r)
+		if len(decoded) == 0 && err != nil {
+			return nil
+		}
+		raw = decoded
+	}
+	return raw
+}
+
+func (f *pdfFile) resolve(v interface{}) interface{} {
+	for i := 0; i < 10; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		o := f.objects[ref.num]
+		if o == nil {
+			return nil
+		}
+		v = o.value
+	}
+	return nil
+}
+
+func (f *pdfFile) streamOf(v interface{}) []byte {
+	if ref, ok := v.(pdfRef); ok {
+		if o := f.objects[ref.num]; o != nil {
+			return o.stream
+		}
+	}
+	return nil
+}
+
+// walkPages visits pages in document order, passing inherited resources.
+func (f *pdfFile) walkPages(node interface{}, inherited map[string]interface{}, depth int, visit func(page, resources map[string]interface{})) {
+	dict, ok := node.(map[string]interface{})
+	if !ok || depth > 32 {
+		return
+	}
+	resources := inherited
+	if r, ok := f.resolve(dict["Resources"]).(map[string]interface{}); ok {
+		resources = r
+	}
+	if dict["Type"] == pdfName("Page") {
+		visit(dict, resources)
+		return
+	}
+	kids, _ := f.resolve(dict["Kids"]).([]interface{})
+	for _, kid := range kids {
+		f.walkPages(f.resolve(kid), resources, depth+1, visit)
+	}
+}
+
+func (f *pdfFile) pageText(page, resources map[string]interface{}) string {
+	var content []byte
+	switch c := page["Contents"].(type) {
+	case pdfRef:
+		if s := f.streamOf(c); s != nil {
+			content = s
+		} else if arr, ok := f.resolve(c).([]interface{}); ok {
+			for _, part := range arr {
+				content = append(append(content, f.streamOf(part)...), '\n')
+			}
+		}
+	case []interface{}:
+		for _, part := range c {
+			content = append(append(content, f.streamOf(part)...), '\n')
+		}
+	}
+
+	fonts := make(map[string]*toUnicode)
+	if resources != nil {
+		if fontDict, ok := f.resolve(resources["Font"]).(map[string]interface{}); ok {
+			for name, ref := range fontDict {
+				font, ok := f.resolve(ref).(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if cmapRef, ok := font["ToUnicode"].(pdfRef); ok {
+					fonts[name] = f.cmap(cmapRef)
+				}
+			}
+		}
+	}
+	return contentText(content, fonts)
+}
+
+func (f *pdfFile) cmap(ref pdfRef) *toUnicode {
+	if m, ok := f.cmaps[ref.num]; ok {
+		return m
+	}
+	var m *toUnicode
+	if s := f.streamOf(ref); s != nil {
+		m = parseToUnicode(s)
+	}
+	f.cmaps[ref.num] = m
+	return m
+}
+
+// contentText runs the text operators of a content stream.
+func contentText(content []byte, fonts map[string]*toUnicode) string {
+	var out strings.Builder
+	var operands []interface{}
+	var font *toUnicode
+	p := &pdfParser{data: content}
+
+	show := func(s interface{}) {
+		if str, ok := s.(pdfString); ok {
+			out.WriteString(font.decode([]byte(str)))
+		}
+	}
+	newline := func() {
+		if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+			out.WriteString("\n")
+		}
+	}
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			break
+		}
+		c := p.data[p.pos]
+		if c == '/' || c == '(' || c == '<' || c == '[' || c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9') {
+			operands = append(operands, p.value())
+			continue
+		}
+		op := p.keyword()
+		if op == "" {
+			p.pos++
+			continue
+		}
+		switch op {
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "'", "\"":
+			newline()
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[len(operands)-1].([]interface{})
+				for _, item := range arr {
+					if n, ok := item.(float64); ok && n < -200 {
+						out.WriteString(" ") // Large negative kerning is a word gap
+						continue
+					}
+					show(item)
+				}
+			}
+		case "Td", "TD":
+			// Vertical moves start a new line; horizontal ones separate words
+			if len(operands) >= 2 {
+				if ty, ok := operands[len(operands)-1].(float64); ok && ty != 0 {
+					newline()
+				} else if !strings.HasSuffix(out.String(), " ") {
+					out.WriteString(" ")
+				}
+			}
+		case "T*", "Tm", "ET":
+			newline()
+		case "BI":
+			// Skip inline image data
+			if i := bytes.Index(p.data[p.pos:], []byte("EI")); i >= 0 {
+				p.pos += i + 2
+			}
+		}
+		operands = operands[:0]
+	}
+	return normalizeExtracted(out.String())
+}
+
+// normalizeExtracted collapses spacing and rejoins words hyphenated across lines.
+func normalizeExtracted(s string) string {
+	lines := strings.Split(s, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			prev := b.String()
+			if strings.HasSuffix(prev, "-") && len(prev) > 1 && unicode.IsLetter(rune(prev[len(prev)-2])) {
+				trimmed := strings.TrimSuffix(prev, "-")
+				b.Reset()
+				b.WriteString(trimmed)
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// toUnicode maps character codes to text, from a font's ToUnicode CMap.
+type toUnicode struct {
+	codeLen int
+	m       map[uint32]string
+}
+
+var (
+	codespaceRegex = regexp.MustCompile(`begincodespacerange\s*<([0-9A-Fa-f]+)>`)
+	bfcharRegex    = regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`)
+	bfrangeRegex   = regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`)
+	hexPairRegex   = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]*)>`)
+	hexRangeRegex  = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>\s*(<[0-9A-Fa-f]*>|\[[^\]]*\])`)
+)
+
+func parseToUnicode(data []byte) *toUnicode {
+	s := string(data)
+	t := &toUnicode{codeLen: 1, m: make(map[uint32]string)}
+	if m := codespaceRegex.FindStringSubmatch(s); m != nil {
+		t.codeLen = (len(m[1]) + 1) / 2
+	}
+	for _, block := range bfcharRegex.FindAllStringSubmatch(s, -1) {
+		for _, pair := range hexPairRegex.FindAllStringSubmatch(block[1], -1) {
+			code, _ := strconv.ParseUint(pair[1], 16, 32)
+			t.m[uint32(code)] = utf16Hex(pair[2])
+		}
+	}
+	for _, block := range bfrangeRegex.FindAllStringSubmatch(s, -1) {
+		for _, r := range hexRangeRegex.FindAllStringSubmatch(block[1], -1) {
+			lo, _ := strconv.ParseUint(r[1], 16, 32)
+			hi, _ := strconv.ParseUint(r[2], 16, 32)
+			if hi < lo || hi-lo > 0xffff {
+				continue
+			}
+			if strings.HasPrefix(r[3], "[") {
+				dsts := regexp.MustCompile(`<([0-9A-Fa-f]*)>`).FindAllStringSubmatch(r[3], -1)
+				for i, d := range dsts {
+					t.m[uint32(lo)+uint32(i)] = utf16Hex(d[1])
+				}
+				continue
+			}
+			base := []rune(utf16Hex(strings.Trim(r[3], "<>")))
+			if len(base) == 0 {
+				continue
+			}
+			for code := lo; code <= hi; code++ {
+				out := append([]rune(nil), base...)
+				out[len(out)-1] += rune(code - lo)
+				t.m[uint32(code)] = string(out)
+			}
+		}
+	}
+	return t
+}
+
+func (t *toUnicode) decode(b []byte) string {
+	if t == nil || len(t.m) == 0 {
+		return latinText(b)
+	}
+	var out strings.Builder
+	for i := 0; i+t.codeLen <= len(b); i += t.codeLen {
+		var code uint32
+		for _, c := range b[i : i+t.codeLen] {
+			code = code<<8 | uint32(c)
+		}
+		if s, ok := t.m[code]; ok {
+			out.WriteString(s)
+		} else if t.codeLen == 1 {
+			out.WriteString(latinText(b[i : i+1]))
+		}
+	}
+	return out.String()
+}
+
+// latinText decodes single-byte strings, expanding the TeX ligature codes.
+func latinText(b []byte) string {
+	var out strings.Builder
+	for _, c := range b {
+		switch {
+		case c == 0x0b:
+			out.WriteString("ff")
+		case c == 0x0c:
+			out.WriteString("fi")
+		case c == 0x0d:
+			out.WriteString("fl")
+		case c == 0x0e:
+			out.WriteString("ffi")
+		case c == 0x0f:
+			out.WriteString("ffl")
+		case c < 0x20:
+			// Control codes carry no text
+		default:
+			out.WriteRune(rune(c))
+		}
+	}
+	return out.String()
+}
+
+func utf16Hex(h string) string {
+	if len(h)%4 != 0 {
+		if n, err := strconv.ParseUint(h, 16, 32); err == nil {
+			return string(rune(n))
+		}
+		return ""
+	}
+	units := make([]uint16, 0, len(h)/4)
+	for i := 0; i < len(h); i += 4 {
+		u, _ := strconv.ParseUint(h[i:i+4], 16, 16)
+		units = append(units, uint16(u))
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfText decodes a text string from the document info (PDFDocEncoding or UTF-16BE).
+func pdfText(v interface{}) string {
+	s, ok := v.(pdfString)
+	if !ok {
+		return ""
+	}
+	b := []byte(s)
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return strings.TrimSpace(string(utf16.Decode(units)))
+	}
+	return strings.TrimSpace(latinText(b))
+}
+
+type pdfString string
+
+// pdfParser reads PDF objects from a byte slice.
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func (p *pdfParser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isPDFSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *pdfParser) keyword() string {
+	start := p.pos
+	for p.pos < len(p.data) && !isPDFSpace(p.data[p.pos]) && !isPDFDelim(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// value parses one object; numbers followed by "G R" become references.
+func (p *pdfParser) value() interface{} {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil
+	}
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		p.pos++
+		return pdfName(p.keyword())
+	case c == '(':
+		return p.literalString()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		p.pos += 2
+		dict := make(map[string]interface{})
+		for {
+			p.skipSpace()
+			if p.pos >= len(p.data) {
+				return dict
+			}
+			if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+				p.pos += 2
+				return dict
+			}
+			key, ok := p.value().(pdfName)
+			if !ok {
+				return dict
+			}
+			dict[string(key)] = p.value()
+		}
+	case c == '<':
+		p.pos++
+		end := bytes.IndexByte(p.data[p.pos:], '>')
+		if end < 0 {
+			p.pos = len(p.data)
+			return pdfString("")
+		}
+		h := strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.ASCII_Hex_Digit, r) {
+				return r
+			}
+			return -1
+		}, string(p.data[p.pos:p.pos+end]))
+		p.pos += end + 1
+		if len(h)%2 == 1 {
+			h += "0"
+		}
+		b := make([]byte, len(h)/2)
+		for i := range b {
+			v, _ := strconv.ParseUint(h[2*i:2*i+2], 16, 8)
+			b[i] = byte(v)
+		}
+		return pdfString(b)
+	case c == '[':
+		p.pos++
+		var arr []interface{}
+		for {
+			p.skipSpace()
+			if p.pos >= len(p.data) {
+				return arr
+			}
+			if p.data[p.pos] == ']' {
+				p.pos++
+				return arr
+			}
+			before := p.pos
+			arr = append(arr, p.value())
+			if p.pos == before {
+				p.pos++ // Skip an unparseable byte rather than loop
+			}
+		}
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		n, err := strconv.ParseFloat(p.keyword(), 64)
+		if err != nil {
+			return nil
+		}
+		// Look ahead for "gen R"
+		save := p.pos
+		p.skipSpace()
+		genStart := p.pos
+		gen := p.keyword()
+		if g, err := strconv.Atoi(gen); err == nil && genStart < p.pos {
+			p.skipSpace()
+			if p.pos < len(p.data) && p.data[p.pos] == 'R' && (p.pos+1 == len(p.data) || isPDFSpace(p.data[p.pos+1]) || isPDFDelim(p.data[p.pos+1])) {
+				p.pos++
+				return pdfRef{num: int(n), gen: g}
+			}
+		}
+		p.pos = save
+		return n
+	default:
+		kw := p.keyword()
+		switch kw {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "":
+			p.pos++
+		}
+		return nil
+	}
+}
+
+func (p *pdfParser) literalString() pdfString {
+	p.pos++ // (
+	var b []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(b)
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				return pdfString(b)
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				if e == '\r' && p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue // Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return pdfString(b)
+}
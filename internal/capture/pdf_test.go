@@ -0,0 +1,167 @@
+package capture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func flate(s string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write([]byte(s))
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+// testPDF assembles a two-page document: a simple font with TeX ligatures on
+// page one and a two-byte font mapped through a ToUnicode CMap on page two.
+func testPDF(encrypt bool) []byte {
+	page1 := "BT /F1 12 Tf 72 700 Td (The \\014rst page) Tj 0 -14 Td [(of a)-300(docu)20(ment)] TJ ET"
+	page2 := "BT /F2 10 Tf 72 700 Td <00010002> Tj T* <0003> Tj ET"
+	cmap := `/CIDInit /ProcSet findresource begin
+begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar
+<0001> <0048>
+<0002> <0069>
+endbfchar
+1 beginbfrange
+<0003> <0003> <00E9>
+endbfrange
+endcmap`
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	obj := func(n int, body string) { fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", n, body) }
+	stream := func(n int, data []byte) {
+		fmt.Fprintf(&b, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", n, len(data))
+		b.Write(data)
+		b.WriteString("\nendstream\nendobj\n")
+	}
+	obj(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	obj(2, "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 7 0 R /F2 8 0 R >> >> >>")
+	obj(3, "<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>")
+	obj(4, "<< /Type /Page /Parent 2 0 R /Contents [6 0 R] >>")
+	stream(5, flate(page1))
+	stream(6, flate(page2))
+	obj(7, "<< /Type /Font /Subtype /Type1 /BaseFont /CMR10 >>")
+	obj(8, "<< /Type /Font /Subtype /Type0 /Encoding /Identity-H /ToUnicode 9 0 R >>")
+	stream(9, flate(cmap))
+	obj(10, "<< /Title <FEFF0054006500730074> /Author (Ada \\(A.\\) Lovelace) >>")
+	trailer := "<< /Root 1 0 R /Info 10 0 R /Size 11 >>"
+	if encrypt {
+		trailer = "<< /Root 1 0 R /Info 10 0 R /Encrypt 11 0 R /Size 12 >>"
+	}
+	fmt.Fprintf(&b, "trailer\n%s\nstartxref\n0\n%%%%EOF\n", trailer)
+	return b.Bytes()
+}
+
+func TestExtractPDF(t *testing.T) {
+	doc, err := ExtractPDF(testPDF(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Test" || doc.Author != "Ada (A.) Lovelace" {
+		t.Errorf("info = %q, %q", doc.Title, doc.Author)
+	}
+	if doc.Pages != 2 {
+		t.Errorf("pages = %d, want 2", doc.Pages)
+	}
+	if want := "The first page of a document\n\nHi é"; doc.Text != want {
+		t.Errorf("text = %q, want %q", doc.Text, want)
+	}
+	if got := doc.Excerpt(3); got != "The first page..." {
+		t.Errorf("Excerpt(3) = %q", got)
+	}
+}
+
+func TestExtractPDFRejects(t *testing.T) {
+	if _, err := ExtractPDF([]byte("<html></html>")); err == nil {
+		t.Error("ExtractPDF(html) succeeded, want error")
+	}
+	if _, err := ExtractPDF(testPDF(true)); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("ExtractPDF(encrypted) err = %v", err)
+	}
+}
+
+func TestParseArxivID(t *testing.T) {
+	cases := map[string]string{
+		"2301.01234":                           "2301.01234",
+		"arxiv:2301.01234v2":                   "2301.01234v2",
+		"https://arxiv.org/abs/1706.03762":     "1706.03762",
+		"https://arxiv.org/pdf/1706.03762v7":   "1706.03762v7",
+		"https://arxiv.org/pdf/2301.01234.pdf": "2301.01234",
+		"hep-th/9901001":                       "hep-th/9901001",
+	}
+	for ref, want := range cases {
+		if id, err := ParseArxivID(ref); err != nil || id != want {
+			t.Errorf("ParseArxivID(%q) = %q, %v; want %q", ref, id, err, want)
+		}
+	}
+	if _, err := ParseArxivID("https://example.com/abs/1706.03762"); err == nil {
+		t.Error("ParseArxivID(other site) succeeded, want error")
+	}
+}
+
+func TestCaptureArxivPaper(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/query":
+			if r.URL.Query().Get("id_list") != "1706.03762" {
+				fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>x</id></entry></feed>`)
+				return
+			}
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>http://arxiv.org/abs/1706.03762v7</id>
+    <published>2017-06-12T17:57:34Z</published>
+    <title>Attention Is All
+      You Need</title>
+    <summary>  The dominant sequence transduction models
+      are based on recurrent networks.</summary>
+    <author><name>Ashish Vaswani</name></author>
+    <author><name>Noam Shazeer</name></author>
+    <link href="http://arxiv.org/abs/1706.03762v7" rel="alternate" type="text/html"/>
+    <link title="pdf" href="%s/pdf/1706.03762v7" rel="related" type="application/pdf"/>
+  </entry>
+</feed>`, srv.URL)
+		case "/pdf/1706.03762v7":
+			_, _ = w.Write(testPDF(false))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := ArxivConfig{APIURL: srv.URL + "/api/query"}
+	paper, err := CaptureArxivPaper("https://arxiv.org/abs/1706.03762", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paper.Title != "Attention Is All You Need" || len(paper.Authors) != 2 || paper.Published.Year() != 2017 {
+		t.Errorf("paper = %+v", paper)
+	}
+	if paper.Abstract != "The dominant sequence transduction models are based on recurrent networks." {
+		t.Errorf("abstract = %q", paper.Abstract)
+	}
+	if paper.Document == nil || paper.Document.Pages != 2 || len(paper.PDF) == 0 {
+		t.Fatalf("document = %+v", paper.Document)
+	}
+	content := paper.Content()
+	for _, want := range []string{"Attention Is All You Need", "Ashish Vaswani, Noam Shazeer", "recurrent networks.", "arxiv.org/abs/1706.03762v7"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q:\n%s", want, content)
+		}
+	}
+
+	if _, err := CaptureArxivPaper("0000.00000", cfg); err == nil {
+		t.Error("CaptureArxivPaper(unknown) succeeded, want error")
+	}
+}
@@ -0,0 +1,588 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNoWhisper is returned by TranscribeAudio when no Whisper command is
+// configured or installed.
+var ErrNoWhisper = errors.New("no whisper command available")
+
+const (
+	maxFeedBytes       = 20 << 20
+	maxTranscriptBytes = 10 << 20
+	whisperTimeout     = 2 * time.Hour
+)
+
+// PodcastEpisode is a podcast episode's metadata plus its transcript when
+// the feed links one or it was transcribed locally.
+type PodcastEpisode struct {
+	Show          string
+	Title         string
+	Author        string
+	Description   string
+	Published     time.Time
+	Duration      string
+	Link          string // Episode page
+	AudioURL      string
+	GUID          string
+	FeedURL       string
+	TranscriptURL string
+	Transcript    string
+	// TranscriptSource is "feed" or "whisper", empty without a transcript.
+	TranscriptSource string
+}
+
+// URL returns the episode page, or the audio file for feeds without one.
+func (e *PodcastEpisode) URL() string {
+	return firstNonEmpty(e.Link, e.AudioURL, e.FeedURL)
+}
+
+// Content renders the episode as beat content.
+func (e *PodcastEpisode) Content() string {
+	var b strings.Builder
+	b.WriteString(e.Title)
+	var line []string
+	if e.Show != "" {
+		line = append(line, e.Show)
+	}
+	if !e.Published.IsZero() {
+		line = append(line, e.Published.Format("2006-01-02"))
+	}
+	if e.Duration != "" {
+		line = append(line, e.Duration)
+	}
+	if len(line) > 0 {
+		b.WriteString("\n" + strings.Join(line, " · "))
+	}
+	if e.Description != "" {
+		b.WriteString("\n\n" + clip(e.Description, 600))
+	}
+	b.WriteString("\n\n" + e.URL())
+	return b.String()
+}
+
+type podcastFeed struct {
+	Channel struct {
+		Title  string        `xml:"title"`
+		Author string        `xml:"author"`
+		Items  []podcastItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type podcastItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+	Summary     string `xml:"summary"` // itunes:summary
+	Duration    string `xml:"duration"`
+	Author      string `xml:"author"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Transcripts []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"transcript"` // podcast:transcript
+}
+
+// ParsePodcastFeed parses an RSS podcast feed into episodes, newest first.
+func ParsePodcastFeed(data []byte, feedURL string) ([]*PodcastEpisode, error) {
+	var feed podcastFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("decoding feed: %w", err)
+	}
+	if len(feed.Channel.Items) == 0 {
+		return nil, fmt.Errorf("feed has no episodes")
+	}
+
+	var episodes []*PodcastEpisode
+	for _, item := range feed.Channel.Items {
+		e := &PodcastEpisode{
+			Show:        strings.TrimSpace(feed.Channel.Title),
+			Title:       strings.Join(strings.Fields(item.Title), " "),
+			Author:      strings.TrimSpace(firstNonEmpty(item.Author, feed.Channel.Author)),
+			Description: htmlToText(firstNonEmpty(item.Description, item.Summary)),
+			Duration:    formatPodcastDuration(item.Duration),
+			Link:        strings.TrimSpace(item.Link),
+			AudioURL:    strings.TrimSpace(item.Enclosure.URL),
+			GUID:        strings.TrimSpace(item.GUID),
+			FeedURL:     feedURL,
+		}
+		e.Published = parsePodcastDate(item.PubDate)
+		// Prefer formats that convert to clean text
+		best := -1
+		for i, t := range item.Transcripts {
+			if best < 0 || transcriptRank(t.Type, t.URL) > transcriptRank(item.Transcripts[best].Type, item.Transcripts[best].URL) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			e.TranscriptURL = resolveURL(feedURL, item.Transcripts[best].URL)
+		}
+		episodes = append(episodes, e)
+	}
+
+	// Most feeds are newest first, but not all
+	for i := 1; i < len(episodes); i++ {
+		for j := i; j > 0 && episodes[j].Published.After(episodes[j-1].Published); j-- {
+			episodes[j], episodes[j-1] = episodes[j-1], episodes[j]
+		}
+	}
+	return episodes, nil
+}
+
+// FindEpisode returns the newest episode, or the one whose GUID, page or
+// audio URL equals query, falling back to a title substring match.
+func FindEpisode(episodes []*PodcastEpisode, query string) *PodcastEpisode {
+	query = strings.TrimSpace(query)
+	if len(episodes) == 0 {
+		return nil
+	}
+	if query == "" {
+		return episodes[0]
+	}
+	for _, e := range episodes {
+		if query == e.GUID || sameURL(query, e.Link) || sameURL(query, e.AudioURL) {
+			return e
+		}
+	}
+	lower := strings.ToLower(query)
+	for _, e := range episodes {
+		if strings.Contains(strings.ToLower(e.Title), lower) {
+			return e
+		}
+	}
+	return nil
+}
+
+// CapturePodcastEpisode resolves ref to an episode. ref is an RSS feed URL
+// (the newest episode, or the one matching episode or the URL's #fragment),
+// an episode page that advertises its feed, or a direct audio URL. The
+// transcript linked by the feed is fetched when there is one; TranscribeAudio
+// covers the rest.
+func CapturePodcastEpisode(ref, episode string) (*PodcastEpisode, error) {
+	ref = strings.TrimSpace(ref)
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return nil, fmt.Errorf("not a podcast feed or episode URL: %s", ref)
+	}
+	if i := strings.Index(ref, "#"); i >= 0 {
+		if episode == "" {
+			if frag, err := url.PathUnescape(ref[i+1:]); err == nil {
+				episode = frag
+			}
+		}
+		ref = ref[:i]
+	}
+
+	ep, err := resolveEpisode(ref, episode)
+	if err != nil {
+		return nil, err
+	}
+
+	if ep.TranscriptURL != "" {
+		if text, err := fetchTranscript(ep.TranscriptURL); err == nil && text != "" {
+			ep.Transcript, ep.TranscriptSource = text, "feed"
+		}
+	}
+	return ep, nil
+}
+
+func resolveEpisode(ref, episode string) (*PodcastEpisode, error) {
+	resp, err := httpClient.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", ref, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", ref, resp.StatusCode)
+	}
+
+	ct := strings.ToLower(resp.Header.Get("Content-Type"))
+	if strings.HasPrefix(ct, "audio/") || strings.HasPrefix(ct, "video/") || isAudioURL(ref) {
+		// Don't download the audio just to learn its name
+		name := strings.TrimSuffix(filepath.Base(resp.Request.URL.Path), filepath.Ext(resp.Request.URL.Path))
+		return &PodcastEpisode{Title: name, AudioURL: ref}, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ref, err)
+	}
+	if isFeed(ct, data) {
+		episodes, err := ParsePodcastFeed(data, ref)
+		if err != nil {
+			return nil, err
+		}
+		ep := FindEpisode(episodes, episode)
+		if ep == nil {
+			return nil, fmt.Errorf("no episode matching %q in %s", episode, ref)
+		}
+		return ep, nil
+	}
+	return pageEpisode(ref, string(data))
+}
+
+// pageEpisode handles an episode web page: the episode from the feed the
+// page advertises when it can be matched, else what the page itself says.
+func pageEpisode(pageURL, page string) (*PodcastEpisode, error) {
+	root := parseHTML(page)
+	article := ExtractArticle(page)
+
+	var feedURL, audioURL, ogTitle string
+	root.each(func(n *node) bool {
+		switch n.tag {
+		case "link":
+			typ := strings.ToLower(n.attrs["type"])
+			if feedURL == "" && strings.Contains(strings.ToLower(n.attrs["rel"]), "alternate") &&
+				(strings.Contains(typ, "rss") || strings.Contains(typ, "xml")) {
+				feedURL = resolveURL(pageURL, n.attrs["href"])
+			}
+		case "meta":
+			switch strings.ToLower(firstNonEmpty(n.attrs["property"], n.attrs["name"])) {
+			case "og:audio", "og:audio:url", "og:audio:secure_url", "twitter:player:stream":
+				if audioURL == "" {
+					audioURL = resolveURL(pageURL, n.attrs["content"])
+				}
+			case "og:title":
+				ogTitle = strings.TrimSpace(n.attrs["content"])
+			}
+		case "audio", "source":
+			if audioURL == "" && n.attrs["src"] != "" {
+				audioURL = resolveURL(pageURL, n.attrs["src"])
+			}
+		case "a":
+			if audioURL == "" && isAudioURL(n.attrs["href"]) {
+				audioURL = resolveURL(pageURL, n.attrs["href"])
+			}
+		}
+		return true
+	})
+
+	title := firstNonEmpty(ogTitle, article.Title)
+	if feedURL != "" {
+		if data, err := fetchLimited(feedURL, maxFeedBytes); err == nil {
+			if episodes, err := ParsePodcastFeed(data, feedURL); err == nil {
+				query := pageURL
+				if FindEpisode(episodes, query) == nil && audioURL != "" {
+					query = audioURL
+				}
+				if ep := FindEpisode(episodes, query); ep != nil {
+					if ep.Link == "" {
+						ep.Link = pageURL
+					}
+					return ep, nil
+				}
+				for _, ep := range episodes {
+					if title != "" && strings.EqualFold(ep.Title, title) {
+						return ep, nil
+					}
+				}
+			}
+		}
+	}
+
+	if audioURL == "" {
+		return nil, fmt.Errorf("no podcast feed or audio found at %s", pageURL)
+	}
+	return &PodcastEpisode{
+		Show:        article.SiteName,
+		Title:       title,
+		Author:      article.Author,
+		Description: firstNonEmpty(article.Description, article.Excerpt(600)),
+		Link:        pageURL,
+		AudioURL:    audioURL,
+		FeedURL:     feedURL,
+	}, nil
+}
+
+// fetchTranscript downloads a transcript and converts it to plain text.
+func fetchTranscript(transcriptURL string) (string, error) {
+	resp, err := httpClient.Get(transcriptURL)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTranscriptBytes))
+	if err != nil {
+		return "", err
+	}
+	return TranscriptText(data, resp.Header.Get("Content-Type"), transcriptURL), nil
+}
+
+// TranscriptText converts a WebVTT, SRT, podcast JSON, HTML or plain text
+// transcript to paragraphs, dropping cue numbers and timings.
+func TranscriptText(data []byte, contentType, source string) string {
+	ct := strings.ToLower(contentType)
+	ext := strings.ToLower(filepath.Ext(strings.SplitN(source, "?", 2)[0]))
+	switch {
+	case strings.Contains(ct, "json") || ext == ".json":
+		var doc struct {
+			Segments []struct {
+				Speaker string `json:"speaker"`
+				Body    string `json:"body"`
+			} `json:"segments"`
+		}
+		if json.Unmarshal(data, &doc) == nil && len(doc.Segments) > 0 {
+			var b strings.Builder
+			speaker := ""
+			for _, s := range doc.Segments {
+				if s.Speaker != "" && s.Speaker != speaker {
+					speaker = s.Speaker
+					b.WriteString("\n\n" + speaker + ": ")
+				} else {
+					b.WriteString(" ")
+				}
+				b.WriteString(strings.TrimSpace(s.Body))
+			}
+			return strings.TrimSpace(b.String())
+		}
+	case strings.Contains(ct, "vtt") || strings.Contains(ct, "srt") || strings.Contains(ct, "subrip") ||
+		ext == ".vtt" || ext == ".srt":
+		return cueText(string(data))
+	case strings.Contains(ct, "html") || ext == ".html" || ext == ".htm":
+		return paragraphs(parseHTML(string(data)))
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+}
+
+// cueText extracts the spoken text from WebVTT or SRT cues, merging the
+// repeated lines rolling captions produce.
+func cueText(src string) string {
+	var lines []string
+	skipBlock := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			skipBlock = false
+			continue
+		case skipBlock:
+			continue
+		case strings.HasPrefix(line, "WEBVTT"), strings.HasPrefix(line, "NOTE"), line == "STYLE", line == "REGION":
+			skipBlock = true
+			continue
+		case strings.Contains(line, "-->"), isDigits(line):
+			continue
+		}
+		text := strings.TrimSpace(htmlToText(line)) // <v Speaker>, <i>, entities
+		if text != "" && (len(lines) == 0 || lines[len(lines)-1] != text) {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// TranscribeAudio downloads an episode's audio and runs Whisper on it. A
+// configured command gets the audio path as its last argument and prints
+// the transcript; otherwise the openai-whisper CLI is used if installed.
+// Without either it returns ErrNoWhisper before downloading anything.
+func TranscribeAudio(audioURL string, cfg PodcastConfig) (string, error) {
+	if !cfg.HasWhisper() {
+		return "", ErrNoWhisper
+	}
+	command := strings.Fields(cfg.Whisper)
+
+	dir, err := os.MkdirTemp("", "beats-podcast-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), whisperTimeout)
+	defer cancel()
+
+	audioPath := filepath.Join(dir, "episode"+audioExt(audioURL))
+	if err := download(ctx, audioURL, audioPath); err != nil {
+		return "", fmt.Errorf("downloading audio: %w", err)
+	}
+
+	if len(command) > 0 {
+		out, err := exec.CommandContext(ctx, command[0], append(command[1:], audioPath)...).Output()
+		if err != nil {
+			return "", fmt.Errorf("whisper command failed: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	model := cfg.WhisperModel
+	if model == "" {
+		model = DefaultConfig().Podcast.WhisperModel
+	}
+	cmd := exec.CommandContext(ctx, "whisper", audioPath,
+		"--model", model, "--output_format", "txt", "--output_dir", dir, "--verbose", "False")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("whisper failed: %w: %s", err, clip(string(out), 200))
+	}
+	text, err := os.ReadFile(filepath.Join(dir, "episode.txt"))
+	if err != nil {
+		return "", fmt.Errorf("whisper wrote no transcript: %w", err)
+	}
+	return strings.Join(strings.Fields(string(text)), " "), nil
+}
+
+// HasWhisper reports whether a Whisper command is configured or installed.
+func (cfg PodcastConfig) HasWhisper() bool {
+	if strings.TrimSpace(cfg.Whisper) != "" {
+		return true
+	}
+	_, err := exec.LookPath("whisper")
+	return err == nil
+}
+
+func download(ctx context.Context, src, dest string) error {
+	req, err := newRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return err
+	}
+	// Episodes are large; the shared client's timeout is for pages
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fetchLimited(src string, limit int64) ([]byte, error) {
+	resp, err := httpClient.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", src, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+func isFeed(contentType string, data []byte) bool {
+	if strings.Contains(contentType, "rss") {
+		return true
+	}
+	head := strings.ToLower(string(data[:min(len(data), 1024)]))
+	return strings.Contains(head, "<rss")
+}
+
+func isAudioURL(ref string) bool {
+	switch audioExt(ref) {
+	case ".mp3", ".m4a", ".aac", ".ogg", ".opus", ".wav", ".flac":
+		return true
+	}
+	return false
+}
+
+func audioExt(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(filepath.Ext(u.Path))
+}
+
+// transcriptRank orders transcript formats by how cleanly they convert.
+func transcriptRank(contentType, ref string) int {
+	ct := strings.ToLower(contentType) + " " + strings.ToLower(filepath.Ext(ref))
+	switch {
+	case strings.Contains(ct, "plain") || strings.Contains(ct, ".txt"):
+		return 4
+	case strings.Contains(ct, "json"):
+		return 3
+	case strings.Contains(ct, "vtt") || strings.Contains(ct, "srt") || strings.Contains(ct, "subrip"):
+		return 2
+	case strings.Contains(ct, "html"):
+		return 1
+	}
+	return 0
+}
+
+func resolveURL(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+	b, err := url.Parse(base)
+	if err != nil || ref == "" {
+		return ref
+	}
+	u, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+func sameURL(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	norm := func(s string) string {
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+		return strings.TrimSuffix(strings.TrimPrefix(s, "www."), "/")
+	}
+	return norm(a) == norm(b)
+}
+
+func parsePodcastDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2 Jan 2006 15:04:05 -0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// formatPodcastDuration normalizes itunes:duration (seconds or [H:]MM:SS).
+func formatPodcastDuration(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || !isDigits(s) {
+		return s
+	}
+	secs := 0
+	for _, r := range s {
+		secs = secs*10 + int(r-'0')
+	}
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs%3600/60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package capture
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const podcastFeedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:podcast="https://podcastindex.org/namespace/1.0">
+<channel>
+  <title>Build Notes</title>
+  <itunes:author>Ada</itunes:author>
+  <item>
+    <title>Older episode</title>
+    <guid>ep-1</guid>
+    <pubDate>Mon, 01 Jan 2024 10:00:00 +0000</pubDate>
+    <enclosure url="https://cdn.example.com/ep1.mp3" type="audio/mpeg"/>
+  </item>
+  <item>
+    <title>Shipping   small</title>
+    <link>https://example.com/ep2</link>
+    <guid>ep-2</guid>
+    <pubDate>Mon, 08 Jan 2024 10:00:00 +0000</pubDate>
+    <description><![CDATA[<p>Why small releases <b>win</b>.</p>]]></description>
+    <itunes:duration>3725</itunes:duration>
+    <enclosure url="https://cdn.example.com/ep2.mp3" type="audio/mpeg"/>
+    <podcast:transcript url="/t/ep2.html" type="text/html"/>
+    <podcast:transcript url="/t/ep2.vtt" type="text/vtt"/>
+  </item>
+</channel>
+</rss>`
+
+func TestParsePodcastFeed(t *testing.T) {
+	episodes, err := ParsePodcastFeed([]byte(podcastFeedXML), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 2 {
+		t.Fatalf("got %d episodes, want 2", len(episodes))
+	}
+
+	ep := episodes[0] // Newest first even though the feed is oldest first
+	if ep.Title != "Shipping small" || ep.Show != "Build Notes" || ep.Author != "Ada" {
+		t.Errorf("episode = %q / %q / %q", ep.Title, ep.Show, ep.Author)
+	}
+	if ep.Description != "Why small releases win." || ep.Duration != "1:02:05" {
+		t.Errorf("Description = %q, Duration = %q", ep.Description, ep.Duration)
+	}
+	if ep.TranscriptURL != "https://example.com/t/ep2.vtt" {
+		t.Errorf("TranscriptURL = %q, want the VTT resolved against the feed", ep.TranscriptURL)
+	}
+	if ep.URL() != "https://example.com/ep2" || episodes[1].URL() != "https://cdn.example.com/ep1.mp3" {
+		t.Errorf("URL() = %q, %q", ep.URL(), episodes[1].URL())
+	}
+
+	for query, want := range map[string]string{
+		"":                                "ep-2",
+		"ep-1":                            "ep-1",
+		"https://cdn.example.com/ep1.mp3": "ep-1",
+		"http://example.com/ep2/":         "ep-2",
+		"older":                           "ep-1",
+	} {
+		if got := FindEpisode(episodes, query); got == nil || got.GUID != want {
+			t.Errorf("FindEpisode(%q) = %v, want %s", query, got, want)
+		}
+	}
+	if FindEpisode(episodes, "missing") != nil {
+		t.Error("FindEpisode(missing) found an episode")
+	}
+}
+
+func TestTranscriptText(t *testing.T) {
+	vtt := "WEBVTT\n\nNOTE generated\nby a tool\n\n1\n00:00:00.000 --> 00:00:02.000\n<v Ada>Hello and welcome.\n\n2\n00:00:02.000 --> 00:00:04.000\n<v Ada>Hello and welcome.\nToday: small releases.\n"
+	if got := TranscriptText([]byte(vtt), "text/vtt", "x.vtt"); got != "Hello and welcome. Today: small releases." {
+		t.Errorf("vtt = %q", got)
+	}
+
+	srt := "1\r\n00:00:00,000 --> 00:00:02,000\r\nFirst line\r\n\r\n2\r\n00:00:02,000 --> 00:00:04,000\r\nSecond line\r\n"
+	if got := TranscriptText([]byte(srt), "", "https://example.com/t.srt?sig=1"); got != "First line Second line" {
+		t.Errorf("srt = %q", got)
+	}
+
+	js := `{"version":"1.0.0","segments":[{"speaker":"Ada","body":"Hi."},{"speaker":"Ada","body":"Welcome."},{"speaker":"Bo","body":"Thanks."}]}`
+	if got := TranscriptText([]byte(js), "application/json", ""); got != "Ada: Hi. Welcome.\n\nBo: Thanks." {
+		t.Errorf("json = %q", got)
+	}
+}
+
+func TestCapturePodcastEpisode(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.xml":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, strings.ReplaceAll(podcastFeedXML, "https://example.com/ep2", srv.URL+"/ep2"))
+		case "/ep2":
+			fmt.Fprintf(w, `<html><head><title>Shipping small</title><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head><body></body></html>`)
+		case "/t/ep2.vtt":
+			fmt.Fprint(w, "WEBVTT\n\n00:00.000 --> 00:02.000\nSmall releases win.\n")
+		case "/ep3.mp3":
+			w.Header().Set("Content-Type", "audio/mpeg")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ep, err := CapturePodcastEpisode(srv.URL+"/feed.xml#older", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.GUID != "ep-1" || ep.Transcript != "" {
+		t.Errorf("fragment selected %q (transcript %q), want ep-1 without transcript", ep.GUID, ep.Transcript)
+	}
+
+	// An episode page is matched against the feed it advertises
+	ep, err = CapturePodcastEpisode(srv.URL+"/ep2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.GUID != "ep-2" || ep.Transcript != "Small releases win." || ep.TranscriptSource != "feed" {
+		t.Errorf("page episode = %q, transcript %q (%s)", ep.GUID, ep.Transcript, ep.TranscriptSource)
+	}
+
+	ep, err = CapturePodcastEpisode(srv.URL+"/ep3.mp3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Title != "ep3" || ep.AudioURL != srv.URL+"/ep3.mp3" {
+		t.Errorf("audio episode = %q, %q", ep.Title, ep.AudioURL)
+	}
+
+	if _, err := CapturePodcastEpisode(srv.URL+"/feed.xml", "missing"); err == nil {
+		t.Error("unknown episode succeeded, want error")
+	}
+}
@@ -0,0 +1,444 @@
+package capture
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Article is the readable content of a web page.
+type Article struct {
+	Title       string
+	Description string
+	SiteName    string
+	Author      string
+	Text        string // Main content, paragraphs separated by blank lines
+
+	main *node // Main content element, for Markdown
+}
+
+// Excerpt returns the page description, or the opening of the main text.
+func (a *Article) Excerpt(max int) string {
+	if a.Description != "" {
+		return clip(a.Description, max)
+	}
+	var b strings.Builder
+	for _, p := range strings.Split(a.Text, "\n\n") {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(p)
+		if b.Len() >= max {
+			break
+		}
+	}
+	return clip(b.String(), max)
+}
+
+func clip(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= max {
+		return s
+	}
+	cut := strings.LastIndex(s[:max], " ")
+	if cut < max/2 {
+		cut = max
+	}
+	return s[:cut] + "..."
+}
+
+// node is an element or text node in a leniently parsed HTML tree.
+type node struct {
+	tag      string // Empty for text nodes
+	attrs    map[string]string
+	text     string
+	parent   *node
+	children []*node
+	score    float64
+	scored   bool
+}
+
+var (
+	voidTags = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+	// Elements whose content is never article text
+	skipTags = map[string]bool{
+		"script": true, "style": true, "noscript": true, "svg": true, "template": true, "iframe": true,
+	}
+	// Page chrome removed before scoring
+	chromeTags = map[string]bool{
+		"nav": true, "header": true, "footer": true, "aside": true, "form": true, "button": true, "select": true,
+	}
+	blockTags = map[string]bool{
+		"p": true, "div": true, "section": true, "article": true, "main": true, "blockquote": true, "pre": true,
+		"li": true, "ul": true, "ol": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"table": true, "tr": true, "td": true, "th": true, "figure": true, "figcaption": true, "br": true, "hr": true,
+		"dd": true, "dt": true, "dl": true,
+	}
+
+	positiveRe = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text|blog`)
+	negativeRe = regexp.MustCompile(`(?i)comment|footer|sidebar|side-bar|nav|menu|share|social|related|promo|advert|\bads?\b|cookie|banner|subscribe|newsletter|popup|modal|widget|breadcrumb|masthead`)
+	attrRe     = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+)
+
+// parseHTML builds a tree from HTML, tolerating unclosed and stray tags.
+func parseHTML(src string) *node {
+	root := &node{tag: "#root"}
+	cur := root
+	lower := strings.ToLower(src)
+
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			cur.appendText(src[i:])
+			break
+		}
+		if lt > 0 {
+			cur.appendText(src[i : i+lt])
+		}
+		i += lt
+
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			end := strings.Index(src[i+4:], "-->")
+			if end < 0 {
+				return root
+			}
+			i += 4 + end + 3
+			continue
+		case strings.HasPrefix(src[i:], "<!") || strings.HasPrefix(src[i:], "<?"):
+			end := strings.IndexByte(src[i:], '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+			continue
+		}
+
+		gt := strings.IndexByte(src[i:], '>')
+		if gt < 0 {
+			cur.appendText(src[i:])
+			break
+		}
+		raw := src[i+1 : i+gt]
+		i += gt + 1
+
+		if strings.HasPrefix(raw, "/") {
+			name := tagName(raw[1:])
+			for n := cur; n != root; n = n.parent {
+				if n.tag == name {
+					cur = n.parent
+					break
+				}
+			}
+			continue
+		}
+
+		name := tagName(raw)
+		if name == "" {
+			cur.appendText("<" + raw + ">")
+			continue
+		}
+		if skipTags[name] {
+			end := strings.Index(lower[i:], "</"+name)
+			if end < 0 {
+				return root
+			}
+			i += end
+			if gt := strings.IndexByte(src[i:], '>'); gt >= 0 {
+				i += gt + 1
+			}
+			continue
+		}
+
+		// Implied end tags: a new paragraph or list item closes the open one
+		if (name == "p" || blockTags[name] && name != "br") && cur.tag == "p" {
+			cur = cur.parent
+		}
+		if name == "li" && cur.tag == "li" {
+			cur = cur.parent
+		}
+
+		el := &node{tag: name, attrs: parseAttrs(raw[len(name):]), parent: cur}
+		cur.children = append(cur.children, el)
+		if !voidTags[name] && !strings.HasSuffix(raw, "/") {
+			cur = el
+		}
+	}
+	return root
+}
+
+func tagName(raw string) string {
+	end := strings.IndexFunc(raw, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '/' || r == '>'
+	})
+	if end < 0 {
+		end = len(raw)
+	}
+	name := strings.ToLower(raw[:end])
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == ':') {
+			return ""
+		}
+	}
+	return name
+}
+
+func parseAttrs(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range attrRe.FindAllStringSubmatch(s, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+	}
+	return attrs
+}
+
+func (n *node) appendText(s string) {
+	if s == "" {
+		return
+	}
+	n.children = append(n.children, &node{text: html.UnescapeString(s), parent: n})
+}
+
+// innerText returns the node's text with whitespace collapsed.
+func (n *node) innerText() string {
+	var b strings.Builder
+	n.walkText(&b)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func (n *node) walkText(b *strings.Builder) {
+	if n.tag == "" {
+		b.WriteString(n.text)
+		return
+	}
+	for _, c := range n.children {
+		c.walkText(b)
+		if c.tag != "" && blockTags[c.tag] {
+			b.WriteString(" ")
+		}
+	}
+}
+
+// linkDensity is the share of a node's text that sits inside links.
+func (n *node) linkDensity() float64 {
+	total := len(n.innerText())
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	n.each(func(c *node) bool {
+		if c.tag == "a" {
+			links += len(c.innerText())
+			return false
+		}
+		return true
+	})
+	return float64(links) / float64(total)
+}
+
+// each visits descendants depth-first; returning false skips a subtree.
+func (n *node) each(fn func(*node) bool) {
+	for _, c := range n.children {
+		if fn(c) {
+			c.each(fn)
+		}
+	}
+}
+
+func (n *node) find(tag string) *node {
+	var found *node
+	n.each(func(c *node) bool {
+		if found == nil && c.tag == tag {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+func (n *node) classWeight() float64 {
+	sig := n.attrs["class"] + " " + n.attrs["id"]
+	weight := 0.0
+	if negativeRe.MatchString(sig) {
+		weight -= 25
+	}
+	if positiveRe.MatchString(sig) {
+		weight += 25
+	}
+	return weight
+}
+
+func (n *node) initScore() {
+	if n.scored {
+		return
+	}
+	n.scored = true
+	switch n.tag {
+	case "div", "article", "main", "section":
+		n.score = 5
+	case "pre", "td", "blockquote":
+		n.score = 3
+	case "ol", "ul", "li", "dl", "dd", "dt":
+		n.score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		n.score = -5
+	}
+	if n.tag == "article" || n.tag == "main" {
+		n.score += 10
+	}
+	n.score += n.classWeight()
+}
+
+// ExtractArticle pulls metadata and the main readable text from a page,
+// scoring containers by the paragraphs they hold, readability-style.
+func ExtractArticle(src string) *Article {
+	root := parseHTML(src)
+	a := &Article{}
+
+	meta := make(map[string]string)
+	root.each(func(n *node) bool {
+		switch n.tag {
+		case "meta":
+			key := strings.ToLower(n.attrs["property"])
+			if key == "" {
+				key = strings.ToLower(n.attrs["name"])
+			}
+			if key != "" && meta[key] == "" {
+				meta[key] = strings.TrimSpace(n.attrs["content"])
+			}
+		case "title":
+			if a.Title == "" {
+				a.Title = cleanTitle(n.innerText())
+			}
+		}
+		return true
+	})
+	if t := meta["og:title"]; t != "" {
+		a.Title = t
+	}
+	a.Description = firstNonEmpty(meta["og:description"], meta["description"], meta["twitter:description"])
+	a.SiteName = meta["og:site_name"]
+	a.Author = firstNonEmpty(meta["author"], meta["article:author"])
+
+	body := root.find("body")
+	if body == nil {
+		body = root
+	}
+	removeChrome(body)
+
+	var candidates []*node
+	body.each(func(n *node) bool {
+		if n.tag != "p" && n.tag != "pre" && n.tag != "td" && n.tag != "blockquote" {
+			return true
+		}
+		text := n.innerText()
+		if len(text) < 25 || n.parent == nil {
+			return true
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		for level, anc := 0, n.parent; anc != nil && level < 3 && anc.tag != "#root"; level, anc = level+1, anc.parent {
+			if !anc.scored {
+				anc.initScore()
+				candidates = append(candidates, anc)
+			}
+			switch level {
+			case 0:
+				anc.score += score
+			case 1:
+				anc.score += score / 2
+			default:
+				anc.score += score / 6
+			}
+		}
+		return true
+	})
+
+	var best *node
+	for _, c := range candidates {
+		c.score *= 1 - c.linkDensity()
+		if best == nil || c.score > best.score {
+			best = c
+		}
+	}
+	if best == nil {
+		best = body
+	}
+
+	a.Text = paragraphs(best)
+	a.main = best
+	return a
+}
+
+// removeChrome drops navigation, headers, footers and elements whose
+// class or id marks them as page furniture.
+func removeChrome(n *node) {
+	kept := n.children[:0]
+	for _, c := range n.children {
+		if c.tag != "" {
+			sig := c.attrs["class"] + " " + c.attrs["id"]
+			if chromeTags[c.tag] || (negativeRe.MatchString(sig) && !positiveRe.MatchString(sig) && c.tag != "body") {
+				continue
+			}
+			removeChrome(c)
+		}
+		kept = append(kept, c)
+	}
+	n.children = kept
+}
+
+// paragraphs renders a node's text with one blank line between blocks,
+// dropping blocks that are mostly links.
+func paragraphs(n *node) string {
+	var out []string
+	var cur strings.Builder
+	flush := func() {
+		if p := strings.Join(strings.Fields(cur.String()), " "); p != "" {
+			out = append(out, p)
+		}
+		cur.Reset()
+	}
+
+	var walk func(*node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			switch {
+			case c.tag == "":
+				cur.WriteString(c.text)
+			case blockTags[c.tag]:
+				flush()
+				if c.tag != "br" && c.tag != "hr" && len(c.innerText()) < 80 && c.linkDensity() > 0.5 {
+					continue
+				}
+				walk(c)
+				flush()
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	flush()
+	return strings.Join(out, "\n\n")
+}
+
+func cleanTitle(title string) string {
+	if idx := strings.Index(title, " | "); idx > 0 {
+		title = title[:idx]
+	}
+	if idx := strings.Index(title, " - "); idx > 0 && idx < len(title)-3 {
+		title = title[:idx]
+	}
+	return strings.TrimSpace(title)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
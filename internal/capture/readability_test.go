@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"strings"
+	"testing"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html><head>
+<title>Why checkout fails | Example Blog</title>
+<meta name="description" content="Surprise shipping costs drive cart abandonment.">
+<meta property="og:site_name" content="Example Blog">
+<meta name="author" content="Ada">
+<script>var tracking = "<p>not content</p>";</script>
+<style>p { color: red }</style>
+</head><body>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/about">About</a></li></ul></nav>
+<div class="sidebar"><p>Subscribe to our newsletter for weekly updates, tips, and more.</p></div>
+<div id="main-content" class="post">
+  <h1>Why checkout fails</h1>
+  <p>Users abandon checkout when shipping costs surprise them, especially on mobile, late in the flow.</p>
+  <p>Showing an estimate on the product page reduced abandonment by a fifth &amp; raised trust.
+  <p>The effect was strongest for first-time buyers, who had no prior sense of delivery pricing.</p>
+</div>
+<footer><p>Copyright 2026 Example Blog. All rights reserved, everywhere.</p></footer>
+</body></html>`
+
+func TestExtractArticle(t *testing.T) {
+	a := ExtractArticle(articlePage)
+
+	if a.Title != "Why checkout fails" {
+		t.Errorf("Title = %q", a.Title)
+	}
+	if a.Description != "Surprise shipping costs drive cart abandonment." {
+		t.Errorf("Description = %q", a.Description)
+	}
+	if a.SiteName != "Example Blog" || a.Author != "Ada" {
+		t.Errorf("SiteName = %q, Author = %q", a.SiteName, a.Author)
+	}
+
+	for _, want := range []string{"shipping costs surprise them", "by a fifth & raised trust", "first-time buyers"} {
+		if !strings.Contains(a.Text, want) {
+			t.Errorf("Text missing %q:\n%s", want, a.Text)
+		}
+	}
+	for _, unwanted := range []string{"not content", "newsletter", "Copyright", "Home"} {
+		if strings.Contains(a.Text, unwanted) {
+			t.Errorf("Text contains %q:\n%s", unwanted, a.Text)
+		}
+	}
+	if n := len(strings.Split(a.Text, "\n\n")); n != 4 {
+		t.Errorf("got %d paragraphs, want 4 (heading + 3):\n%s", n, a.Text)
+	}
+}
+
+func TestArticleExcerpt(t *testing.T) {
+	a := &Article{Text: "First paragraph here.\n\nSecond paragraph follows with more words."}
+	if got := a.Excerpt(30); got != "First paragraph here. Second..." {
+		t.Errorf("Excerpt = %q", got)
+	}
+	a.Description = "Short description."
+	if got := a.Excerpt(30); got != "Short description." {
+		t.Errorf("Excerpt with description = %q", got)
+	}
+}
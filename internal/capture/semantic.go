@@ -1,22 +1,29 @@
 package capture
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"math"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
+
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/llm"
+	"github.com/bierlingm/beats/internal/wald"
 )
 
 const purposeCacheFile = ".wald/purpose-embeddings.json"
 
+// DefaultInferenceMinScore is the similarity below which no context is inferred.
+const DefaultInferenceMinScore = 0.3
+
 type SemanticInference struct {
 	werkRoot string
 	cache    *PurposeEmbeddingsCache
-	ollama   *OllamaClient
+	ollama   *llm.Client
+	model    string
+	minScore float64
 }
 
 type PurposeEmbeddingsCache struct {
@@ -30,23 +37,21 @@ type InferredContext struct {
 	Confidence      float64
 }
 
-type OllamaClient struct {
-	baseURL string
-}
-
-type waldDirectory struct {
-	Path    string
-	Purpose string
-	State   string
-}
-
 func NewSemanticInference(werkRoot string) *SemanticInference {
+	cfg := config.Get()
 	return &SemanticInference{
 		werkRoot: werkRoot,
-		ollama:   &OllamaClient{baseURL: "http://localhost:11434"},
+		ollama:   llm.New(cfg.OllamaURL).WithTimeout(30 * time.Second),
+		model:    cfg.EmbedModel,
+		minScore: DefaultInferenceMinScore,
 	}
 }
 
+// SetMinScore overrides the similarity cutoff (context_inference_min in scoring.json).
+func (s *SemanticInference) SetMinScore(min float64) {
+	s.minScore = min
+}
+
 func (s *SemanticInference) InferContext(beatContent string) (*InferredContext, error) {
 	if !s.isOllamaAvailable() {
 		return nil, nil
@@ -72,7 +77,7 @@ func (s *SemanticInference) InferContext(beatContent string) (*InferredContext,
 		}
 	}
 
-	if bestDir == "" || bestScore < 0.3 {
+	if bestDir == "" || bestScore < s.minScore {
 		return nil, nil
 	}
 
@@ -86,44 +91,11 @@ func (s *SemanticInference) InferContext(beatContent string) (*InferredContext,
 func (s *SemanticInference) isOllamaAvailable() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
-
-	req, err := newRequestWithContext(ctx, "GET", s.ollama.baseURL+"/api/tags", nil)
-	if err != nil {
-		return false
-	}
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return false
-	}
-	defer func() { _ = resp.Body.Close() }()
-	return resp.StatusCode == 200
+	return s.ollama.Available(ctx)
 }
 
 func (s *SemanticInference) getEmbedding(text string) ([]float64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	body, _ := json.Marshal(map[string]string{"model": "nomic-embed-text", "prompt": text})
-	req, err := newRequestWithContext(ctx, "POST", s.ollama.baseURL+"/api/embeddings", body)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	var result struct {
-		Embedding []float64 `json:"embedding"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-	return result.Embedding, nil
+	return s.ollama.Embed(context.Background(), s.model, text)
 }
 
 func (s *SemanticInference) ensureCache() error {
@@ -141,8 +113,7 @@ func (s *SemanticInference) ensureCache() error {
 }
 
 func (s *SemanticInference) rebuildCache() error {
-	waldPath := filepath.Join(s.werkRoot, "WALD.yaml")
-	dirs, err := parseWALDDirectories(waldPath)
+	cfg, err := wald.Load(s.werkRoot)
 	if err != nil {
 		return err
 	}
@@ -152,7 +123,7 @@ func (s *SemanticInference) rebuildCache() error {
 		UpdatedAt:   time.Now().UTC().Format(time.RFC3339),
 	}
 
-	for _, dir := range dirs {
+	for _, dir := range cfg.Directories {
 		if dir.Purpose == "" || dir.State == "archived" {
 			continue
 		}
@@ -170,64 +141,6 @@ func (s *SemanticInference) rebuildCache() error {
 	return os.WriteFile(filepath.Join(s.werkRoot, purposeCacheFile), cacheData, 0644)
 }
 
-// parseWALDDirectories parses WALD.yaml for directories with path, purpose, state.
-func parseWALDDirectories(waldPath string) ([]waldDirectory, error) {
-	f, err := os.Open(waldPath)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var dirs []waldDirectory
-	var current *waldDirectory
-	inDirectories := false
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-
-		if strings.HasPrefix(trimmed, "directories:") {
-			inDirectories = true
-			continue
-		}
-
-		if !inDirectories {
-			continue
-		}
-
-		// New directory entry starts with "- path:"
-		if strings.HasPrefix(trimmed, "- path:") {
-			if current != nil {
-				dirs = append(dirs, *current)
-			}
-			current = &waldDirectory{
-				Path: strings.TrimSpace(strings.TrimPrefix(trimmed, "- path:")),
-			}
-			continue
-		}
-
-		if current == nil {
-			continue
-		}
-
-		if strings.HasPrefix(trimmed, "purpose:") {
-			purpose := strings.TrimPrefix(trimmed, "purpose:")
-			purpose = strings.TrimSpace(purpose)
-			purpose = strings.Trim(purpose, "\"")
-			current.Purpose = purpose
-		} else if strings.HasPrefix(trimmed, "state:") {
-			current.State = strings.TrimSpace(strings.TrimPrefix(trimmed, "state:"))
-		}
-	}
-
-	if current != nil {
-		dirs = append(dirs, *current)
-	}
-
-	return dirs, scanner.Err()
-}
-
 func cosineSimilarity(a, b []float64) float64 {
 	if len(a) != len(b) || len(a) == 0 {
 		return 0
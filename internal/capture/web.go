@@ -1,25 +1,34 @@
 package capture
 
 import (
-	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 )
 
-var titleRegex = regexp.MustCompile(`(?i)<title[^>]*>([^<]+)</title>`)
+// maxPageBytes bounds how much of a page is read for extraction.
+const maxPageBytes = 2 << 20
+
+// excerptLength is the size of the excerpt stored in the beat itself.
+const excerptLength = 400
 
 // WebCapture represents captured content from a URL
 type WebCapture struct {
-	URL     string
-	Title   string
-	Content string
-	Impetus string
+	URL         string
+	Title       string
+	Description string
+	SiteName    string
+	Author      string
+	Excerpt     string // Stored in the beat content
+	Text        string // Full readable text, stored as an attachment
+	Markdown    string // Main content as markdown, for snapshots
+	HTML        []byte // Raw page, for snapshots
+	Content     string
+	Impetus     string
 }
 
-// CaptureFromURL fetches a URL and extracts title
+// CaptureFromURL fetches a URL and extracts its title, metadata and main text
 func CaptureFromURL(url string, additionalContent string) (*WebCapture, error) {
 	client := &http.Client{
 		Timeout: 10 * time.Second,
@@ -31,49 +40,52 @@ func CaptureFromURL(url string, additionalContent string) (*WebCapture, error) {
 		return &WebCapture{
 			URL:     url,
 			Title:   "",
-			Content: buildContent(url, "", additionalContent),
+			Content: buildContent(url, "", "", additionalContent),
 			Impetus: inferImpetusFromURL(url),
 		}, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	body, _ := io.ReadAll(io.LimitReader(resp.Body, 100*1024))
-	title := extractTitle(string(body))
-
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
 	capture := &WebCapture{
 		URL:     url,
-		Title:   title,
-		Content: buildContent(url, title, additionalContent),
 		Impetus: inferImpetusFromURL(url),
 	}
+	if ct := resp.Header.Get("Content-Type"); ct == "" || strings.Contains(ct, "html") {
+		article := ExtractArticle(string(body))
+		capture.Title = article.Title
+		capture.Description = article.Description
+		capture.SiteName = article.SiteName
+		capture.Author = article.Author
+		capture.Text = article.Text
+		capture.Excerpt = article.Excerpt(excerptLength)
+		capture.HTML = body
+		base := url
+		if resp.Request != nil && resp.Request.URL != nil {
+			base = resp.Request.URL.String() // Relative links resolve against the final URL
+		}
+		capture.Markdown = article.Markdown(base)
+	}
+	capture.Content = buildContent(url, capture.Title, capture.Excerpt, additionalContent)
 
 	return capture, nil
 }
 
-func buildContent(url, title, additionalContent string) string {
-	if additionalContent != "" {
-		return fmt.Sprintf("%s\n\n%s", additionalContent, url)
-	}
-	if title != "" {
-		return fmt.Sprintf("%s\n\n%s", title, url)
-	}
-	return url
+// SetTitle sets a title known to the caller (e.g. a browser tab's title) for
+// pages that had none or could not be fetched, and rebuilds the content.
+func (w *WebCapture) SetTitle(title, additionalContent string) {
+	w.Title = title
+	w.Content = buildContent(w.URL, title, w.Excerpt, additionalContent)
 }
 
-func extractTitle(html string) string {
-	matches := titleRegex.FindStringSubmatch(html)
-	if len(matches) > 1 {
-		title := strings.TrimSpace(matches[1])
-		// Clean up common suffixes
-		if idx := strings.Index(title, " | "); idx > 0 {
-			title = title[:idx]
-		}
-		if idx := strings.Index(title, " - "); idx > 0 && idx < len(title)-3 {
-			title = title[:idx]
+func buildContent(url, title, excerpt, additionalContent string) string {
+	var parts []string
+	for _, p := range []string{additionalContent, title, excerpt, url} {
+		if p != "" {
+			parts = append(parts, p)
 		}
-		return title
 	}
-	return ""
+	return strings.Join(parts, "\n\n")
 }
 
 func inferImpetusFromURL(url string) string {
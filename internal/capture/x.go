@@ -0,0 +1,205 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var xStatusRegex = regexp.MustCompile(`(?:twitter\.com|x\.com)/(?:[A-Za-z0-9_]+|i/web)/status(?:es)?/(\d+)`)
+
+// XPost is one post in a thread.
+type XPost struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// XThread is an unrolled X/Twitter thread, oldest post first.
+type XThread struct {
+	URL    string  `json:"url"`
+	Author string  `json:"author"`
+	Handle string  `json:"handle"`
+	Posts  []XPost `json:"posts"`
+}
+
+// Content renders the thread as beat content.
+func (t *XThread) Content() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s", t.Handle)
+	if t.Author != "" && t.Author != t.Handle {
+		fmt.Fprintf(&b, " (%s)", t.Author)
+	}
+	if len(t.Posts) > 0 && !t.Posts[0].CreatedAt.IsZero() {
+		fmt.Fprintf(&b, ", %s", t.Posts[0].CreatedAt.Format("2006-01-02"))
+	}
+	b.WriteString("\n\n")
+	for i, p := range t.Posts {
+		if len(t.Posts) > 1 {
+			fmt.Fprintf(&b, "%d/ ", i+1)
+		}
+		b.WriteString(strings.TrimSpace(p.Text))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(t.URL)
+	return b.String()
+}
+
+// ParseXStatusID extracts the post ID from an X/Twitter status URL.
+func ParseXStatusID(rawURL string) (string, error) {
+	m := xStatusRegex.FindStringSubmatch(rawURL)
+	if m == nil {
+		return "", fmt.Errorf("not an X/Twitter status URL: %s", rawURL)
+	}
+	return m[1], nil
+}
+
+// CaptureXThread unrolls the thread ending at the given post. Without a
+// configured fetcher it walks the author's reply chain upward through the
+// public syndication endpoint, so pass the URL of the thread's last post.
+func CaptureXThread(postURL string, cfg XConfig) (*XThread, error) {
+	id, err := ParseXStatusID(postURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Fetcher != "" {
+		return fetchXThreadCommand(cfg.Fetcher, postURL)
+	}
+	if cfg.MaxPosts <= 0 {
+		cfg.MaxPosts = DefaultConfig().X.MaxPosts
+	}
+	if cfg.SyndicationURL == "" {
+		cfg.SyndicationURL = DefaultConfig().X.SyndicationURL
+	}
+
+	thread := &XThread{URL: postURL}
+	for id != "" && len(thread.Posts) < cfg.MaxPosts {
+		post, err := fetchSyndicatedPost(cfg.SyndicationURL, id)
+		if err != nil {
+			if len(thread.Posts) > 0 {
+				break // Keep what was unrolled; the parent may be deleted or protected
+			}
+			return nil, err
+		}
+		if thread.Handle == "" {
+			thread.Handle = post.User.ScreenName
+			thread.Author = post.User.Name
+		}
+		thread.Posts = append([]XPost{post.toPost()}, thread.Posts...)
+
+		// Only follow replies the author made to themselves
+		id = ""
+		if strings.EqualFold(post.InReplyToScreenName, thread.Handle) {
+			id = post.InReplyToStatusID
+		}
+	}
+	return thread, nil
+}
+
+// syndicatedPost is the subset of the syndication tweet-result used here.
+type syndicatedPost struct {
+	ID                  string `json:"id_str"`
+	Text                string `json:"text"`
+	CreatedAt           string `json:"created_at"`
+	InReplyToStatusID   string `json:"in_reply_to_status_id_str"`
+	InReplyToScreenName string `json:"in_reply_to_screen_name"`
+	User                struct {
+		Name       string `json:"name"`
+		ScreenName string `json:"screen_name"`
+	} `json:"user"`
+	Entities struct {
+		URLs []struct {
+			URL         string `json:"url"`
+			ExpandedURL string `json:"expanded_url"`
+		} `json:"urls"`
+	} `json:"entities"`
+}
+
+func (p *syndicatedPost) toPost() XPost {
+	text := p.Text
+	// Replace t.co short links with their targets so they are searchable
+	for _, u := range p.Entities.URLs {
+		if u.URL != "" && u.ExpandedURL != "" {
+			text = strings.ReplaceAll(text, u.URL, u.ExpandedURL)
+		}
+	}
+	created, _ := time.Parse(time.RFC3339, p.CreatedAt)
+	return XPost{ID: p.ID, Text: text, CreatedAt: created.UTC()}
+}
+
+func fetchSyndicatedPost(base, id string) (*syndicatedPost, error) {
+	q := url.Values{"id": {id}, "lang": {"en"}, "token": {syndicationToken(id)}}
+	resp, err := httpClient.Get(base + "?" + q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("fetching post %s: %w", id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching post %s: status %d", id, resp.StatusCode)
+	}
+
+	var post syndicatedPost
+	if err := json.NewDecoder(resp.Body).Decode(&post); err != nil {
+		return nil, fmt.Errorf("decoding post %s: %w", id, err)
+	}
+	if post.Text == "" {
+		return nil, fmt.Errorf("post %s unavailable", id)
+	}
+	if post.ID == "" {
+		post.ID = id
+	}
+	return &post, nil
+}
+
+// syndicationToken derives the token the embed widget sends with a post ID:
+// (id / 1e15 * π) in base 36, without zeros or the radix point.
+func syndicationToken(id string) string {
+	n, err := strconv.ParseFloat(id, 64)
+	if err != nil {
+		return "0"
+	}
+	x := n / 1e15 * math.Pi
+	const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	intPart := math.Floor(x)
+	frac := x - intPart
+	s := strconv.FormatInt(int64(intPart), 36)
+	for i := 0; i < 11 && frac > 0; i++ {
+		frac *= 36
+		d := math.Floor(frac)
+		s += string(digits[int(d)])
+		frac -= d
+	}
+	return strings.ReplaceAll(s, "0", "")
+}
+
+// fetchXThreadCommand runs a user-configured fetcher that prints XThread JSON.
+func fetchXThreadCommand(command, postURL string) (*XThread, error) {
+	fields := strings.Fields(command)
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, fields[0], append(fields[1:], postURL)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("x fetcher failed: %w", err)
+	}
+	var thread XThread
+	if err := json.Unmarshal(out, &thread); err != nil {
+		return nil, fmt.Errorf("x fetcher output: %w", err)
+	}
+	if len(thread.Posts) == 0 {
+		return nil, fmt.Errorf("x fetcher returned no posts")
+	}
+	if thread.URL == "" {
+		thread.URL = postURL
+	}
+	return &thread, nil
+}
@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseXStatusID(t *testing.T) {
+	for _, u := range []string{
+		"https://x.com/jack/status/20",
+		"https://twitter.com/jack/status/20?s=20",
+		"https://mobile.twitter.com/i/web/status/20",
+	} {
+		if id, err := ParseXStatusID(u); err != nil || id != "20" {
+			t.Errorf("ParseXStatusID(%q) = %q, %v", u, id, err)
+		}
+	}
+	if _, err := ParseXStatusID("https://x.com/jack"); err == nil {
+		t.Error("ParseXStatusID(profile URL) succeeded, want error")
+	}
+}
+
+func TestCaptureXThread(t *testing.T) {
+	posts := map[string]map[string]interface{}{
+		"1": {"id_str": "1", "text": "Thread on checkout design", "created_at": "2026-01-15T10:00:00.000Z",
+			"in_reply_to_status_id_str": "0", "in_reply_to_screen_name": "someone_else"},
+		"2": {"id_str": "2", "text": "Shipping surprises kill conversion https://t.co/abc", "created_at": "2026-01-15T10:01:00.000Z",
+			"in_reply_to_status_id_str": "1", "in_reply_to_screen_name": "ada",
+			"entities": map[string]interface{}{"urls": []map[string]string{{"url": "https://t.co/abc", "expanded_url": "https://example.com/study"}}}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := posts[r.URL.Query().Get("id")]
+		if !ok || r.URL.Query().Get("token") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		p["user"] = map[string]string{"name": "Ada L", "screen_name": "ada"}
+		_ = json.NewEncoder(w).Encode(p)
+	}))
+	defer srv.Close()
+
+	thread, err := CaptureXThread("https://x.com/ada/status/2", XConfig{SyndicationURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thread.Handle != "ada" || thread.Author != "Ada L" {
+		t.Errorf("author = %q (@%s)", thread.Author, thread.Handle)
+	}
+	// Post 1 replies to someone else, so the chain stops there
+	if len(thread.Posts) != 2 || thread.Posts[0].ID != "1" || thread.Posts[1].ID != "2" {
+		t.Fatalf("posts = %+v, want [1 2]", thread.Posts)
+	}
+
+	content := thread.Content()
+	for _, want := range []string{"@ada (Ada L), 2026-01-15", "1/ Thread on checkout design", "2/ Shipping", "https://example.com/study"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// maxAnnotation bounds an annotation; longer thoughts belong in a new beat.
+const maxAnnotation = 1000
+
+// annotate appends a note to a beat, leaving its content as captured.
+// With expected set, the beat must not have changed since it was read.
+func annotate(s *store.JSONLStore, id, note, author string, expected *time.Time) (*beat.Beat, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, fmt.Errorf("annotation is empty")
+	}
+	if len(note) > maxAnnotation {
+		return nil, fmt.Errorf("annotation is %d bytes, over %d; capture a new beat instead", len(note), maxAnnotation)
+	}
+	a := beat.Annotation{Note: note, Author: author, CreatedAt: clock.Now().UTC()}
+	return updateBeat(s, id, expected, func(b *beat.Beat) error {
+		b.Annotations = append(b.Annotations, a)
+		return nil
+	})
+}
+
+// Annotate adds a note to a beat without changing its content.
+func (c *HumanCLI) Annotate(id, note, author string) error {
+	if author == "" {
+		author = linkAuthor()
+	}
+	b, err := annotate(c.store, id, note, author, nil)
+	if err != nil {
+		return fmt.Errorf("failed to annotate beat: %w", err)
+	}
+	fmt.Printf("Annotated %s (%d annotation(s))\n", b.ID, len(b.Annotations))
+	return nil
+}
+
+// AnnotateInput is the input for --robot-annotate.
+type AnnotateInput struct {
+	BeatID string `json:"beat_id"`
+	Note   string `json:"note"`
+	Author string `json:"author,omitempty"` // Agent or person annotating (default "robot")
+
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"` // Fail with a conflict if the beat changed since
+}
+
+// Annotate adds a note to a beat and returns the updated beat.
+func (c *RobotCLI) Annotate(input io.Reader) error {
+	var in AnnotateInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
+		return outputError("invalid input JSON", err)
+	}
+	if in.BeatID == "" {
+		return outputError("beat_id is required", nil)
+	}
+	b, err := annotate(c.store, in.BeatID, in.Note, robotAuthor(in.Author), in.ExpectedUpdatedAt)
+	if err != nil {
+		return outputError("failed to annotate beat", err)
+	}
+	return outputJSON(b)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestAnnotate(t *testing.T) {
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+	clock.Set(now)
+	defer clock.Reset()
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := now.AddDate(0, 0, -3)
+	if err := s.Append(&beat.Beat{ID: "beat-20260317-001", CreatedAt: created, UpdatedAt: created, Impetus: beat.Impetus{Label: "Manual entry"}, Content: "Pricing tiers confuse people"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := annotate(s, "beat-20260317-001", "this turned out wrong", "ada", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := annotate(s, "beat-20260317-001", "  second look  ", "grace", nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Get("beat-20260317-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Content != "Pricing tiers confuse people" || !b.CreatedAt.Equal(created) {
+		t.Errorf("annotating changed the beat: %+v", b)
+	}
+	if len(b.Annotations) != 2 {
+		t.Fatalf("annotations = %+v; want 2", b.Annotations)
+	}
+	if a := b.Annotations[1]; a.Note != "second look" || a.Author != "grace" || !a.CreatedAt.Equal(now) {
+		t.Errorf("annotation = %+v", a)
+	}
+
+	if _, err := annotate(s, "beat-20260317-001", " ", "ada", nil); err == nil {
+		t.Error("empty annotation accepted")
+	}
+	if _, err := annotate(s, "beat-20260317-001", strings.Repeat("x", maxAnnotation+1), "ada", nil); err == nil {
+		t.Error("overlong annotation accepted")
+	}
+}
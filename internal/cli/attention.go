@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/topics"
+)
+
+// Defaults for the session-priming views.
+const (
+	DefaultAttentionWindow = 72 * time.Hour
+	DefaultRipeAge         = 14 * 24 * time.Hour
+	DefaultRipeMax         = 10
+	minRipeScore           = 2 // A single weak signal is not enough
+	maxActivations         = 8
+)
+
+// Attention modes: embedding clusters when embeddings exist, otherwise
+// counts of the entities the beats mention.
+const (
+	AttentionTopics   = "topics"
+	AttentionEntities = "entities"
+)
+
+// AttentionOptions configures Attention.
+type AttentionOptions struct {
+	Window time.Duration // Length of the current (and previous) window
+	Now    time.Time     // End of the current window (default: now)
+}
+
+// Activation is a topic active in the current window.
+type Activation struct {
+	Topic    string   `json:"topic"`
+	Keywords []string `json:"keywords,omitempty"`
+	Beats    int      `json:"beats"`    // In the current window
+	Previous int      `json:"previous"` // In the previous window
+	Trend    string   `json:"trend"`    // emerging, steady or fading
+	BeatIDs  []string `json:"beat_ids,omitempty"`
+}
+
+// AttentionReport lists what recent beats keep coming back to.
+type AttentionReport struct {
+	Window      string       `json:"window"`
+	Mode        string       `json:"mode"`
+	Activations []Activation `json:"activations"`
+}
+
+// Attention reports the topics active in the last window, compared with the
+// window before it. Topics come from embedding clusters when the store has
+// embeddings and from entity mentions otherwise.
+func Attention(s *store.JSONLStore, opts AttentionOptions) (*AttentionReport, error) {
+	if opts.Window <= 0 {
+		opts.Window = DefaultAttentionWindow
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	out := &AttentionReport{Window: topics.FormatWindow(opts.Window), Activations: []Activation{}}
+
+	if report, err := topics.ForStore(s, topics.Options{Window: opts.Window, Now: opts.Now}); err == nil {
+		out.Mode = AttentionTopics
+		for _, t := range report.Topics {
+			if t.Current == 0 {
+				continue
+			}
+			a := Activation{Topic: t.Label, Keywords: t.Keywords, Beats: t.Current, Previous: t.Previous, Trend: t.Trend}
+			for _, r := range t.Representatives {
+				a.BeatIDs = append(a.BeatIDs, r.ID)
+			}
+			out.Activations = append(out.Activations, a)
+		}
+		return out, nil
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	out.Mode = AttentionEntities
+	out.Activations = entityActivations(beats, opts.Now.Add(-opts.Window), opts.Now, opts.Window)
+	return out, nil
+}
+
+// entityActivations counts the beats mentioning each entity in the window
+// [start, end) and in the window of the same length before it.
+func entityActivations(beats []beat.Beat, start, end time.Time, window time.Duration) []Activation {
+	prevStart := start.Add(-window)
+	byKey := make(map[string]*Activation)
+	for _, b := range beats {
+		current := !b.CreatedAt.Before(start) && b.CreatedAt.Before(end)
+		previous := !b.CreatedAt.Before(prevStart) && b.CreatedAt.Before(start)
+		if !current && !previous {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, e := range b.Entities {
+			key := strings.ToLower(strings.TrimSpace(e.Label))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			a := byKey[key]
+			if a == nil {
+				a = &Activation{Topic: e.Label}
+				byKey[key] = a
+			}
+			if current {
+				a.Beats++
+				a.BeatIDs = append(a.BeatIDs, b.ID)
+			} else {
+				a.Previous++
+			}
+		}
+	}
+
+	out := []Activation{}
+	for _, a := range byKey {
+		if a.Beats == 0 {
+			continue
+		}
+		a.Trend = topics.Trend(a.Beats, a.Previous)
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Beats != out[j].Beats {
+			return out[i].Beats > out[j].Beats
+		}
+		return out[i].Topic < out[j].Topic
+	})
+	if len(out) > maxActivations {
+		out = out[:maxActivations]
+	}
+	return out
+}
+
+// RipeOptions configures Ripe.
+type RipeOptions struct {
+	MinAge time.Duration // Only beats at least this old
+	Max    int           // Beats returned
+	Now    time.Time
+}
+
+// RipeBeat is an older beat that was never linked to a bead.
+type RipeBeat struct {
+	ID      string   `json:"id"`
+	Preview string   `json:"preview"`
+	Impetus string   `json:"impetus"`
+	AgeDays int      `json:"age_days"`
+	Score   int      `json:"score"`
+	Signals []string `json:"signals"` // What made it score
+}
+
+// RipeReport lists beats ready to be turned into work.
+type RipeReport struct {
+	MinAgeDays int        `json:"min_age_days"`
+	Candidates int        `json:"candidates"` // Unlinked beats old enough
+	Beats      []RipeBeat `json:"beats"`
+}
+
+// Ripe finds old beats that were never linked to a bead but carry signal:
+// length, references, entities, later edits and coaching or session-insight
+// impetus. The strongest come first.
+func Ripe(s *store.JSONLStore, opts RipeOptions) (*RipeReport, error) {
+	if opts.MinAge <= 0 {
+		opts.MinAge = DefaultRipeAge
+	}
+	if opts.Max <= 0 {
+		opts.Max = DefaultRipeMax
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	out := &RipeReport{MinAgeDays: int(opts.MinAge.Hours() / 24), Beats: []RipeBeat{}}
+	cutoff := opts.Now.Add(-opts.MinAge)
+	for _, b := range beats {
+		if len(b.LinkedBeads) > 0 || b.CreatedAt.After(cutoff) {
+			continue
+		}
+		out.Candidates++
+		score, signals := ripeSignals(b)
+		if score < minRipeScore {
+			continue
+		}
+		out.Beats = append(out.Beats, RipeBeat{
+			ID:      b.ID,
+			Preview: truncate(b.Content, 100),
+			Impetus: b.Impetus.Label,
+			AgeDays: int(opts.Now.Sub(b.CreatedAt).Hours() / 24),
+			Score:   score,
+			Signals: signals,
+		})
+	}
+	sort.SliceStable(out.Beats, func(i, j int) bool {
+		if out.Beats[i].Score != out.Beats[j].Score {
+			return out.Beats[i].Score > out.Beats[j].Score
+		}
+		return out.Beats[i].AgeDays > out.Beats[j].AgeDays
+	})
+	if len(out.Beats) > opts.Max {
+		out.Beats = out.Beats[:opts.Max]
+	}
+	return out, nil
+}
+
+// ripeSignals scores how much substance a beat has.
+func ripeSignals(b beat.Beat) (int, []string) {
+	score := 0
+	var signals []string
+	if words := len(strings.Fields(b.Content)); words >= 40 {
+		score += 2
+		signals = append(signals, fmt.Sprintf("%d words", words))
+	} else if words >= 15 {
+		score++
+		signals = append(signals, fmt.Sprintf("%d words", words))
+	}
+	if n := len(b.References); n > 0 {
+		score += min(n, 2)
+		signals = append(signals, fmt.Sprintf("%d reference(s)", n))
+	}
+	if n := len(b.Entities); n > 0 {
+		score += min(n, 2)
+		signals = append(signals, fmt.Sprintf("%d entity mention(s)", n))
+	}
+	if b.UpdatedAt.Sub(b.CreatedAt) > time.Hour {
+		score++
+		signals = append(signals, "revisited")
+	}
+	label := strings.ToLower(b.Impetus.Label)
+	if strings.Contains(label, "coaching") || strings.Contains(label, "insight") {
+		score += 2
+		signals = append(signals, b.Impetus.Label)
+	}
+	return score, signals
+}
+
+// Orientation summarizes where attention is and where it is heading.
+type Orientation struct {
+	Direction        string   `json:"direction"`
+	Summary          string   `json:"summary"`
+	Window           string   `json:"window"`
+	RecentBeats      int      `json:"recent_beats"`
+	PreviousBeats    int      `json:"previous_beats"`
+	Emerging         []string `json:"emerging"`
+	Fading           []string `json:"fading"`
+	TopDirectories   []string `json:"top_directories"` // WALD directories of recent beats, busiest first
+	RipeCount        int      `json:"ripe_count"`
+	PendingSynthesis bool     `json:"pending_synthesis"`
+
+	// What the direction was derived from
+	attention *AttentionReport
+	ripe      *RipeReport
+}
+
+// Orient combines attention, ripe beats and the synthesis state into a
+// short statement of direction for the start of a session.
+func Orient(s *store.JSONLStore, opts AttentionOptions) (*Orientation, error) {
+	if opts.Window <= 0 {
+		opts.Window = DefaultAttentionWindow
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	attention, err := Attention(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	ripe, err := Ripe(s, RipeOptions{Now: opts.Now})
+	if err != nil {
+		return nil, err
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Orientation{
+		Window:         attention.Window,
+		Emerging:       []string{},
+		Fading:         []string{},
+		TopDirectories: []string{},
+		RipeCount:      len(ripe.Beats),
+		attention:      attention,
+		ripe:           ripe,
+	}
+	start := opts.Now.Add(-opts.Window)
+	dirs := make(map[string]int)
+	for _, b := range beats {
+		switch {
+		case !b.CreatedAt.Before(start) && !b.CreatedAt.After(opts.Now):
+			o.RecentBeats++
+			if b.Context != nil && b.Context.WALDDirectory != "" {
+				dirs[b.Context.WALDDirectory]++
+			}
+		case !b.CreatedAt.Before(start.Add(-opts.Window)) && b.CreatedAt.Before(start):
+			o.PreviousBeats++
+		}
+	}
+	for dir := range dirs {
+		o.TopDirectories = append(o.TopDirectories, dir)
+	}
+	sort.Slice(o.TopDirectories, func(i, j int) bool {
+		a, b := o.TopDirectories[i], o.TopDirectories[j]
+		if dirs[a] != dirs[b] {
+			return dirs[a] > dirs[b]
+		}
+		return a < b
+	})
+	if len(o.TopDirectories) > 3 {
+		o.TopDirectories = o.TopDirectories[:3]
+	}
+	for _, a := range attention.Activations {
+		switch a.Trend {
+		case topics.Emerging:
+			o.Emerging = append(o.Emerging, a.Topic)
+		case topics.Fading:
+			o.Fading = append(o.Fading, a.Topic)
+		}
+	}
+	if _, err := hooks.GetSynthesisRequest(s.Dir()); err == nil {
+		o.PendingSynthesis = true
+	}
+
+	o.Direction = orientationDirection(o, attention.Activations)
+	o.Summary = orientationSummary(o)
+	return o, nil
+}
+
+func orientationDirection(o *Orientation, activations []Activation) string {
+	switch {
+	case o.RecentBeats == 0:
+		return fmt.Sprintf("Quiet: no beats in the last %s.", o.Window)
+	case len(o.Emerging) > 0:
+		d := "Attention is moving toward " + joinTopics(o.Emerging, 3)
+		if len(o.Fading) > 0 {
+			d += ", away from " + joinTopics(o.Fading, 2)
+		}
+		return d + "."
+	case len(activations) > 0:
+		return "Attention stays on " + joinTopics([]string{activations[0].Topic}, 1) + "."
+	default:
+		return fmt.Sprintf("%d beat(s) in the last %s without a common thread.", o.RecentBeats, o.Window)
+	}
+}
+
+func orientationSummary(o *Orientation) string {
+	parts := []string{fmt.Sprintf("%d beat(s) in the last %s (%d the %s before)", o.RecentBeats, o.Window, o.PreviousBeats, o.Window)}
+	if len(o.TopDirectories) > 0 {
+		parts = append(parts, "mostly in "+strings.Join(o.TopDirectories, ", "))
+	}
+	if o.RipeCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d ripe beat(s) waiting for a bead", o.RipeCount))
+	}
+	if o.PendingSynthesis {
+		parts = append(parts, "a synthesis is pending")
+	}
+	return strings.Join(parts, "; ") + "."
+}
+
+func joinTopics(names []string, max int) string {
+	if len(names) > max {
+		names = names[:max]
+	}
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "\"" + n + "\""
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Attention outputs the topics active in the last 72 hours.
+func (c *RobotCLI) Attention() error {
+	report, err := Attention(c.store, AttentionOptions{})
+	if err != nil {
+		return outputError("failed to compute attention", err)
+	}
+	return outputJSON(report)
+}
+
+// Ripe outputs old unlinked beats with the most signal.
+func (c *RobotCLI) Ripe() error {
+	report, err := Ripe(c.store, RipeOptions{})
+	if err != nil {
+		return outputError("failed to find ripe beats", err)
+	}
+	return outputJSON(report)
+}
+
+// Orientation outputs the direction of recent attention.
+func (c *RobotCLI) Orientation() error {
+	o, err := Orient(c.store, AttentionOptions{})
+	if err != nil {
+		return outputError("failed to compute orientation", err)
+	}
+	return outputJSON(o)
+}
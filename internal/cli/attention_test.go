@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/topics"
+)
+
+func TestAttentionRipeOrientation(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	mk := func(id string, hoursAgo int, content string, entities ...string) *beat.Beat {
+		at := now.Add(-time.Duration(hoursAgo) * time.Hour)
+		b := &beat.Beat{ID: id, CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Manual entry"}, Content: content}
+		for _, e := range entities {
+			b.Entities = append(b.Entities, beat.Entity{Label: e, Category: "concept"})
+		}
+		return b
+	}
+	long := strings.Repeat("word ", 45)
+	old := mk("beat-20260101-001", 24*78, long, "identity", "practice")
+	old.Impetus.Label = "Coaching insight"
+	linked := mk("beat-20260101-002", 24*78, long, "identity")
+	linked.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1"})
+	thin := mk("beat-20260102-001", 24*77, "short note")
+	young := mk("beat-20260310-001", 24*10, long, "pricing")
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	beats := []*beat.Beat{old, linked, thin, young,
+		mk("beat-20260316-001", 100, "pricing call", "Pricing"),
+		mk("beat-20260319-001", 20, "pricing again", "pricing"),
+		mk("beat-20260319-002", 10, "onboarding", "onboarding"),
+		mk("beat-20260320-001", 2, "onboarding flow", "Onboarding"),
+	}
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	ripe, err := Ripe(s, RipeOptions{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ripe.Candidates != 2 || len(ripe.Beats) != 1 || ripe.Beats[0].ID != old.ID {
+		t.Errorf("Ripe() = %+v; want only the unlinked coaching beat (the thin one scores too low)", ripe)
+	}
+
+	attention, err := Attention(s, AttentionOptions{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attention.Mode != AttentionEntities || len(attention.Activations) != 2 {
+		t.Fatalf("Attention() = %+v; want pricing and onboarding from entities", attention)
+	}
+	trends := map[string]string{}
+	for _, a := range attention.Activations {
+		trends[strings.ToLower(a.Topic)] = a.Trend
+	}
+	// Onboarding is new; pricing had one beat in the previous window too
+	if trends["onboarding"] != topics.Emerging || trends["pricing"] != topics.Steady {
+		t.Errorf("trends = %v", trends)
+	}
+
+	o, err := Orient(s, AttentionOptions{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.RecentBeats != 3 || o.PreviousBeats != 1 || o.RipeCount != 1 {
+		t.Errorf("Orient() counts = %d recent, %d previous, %d ripe", o.RecentBeats, o.PreviousBeats, o.RipeCount)
+	}
+	if !strings.Contains(strings.ToLower(o.Direction), `toward "onboarding"`) {
+		t.Errorf("Direction = %q", o.Direction)
+	}
+
+	p, err := Prime(s, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TotalBeats != len(beats) || len(p.Recent) != primeRecent || p.Recent[0].ID != "beat-20260320-001" {
+		t.Errorf("Prime() = %d beats, recent %+v; want the %d newest first", p.TotalBeats, p.Recent, primeRecent)
+	}
+	md := p.Markdown()
+	for _, section := range []string{"## Activating Topics (3d)", "## Attention Direction", "## Ripe Beats", "## Recent Beats", "## Quick Commands"} {
+		if !strings.Contains(md, section) {
+			t.Errorf("Markdown() is missing %q:\n%s", section, md)
+		}
+	}
+}
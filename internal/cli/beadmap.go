@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// MapOptions contains options for the map command.
+type MapOptions struct {
+	Format   string // mermaid (default) or dot
+	MaxBeats int    // Seed beats drawn per bead (default 5)
+	Output   string // File to write (default stdout)
+}
+
+// mapBead is a bead on the map with the seed beats drawn for it.
+type mapBead struct {
+	bead  beads.Bead
+	seeds []beat.Beat
+	more  int // Seed beats beyond MaxBeats
+}
+
+// mapGroup is an epic and the beads under it. The epic itself is a member
+// when beats seed it directly.
+type mapGroup struct {
+	epic    beads.Bead
+	members []*mapBead
+}
+
+// Map draws the beads that beats seeded, grouped by epic, with their seed
+// beats attached, as a Mermaid flowchart or a Graphviz digraph.
+func (c *HumanCLI) Map(opts MapOptions) error {
+	if opts.Format == "" {
+		opts.Format = "mermaid"
+	}
+	if opts.Format != "mermaid" && opts.Format != "dot" {
+		return fmt.Errorf("invalid --format %q (use mermaid or dot)", opts.Format)
+	}
+	if opts.MaxBeats <= 0 {
+		opts.MaxBeats = 5
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return err
+	}
+	list, _, err := beads.Inventory(c.store.Dir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not list beads: %v\n", err)
+	}
+	groups, loose := mapGroups(all, list, opts.MaxBeats)
+	if len(groups)+len(loose) == 0 {
+		fmt.Fprintln(os.Stderr, "No beats are linked to beads as seeds yet.")
+	}
+
+	var out string
+	if opts.Format == "dot" {
+		out = renderDot(groups, loose)
+	} else {
+		out = renderMermaid(groups, loose)
+	}
+	if opts.Output == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(opts.Output, []byte(out), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote map to %s\n", opts.Output)
+	return nil
+}
+
+// mapGroups collects the beads with seed beats and groups them under their
+// epic: the bead's parent, or the bead itself when it is an epic. Beads
+// without an epic are returned separately.
+func mapGroups(all []beat.Beat, list []beads.Bead, maxBeats int) ([]*mapGroup, []*mapBead) {
+	known := make(map[string]beads.Bead, len(list))
+	for _, b := range list {
+		known[b.ID] = b
+	}
+	bead := func(id string) beads.Bead {
+		if b, ok := known[id]; ok {
+			return b
+		}
+		return beads.Bead{ID: id}
+	}
+
+	seeded := make(map[string]*mapBead)
+	sorted := append([]beat.Beat(nil), all...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+	for _, b := range sorted {
+		for _, link := range b.LinkedBeads {
+			if link.Relation != beat.RelationSeed {
+				continue
+			}
+			mb := seeded[link.BeadID]
+			if mb == nil {
+				mb = &mapBead{bead: bead(link.BeadID)}
+				seeded[link.BeadID] = mb
+			}
+			if len(mb.seeds) < maxBeats {
+				mb.seeds = append(mb.seeds, b)
+			} else {
+				mb.more++
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(seeded))
+	for id := range seeded {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	byEpic := make(map[string]*mapGroup)
+	var groups []*mapGroup
+	var loose []*mapBead
+	for _, id := range ids {
+		mb := seeded[id]
+		epicID := mb.bead.Parent
+		if epicID == "" && mb.bead.Type == "epic" {
+			epicID = id
+		}
+		if epicID == "" {
+			loose = append(loose, mb)
+			continue
+		}
+		g := byEpic[epicID]
+		if g == nil {
+			g = &mapGroup{epic: bead(epicID)}
+			byEpic[epicID] = g
+			groups = append(groups, g)
+		}
+		g.members = append(g.members, mb)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].epic.ID < groups[j].epic.ID })
+	return groups, loose
+}
+
+// mapNodeID turns an ID into a node identifier both formats accept.
+func mapNodeID(prefix, id string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, r := range id {
+		if r < 128 && (r == '_' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+func beadMapLabel(b beads.Bead) string {
+	if b.Title == "" {
+		return b.ID
+	}
+	return b.ID + ": " + truncate(b.Title, 50)
+}
+
+func beatMapLabel(b beat.Beat) string {
+	return b.ID + ": " + truncate(b.Content, 40)
+}
+
+func moreLabel(n int) string {
+	return fmt.Sprintf("+%d more", n)
+}
+
+// mermaidText escapes a label for a quoted Mermaid node.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+func renderMermaid(groups []*mapGroup, loose []*mapBead) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	var edges []string
+	drawBead := func(mb *mapBead, indent string, isEpic bool) {
+		node := mapNodeID("bead_", mb.bead.ID)
+		if isEpic {
+			node = mapNodeID("epic_", mb.bead.ID)
+		} else {
+			fmt.Fprintf(&sb, "%s%s[\"%s\"]\n", indent, node, mermaidText(beadMapLabel(mb.bead)))
+		}
+		for _, b := range mb.seeds {
+			edges = append(edges, fmt.Sprintf("  %s([\"%s\"]) --> %s", mapNodeID("", b.ID), mermaidText(beatMapLabel(b)), node))
+		}
+		if mb.more > 0 {
+			edges = append(edges, fmt.Sprintf("  %s([\"%s\"]) -.-> %s", node+"_more", moreLabel(mb.more), node))
+		}
+	}
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "  subgraph %s[\"%s\"]\n", mapNodeID("epic_", g.epic.ID), mermaidText(beadMapLabel(g.epic)))
+		for _, mb := range g.members {
+			if mb.bead.ID != g.epic.ID {
+				drawBead(mb, "    ", false)
+			}
+		}
+		sb.WriteString("  end\n")
+		for _, mb := range g.members {
+			if mb.bead.ID == g.epic.ID {
+				drawBead(mb, "", true)
+			}
+		}
+	}
+	for _, mb := range loose {
+		drawBead(mb, "  ", false)
+	}
+	for _, e := range edges {
+		sb.WriteString(e + "\n")
+	}
+	return sb.String()
+}
+
+// dotText escapes a label for a quoted Graphviz string.
+func dotText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+func renderDot(groups []*mapGroup, loose []*mapBead) string {
+	var sb strings.Builder
+	sb.WriteString("digraph beats {\n  rankdir=LR;\n  node [shape=box];\n")
+	var edges []string
+	drawBead := func(mb *mapBead, indent string) {
+		node := mapNodeID("bead_", mb.bead.ID)
+		fmt.Fprintf(&sb, "%s%s [label=\"%s\"];\n", indent, node, dotText(beadMapLabel(mb.bead)))
+		for _, b := range mb.seeds {
+			beatNode := mapNodeID("", b.ID)
+			edges = append(edges, fmt.Sprintf("  %s [label=\"%s\", shape=note];\n  %s -> %s;", beatNode, dotText(beatMapLabel(b)), beatNode, node))
+		}
+		if mb.more > 0 {
+			edges = append(edges, fmt.Sprintf("  %s_more [label=\"%s\", shape=plaintext];\n  %s_more -> %s [style=dashed];", node, moreLabel(mb.more), node, node))
+		}
+	}
+	for _, g := range groups {
+		fmt.Fprintf(&sb, "  subgraph %s {\n    label=\"%s\";\n    style=rounded;\n", mapNodeID("cluster_", g.epic.ID), dotText(beadMapLabel(g.epic)))
+		for _, mb := range g.members {
+			drawBead(mb, "    ")
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, mb := range loose {
+		drawBead(mb, "  ")
+	}
+	for _, e := range edges {
+		sb.WriteString(e + "\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,451 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// lookupBeads resolves bead IDs with the configured provider. It returns nil
+// without an error when no provider is configured or available. When the
+// provider cannot run, beads found in the cache come back with its error.
+func lookupBeads(beatsDir string, ids []string) (map[string]beads.Bead, beads.Provider, error) {
+	provider, err := beads.NewProvider(beatsDir)
+	if (err == nil && provider == nil) || len(ids) == 0 {
+		return nil, provider, err
+	}
+	var found map[string]beads.Bead
+	if err == nil {
+		found, err = provider.Lookup(ids)
+	}
+	if err != nil {
+		if cached, cerr := (beads.CacheProvider{BeatsDir: beatsDir}).Lookup(ids); cerr == nil && len(cached) > 0 {
+			return cached, provider, err
+		}
+	}
+	return found, provider, err
+}
+
+// beadLabel renders a bead ID with its title and status when known.
+func beadLabel(id string, known map[string]beads.Bead) string {
+	b, ok := known[id]
+	if !ok || b.Title == "" {
+		return id
+	}
+	if b.Status != "" {
+		return fmt.Sprintf("%s  %s (%s)", id, b.Title, b.Status)
+	}
+	return fmt.Sprintf("%s  %s", id, b.Title)
+}
+
+// unknownBeads lists ids the provider did not find.
+func unknownBeads(ids []string, found map[string]beads.Bead) []string {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// checkBeadsExist validates bead IDs before they are linked and returns the
+// beads found. Unknown IDs are an error unless force is set; a provider that
+// cannot run only warns, so linking works offline.
+func (c *HumanCLI) checkBeadsExist(ids []string, force bool) (map[string]beads.Bead, error) {
+	found, provider, err := lookupBeads(c.store.Dir(), ids)
+	if err != nil {
+		fmt.Printf("Warning: could not check bead IDs: %v\n", err)
+		return found, nil // Titles from the cache, if any
+	}
+	if provider == nil {
+		return nil, nil
+	}
+	if missing := unknownBeads(ids, found); len(missing) > 0 {
+		if !force {
+			return nil, fmt.Errorf("unknown bead(s) in %s: %s (--force links anyway)", provider.Name(), strings.Join(missing, ", "))
+		}
+		fmt.Printf("Warning: unknown bead(s) in %s: %s\n", provider.Name(), strings.Join(missing, ", "))
+	}
+	return found, nil
+}
+
+// canonicalBeads replaces IDs with the provider's canonical form (e.g. "#12"
+// becomes "owner/repo#12") and re-keys known to match.
+func canonicalBeads(ids []string, known map[string]beads.Bead) ([]string, map[string]beads.Bead) {
+	if len(known) == 0 {
+		return ids, known
+	}
+	out := make([]string, len(ids))
+	byID := make(map[string]beads.Bead, len(known))
+	for i, id := range ids {
+		out[i] = id
+		if b, ok := known[id]; ok {
+			if b.ID != "" {
+				out[i] = b.ID
+			}
+			byID[out[i]] = b
+		}
+	}
+	return out, byID
+}
+
+// commentOnBeads tells newly linked beads that narrative context exists,
+// when comment_back is set and the provider supports comments. Failures
+// only warn; the link itself has already been saved.
+func (c *HumanCLI) commentOnBeads(b *beat.Beat, ids []string, known map[string]beads.Bead) {
+	if len(ids) == 0 {
+		return
+	}
+	cfg, err := beads.LoadConfig(c.store.Dir())
+	if err != nil || !cfg.CommentBack {
+		return
+	}
+	provider, err := cfg.Open(c.store.Dir())
+	if err != nil || provider == nil {
+		return
+	}
+	commenter, ok := provider.(beads.Commenter)
+	if !ok {
+		return
+	}
+	body := beadComment(b)
+	for _, id := range ids {
+		if _, ok := known[id]; !ok {
+			continue // Unknown beads were linked with --force
+		}
+		if err := commenter.Comment(id, body); err != nil {
+			fmt.Printf("Warning: could not comment on %s: %v\n", id, err)
+			continue
+		}
+		fmt.Printf("Commented on %s\n", id)
+	}
+}
+
+// beadComment is the note posted on a bead linked to b.
+func beadComment(b *beat.Beat) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Narrative context available: %s", b.ID)
+	if b.Impetus.Label != "" {
+		fmt.Fprintf(&sb, " (%s)", b.Impetus.Label)
+	}
+	if excerpt := truncate(strings.TrimSpace(b.Content), 280); excerpt != "" {
+		sb.WriteString("\n\n> " + strings.ReplaceAll(excerpt, "\n", "\n> "))
+	}
+	sb.WriteString("\n\nRun `bt show " + b.ID + "` for the full beat.")
+	return sb.String()
+}
+
+// beadClosedKey is the impetus meta key recording when a linked bead closed.
+const beadClosedKey = "bead_closed:"
+
+// BeadsSyncOptions controls BeadsSync.
+type BeadsSyncOptions struct {
+	DryRun bool
+	JSON   bool
+}
+
+// BeadChange is a linked bead whose status changed since the last sync.
+type BeadChange struct {
+	BeatID   string `json:"beat_id"`
+	BeadID   string `json:"bead_id"`
+	ClosedAt string `json:"closed_at,omitempty"`
+}
+
+// ClosedBeat is a beat whose linked beads are all closed.
+type ClosedBeat struct {
+	BeatID   string   `json:"beat_id"`
+	Beads    []string `json:"beads"`
+	ClosedAt string   `json:"closed_at"` // When the last of its beads closed
+	Content  string   `json:"content"`
+}
+
+// BeadsSyncResult is the output of BeadsSync.
+type BeadsSyncResult struct {
+	Provider  string       `json:"provider"`
+	Checked   int          `json:"checked"`
+	Missing   []string     `json:"missing,omitempty"`
+	Closed    []BeadChange `json:"closed"`
+	Reopened  []BeadChange `json:"reopened"`
+	AllClosed []ClosedBeat `json:"all_closed"`
+	DryRun    bool         `json:"dry_run,omitempty"`
+}
+
+// BeadsSync pulls the status of every linked bead from the configured
+// provider and records closures on the beats linked to them (impetus meta
+// bead_closed:<id> = time). A bead that reopens loses its mark. Beats whose
+// beads are all closed are reported as candidates for archival or a
+// retrospective.
+func (c *HumanCLI) BeadsSync(opts BeadsSyncOptions) error {
+	provider, err := beads.NewProvider(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("no bead provider available (configure one in %s)", beads.ConfigFile)
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, b := range all {
+		for _, id := range b.LinkedBeads.IDs() {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	known, err := provider.Lookup(ids)
+	if err != nil {
+		return fmt.Errorf("failed to look up beads in %s: %w", provider.Name(), err)
+	}
+
+	res := BeadsSyncResult{
+		Provider:  provider.Name(),
+		Checked:   len(ids),
+		Missing:   unknownBeads(ids, known),
+		Closed:    []BeadChange{},
+		Reopened:  []BeadChange{},
+		AllClosed: []ClosedBeat{},
+		DryRun:    opts.DryRun,
+	}
+	now := clock.Now().UTC().Format(time.RFC3339)
+	for i := range all {
+		b := &all[i]
+		closed, reopened := beadChanges(b, known, now)
+		if len(closed)+len(reopened) > 0 {
+			res.Closed = append(res.Closed, closed...)
+			res.Reopened = append(res.Reopened, reopened...)
+			if !opts.DryRun {
+				if _, err := c.store.Update(b.ID, func(stored *beat.Beat) error {
+					beadChanges(stored, known, now)
+					return nil
+				}); err != nil {
+					return fmt.Errorf("failed to update %s: %w", b.ID, err)
+				}
+			}
+		}
+		if last := allBeadsClosed(b); last != "" {
+			res.AllClosed = append(res.AllClosed, ClosedBeat{
+				BeatID:   b.ID,
+				Beads:    b.LinkedBeads.IDs(),
+				ClosedAt: last,
+				Content:  truncate(b.Content, 200),
+			})
+		}
+	}
+	sort.Slice(res.AllClosed, func(i, j int) bool { return res.AllClosed[i].ClosedAt > res.AllClosed[j].ClosedAt })
+
+	if opts.JSON {
+		return outputJSON(res)
+	}
+	verb := "Recorded"
+	if opts.DryRun {
+		verb = "Would record"
+	}
+	fmt.Printf("Checked %d linked bead(s) in %s\n", res.Checked, res.Provider)
+	for _, ch := range res.Closed {
+		fmt.Printf("  %s: %s closed at %s\n", ch.BeatID, ch.BeadID, ch.ClosedAt)
+	}
+	for _, ch := range res.Reopened {
+		fmt.Printf("  %s: %s reopened\n", ch.BeatID, ch.BeadID)
+	}
+	fmt.Printf("%s %d closure(s), %d reopening(s)\n", verb, len(res.Closed), len(res.Reopened))
+	if len(res.Missing) > 0 {
+		fmt.Printf("Not found in %s: %s\n", res.Provider, strings.Join(res.Missing, ", "))
+	}
+	if len(res.AllClosed) > 0 {
+		fmt.Printf("\nBeats whose beads are all closed (archive or write a retrospective):\n")
+		for _, cb := range res.AllClosed {
+			fmt.Printf("  %s  [%s]  %s\n", cb.BeatID, cb.ClosedAt[:min(10, len(cb.ClosedAt))], truncate(cb.Content, 60))
+		}
+	}
+	return nil
+}
+
+// beadChanges applies the known status of b's linked beads to its impetus
+// meta and returns what changed. Beads the provider did not find keep their
+// mark.
+func beadChanges(b *beat.Beat, known map[string]beads.Bead, now string) (closed, reopened []BeadChange) {
+	for _, id := range b.LinkedBeads.IDs() {
+		bead, ok := known[id]
+		if !ok {
+			continue
+		}
+		key := beadClosedKey + id
+		_, marked := b.Impetus.Meta[key]
+		switch {
+		case bead.Closed() && !marked:
+			at := now
+			if t, err := time.Parse(time.RFC3339, bead.ClosedAt); err == nil {
+				at = t.UTC().Format(time.RFC3339)
+			}
+			if b.Impetus.Meta == nil {
+				b.Impetus.Meta = make(map[string]string)
+			}
+			b.Impetus.Meta[key] = at
+			closed = append(closed, BeadChange{BeatID: b.ID, BeadID: id, ClosedAt: at})
+		case !bead.Closed() && marked:
+			delete(b.Impetus.Meta, key)
+			reopened = append(reopened, BeadChange{BeatID: b.ID, BeadID: id})
+		}
+	}
+	return closed, reopened
+}
+
+// allBeadsClosed returns when the last of b's linked beads closed, or ""
+// unless all of them are marked closed.
+func allBeadsClosed(b *beat.Beat) string {
+	if len(b.LinkedBeads) == 0 {
+		return ""
+	}
+	last := ""
+	for _, id := range b.LinkedBeads.IDs() {
+		at, ok := b.Impetus.Meta[beadClosedKey+id]
+		if !ok {
+			return ""
+		}
+		if at > last {
+			last = at
+		}
+	}
+	return last
+}
+
+// BeadsRemapResult is the output of BeadsRemap.
+type BeadsRemapResult struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Beats  []string `json:"beats"` // Beats now pointing at To
+	DryRun bool     `json:"dry_run,omitempty"`
+}
+
+// BeadsRemap renames a linked bead in every beat, for a bead that moved or
+// was renamed in the tracker.
+func (c *HumanCLI) BeadsRemap(from, to string, dryRun, jsonOut bool) error {
+	ids, err := c.store.RemapBead(from, to, dryRun)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(BeadsRemapResult{From: from, To: to, Beats: ids, DryRun: dryRun})
+	}
+	if len(ids) == 0 {
+		fmt.Printf("No beats link to %s\n", from)
+		return nil
+	}
+	verb := "Remapped"
+	if dryRun {
+		verb = "Would remap"
+	}
+	fmt.Printf("%s %s -> %s in %d beat(s):\n", verb, from, to, len(ids))
+	for _, id := range ids {
+		fmt.Printf("  %s\n", id)
+	}
+	return nil
+}
+
+// BeadsRefresh lists every bead from the provider into beads_cache.json.
+func (c *HumanCLI) BeadsRefresh(jsonOut bool) error {
+	provider, err := beads.NewProvider(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("no bead provider available (configure one in %s)", beads.ConfigFile)
+	}
+	if _, isCache := provider.(beads.CacheProvider); isCache {
+		return fmt.Errorf("the provider is %s itself; configure the source to refresh from in %s", beads.CacheFile, beads.ConfigFile)
+	}
+	list, err := beads.Refresh(c.store.Dir(), provider)
+	if err != nil {
+		return fmt.Errorf("failed to list beads in %s: %w", provider.Name(), err)
+	}
+
+	open := 0
+	for _, b := range list {
+		if !b.Closed() {
+			open++
+		}
+	}
+	if jsonOut {
+		return outputJSON(map[string]interface{}{
+			"source": provider.Name(),
+			"beads":  len(list),
+			"open":   open,
+			"cache":  beads.CacheFile,
+		})
+	}
+	fmt.Printf("Cached %d bead(s) from %s (%d open) in %s\n", len(list), provider.Name(), open, beads.CacheFile)
+	return nil
+}
+
+// linkAuthor records who created a bead link from the command line.
+func linkAuthor() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return "cli"
+}
+
+// RegisterBeadsInput is the object form of --robot-register-beads input; a
+// plain array of beads is also accepted.
+type RegisterBeadsInput struct {
+	Beads   []beads.Bead `json:"beads"`
+	Replace bool         `json:"replace,omitempty"` // Drop cached beads not listed
+}
+
+// RegisterBeads stores beads an agent knows about in beads_cache.json, so
+// mapping, link suggestions and lookups see them without being sent the list
+// every call.
+func (c *RobotCLI) RegisterBeads(input io.Reader) error {
+	var raw json.RawMessage
+	if err := json.NewDecoder(input).Decode(&raw); err != nil {
+		return outputError("invalid input JSON", err)
+	}
+	var in RegisterBeadsInput
+	var err error
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(raw, &in.Beads)
+	} else {
+		err = json.Unmarshal(raw, &in)
+	}
+	if err != nil {
+		return outputError("invalid input JSON", err)
+	}
+	if len(in.Beads) == 0 && !in.Replace {
+		return outputError("beads is required", nil)
+	}
+
+	list, added, err := beads.Register(c.store.Dir(), in.Beads, in.Replace)
+	if err != nil {
+		return outputError("failed to register beads", err)
+	}
+	open := 0
+	for _, b := range list {
+		if !b.Closed() {
+			open++
+		}
+	}
+	return outputJSON(map[string]interface{}{
+		"registered": len(in.Beads),
+		"added":      added,
+		"updated":    len(in.Beads) - added,
+		"beads":      len(list),
+		"open":       open,
+		"cache":      beads.CacheFile,
+	})
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// beadsFixture is a store whose beads.json points at a JSONL bead store;
+// setBeads rewrites that store.
+func beadsFixture(t *testing.T) (*store.JSONLStore, func(lines string)) {
+	t.Helper()
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := filepath.Join(t.TempDir(), "issues.jsonl")
+	cfg, err := json.Marshal(beads.Config{Provider: "jsonl", Path: issues})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(), beads.ConfigFile), cfg, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return s, func(lines string) {
+		if err := os.WriteFile(issues, []byte(lines), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func linked(ids ...string) beat.BeadLinks {
+	var links beat.BeadLinks
+	for _, id := range ids {
+		links = append(links, beat.BeadLink{BeadID: id, Relation: beat.RelationSeed})
+	}
+	return links
+}
+
+func TestBeadsSync(t *testing.T) {
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+	clock.Set(now)
+	defer clock.Reset()
+
+	s, setBeads := beadsFixture(t)
+	created := now.AddDate(0, 0, -5)
+	for _, b := range []*beat.Beat{
+		{ID: "beat-20260315-001", Content: "Login and search", LinkedBeads: linked("bd-1", "bd-2")},
+		{ID: "beat-20260315-002", Content: "Login only", LinkedBeads: linked("bd-1")},
+		{ID: "beat-20260315-003", Content: "Gone", LinkedBeads: linked("bd-9")},
+		{ID: "beat-20260315-004", Content: "Not linked"},
+	} {
+		b.CreatedAt, b.UpdatedAt = created, created
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setBeads(`{"id":"bd-1","title":"Fix login","status":"closed","closed_at":"2026-03-18T14:00:00+02:00"}
+{"id":"bd-2","title":"Search","status":"open"}
+`)
+	beatsFile := filepath.Join(s.Dir(), store.DefaultBeatsFile)
+	stored := func() []byte {
+		data, err := os.ReadFile(beatsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	c := NewHumanCLI(s)
+	sync := func(dryRun bool) BeadsSyncResult {
+		t.Helper()
+		var buf bytes.Buffer
+		SetJSONOutput(&buf)
+		defer SetJSONOutput(nil)
+		if err := c.BeadsSync(BeadsSyncOptions{DryRun: dryRun, JSON: true}); err != nil {
+			t.Fatal(err)
+		}
+		var res BeadsSyncResult
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			t.Fatalf("invalid output %s: %v", buf.String(), err)
+		}
+		return res
+	}
+	closedMarks := func(id string) map[string]string {
+		t.Helper()
+		b, err := s.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		marks := map[string]string{}
+		for k, v := range b.Impetus.Meta {
+			marks[k] = v
+		}
+		return marks
+	}
+
+	before := stored()
+	res := sync(true)
+	if len(res.Closed) != 2 || !bytes.Equal(stored(), before) {
+		t.Fatalf("dry run: closed %+v, store changed %v", res.Closed, !bytes.Equal(stored(), before))
+	}
+
+	// The closure is recorded at the bead's closed_at, in UTC, on every beat
+	// linked to it; a beat is a candidate once all its beads are closed.
+	res = sync(false)
+	if res.Checked != 3 || len(res.Missing) != 1 || res.Missing[0] != "bd-9" {
+		t.Errorf("checked %d, missing %v", res.Checked, res.Missing)
+	}
+	want := []BeadChange{
+		{BeatID: "beat-20260315-001", BeadID: "bd-1", ClosedAt: "2026-03-18T12:00:00Z"},
+		{BeatID: "beat-20260315-002", BeadID: "bd-1", ClosedAt: "2026-03-18T12:00:00Z"},
+	}
+	if len(res.Closed) != len(want) || res.Closed[0] != want[0] || res.Closed[1] != want[1] {
+		t.Errorf("closed = %+v, want %+v", res.Closed, want)
+	}
+	if len(res.AllClosed) != 1 || res.AllClosed[0].BeatID != "beat-20260315-002" || res.AllClosed[0].ClosedAt != "2026-03-18T12:00:00Z" {
+		t.Errorf("all closed = %+v, want only beat-20260315-002", res.AllClosed)
+	}
+	if marks := closedMarks("beat-20260315-001"); len(marks) != 1 || marks["bead_closed:bd-1"] != "2026-03-18T12:00:00Z" {
+		t.Errorf("meta = %v", marks)
+	}
+
+	// Syncing again changes nothing and rewrites nothing.
+	before = stored()
+	res = sync(false)
+	if len(res.Closed)+len(res.Reopened) != 0 || len(res.AllClosed) != 1 {
+		t.Errorf("re-sync: closed %+v, reopened %+v, all closed %+v", res.Closed, res.Reopened, res.AllClosed)
+	}
+	if !bytes.Equal(stored(), before) {
+		t.Error("re-sync rewrote the store")
+	}
+
+	// A reopened bead loses its mark; one closed without a time is stamped now.
+	setBeads(`{"id":"bd-1","title":"Fix login","status":"in_progress"}
+{"id":"bd-2","title":"Search","status":"closed"}
+`)
+	res = sync(false)
+	if len(res.Reopened) != 2 || len(res.Closed) != 1 || res.Closed[0] != (BeadChange{BeatID: "beat-20260315-001", BeadID: "bd-2", ClosedAt: "2026-03-20T09:00:00Z"}) {
+		t.Errorf("reopened %+v, closed %+v", res.Reopened, res.Closed)
+	}
+	if len(res.AllClosed) != 0 {
+		t.Errorf("all closed = %+v, want none", res.AllClosed)
+	}
+	if marks := closedMarks("beat-20260315-001"); len(marks) != 1 || marks["bead_closed:bd-2"] != "2026-03-20T09:00:00Z" {
+		t.Errorf("meta after reopen = %v", marks)
+	}
+	if marks := closedMarks("beat-20260315-002"); len(marks) != 0 {
+		t.Errorf("meta after reopen = %v, want none", marks)
+	}
+}
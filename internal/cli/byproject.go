@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/wald"
+)
+
+// Unassigned labels beats without a WALD directory in by-project output.
+const Unassigned = "(unassigned)"
+
+// ByProjectOptions contains options for the by-project command.
+type ByProjectOptions struct {
+	Wald string // Only this WALD directory and its subdirectories
+	Max  int    // Recent beats shown per directory (default 3)
+	JSON bool
+}
+
+// ProjectBeat is a beat listed under its WALD directory.
+type ProjectBeat struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Impetus    string    `json:"impetus"`
+	Content    string    `json:"content"`
+	Method     string    `json:"inference_method,omitempty"`
+	Confidence float64   `json:"confidence,omitempty"`
+}
+
+// ProjectGroup is the beats filed under one WALD directory.
+type ProjectGroup struct {
+	Directory string        `json:"wald_directory"` // Unassigned for beats without one
+	Purpose   string        `json:"purpose,omitempty"`
+	Beats     int           `json:"beats"`
+	Latest    time.Time     `json:"latest"`
+	Recent    []ProjectBeat `json:"recent"`
+}
+
+// ByProject groups beats by the WALD directory they were captured in, busiest
+// first, with each directory's most recent beats.
+func (c *HumanCLI) ByProject(opts ByProjectOptions) error {
+	if opts.Max <= 0 {
+		opts.Max = 3
+	}
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	all = filterWALD(all, opts.Wald)
+
+	var cfg *wald.Config
+	cwd, _ := os.Getwd()
+	if root := wald.FindWerkRoot(cwd); root != "" {
+		cfg, _ = wald.Load(root) // Purposes are a nicety
+	}
+	groups := projectGroups(all, cfg, opts.Max)
+
+	if opts.JSON {
+		return outputJSON(groups)
+	}
+	if len(groups) == 0 {
+		fmt.Println("No beats found.")
+		return nil
+	}
+	for _, g := range groups {
+		noun := "beats"
+		if g.Beats == 1 {
+			noun = "beat"
+		}
+		fmt.Printf("%s (%d %s, latest %s)", g.Directory, g.Beats, noun, displayDate(g.Latest))
+		if g.Purpose != "" {
+			fmt.Printf("  %s", truncate(g.Purpose, 60))
+		}
+		fmt.Println()
+		for _, b := range g.Recent {
+			fmt.Printf("  %s  %s: %s\n", b.ID, b.Impetus, truncate(b.Content, 60))
+		}
+		if more := g.Beats - len(g.Recent); more > 0 {
+			fmt.Printf("  ... %d more (bt list --wald %s)\n", more, g.Directory)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// projectGroups groups beats by WALD directory. Beats without one come last.
+func projectGroups(all []beat.Beat, cfg *wald.Config, max int) []ProjectGroup {
+	byDir := make(map[string][]beat.Beat)
+	for _, b := range all {
+		dir := Unassigned
+		if b.Context != nil && b.Context.WALDDirectory != "" {
+			dir = b.Context.WALDDirectory
+		}
+		byDir[dir] = append(byDir[dir], b)
+	}
+
+	groups := []ProjectGroup{}
+	for dir, beats := range byDir {
+		sort.SliceStable(beats, func(i, j int) bool { return beats[i].CreatedAt.After(beats[j].CreatedAt) })
+		g := ProjectGroup{Directory: dir, Beats: len(beats), Latest: beats[0].CreatedAt, Recent: []ProjectBeat{}}
+		if cfg != nil {
+			if d := cfg.Directory(dir); d != nil {
+				g.Purpose = d.Purpose
+			}
+		}
+		for _, b := range beats[:min(max, len(beats))] {
+			pb := ProjectBeat{ID: b.ID, CreatedAt: b.CreatedAt, Impetus: b.Impetus.Label, Content: b.Content}
+			if b.Context != nil {
+				pb.Method, pb.Confidence = b.Context.InferenceMethod, b.Context.Confidence
+			}
+			g.Recent = append(g.Recent, pb)
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Directory == Unassigned) != (b.Directory == Unassigned) {
+			return b.Directory == Unassigned
+		}
+		if a.Beats != b.Beats {
+			return a.Beats > b.Beats
+		}
+		return a.Directory < b.Directory
+	})
+	return groups
+}
+
+// filterWALD keeps the beats filed under a WALD directory, given as a path
+// on disk or as listed in WALD.yaml. An empty directory keeps every beat.
+func filterWALD(all []beat.Beat, dir string) []beat.Beat {
+	if dir == "" {
+		return all
+	}
+	dir = wald.ResolveDir(dir)
+	var kept []beat.Beat
+	for _, b := range all {
+		if b.InWALD(dir) {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
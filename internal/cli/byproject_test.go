@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/wald"
+)
+
+func TestProjectGroups(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	filed := func(id, dir string, days int) beat.Beat {
+		b := beat.Beat{ID: id, CreatedAt: day.AddDate(0, 0, days)}
+		if dir != "" {
+			b.Context = &beat.Context{WALDDirectory: dir, InferenceMethod: pipeline.InferenceLocation, Confidence: 1}
+		}
+		return b
+	}
+	all := []beat.Beat{
+		filed("b1", "projects/beats", 0),
+		filed("b2", "projects/beats/internal", 2),
+		filed("b3", "projects/beats", 1),
+		filed("b4", "projects/beatsmith", 3),
+		filed("b5", "", 4),
+		filed("b6", "", 5),
+		filed("b7", "", 6),
+	}
+	cfg := &wald.Config{Directories: []wald.Directory{{Path: "projects/beats", Purpose: "Narrative substrate"}}}
+
+	groups := projectGroups(all, cfg, 1)
+	var dirs []string
+	for _, g := range groups {
+		dirs = append(dirs, g.Directory)
+	}
+	want := []string{"projects/beats", "projects/beats/internal", "projects/beatsmith", Unassigned}
+	if len(dirs) != len(want) {
+		t.Fatalf("groups = %v, want %v", dirs, want)
+	}
+	for i := range want {
+		if dirs[i] != want[i] {
+			t.Fatalf("groups = %v, want %v (busiest first, unassigned last)", dirs, want)
+		}
+	}
+	top := groups[0]
+	if top.Beats != 2 || top.Purpose != "Narrative substrate" || len(top.Recent) != 1 || top.Recent[0].ID != "b3" || !top.Latest.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("projects/beats = %+v", top)
+	}
+
+	// A directory filter includes subdirectories but not siblings sharing a prefix
+	kept := filterWALD(all, "projects/beats/")
+	if len(kept) != 3 {
+		t.Errorf("filterWALD = %d beats, want b1, b2 and b3", len(kept))
+	}
+	if got := filterWALD(all, ""); len(got) != len(all) {
+		t.Errorf("empty filter kept %d of %d", len(got), len(all))
+	}
+}
@@ -0,0 +1,722 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/importer"
+)
+
+// gitCommitBeat converts a captured commit into a proposed beat with impetus "Code change".
+func gitCommitBeat(commit *capture.GitCommit) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source": "git",
+		"repo":   commit.Repo,
+		"commit": commit.Hash,
+		"author": commit.Author,
+	}
+	if commit.Branch != "" {
+		meta["branch"] = commit.Branch
+	}
+
+	locator := commit.RepoPath + "@" + commit.Hash
+	if commit.Remote != "" {
+		locator = commit.Remote + "@" + commit.Hash
+	}
+
+	createdAt := commit.Date
+	return &beat.ProposedBeat{
+		Content: commit.Content(),
+		Impetus: beat.Impetus{
+			Label: "Code change",
+			Raw:   commit.Subject,
+			Meta:  meta,
+		},
+		References: []beat.Reference{{
+			Kind:    "git",
+			Subtype: "commit",
+			Locator: locator,
+			Label:   commit.Subject,
+		}},
+		Entities: []beat.Entity{{
+			Label:    commit.Repo,
+			Category: "project",
+			Meta: map[string]string{
+				"confidence": "1.0",
+				"repo_path":  commit.RepoPath,
+			},
+		}},
+		LinkedBeads: []string{},
+		CreatedAt:   &createdAt,
+	}
+}
+
+// hasCommitBeat reports whether a beat for the given commit hash already exists.
+func (c *HumanCLI) hasCommitBeat(hash string) (bool, error) {
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		return false, err
+	}
+	for _, b := range beats {
+		if b.Impetus.Meta["commit"] == hash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CaptureGitCommit creates a beat from a commit in the repository at repoDir.
+// Commits that were already captured (e.g. after an amend hook re-run) are skipped.
+func (c *HumanCLI) CaptureGitCommit(repoDir, rev string) error {
+	commit, err := capture.CaptureGitCommit(repoDir, rev)
+	if err != nil {
+		return err
+	}
+
+	exists, err := c.hasCommitBeat(commit.Hash)
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	if exists {
+		fmt.Printf("Commit %s already captured\n", commit.ShortHash())
+		return nil
+	}
+
+	b, err := c.commit(gitCommitBeat(commit), beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created beat: %s (%s@%s)\n", b.ID, commit.Repo, commit.ShortHash())
+	return nil
+}
+
+// xThreadBeat converts an unrolled thread into a proposed beat with impetus "X discovery".
+func xThreadBeat(thread *capture.XThread, note string) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source": "x",
+		"url":    thread.URL,
+		"handle": thread.Handle,
+		"posts":  fmt.Sprint(len(thread.Posts)),
+	}
+	if thread.Author != "" {
+		meta["author"] = thread.Author
+	}
+	if len(thread.Posts) > 0 {
+		first := thread.Posts[0]
+		meta["post_id"] = first.ID
+		if !first.CreatedAt.IsZero() {
+			meta["posted_at"] = first.CreatedAt.Format(time.RFC3339)
+		}
+	}
+
+	content := thread.Content()
+	if note != "" {
+		content = note + "\n\n" + content
+	}
+
+	return &beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: "X discovery",
+			Raw:   thread.URL,
+			Meta:  meta,
+		},
+		References: []beat.Reference{{
+			Kind:    "url",
+			Subtype: "x",
+			Locator: thread.URL,
+			Label:   "@" + thread.Handle,
+		}},
+		Entities: []beat.Entity{{
+			Label:    "@" + thread.Handle,
+			Category: "person",
+			Meta:     map[string]string{"confidence": "1.0"},
+		}},
+		LinkedBeads: []string{},
+	}
+}
+
+// CaptureX unrolls an X/Twitter thread and saves it as a single beat.
+func (c *HumanCLI) CaptureX(postURL, note string) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	thread, err := capture.CaptureXThread(postURL, cfg.X)
+	if err != nil {
+		return fmt.Errorf("X capture failed: %w", err)
+	}
+
+	b, err := c.commit(xThreadBeat(thread, note), beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created beat: %s (@%s, %d post(s))\n", b.ID, thread.Handle, len(thread.Posts))
+	return nil
+}
+
+// CaptureHN saves a Hacker News story, its linked article and optionally its
+// top comments as a beat with impetus "HN discovery".
+func (c *HumanCLI) CaptureHN(ref string, comments int, note string) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	story, err := capture.CaptureHNStory(ref, comments, cfg.HN)
+	if err != nil {
+		return fmt.Errorf("HN capture failed: %w", err)
+	}
+
+	meta := map[string]string{
+		"source":    "hn",
+		"hn_id":     fmt.Sprint(story.ID),
+		"title":     story.Title,
+		"author":    story.Author,
+		"score":     fmt.Sprint(story.Score),
+		"comments":  fmt.Sprint(story.Comments),
+		"posted_at": story.CreatedAt.Format(time.RFC3339),
+	}
+	refs := []beat.Reference{{
+		Kind:    "url",
+		Subtype: "hn",
+		Locator: story.DiscussionURL(),
+		Label:   story.Title,
+	}}
+	if story.Article != nil {
+		// Classify and attach the linked article exactly as `bt add -w` would
+		articleMeta, articleRefs := c.webCaptureDetails(story.Article, c.wantSnapshot(false))
+		meta["url"] = story.URL
+		meta["article_impetus"] = story.Article.Impetus
+		if site := articleMeta["site_name"]; site != "" {
+			meta["site_name"] = site
+		}
+		refs = append(refs, articleRefs...)
+	}
+
+	content := story.Content()
+	if note != "" {
+		content = note + "\n\n" + content
+	}
+
+	b, err := c.commit(&beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: "HN discovery",
+			Raw:   story.DiscussionURL(),
+			Meta:  meta,
+		},
+		References:  refs,
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+	}, beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created beat: %s (%s, %d points)\n", b.ID, story.Title, story.Score)
+	return nil
+}
+
+// pdfExcerptWords bounds the document text copied into a PDF beat's content.
+const pdfExcerptWords = 120
+
+// pdfReferences builds the pdf reference for a document and stores the file
+// and its extracted text as attachments.
+func (c *HumanCLI) pdfReferences(source, title string, data []byte, doc *capture.PDFDocument) []beat.Reference {
+	refs := []beat.Reference{{
+		Kind:    "pdf",
+		Locator: source,
+		Label:   title,
+	}}
+	if len(data) > 0 {
+		if rel, err := c.store.SaveAttachment(data, ".pdf"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "application/pdf",
+				Locator: rel,
+				Label:   "Document",
+				Meta:    map[string]string{"source": source, "bytes": fmt.Sprint(len(data))},
+			})
+		}
+	}
+	if doc != nil && doc.Text != "" {
+		if rel, err := c.store.SaveAttachment([]byte(doc.Text+"\n"), ".txt"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "text/plain",
+				Locator: rel,
+				Label:   "Full text",
+				Meta: map[string]string{
+					"source": source,
+					"words":  fmt.Sprint(len(strings.Fields(doc.Text))),
+					"pages":  fmt.Sprint(doc.Pages),
+				},
+			})
+		}
+	}
+	return refs
+}
+
+// CapturePDF extracts a local or remote PDF and saves it as a beat with
+// impetus "PDF discovery". The opening text goes into the beat content so
+// keyword and semantic search find it; the full text is kept as an attachment.
+func (c *HumanCLI) CapturePDF(pathOrURL, note string) error {
+	source := pathOrURL
+	if !strings.HasPrefix(pathOrURL, "http://") && !strings.HasPrefix(pathOrURL, "https://") {
+		if abs, err := filepath.Abs(pathOrURL); err == nil {
+			source = abs
+		}
+	}
+
+	data, err := capture.ReadPDF(pathOrURL)
+	if err != nil {
+		return fmt.Errorf("PDF capture failed: %w", err)
+	}
+	doc, err := capture.ExtractPDF(data)
+	if err != nil {
+		return fmt.Errorf("PDF capture failed: %w", err)
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(source), path.Ext(source))
+	}
+
+	meta := map[string]string{
+		"source": "pdf",
+		"title":  title,
+		"pages":  fmt.Sprint(doc.Pages),
+		"words":  fmt.Sprint(len(strings.Fields(doc.Text))),
+	}
+	if doc.Author != "" {
+		meta["author"] = doc.Author
+	}
+
+	var content strings.Builder
+	if note != "" {
+		content.WriteString(note + "\n\n")
+	}
+	content.WriteString(title)
+	if doc.Author != "" {
+		content.WriteString("\n" + doc.Author)
+	}
+	if excerpt := doc.Excerpt(pdfExcerptWords); excerpt != "" {
+		content.WriteString("\n\n" + excerpt)
+	} else {
+		meta["text"] = "none" // Scanned or image-only document
+	}
+	content.WriteString("\n\n" + source)
+
+	b, err := c.commit(&beat.ProposedBeat{
+		Content: content.String(),
+		Impetus: beat.Impetus{
+			Label: "PDF discovery",
+			Raw:   source,
+			Meta:  meta,
+		},
+		References:  c.pdfReferences(source, title, data, doc),
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+	}, beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created beat: %s (%s, %d page(s))\n", b.ID, title, doc.Pages)
+	return nil
+}
+
+// CaptureArxiv saves an arXiv paper's title, authors and abstract as a beat
+// with impetus "arXiv discovery", attaching the PDF and its extracted text.
+func (c *HumanCLI) CaptureArxiv(ref, note string) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	paper, err := capture.CaptureArxivPaper(ref, cfg.Arxiv)
+	if err != nil {
+		return fmt.Errorf("arXiv capture failed: %w", err)
+	}
+
+	meta := map[string]string{
+		"source":   "arxiv",
+		"arxiv_id": paper.ID,
+		"title":    paper.Title,
+		"url":      paper.AbsURL,
+	}
+	if len(paper.Authors) > 0 {
+		meta["authors"] = strings.Join(paper.Authors, ", ")
+	}
+	if !paper.Published.IsZero() {
+		meta["published_at"] = paper.Published.Format(time.RFC3339)
+	}
+	if paper.Document != nil {
+		meta["pages"] = fmt.Sprint(paper.Document.Pages)
+	}
+
+	content := paper.Content()
+	if note != "" {
+		content = note + "\n\n" + content
+	}
+
+	refs := append([]beat.Reference{{
+		Kind:    "url",
+		Subtype: "arxiv",
+		Locator: paper.AbsURL,
+		Label:   paper.Title,
+	}}, c.pdfReferences(paper.PDFURL, paper.Title, paper.PDF, paper.Document)...)
+
+	entities := []beat.Entity{}
+	for _, author := range paper.Authors {
+		entities = append(entities, beat.Entity{
+			Label:    author,
+			Category: "person",
+			Meta:     map[string]string{"confidence": "1.0"},
+		})
+	}
+
+	b, err := c.commit(&beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: "arXiv discovery",
+			Raw:   paper.AbsURL,
+			Meta:  meta,
+		},
+		References:  refs,
+		Entities:    entities,
+		LinkedBeads: []string{},
+	}, beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	if paper.Document == nil {
+		fmt.Printf("Created beat: %s (%s, PDF text unavailable)\n", b.ID, paper.Title)
+		return nil
+	}
+	fmt.Printf("Created beat: %s (%s, %d page(s))\n", b.ID, paper.Title, paper.Document.Pages)
+	return nil
+}
+
+// githubStarBeat converts a starred repository into a proposed beat with
+// impetus "GitHub star", dated when the star was given.
+func githubStarBeat(user string, star capture.GitHubStar) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source":             "github-stars",
+		importer.SourceIDKey: user + ":" + star.FullName,
+		"user":               user,
+		"repo":               star.FullName,
+		"url":                star.URL,
+		"starred_at":         star.StarredAt.Format(time.RFC3339),
+	}
+	if star.Language != "" {
+		meta["language"] = star.Language
+	}
+
+	entities := []beat.Entity{{
+		Label:    star.FullName,
+		Category: "project",
+		Meta:     map[string]string{"confidence": "1.0"},
+	}}
+	for _, topic := range star.Topics {
+		entities = append(entities, beat.Entity{
+			Label:    topic,
+			Category: "topic",
+			Meta:     map[string]string{"confidence": "1.0", "source": "github-topic"},
+		})
+	}
+
+	starredAt := star.StarredAt
+	return &beat.ProposedBeat{
+		Content: star.Content(),
+		Impetus: beat.Impetus{
+			Label: "GitHub star",
+			Raw:   star.URL,
+			Meta:  meta,
+		},
+		References: []beat.Reference{{
+			Kind:    "url",
+			Subtype: "github",
+			Locator: star.URL,
+			Label:   star.FullName,
+		}},
+		Entities:    entities,
+		LinkedBeads: []string{},
+		CreatedAt:   &starredAt,
+	}
+}
+
+// CaptureGitHubStars creates one beat per repository the user starred since
+// the newest star already captured for them (all stars on the first sync).
+func (c *HumanCLI) CaptureGitHubStars(user string, dryRun bool) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	existing, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	var since time.Time
+	for _, b := range existing {
+		if b.Impetus.Meta["source"] != "github-stars" || b.Impetus.Meta["user"] != user {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, b.Impetus.Meta["starred_at"]); err == nil && t.After(since) {
+			since = t
+		}
+	}
+
+	stars, err := capture.FetchGitHubStars(user, since, cfg.GitHub)
+	if err != nil {
+		return fmt.Errorf("GitHub stars sync failed: %w", err)
+	}
+	if !since.IsZero() {
+		fmt.Printf("Stars since %s\n", displayTime(since))
+	}
+
+	proposed := make([]*beat.ProposedBeat, 0, len(stars))
+	for _, star := range stars {
+		p := githubStarBeat(user, star)
+		p.SetChannel(beat.ChannelCapture)
+		proposed = append(proposed, p)
+	}
+	return c.importProposed("github-stars", proposed, dryRun)
+}
+
+// CaptureGitHubIssue saves an issue or pull request's title, body, state and
+// labels as a beat with impetus "GitHub issue" (or "GitHub pull request").
+func (c *HumanCLI) CaptureGitHubIssue(ref, note string) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	issue, err := capture.CaptureGitHubIssue(ref, cfg.GitHub)
+	if err != nil {
+		return fmt.Errorf("GitHub issue capture failed: %w", err)
+	}
+
+	meta := map[string]string{
+		"source":    "github",
+		"repo":      issue.FullName(),
+		"number":    fmt.Sprint(issue.Number),
+		"kind":      issue.Kind(),
+		"state":     issue.State,
+		"author":    issue.Author,
+		"url":       issue.URL,
+		"title":     issue.Title,
+		"opened_at": issue.CreatedAt.Format(time.RFC3339),
+	}
+	if len(issue.Labels) > 0 {
+		meta["labels"] = strings.Join(issue.Labels, ",")
+	}
+	if issue.ClosedAt != nil {
+		meta["closed_at"] = issue.ClosedAt.UTC().Format(time.RFC3339)
+	}
+
+	entities := []beat.Entity{{
+		Label:    issue.FullName(),
+		Category: "project",
+		Meta:     map[string]string{"confidence": "1.0"},
+	}}
+	if issue.Author != "" {
+		entities = append(entities, beat.Entity{
+			Label:    "@" + issue.Author,
+			Category: "person",
+			Meta:     map[string]string{"confidence": "1.0"},
+		})
+	}
+	for _, label := range issue.Labels {
+		entities = append(entities, beat.Entity{
+			Label:    label,
+			Category: "topic",
+			Meta:     map[string]string{"confidence": "1.0", "source": "github-label"},
+		})
+	}
+
+	content := issue.Content()
+	if note != "" {
+		content = note + "\n\n" + content
+	}
+
+	label, subtype := "GitHub issue", "github-issue"
+	if issue.PullRequest {
+		label, subtype = "GitHub pull request", "github-pull"
+	}
+	b, err := c.commit(&beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: label,
+			Raw:   issue.URL,
+			Meta:  meta,
+		},
+		References: []beat.Reference{{
+			Kind:    "url",
+			Subtype: subtype,
+			Locator: issue.URL,
+			Label:   fmt.Sprintf("%s#%d", issue.FullName(), issue.Number),
+		}},
+		Entities:    entities,
+		LinkedBeads: []string{},
+	}, beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created beat: %s (%s#%d, %s)\n", b.ID, issue.FullName(), issue.Number, issue.State)
+	return nil
+}
+
+// podcastPromptChars bounds the transcript sent for summarization; long
+// episodes are summarized from their first part.
+const podcastPromptChars = 24000
+
+// CapturePodcast saves a podcast episode's metadata as a beat with impetus
+// "Podcast discovery". When the feed links a transcript, or Whisper can
+// transcribe the audio, the transcript is attached and summarized with the
+// LLM configured for session summaries.
+func (c *HumanCLI) CapturePodcast(ref, episode, note string, transcribe bool) error {
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	ep, err := capture.CapturePodcastEpisode(ref, episode)
+	if err != nil {
+		return fmt.Errorf("podcast capture failed: %w", err)
+	}
+	if ep.Transcript == "" && transcribe && ep.AudioURL != "" {
+		if cfg.Podcast.HasWhisper() {
+			fmt.Println("Transcribing audio with Whisper (this can take a while)...")
+		}
+		text, err := capture.TranscribeAudio(ep.AudioURL, cfg.Podcast)
+		switch {
+		case errors.Is(err, capture.ErrNoWhisper):
+			fmt.Println("No transcript in the feed and no Whisper installed; saving metadata only")
+		case err != nil:
+			fmt.Printf("Warning: transcription failed (%v); saving metadata only\n", err)
+		default:
+			ep.Transcript, ep.TranscriptSource = text, "whisper"
+		}
+	}
+
+	meta := map[string]string{
+		"source": "podcast",
+		"title":  ep.Title,
+		"url":    ep.URL(),
+	}
+	for key, value := range map[string]string{
+		"show":       ep.Show,
+		"author":     ep.Author,
+		"duration":   ep.Duration,
+		"audio_url":  ep.AudioURL,
+		"feed_url":   ep.FeedURL,
+		"guid":       ep.GUID,
+		"transcript": ep.TranscriptSource,
+	} {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+	if !ep.Published.IsZero() {
+		meta["published_at"] = ep.Published.Format(time.RFC3339)
+	}
+
+	refs := []beat.Reference{{
+		Kind:    "url",
+		Subtype: "podcast",
+		Locator: ep.URL(),
+		Label:   ep.Title,
+	}}
+	if ep.AudioURL != "" && ep.AudioURL != ep.URL() {
+		refs = append(refs, beat.Reference{
+			Kind:    "url",
+			Subtype: "audio",
+			Locator: ep.AudioURL,
+			Label:   "Audio",
+		})
+	}
+
+	summary := ""
+	if ep.Transcript != "" {
+		source := ep.TranscriptURL
+		if ep.TranscriptSource == "whisper" {
+			source = ep.AudioURL
+		}
+		if rel, err := c.store.SaveAttachment([]byte(ep.Title+"\n\n"+ep.Transcript+"\n"), ".txt"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "text/plain",
+				Locator: rel,
+				Label:   "Transcript",
+				Meta: map[string]string{
+					"source": source,
+					"words":  fmt.Sprint(len(strings.Fields(ep.Transcript))),
+				},
+			})
+		}
+
+		llm := hooks.GetSessionEndConfig(c.store.Dir())
+		transcript := ep.Transcript
+		if len(transcript) > podcastPromptChars {
+			transcript = transcript[:podcastPromptChars]
+		}
+		prompt := fmt.Sprintf("Summarize this podcast episode (%q) in 3-5 sentences: the main ideas, claims and anything worth following up. Be specific, no preamble:\n\n%s", ep.Title, transcript)
+		if summary, err = llm.Generate(prompt); err != nil {
+			fmt.Printf("Warning: summary unavailable (%v); transcript saved as an attachment\n", err)
+			summary = ""
+		} else {
+			meta["summary_model"] = llm.OllamaModel
+		}
+	}
+
+	content := ep.Content()
+	if summary != "" {
+		content = summary + "\n\n" + content
+	}
+	if note != "" {
+		content = note + "\n\n" + content
+	}
+
+	entities := []beat.Entity{}
+	if ep.Show != "" {
+		entities = append(entities, beat.Entity{
+			Label:    ep.Show,
+			Category: "project",
+			Meta:     map[string]string{"confidence": "1.0", "source": "podcast"},
+		})
+	}
+
+	b, err := c.commit(&beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: "Podcast discovery",
+			Raw:   ep.URL(),
+			Meta:  meta,
+		},
+		References:  refs,
+		Entities:    entities,
+		LinkedBeads: []string{},
+	}, beat.ChannelCapture)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case summary != "":
+		fmt.Printf("Created beat: %s (%s, summarized %s transcript)\n", b.ID, ep.Title, ep.TranscriptSource)
+	case ep.Transcript != "":
+		fmt.Printf("Created beat: %s (%s, %s transcript attached)\n", b.ID, ep.Title, ep.TranscriptSource)
+	default:
+		fmt.Printf("Created beat: %s (%s, no transcript)\n", b.ID, ep.Title)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/config"
+)
+
+// ConfigShowOutput is the output of config show --robot.
+type ConfigShowOutput struct {
+	Path     string           `json:"path"` // Config file read, whether or not it exists
+	Settings []config.Setting `json:"settings"`
+}
+
+// ConfigShow prints the effective configuration and where each setting
+// comes from: an environment variable, the config file or the default.
+func ConfigShow(jsonOut bool) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(ConfigShowOutput{Path: cfg.Path, Settings: cfg.Settings()})
+	}
+
+	fmt.Printf("Config file: %s\n\n", cfg.Path)
+	for _, s := range cfg.Settings() {
+		value := s.Value
+		if value == "" {
+			value = "(unset)"
+		}
+		fmt.Printf("  %-12s %-40s %-22s (set with %s)\n", s.Key, value, s.Source, s.Env)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// CoverageOptions contains options for the coverage command.
+type CoverageOptions struct {
+	By      string // month (default) or week
+	Periods int    // Trend periods to show (default 6)
+	JSON    bool
+}
+
+// BeadCoverage counts the beats linked to one bead.
+type BeadCoverage struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+	Beats  int    `json:"beats"`
+	Latest string `json:"latest"` // Most recent linked beat
+}
+
+// CoveragePeriod is the link rate of the beats created in one period.
+type CoveragePeriod struct {
+	Period string  `json:"period"`
+	Beats  int     `json:"beats"`
+	Linked int     `json:"linked"`
+	Rate   float64 `json:"rate"`
+}
+
+// CoverageReport is the output of Coverage.
+type CoverageReport struct {
+	Total     int              `json:"total"`
+	Linked    int              `json:"linked"`
+	Orphaned  int              `json:"orphaned"`
+	Rate      float64          `json:"rate"`
+	Beads     []BeadCoverage   `json:"beads"`
+	Provider  string           `json:"provider,omitempty"`
+	Uncovered []beads.Bead     `json:"uncovered"` // Open beads no beat links to
+	Trend     []CoveragePeriod `json:"trend"`
+}
+
+// Coverage reports how well beats feed the work tracker: linked vs orphaned
+// beats, beats per bead, open beads without narrative context, and the link
+// rate over time.
+func (c *HumanCLI) Coverage(opts CoverageOptions) error {
+	if opts.By == "" {
+		opts.By = "month"
+	}
+	if opts.By != "month" && opts.By != "week" {
+		return fmt.Errorf("invalid --by %q (use month or week)", opts.By)
+	}
+	if opts.Periods <= 0 {
+		opts.Periods = 6
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return err
+	}
+	report := coverageOf(all, opts.By, opts.Periods, clock.Now())
+
+	list, source, err := beads.Inventory(c.store.Dir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not list beads: %v\n", err)
+	}
+	if source != "" {
+		report.Provider = source
+		byID := make(map[string]beads.Bead, len(list))
+		for _, b := range list {
+			byID[b.ID] = b
+		}
+		covered := make(map[string]bool, len(report.Beads))
+		for i := range report.Beads {
+			bc := &report.Beads[i]
+			covered[bc.ID] = true
+			if b, ok := byID[bc.ID]; ok {
+				bc.Title, bc.Status = b.Title, b.Status
+			}
+		}
+		for _, b := range list {
+			if !covered[b.ID] && !b.Closed() {
+				report.Uncovered = append(report.Uncovered, b)
+			}
+		}
+	}
+
+	if opts.JSON {
+		return outputJSON(report)
+	}
+
+	fmt.Printf("Coverage: %d of %d beats linked to beads (%.0f%%), %d orphaned\n",
+		report.Linked, report.Total, report.Rate*100, report.Orphaned)
+
+	if len(report.Beads) > 0 {
+		fmt.Printf("\nBeats per bead:\n")
+		for _, bc := range report.Beads {
+			label := bc.ID
+			if bc.Title != "" {
+				label += "  " + truncate(bc.Title, 50)
+			}
+			fmt.Printf("  %3d  %s\n", bc.Beats, label)
+		}
+	}
+
+	if report.Provider != "" {
+		if len(report.Uncovered) == 0 {
+			fmt.Printf("\nEvery open bead in %s has narrative context.\n", report.Provider)
+		} else {
+			fmt.Printf("\nOpen beads in %s with no beats (%d):\n", report.Provider, len(report.Uncovered))
+			for _, b := range report.Uncovered {
+				fmt.Printf("  %s  %s\n", b.ID, truncate(b.Title, 60))
+			}
+		}
+	}
+
+	fmt.Printf("\nLink rate by %s:\n", opts.By)
+	for _, p := range report.Trend {
+		bar := strings.Repeat("#", int(p.Rate*20+0.5))
+		line := fmt.Sprintf("  %-8s  %3d/%-3d  %3.0f%%  %s", p.Period, p.Linked, p.Beats, p.Rate*100, bar)
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
+// coverageOf computes the store-side part of a coverage report; the
+// provider fills in titles and uncovered beads.
+func coverageOf(all []beat.Beat, by string, periods int, now time.Time) CoverageReport {
+	report := CoverageReport{
+		Total:     len(all),
+		Beads:     []BeadCoverage{},
+		Uncovered: []beads.Bead{},
+	}
+
+	perBead := make(map[string]*BeadCoverage)
+	for _, b := range all {
+		if len(b.LinkedBeads) == 0 {
+			report.Orphaned++
+			continue
+		}
+		report.Linked++
+		created := b.CreatedAt.UTC().Format(time.RFC3339)
+		for _, id := range b.LinkedBeads.IDs() {
+			bc := perBead[id]
+			if bc == nil {
+				bc = &BeadCoverage{ID: id}
+				perBead[id] = bc
+			}
+			bc.Beats++
+			if created > bc.Latest {
+				bc.Latest = created
+			}
+		}
+	}
+	if report.Total > 0 {
+		report.Rate = float64(report.Linked) / float64(report.Total)
+	}
+	for _, bc := range perBead {
+		report.Beads = append(report.Beads, *bc)
+	}
+	sort.Slice(report.Beads, func(i, j int) bool {
+		if report.Beads[i].Beats != report.Beads[j].Beats {
+			return report.Beads[i].Beats > report.Beads[j].Beats
+		}
+		return report.Beads[i].ID < report.Beads[j].ID
+	})
+
+	// Trend: the last periods months or weeks, oldest first
+	report.Trend = make([]CoveragePeriod, periods)
+	index := make(map[string]int, periods)
+	start := periodStart(now.UTC(), by)
+	for i := 0; i < periods; i++ {
+		p := start
+		if by == "week" {
+			p = p.AddDate(0, 0, -7*(periods-1-i))
+		} else {
+			p = p.AddDate(0, -(periods - 1 - i), 0)
+		}
+		label := periodLabel(p, by)
+		report.Trend[i].Period = label
+		index[label] = i
+	}
+	for _, b := range all {
+		i, ok := index[periodLabel(periodStart(b.CreatedAt.UTC(), by), by)]
+		if !ok {
+			continue
+		}
+		report.Trend[i].Beats++
+		if len(b.LinkedBeads) > 0 {
+			report.Trend[i].Linked++
+		}
+	}
+	for i := range report.Trend {
+		if p := &report.Trend[i]; p.Beats > 0 {
+			p.Rate = float64(p.Linked) / float64(p.Beats)
+		}
+	}
+	return report
+}
+
+// periodStart truncates t to the start of its month or ISO week.
+func periodStart(t time.Time, by string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if by == "week" {
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day.AddDate(0, 0, 1-day.Day())
+}
+
+// periodLabel names a period: 2024-01 or 2024-W03.
+func periodLabel(t time.Time, by string) string {
+	if by == "week" {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01")
+}
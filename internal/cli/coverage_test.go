@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+func TestCoverage(t *testing.T) {
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC) // A Friday, in ISO week 12
+	clock.Set(now)
+	defer clock.Reset()
+
+	s, setBeads := beadsFixture(t)
+	setBeads(`{"id":"bd-1","title":"Fix login","status":"open"}
+{"id":"bd-2","title":"Search","status":"closed"}
+{"id":"bd-3","title":"Billing export","status":"in_progress"}
+{"id":"bd-4","title":"Old cleanup","status":"closed"}
+`)
+	for _, b := range []*beat.Beat{
+		{ID: "beat-20260318-001", CreatedAt: now.AddDate(0, 0, -2), LinkedBeads: linked("bd-1")},
+		{ID: "beat-20260319-001", CreatedAt: now.AddDate(0, 0, -1), LinkedBeads: linked("bd-1", "bd-2")},
+		{ID: "beat-20260302-001", CreatedAt: now.AddDate(0, 0, -18)},
+		{ID: "beat-20260210-001", CreatedAt: now.AddDate(0, 0, -38), LinkedBeads: linked("bd-2")},
+		{ID: "beat-20260211-001", CreatedAt: now.AddDate(0, 0, -37)},
+		{ID: "beat-20250601-001", CreatedAt: now.AddDate(0, -9, 0), LinkedBeads: linked("bd-1")}, // Before the trend
+	} {
+		b.UpdatedAt, b.Content = b.CreatedAt, b.ID
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewHumanCLI(s)
+	coverage := func(opts CoverageOptions) CoverageReport {
+		t.Helper()
+		var buf bytes.Buffer
+		SetJSONOutput(&buf)
+		defer SetJSONOutput(nil)
+		opts.JSON = true
+		if err := c.Coverage(opts); err != nil {
+			t.Fatal(err)
+		}
+		var r CoverageReport
+		if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+			t.Fatalf("invalid output %s: %v", buf.String(), err)
+		}
+		return r
+	}
+
+	r := coverage(CoverageOptions{})
+	if r.Total != 6 || r.Linked != 4 || r.Orphaned != 2 || r.Rate != 4.0/6 {
+		t.Errorf("totals = %d linked of %d, %d orphaned, rate %v", r.Linked, r.Total, r.Orphaned, r.Rate)
+	}
+	wantBeads := []BeadCoverage{
+		{ID: "bd-1", Title: "Fix login", Status: "open", Beats: 3, Latest: "2026-03-19T09:00:00Z"},
+		{ID: "bd-2", Title: "Search", Status: "closed", Beats: 2, Latest: "2026-03-19T09:00:00Z"},
+	}
+	if len(r.Beads) != len(wantBeads) || r.Beads[0] != wantBeads[0] || r.Beads[1] != wantBeads[1] {
+		t.Errorf("beads = %+v, want %+v", r.Beads, wantBeads)
+	}
+	// Only open beads count as missing context; closed ones are done with.
+	if len(r.Uncovered) != 1 || r.Uncovered[0].ID != "bd-3" || r.Provider == "" {
+		t.Errorf("uncovered in %q = %+v, want only bd-3", r.Provider, r.Uncovered)
+	}
+
+	monthly := []CoveragePeriod{
+		{Period: "2025-10"}, {Period: "2025-11"}, {Period: "2025-12"}, {Period: "2026-01"},
+		{Period: "2026-02", Beats: 2, Linked: 1, Rate: 0.5},
+		{Period: "2026-03", Beats: 3, Linked: 2, Rate: 2.0 / 3},
+	}
+	if len(r.Trend) != len(monthly) {
+		t.Fatalf("monthly trend = %+v", r.Trend)
+	}
+	for i, p := range monthly {
+		if r.Trend[i] != p {
+			t.Errorf("monthly trend[%d] = %+v, want %+v", i, r.Trend[i], p)
+		}
+	}
+
+	r = coverage(CoverageOptions{By: "week", Periods: 3})
+	weekly := []CoveragePeriod{
+		{Period: "2026-W10", Beats: 1},
+		{Period: "2026-W11"},
+		{Period: "2026-W12", Beats: 2, Linked: 2, Rate: 1},
+	}
+	if len(r.Trend) != len(weekly) {
+		t.Fatalf("weekly trend = %+v", r.Trend)
+	}
+	for i, p := range weekly {
+		if r.Trend[i] != p {
+			t.Errorf("weekly trend[%d] = %+v, want %+v", i, r.Trend[i], p)
+		}
+	}
+
+	if err := c.Coverage(CoverageOptions{By: "day"}); err == nil {
+		t.Error("--by day accepted")
+	}
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/pipeline"
+)
+
+// DigestKind marks a digest beat in impetus.meta["kind"].
+const DigestKind = "digest"
+
+// llmGenerate runs a prompt through the LLM configured for session
+// summaries and returns the model used; replaced in tests.
+var llmGenerate = pipeline.Generate
+
+// DigestOptions configures bt digest.
+type DigestOptions struct {
+	Date     string // One day, YYYY-MM-DD, "today" or "yesterday"
+	Since    string // Every complete day from this date to yesterday
+	Commit   bool   // Store the digests as beats (default: preview)
+	MinBeats int    // Days with fewer beats get no digest
+	Format   string // "text" (default) or FormatHTML
+	Output   string // File for the HTML page (default: stdout)
+	JSON     bool
+}
+
+// DigestDay is the digest of one calendar day in the display time zone.
+type DigestDay struct {
+	Day     string   `json:"day"`
+	Sources []string `json:"sources"`
+	Content string   `json:"content,omitempty"`
+	Model   string   `json:"model,omitempty"` // Empty when the LLM was unavailable
+	ID      string   `json:"id,omitempty"`    // The digest beat, once stored
+	Skipped string   `json:"skipped,omitempty"`
+}
+
+// digestDays resolves the days a digest run covers.
+func digestDays(opts DigestOptions, now time.Time) ([]string, error) {
+	today := now.In(DisplayLocation)
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	switch {
+	case opts.Date == "today":
+		return []string{today.Format("2006-01-02")}, nil
+	case opts.Date == "yesterday":
+		return []string{yesterday}, nil
+	case opts.Date != "":
+		if _, err := time.ParseInLocation("2006-01-02", opts.Date, DisplayLocation); err != nil {
+			return nil, fmt.Errorf("invalid --date %q (use YYYY-MM-DD)", opts.Date)
+		}
+		return []string{opts.Date}, nil
+	case opts.Since != "":
+		since, err := clock.ParseRelative(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		var days []string
+		for d := since.In(DisplayLocation); d.Format("2006-01-02") <= yesterday; d = d.AddDate(0, 0, 1) {
+			days = append(days, d.Format("2006-01-02"))
+		}
+		return days, nil
+	default:
+		return []string{yesterday}, nil
+	}
+}
+
+// digests groups the given days' beats and summarizes each day that has
+// enough of them and no digest yet. With commit, each digest is stored as a
+// beat that references its sources.
+func (c *HumanCLI) digests(days []string, minBeats int, commit bool) ([]DigestDay, error) {
+	if minBeats <= 0 {
+		minBeats = 2
+	}
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read beats: %w", err)
+	}
+	wanted := make(map[string]bool, len(days))
+	for _, d := range days {
+		wanted[d] = true
+	}
+	byDay := make(map[string][]beat.Beat)
+	digested := make(map[string]string)
+	for _, b := range beats {
+		day := displayDate(b.CreatedAt)
+		if b.Impetus.Meta["kind"] == DigestKind {
+			digested[b.Impetus.Meta["day"]] = b.ID
+			continue
+		}
+		if wanted[day] {
+			byDay[day] = append(byDay[day], b)
+		}
+	}
+
+	var out []DigestDay
+	llmFailed := false
+	for _, day := range days {
+		sources := byDay[day]
+		sort.SliceStable(sources, func(i, j int) bool { return sources[i].CreatedAt.Before(sources[j].CreatedAt) })
+		d := DigestDay{Day: day, Sources: []string{}}
+		for _, b := range sources {
+			d.Sources = append(d.Sources, b.ID)
+		}
+		if id := digested[day]; id != "" {
+			d.ID, d.Skipped = id, "already digested"
+			out = append(out, d)
+			continue
+		}
+		if len(sources) < minBeats {
+			d.Skipped = fmt.Sprintf("%d beat(s), fewer than %d", len(sources), minBeats)
+			out = append(out, d)
+			continue
+		}
+
+		var list strings.Builder
+		for _, b := range sources {
+			fmt.Fprintf(&list, "- [%s] (%s) %s\n", b.ID, b.Impetus.Label, truncate(strings.Join(strings.Fields(b.Content), " "), 500))
+		}
+		summary := ""
+		if !llmFailed {
+			prompt := fmt.Sprintf("Write a digest of these notes captured on %s in 3-6 sentences: the main threads, decisions and open questions. Be specific, no preamble:\n\n%s", day, list.String())
+			var err error
+			if summary, d.Model, err = llmGenerate(context.Background(), c.store.Dir(), prompt); err != nil {
+				// Don't retry a down or missing model for every remaining day
+				fmt.Fprintf(os.Stderr, "Warning: summaries unavailable (%v); listing beats instead\n", err)
+				llmFailed, summary, d.Model = true, "", ""
+			}
+		}
+		d.Content = fmt.Sprintf("Digest of %s: %d beat(s)", day, len(sources))
+		if summary != "" {
+			d.Content = summary + "\n\n" + d.Content
+		}
+		d.Content += "\n\n" + strings.TrimSpace(list.String())
+
+		if commit {
+			b, err := c.commit(digestBeat(d, sources[len(sources)-1].CreatedAt), beat.ChannelGenerated)
+			if err != nil {
+				return out, fmt.Errorf("digest for %s: %w", day, err)
+			}
+			d.ID = b.ID
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+// digestBeat is the stored form of a day's digest, dated with its last source
+// so it sorts with the day it covers.
+func digestBeat(d DigestDay, createdAt time.Time) *beat.ProposedBeat {
+	meta := map[string]string{
+		"kind":    DigestKind,
+		"source":  DigestKind,
+		"day":     d.Day,
+		"sources": strings.Join(d.Sources, ","),
+	}
+	if d.Model != "" {
+		meta["summary_model"] = d.Model
+	}
+	refs := make([]beat.Reference, len(d.Sources))
+	for i, id := range d.Sources {
+		refs[i] = beat.Reference{Kind: "beat", Locator: id}
+	}
+	return &beat.ProposedBeat{
+		Content:     d.Content,
+		Impetus:     beat.Impetus{Label: "Daily digest", Raw: d.Day, Meta: meta},
+		References:  refs,
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+		CreatedAt:   &createdAt,
+	}
+}
+
+// Digest previews or, with --commit, stores a digest beat per day. With
+// --format html the digests are also rendered as one page.
+func (c *HumanCLI) Digest(opts DigestOptions) error {
+	if opts.Format != "" && opts.Format != "text" && opts.Format != FormatHTML {
+		return fmt.Errorf("unknown format %q (use text or %s)", opts.Format, FormatHTML)
+	}
+	days, err := digestDays(opts, clock.Now())
+	if err != nil {
+		return err
+	}
+	out, err := c.digests(days, opts.MinBeats, opts.Commit)
+	if opts.Format == FormatHTML && !opts.JSON {
+		if herr := c.writeDigestHTML(out, opts.Output); herr != nil || opts.Output == "" {
+			return errors.Join(err, herr)
+		}
+	}
+	if opts.JSON {
+		if out == nil {
+			out = []DigestDay{}
+		}
+		if jerr := outputJSON(map[string]interface{}{"committed": opts.Commit, "days": out}); jerr != nil {
+			return jerr
+		}
+		return err
+	}
+	for _, d := range out {
+		switch {
+		case d.Skipped != "" && d.ID != "":
+			fmt.Printf("%s: %s (%s)\n", d.Day, d.Skipped, d.ID)
+		case d.Skipped != "":
+			fmt.Printf("%s: skipped, %s\n", d.Day, d.Skipped)
+		case opts.Commit:
+			fmt.Printf("Created digest %s for %s (%d beats)\n", d.ID, d.Day, len(d.Sources))
+		default:
+			fmt.Printf("=== %s (%d beats) ===\n%s\n\n", d.Day, len(d.Sources), d.Content)
+		}
+	}
+	if err == nil && !opts.Commit && len(out) > 0 {
+		fmt.Println("Preview only; run with --commit to store digests as beats")
+	}
+	return err
+}
+
+// DigestYesterday stores yesterday's digest if it has none; the daemon calls
+// it once a day.
+func (c *HumanCLI) DigestYesterday() (*DigestDay, error) {
+	days, err := digestDays(DigestOptions{}, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.digests(days, 0, true)
+	if err != nil || len(out) == 0 {
+		return nil, err
+	}
+	return &out[0], nil
+}
+
+// digestPage renders digests as one page, one section per day. Days that
+// were digested before are shown from their stored beat.
+func (c *HumanCLI) digestPage(days []DigestDay) HTMLPage {
+	page := HTMLPage{Title: "Daily digest"}
+	for _, d := range days {
+		content := d.Content
+		if content == "" && d.ID != "" {
+			if b, err := c.store.Get(d.ID); err == nil {
+				content = b.Content
+			}
+		}
+		if content == "" {
+			continue
+		}
+		meta := fmt.Sprintf("%d beat(s)", len(d.Sources))
+		if d.Model != "" {
+			meta += ", summarized by " + d.Model
+		}
+		page.Sections = append(page.Sections, HTMLSection{Title: d.Day, Meta: meta, Body: markdownHTML(content)})
+	}
+	switch {
+	case len(days) == 1:
+		page.Title += " " + days[0].Day
+	case len(days) > 1:
+		page.Subtitle = fmt.Sprintf("%s to %s", days[0].Day, days[len(days)-1].Day)
+	}
+	return page
+}
+
+// writeDigestHTML writes the digest page to a file, or stdout.
+func (c *HumanCLI) writeDigestHTML(days []DigestDay, path string) error {
+	page := c.digestPage(days)
+	if len(page.Sections) == 0 {
+		return fmt.Errorf("no digests to render")
+	}
+	data, err := page.Render()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d digest(s) to %s\n", len(page.Sections), path)
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestDigests(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	defer func(f func(context.Context, string, string) (string, string, error)) { llmGenerate = f }(llmGenerate)
+	var prompts []string
+	llmGenerate = func(_ context.Context, _, prompt string) (string, string, error) {
+		prompts = append(prompts, prompt)
+		return "Pricing and onboarding dominated.", "test-model", nil
+	}
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(v string) time.Time { ts, _ := time.Parse(time.RFC3339, v); return ts }
+	var beats []*beat.Beat
+	for _, b := range []struct{ id, at, content string }{
+		{"beat-20260310-001", "2026-03-10T09:00:00Z", "pricing call notes"},
+		{"beat-20260310-002", "2026-03-10T17:00:00Z", "onboarding flow idea"},
+		{"beat-20260311-001", "2026-03-11T08:00:00Z", "a lone beat"},
+	} {
+		beats = append(beats, &beat.Beat{ID: b.id, CreatedAt: at(b.at), UpdatedAt: at(b.at), Impetus: beat.Impetus{Label: "Manual entry"}, Content: b.content})
+	}
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewHumanCLI(s)
+	out, err := c.digests([]string{"2026-03-10", "2026-03-11"}, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].ID == "" || out[0].Model != "test-model" || out[1].Skipped == "" {
+		t.Fatalf("digests() = %+v; want the 10th stored and the 11th skipped", out)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "beat-20260310-002") {
+		t.Errorf("prompts = %q", prompts)
+	}
+
+	d, err := s.Get(out[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Impetus.Meta["kind"] != DigestKind || d.Impetus.Meta["day"] != "2026-03-10" || len(d.References) != 2 ||
+		d.References[0].Kind != "beat" || d.References[0].Locator != "beat-20260310-001" {
+		t.Errorf("digest beat = %+v", d)
+	}
+	if !strings.HasPrefix(d.Content, "Pricing and onboarding dominated.") || !d.CreatedAt.Equal(at("2026-03-10T17:00:00Z")) {
+		t.Errorf("digest content %q at %v", d.Content, d.CreatedAt)
+	}
+
+	// A second run finds the digest and does not summarize the day again
+	again, err := c.digests([]string{"2026-03-10"}, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].ID != d.ID || again[0].Skipped == "" || len(prompts) != 1 {
+		t.Errorf("second run = %+v", again)
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// DoctorOptions configures bt doctor.
+type DoctorOptions struct {
+	Fix  bool // Quarantine malformed lines
+	JSON bool
+}
+
+// DoctorReport is the result of checking a store.
+type DoctorReport struct {
+	Store        string          `json:"store"`
+	Beats        int             `json:"beats"`
+	BadLines     []store.BadLine `json:"bad_lines"`
+	DuplicateIDs []string        `json:"duplicate_ids"` // IDs held by more than one beat
+	Quarantined  int             `json:"quarantined"`   // Lines moved by this run
+	Quarantine   string          `json:"quarantine,omitempty"`
+	Healthy      bool            `json:"healthy"`
+}
+
+// Doctor checks beats.jsonl for lines that are not valid beats and for
+// duplicate IDs. With Fix, the malformed lines are moved to quarantine.jsonl.
+func (c *HumanCLI) Doctor(opts DoctorOptions) error {
+	beats, bad, err := c.store.ReadAllTolerant()
+	if err != nil {
+		return err
+	}
+	report := DoctorReport{
+		Store:        c.store.Path(),
+		Beats:        len(beats),
+		BadLines:     bad,
+		DuplicateIDs: []string{},
+	}
+	if report.BadLines == nil {
+		report.BadLines = []store.BadLine{}
+	}
+	seen := make(map[string]int)
+	for _, b := range beats {
+		seen[b.ID]++
+	}
+	for id, n := range seen {
+		if n > 1 {
+			report.DuplicateIDs = append(report.DuplicateIDs, id)
+		}
+	}
+	sort.Strings(report.DuplicateIDs)
+
+	if opts.Fix && len(bad) > 0 {
+		moved, err := c.store.Quarantine()
+		if err != nil {
+			return fmt.Errorf("failed to quarantine: %w", err)
+		}
+		report.Quarantined = len(moved)
+		report.Quarantine = filepath.Join(c.store.Dir(), store.QuarantineFile)
+	}
+	report.Healthy = len(report.BadLines) == report.Quarantined && len(report.DuplicateIDs) == 0
+
+	if opts.JSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(report)
+	}
+	if unfixed := len(report.BadLines) - report.Quarantined; unfixed > 0 {
+		return fmt.Errorf("%d malformed line(s) left in %s", unfixed, report.Store)
+	}
+	return nil
+}
+
+func printDoctorReport(r DoctorReport) {
+	fmt.Printf("Store: %s (%d beats)\n", r.Store, r.Beats)
+	if len(r.BadLines) == 0 {
+		fmt.Println("  ok  every line is a valid beat")
+	} else {
+		fmt.Printf("  !!  %d malformed line(s):\n", len(r.BadLines))
+		for _, b := range r.BadLines {
+			fmt.Printf("        line %d: %s\n", b.Line, b.Error)
+			fmt.Printf("          %s\n", truncate(b.Text, 70))
+		}
+		if r.Quarantined > 0 {
+			fmt.Printf("      moved %d line(s) to %s\n", r.Quarantined, r.Quarantine)
+		} else {
+			fmt.Println("      run 'bt doctor --fix' to move them to quarantine.jsonl")
+		}
+	}
+	if len(r.DuplicateIDs) == 0 {
+		fmt.Println("  ok  beat IDs are unique")
+	} else {
+		fmt.Printf("  !!  %d ID(s) used by more than one beat: ", len(r.DuplicateIDs))
+		for i, id := range r.DuplicateIDs {
+			if i > 0 {
+				fmt.Print(", ")
+			}
+			fmt.Print(id)
+		}
+		fmt.Println()
+	}
+}
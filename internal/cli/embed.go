@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/embeddings"
+)
+
+// EmbedOptions contains options for the embed command.
+type EmbedOptions struct {
+	Model     string  // Ollama embedding model (default: the store's model)
+	BatchSize int     // Beats per request
+	Rate      float64 // Maximum beats embedded per second (0 = unlimited)
+	Migrate   string  // Re-embed everything with this model and switch over
+	KeepOld   bool    // Keep the previous index after a migration
+	Compact   bool    // Drop vectors of deleted or re-embedded beats
+	Export    string  // Write vectors to a .jsonl, .npy or .parquet file
+}
+
+// Embed computes missing embeddings in batches with a progress bar.
+// Progress is saved after every batch; Ctrl-C stops cleanly and a rerun resumes.
+func (c *HumanCLI) Embed(opts EmbedOptions) error {
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+
+	embStore, err := embeddings.NewStore(c.store.Dir())
+	if err != nil {
+		return fmt.Errorf("failed to init embedding store: %w", err)
+	}
+
+	if opts.Compact {
+		return c.embedCompact(beats, embStore)
+	}
+	if opts.Export != "" {
+		return c.embedExport(beats, embStore, opts.Export)
+	}
+
+	if opts.Migrate == "" {
+		if err := embStore.SetModel(opts.Model); err != nil {
+			return err
+		}
+	}
+
+	// Query with the store's model, or the target model when migrating
+	ollama := embeddings.NewOllamaClient()
+	ollama.SetModel(embStore.Model())
+	ollama.SetModel(opts.Migrate)
+	if !ollama.IsAvailable() {
+		return fmt.Errorf("ollama not available (is it running?)")
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 32
+	}
+	var interval time.Duration
+	if opts.Rate > 0 {
+		interval = time.Duration(float64(opts.BatchSize) / opts.Rate * float64(time.Second))
+	}
+	computeOpts := embeddings.ComputeOptions{
+		BatchSize: opts.BatchSize,
+		Interval:  interval,
+		Progress:  printProgress,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if opts.Migrate != "" {
+		return c.embedMigrate(ctx, beats, ollama, computeOpts, opts.KeepOld)
+	}
+
+	missing := 0
+	for _, b := range beats {
+		if !embStore.Has(b.ID) {
+			missing++
+		}
+	}
+	if missing == 0 {
+		fmt.Printf("All %d beats already have embeddings.\n", len(beats))
+		return nil
+	}
+	fmt.Printf("Embedding %d beats with %s (batch %d)...\n", missing, ollama.Model(), opts.BatchSize)
+
+	result, err := embeddings.ComputeMissingWithOptions(ctx, beats, embStore, ollama, computeOpts)
+	fmt.Fprintln(os.Stderr)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	if err != nil {
+		fmt.Println("Interrupted. Progress saved; run 'beats embed' again to resume.")
+	}
+
+	fmt.Printf("Done: %d computed, %d skipped, %d errors\n", result.Computed, result.Skipped, result.Errors)
+	fmt.Printf("Coverage: %d/%d (%.1f%%)\n", embStore.Count(), len(beats), embStore.Coverage(len(beats)))
+	return nil
+}
+
+func (c *HumanCLI) embedMigrate(ctx context.Context, beats []beat.Beat, ollama *embeddings.OllamaClient, computeOpts embeddings.ComputeOptions, keepOld bool) error {
+	fmt.Printf("Re-embedding %d beats with %s into a parallel index...\n", len(beats), ollama.Model())
+
+	result, err := embeddings.Migrate(ctx, c.store.Dir(), beats, ollama, embeddings.MigrateOptions{
+		Compute: computeOpts,
+		KeepOld: keepOld,
+	})
+	fmt.Fprintln(os.Stderr)
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("Interrupted. The current index is untouched; rerun to resume the migration.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	from := result.OldModel
+	if from == "" {
+		from = "(none)"
+	}
+	fmt.Printf("Verified %d embeddings (%d dimensions)\n", result.Verified, result.Dimensions)
+	fmt.Printf("Switched embedding index: %s -> %s\n", from, result.NewModel)
+	if keepOld {
+		fmt.Println("Previous index kept as embeddings.prev.*")
+	}
+	return nil
+}
+
+func (c *HumanCLI) embedCompact(beats []beat.Beat, embStore *embeddings.Store) error {
+	keep := make(map[string]bool, len(beats))
+	for _, b := range beats {
+		keep[b.ID] = true
+	}
+
+	result, err := embStore.Compact(keep)
+	if err != nil {
+		return fmt.Errorf("failed to compact embeddings: %w", err)
+	}
+
+	fmt.Printf("Compacted embeddings: kept %d, removed %d orphaned\n", result.Kept, result.Removed)
+	fmt.Printf("Size: %s -> %s (reclaimed %s)\n",
+		formatBytes(result.BytesBefore), formatBytes(result.BytesAfter), formatBytes(result.BytesBefore-result.BytesAfter))
+	return nil
+}
+
+// embedExport writes every stored vector with its beat ID and metadata.
+func (c *HumanCLI) embedExport(beats []beat.Beat, embStore *embeddings.Store, path string) error {
+	if _, err := embeddings.ExportFormat(path); err != nil {
+		return err
+	}
+	if embStore.Count() == 0 {
+		return fmt.Errorf("no embeddings to export (run 'bt embed' first)")
+	}
+
+	rows, err := embeddings.ExportRows(beats, embStore)
+	if err != nil {
+		return fmt.Errorf("failed to read embeddings: %w", err)
+	}
+	if err := embeddings.Export(path, rows, embStore.Meta()); err != nil {
+		return fmt.Errorf("failed to export embeddings: %w", err)
+	}
+
+	fmt.Printf("Exported %d vectors (%d beats, %s, %d dims) to %s\n",
+		len(rows), embStore.Count(), embStore.Model(), embStore.Dimensions(), path)
+	if strings.HasSuffix(strings.ToLower(path), ".npy") {
+		fmt.Printf("Row metadata: %s\n", embeddings.NPYSidecarPath(path))
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// printProgress renders a single-line progress bar on stderr.
+func printProgress(done, total int) {
+	const width = 30
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+	fmt.Fprintf(os.Stderr, "\r[%s] %d/%d (%.0f%%)", bar, done, total, float64(done)/float64(total)*100)
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/topics"
+)
+
+// Defaults for the entity co-occurrence graph.
+const (
+	DefaultEntityWindow = 30 * 24 * time.Hour
+	entityHalfLife      = 30 * 24 * time.Hour // Weight of a co-occurrence halves every 30 days
+	entityPairBeats     = 5                   // Most recent beat IDs kept per pair
+)
+
+// EntityGraphOptions configures bt entities graph.
+type EntityGraphOptions struct {
+	Window   time.Duration // Pairs first seen within it are emerging
+	MinCount int           // Pairs seen fewer times are left out (emerging ones too)
+	Max      int           // Most pairs drawn or listed
+	Format   string        // json or mermaid
+	Output   string        // File to write (default stdout)
+	Now      time.Time
+}
+
+// GraphEntity is an entity in the graph with the number of beats naming it.
+type GraphEntity struct {
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	Beats    int    `json:"beats"`
+}
+
+// EntityPair is two entities named in the same beats.
+type EntityPair struct {
+	A         string    `json:"a"`
+	B         string    `json:"b"`
+	Count     int       `json:"count"`    // Beats naming both
+	Strength  float64   `json:"strength"` // Count over the beats naming either (0-1)
+	Weight    float64   `json:"weight"`   // Count with each beat decayed by age
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Emerging  bool      `json:"emerging"` // First seen within the window
+	BeatIDs   []string  `json:"beat_ids"` // Most recent first
+}
+
+// EntityGraph is the co-occurrence graph of a store's entities.
+type EntityGraph struct {
+	Window   string        `json:"window"`
+	Beats    int           `json:"beats"` // Beats naming at least two entities
+	Entities []GraphEntity `json:"entities"`
+	Pairs    []EntityPair  `json:"pairs"`    // Strongest first
+	Emerging []EntityPair  `json:"emerging"` // New pairings, most frequent first
+}
+
+// BuildEntityGraph counts which entities are named together. Labels are
+// compared case-insensitively and shown in their most common spelling.
+// Digest beats repeat their sources and are left out.
+func BuildEntityGraph(s *store.JSONLStore, opts EntityGraphOptions) (*EntityGraph, error) {
+	if opts.Window <= 0 {
+		opts.Window = DefaultEntityWindow
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = 2
+	}
+	if opts.Max <= 0 {
+		opts.Max = 40
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(beats, func(i, j int) bool { return beats[i].CreatedAt.After(beats[j].CreatedAt) })
+
+	type entityInfo struct {
+		beats      int
+		spellings  map[string]int
+		categories map[string]int
+	}
+	entities := make(map[string]*entityInfo)
+	pairs := make(map[[2]string]*EntityPair)
+	g := &EntityGraph{Window: topics.FormatWindow(opts.Window), Entities: []GraphEntity{}, Pairs: []EntityPair{}, Emerging: []EntityPair{}}
+
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] == DigestKind {
+			continue
+		}
+		var keys []string
+		seen := make(map[string]bool)
+		for _, e := range b.Entities {
+			label := strings.TrimSpace(e.Label)
+			key := strings.ToLower(label)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+			info := entities[key]
+			if info == nil {
+				info = &entityInfo{spellings: map[string]int{}, categories: map[string]int{}}
+				entities[key] = info
+			}
+			info.beats++
+			info.spellings[label]++
+			info.categories[e.Category]++
+		}
+		if len(keys) < 2 {
+			continue
+		}
+		g.Beats++
+		sort.Strings(keys)
+		decay := math.Exp2(-opts.Now.Sub(b.CreatedAt).Hours() / entityHalfLife.Hours())
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				k := [2]string{keys[i], keys[j]}
+				p := pairs[k]
+				if p == nil {
+					// Beats are newest first, so the first one seen is the latest
+					p = &EntityPair{A: k[0], B: k[1], LastSeen: b.CreatedAt}
+					pairs[k] = p
+				}
+				p.Count++
+				p.Weight += decay
+				p.FirstSeen = b.CreatedAt
+				if len(p.BeatIDs) < entityPairBeats {
+					p.BeatIDs = append(p.BeatIDs, b.ID)
+				}
+			}
+		}
+	}
+
+	display := func(key string) string { return mostCommon(entities[key].spellings) }
+	since := opts.Now.Add(-opts.Window)
+	var kept []EntityPair
+	for _, p := range pairs {
+		if p.Count < opts.MinCount {
+			continue
+		}
+		union := entities[p.A].beats + entities[p.B].beats - p.Count
+		p.Strength = math.Round(float64(p.Count)/float64(union)*1000) / 1000
+		p.Weight = math.Round(p.Weight*1000) / 1000
+		p.Emerging = !p.FirstSeen.Before(since)
+		p.A, p.B = display(p.A), display(p.B)
+		kept = append(kept, *p)
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Weight != kept[j].Weight {
+			return kept[i].Weight > kept[j].Weight
+		}
+		if kept[i].Count != kept[j].Count {
+			return kept[i].Count > kept[j].Count
+		}
+		return kept[i].A+kept[i].B < kept[j].A+kept[j].B
+	})
+	if len(kept) > opts.Max {
+		kept = kept[:opts.Max]
+	}
+	g.Pairs = append(g.Pairs, kept...)
+	for _, p := range kept {
+		if p.Emerging {
+			g.Emerging = append(g.Emerging, p)
+		}
+	}
+	sort.SliceStable(g.Emerging, func(i, j int) bool { return g.Emerging[i].Count > g.Emerging[j].Count })
+
+	// Entities in the graph, most mentioned first
+	inGraph := make(map[string]bool)
+	for _, p := range g.Pairs {
+		inGraph[strings.ToLower(p.A)], inGraph[strings.ToLower(p.B)] = true, true
+	}
+	for key := range inGraph {
+		info := entities[key]
+		g.Entities = append(g.Entities, GraphEntity{Label: display(key), Category: mostCommon(info.categories), Beats: info.beats})
+	}
+	sort.Slice(g.Entities, func(i, j int) bool {
+		if g.Entities[i].Beats != g.Entities[j].Beats {
+			return g.Entities[i].Beats > g.Entities[j].Beats
+		}
+		return g.Entities[i].Label < g.Entities[j].Label
+	})
+	return g, nil
+}
+
+// mostCommon returns the most frequent key, the smallest on a tie.
+func mostCommon(counts map[string]int) string {
+	best, n := "", 0
+	for k, c := range counts {
+		if c > n || (c == n && k < best) {
+			best, n = k, c
+		}
+	}
+	return best
+}
+
+// Mermaid draws the graph as a flowchart: an edge per pair labelled with its
+// count, dashed for emerging pairs.
+func (g *EntityGraph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	// Labels like "C++" and "C#" would collide as identifiers, so number them
+	nodes := make(map[string]string, len(g.Entities))
+	for i, e := range g.Entities {
+		nodes[e.Label] = fmt.Sprintf("ent%d", i+1)
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", nodes[e.Label], mermaidText(fmt.Sprintf("%s (%d)", e.Label, e.Beats)))
+	}
+	for _, p := range g.Pairs {
+		edge := "---"
+		if p.Emerging {
+			edge = "-.-"
+		}
+		fmt.Fprintf(&sb, "  %s %s|%d| %s\n", nodes[p.A], edge, p.Count, nodes[p.B])
+	}
+	return sb.String()
+}
+
+// EntityGraph prints the co-occurrence graph as JSON or Mermaid.
+func (c *HumanCLI) EntityGraph(opts EntityGraphOptions) error {
+	if opts.Format == "" {
+		opts.Format = "mermaid"
+	}
+	if opts.Format != "mermaid" && opts.Format != "json" {
+		return fmt.Errorf("invalid --format %q (use mermaid or json)", opts.Format)
+	}
+	g, err := BuildEntityGraph(c.store, opts)
+	if err != nil {
+		return err
+	}
+	if len(g.Pairs) == 0 {
+		fmt.Fprintf(os.Stderr, "No entities are named together in %d or more beats.\n", max(opts.MinCount, 2))
+	}
+	if opts.Format == "json" && opts.Output == "" {
+		return outputJSON(g)
+	}
+
+	out := g.Mermaid()
+	if opts.Format == "json" {
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = string(data) + "\n"
+	}
+	if opts.Output == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(opts.Output, []byte(out), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote entity graph to %s\n", opts.Output)
+	return nil
+}
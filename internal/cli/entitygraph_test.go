@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestEntityGraph(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	mk := func(id string, daysAgo int, entities ...string) *beat.Beat {
+		at := now.AddDate(0, 0, -daysAgo)
+		b := &beat.Beat{ID: id, CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Manual entry"}, Content: id}
+		for _, e := range entities {
+			b.Entities = append(b.Entities, beat.Entity{Label: e, Category: "concept"})
+		}
+		return b
+	}
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := mk("beat-20260319-900", 1, "pricing", "onboarding")
+	digest.Impetus.Meta = map[string]string{"kind": DigestKind}
+	if err := s.AppendBulk([]*beat.Beat{
+		mk("beat-20260101-001", 78, "Pricing", "Churn"), // Shown as the more common "pricing"
+		mk("beat-20260102-001", 77, "pricing", "churn", "pricing"),
+		mk("beat-20260103-001", 76, "pricing", "churn"),
+		mk("beat-20260315-001", 5, "Pricing", "Onboarding"),
+		mk("beat-20260318-001", 2, "pricing", "onboarding"),
+		mk("beat-20260319-001", 1, "onboarding", "Churn"), // Seen once: left out
+		digest,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := BuildEntityGraph(s, EntityGraphOptions{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Pairs) != 2 || g.Beats != 6 {
+		t.Fatalf("pairs = %+v (%d beats); want pricing-churn and pricing-onboarding", g.Pairs, g.Beats)
+	}
+	// Two recent beats outweigh three old ones
+	recent, old := g.Pairs[0], g.Pairs[1]
+	if recent.A != "onboarding" || recent.B != "pricing" || !recent.Emerging || recent.BeatIDs[0] != "beat-20260318-001" {
+		t.Errorf("strongest pair = %+v", recent)
+	}
+	if old.Count != 3 || old.Emerging || old.Strength != 0.5 || !old.FirstSeen.Equal(now.AddDate(0, 0, -78)) {
+		t.Errorf("old pair = %+v; want 3 of the 6 beats naming either", old)
+	}
+	if len(g.Emerging) != 1 || len(g.Entities) != 3 || g.Entities[0].Label != "pricing" || g.Entities[0].Beats != 5 {
+		t.Errorf("emerging %+v, entities %+v", g.Emerging, g.Entities)
+	}
+
+	m := g.Mermaid()
+	if !strings.Contains(m, `ent1["pricing (5)"]`) || !strings.Contains(m, "ent3 -.-|2| ent1") || !strings.Contains(m, "ent2 ---|3| ent1") {
+		t.Errorf("Mermaid() =\n%s", m)
+	}
+}
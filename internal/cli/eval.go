@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// DefaultEvalK is the rank cutoff retrieval metrics are computed at.
+const DefaultEvalK = 10
+
+// EvalModes are the retrieval modes bt eval search compares.
+var EvalModes = []string{"keyword", "semantic", "hybrid"}
+
+// EvalCase is one query and the beats a good search returns for it.
+type EvalCase struct {
+	Query    string   `yaml:"query" json:"query"`
+	Expected []string `yaml:"expected" json:"expected"`
+}
+
+// EvalCases is a cases file:
+//
+//	k: 10
+//	cases:
+//	  - query: pricing strategy
+//	    expected: [beat-20260102-001, beat-20260301-004]
+type EvalCases struct {
+	K     int        `yaml:"k"`
+	Cases []EvalCase `yaml:"cases"`
+}
+
+// LoadEvalCases reads a cases file.
+func LoadEvalCases(path string) (*EvalCases, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases EvalCases
+	if err := yaml.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("invalid cases file: %w", err)
+	}
+	for i, c := range cases.Cases {
+		if strings.TrimSpace(c.Query) == "" || len(c.Expected) == 0 {
+			return nil, fmt.Errorf("case %d needs a query and expected beat IDs", i+1)
+		}
+	}
+	if len(cases.Cases) == 0 {
+		return nil, fmt.Errorf("no cases in %s", path)
+	}
+	return &cases, nil
+}
+
+// EvalCaseResult is how one mode did on one case.
+type EvalCaseResult struct {
+	Query     string   `json:"query"`
+	Precision float64  `json:"precision"`
+	Recall    float64  `json:"recall"`
+	RR        float64  `json:"reciprocal_rank"` // 1/rank of the first expected beat, 0 if none in the top k
+	Missed    []string `json:"missed,omitempty"`
+}
+
+// EvalModeResult is one retrieval mode over every case.
+type EvalModeResult struct {
+	Mode       string           `json:"mode"`
+	Backend    string           `json:"backend"` // What ran: keyword, semantic or tfidf (offline fallback)
+	Precision  float64          `json:"precision"`
+	Recall     float64          `json:"recall"`
+	MRR        float64          `json:"mrr"`
+	DurationMS int64            `json:"duration_ms"`
+	Cases      []EvalCaseResult `json:"cases"`
+}
+
+// EvalReport compares retrieval modes on a set of cases.
+type EvalReport struct {
+	K       int              `json:"k"`
+	Cases   int              `json:"cases"`
+	Unknown []string         `json:"unknown_ids,omitempty"` // Expected IDs not in the store
+	Modes   []EvalModeResult `json:"modes"`
+}
+
+// EvalSearch runs every case through each mode and scores the top k
+// results: precision@k, recall@k and mean reciprocal rank. Semantic and
+// hybrid use the scoring settings in effect, so a change to them shows up
+// as a change in the numbers.
+func EvalSearch(s *store.JSONLStore, cases *EvalCases, k int, modes []string) (*EvalReport, error) {
+	if k <= 0 {
+		k = cases.K
+	}
+	if k <= 0 {
+		k = DefaultEvalK
+	}
+	if len(modes) == 0 {
+		modes = EvalModes
+	}
+	scoring, err := store.LoadScoringConfig(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]beat.Beat, len(beats))
+	for _, b := range beats {
+		byID[b.ID] = b
+	}
+
+	report := &EvalReport{K: k, Cases: len(cases.Cases), Modes: []EvalModeResult{}}
+	seen := make(map[string]bool)
+	for _, c := range cases.Cases {
+		for _, id := range c.Expected {
+			if _, ok := byID[id]; !ok && !seen[id] {
+				seen[id] = true
+				report.Unknown = append(report.Unknown, id)
+			}
+		}
+	}
+
+	for _, mode := range modes {
+		result := EvalModeResult{Mode: mode}
+		start := time.Now()
+		for _, c := range cases.Cases {
+			ranked, backend, err := evalRank(s, byID, mode, c.Query, k, scoring)
+			if err != nil {
+				return nil, fmt.Errorf("%s search for %q: %w", mode, c.Query, err)
+			}
+			result.Backend = backend
+			cr := scoreCase(c, ranked, k)
+			result.Precision += cr.Precision
+			result.Recall += cr.Recall
+			result.MRR += cr.RR
+			result.Cases = append(result.Cases, cr)
+		}
+		result.DurationMS = time.Since(start).Milliseconds()
+		n := float64(len(cases.Cases))
+		result.Precision /= n
+		result.Recall /= n
+		result.MRR /= n
+		report.Modes = append(report.Modes, result)
+	}
+	return report, nil
+}
+
+// evalRank returns the top k beat IDs for a query in one mode, and what
+// backend produced them.
+func evalRank(s *store.JSONLStore, byID map[string]beat.Beat, mode, query string, k int, scoring store.ScoringConfig) ([]string, string, error) {
+	switch mode {
+	case "keyword":
+		results, err := s.SearchWithScoring(query, k, scoring)
+		return resultIDs(results), "keyword", err
+	case "semantic":
+		out, err := store.HybridSearchWithScoring(s, query, k, true, scoring)
+		if err != nil {
+			return nil, "", err
+		}
+		return resultIDs(out.Results), out.Mode, nil
+	case "hybrid":
+		// The fusion --robot-context retrieves with
+		semantic, err := store.HybridSearchWithScoring(s, query, ragCandidates, true, scoring)
+		if err != nil {
+			return nil, "", err
+		}
+		keyword, err := s.SearchWithScoring(query, ragCandidates, scoring)
+		if err != nil {
+			return nil, "", err
+		}
+		scores := fuseScores(semantic.Results, keyword)
+		ids := make([]string, 0, len(scores))
+		for id := range scores {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if scores[ids[i]] != scores[ids[j]] {
+				return scores[ids[i]] > scores[ids[j]]
+			}
+			return byID[ids[i]].CreatedAt.After(byID[ids[j]].CreatedAt)
+		})
+		if len(ids) > k {
+			ids = ids[:k]
+		}
+		return ids, "keyword+" + semantic.Mode, nil
+	}
+	return nil, "", fmt.Errorf("unknown mode %q (want %s)", mode, strings.Join(EvalModes, ", "))
+}
+
+func resultIDs(results []beat.SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// scoreCase computes precision@k, recall@k and the reciprocal rank of the
+// first expected beat.
+func scoreCase(c EvalCase, ranked []string, k int) EvalCaseResult {
+	if len(ranked) > k {
+		ranked = ranked[:k]
+	}
+	expected := make(map[string]bool, len(c.Expected))
+	for _, id := range c.Expected {
+		expected[id] = true
+	}
+	r := EvalCaseResult{Query: c.Query}
+	found := make(map[string]bool)
+	for i, id := range ranked {
+		if expected[id] && !found[id] {
+			found[id] = true
+			if r.RR == 0 {
+				r.RR = 1 / float64(i+1)
+			}
+		}
+	}
+	r.Precision = float64(len(found)) / float64(k)
+	r.Recall = float64(len(found)) / float64(len(expected))
+	for _, id := range c.Expected {
+		if !found[id] {
+			r.Missed = append(r.Missed, id)
+		}
+	}
+	return r
+}
+
+// EvalSearch prints how each retrieval mode does on the cases in a file.
+func (c *HumanCLI) EvalSearch(casesPath string, k int, modes []string, jsonOut bool) error {
+	cases, err := LoadEvalCases(casesPath)
+	if err != nil {
+		return err
+	}
+	report, err := EvalSearch(c.store, cases, k, modes)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(report)
+	}
+
+	fmt.Printf("%d case(s), top %d\n\n", report.Cases, report.K)
+	fmt.Printf("  %-10s %8s %8s %8s %9s  %s\n", "MODE", fmt.Sprintf("P@%d", report.K), fmt.Sprintf("R@%d", report.K), "MRR", "TIME", "BACKEND")
+	for _, m := range report.Modes {
+		fmt.Printf("  %-10s %8.3f %8.3f %8.3f %7dms  %s\n", m.Mode, m.Precision, m.Recall, m.MRR, m.DurationMS, m.Backend)
+	}
+
+	// Cases every mode failed outright are the ones worth a look first
+	var failed []string
+	for i, cs := range cases.Cases {
+		missedAll := true
+		for _, m := range report.Modes {
+			missedAll = missedAll && m.Cases[i].RR == 0
+		}
+		if missedAll {
+			failed = append(failed, cs.Query)
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Printf("\nNo expected beat in the top %d for any mode:\n", report.K)
+		for _, q := range failed {
+			fmt.Printf("  %s\n", q)
+		}
+	}
+	if len(report.Unknown) > 0 {
+		fmt.Printf("\nWarning: expected beats not in this store: %s\n", strings.Join(report.Unknown, ", "))
+	}
+	return nil
+}
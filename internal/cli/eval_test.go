@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestScoreCase(t *testing.T) {
+	c := EvalCase{Query: "q", Expected: []string{"a", "b"}}
+	r := scoreCase(c, []string{"x", "a", "y", "z"}, 4)
+	if r.Precision != 0.25 || r.Recall != 0.5 || r.RR != 0.5 || len(r.Missed) != 1 || r.Missed[0] != "b" {
+		t.Errorf("scoreCase = %+v", r)
+	}
+	if r := scoreCase(c, []string{"x", "y", "a"}, 2); r.RR != 0 || r.Recall != 0 {
+		t.Errorf("expected beat past k counted: %+v", r)
+	}
+}
+
+func TestEvalSearch(t *testing.T) {
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, b := range []beat.Beat{
+		{ID: "beat-20260301-001", Content: "Pricing strategy for the enterprise tier", CreatedAt: now},
+		{ID: "beat-20260301-002", Content: "Onboarding friction in the signup flow", CreatedAt: now},
+		{ID: "beat-20260301-003", Content: "Lunch with the team", CreatedAt: now},
+	} {
+		if err := s.Append(&b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "cases.yaml")
+	cases := "k: 2\ncases:\n  - query: pricing\n    expected: [beat-20260301-001]\n  - query: signup\n    expected: [beat-20260301-002, beat-20990101-001]\n"
+	if err := os.WriteFile(path, []byte(cases), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadEvalCases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := EvalSearch(s, loaded, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.K != 2 || r.Cases != 2 || len(r.Modes) != 3 {
+		t.Fatalf("report = %+v", r)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0] != "beat-20990101-001" {
+		t.Errorf("unknown = %v", r.Unknown)
+	}
+	keyword := r.Modes[0]
+	if keyword.Mode != "keyword" || keyword.MRR != 1 || keyword.Recall != 0.75 {
+		t.Errorf("keyword = %+v, want MRR 1 and recall 0.75", keyword)
+	}
+	if r.Modes[1].Backend != "tfidf" {
+		t.Errorf("semantic backend offline = %q, want tfidf", r.Modes[1].Backend)
+	}
+	if _, err := EvalSearch(s, loaded, 0, []string{"fuzzy"}); err == nil {
+		t.Error("unknown mode: want an error")
+	}
+}
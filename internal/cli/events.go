@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+// EventsReplayOptions configures bt events replay.
+type EventsReplayOptions struct {
+	Since  time.Time
+	Target string   // hooks.TargetHook or hooks.TargetWebhook
+	URL    string   // Webhook URL (default: the on_event url)
+	Events []string // Only these events (default: all)
+	DryRun bool     // List the events without sending them
+	JSON   bool
+}
+
+// ReplayedEvent is one event a replay sent or would send.
+type ReplayedEvent struct {
+	Event  string    `json:"event"`
+	At     time.Time `json:"at"`
+	BeatID string    `json:"beat_id"`
+	BeadID string    `json:"bead_id,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// EventsReplayResult is the outcome of a replay.
+type EventsReplayResult struct {
+	Since  time.Time       `json:"since"`
+	Target string          `json:"target"`
+	DryRun bool            `json:"dry_run,omitempty"`
+	Sent   int             `json:"sent"`
+	Failed int             `json:"failed"`
+	Events []ReplayedEvent `json:"events"`
+}
+
+// EventsReplay re-sends the beat_added and beat_linked events recorded in
+// the journal since a moment to the on_event script or a webhook, oldest
+// first, so a new consumer can catch up on what it missed.
+func (c *HumanCLI) EventsReplay(opts EventsReplayOptions) error {
+	if opts.Target != hooks.TargetHook && opts.Target != hooks.TargetWebhook {
+		return fmt.Errorf("invalid --target %q (use %s or %s)", opts.Target, hooks.TargetHook, hooks.TargetWebhook)
+	}
+	events, err := c.store.JournalEvents(opts.Since)
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	mgr, err := hooks.NewManager(c.store.Dir())
+	if err != nil {
+		return err
+	}
+
+	r := &EventsReplayResult{Since: opts.Since, Target: opts.Target, DryRun: opts.DryRun, Events: []ReplayedEvent{}}
+	for _, e := range events {
+		if len(opts.Events) > 0 && !slices.Contains(opts.Events, e.Event) {
+			continue
+		}
+		re := ReplayedEvent{Event: e.Event, At: e.At, BeatID: e.BeatID, BeadID: e.BeadID}
+		if !opts.DryRun {
+			if err := mgr.DeliverEvent(e, opts.Target, opts.URL); err != nil {
+				re.Error = err.Error()
+				r.Failed++
+			} else {
+				r.Sent++
+			}
+		}
+		r.Events = append(r.Events, re)
+	}
+
+	if opts.JSON {
+		return outputJSON(r)
+	}
+	if len(r.Events) == 0 {
+		fmt.Printf("No events since %s.\n", displayTime(opts.Since))
+		return nil
+	}
+	for _, re := range r.Events {
+		line := fmt.Sprintf("  %s  %-12s %s", displayTime(re.At), re.Event, re.BeatID)
+		if re.BeadID != "" {
+			line += " -> " + re.BeadID
+		}
+		if re.Error != "" {
+			line += "  FAILED: " + re.Error
+		}
+		fmt.Println(line)
+	}
+	if r.DryRun {
+		fmt.Printf("\n[dry-run] Would send %d event(s) to the %s\n", len(r.Events), opts.Target)
+		return nil
+	}
+	fmt.Printf("\nSent %d event(s) to the %s", r.Sent, opts.Target)
+	if r.Failed > 0 {
+		fmt.Printf(", %d failed", r.Failed)
+	}
+	fmt.Println()
+	if r.Failed > 0 {
+		return fmt.Errorf("%d event(s) failed", r.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// feedItems is how many digests and reviews the feed carries.
+const feedItems = 30
+
+// rssFeed is an RSS 2.0 document.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Generator   string    `xml:"generator"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"` // Escaped HTML, as readers expect
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	ID          string `xml:",chardata"`
+}
+
+// isDigestBeat reports whether a beat is a daily digest or a weekly review.
+func isDigestBeat(b beat.Beat) bool {
+	kind := b.Impetus.Meta["kind"]
+	return kind == DigestKind || kind == WeeklyKind
+}
+
+// recentDigests returns the newest digests and weekly reviews, newest first.
+func (c *HumanCLI) recentDigests(limit int) ([]beat.Beat, error) {
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var digests []beat.Beat
+	for _, b := range all {
+		if isDigestBeat(b) {
+			digests = append(digests, b)
+		}
+	}
+	sort.SliceStable(digests, func(i, j int) bool { return digests[i].CreatedAt.After(digests[j].CreatedAt) })
+	if len(digests) > limit {
+		digests = digests[:limit]
+	}
+	return digests, nil
+}
+
+// digestTitle names a digest or review beat, e.g. "Daily digest 2025-12-01".
+func digestTitle(b beat.Beat) string {
+	return strings.TrimSpace(b.Impetus.Label + " " + b.Impetus.Raw)
+}
+
+// digestBeatPage renders a stored digest or review as a standalone page.
+func digestBeatPage(b beat.Beat) HTMLPage {
+	title := digestTitle(b)
+	meta := displayTime(b.CreatedAt)
+	if sources := b.Impetus.Meta["sources"]; sources != "" {
+		meta += fmt.Sprintf(", %d beat(s)", len(strings.Split(sources, ",")))
+	}
+	if model := b.Impetus.Meta["summary_model"]; model != "" {
+		meta += ", summarized by " + model
+	}
+	// Weekly reviews repeat their title as the first line
+	body := strings.TrimSpace(strings.TrimPrefix(b.Content, title))
+	return HTMLPage{
+		Title:    title,
+		Subtitle: meta,
+		Sections: []HTMLSection{{Body: markdownHTML(body)}},
+	}
+}
+
+// digestFeedHandler serves GET /feed/digests.xml: the latest digests and
+// weekly reviews as RSS, each linking to its page on this server. Both take
+// a read token, also as ?token= since feed readers cannot set headers.
+func (c *HumanCLI) digestFeedHandler(auth *serveAuth) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		t := auth.authorize(w, r, ScopeRead, true)
+		if t == nil {
+			return
+		}
+		digests, err := c.recentDigests(feedItems)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		base := "http://" + r.Host
+		// Item links carry the token the feed was read with
+		query := "?" + url.Values{"token": {t.token}}.Encode()
+		feed := rssFeed{Version: "2.0", Channel: rssChannel{
+			Title:       "beats digests",
+			Link:        base + "/feed/digests.xml",
+			Description: "Daily digests and weekly reviews from " + c.store.Dir(),
+			Generator:   "beats",
+			Items:       []rssItem{},
+		}}
+		for _, b := range digests {
+			feed.Channel.Items = append(feed.Channel.Items, rssItem{
+				Title:       digestTitle(b),
+				Link:        base + "/digests/" + url.PathEscape(b.ID) + ".html" + query,
+				GUID:        rssGUID{ID: b.ID},
+				PubDate:     b.CreatedAt.Format(time.RFC1123Z),
+				Description: string(markdownHTML(b.Content)),
+			})
+		}
+		out, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Write([]byte(xml.Header))
+		w.Write(out)
+	})
+}
+
+// digestPageHandler serves GET /digests/<id>.html for digest and review beats.
+func (c *HumanCLI) digestPageHandler(auth *serveAuth) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if auth.authorize(w, r, ScopeRead, true) == nil {
+			return
+		}
+		id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/digests/"), ".html")
+		b, err := c.store.Get(id)
+		if !ok || err != nil || !isDigestBeat(*b) {
+			http.NotFound(w, r)
+			return
+		}
+		out, err := digestBeatPage(*b).Render()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(out)
+	})
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/fixtures"
+)
+
+// FixturesOutput is the JSON output of bt fixtures.
+type FixturesOutput struct {
+	Dir   string   `json:"dir"`
+	Seed  int64    `json:"seed"`
+	Beats int      `json:"beats"`
+	IDs   []string `json:"ids"`
+}
+
+// Fixtures fills an empty store with generated beats, the same ones for the
+// same seed and options.
+func (c *HumanCLI) Fixtures(opts fixtures.Options, jsonOut bool) error {
+	existing, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%s already has %d beats; fixtures go into an empty store (use --dir)", c.store.Dir(), len(existing))
+	}
+
+	beats := fixtures.Generate(opts)
+	if err := c.store.AppendBulk(beats); err != nil {
+		return fmt.Errorf("failed to save beats: %w", err)
+	}
+
+	out := FixturesOutput{Dir: c.store.Dir(), Seed: opts.Seed, Beats: len(beats), IDs: make([]string, len(beats))}
+	for i, b := range beats {
+		out.IDs[i] = b.ID
+	}
+	if jsonOut {
+		return outputJSON(out)
+	}
+	fmt.Printf("Generated %d beats in %s (seed %d), %s to %s\n", len(beats), c.store.Dir(), opts.Seed, out.IDs[0], out.IDs[len(out.IDs)-1])
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// openFTS opens the SQLite full-text index of s. With raw, queries are
+// passed to FTS5 untouched instead of escaped.
+func openFTS(s *store.JSONLStore, raw bool) (*store.SQLiteStore, error) {
+	sqlite, err := store.NewSQLiteStore(s)
+	if err != nil {
+		return nil, err
+	}
+	sqlite.RawQueries = raw
+	return sqlite, nil
+}
+
+// allowedBeats returns the IDs of the beats passing the session and WALD
+// filters, or nil when there are no filters.
+func allowedBeats(s *store.JSONLStore, sessionFilter, waldFilter string) (map[string]bool, error) {
+	if sessionFilter == "current" {
+		sessionFilter = os.Getenv("FACTORY_SESSION_ID")
+	}
+	if sessionFilter == "" && waldFilter == "" {
+		return nil, nil
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]bool)
+	for _, b := range filterWALD(beats, waldFilter) {
+		if strings.HasPrefix(b.SessionID, sessionFilter) {
+			allowed[b.ID] = true
+		}
+	}
+	return allowed, nil
+}
+
+// searchFTS runs an FTS5 query over the beats in the SQLite index, keeping
+// matches on beats that pass the session and WALD filters.
+func searchFTS(s *store.JSONLStore, query string, maxResults int, sessionFilter, waldFilter string, raw bool) ([]beat.SearchResult, error) {
+	sqlite, err := openFTS(s, raw)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlite.Close()
+	allowed, err := allowedBeats(s, sessionFilter, waldFilter)
+	if err != nil {
+		return nil, err
+	}
+	limit := maxResults
+	if allowed != nil || maxResults <= 0 {
+		limit = -1 // Filter everything, then cut
+	}
+	results, err := sqlite.Search(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	kept := []beat.SearchResult{}
+	for _, r := range results {
+		if (allowed == nil || allowed[r.ID]) && (maxResults <= 0 || len(kept) < maxResults) {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
+}
+
+// searchAttachments searches the extracted text of s's attachments through
+// the SQLite index, keeping matches on beats that pass the session and WALD
+// filters. A maxResults of 0 means no limit.
+func searchAttachments(s *store.JSONLStore, query string, maxResults int, sessionFilter, waldFilter string, raw bool) ([]store.AttachmentMatch, error) {
+	sqlite, err := openFTS(s, raw)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlite.Close()
+	allowed, err := allowedBeats(s, sessionFilter, waldFilter)
+	if err != nil {
+		return nil, err
+	}
+	if allowed == nil {
+		return sqlite.SearchAttachments(query, maxResults)
+	}
+	matches, err := sqlite.SearchAttachments(query, 0)
+	if err != nil {
+		return nil, err
+	}
+	kept := []store.AttachmentMatch{}
+	for _, m := range matches {
+		if allowed[m.BeatID] && (maxResults <= 0 || len(kept) < maxResults) {
+			kept = append(kept, m)
+		}
+	}
+	return kept, nil
+}
+
+// SearchRawFTS searches beats with an FTS5 query passed through untouched.
+func (c *HumanCLI) SearchRawFTS(query string, maxResults int, sessionFilter, waldFilter string) error {
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+	results, err := searchFTS(c.store, query, maxResults, sessionFilter, waldFilter, true)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	printSearchResults(query, results, maxResults)
+	return nil
+}
+
+// SearchAttachments prints the attachments whose text matches query, with
+// where in each the match is. With raw, query is FTS5 syntax.
+func (c *HumanCLI) SearchAttachments(query string, maxResults int, sessionFilter, waldFilter string, raw bool) error {
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+	matches, err := searchAttachments(c.store, query, maxResults, sessionFilter, waldFilter, raw)
+	if err != nil {
+		return fmt.Errorf("attachment search failed: %w", err)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("\nNo attachments found matching: %s\n", query)
+		return nil
+	}
+	fmt.Printf("\nFound %d attachment match(es):\n\n", len(matches))
+	for _, m := range matches {
+		label := m.Label
+		if label == "" {
+			label = "Attachment"
+		}
+		fmt.Printf("  [%.2f] %s  %s (%s, offset %d)\n", m.Score, m.BeatID, label, m.Locator, m.Offset)
+		fmt.Printf("              %s\n\n", m.Snippet)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// GCOptions configures GC.
+type GCOptions struct {
+	Grace  time.Duration // Keep unreferenced blobs changed more recently than this
+	DryRun bool          // Report what would be removed
+}
+
+// CollectBlobs removes attachments no beat refers to. Captures waiting in
+// the pending queue count as references, so accepting one later finds its
+// attachments.
+func CollectBlobs(s *store.JSONLStore, opts GCOptions) (*store.GCReport, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	refs := store.BlobRefs(beats)
+	pending, err := pipeline.LoadPending(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	for _, pb := range pending {
+		store.CountBlobRefs(refs, pb.Beat.References)
+	}
+	return s.CollectGarbage(refs, opts.Grace, opts.DryRun)
+}
+
+// GC collects unreferenced blobs and reports what it freed.
+func (c *HumanCLI) GC(opts GCOptions, jsonOut bool) error {
+	report, err := CollectBlobs(c.store, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(report)
+	}
+
+	fmt.Printf("Blobs: %d (%s), %d referenced\n", report.Blobs, formatBytes(report.Bytes), report.Referenced)
+	if report.Young > 0 {
+		fmt.Printf("Kept %d unreferenced blob(s) newer than %s\n", report.Young, opts.Grace)
+	}
+	verb := "Removed"
+	if report.DryRun {
+		verb = "Would remove"
+	}
+	if len(report.Removed) == 0 {
+		fmt.Println("Nothing to collect.")
+		return nil
+	}
+	for _, b := range report.Removed {
+		fmt.Printf("  %s  %s\n", b.Path, formatBytes(b.Size))
+	}
+	fmt.Printf("%s %d blob(s), %s\n", verb, len(report.Removed), formatBytes(report.Freed))
+	return nil
+}
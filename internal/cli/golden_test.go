@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/fixtures"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files in testdata/golden")
+
+// TestRobotGolden runs robot commands against a generated store with the
+// clock frozen and compares their output with testdata/golden. Run
+// go test ./internal/cli -run Golden -update after an intended change.
+func TestRobotGolden(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	clock.Set(time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC))
+	defer clock.Reset()
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendBulk(fixtures.Generate(fixtures.Options{Seed: 1, Count: 30})); err != nil {
+		t.Fatal(err)
+	}
+	robot := NewRobotCLI(s)
+
+	for _, tt := range []struct {
+		name string
+		run  func() error
+	}{
+		{"robot-search", func() error {
+			return robot.Search(strings.NewReader(`{"query":"pricing","max_results":3}`))
+		}},
+		{"robot-brief", func() error {
+			return robot.Brief(strings.NewReader(`{"topic":"onboarding","max_beats":4,"max_tokens":400}`))
+		}},
+		{"robot-stats", robot.Stats},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			SetJSONOutput(&out)
+			defer SetJSONOutput(nil)
+			if err := tt.run(); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join("testdata", "golden", tt.name+".json")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Errorf("output differs from %s (run with -update if intended):\n%s", path, out.String())
+			}
+		})
+	}
+}
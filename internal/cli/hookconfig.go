@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+// HooksShow prints the global and store hooks.json as they are or, with
+// effective, the configuration the store runs with and where each section
+// comes from.
+func (c *HumanCLI) HooksShow(effective, jsonOut bool) error {
+	e, err := hooks.LoadEffectiveHooks(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(e)
+	}
+
+	if !effective {
+		for _, layer := range []struct{ name, path string }{{"Global", e.GlobalFile}, {"Store", e.StoreFile}} {
+			fmt.Printf("%s hooks: %s\n", layer.name, layer.path)
+			data, err := os.ReadFile(layer.path)
+			switch {
+			case layer.path == "":
+				fmt.Println("  (no user config directory)")
+			case err != nil:
+				fmt.Println("  (none)")
+			default:
+				fmt.Println(strings.TrimRight(string(data), "\n"))
+			}
+			fmt.Println()
+		}
+		fmt.Println("The store's settings win, key by key; run with --effective to see them merged.")
+		return nil
+	}
+
+	global, store := e.GlobalFile, e.StoreFile
+	if !e.GlobalExists {
+		global += " (none)"
+	}
+	if !e.StoreExists {
+		store += " (none)"
+	}
+	fmt.Printf("Global: %s\nStore:  %s\n\n", global, store)
+	if len(e.Config) == 0 {
+		fmt.Println("No hooks configured.")
+		return nil
+	}
+	for _, name := range e.Sections() {
+		fmt.Printf("  %-17s %s\n", name, e.Sources[name])
+	}
+	data, err := json.MarshalIndent(e.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", data)
+	return nil
+}
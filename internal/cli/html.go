@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// FormatHTML renders briefs, digests and reviews as a standalone HTML page
+// that reads well on a phone or a shared team page.
+const FormatHTML = "html"
+
+// HTMLPage is a standalone page: a title, a line under it and sections of
+// HTML.
+type HTMLPage struct {
+	Title    string
+	Subtitle string
+	Sections []HTMLSection
+}
+
+// HTMLSection is one part of a page, with its body already rendered.
+type HTMLSection struct {
+	Title string
+	Meta  string
+	Body  template.HTML
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="beats">
+<title>{{.Title}}</title>
+<style>
+:root { color-scheme: light dark; --fg: #1d1d1f; --bg: #fdfdfc; --muted: #6e6e73; --rule: #e3e3e0; --accent: #b4541f; }
+@media (prefers-color-scheme: dark) { :root { --fg: #e8e8e6; --bg: #161615; --muted: #9a9a96; --rule: #2e2e2c; --accent: #e08a55; } }
+body { margin: 0 auto; max-width: 42rem; padding: 2rem 1.25rem 4rem; font: 17px/1.6 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: var(--fg); background: var(--bg); }
+h1 { font-size: 1.6rem; line-height: 1.25; margin: 0 0 .25rem; }
+h2 { font-size: 1.2rem; margin: 2.25rem 0 .5rem; padding-top: 1rem; border-top: 1px solid var(--rule); }
+h3 { font-size: 1.05rem; margin: 1.5rem 0 .4rem; }
+a { color: var(--accent); }
+code, pre { font: .9em/1.5 ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre { overflow-x: auto; padding: .75rem; border: 1px solid var(--rule); border-radius: 6px; }
+blockquote { margin: 1rem 0; padding-left: 1rem; border-left: 3px solid var(--rule); color: var(--muted); }
+.meta, footer { color: var(--muted); font-size: .875rem; }
+footer { margin-top: 3rem; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{- if .Subtitle}}
+<p class="meta">{{.Subtitle}}</p>
+{{- end}}
+</header>
+{{- range .Sections}}
+<section>
+{{- if .Title}}
+<h2>{{.Title}}</h2>
+{{- end}}
+{{- if .Meta}}
+<p class="meta">{{.Meta}}</p>
+{{- end}}
+{{.Body}}
+</section>
+{{- end}}
+<footer>Generated by beats on {{.Generated}}</footer>
+</body>
+</html>
+`))
+
+// Render returns the page as a complete HTML document.
+func (p HTMLPage) Render() ([]byte, error) {
+	var buf bytes.Buffer
+	err := pageTemplate.Execute(&buf, struct {
+		HTMLPage
+		Generated string
+	}{p, displayTime(clock.Now())})
+	return buf.Bytes(), err
+}
+
+var (
+	mdHeadingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdBulletRe   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	mdNumberedRe = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	mdRuleRe     = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	mdLinkHTMLRe = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)]+)\)`)
+	mdBareURLRe  = regexp.MustCompile(`(^|[\s(])(https?://[^\s<)]+)`)
+	mdStrongRe   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdEmRe       = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*`)
+)
+
+// markdownHTML renders the markdown that beats and the LLM write: headings,
+// paragraphs, bulleted and numbered lists, quotes, rules, fenced code and
+// inline code, bold, italics and links. Everything else is escaped as text.
+func markdownHTML(md string) template.HTML {
+	var out strings.Builder
+	var para []string
+	list := "" // The open list element, ul or ol
+	inCode := false
+
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + inlineHTML(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(kind string) {
+		flush()
+		if list != kind {
+			closeList()
+			out.WriteString("<" + kind + ">\n")
+			list = kind
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inCode {
+			if strings.HasPrefix(trimmed, "```") {
+				out.WriteString("</code></pre>\n")
+				inCode = false
+			} else {
+				out.WriteString(html.EscapeString(line) + "\n")
+			}
+			continue
+		}
+		if m := mdBulletRe.FindStringSubmatch(trimmed); m != nil && !mdRuleRe.MatchString(trimmed) {
+			openList("ul")
+			out.WriteString("<li>" + inlineHTML(m[1]) + "</li>\n")
+			continue
+		}
+		if m := mdNumberedRe.FindStringSubmatch(trimmed); m != nil {
+			openList("ol")
+			out.WriteString("<li>" + inlineHTML(m[1]) + "</li>\n")
+			continue
+		}
+		closeList()
+		m := mdHeadingRe.FindStringSubmatch(trimmed)
+		if trimmed != "" && m == nil && !strings.HasPrefix(trimmed, "```") && !mdRuleRe.MatchString(trimmed) && !strings.HasPrefix(trimmed, ">") {
+			para = append(para, trimmed)
+			continue
+		}
+		flush()
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "```"):
+			out.WriteString("<pre><code>")
+			inCode = true
+		case m != nil:
+			// The page title is the only h1
+			level := min(len(m[1])+1, 6)
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", level, inlineHTML(m[2]), level)
+		case mdRuleRe.MatchString(trimmed):
+			out.WriteString("<hr>\n")
+		default:
+			out.WriteString("<blockquote>" + inlineHTML(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>\n")
+		}
+	}
+	flush()
+	closeList()
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	return template.HTML(out.String())
+}
+
+// inlineHTML escapes a line of markdown and renders its inline code, bold,
+// italics and links. Code spans are left as they are written.
+func inlineHTML(text string) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		part = html.EscapeString(part)
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + part + "</code>"
+			continue
+		}
+		if i%2 == 1 {
+			part = "`" + part // An unclosed backtick
+		}
+		part = mdLinkHTMLRe.ReplaceAllString(part, `<a href="$2">$1</a>`)
+		part = mdBareURLRe.ReplaceAllString(part, `$1<a href="$2">$2</a>`)
+		part = mdStrongRe.ReplaceAllString(part, `<strong>$1</strong>`)
+		parts[i] = mdEmRe.ReplaceAllString(part, `$1<em>$2</em>`)
+	}
+	return strings.Join(parts, "")
+}
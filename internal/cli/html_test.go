@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestMarkdownHTML(t *testing.T) {
+	md := "## Themes\nShipped the **parser** and `a<b` see [notes](https://example.com/a?b=1&c=2).\nStill *open*: https://example.com/x\n\n" +
+		"- one\n- <script>alert(1)</script>\n\n1. first\n2) second\n\n> quoted\n\n```\nif a < b {}\n```\n---"
+	want := "<h3>Themes</h3>\n" +
+		"<p>Shipped the <strong>parser</strong> and <code>a&lt;b</code> see <a href=\"https://example.com/a?b=1&amp;c=2\">notes</a>. " +
+		"Still <em>open</em>: <a href=\"https://example.com/x\">https://example.com/x</a></p>\n" +
+		"<ul>\n<li>one</li>\n<li>&lt;script&gt;alert(1)&lt;/script&gt;</li>\n</ul>\n" +
+		"<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n" +
+		"<blockquote>quoted</blockquote>\n" +
+		"<pre><code>if a &lt; b {}\n</code></pre>\n<hr>\n"
+	if got := string(markdownHTML(md)); got != want {
+		t.Errorf("markdownHTML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDigestFeed(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewHumanCLI(s)
+	note, err := c.commit(&beat.ProposedBeat{Content: "An ordinary note"}, beat.ChannelManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := c.commit(digestBeat(DigestDay{Day: "2025-12-01", Sources: []string{note.ID}, Content: "Fixed the <b>build</b>"}, note.CreatedAt), beat.ChannelGenerated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := newServeAuth("secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(c.serveMux(auth, log.New(io.Discard, "", 0)))
+	defer srv.Close()
+
+	if resp, err := http.Get(srv.URL + "/feed/digests.xml"); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("feed without a token = %v, %v; want 401", resp, err)
+	}
+	resp, err := http.Get(srv.URL + "/feed/digests.xml?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var feed rssFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("feed items = %+v, want only the digest", feed.Channel.Items)
+	}
+	item := feed.Channel.Items[0]
+	if item.GUID.ID != digest.ID || item.Title != "Daily digest 2025-12-01" || !strings.Contains(item.Description, "&lt;b&gt;build") {
+		t.Errorf("item = %+v, want the digest with escaped content", item)
+	}
+
+	page, err := http.Get(item.Link)
+	if err != nil || page.StatusCode != http.StatusOK || !strings.HasPrefix(page.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("digest page = %v, %v; want an HTML page", page, err)
+	}
+	page.Body.Close()
+	if resp, err := http.Get(srv.URL + "/digests/" + note.ID + ".html?token=secret"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("page for a plain beat = %v, %v; want 404", resp, err)
+	}
+}
@@ -12,14 +12,15 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/bierlingm/beats/internal/beat"
 	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
 	"github.com/bierlingm/beats/internal/embeddings"
-	"github.com/bierlingm/beats/internal/entity"
-	"github.com/bierlingm/beats/internal/impetus"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
 	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/wald"
 )
 
 // HumanCLI handles human-facing CLI commands.
@@ -39,9 +40,13 @@ type AddOptions struct {
 	WebURL       string
 	GitHubRef    string
 	TwitterURL   string
+	Snapshot     bool // Archive the captured page as HTML and markdown attachments
 	Coaching     bool
 	Session      bool
 	Date         *time.Time
+	Context      string // WALD directory to file the beat under, instead of inferring it
+	Redact       bool   // Mask secrets and personal data found in the content
+	Extract      string // Entity extraction: heuristic, llm or off (default: entity_extraction setting)
 }
 
 // Add creates a new beat with the given content.
@@ -55,21 +60,7 @@ func (c *HumanCLI) Add(content string, impetusLabel string) error {
 // resolveWALDDirectory resolves a capture path to a WALD directory.
 // Returns the relative WALD directory path and confidence score.
 func resolveWALDDirectory(capturePath string) (string, float64) {
-	// Find the werk root by looking for WALD.yaml
-	werkRoot := ""
-	dir := capturePath
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "WALD.yaml")); err == nil {
-			werkRoot = dir
-			break
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-
+	werkRoot := wald.FindRoot(capturePath)
 	if werkRoot == "" {
 		return "", 0
 	}
@@ -91,8 +82,13 @@ func resolveWALDDirectory(capturePath string) (string, float64) {
 
 // AddWithOptions creates a new beat with extended options.
 func (c *HumanCLI) AddWithOptions(opts AddOptions) error {
+	if _, err := pipeline.ExtractionMode(opts.Extract); err != nil {
+		return err
+	}
 	var finalContent string
 	var finalImpetus string
+	var impetusMeta map[string]string
+	references := []beat.Reference{}
 
 	// Handle web capture
 	if opts.WebURL != "" {
@@ -102,6 +98,7 @@ func (c *HumanCLI) AddWithOptions(opts AddOptions) error {
 		}
 		finalContent = web.Content
 		finalImpetus = web.Impetus
+		impetusMeta, references = c.webCaptureDetails(web, c.wantSnapshot(opts.Snapshot))
 	} else if opts.GitHubRef != "" {
 		// Handle GitHub capture
 		gh, err := capture.CaptureFromGitHub(opts.GitHubRef, opts.Content)
@@ -136,63 +133,225 @@ func (c *HumanCLI) AddWithOptions(opts AddOptions) error {
 	}
 
 	// Determine the creation time
-	createdAt := time.Now().UTC()
+	createdAt := clock.Now().UTC()
 	if opts.Date != nil {
 		createdAt = opts.Date.UTC()
 	}
 
-	seq, err := c.store.NextSequenceForDate(createdAt)
+	proposed := &beat.ProposedBeat{
+		Content:     finalContent,
+		Impetus:     beat.Impetus{Label: finalImpetus, Meta: impetusMeta},
+		References:  references,
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+		CreatedAt:   &createdAt,
+	}
+	if opts.Context != "" {
+		proposed.Context = &beat.Context{WALDDirectory: wald.ResolveDir(opts.Context)}
+	}
+	b, err := c.commitBeat(proposed, pipeline.Options{Redact: opts.Redact, Extract: opts.Extract, Channel: beat.ChannelManual})
 	if err != nil {
-		return fmt.Errorf("failed to get sequence: %w", err)
+		return err
 	}
 
-	imp := beat.Impetus{
-		Label: finalImpetus,
+	fmt.Printf("Created beat: %s\n", b.ID)
+	if len(b.Entities) > 0 {
+		fmt.Printf("Entities: %s\n", entitySummary(b.Entities))
 	}
-	if finalImpetus == "" {
-		if inferred := impetus.Infer(finalContent); inferred != "" {
-			imp.Label = inferred
-		} else {
-			imp.Label = "Manual entry"
+	if opts.Date == nil && config.Get().Streak() {
+		if st, err := Stats(c.store, StatsOptions{Days: 1}); err == nil && st.StreakLine() != "" {
+			fmt.Println(st.StreakLine())
 		}
 	}
+	return nil
+}
 
-	// Extract entities from content using WALD.yaml data
-	extractedEntities := entity.ExtractEntities(finalContent, "")
+// webCaptureDetails records page metadata and references for a web capture,
+// saving the full readable text as an attachment. With snapshot set the page
+// is also archived as HTML and markdown so the beat survives link rot.
+func (c *HumanCLI) webCaptureDetails(web *capture.WebCapture, snapshot bool) (map[string]string, []beat.Reference) {
+	meta := map[string]string{"url": web.URL}
+	for key, value := range map[string]string{
+		"title":       web.Title,
+		"site_name":   web.SiteName,
+		"author":      web.Author,
+		"description": web.Description,
+	} {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+
+	refs := []beat.Reference{{
+		Kind:    "url",
+		Subtype: "web",
+		Locator: web.URL,
+		Label:   web.Title,
+	}}
+	if web.Text != "" {
+		text := web.Text
+		if web.Title != "" && !strings.HasPrefix(text, web.Title) {
+			text = web.Title + "\n\n" + text
+		}
+		if rel, err := c.store.SaveAttachment([]byte(text+"\n"), ".txt"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "text/plain",
+				Locator: rel,
+				Label:   "Full text",
+				Meta:    map[string]string{"source": web.URL, "words": fmt.Sprint(len(strings.Fields(web.Text)))},
+			})
+		}
+	}
+	if snapshot {
+		if snapRefs := c.saveSnapshot(web); len(snapRefs) > 0 {
+			refs = append(refs, snapRefs...)
+			meta["snapshot"] = "true"
+		}
+	}
+	return meta, refs
+}
 
-	b := &beat.Beat{
-		ID:          beat.GenerateIDWithSequence(createdAt, seq),
-		CreatedAt:   createdAt,
-		UpdatedAt:   time.Now().UTC(),
-		Impetus:     imp,
-		Content:     finalContent,
-		References:  []beat.Reference{},
-		Entities:    extractedEntities,
-		LinkedBeads: []string{},
+// wantSnapshot reports whether a web capture should be archived: when asked
+// for explicitly or when snapshots are enabled in capture.json.
+func (c *HumanCLI) wantSnapshot(explicit bool) bool {
+	if explicit {
+		return true
 	}
+	cfg, err := capture.LoadConfig(c.store.Dir())
+	return err == nil && cfg.Snapshots.Enabled
+}
 
-	if sessionID := os.Getenv("FACTORY_SESSION_ID"); sessionID != "" {
-		b.SessionID = sessionID
+// saveSnapshot stores the raw page and its main content as markdown. The HTML
+// gets a <base> element so relative links and images still resolve when the
+// file is opened locally.
+func (c *HumanCLI) saveSnapshot(web *capture.WebCapture) []beat.Reference {
+	capturedAt := clock.Now().UTC().Format(time.RFC3339)
+	var refs []beat.Reference
+	if len(web.HTML) > 0 {
+		page := snapshotHTML(web.HTML, web.URL, capturedAt)
+		if rel, err := c.store.SaveAttachment(page, ".html"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "text/html",
+				Locator: rel,
+				Label:   "Snapshot",
+				Meta:    map[string]string{"source": web.URL, "captured_at": capturedAt, "bytes": fmt.Sprint(len(web.HTML))},
+			})
+		}
+	}
+	if web.Markdown != "" {
+		body := strings.TrimSpace(web.Markdown)
+		var md strings.Builder
+		if web.Title != "" {
+			// The provenance line goes under the title, even when the page has its own
+			body = strings.TrimSpace(strings.TrimPrefix(body, "# "+web.Title+"\n"))
+			fmt.Fprintf(&md, "# %s\n\n", web.Title)
+		}
+		fmt.Fprintf(&md, "Snapshot of <%s>, captured %s\n\n", web.URL, capturedAt)
+		md.WriteString(body + "\n")
+		if rel, err := c.store.SaveAttachment([]byte(md.String()), ".md"); err == nil {
+			refs = append(refs, beat.Reference{
+				Kind:    "attachment",
+				Subtype: "text/markdown",
+				Locator: rel,
+				Label:   "Snapshot (markdown)",
+				Meta:    map[string]string{"source": web.URL, "captured_at": capturedAt, "words": fmt.Sprint(len(strings.Fields(web.Markdown)))},
+			})
+		}
 	}
+	return refs
+}
 
-	// Global store architecture: beats have NO context field.
-	// Context/directory assignment happens via claims at query time (P2).
-	// b.Context is left nil.
+// snapshotHTML prefixes a page with a provenance comment and, unless it has
+// one already, a <base href> pointing at the original URL.
+func snapshotHTML(page []byte, url, capturedAt string) []byte {
+	html := string(page)
+	lower := strings.ToLower(html)
+	if !strings.Contains(lower, "<base ") {
+		base := fmt.Sprintf("<base href=\"%s\">", strings.ReplaceAll(url, `"`, "%22"))
+		if i := strings.Index(lower, "<head"); i >= 0 {
+			if end := strings.Index(lower[i:], ">"); end >= 0 {
+				at := i + end + 1
+				html = html[:at] + base + html[at:]
+			}
+		} else {
+			html = base + html
+		}
+	}
+	header := fmt.Sprintf("<!-- Snapshot of %s captured %s by beats -->\n", strings.ReplaceAll(url, "--", "%2D%2D"), capturedAt)
+	return []byte(header + html)
+}
 
-	if err := c.store.Append(b); err != nil {
-		return fmt.Errorf("failed to save beat: %w", err)
+// entitySummary lists entities for a confirmation line, e.g.
+// "Ada Lovelace (person), beats (project)".
+func entitySummary(entities []beat.Entity) string {
+	parts := make([]string, len(entities))
+	for i, e := range entities {
+		parts[i] = e.Label + " (" + e.Category + ")"
 	}
+	return strings.Join(parts, ", ")
+}
 
-	fmt.Printf("Created beat: %s\n", b.ID)
-	return nil
+func init() {
+	// The session-end hook cannot import this package to capture its summaries
+	hooks.CaptureBeat = func(beatsDir string, p *beat.ProposedBeat) (*beat.Beat, error) {
+		s, err := store.NewJSONLStore(beatsDir)
+		if err != nil {
+			return nil, err
+		}
+		return NewHumanCLI(s).commit(p, beat.ChannelSession)
+	}
+}
+
+// commit runs the pre_commit hook on a proposed beat, assigns its ID and
+// appends it to the store. Shared by every human capture path.
+func (c *HumanCLI) commit(p *beat.ProposedBeat, channel string) (*beat.Beat, error) {
+	return c.commitBeat(p, pipeline.Options{Channel: channel})
+}
+
+// commitBeat is commit with the options bt add takes. An automated capture
+// held for review returns a *pipeline.PendingError. Quota days start in the
+// display time zone.
+func (c *HumanCLI) commitBeat(p *beat.ProposedBeat, opts pipeline.Options) (*beat.Beat, error) {
+	if opts.Location == nil {
+		opts.Location = DisplayLocation
+	}
+	b, run, err := pipeline.Capture(context.Background(), c.store, p, opts)
+	if run != nil {
+		for _, w := range run.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range run.Findings {
+		if f.Redacted {
+			fmt.Fprintf(os.Stderr, "Redacted %s\n", f)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: possible secret, %s (use --redact to mask)\n", f)
+		}
+	}
+
+	if check := run.Check; check != nil {
+		for _, d := range check.Duplicates {
+			fmt.Printf("Possible duplicate of %s (similarity %.2f): %s\n", d.ID, d.Score, truncate(d.Content, 60))
+		}
+		for _, l := range check.SuggestedLinks {
+			fmt.Printf("Suggested link: %s %s (%.2f) - bt link %s %s\n", l.BeadID, l.Title, l.Confidence, b.ID, l.BeadID)
+		}
+	}
+	return b, nil
 }
 
-// List displays all beats, optionally filtered by session.
-func (c *HumanCLI) List(sessionFilter string) error {
+// List displays all beats, optionally filtered by session and WALD directory.
+func (c *HumanCLI) List(sessionFilter, waldFilter string) error {
 	beats, err := c.store.ReadAll()
 	if err != nil {
 		return fmt.Errorf("failed to read beats: %w", err)
 	}
+	beats = filterWALD(beats, waldFilter)
 
 	// Resolve "current" to actual session ID
 	if sessionFilter == "current" {
@@ -218,7 +377,7 @@ func (c *HumanCLI) List(sessionFilter string) error {
 	fmt.Printf("Found %d beat(s):\n\n", len(beats))
 	for _, b := range beats {
 		preview := truncate(b.Content, 60)
-		fmt.Printf("  %s  %s\n", b.ID, b.Impetus.Label)
+		fmt.Printf("  %s  %s  %s\n", b.ID, displayTime(b.CreatedAt), b.Impetus.Label)
 		fmt.Printf("            %s\n\n", preview)
 	}
 
@@ -233,8 +392,8 @@ func (c *HumanCLI) Show(id string) error {
 	}
 
 	fmt.Printf("ID:         %s\n", b.ID)
-	fmt.Printf("Created:    %s\n", b.CreatedAt.Format(time.RFC3339))
-	fmt.Printf("Updated:    %s\n", b.UpdatedAt.Format(time.RFC3339))
+	fmt.Printf("Created:    %s\n", displayStamp(b.CreatedAt))
+	fmt.Printf("Updated:    %s\n", displayStamp(b.UpdatedAt))
 	fmt.Printf("Impetus:    %s\n", b.Impetus.Label)
 	if b.Impetus.Raw != "" {
 		fmt.Printf("Raw:        %s\n", b.Impetus.Raw)
@@ -258,94 +417,83 @@ func (c *HumanCLI) Show(id string) error {
 		}
 	}
 
+	if len(b.Relations) > 0 {
+		fmt.Printf("\nRelations:\n")
+		for _, r := range b.Relations {
+			fmt.Printf("  - %s -[%s]-> %s\n", r.From, r.Type, r.To)
+		}
+	}
+
 	if len(b.LinkedBeads) > 0 {
 		fmt.Printf("\nLinked Beads:\n")
-		for _, beadID := range b.LinkedBeads {
-			fmt.Printf("  - %s\n", beadID)
+		known, _, _ := lookupBeads(c.store.Dir(), b.LinkedBeads.IDs()) // Titles are a nicety; IDs suffice offline
+		for _, link := range b.LinkedBeads {
+			beadID := link.BeadID
+			label := beadLabel(beadID, known)
+			if link.Relation != beat.RelationSeed {
+				label += "  [" + link.Relation + "]"
+			}
+			fmt.Printf("  - %s\n", label)
+			if u := known[beadID].URL; u != "" {
+				fmt.Printf("    %s\n", u)
+			}
 		}
 	}
 
-	return nil
-}
-
-// Search finds beats matching the query, optionally filtered by session.
-func (c *HumanCLI) Search(query string, maxResults int, sessionFilter string) error {
-	if maxResults <= 0 {
-		maxResults = 20
+	if len(b.Annotations) > 0 {
+		fmt.Printf("\nAnnotations:\n")
+		for _, a := range b.Annotations {
+			fmt.Printf("  - %s  %s: %s\n", displayTime(a.CreatedAt), a.Author, a.Note)
+		}
 	}
 
-	// Resolve "current" to actual session ID
-	if sessionFilter == "current" {
-		sessionFilter = os.Getenv("FACTORY_SESSION_ID")
+	backlinks, err := c.store.Backlinks()
+	if err != nil {
+		return fmt.Errorf("failed to read backlinks: %w", err)
 	}
-
-	// If session filter specified, we need to filter results
-	if sessionFilter != "" {
-		beats, err := c.store.ReadAll()
-		if err != nil {
-			return fmt.Errorf("search failed: %w", err)
+	if refs := backlinks[b.ID]; len(refs) > 0 {
+		ids := make([]string, len(refs))
+		for i, r := range refs {
+			ids[i] = r.ID
 		}
-
-		queryLower := strings.ToLower(query)
-		var results []beat.SearchResult
-		for _, b := range beats {
-			// Check session filter
-			if !strings.HasPrefix(b.SessionID, sessionFilter) {
-				continue
-			}
-
-			contentLower := strings.ToLower(b.Content)
-			labelLower := strings.ToLower(b.Impetus.Label)
-
-			score := 0.0
-			if strings.Contains(contentLower, queryLower) {
-				score += 0.5
-			}
-			if strings.Contains(labelLower, queryLower) {
-				score += 0.5
-			}
-
-			if score > 0 {
-				results = append(results, beat.SearchResult{
-					ID:      b.ID,
-					Score:   score,
-					Content: b.Content,
-					Impetus: b.Impetus,
-				})
-			}
+		citing, _ := c.store.GetByIDs(ids)
+		content := make(map[string]string, len(citing))
+		for _, x := range citing {
+			content[x.ID] = x.Content
 		}
-
-		if len(results) == 0 {
-			fmt.Printf("No beats found matching: %s\n", query)
-			return nil
+		fmt.Printf("\nReferenced by:\n")
+		for _, r := range refs {
+			fmt.Printf("  - %s  [%s] %s\n", r.ID, r.Via, truncate(content[r.ID], 60))
 		}
+	}
 
-		// Sort by score
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].Score > results[j].Score
-		})
-
-		if maxResults > 0 && len(results) > maxResults {
-			results = results[:maxResults]
-		}
+	return nil
+}
 
-		fmt.Printf("Found %d result(s) for \"%s\":\n\n", len(results), query)
-		for _, r := range results {
-			preview := truncate(r.Content, 60)
-			fmt.Printf("  [%.2f] %s  %s\n", r.Score, r.ID, r.Impetus.Label)
-			fmt.Printf("              %s\n\n", preview)
-		}
-		return nil
+// Search finds beats matching the query, optionally filtered by session and
+// WALD directory.
+func (c *HumanCLI) Search(query string, maxResults int, sessionFilter, waldFilter string) error {
+	if maxResults <= 0 {
+		maxResults = 20
 	}
 
-	results, err := c.store.Search(query, maxResults)
+	results, err := searchStore(c.store, query, sessionFilter, waldFilter)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
+	printSearchResults(query, results, maxResults)
+	return nil
+}
 
+// printSearchResults prints up to maxResults search results.
+func printSearchResults(query string, results []beat.SearchResult, maxResults int) {
 	if len(results) == 0 {
 		fmt.Printf("No beats found matching: %s\n", query)
-		return nil
+		return
+	}
+
+	if len(results) > maxResults {
+		results = results[:maxResults]
 	}
 
 	fmt.Printf("Found %d result(s) for \"%s\":\n\n", len(results), query)
@@ -354,8 +502,59 @@ func (c *HumanCLI) Search(query string, maxResults int, sessionFilter string) er
 		fmt.Printf("  [%.2f] %s  %s\n", r.Score, r.ID, r.Impetus.Label)
 		fmt.Printf("              %s\n\n", preview)
 	}
+}
+
+// searchStore returns every beat in s matching the query, best first,
+// optionally filtered by session and WALD directory.
+func searchStore(s *store.JSONLStore, query string, sessionFilter, waldFilter string) ([]beat.SearchResult, error) {
+	// Resolve "current" to actual session ID
+	if sessionFilter == "current" {
+		sessionFilter = os.Getenv("FACTORY_SESSION_ID")
+	}
+	if sessionFilter == "" && waldFilter == "" {
+		return s.Search(query, 0)
+	}
 
-	return nil
+	// With a filter, score only the beats that pass it
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	beats = filterWALD(beats, waldFilter)
+
+	scoring, err := store.LoadScoringConfig(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	queryLower := strings.ToLower(query)
+	var results []beat.SearchResult
+	for _, b := range beats {
+		// Check session filter
+		if !strings.HasPrefix(b.SessionID, sessionFilter) {
+			continue
+		}
+
+		contentLower := strings.ToLower(b.Content)
+		labelLower := strings.ToLower(b.Impetus.Label)
+
+		score := scoring.KeywordScore(strings.Contains(contentLower, queryLower), strings.Contains(labelLower, queryLower))
+
+		if score > 0 {
+			results = append(results, beat.SearchResult{
+				ID:      b.ID,
+				Score:   score,
+				Content: b.Content,
+				Impetus: b.Impetus,
+			})
+		}
+	}
+
+	// Sort by score
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
 }
 
 // EditOptions contains options for editing a beat.
@@ -367,11 +566,13 @@ type EditOptions struct {
 	RmRefs   []string
 	AddBeads []string
 	RmBeads  []string
+	Relation string // Relation for AddBeads (default seed)
+	Force    bool   // Link bead IDs the provider does not know
 }
 
 // Redate changes the creation date of a beat (convenience wrapper around Edit).
 func (c *HumanCLI) Redate(id string, dateStr string) error {
-	parsedDate, err := ParseRelativeDate(dateStr)
+	parsedDate, err := clock.ParseRelative(dateStr)
 	if err != nil {
 		return fmt.Errorf("invalid date: %w", err)
 	}
@@ -382,10 +583,21 @@ func (c *HumanCLI) Redate(id string, dateStr string) error {
 
 // Edit modifies an existing beat.
 func (c *HumanCLI) Edit(id string, opts EditOptions) error {
+	if opts.Relation != "" && !beat.ValidRelation(opts.Relation) {
+		return fmt.Errorf("invalid relation %q (use %s)", opts.Relation, strings.Join(beat.Relations, ", "))
+	}
 	existingBeat, err := c.store.Get(id)
 	if err != nil {
 		return err
 	}
+	id = existingBeat.ID // A short form such as @1 would name another beat once this one moves
+	if len(opts.AddBeads) > 0 {
+		known, err := c.checkBeadsExist(opts.AddBeads, opts.Force)
+		if err != nil {
+			return err
+		}
+		opts.AddBeads, _ = canonicalBeads(opts.AddBeads, known)
+	}
 
 	var newDate time.Time
 	dateChanging := false
@@ -398,9 +610,7 @@ func (c *HumanCLI) Edit(id string, opts EditOptions) error {
 			}
 		}
 		newDate = parsedDate.UTC()
-		oldDateStr := existingBeat.CreatedAt.UTC().Format("20060102")
-		newDateStr := newDate.Format("20060102")
-		dateChanging = oldDateStr != newDateStr
+		dateChanging = beat.IDDate(existingBeat.CreatedAt) != beat.IDDate(newDate)
 	}
 
 	if dateChanging {
@@ -412,7 +622,7 @@ func (c *HumanCLI) Edit(id string, opts EditOptions) error {
 		newBeat := *existingBeat
 		newBeat.ID = beat.GenerateIDWithSequence(newDate, seq)
 		newBeat.CreatedAt = newDate
-		newBeat.UpdatedAt = time.Now().UTC()
+		newBeat.UpdatedAt = clock.Now().UTC()
 
 		applyEditOptions(&newBeat, opts)
 
@@ -442,6 +652,7 @@ func (c *HumanCLI) Edit(id string, opts EditOptions) error {
 func applyEditOptions(b *beat.Beat, opts EditOptions) {
 	if opts.Content != "" {
 		b.Content = opts.Content
+		b.References = beat.LinkCitations(b.References, b.Content)
 	}
 	if opts.Impetus != "" {
 		b.Impetus.Label = opts.Impetus
@@ -467,26 +678,11 @@ func applyEditOptions(b *beat.Beat, opts EditOptions) {
 		b.References = filtered
 	}
 
-	existing := make(map[string]bool)
-	for _, beadID := range b.LinkedBeads {
-		existing[beadID] = true
-	}
+	now := clock.Now().UTC()
 	for _, beadID := range opts.AddBeads {
-		if !existing[beadID] {
-			b.LinkedBeads = append(b.LinkedBeads, beadID)
-			existing[beadID] = true
-		}
-	}
-
-	for _, beadID := range opts.RmBeads {
-		filtered := make([]string, 0, len(b.LinkedBeads))
-		for _, bid := range b.LinkedBeads {
-			if bid != beadID {
-				filtered = append(filtered, bid)
-			}
-		}
-		b.LinkedBeads = filtered
+		b.LinkedBeads.Add(beat.BeadLink{BeadID: beadID, Relation: opts.Relation, CreatedAt: now, CreatedBy: linkAuthor()})
 	}
+	b.UnlinkBeads(now, opts.RmBeads...)
 }
 
 // Amend edits the most recent beat.
@@ -498,18 +694,28 @@ func (c *HumanCLI) Amend(opts EditOptions) error {
 	return c.Edit(mostRecent.ID, opts)
 }
 
-// Link adds bead IDs to a beat's linked_beads.
-func (c *HumanCLI) Link(beatID string, beadIDs []string) error {
+// Link adds bead IDs to a beat's linked_beads with a relation (default
+// seed); linking an already linked bead changes its relation. IDs are
+// checked against the configured bead provider first; force links unknown
+// IDs anyway.
+func (c *HumanCLI) Link(beatID string, beadIDs []string, relation string, force bool) error {
+	if relation != "" && !beat.ValidRelation(relation) {
+		return fmt.Errorf("invalid relation %q (use %s)", relation, strings.Join(beat.Relations, ", "))
+	}
+	known, err := c.checkBeadsExist(beadIDs, force)
+	if err != nil {
+		return err
+	}
+	beadIDs, known = canonicalBeads(beadIDs, known)
+
+	var added []string
+	now := clock.Now().UTC()
 	updated, err := c.store.Update(beatID, func(b *beat.Beat) error {
-		// Add new bead IDs, avoiding duplicates
-		existing := make(map[string]bool)
-		for _, id := range b.LinkedBeads {
-			existing[id] = true
-		}
 		for _, id := range beadIDs {
-			if !existing[id] {
-				b.LinkedBeads = append(b.LinkedBeads, id)
-				existing[id] = true
+			isNew := !b.LinkedBeads.Has(id)
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: id, Relation: relation, CreatedAt: now, CreatedBy: linkAuthor()})
+			if isNew {
+				added = append(added, id)
 			}
 		}
 		return nil
@@ -517,9 +723,13 @@ func (c *HumanCLI) Link(beatID string, beadIDs []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to link beat: %w", err)
 	}
+	defer c.commentOnBeads(updated, added, known)
 
 	fmt.Printf("Updated %s\n", updated.ID)
-	fmt.Printf("Linked beads: %s\n", strings.Join(updated.LinkedBeads, ", "))
+	fmt.Println("Linked beads:")
+	for _, link := range updated.LinkedBeads {
+		fmt.Printf("  - %s  [%s]\n", beadLabel(link.BeadID, known), link.Relation)
+	}
 	return nil
 }
 
@@ -530,6 +740,7 @@ func (c *HumanCLI) Delete(id string, force bool) error {
 	if err != nil {
 		return err
 	}
+	id = b.ID
 
 	if !force {
 		fmt.Printf("Deleting beat: %s\n", b.ID)
@@ -559,6 +770,7 @@ func (c *HumanCLI) Move(id string, targetDir string) error {
 	if err != nil {
 		return err
 	}
+	id = b.ID
 
 	// Create target store
 	targetStore, err := store.NewJSONLStore(targetDir)
@@ -675,9 +887,10 @@ func (c *HumanCLI) ListProjects(root string) error {
 }
 
 // GetDefaultRoot returns the default root directory for cross-project operations.
-// Uses BEATS_ROOT env var if set, otherwise tries to find a reasonable default.
+// Uses root from the config (BEATS_ROOT or the config file) if set, otherwise
+// tries to find a reasonable default.
 func GetDefaultRoot() string {
-	if root := os.Getenv("BEATS_ROOT"); root != "" {
+	if root := config.Get().Root; root != "" {
 		return root
 	}
 	// Try common locations
@@ -706,98 +919,9 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-// ParseRelativeDate parses a date string that can be:
-// - ISO8601 datetime (e.g., "2024-01-15", "2024-01-15T10:30:00Z")
-// - Relative string (e.g., "yesterday", "3d ago", "1 week ago")
-// Returns error if date is in the future.
-func ParseRelativeDate(s string) (time.Time, error) {
-	now := time.Now().UTC()
-	s = strings.TrimSpace(strings.ToLower(s))
-
-	// Try ISO8601 formats first
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05",
-		"2006-01-02",
-	}
-	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
-			t = t.UTC()
-			if t.After(now) {
-				return time.Time{}, fmt.Errorf("date cannot be in the future")
-			}
-			return t, nil
-		}
-	}
-
-	// Handle relative dates
-	var result time.Time
-	switch s {
-	case "yesterday":
-		result = now.AddDate(0, 0, -1)
-	case "today":
-		result = now
-	default:
-		// Parse patterns like "3d ago", "1 week ago", "2 weeks ago", "1w ago"
-		s = strings.TrimSuffix(s, " ago")
-		s = strings.TrimSpace(s)
-
-		// Try patterns: "3d", "3 days", "1w", "1 week", "2 weeks"
-		var num int
-		var unit string
-
-		// Try "Nd" or "Nw" format
-		if n, err := fmt.Sscanf(s, "%d%s", &num, &unit); err == nil && n == 2 {
-			unit = strings.TrimSpace(unit)
-		} else if n, err := fmt.Sscanf(s, "%d %s", &num, &unit); err == nil && n == 2 {
-			// "N unit" format
-		} else {
-			return time.Time{}, fmt.Errorf("unrecognized date format: %s", s)
-		}
-
-		switch unit {
-		case "d", "day", "days":
-			result = now.AddDate(0, 0, -num)
-		case "w", "week", "weeks":
-			result = now.AddDate(0, 0, -num*7)
-		case "m", "month", "months":
-			result = now.AddDate(0, -num, 0)
-		default:
-			return time.Time{}, fmt.Errorf("unrecognized time unit: %s", unit)
-		}
-	}
-
-	if result.After(now) {
-		return time.Time{}, fmt.Errorf("date cannot be in the future")
-	}
-	return result, nil
-}
-
 // EmbeddingsCompute generates embeddings for all beats
 func (c *HumanCLI) EmbeddingsCompute() error {
-	beats, err := c.store.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to read beats: %w", err)
-	}
-
-	embStore, err := embeddings.NewStore(c.store.Dir())
-	if err != nil {
-		return fmt.Errorf("failed to init embedding store: %w", err)
-	}
-
-	ollama := embeddings.NewOllamaClient()
-	if !ollama.IsAvailable() {
-		return fmt.Errorf("ollama not available (is it running?)")
-	}
-
-	fmt.Printf("Computing embeddings for %d beats...\n", len(beats))
-	result, err := embeddings.ComputeMissing(context.Background(), beats, embStore, ollama)
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Done: %d computed, %d skipped, %d errors\n", result.Computed, result.Skipped, result.Errors)
-	return nil
+	return c.Embed(EmbedOptions{})
 }
 
 // EmbeddingsStatus shows embedding coverage
@@ -890,42 +1014,6 @@ func (c *HumanCLI) BackfillContext(dryRun bool) error {
 	return nil
 }
 
-// WALDConfig represents the WALD.yaml structure.
-type WALDConfig struct {
-	Directories []WALDDirectory `yaml:"directories"`
-}
-
-// WALDDirectory represents a directory entry in WALD.yaml.
-type WALDDirectory struct {
-	Path    string      `yaml:"path"`
-	Purpose string      `yaml:"purpose"`
-	State   string      `yaml:"state"`
-	Gravity string      `yaml:"gravity"`
-	Claims  *WALDClaims `yaml:"claims,omitempty"`
-}
-
-// WALDClaims represents the claims a directory makes on beats.
-type WALDClaims struct {
-	Clusters    []string `yaml:"clusters,omitempty"`
-	Topics      []string `yaml:"topics,omitempty"`
-	Keywords    []string `yaml:"keywords,omitempty"`
-	Cooperators []string `yaml:"cooperators,omitempty"`
-}
-
-// loadWALDConfig loads WALD.yaml from the werk root.
-func loadWALDConfig(werkRoot string) (*WALDConfig, error) {
-	waldPath := filepath.Join(werkRoot, "WALD.yaml")
-	data, err := os.ReadFile(waldPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read WALD.yaml: %w", err)
-	}
-	var config WALDConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse WALD.yaml: %w", err)
-	}
-	return &config, nil
-}
-
 // claimedBeat represents a beat matched via a claim.
 type claimedBeat struct {
 	Beat       beat.Beat
@@ -1006,22 +1094,16 @@ func (c *HumanCLI) ContextWithOptions(path string, limit int, robotOutput bool)
 	}
 
 	// Load WALD.yaml to get claims
-	waldConfig, err := loadWALDConfig(werkRoot)
+	waldConfig, err := wald.Load(werkRoot)
 	if err != nil {
-		return fmt.Errorf("failed to load WALD.yaml: %w", err)
+		return err
 	}
 
 	// Find the directory entry and its claims
-	var dirEntry *WALDDirectory
-	for i := range waldConfig.Directories {
-		if waldConfig.Directories[i].Path == waldPath {
-			dirEntry = &waldConfig.Directories[i]
-			break
-		}
-	}
+	dirEntry := waldConfig.Directory(waldPath)
 
 	// Get claims (may be nil/empty)
-	var claims *WALDClaims
+	var claims *wald.Claims
 	if dirEntry != nil && dirEntry.Claims != nil {
 		claims = dirEntry.Claims
 	}
@@ -1103,7 +1185,7 @@ func (c *HumanCLI) ContextWithOptions(path string, limit int, robotOutput bool)
 	if totalBeats > 0 {
 		// Simple heuristic: more beats = hotter, recent beats = hotter
 		recentCount := 0
-		now := time.Now()
+		now := clock.Now()
 		for _, cb := range append(append(append(clusterBeats, topicBeats...), keywordBeats...), cooperatorBeats...) {
 			if now.Sub(cb.Beat.CreatedAt).Hours() < 24*7 { // within a week
 				recentCount++
@@ -1122,7 +1204,7 @@ func (c *HumanCLI) ContextWithOptions(path string, limit int, robotOutput bool)
 	return c.outputContextHuman(waldPath, temperature, claims, clusterBeats, topicBeats, keywordBeats, cooperatorBeats, totalBeats, limit)
 }
 
-func (c *HumanCLI) outputContextJSON(waldPath string, temperature float64, claims *WALDClaims,
+func (c *HumanCLI) outputContextJSON(waldPath string, temperature float64, claims *wald.Claims,
 	clusterBeats, topicBeats, keywordBeats, cooperatorBeats []claimedBeat, limit int) error {
 
 	toOutput := func(cbs []claimedBeat, maxItems int) []ClaimedBeatOutput {
@@ -1180,7 +1262,7 @@ func (c *HumanCLI) outputContextJSON(waldPath string, temperature float64, claim
 	return enc.Encode(output)
 }
 
-func (c *HumanCLI) outputContextHuman(waldPath string, temperature float64, claims *WALDClaims,
+func (c *HumanCLI) outputContextHuman(waldPath string, temperature float64, claims *wald.Claims,
 	clusterBeats, topicBeats, keywordBeats, cooperatorBeats []claimedBeat, totalBeats, limit int) error {
 
 	// Header
@@ -1263,22 +1345,15 @@ func (c *HumanCLI) outputContextHuman(waldPath string, temperature float64, clai
 // resolveToWALDPath resolves an absolute path to a WALD-relative path.
 // Returns (waldPath, werkRoot) where werkRoot is empty if not in a WALD workspace.
 func resolveToWALDPath(absPath string) (string, string) {
-	dir := absPath
-	for {
-		waldFile := filepath.Join(dir, "WALD.yaml")
-		if _, err := os.Stat(waldFile); err == nil {
-			relPath, _ := filepath.Rel(dir, absPath)
-			if relPath == "" {
-				relPath = "."
-			}
-			return relPath, dir
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return "", ""
-		}
-		dir = parent
+	dir := wald.FindRoot(absPath)
+	if dir == "" {
+		return "", ""
+	}
+	relPath, _ := filepath.Rel(dir, absPath)
+	if relPath == "" {
+		relPath = "."
 	}
+	return relPath, dir
 }
 
 // formatAge formats a time as a relative age string (e.g., "3d", "2h").
@@ -1299,38 +1374,68 @@ func formatAge(t time.Time) string {
 	return "now"
 }
 
-// SemanticSearch performs semantic search using embeddings
-func (c *HumanCLI) SemanticSearch(query string, maxResults int) error {
+// SemanticSearch performs semantic search using embeddings, optionally within
+// a WALD directory. A negative minScore uses semantic_min_score from
+// scoring.json.
+func (c *HumanCLI) SemanticSearch(query string, maxResults int, minScore float64, waldFilter string) error {
 	if maxResults <= 0 {
 		maxResults = 20
 	}
+	if minScore < 0 {
+		scoring, err := store.LoadScoringConfig(c.store.Dir())
+		if err != nil {
+			return err
+		}
+		minScore = scoring.SemanticMinScore
+	}
 
 	beats, err := c.store.ReadAll()
 	if err != nil {
 		return fmt.Errorf("failed to read beats: %w", err)
 	}
+	beats = filterWALD(beats, waldFilter)
 
 	embStore, err := embeddings.NewStore(c.store.Dir())
 	if err != nil {
 		return fmt.Errorf("failed to init embedding store: %w", err)
 	}
 
+	cache := embeddings.OpenDefaultCache(c.store.Dir())
+	defer func() { _ = cache.Save() }()
 	ollama := embeddings.NewOllamaClient()
-	if !ollama.IsAvailable() {
-		return fmt.Errorf("ollama not available (is it running?)")
+	ollama.SetModel(embStore.Model())
+	ollama.SetCache(cache)
+
+	mode := "semantic"
+	var results []beat.SearchResult
+	if embStore.Count() > 0 && ollama.IsAvailable() {
+		found, err := embeddings.SemanticSearch(context.Background(), query, beats, embStore, ollama, maxResults)
+		if err != nil {
+			return fmt.Errorf("semantic search failed: %w", err)
+		}
+		for _, r := range found {
+			results = append(results, beat.SearchResult{ID: r.ID, Score: r.Score, Content: r.Content, Impetus: r.Impetus})
+		}
+	} else {
+		// No embeddings available: use the local TF-IDF model instead
+		mode = "tf-idf, offline"
+		results = store.NewTFIDFIndex(beats).Search(query, maxResults)
 	}
 
-	results, err := embeddings.SemanticSearch(context.Background(), query, beats, embStore, ollama, maxResults)
-	if err != nil {
-		return fmt.Errorf("semantic search failed: %w", err)
+	kept := results[:0]
+	for _, r := range results {
+		if r.Score >= minScore {
+			kept = append(kept, r)
+		}
 	}
+	results = kept
 
 	if len(results) == 0 {
 		fmt.Printf("No beats found for: %s\n", query)
 		return nil
 	}
 
-	fmt.Printf("Found %d result(s) for \"%s\" (semantic):\n\n", len(results), query)
+	fmt.Printf("Found %d result(s) for \"%s\" (%s):\n\n", len(results), query, mode)
 	for _, r := range results {
 		preview := truncate(r.Content, 60)
 		fmt.Printf("  [%.3f] %s  %s\n", r.Score, r.ID, r.Impetus.Label)
@@ -1341,12 +1446,13 @@ func (c *HumanCLI) SemanticSearch(query string, maxResults int) error {
 
 // ExportOptions contains options for the export command.
 type ExportOptions struct {
-	Format  string // json, jsonl, csv
+	Format  string // json, jsonl, csv, jsonld
 	Since   string // datetime filter (created_at >= since)
 	Until   string // datetime filter (created_at <= until)
 	Impetus string // filter by impetus label (substring match)
 	Query   string // filter by content (substring match)
 	Output  string // output file path (empty = stdout)
+	Base    string // IRI prefix for beat nodes in JSON-LD (default urn:beats:)
 }
 
 // Export exports beats in the specified format with optional filters.
@@ -1439,6 +1545,12 @@ func (c *HumanCLI) Export(opts ExportOptions) error {
 				return fmt.Errorf("failed to write JSONL: %w", err)
 			}
 		}
+	case FormatJSONLD:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(buildJSONLD(filtered, opts.Base)); err != nil {
+			return fmt.Errorf("failed to write JSON-LD: %w", err)
+		}
 	case "csv":
 		if _, err := fmt.Fprintln(out, "id,created_at,updated_at,impetus_label,content"); err != nil {
 			return fmt.Errorf("failed to write CSV header: %w", err)
@@ -1456,7 +1568,7 @@ func (c *HumanCLI) Export(opts ExportOptions) error {
 			}
 		}
 	default:
-		return fmt.Errorf("unknown format: %s (use json, jsonl, or csv)", opts.Format)
+		return fmt.Errorf("unknown format: %s (use json, jsonl, csv or jsonld)", opts.Format)
 	}
 
 	return nil
@@ -1473,12 +1585,12 @@ func parseDateTime(s string) (time.Time, error) {
 	if strings.HasPrefix(s, "-") {
 		dur, err := time.ParseDuration(s[1:])
 		if err == nil {
-			return time.Now().Add(-dur), nil
+			return clock.Now().Add(-dur), nil
 		}
 		if strings.HasSuffix(s, "d") {
 			var n int
 			if _, err := fmt.Sscanf(s[1:], "%dd", &n); err == nil {
-				return time.Now().AddDate(0, 0, -n), nil
+				return clock.Now().AddDate(0, 0, -n), nil
 			}
 		}
 	}
@@ -1608,7 +1720,7 @@ func (c *HumanCLI) Import(filePath string, opts ImportOptions) error {
 			if b.ID == "" {
 				createdAt := b.CreatedAt
 				if createdAt.IsZero() {
-					createdAt = time.Now().UTC()
+					createdAt = clock.Now().UTC()
 				}
 				seq, err := c.store.NextSequenceForDate(createdAt)
 				if err != nil {
@@ -1626,7 +1738,7 @@ func (c *HumanCLI) Import(filePath string, opts ImportOptions) error {
 			if b.ID == "" {
 				createdAt := b.CreatedAt
 				if createdAt.IsZero() {
-					createdAt = time.Now().UTC()
+					createdAt = clock.Now().UTC()
 				}
 				seq, err := c.store.NextSequenceForDate(createdAt)
 				if err != nil {
@@ -1639,7 +1751,7 @@ func (c *HumanCLI) Import(filePath string, opts ImportOptions) error {
 		case "renumber":
 			createdAt := b.CreatedAt
 			if createdAt.IsZero() {
-				createdAt = time.Now().UTC()
+				createdAt = clock.Now().UTC()
 			}
 			seq, err := c.store.NextSequenceForDate(createdAt)
 			if err != nil {
@@ -1655,10 +1767,10 @@ func (c *HumanCLI) Import(filePath string, opts ImportOptions) error {
 
 		// Set timestamps if missing
 		if b.CreatedAt.IsZero() {
-			toImport[len(toImport)-1].CreatedAt = time.Now().UTC()
+			toImport[len(toImport)-1].CreatedAt = clock.Now().UTC()
 		}
 		if b.UpdatedAt.IsZero() {
-			toImport[len(toImport)-1].UpdatedAt = time.Now().UTC()
+			toImport[len(toImport)-1].UpdatedAt = clock.Now().UTC()
 		}
 	}
 
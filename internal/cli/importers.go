@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/importer"
+)
+
+// importProposed appends beats produced by an importer in one write. Items
+// whose impetus.meta source_id was imported from the same source before are
+// skipped, so re-running an import only adds new notes.
+func (c *HumanCLI) importProposed(source string, proposed []*beat.ProposedBeat, dryRun bool) error {
+	existing, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+
+	imported := make(map[string]bool)
+	nextSeq := make(map[string]int) // YYYYMMDD -> next free sequence
+	for _, b := range existing {
+		if b.Impetus.Meta["source"] == source && b.Impetus.Meta[importer.SourceIDKey] != "" {
+			imported[b.Impetus.Meta[importer.SourceIDKey]] = true
+		}
+		parts := strings.Split(b.ID, "-")
+		if len(parts) == 3 {
+			if seq, err := strconv.Atoi(parts[2]); err == nil && seq >= nextSeq[parts[1]] {
+				nextSeq[parts[1]] = seq + 1
+			}
+		}
+	}
+
+	var toImport []*beat.Beat
+	skipped := 0
+	for _, p := range proposed {
+		id := p.Impetus.Meta[importer.SourceIDKey]
+		if id != "" && imported[id] {
+			skipped++
+			continue
+		}
+		imported[id] = true
+		p.SetChannel(beat.ChannelImport)
+
+		createdAt := clock.Now().UTC()
+		if p.CreatedAt != nil {
+			createdAt = p.CreatedAt.UTC()
+		}
+		day := beat.IDDate(createdAt)
+		if nextSeq[day] == 0 {
+			nextSeq[day] = 1
+		}
+		toImport = append(toImport, p.ToBeat(nextSeq[day]))
+		nextSeq[day]++
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would import %d beat(s) from %s\n", len(toImport), source)
+		if skipped > 0 {
+			fmt.Printf("[dry-run] Skipped %d already imported\n", skipped)
+		}
+		for _, b := range toImport {
+			fmt.Printf("  %s  %s\n", b.ID, truncate(b.Impetus.Meta[importer.SourceIDKey], 60))
+		}
+		return nil
+	}
+
+	if err := c.store.AppendBulk(toImport); err != nil {
+		return fmt.Errorf("failed to write beats: %w", err)
+	}
+	fmt.Printf("Imported %d beat(s) from %s\n", len(toImport), source)
+	if skipped > 0 {
+		fmt.Printf("Skipped %d already imported\n", skipped)
+	}
+	return nil
+}
+
+// printImportWarnings reports items an importer could not convert.
+func printImportWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+}
+
+// ImportObsidian imports notes from an Obsidian vault, optionally limited to
+// folders or tags. Re-running skips notes imported before.
+func (c *HumanCLI) ImportObsidian(vault string, opts importer.ObsidianOptions, dryRun bool) error {
+	proposed, warnings, err := importer.Obsidian(vault, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read vault: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("obsidian", proposed, dryRun)
+}
+
+// ImportMarkdown imports a plain folder of markdown notes, one beat per file
+// or per "##" section. Re-running skips notes imported before.
+func (c *HumanCLI) ImportMarkdown(dir string, opts importer.MarkdownOptions, dryRun bool) error {
+	proposed, warnings, err := importer.Markdown(dir, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("markdown", proposed, dryRun)
+}
+
+// ImportLogseq imports the pages and journals of a Logseq graph directory.
+func (c *HumanCLI) ImportLogseq(graph string, opts importer.OutlineOptions, dryRun bool) error {
+	proposed, warnings, err := importer.Logseq(graph, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read graph: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("logseq", proposed, dryRun)
+}
+
+// ImportRoam imports a Roam Research JSON export.
+func (c *HumanCLI) ImportRoam(path string, opts importer.OutlineOptions, dryRun bool) error {
+	proposed, warnings, err := importer.Roam(path, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("roam", proposed, dryRun)
+}
+
+// ImportAppleNotes imports notes from the macOS Notes app, optionally one folder.
+func (c *HumanCLI) ImportAppleNotes(opts importer.AppleNotesOptions, dryRun bool) error {
+	proposed, warnings, err := importer.AppleNotes(opts)
+	if err != nil {
+		return fmt.Errorf("failed to read Apple Notes: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("apple-notes", proposed, dryRun)
+}
+
+// ImportICal imports past meetings from an .ics file, iCalendar feed or CalDAV
+// collection as "Meeting" beats with attendees as person entities.
+func (c *HumanCLI) ImportICal(source string, opts importer.ICalOptions, dryRun bool) error {
+	proposed, warnings, err := importer.ICal(source, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read calendar: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("ical", proposed, dryRun)
+}
+
+// ImportNotion imports a Notion export zip or a CSV file, one beat per
+// database row or page.
+func (c *HumanCLI) ImportNotion(source string, opts importer.NotionOptions, dryRun bool) error {
+	proposed, warnings, err := importer.Notion(source, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	printImportWarnings(warnings)
+	return c.importProposed("notion", proposed, dryRun)
+}
+
+// ImportGitOptions configures a git history import.
+type ImportGitOptions struct {
+	capture.GitLogOptions
+	Summarize bool // One LLM-summarized beat per day instead of one per commit
+	DryRun    bool
+}
+
+// ImportGit converts a repository's history into "Code change" beats. Commits
+// already captured (by the post-commit hook or an earlier import) are skipped.
+func (c *HumanCLI) ImportGit(repoDir string, opts ImportGitOptions) error {
+	commits, err := capture.GitLog(repoDir, opts.GitLogOptions)
+	if err != nil {
+		return err
+	}
+
+	existing, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	captured := make(map[string]bool)
+	for _, b := range existing {
+		if hash := b.Impetus.Meta["commit"]; hash != "" {
+			captured[hash] = true
+		}
+		for _, hash := range strings.Split(b.Impetus.Meta["commits"], ",") {
+			captured[hash] = true
+		}
+	}
+	var fresh []*capture.GitCommit
+	for _, commit := range commits {
+		if !captured[commit.Hash] {
+			fresh = append(fresh, commit)
+		}
+	}
+	if skipped := len(commits) - len(fresh); skipped > 0 {
+		fmt.Printf("Skipping %d commit(s) already captured\n", skipped)
+	}
+
+	var proposed []*beat.ProposedBeat
+	if opts.Summarize {
+		proposed = c.gitDayBeats(fresh)
+	} else {
+		for _, commit := range fresh {
+			p := gitCommitBeat(commit)
+			p.Impetus.Meta[importer.SourceIDKey] = commit.Hash
+			proposed = append(proposed, p)
+		}
+	}
+	return c.importProposed("git", proposed, opts.DryRun)
+}
+
+// gitDayBeats groups commits by day and summarizes each day with the LLM
+// configured for session summaries, falling back to the list of subjects.
+func (c *HumanCLI) gitDayBeats(commits []*capture.GitCommit) []*beat.ProposedBeat {
+	llm := hooks.GetSessionEndConfig(c.store.Dir())
+
+	var days []string
+	byDay := make(map[string][]*capture.GitCommit)
+	for _, commit := range commits {
+		day := commit.Date.Format("2006-01-02")
+		if byDay[day] == nil {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], commit)
+	}
+
+	var proposed []*beat.ProposedBeat
+	llmFailed := false
+	for _, day := range days {
+		batch := byDay[day]
+		first := batch[0]
+
+		var log strings.Builder
+		hashes := make([]string, len(batch))
+		authors := make(map[string]bool)
+		for i, commit := range batch {
+			hashes[i] = commit.Hash
+			authors[commit.Author] = true
+			fmt.Fprintf(&log, "- %s %s", commit.ShortHash(), commit.Subject)
+			if len(commit.Files) > 0 {
+				fmt.Fprintf(&log, " (%d file(s))", len(commit.Files))
+			}
+			log.WriteString("\n")
+		}
+
+		meta := map[string]string{
+			"source":             "git",
+			"repo":               first.Repo,
+			"commits":            strings.Join(hashes, ","),
+			"day":                day,
+			importer.SourceIDKey: first.Repo + "@" + day + "@" + batch[len(batch)-1].ShortHash(),
+		}
+		summary := ""
+		if !llmFailed {
+			prompt := fmt.Sprintf("Summarize what was worked on in the %s repository on %s in 1-3 sentences, based on these commits. Be specific, no preamble:\n\n%s", first.Repo, day, log.String())
+			var err error
+			if summary, err = llm.Generate(prompt); err != nil {
+				// Don't retry a down or missing model for every remaining day
+				fmt.Printf("Warning: summaries unavailable (%v); listing commits instead\n", err)
+				llmFailed = true
+			} else {
+				meta["summary_model"] = llm.OllamaModel
+			}
+		}
+
+		content := fmt.Sprintf("%s: %d commit(s) on %s", first.Repo, len(batch), day)
+		if summary != "" {
+			content = summary + "\n\n" + content
+		}
+		content += "\n\n" + strings.TrimSpace(log.String())
+
+		createdAt := batch[len(batch)-1].Date
+		proposed = append(proposed, &beat.ProposedBeat{
+			Content: content,
+			Impetus: beat.Impetus{
+				Label: "Code change",
+				Raw:   fmt.Sprintf("%s %s", first.Repo, day),
+				Meta:  meta,
+			},
+			References: []beat.Reference{{
+				Kind:    "git",
+				Subtype: "range",
+				Locator: first.RepoPath + "@" + batch[0].ShortHash() + "^.." + batch[len(batch)-1].ShortHash(),
+				Label:   fmt.Sprintf("%d commit(s)", len(batch)),
+			}},
+			Entities: []beat.Entity{{
+				Label:    first.Repo,
+				Category: "project",
+				Meta: map[string]string{
+					"confidence": "1.0",
+					"repo_path":  first.RepoPath,
+				},
+			}},
+			LinkedBeads: []string{},
+			CreatedAt:   &createdAt,
+		})
+	}
+	return proposed
+}
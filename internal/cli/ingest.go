@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// IngestSourceKind marks the parent beat of an ingested document in
+// impetus.meta["kind"]; its chunks name it in impetus.meta["ingest_source"].
+const IngestSourceKind = "ingest_source"
+
+// defaultIngestChunk is the most characters a chunk holds by default.
+const defaultIngestChunk = 2000
+
+// IngestOptions configures bt ingest.
+type IngestOptions struct {
+	Title    string // Document title (default: its first heading or line)
+	Impetus  string // Label for every chunk (default: inferred per chunk)
+	MaxChars int    // Longest chunk (default 2000)
+	Yes      bool   // Commit without asking
+	DryRun   bool   // Only show the chunks
+	JSON     bool
+}
+
+// IngestChunk is one part of an ingested document.
+type IngestChunk struct {
+	Heading string `json:"heading,omitempty"` // The heading it falls under
+	Content string `json:"content"`
+	ID      string `json:"id,omitempty"` // Once committed
+	Error   string `json:"error,omitempty"`
+}
+
+// IngestResult is the outcome of bt ingest.
+type IngestResult struct {
+	Title     string        `json:"title"`
+	Words     int           `json:"words"`
+	Committed bool          `json:"committed"`
+	SourceID  string        `json:"source_id,omitempty"`
+	Chunks    []IngestChunk `json:"chunks"`
+}
+
+var (
+	ingestHeadingRe  = regexp.MustCompile(`^#{1,3}\s+(.+?)\s*#*$`)
+	ingestSentenceRe = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+)
+
+// chunkDocument splits a document into chunks of at most maxChars. It cuts
+// at headings first, then between paragraphs, lines and sentences as a
+// section needs, and packs the pieces of a long section back together up to
+// the limit. Fenced code blocks are kept whole where they fit. A heading
+// with nothing under it before the next one is kept with the next section.
+func chunkDocument(text string, maxChars int) []IngestChunk {
+	if maxChars <= 0 {
+		maxChars = defaultIngestChunk
+	}
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(strings.TrimPrefix(text, "\ufeff"))
+
+	type section struct {
+		heading string
+		lines   []string
+		body    bool // Has more than headings and blank lines
+	}
+	var sections []section
+	cur := section{}
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		fence := strings.HasPrefix(strings.TrimSpace(line), "```") || strings.HasPrefix(strings.TrimSpace(line), "~~~")
+		if m := ingestHeadingRe.FindStringSubmatch(line); m != nil && !inFence {
+			if cur.body {
+				sections = append(sections, cur)
+				cur = section{}
+			}
+			cur.heading = m[1]
+		} else if strings.TrimSpace(line) != "" {
+			cur.body = true
+		}
+		if fence {
+			inFence = !inFence
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	sections = append(sections, cur)
+
+	var chunks []IngestChunk
+	for _, s := range sections {
+		content := strings.TrimSpace(strings.Join(s.lines, "\n"))
+		if content == "" {
+			continue
+		}
+		if len(content) <= maxChars {
+			chunks = append(chunks, IngestChunk{Heading: s.heading, Content: content})
+			continue
+		}
+		var pieces []string
+		for _, block := range splitBlocks(content) {
+			pieces = append(pieces, splitLong(block, maxChars)...)
+		}
+		for _, packed := range packJoined(pieces, "\n\n", maxChars) {
+			chunks = append(chunks, IngestChunk{Heading: s.heading, Content: packed})
+		}
+	}
+	return chunks
+}
+
+// splitBlocks splits text into paragraphs at blank lines outside fenced code.
+func splitBlocks(text string) []string {
+	var blocks, cur []string
+	inFence := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") || strings.HasPrefix(strings.TrimSpace(line), "~~~") {
+			inFence = !inFence
+		}
+		if strings.TrimSpace(line) == "" && !inFence {
+			if len(cur) > 0 {
+				blocks = append(blocks, strings.Join(cur, "\n"))
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, strings.Join(cur, "\n"))
+	}
+	return blocks
+}
+
+// splitLong cuts a paragraph longer than maxChars at line ends, then at
+// sentence ends and, for a sentence that is still too long, at the last
+// space that fits.
+func splitLong(block string, maxChars int) []string {
+	if len(block) <= maxChars {
+		return []string{block}
+	}
+	if lines := strings.Split(block, "\n"); len(lines) > 1 {
+		var out []string
+		for _, l := range lines {
+			out = append(out, splitLong(l, maxChars)...)
+		}
+		return packJoined(out, "\n", maxChars)
+	}
+	var sentences []string
+	rest := block
+	for {
+		loc := ingestSentenceRe.FindStringIndex(rest)
+		if loc == nil {
+			break
+		}
+		sentences = append(sentences, strings.TrimSpace(rest[:loc[1]]))
+		rest = rest[loc[1]:]
+	}
+	if rest = strings.TrimSpace(rest); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	var out []string
+	for _, s := range sentences {
+		for len(s) > maxChars {
+			cut := strings.LastIndex(s[:maxChars], " ")
+			if cut <= 0 {
+				cut = maxChars
+			}
+			out = append(out, strings.TrimSpace(s[:cut]))
+			s = strings.TrimSpace(s[cut:])
+		}
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return packJoined(out, " ", maxChars)
+}
+
+// packJoined greedily joins parts with sep into strings of at most maxChars.
+func packJoined(parts []string, sep string, maxChars int) []string {
+	var out []string
+	cur := ""
+	for _, p := range parts {
+		switch {
+		case cur == "":
+			cur = p
+		case len(cur)+len(sep)+len(p) <= maxChars:
+			cur += sep + p
+		default:
+			out = append(out, cur)
+			cur = p
+		}
+	}
+	if cur != "" {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// documentTitle is the document's first heading, or else its first line.
+func documentTitle(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if m := ingestHeadingRe.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+		return truncate(line, 60)
+	}
+	return "Pasted document"
+}
+
+// sourceBeat is the parent of an ingested document's chunks. The full text
+// is kept as an attachment.
+func (c *HumanCLI) sourceBeat(title, text, hash string, chunks int) *beat.ProposedBeat {
+	words := len(strings.Fields(text))
+	p := &beat.ProposedBeat{
+		Content: fmt.Sprintf("%s\n\nIngested document: %d words in %d beat(s).", title, words, chunks),
+		Impetus: beat.Impetus{
+			Label: "Ingested document",
+			Raw:   title,
+			Meta: map[string]string{
+				"kind":   IngestSourceKind,
+				"source": "ingest",
+				"title":  title,
+				"sha256": hash,
+				"chunks": fmt.Sprint(chunks),
+				"words":  fmt.Sprint(words),
+			},
+		},
+		References:  []beat.Reference{},
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+	}
+	if rel, err := c.store.SaveAttachment([]byte(text+"\n"), ".md"); err == nil {
+		p.References = append(p.References, beat.Reference{
+			Kind:    "attachment",
+			Subtype: "text/markdown",
+			Locator: rel,
+			Label:   "Full text",
+			Meta:    map[string]string{"words": fmt.Sprint(words)},
+		})
+	}
+	return p
+}
+
+// chunkBeat is one chunk as a beat, pointing at its source beat.
+func chunkBeat(ch IngestChunk, i, n int, title, impetus, sourceID string) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source":        "ingest",
+		"ingest_source": sourceID,
+		"title":         title,
+		"chunk":         fmt.Sprintf("%d/%d", i+1, n),
+	}
+	if ch.Heading != "" {
+		meta["heading"] = ch.Heading
+	}
+	return &beat.ProposedBeat{
+		Content:     ch.Content,
+		Impetus:     beat.Impetus{Label: impetus, Raw: title, Meta: meta},
+		References:  []beat.Reference{{Kind: "beat", Subtype: "source", Locator: sourceID, Label: title}},
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+	}
+}
+
+// confirmIngest asks on the terminal whether to commit. The document came
+// in on stdin, so the answer is read from /dev/tty.
+func confirmIngest(n int) (bool, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false, fmt.Errorf("no terminal to confirm on; use --yes to commit or --dry-run to preview")
+	}
+	defer tty.Close()
+	fmt.Printf("\nCommit a source beat and %d beat(s)? [y/N] ", n)
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// Ingest splits a long document or transcript into chunks and, once
+// confirmed, stores a parent source beat and one beat per chunk that
+// references it. Each chunk goes through the capture pipeline on its own.
+func (c *HumanCLI) Ingest(r io.Reader, opts IngestOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return fmt.Errorf("the document is empty")
+	}
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+
+	res := &IngestResult{Title: opts.Title, Words: len(strings.Fields(text)), Chunks: chunkDocument(text, opts.MaxChars)}
+	if res.Title == "" {
+		res.Title = documentTitle(text)
+	}
+
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] == IngestSourceKind && b.Impetus.Meta["sha256"] == hash {
+			return fmt.Errorf("this document was already ingested as %s", b.ID)
+		}
+	}
+
+	if !opts.JSON {
+		fmt.Printf("%s: %d words in %d chunk(s)\n", res.Title, res.Words, len(res.Chunks))
+		for i, ch := range res.Chunks {
+			heading := ""
+			if ch.Heading != "" {
+				heading = " [" + ch.Heading + "]"
+			}
+			fmt.Printf("  %2d.%s %d chars: %s\n", i+1, heading, len(ch.Content), truncate(strings.Join(strings.Fields(ch.Content), " "), 70))
+		}
+	}
+	if opts.DryRun {
+		if opts.JSON {
+			return outputJSON(res)
+		}
+		fmt.Println("[dry-run] Nothing stored")
+		return nil
+	}
+	if !opts.Yes {
+		ok, err := confirmIngest(len(res.Chunks))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+
+	source, err := c.commit(c.sourceBeat(res.Title, text, hash, len(res.Chunks)), beat.ChannelManual)
+	if err != nil {
+		return fmt.Errorf("failed to store the source beat: %w", err)
+	}
+	res.SourceID, res.Committed = source.ID, true
+	failed := 0
+	for i := range res.Chunks {
+		ch := &res.Chunks[i]
+		b, err := c.commit(chunkBeat(*ch, i, len(res.Chunks), res.Title, opts.Impetus, source.ID), beat.ChannelManual)
+		if err != nil {
+			ch.Error = err.Error()
+			failed++
+			continue
+		}
+		ch.ID = b.ID
+	}
+
+	if opts.JSON {
+		if err := outputJSON(res); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Created source beat %s and %d beat(s)\n", source.ID, len(res.Chunks)-failed)
+		for i, ch := range res.Chunks {
+			if ch.Error != "" {
+				fmt.Printf("  chunk %d not stored: %s\n", i+1, ch.Error)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d chunk(s) were not stored", failed, len(res.Chunks))
+	}
+	return nil
+}
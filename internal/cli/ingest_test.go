@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestChunkDocument(t *testing.T) {
+	long := strings.Repeat("This sentence is about queues. ", 20) // 620 chars, one line
+	doc := "# Notes\n\n## Queues\n\n" + long + "\n\n```\n## not a heading\n```\n\n## Empty\n### Hiring\nOne more engineer.\n"
+
+	chunks := chunkDocument(doc, 300)
+	var headings []string
+	for _, ch := range chunks {
+		if len(ch.Content) > 300 {
+			t.Errorf("chunk of %d chars: %q", len(ch.Content), ch.Content)
+		}
+		headings = append(headings, ch.Heading)
+	}
+	if want := "Queues Queues Queues Hiring"; strings.Join(headings, " ") != want {
+		t.Errorf("chunk headings = %q, want %q", headings, want)
+	}
+	if !strings.HasSuffix(chunks[1].Content, "queues.") || !strings.Contains(chunks[2].Content, "## not a heading") {
+		t.Errorf("chunks = %+v; want cuts at sentence ends and the code block kept", chunks)
+	}
+	if last := chunks[len(chunks)-1].Content; !strings.HasPrefix(last, "## Empty\n### Hiring") {
+		t.Errorf("last chunk = %q, want the empty heading kept with the next", last)
+	}
+
+	if got := chunkDocument("A short note.", 0); len(got) != 1 || got[0].Content != "A short note." {
+		t.Errorf("chunkDocument(short) = %+v", got)
+	}
+}
+
+func TestIngest(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewHumanCLI(s)
+	doc := "# Offsite\n\n## Pricing\nRaise the team plan.\n\n## Hiring\nOpen a backend role.\n"
+	if err := c.Ingest(strings.NewReader(doc), IngestOptions{Yes: true, Impetus: "Offsite notes"}); err != nil {
+		t.Fatal(err)
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil || len(beats) != 3 {
+		t.Fatalf("beats = %d, %v; want a source beat and two chunks", len(beats), err)
+	}
+	source := beats[0]
+	if source.Impetus.Meta["kind"] != IngestSourceKind || source.Impetus.Raw != "Offsite" || len(source.References) != 1 || source.References[0].Kind != "attachment" {
+		t.Errorf("source beat = %+v", source)
+	}
+	for i, b := range beats[1:] {
+		if b.Impetus.Label != "Offsite notes" || b.Impetus.Meta["ingest_source"] != source.ID ||
+			b.Impetus.Meta["chunk"] != []string{"1/2", "2/2"}[i] || b.References[0].Locator != source.ID {
+			t.Errorf("chunk beat %d = %+v; want it to point at %s", i, b, source.ID)
+		}
+	}
+
+	if err := c.Ingest(strings.NewReader(doc), IngestOptions{Yes: true}); err == nil || !strings.Contains(err.Error(), source.ID) {
+		t.Errorf("second ingest = %v, want already ingested as %s", err, source.ID)
+	}
+}
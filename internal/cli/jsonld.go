@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// FormatJSONLD exports beats as a schema.org JSON-LD graph.
+const FormatJSONLD = "jsonld"
+
+// DefaultJSONLDBase prefixes beat IDs to make the node IRIs of a JSON-LD
+// export.
+const DefaultJSONLDBase = "urn:beats:"
+
+// buildJSONLD maps beats onto schema.org: each beat is a NoteDigitalDocument
+// (a CreativeWork) with its entities as mentions, beat and web references as
+// citations, attachments as associated media and annotations as comments.
+// Node IRIs are base followed by the beat ID, so citations between exported
+// beats resolve to the same nodes.
+func buildJSONLD(beats []beat.Beat, base string) map[string]interface{} {
+	if base == "" {
+		base = DefaultJSONLDBase
+	}
+	graph := make([]map[string]interface{}, 0, len(beats))
+	for _, b := range beats {
+		node := map[string]interface{}{
+			"@id":          base + b.ID,
+			"@type":        []string{"CreativeWork", "NoteDigitalDocument"},
+			"identifier":   b.ID,
+			"dateCreated":  b.CreatedAt.UTC().Format(time.RFC3339),
+			"dateModified": b.UpdatedAt.UTC().Format(time.RFC3339),
+			"text":         b.Content,
+		}
+		if b.Impetus.Label != "" {
+			node["genre"] = b.Impetus.Label
+		}
+
+		var mentions []map[string]interface{}
+		var keywords []string
+		for _, e := range b.Entities {
+			if e.Category == "topic" {
+				keywords = append(keywords, e.Label)
+				continue
+			}
+			mentions = append(mentions, map[string]interface{}{"@type": schemaType(e.Category), "name": e.Label})
+		}
+		if len(mentions) > 0 {
+			node["mentions"] = mentions
+		}
+		if len(keywords) > 0 {
+			node["keywords"] = keywords
+		}
+
+		var citations, media []map[string]interface{}
+		for _, r := range b.References {
+			switch {
+			case r.Kind == "beat":
+				citations = append(citations, map[string]interface{}{"@id": base + r.Locator})
+			case r.Kind == "attachment":
+				m := map[string]interface{}{"@type": "MediaObject", "contentUrl": r.Locator}
+				if r.Label != "" {
+					m["name"] = r.Label
+				}
+				media = append(media, m)
+			case strings.HasPrefix(r.Locator, "http://") || strings.HasPrefix(r.Locator, "https://"):
+				citations = append(citations, map[string]interface{}{"@id": r.Locator, "@type": "WebPage", "url": r.Locator})
+			default:
+				citations = append(citations, map[string]interface{}{"@type": "CreativeWork", "name": r.Locator, "additionalType": r.Kind})
+			}
+		}
+		if len(citations) > 0 {
+			node["citation"] = citations
+		}
+		if len(media) > 0 {
+			node["associatedMedia"] = media
+		}
+
+		if len(b.Annotations) > 0 {
+			comments := make([]map[string]interface{}, len(b.Annotations))
+			for i, a := range b.Annotations {
+				comments[i] = map[string]interface{}{
+					"@type":       "Comment",
+					"text":        a.Note,
+					"author":      map[string]interface{}{"@type": "Person", "name": a.Author},
+					"dateCreated": a.CreatedAt.UTC().Format(time.RFC3339),
+				}
+			}
+			node["comment"] = comments
+		}
+		graph = append(graph, node)
+	}
+	return map[string]interface{}{
+		"@context": "https://schema.org/",
+		"@graph":   graph,
+	}
+}
+
+// schemaType is the schema.org type for an entity category.
+func schemaType(category string) string {
+	switch category {
+	case "person":
+		return "Person"
+	case "organization":
+		return "Organization"
+	case "place", "location":
+		return "Place"
+	case "project":
+		return "Project"
+	case "url":
+		return "WebPage"
+	default:
+		return "Thing"
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestBuildJSONLD(t *testing.T) {
+	at := time.Date(2025, 12, 4, 9, 0, 0, 0, time.UTC)
+	beats := []beat.Beat{{
+		ID: "beat-20251204-002", CreatedAt: at, UpdatedAt: at,
+		Impetus: beat.Impetus{Label: "Call"},
+		Content: "Jane agreed, see [[beat-20251204-001]]",
+		Entities: []beat.Entity{
+			{Label: "Jane", Category: "person"},
+			{Label: "pricing", Category: "topic"},
+		},
+		References: []beat.Reference{
+			{Kind: "beat", Subtype: beat.CitationSubtype, Locator: "beat-20251204-001"},
+			{Kind: "url", Locator: "https://example.com/deck"},
+			{Kind: "attachment", Locator: "blobs/3f/3f9a.txt", Label: "Full text"},
+		},
+	}}
+
+	data, err := json.Marshal(buildJSONLD(beats, "https://notes.example.com/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Context string `json:"@context"`
+		Graph   []struct {
+			ID       string   `json:"@id"`
+			Type     []string `json:"@type"`
+			Genre    string   `json:"genre"`
+			Keywords []string `json:"keywords"`
+			Mentions []struct {
+				Type string `json:"@type"`
+				Name string `json:"name"`
+			} `json:"mentions"`
+			Citation []struct {
+				ID string `json:"@id"`
+			} `json:"citation"`
+			Media []struct {
+				URL string `json:"contentUrl"`
+			} `json:"associatedMedia"`
+		} `json:"@graph"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Context != "https://schema.org/" || len(doc.Graph) != 1 {
+		t.Fatalf("document = %s", data)
+	}
+	n := doc.Graph[0]
+	if n.ID != "https://notes.example.com/beat-20251204-002" || strings.Join(n.Type, ",") != "CreativeWork,NoteDigitalDocument" || n.Genre != "Call" {
+		t.Errorf("node = %+v", n)
+	}
+	if len(n.Mentions) != 1 || n.Mentions[0].Type != "Person" || len(n.Keywords) != 1 || n.Keywords[0] != "pricing" {
+		t.Errorf("mentions = %+v, keywords = %v; want Jane as a Person and pricing as a keyword", n.Mentions, n.Keywords)
+	}
+	if len(n.Citation) != 2 || n.Citation[0].ID != "https://notes.example.com/beat-20251204-001" || n.Citation[1].ID != "https://example.com/deck" {
+		t.Errorf("citations = %+v", n.Citation)
+	}
+	if len(n.Media) != 1 || n.Media[0].URL != "blobs/3f/3f9a.txt" {
+		t.Errorf("media = %+v", n.Media)
+	}
+}
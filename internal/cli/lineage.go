@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+// RelationMention marks a lineage beat that names the bead without being
+// linked to it.
+const RelationMention = "mention"
+
+// LineageBeat is a beat in a bead's lineage.
+type LineageBeat struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Content   string    `json:"content"`
+	Relation  string    `json:"relation"`
+	LinkedAt  time.Time `json:"linked_at,omitzero"`
+	LinkedBy  string    `json:"linked_by,omitempty"`
+}
+
+// LineageSynthesis is an archived synthesis that clustered seed beats.
+type LineageSynthesis struct {
+	ID          string    `json:"id"`
+	TriggeredAt time.Time `json:"triggered_at"`
+	BeatIDs     []string  `json:"beat_ids"` // Seed beats it included
+	Responded   bool      `json:"responded"`
+}
+
+// LineagePromotion is the promotion of beats into the bead.
+type LineagePromotion struct {
+	At      string   `json:"at,omitempty"`
+	BeatIDs []string `json:"beat_ids"`
+}
+
+// BeadLineage is the narrative ancestry of a bead.
+type BeadLineage struct {
+	Bead      beads.Bead         `json:"bead"`
+	Seeds     []LineageBeat      `json:"seeds"`
+	Syntheses []LineageSynthesis `json:"syntheses"`
+	Promotion *LineagePromotion  `json:"promotion,omitempty"`
+	Later     []LineageBeat      `json:"later"` // Evidence, blockers, retrospectives and mentions
+}
+
+// Lineage shows where a bead came from: the beats that seeded it, the
+// syntheses that clustered them, its promotion, and the beats that
+// referenced it afterwards.
+func (c *HumanCLI) Lineage(beadID string, jsonOut bool) error {
+	known, _, err := lookupBeads(c.store.Dir(), []string{beadID})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not look up bead: %v\n", err)
+	}
+	bead, ok := known[beadID]
+	if !ok {
+		bead = beads.Bead{ID: beadID}
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return err
+	}
+	syntheses, err := hooks.ListSyntheses(c.store.Dir())
+	if err != nil {
+		return fmt.Errorf("failed to read syntheses: %w", err)
+	}
+	lineage := lineageOf(bead, all, syntheses)
+
+	if jsonOut {
+		return outputJSON(lineage)
+	}
+	printLineage(lineage)
+	return nil
+}
+
+// lineageOf assembles a bead's lineage from the store and the synthesis
+// archive. Beats are listed oldest first.
+func lineageOf(bead beads.Bead, all []beat.Beat, syntheses []hooks.SynthesisRecord) BeadLineage {
+	l := BeadLineage{
+		Bead:      bead,
+		Seeds:     []LineageBeat{},
+		Syntheses: []LineageSynthesis{},
+		Later:     []LineageBeat{},
+	}
+
+	seeds := make(map[string]bool)
+	var promoted []beat.Beat
+	for _, b := range all {
+		entry := LineageBeat{
+			ID:        b.ID,
+			CreatedAt: b.CreatedAt,
+			Impetus:   b.Impetus.Label,
+			Content:   b.Content,
+		}
+		if b.Impetus.Meta["promoted_to"] == bead.ID {
+			promoted = append(promoted, b)
+		}
+		if link, ok := b.LinkedBeads.Get(bead.ID); ok {
+			entry.Relation, entry.LinkedAt, entry.LinkedBy = link.Relation, link.CreatedAt, link.CreatedBy
+			if link.Relation == beat.RelationSeed {
+				seeds[b.ID] = true
+				l.Seeds = append(l.Seeds, entry)
+			} else {
+				l.Later = append(l.Later, entry)
+			}
+			continue
+		}
+		if mentionsBead(b, bead.ID) {
+			entry.Relation = RelationMention
+			l.Later = append(l.Later, entry)
+		}
+	}
+	byTime := func(list []LineageBeat) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	}
+	byTime(l.Seeds)
+	byTime(l.Later)
+
+	for _, rec := range syntheses {
+		var ids []string
+		for _, b := range rec.Request.RecentBeats {
+			if seeds[b.ID] {
+				ids = append(ids, b.ID)
+			}
+		}
+		if len(ids) > 0 {
+			l.Syntheses = append(l.Syntheses, LineageSynthesis{
+				ID:          rec.ID,
+				TriggeredAt: rec.Request.TriggeredAt,
+				BeatIDs:     ids,
+				Responded:   rec.Response != "",
+			})
+		}
+	}
+	sort.SliceStable(l.Syntheses, func(i, j int) bool {
+		return l.Syntheses[i].TriggeredAt.Before(l.Syntheses[j].TriggeredAt)
+	})
+
+	if len(promoted) > 0 {
+		l.Promotion = &LineagePromotion{}
+		for _, b := range promoted {
+			l.Promotion.BeatIDs = append(l.Promotion.BeatIDs, b.ID)
+			if at := b.Impetus.Meta["promoted_at"]; at > l.Promotion.At {
+				l.Promotion.At = at
+			}
+		}
+	}
+	return l
+}
+
+// mentionsBead reports whether a beat names the bead ID in its content or
+// references as a whole word, so bd-1 does not match bd-12.
+func mentionsBead(b beat.Beat, id string) bool {
+	texts := []string{b.Content}
+	for _, ref := range b.References {
+		texts = append(texts, ref.Locator, ref.Label)
+	}
+	for _, text := range texts {
+		for i := 0; ; {
+			j := strings.Index(text[i:], id)
+			if j < 0 {
+				break
+			}
+			start, end := i+j, i+j+len(id)
+			if (start == 0 || !isIDChar(text[start-1])) && (end == len(text) || !isIDChar(text[end])) {
+				return true
+			}
+			i = start + 1
+		}
+	}
+	return false
+}
+
+func isIDChar(c byte) bool {
+	return c == '-' || c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// printLineage renders a lineage as a tree.
+func printLineage(l BeadLineage) {
+	header := l.Bead.ID
+	if l.Bead.Title != "" {
+		header += "  " + l.Bead.Title
+	}
+	if l.Bead.Status != "" {
+		header += " (" + l.Bead.Status + ")"
+	}
+	fmt.Println(header)
+
+	type branch struct {
+		label string
+		items []string
+	}
+	beatLine := func(b LineageBeat, relation bool) string {
+		line := fmt.Sprintf("%s  %s  %s", b.ID, displayDate(b.CreatedAt), b.Impetus)
+		if relation {
+			line += " [" + b.Relation + "]"
+		}
+		return line + ": " + truncate(b.Content, 60)
+	}
+
+	var branches []branch
+	seeds := branch{label: fmt.Sprintf("Seed beats (%d)", len(l.Seeds))}
+	for _, b := range l.Seeds {
+		seeds.items = append(seeds.items, beatLine(b, false))
+	}
+	branches = append(branches, seeds)
+	if len(l.Syntheses) > 0 {
+		s := branch{label: fmt.Sprintf("Syntheses (%d)", len(l.Syntheses))}
+		for _, syn := range l.Syntheses {
+			line := fmt.Sprintf("%s  %s  clustered %s", syn.ID, displayDate(syn.TriggeredAt), strings.Join(syn.BeatIDs, ", "))
+			if syn.Responded {
+				line += " [responded]"
+			}
+			s.items = append(s.items, line)
+		}
+		branches = append(branches, s)
+	}
+	if p := l.Promotion; p != nil {
+		label := "Promoted"
+		if len(p.At) >= len("2006-01-02") {
+			label += " " + p.At[:len("2006-01-02")]
+		}
+		branches = append(branches, branch{label: label + " from " + strings.Join(p.BeatIDs, ", ")})
+	}
+	later := branch{label: fmt.Sprintf("Later beats (%d)", len(l.Later))}
+	for _, b := range l.Later {
+		later.items = append(later.items, beatLine(b, true))
+	}
+	branches = append(branches, later)
+
+	for i, br := range branches {
+		last := i == len(branches)-1
+		prefix, indent := "├── ", "│   "
+		if last {
+			prefix, indent = "└── ", "    "
+		}
+		fmt.Println(prefix + br.label)
+		for j, item := range br.items {
+			if j == len(br.items)-1 {
+				fmt.Println(indent + "└── " + item)
+			} else {
+				fmt.Println(indent + "├── " + item)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+func TestLineage(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+
+	s, setBeads := beadsFixture(t)
+	setBeads(`{"id":"bd-7","title":"Queue sharding","status":"open"}` + "\n")
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC) }
+	promoted := map[string]string{"promoted_to": "bd-7", "promoted_at": "2026-03-05T10:00:00Z"}
+	seedB := &beat.Beat{ID: "beat-20260302-001", CreatedAt: day(2), Impetus: beat.Impetus{Label: "Manual entry", Meta: promoted}, Content: "Shard the queue by tenant", LinkedBeads: linked("bd-7")}
+	seedA := &beat.Beat{ID: "beat-20260301-001", CreatedAt: day(1), Impetus: beat.Impetus{Label: "Manual entry", Meta: promoted}, Content: "One queue cannot keep up", LinkedBeads: linked("bd-7")}
+	unrelated := &beat.Beat{ID: "beat-20260303-001", CreatedAt: day(3), Impetus: beat.Impetus{Label: "Manual entry"}, Content: "Pricing tiers confuse people"}
+	for _, b := range []*beat.Beat{
+		seedB, seedA, unrelated, // Out of order: the lineage sorts by creation
+		{ID: "beat-20260310-001", CreatedAt: day(10), Impetus: beat.Impetus{Label: "Research"}, Content: "Benchmarks of sharded queues",
+			LinkedBeads: beat.BeadLinks{{BeadID: "bd-7", Relation: beat.RelationEvidence, CreatedAt: day(11), CreatedBy: "ada"}}},
+		{ID: "beat-20260312-001", CreatedAt: day(12), Impetus: beat.Impetus{Label: "Manual entry"}, Content: "Rollout of bd-7 slipped a week"},
+		{ID: "beat-20260313-001", CreatedAt: day(13), Impetus: beat.Impetus{Label: "Manual entry"}, Content: "bd-70 is unrelated"},
+		{ID: "beat-20260314-001", CreatedAt: day(14), Impetus: beat.Impetus{Label: "Web"}, Content: "Design review",
+			References: []beat.Reference{{Kind: "url", Locator: "https://tracker.example/issues/bd-7"}}},
+	} {
+		b.UpdatedAt = b.CreatedAt
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, req := range []hooks.SynthesisRequest{
+		{TriggeredAt: day(4), RecentBeats: []beat.Beat{*seedA, *seedB, *unrelated}},
+		{TriggeredAt: day(3), RecentBeats: []beat.Beat{*seedA}},
+		{TriggeredAt: day(5), RecentBeats: []beat.Beat{*unrelated}}, // Clustered no seed
+	} {
+		if _, err := hooks.ArchiveSynthesis(s.Dir(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := hooks.RecordSynthesisResponse(s.Dir(), "synthesis-20260304T090000Z", "Shard by tenant"); err != nil {
+		t.Fatal(err)
+	}
+	c := NewHumanCLI(s)
+
+	var buf bytes.Buffer
+	SetJSONOutput(&buf)
+	err := c.Lineage("bd-7", true)
+	SetJSONOutput(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l BeadLineage
+	if err := json.Unmarshal(buf.Bytes(), &l); err != nil {
+		t.Fatalf("invalid output %s: %v", buf.String(), err)
+	}
+	if l.Bead.Title != "Queue sharding" {
+		t.Errorf("bead = %+v", l.Bead)
+	}
+	ids := func(list []LineageBeat) (out []string) {
+		for _, b := range list {
+			out = append(out, b.ID+" "+b.Relation)
+		}
+		return out
+	}
+	if got := strings.Join(ids(l.Seeds), ", "); got != "beat-20260301-001 seed, beat-20260302-001 seed" {
+		t.Errorf("seeds = %s", got)
+	}
+	if got := strings.Join(ids(l.Later), ", "); got != "beat-20260310-001 evidence, beat-20260312-001 mention, beat-20260314-001 mention" {
+		t.Errorf("later = %s", got)
+	}
+	if ev := l.Later[0]; ev.LinkedBy != "ada" || !ev.LinkedAt.Equal(day(11)) {
+		t.Errorf("evidence link = %+v", ev)
+	}
+	if len(l.Syntheses) != 2 {
+		t.Fatalf("syntheses = %+v", l.Syntheses)
+	}
+	if syn := l.Syntheses[0]; syn.ID != "synthesis-20260303T090000Z" || strings.Join(syn.BeatIDs, ",") != "beat-20260301-001" || syn.Responded {
+		t.Errorf("first synthesis = %+v", syn)
+	}
+	if syn := l.Syntheses[1]; syn.ID != "synthesis-20260304T090000Z" || strings.Join(syn.BeatIDs, ",") != "beat-20260301-001,beat-20260302-001" || !syn.Responded {
+		t.Errorf("second synthesis = %+v", syn)
+	}
+	if p := l.Promotion; p == nil || p.At != "2026-03-05T10:00:00Z" || strings.Join(p.BeatIDs, ",") != "beat-20260302-001,beat-20260301-001" {
+		t.Errorf("promotion = %+v", p)
+	}
+
+	want := `bd-7  Queue sharding (open)
+├── Seed beats (2)
+│   ├── beat-20260301-001  2026-03-01  Manual entry: One queue cannot keep up
+│   └── beat-20260302-001  2026-03-02  Manual entry: Shard the queue by tenant
+├── Syntheses (2)
+│   ├── synthesis-20260303T090000Z  2026-03-03  clustered beat-20260301-001
+│   └── synthesis-20260304T090000Z  2026-03-04  clustered beat-20260301-001, beat-20260302-001 [responded]
+├── Promoted 2026-03-05 from beat-20260302-001, beat-20260301-001
+└── Later beats (3)
+    ├── beat-20260310-001  2026-03-10  Research [evidence]: Benchmarks of sharded queues
+    ├── beat-20260312-001  2026-03-12  Manual entry [mention]: Rollout of bd-7 slipped a week
+    └── beat-20260314-001  2026-03-14  Web [mention]: Design review
+`
+	if got := captureStdout(t, func() { printLineage(l) }); got != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// captureStdout returns what fn prints.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	os.Stdout = stdout
+	w.Close()
+	return <-done
+}
@@ -4,409 +4,575 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
 	"github.com/bierlingm/beats/internal/store"
 )
 
+// MigrateConfigFile configures migrate for a user. It lives in the beats
+// directory under os.UserConfigDir (e.g. ~/.config/beats/migrate.json).
+const MigrateConfigFile = "migrate.json"
+
+// MigrateConfig lists where scattered stores live and where they are
+// consolidated.
+type MigrateConfig struct {
+	Roots       []string `json:"roots,omitempty"`        // Directories scanned for .beats stores
+	GlobalStore string   `json:"global_store,omitempty"` // Store the beats are merged into
+}
+
+// MigrateConfigPath returns the path of the user's migrate config, or "" when
+// there is no user config directory.
+func MigrateConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "beats", MigrateConfigFile)
+}
+
+// LoadMigrateConfig reads the user's migrate config. A missing file is an
+// empty config.
+func LoadMigrateConfig() (MigrateConfig, error) {
+	var cfg MigrateConfig
+	path := MigrateConfigPath()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
 // MigrateOptions contains options for the migrate command.
 type MigrateOptions struct {
 	DryRun  bool
 	Cleanup bool
 	Force   bool
+	// Roots are scanned for .beats stores. Default: roots in the migrate
+	// config, then root in the config (BEATS_ROOT, a path list), then
+	// GetDefaultRoot.
+	Roots []string
+	// GlobalStore receives the beats. Default: global_store in the migrate
+	// config, then the current store.
+	GlobalStore string
+	JSON        bool // Print a MigrateResult; progress goes to stderr
 }
 
-// MigrateConsolidate merges all scattered .beats/ directories into the global store.
-func (c *HumanCLI) MigrateConsolidate(opts MigrateOptions) error {
-	werkRoot := "/Users/moritzbierling/werk"
-	globalStore := store.GlobalBeatsStore
+// migrateTargets resolves the roots to scan and the global store.
+func (c *HumanCLI) migrateTargets(opts MigrateOptions) ([]string, string, error) {
+	cfg, err := LoadMigrateConfig()
+	if err != nil {
+		return nil, "", err
+	}
 
-	// Find all .beats directories
-	var scatteredStores []string
-	err := filepath.WalkDir(werkRoot, func(path string, d os.DirEntry, err error) error {
+	roots := opts.Roots
+	if len(roots) == 0 {
+		roots = cfg.Roots
+	}
+	if len(roots) == 0 {
+		if root := config.Get().Root; root != "" {
+			roots = filepath.SplitList(root)
+		} else {
+			roots = []string{GetDefaultRoot()}
+		}
+	}
+	var resolved []string
+	for _, root := range roots {
+		if root = strings.TrimSpace(root); root == "" {
+			continue
+		}
+		abs, err := filepath.Abs(config.ExpandHome(root))
 		if err != nil {
-			return nil
+			return nil, "", err
 		}
-		if !d.IsDir() {
-			return nil
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			return nil, "", fmt.Errorf("root is not a directory: %s", root)
 		}
+		resolved = append(resolved, abs)
+	}
+	if len(resolved) == 0 {
+		return nil, "", fmt.Errorf("no roots to scan")
+	}
 
-		name := d.Name()
+	global := opts.GlobalStore
+	if global == "" {
+		global = cfg.GlobalStore
+	}
+	if global == "" {
+		global = c.store.Dir()
+	}
+	global, err = filepath.Abs(config.ExpandHome(global))
+	if err != nil {
+		return nil, "", err
+	}
+	return resolved, global, nil
+}
 
-		// Skip hidden dirs except .beats
-		if strings.HasPrefix(name, ".") && name != ".beats" {
-			return filepath.SkipDir
-		}
+// scatteredStore is a .beats directory found under a root.
+type scatteredStore struct {
+	Path string // The .beats directory
+	Rel  string // Its project directory relative to the root
+	Name string // Unique label: Rel, prefixed with the root's name when several roots are scanned
+}
 
-		// Skip common non-project dirs
-		skipDirs := map[string]bool{
-			"node_modules": true,
-			"vendor":       true,
-			"__pycache__":  true,
-			".git":         true,
-		}
-		if skipDirs[name] {
-			return filepath.SkipDir
+// findScatteredStores walks the roots for .beats directories, other than
+// the global store, whose beats file passes accept. Stores under
+// overlapping roots are listed once.
+func findScatteredStores(roots []string, global string, accept func(dir string) bool) ([]scatteredStore, error) {
+	skipDirs := map[string]bool{
+		"node_modules": true,
+		"vendor":       true,
+		"__pycache__":  true,
+		".git":         true,
+	}
+	seen := make(map[string]bool)
+	var stores []scatteredStore
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if name == store.DefaultBeatsDir {
+				if path != global && !seen[path] && accept(path) {
+					seen[path] = true
+					rel, err := filepath.Rel(root, filepath.Dir(path))
+					if err != nil {
+						rel = filepath.Dir(path)
+					}
+					label := rel
+					if len(roots) > 1 {
+						label = filepath.Join(filepath.Base(root), rel)
+					}
+					stores = append(stores, scatteredStore{Path: path, Rel: rel, Name: label})
+				}
+				return filepath.SkipDir
+			}
+			// Skip hidden and common non-project dirs
+			if path != root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
+	return stores, nil
+}
 
-		// Check for .beats with beats.jsonl
-		if name == ".beats" {
-			beatsFile := filepath.Join(path, "beats.jsonl")
-			if _, err := os.Stat(beatsFile); err == nil {
-				// Skip the global store itself
-				if path != globalStore {
-					scatteredStores = append(scatteredStores, path)
-				}
+// MigrateStore is one scattered store in a migrate run.
+type MigrateStore struct {
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Beats      int    `json:"beats"`
+	Migrated   int    `json:"migrated"`             // Consolidate: beats copied; cleanup: beats found in the global store
+	Duplicates int    `json:"duplicates,omitempty"` // Beats already in the global store
+	Status     string `json:"status"`               // migrated, up_to_date, verified, incomplete, archived, failed
+	Error      string `json:"error,omitempty"`
+	ArchivedTo string `json:"archived_to,omitempty"`
+}
+
+// MigrateResult summarizes a migrate run.
+type MigrateResult struct {
+	Action      string         `json:"action"` // consolidate or cleanup
+	DryRun      bool           `json:"dry_run"`
+	Roots       []string       `json:"roots"`
+	GlobalStore string         `json:"global_store"`
+	GlobalBeats int            `json:"global_beats"`
+	Stores      []MigrateStore `json:"stores"`
+	Migrated    int            `json:"migrated"`
+	Duplicates  int            `json:"duplicates"`
+	Archived    int            `json:"archived"`
+	Failed      int            `json:"failed"`
+}
+
+// migrateOutput is where progress goes: stdout, or stderr when the result
+// is printed as JSON.
+func migrateOutput(opts MigrateOptions) io.Writer {
+	if opts.JSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// readBeatsFile reads the beats in a JSONL file, skipping blank lines and
+// reporting lines that do not parse to warn.
+func readBeatsFile(path string, warn func(error)) ([]beat.Beat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var beats []beat.Beat
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var b beat.Beat
+		if err := json.Unmarshal([]byte(line), &b); err != nil {
+			if warn != nil {
+				warn(err)
 			}
-			return filepath.SkipDir
+			continue
 		}
+		beats = append(beats, b)
+	}
+	return beats, scanner.Err()
+}
 
-		return nil
-	})
+// MigrateConsolidate merges all scattered .beats/ directories into the global store.
+func (c *HumanCLI) MigrateConsolidate(opts MigrateOptions) error {
+	res, err := c.migrateConsolidate(opts, migrateOutput(opts))
 	if err != nil {
-		return fmt.Errorf("failed to scan for .beats directories: %w", err)
+		return err
 	}
+	if opts.JSON {
+		return outputJSON(res)
+	}
+	return nil
+}
 
-	if len(scatteredStores) == 0 {
-		fmt.Println("No scattered .beats directories found to migrate.")
-		return nil
+func (c *HumanCLI) migrateConsolidate(opts MigrateOptions, out io.Writer) (*MigrateResult, error) {
+	roots, globalStore, err := c.migrateTargets(opts)
+	if err != nil {
+		return nil, err
 	}
+	res := &MigrateResult{Action: "consolidate", DryRun: opts.DryRun, Roots: roots, GlobalStore: globalStore, Stores: []MigrateStore{}}
 
-	fmt.Printf("Found %d scattered .beats directories:\n", len(scatteredStores))
-	for _, s := range scatteredStores {
-		fmt.Printf("  - %s\n", s)
+	// Find all .beats directories with beats.jsonl
+	fmt.Fprintf(out, "Scanning %s...\n", strings.Join(roots, ", "))
+	scatteredStores, err := findScatteredStores(roots, globalStore, func(dir string) bool {
+		_, err := os.Stat(filepath.Join(dir, store.DefaultBeatsFile))
+		return err == nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for .beats directories: %w", err)
+	}
+	if len(scatteredStores) == 0 {
+		fmt.Fprintln(out, "No scattered .beats directories found to migrate.")
+		return res, nil
 	}
-	fmt.Println()
+	fmt.Fprintf(out, "Found %d scattered .beats directories; consolidating into %s\n\n", len(scatteredStores), globalStore)
 
 	// Load existing beats from global store (for deduplication)
 	existingBeats := make(map[string]beat.Beat)
-	globalBeatsFile := filepath.Join(globalStore, "beats.jsonl")
-	if f, err := os.Open(globalBeatsFile); err == nil {
-		scanner := bufio.NewScanner(f)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-			var b beat.Beat
-			if err := json.Unmarshal([]byte(line), &b); err == nil {
-				existingBeats[b.ID] = b
-			}
-		}
-		f.Close()
+	globalBeatsFile := filepath.Join(globalStore, store.DefaultBeatsFile)
+	existing, err := readBeatsFile(globalBeatsFile, nil)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read global store: %w", err)
 	}
+	for _, b := range existing {
+		existingBeats[b.ID] = b
+	}
+	res.GlobalBeats = len(existingBeats)
 
-	var totalMigrated, totalDuplicates int
-
-	// Process each scattered store
-	for _, storePath := range scatteredStores {
-		beatsFile := filepath.Join(storePath, "beats.jsonl")
+	for i, scattered := range scatteredStores {
+		entry := MigrateStore{Path: scattered.Path, Name: scattered.Name}
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(scatteredStores), scattered.Name)
+		beatsFile := filepath.Join(scattered.Path, store.DefaultBeatsFile)
 
-		// Derive wald_directory from path
-		relPath, err := filepath.Rel(werkRoot, filepath.Dir(storePath))
+		stored, err := readBeatsFile(beatsFile, func(err error) {
+			fmt.Fprintf(out, "  Warning: could not parse beat in %s: %v\n", beatsFile, err)
+		})
 		if err != nil {
-			relPath = filepath.Dir(storePath)
-		}
-
-		// Read beats from this store
-		f, err := os.Open(beatsFile)
-		if err != nil {
-			fmt.Printf("  Warning: could not read %s: %v\n", beatsFile, err)
+			entry.Status, entry.Error = "failed", err.Error()
+			res.Stores = append(res.Stores, entry)
+			res.Failed++
+			fmt.Fprintf(out, "%s: could not read: %v\n", progress, err)
 			continue
 		}
+		entry.Beats = len(stored)
 
 		var beatsToMigrate []beat.Beat
-		scanner := bufio.NewScanner(f)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-			var b beat.Beat
-			if err := json.Unmarshal([]byte(line), &b); err != nil {
-				fmt.Printf("  Warning: could not parse beat in %s: %v\n", beatsFile, err)
-				continue
-			}
-
+		for _, b := range stored {
 			// Check for duplicate
 			if existing, ok := existingBeats[b.ID]; ok {
 				// Keep the more recent one
 				if b.UpdatedAt.After(existing.UpdatedAt) {
 					beatsToMigrate = append(beatsToMigrate, b)
 				}
-				totalDuplicates++
+				entry.Duplicates++
 				continue
 			}
-
 			beatsToMigrate = append(beatsToMigrate, b)
 		}
-		f.Close()
+		res.Duplicates += entry.Duplicates
 
 		if len(beatsToMigrate) == 0 {
-			fmt.Printf("  %s: 0 beats to migrate\n", relPath)
+			entry.Status = "up_to_date"
+			res.Stores = append(res.Stores, entry)
+			fmt.Fprintf(out, "%s: 0 of %d beats to migrate\n", progress, entry.Beats)
 			continue
 		}
-
-		fmt.Printf("  %s: %d beats to migrate\n", relPath, len(beatsToMigrate))
+		entry.Migrated = len(beatsToMigrate)
+		entry.Status = "migrated"
+		fmt.Fprintf(out, "%s: %d of %d beats to migrate\n", progress, entry.Migrated, entry.Beats)
 
 		if opts.DryRun {
-			totalMigrated += len(beatsToMigrate)
+			res.Migrated += entry.Migrated
+			res.Stores = append(res.Stores, entry)
 			continue
 		}
 
-		// Add _legacy_context to each beat and append to global store
-		globalFile, err := os.OpenFile(globalBeatsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			return fmt.Errorf("failed to open global store: %w", err)
+		if err := appendLegacyBeats(globalStore, beatsToMigrate, scattered); err != nil {
+			return nil, err
 		}
-
 		for _, b := range beatsToMigrate {
-			// Add legacy context
-			legacyContext := map[string]interface{}{
-				"wald_directory": relPath,
-				"migrated_from":  storePath,
-				"migrated_at":    time.Now().UTC().Format(time.RFC3339),
-			}
-
-			// Marshal beat to map to add _legacy_context
-			beatData, _ := json.Marshal(b)
-			var beatMap map[string]interface{}
-			_ = json.Unmarshal(beatData, &beatMap)
-			beatMap["_legacy_context"] = legacyContext
-
-			// Write to global store
-			data, _ := json.Marshal(beatMap)
-			_, _ = globalFile.Write(append(data, '\n'))
-
 			existingBeats[b.ID] = b
-			totalMigrated++
 		}
-		globalFile.Close()
+		res.Migrated += entry.Migrated
+		res.Stores = append(res.Stores, entry)
 
-		// Create backup of original
-		backupPath := beatsFile + ".bak"
-		if err := os.Rename(beatsFile, backupPath); err != nil {
-			fmt.Printf("  Warning: could not backup %s: %v\n", beatsFile, err)
+		// Keep the original as a backup
+		if err := os.Rename(beatsFile, beatsFile+".bak"); err != nil {
+			fmt.Fprintf(out, "  Warning: could not back up %s: %v\n", beatsFile, err)
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 	if opts.DryRun {
-		fmt.Printf("[dry-run] Would migrate %d beats from %d stores, %d duplicates resolved\n",
-			totalMigrated, len(scatteredStores), totalDuplicates)
+		fmt.Fprintf(out, "[dry-run] Would migrate %d beats from %d stores, %d duplicates resolved\n",
+			res.Migrated, len(scatteredStores), res.Duplicates)
 	} else {
-		fmt.Printf("Migrated %d beats from %d stores, %d duplicates resolved\n",
-			totalMigrated, len(scatteredStores), totalDuplicates)
-		fmt.Println("Original .beats/beats.jsonl files renamed to .bak")
+		fmt.Fprintf(out, "Migrated %d beats from %d stores, %d duplicates resolved\n",
+			res.Migrated, len(scatteredStores), res.Duplicates)
+		fmt.Fprintln(out, "Original .beats/beats.jsonl files renamed to .bak")
 	}
+	return res, nil
+}
 
+// appendLegacyBeats appends beats to the global store, each with a
+// _legacy_context recording the project directory and store it came from.
+func appendLegacyBeats(globalStore string, beats []beat.Beat, from scatteredStore) error {
+	global, err := store.NewJSONLStore(globalStore)
+	if err != nil {
+		return fmt.Errorf("failed to create global store: %w", err)
+	}
+
+	migratedAt := clock.Now().UTC().Format(time.RFC3339)
+	lines := make([][]byte, 0, len(beats))
+	for _, b := range beats {
+		// Marshal beat to map to add _legacy_context
+		beatData, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		var beatMap map[string]interface{}
+		if err := json.Unmarshal(beatData, &beatMap); err != nil {
+			return err
+		}
+		beatMap["_legacy_context"] = map[string]interface{}{
+			"wald_directory": from.Rel,
+			"migrated_from":  from.Path,
+			"migrated_at":    migratedAt,
+		}
+		data, err := json.Marshal(beatMap)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, data)
+	}
+	if err := global.AppendRaw(lines); err != nil {
+		return fmt.Errorf("failed to write global store: %w", err)
+	}
 	return nil
 }
 
 // MigrateCleanup removes old .beats/ directories after verifying migration
 func (c *HumanCLI) MigrateCleanup(opts MigrateOptions) error {
-	werkRoot := "/Users/moritzbierling/werk"
-	globalStore := store.GlobalBeatsStore
-	globalBeatsFile := filepath.Join(globalStore, "beats.jsonl")
-
-	// Verify global store exists and has beats
-	if _, err := os.Stat(globalBeatsFile); err != nil {
-		return fmt.Errorf("global store not found at %s - run 'bt migrate --consolidate' first", globalStore)
+	res, err := c.migrateCleanup(opts, migrateOutput(opts))
+	if opts.JSON && res != nil {
+		if jerr := outputJSON(res); jerr != nil {
+			return jerr
+		}
 	}
+	return err
+}
 
-	// Load global store beats
-	globalBeats := make(map[string]bool)
-	f, err := os.Open(globalBeatsFile)
+func (c *HumanCLI) migrateCleanup(opts MigrateOptions, out io.Writer) (*MigrateResult, error) {
+	roots, globalStore, err := c.migrateTargets(opts)
 	if err != nil {
-		return fmt.Errorf("failed to open global store: %w", err)
+		return nil, err
 	}
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		var b beat.Beat
-		if err := json.Unmarshal([]byte(line), &b); err == nil {
-			globalBeats[b.ID] = true
+	res := &MigrateResult{Action: "cleanup", DryRun: opts.DryRun, Roots: roots, GlobalStore: globalStore, Stores: []MigrateStore{}}
+	globalBeatsFile := filepath.Join(globalStore, store.DefaultBeatsFile)
+
+	// Verify global store exists and has beats
+	global, err := readBeatsFile(globalBeatsFile, nil)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("global store not found at %s - run 'bt migrate consolidate' first", globalStore)
 		}
+		return nil, fmt.Errorf("failed to read global store: %w", err)
+	}
+	globalBeats := make(map[string]bool, len(global))
+	for _, b := range global {
+		globalBeats[b.ID] = true
 	}
-	f.Close()
-
 	if len(globalBeats) == 0 {
-		return fmt.Errorf("global store is empty - run 'bt migrate --consolidate' first")
+		return nil, fmt.Errorf("global store is empty - run 'bt migrate consolidate' first")
 	}
+	res.GlobalBeats = len(globalBeats)
 
 	// Find all old .beats directories (excluding global store)
-	var oldStores []string
-	var oldStoreBeats = make(map[string][]string) // path -> beat IDs
-
-	err = filepath.WalkDir(werkRoot, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		if !d.IsDir() {
-			return nil
-		}
-
-		name := d.Name()
-
-		// Skip hidden dirs except .beats
-		if strings.HasPrefix(name, ".") && name != ".beats" {
-			return filepath.SkipDir
-		}
-
-		// Skip common non-project dirs
-		skipDirs := map[string]bool{
-			"node_modules": true,
-			"vendor":       true,
-			"__pycache__":  true,
-			".git":         true,
-		}
-		if skipDirs[name] {
-			return filepath.SkipDir
-		}
-
-		// Check for .beats with beats.jsonl or .bak
-		if name == ".beats" {
-			// Skip the global store itself
-			if path == globalStore {
-				return filepath.SkipDir
-			}
-
-			// Check for beats.jsonl or beats.jsonl.bak
-			beatsFile := filepath.Join(path, "beats.jsonl")
-			bakFile := filepath.Join(path, "beats.jsonl.bak")
-
-			hasBeats := false
-			var beatsToCheck string
-			if _, err := os.Stat(beatsFile); err == nil {
-				hasBeats = true
-				beatsToCheck = beatsFile
-			} else if _, err := os.Stat(bakFile); err == nil {
-				hasBeats = true
-				beatsToCheck = bakFile
+	beatsFileOf := func(dir string) string {
+		for _, name := range []string{store.DefaultBeatsFile, store.DefaultBeatsFile + ".bak"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return filepath.Join(dir, name)
 			}
-
-			if hasBeats {
-				oldStores = append(oldStores, path)
-
-				// Read beat IDs from this store
-				bf, err := os.Open(beatsToCheck)
-				if err == nil {
-					scanner := bufio.NewScanner(bf)
-					for scanner.Scan() {
-						line := scanner.Text()
-						if strings.TrimSpace(line) == "" {
-							continue
-						}
-						var b beat.Beat
-						if err := json.Unmarshal([]byte(line), &b); err == nil {
-							oldStoreBeats[path] = append(oldStoreBeats[path], b.ID)
-						}
-					}
-					bf.Close()
-				}
-			}
-			return filepath.SkipDir
 		}
-
-		return nil
+		return ""
+	}
+	fmt.Fprintf(out, "Scanning %s...\n", strings.Join(roots, ", "))
+	oldStores, err := findScatteredStores(roots, globalStore, func(dir string) bool {
+		return beatsFileOf(dir) != ""
 	})
 	if err != nil {
-		return fmt.Errorf("failed to scan for .beats directories: %w", err)
+		return nil, fmt.Errorf("failed to scan for .beats directories: %w", err)
 	}
-
 	if len(oldStores) == 0 {
-		fmt.Println("No old .beats directories found to clean up.")
-		return nil
+		fmt.Fprintln(out, "No old .beats directories found to clean up.")
+		return res, nil
 	}
 
-	fmt.Println("Migration cleanup verification:")
-	fmt.Println()
-	fmt.Printf("Global store: %s (%d beats)\n", globalBeatsFile, len(globalBeats))
-	fmt.Println()
-	fmt.Println("Old stores to remove:")
+	fmt.Fprintln(out, "Migration cleanup verification:")
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Global store: %s (%d beats)\n", globalBeatsFile, len(globalBeats))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Old stores to remove:")
 
 	allMigrated := true
-	for _, storePath := range oldStores {
-		beatIDs := oldStoreBeats[storePath]
-		migratedCount := 0
-		for _, id := range beatIDs {
-			if globalBeats[id] {
-				migratedCount++
+	for _, old := range oldStores {
+		entry := MigrateStore{Path: old.Path, Name: old.Name, Status: "verified"}
+		beats, _ := readBeatsFile(beatsFileOf(old.Path), nil)
+		entry.Beats = len(beats)
+		for _, b := range beats {
+			if globalBeats[b.ID] {
+				entry.Migrated++
 			}
 		}
 
 		status := "✓ all migrated"
-		if migratedCount < len(beatIDs) {
-			status = fmt.Sprintf("✗ %d of %d migrated", migratedCount, len(beatIDs))
+		if entry.Migrated < entry.Beats {
+			status = fmt.Sprintf("✗ %d of %d migrated", entry.Migrated, entry.Beats)
+			entry.Status = "incomplete"
 			allMigrated = false
 		}
-
-		relPath, _ := filepath.Rel(werkRoot, storePath)
-		fmt.Printf("  %s (%d beats) %s\n", relPath, len(beatIDs), status)
+		res.Stores = append(res.Stores, entry)
+		fmt.Fprintf(out, "  %s (%d beats) %s\n", old.Name, entry.Beats, status)
 	}
 
 	if !allMigrated {
-		fmt.Println()
-		fmt.Println("Warning: Some beats were not migrated. Run 'bt migrate --consolidate' first.")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Warning: Some beats were not migrated. Run 'bt migrate consolidate' first.")
 		if !opts.Force {
-			return fmt.Errorf("cleanup aborted - not all beats migrated")
+			return res, fmt.Errorf("cleanup aborted - not all beats migrated")
 		}
-		fmt.Println("Proceeding with --force...")
+		fmt.Fprintln(out, "Proceeding with --force...")
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 
-	if !opts.Force {
-		fmt.Println("Run with --force to delete old stores.")
-		fmt.Printf("Backups were created at %s/migration-backup/ during consolidation\n", globalStore)
-		return nil
+	if !opts.Force && !opts.DryRun {
+		fmt.Fprintln(out, "Run with --force to move the old stores to the global store's archived-stores/.")
+		fmt.Fprintln(out, "Consolidation left each original beats file as beats.jsonl.bak.")
+		return res, nil
 	}
 
 	// Create archive directory
 	archiveDir := filepath.Join(globalStore, "archived-stores")
-	if err := os.MkdirAll(archiveDir, 0755); err != nil {
-		return fmt.Errorf("failed to create archive directory: %w", err)
+	if !opts.DryRun {
+		if err := os.MkdirAll(archiveDir, 0755); err != nil {
+			return res, fmt.Errorf("failed to create archive directory: %w", err)
+		}
 	}
 
 	// Move old stores to archive
-	var removed, failed int
-	for _, storePath := range oldStores {
-		relPath, _ := filepath.Rel(werkRoot, storePath)
-		archivePath := filepath.Join(archiveDir, strings.ReplaceAll(relPath, "/", "_"))
+	for i, old := range oldStores {
+		entry := &res.Stores[i]
+		entry.ArchivedTo = filepath.Join(archiveDir, strings.ReplaceAll(old.Name, string(filepath.Separator), "_"))
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(oldStores), old.Name)
 
 		if opts.DryRun {
-			fmt.Printf("[dry-run] Would move %s to %s\n", relPath, archivePath)
-			removed++
+			fmt.Fprintf(out, "%s: [dry-run] would move to %s\n", progress, entry.ArchivedTo)
+			res.Archived++
 			continue
 		}
 
-		// Move to archive
-		if err := os.Rename(storePath, archivePath); err != nil {
-			fmt.Printf("Failed to archive %s: %v\n", relPath, err)
-			failed++
+		if err := os.Rename(old.Path, entry.ArchivedTo); err != nil {
+			fmt.Fprintf(out, "%s: failed to archive: %v\n", progress, err)
+			entry.Status, entry.Error, entry.ArchivedTo = "failed", err.Error(), ""
+			res.Failed++
 			continue
 		}
 
-		fmt.Printf("Archived %s\n", relPath)
-		removed++
+		fmt.Fprintf(out, "%s: archived\n", progress)
+		entry.Status = "archived"
+		res.Archived++
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 	if opts.DryRun {
-		fmt.Printf("[dry-run] Would archive %d old .beats directories\n", removed)
+		fmt.Fprintf(out, "[dry-run] Would archive %d old .beats directories\n", res.Archived)
 	} else {
-		fmt.Printf("Archived %d old .beats directories to %s\n", removed, archiveDir)
-		if failed > 0 {
-			fmt.Printf("%d directories could not be archived\n", failed)
+		fmt.Fprintf(out, "Archived %d old .beats directories to %s\n", res.Archived, archiveDir)
+		if res.Failed > 0 {
+			fmt.Fprintf(out, "%d directories could not be archived\n", res.Failed)
 		}
 	}
+	return res, nil
+}
 
+// BackendResult is the outcome of bt migrate backend.
+type BackendResult struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Beats int    `json:"beats"`
+	Path  string `json:"path"`
+	Old   string `json:"old,omitempty"` // Where the previous data was set aside
+}
+
+// MigrateBackend converts the store to another storage backend, or with an
+// empty target reports the one it uses.
+func (c *HumanCLI) MigrateBackend(to string, jsonOut bool) error {
+	from := c.store.Backend()
+	r := &BackendResult{From: from, To: from, Path: c.store.Path()}
+	if to != "" {
+		old := c.store.Path()
+		n, err := c.store.ConvertBackend(to)
+		if err != nil {
+			return err
+		}
+		r.To, r.Beats, r.Path, r.Old = to, n, c.store.Path(), old+".bak"
+	}
+	if jsonOut {
+		return outputJSON(r)
+	}
+	if to == "" {
+		fmt.Printf("Backend: %s (%s)\n", r.From, r.Path)
+		return nil
+	}
+	fmt.Printf("Converted %d beat(s) from %s to %s: %s\n", r.Beats, r.From, r.To, r.Path)
+	fmt.Printf("The previous data is kept as %s; remove it once you are happy with the result.\n", r.Old)
 	return nil
 }
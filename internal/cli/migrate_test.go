@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// writeStore writes beats to dir/.beats/beats.jsonl and returns the .beats
+// directory.
+func writeStore(t *testing.T, dir string, beats ...beat.Beat) string {
+	t.Helper()
+	beatsDir := filepath.Join(dir, store.DefaultBeatsDir)
+	if err := os.MkdirAll(beatsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	for _, b := range beats {
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.Write(append(data, '\n'))
+	}
+	if err := os.WriteFile(filepath.Join(beatsDir, store.DefaultBeatsFile), []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return beatsDir
+}
+
+func testBeat(id, content string, updated time.Time) beat.Beat {
+	return beat.Beat{ID: id, Content: content, CreatedAt: updated, UpdatedAt: updated}
+}
+
+// migrateFixture lays out two roots with scattered stores and a global
+// store holding one of their beats, and returns a CLI on the global store.
+func migrateFixture(t *testing.T) (*HumanCLI, MigrateOptions) {
+	t.Helper()
+	// Keep the user's migrate.json out of the test
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	base := t.TempDir()
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := old.Add(time.Hour)
+
+	work := filepath.Join(base, "work")
+	src := filepath.Join(base, "src")
+	writeStore(t, filepath.Join(work, "alpha"),
+		testBeat("beat-20250101-001", "alpha one", old),
+		testBeat("beat-20250101-002", "shared, edited later", newer))
+	writeStore(t, filepath.Join(src, "beta"), testBeat("beat-20250102-001", "beta one", old))
+	writeStore(t, filepath.Join(src, "beta", "node_modules", "dep"), testBeat("beat-20250103-001", "ignored", old))
+
+	global := writeStore(t, filepath.Join(base, "home"), testBeat("beat-20250101-002", "shared", old))
+	s, err := store.NewJSONLStore(global)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHumanCLI(s), MigrateOptions{Roots: []string{work, src}}
+}
+
+func TestMigrateConsolidate(t *testing.T) {
+	c, opts := migrateFixture(t)
+
+	res, err := c.migrateConsolidate(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Stores) != 2 {
+		t.Fatalf("stores = %+v, want alpha and beta (node_modules skipped)", res.Stores)
+	}
+	// The newer copy of the shared beat is appended; ReadAll keeps the last
+	if res.Migrated != 3 || res.Duplicates != 1 || res.GlobalBeats != 1 {
+		t.Errorf("result = %+v, want 3 migrated, 1 duplicate, 1 global beat", res)
+	}
+	if res.Stores[0].Name != filepath.Join("work", "alpha") || res.Stores[0].Status != "migrated" {
+		t.Errorf("first store = %+v", res.Stores[0])
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, b := range all {
+		contents[b.ID] = b.Content
+	}
+	if len(contents) != 3 || contents["beat-20250101-002"] != "shared, edited later" {
+		t.Errorf("global store = %v", contents)
+	}
+	data, err := os.ReadFile(filepath.Join(c.store.Dir(), store.DefaultBeatsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"wald_directory":"beta"`) {
+		t.Errorf("migrated beats lack _legacy_context:\n%s", data)
+	}
+
+	for _, st := range res.Stores {
+		if _, err := os.Stat(filepath.Join(st.Path, store.DefaultBeatsFile+".bak")); err != nil {
+			t.Errorf("%s not backed up: %v", st.Name, err)
+		}
+	}
+
+	// A second run finds only the backups, which consolidate ignores
+	again, err := c.migrateConsolidate(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Stores) != 0 || again.Migrated != 0 {
+		t.Errorf("second run = %+v, want nothing to do", again)
+	}
+}
+
+func TestMigrateConsolidateDryRun(t *testing.T) {
+	c, opts := migrateFixture(t)
+	globalFile := filepath.Join(c.store.Dir(), store.DefaultBeatsFile)
+	before, err := os.ReadFile(globalFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.DryRun = true
+	res, err := c.migrateConsolidate(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.DryRun || res.Migrated != 3 {
+		t.Errorf("result = %+v, want a dry run of 3 beats", res)
+	}
+
+	after, err := os.ReadFile(globalFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("dry run wrote to the global store")
+	}
+	for _, st := range res.Stores {
+		if _, err := os.Stat(filepath.Join(st.Path, store.DefaultBeatsFile)); err != nil {
+			t.Errorf("dry run moved %s: %v", st.Name, err)
+		}
+	}
+}
+
+func TestMigrateCleanup(t *testing.T) {
+	c, opts := migrateFixture(t)
+
+	// Nothing consolidated yet: verification fails
+	res, err := c.migrateCleanup(opts, io.Discard)
+	if err == nil {
+		t.Fatal("cleanup before consolidate succeeded")
+	}
+	if len(res.Stores) != 2 || res.Stores[0].Status != "incomplete" {
+		t.Errorf("stores = %+v, want both incomplete", res.Stores)
+	}
+
+	if _, err := c.migrateConsolidate(opts, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verified, but nothing is moved without --force
+	res, err = c.migrateCleanup(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Archived != 0 || res.Stores[0].Status != "verified" {
+		t.Errorf("result = %+v, want verified and nothing archived", res)
+	}
+
+	opts.DryRun = true
+	res, err = c.migrateCleanup(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Archived != 2 {
+		t.Errorf("dry run archived = %d, want 2", res.Archived)
+	}
+	if _, err := os.Stat(res.Stores[0].Path); err != nil {
+		t.Errorf("dry run moved %s", res.Stores[0].Path)
+	}
+
+	opts.DryRun, opts.Force = false, true
+	res, err = c.migrateCleanup(opts, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Archived != 2 || res.Failed != 0 {
+		t.Fatalf("result = %+v, want 2 archived", res)
+	}
+	for _, st := range res.Stores {
+		if _, err := os.Stat(st.Path); !os.IsNotExist(err) {
+			t.Errorf("%s still in place", st.Path)
+		}
+		if _, err := os.Stat(filepath.Join(st.ArchivedTo, store.DefaultBeatsFile+".bak")); err != nil {
+			t.Errorf("%s not archived: %v", st.Name, err)
+		}
+	}
+	if want := filepath.Join(c.store.Dir(), "archived-stores", "src_beta"); res.Stores[1].ArchivedTo != want {
+		t.Errorf("archived to %s, want %s", res.Stores[1].ArchivedTo, want)
+	}
+}
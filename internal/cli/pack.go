@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/tokens"
+)
+
+const (
+	defaultPackBudget  = 8000
+	packEntities       = 15  // Entities the pack lists
+	packBeadSummary    = 300 // Characters of a bead description kept
+	packSummaryReserve = 400 // Tokens held back for an LLM-written brief
+)
+
+// PackOptions configures bt pack.
+type PackOptions struct {
+	Topic     string
+	Budget    int    // Token budget for the whole pack (default 8000)
+	Out       string // File to write (default: stdout)
+	Format    string // PrimeMarkdown, PrimeJSON or FormatHTML
+	Summarize bool   // Have the LLM write the brief
+	Provider  string // LLM provider the pack is for, choosing its redaction profile
+}
+
+// ContextPack is a topic's context bundled for pasting into a fresh LLM
+// session: a brief, the most relevant beats verbatim, the entities they
+// mention and the beads they link to.
+type ContextPack struct {
+	Topic       string       `json:"topic"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	TokenBudget int          `json:"token_budget"`
+	TokensUsed  int          `json:"tokens_used"` // Of the markdown rendering
+	Mode        string       `json:"mode"`
+	Brief       string       `json:"brief"`
+	Model       string       `json:"model,omitempty"` // Set when the LLM wrote the brief
+	Beats       []PackBeat   `json:"beats"`
+	Entities    []PackEntity `json:"entities"`
+	Beads       []PackBead   `json:"beads"`
+	Omitted     int          `json:"omitted,omitempty"` // Matches left out to fit the budget
+	Redaction   *redact.Info `json:"redaction,omitempty"`
+}
+
+// PackBeat is a beat as included in a pack.
+type PackBeat struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Score     float64   `json:"score"`
+	Content   string    `json:"content"`
+	Beads     []string  `json:"beads,omitempty"`
+}
+
+// PackEntity is an entity mentioned by the packed beats.
+type PackEntity struct {
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	Beats    int    `json:"beats"`
+}
+
+// PackBead summarizes a bead the packed beats link to. Title and status are
+// empty for beads missing from the beads cache.
+type PackBead struct {
+	ID      string `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// BuildPack assembles the context pack for a topic: the matching beats, most
+// relevant first, are added whole for as long as the rendered pack stays
+// within the budget.
+func BuildPack(ctx context.Context, s *store.JSONLStore, opts PackOptions) (*ContextPack, error) {
+	topic := strings.TrimSpace(opts.Topic)
+	if topic == "" {
+		return nil, fmt.Errorf("a topic is required (--topic)")
+	}
+	budget := opts.Budget
+	if budget <= 0 {
+		budget = defaultPackBudget
+	}
+	scoring, err := store.LoadScoringConfig(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	ranked, scores, mode, err := rankBeats(ctx, s, topic, scoring)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	cached, err := beads.LoadCache(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]beads.Bead, len(cached))
+	for _, b := range cached {
+		byID[b.ID] = b
+	}
+
+	p := &ContextPack{Topic: topic, GeneratedAt: clock.Now().UTC(), TokenBudget: budget, Mode: mode}
+	room := budget
+	if opts.Summarize {
+		room -= packSummaryReserve
+	}
+	var selected []beat.Beat
+	for _, b := range ranked {
+		p.fill(append(selected, b), scores, byID)
+		if tokens.Estimate(p.Markdown()) > room {
+			p.Omitted++
+			continue
+		}
+		selected = append(selected, b)
+	}
+	p.fill(selected, scores, byID)
+
+	if opts.Summarize && len(selected) > 0 {
+		brief, model, err := llmGenerate(ctx, s.Dir(), packPrompt(topic, selected))
+		if err != nil {
+			return nil, fmt.Errorf("the brief needs the LLM (llm_model %s): %w", model, err)
+		}
+		p.Brief, p.Model = strings.TrimSpace(brief), model
+	}
+
+	r, redaction, err := providerRedactor(s, opts.Provider, selected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load redaction profile: %w", err)
+	}
+	if r != nil {
+		p.Brief = r.Text(p.Brief)
+		for i := range p.Beats {
+			p.Beats[i].Content = r.Text(p.Beats[i].Content)
+		}
+		for i := range p.Entities {
+			p.Entities[i].Label = r.Text(p.Entities[i].Label)
+		}
+		for i := range p.Beads {
+			p.Beads[i].Title = r.Text(p.Beads[i].Title)
+			p.Beads[i].Summary = r.Text(p.Beads[i].Summary)
+		}
+		redaction.Replaced = r.Replaced
+		p.Redaction = redaction
+	}
+	p.TokensUsed = tokens.Estimate(p.Markdown())
+	return p, nil
+}
+
+// fill sets the pack's beats, entities, beads and default brief from the
+// selected beats.
+func (p *ContextPack) fill(selected []beat.Beat, scores map[string]float64, byID map[string]beads.Bead) {
+	p.Beats = []PackBeat{}
+	p.Entities = []PackEntity{}
+	p.Beads = []PackBead{}
+	entities := make(map[string]*PackEntity)
+	seenBead := make(map[string]bool)
+	for _, b := range selected {
+		p.Beats = append(p.Beats, PackBeat{
+			ID:        b.ID,
+			CreatedAt: b.CreatedAt,
+			Impetus:   b.Impetus.Label,
+			Score:     scores[b.ID],
+			Content:   strings.TrimSpace(b.Content),
+			Beads:     b.LinkedBeads.IDs(),
+		})
+		seenEntity := make(map[string]bool)
+		for _, e := range b.Entities {
+			key := e.Category + "\x00" + strings.ToLower(e.Label)
+			if seenEntity[key] {
+				continue
+			}
+			seenEntity[key] = true
+			if entities[key] == nil {
+				entities[key] = &PackEntity{Label: e.Label, Category: e.Category}
+			}
+			entities[key].Beats++
+		}
+		for _, id := range b.LinkedBeads.IDs() {
+			if seenBead[id] {
+				continue
+			}
+			seenBead[id] = true
+			pb := PackBead{ID: id}
+			if bd, ok := byID[id]; ok {
+				pb.Title, pb.Status = bd.Title, bd.Status
+				pb.Summary = truncate(bd.Description, packBeadSummary)
+			}
+			p.Beads = append(p.Beads, pb)
+		}
+	}
+	for _, e := range entities {
+		p.Entities = append(p.Entities, *e)
+	}
+	sort.Slice(p.Entities, func(i, j int) bool {
+		if p.Entities[i].Beats != p.Entities[j].Beats {
+			return p.Entities[i].Beats > p.Entities[j].Beats
+		}
+		return strings.ToLower(p.Entities[i].Label) < strings.ToLower(p.Entities[j].Label)
+	})
+	if len(p.Entities) > packEntities {
+		p.Entities = p.Entities[:packEntities]
+	}
+	p.Brief = p.overview(selected)
+}
+
+// overview is the brief written without the LLM: how much the store holds on
+// the topic, over what period, and what it centres on.
+func (p *ContextPack) overview(selected []beat.Beat) string {
+	if len(selected) == 0 {
+		return fmt.Sprintf("No beats match %q.", p.Topic)
+	}
+	first, last := selected[0].CreatedAt, selected[0].CreatedAt
+	for _, b := range selected {
+		if b.CreatedAt.Before(first) {
+			first = b.CreatedAt
+		}
+		if b.CreatedAt.After(last) {
+			last = b.CreatedAt
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d beat(s) on %q, captured %s to %s.", len(selected), p.Topic, displayDate(first), displayDate(last))
+	if len(p.Entities) > 0 {
+		var names []string
+		for i := 0; i < len(p.Entities) && i < 5; i++ {
+			names = append(names, p.Entities[i].Label)
+		}
+		fmt.Fprintf(&sb, " Most mentioned: %s.", strings.Join(names, ", "))
+	}
+	if len(p.Beads) > 0 {
+		open := 0
+		for _, b := range p.Beads {
+			if b.Status != "" && b.Status != "closed" {
+				open++
+			}
+		}
+		fmt.Fprintf(&sb, " Linked to %d bead(s), %d open.", len(p.Beads), open)
+	}
+	return sb.String()
+}
+
+// packPrompt asks the LLM for the pack's brief.
+func packPrompt(topic string, beats []beat.Beat) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "These are my notes on %q. Write a brief of 3 to 5 sentences for someone picking the topic up cold: what it is about, where it stands and what is unresolved. No preamble.\n\nNotes:\n", topic)
+	for _, b := range beats {
+		fmt.Fprintf(&sb, "- [%s] %s (%s): %s\n", b.ID, displayDate(b.CreatedAt), b.Impetus.Label, truncate(b.Content, 600))
+	}
+	return sb.String()
+}
+
+// Markdown renders the pack for pasting into a session.
+func (p *ContextPack) Markdown() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# Context: %s\n\n", p.Topic)
+	out.WriteString("> Notes from my beats store, most relevant first. Cite beats by ID.\n\n")
+	fmt.Fprintf(&out, "## Brief\n%s\n\n", p.Brief)
+
+	if len(p.Beats) > 0 {
+		out.WriteString("## Beats\n")
+		for _, b := range p.Beats {
+			fmt.Fprintf(&out, "\n### %s (%s, %s)\n", b.ID, displayDate(b.CreatedAt), b.Impetus)
+			if len(b.Beads) > 0 {
+				fmt.Fprintf(&out, "Beads: %s\n", strings.Join(b.Beads, ", "))
+			}
+			fmt.Fprintf(&out, "\n%s\n", b.Content)
+		}
+		out.WriteString("\n")
+	}
+
+	if len(p.Entities) > 0 {
+		out.WriteString("## Entities\n")
+		for _, e := range p.Entities {
+			fmt.Fprintf(&out, "- %s (%s, %d beat(s))\n", e.Label, e.Category, e.Beats)
+		}
+		out.WriteString("\n")
+	}
+
+	if len(p.Beads) > 0 {
+		out.WriteString("## Linked Beads\n")
+		for _, b := range p.Beads {
+			line := "- " + b.ID
+			if b.Title != "" {
+				line += fmt.Sprintf(" [%s] %s", b.Status, b.Title)
+			}
+			if b.Summary != "" {
+				line += ": " + b.Summary
+			}
+			out.WriteString(line + "\n")
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// htmlPage renders the pack as a standalone page, for reading rather than
+// pasting.
+func (p *ContextPack) htmlPage() HTMLPage {
+	_, body, _ := strings.Cut(p.Markdown(), "\n") // The title line becomes the page title
+	subtitle := fmt.Sprintf("%d beat(s), generated %s", len(p.Beats), displayTime(p.GeneratedAt))
+	if p.Model != "" {
+		subtitle += ", brief by " + p.Model
+	}
+	return HTMLPage{
+		Title:    "Context: " + p.Topic,
+		Subtitle: subtitle,
+		Sections: []HTMLSection{{Body: markdownHTML(body)}},
+	}
+}
+
+// Pack writes the context pack for a topic to opts.Out, or stdout.
+func (c *HumanCLI) Pack(opts PackOptions) error {
+	if opts.Format == "" {
+		opts.Format = PrimeMarkdown
+	}
+	if opts.Format != PrimeMarkdown && opts.Format != PrimeJSON && opts.Format != FormatHTML {
+		return fmt.Errorf("unknown format %q (use %s, %s or %s)", opts.Format, PrimeMarkdown, PrimeJSON, FormatHTML)
+	}
+	p, err := BuildPack(context.Background(), c.store, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Out == "" && opts.Format == PrimeJSON {
+		return outputJSON(p)
+	}
+
+	data := []byte(p.Markdown())
+	switch opts.Format {
+	case PrimeJSON:
+		if data, err = json.MarshalIndent(p, "", "  "); err != nil {
+			return err
+		}
+	case FormatHTML:
+		if data, err = p.htmlPage().Render(); err != nil {
+			return err
+		}
+	}
+	if opts.Out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(opts.Out); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(opts.Out, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pack: %w", err)
+	}
+	fmt.Printf("Wrote context pack for %q to %s: %d beat(s), ~%d of %d tokens", p.Topic, opts.Out, len(p.Beats), p.TokensUsed, p.TokenBudget)
+	if p.Omitted > 0 {
+		fmt.Printf(", %d more matches left out", p.Omitted)
+	}
+	fmt.Println()
+	return nil
+}
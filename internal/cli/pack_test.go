@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestBuildPack(t *testing.T) {
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	dir := t.TempDir()
+	s, err := store.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	long := strings.Repeat("Pricing detail that goes on and on. ", 200)
+	for _, b := range []beat.Beat{
+		{ID: "beat-20260301-001", Content: "Pricing strategy for the enterprise tier", CreatedAt: now,
+			Entities:    []beat.Entity{{Label: "Acme", Category: "organization"}},
+			LinkedBeads: beat.BeadLinks{{BeadID: "bd-1", Relation: "informs"}}},
+		{ID: "beat-20260301-002", Content: "Pricing call with Acme about seats", CreatedAt: now.Add(time.Hour),
+			Entities: []beat.Entity{{Label: "Acme", Category: "organization"}, {Label: "Ada", Category: "person"}}},
+		{ID: "beat-20260301-003", Content: long, CreatedAt: now},
+		{ID: "beat-20260301-004", Content: "Lunch with the team", CreatedAt: now},
+	} {
+		if err := s.Append(&b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, beads.CacheFile), []byte(`[{"id":"bd-1","title":"Launch enterprise tier","status":"open","description":"Seats and SSO"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := BuildPack(context.Background(), s, PackOptions{Topic: "pricing", Budget: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Beats) != 2 || p.Omitted != 1 {
+		t.Fatalf("beats = %+v, omitted %d; want the two short pricing beats and the long one left out", p.Beats, p.Omitted)
+	}
+	if p.Beats[0].Content != "Pricing strategy for the enterprise tier" && p.Beats[1].Content != "Pricing strategy for the enterprise tier" {
+		t.Errorf("beat content not kept verbatim: %+v", p.Beats)
+	}
+	if len(p.Entities) != 2 || p.Entities[0].Label != "Acme" || p.Entities[0].Beats != 2 {
+		t.Errorf("entities = %+v", p.Entities)
+	}
+	if len(p.Beads) != 1 || p.Beads[0].Title != "Launch enterprise tier" || p.Beads[0].Status != "open" {
+		t.Errorf("beads = %+v", p.Beads)
+	}
+	if p.TokensUsed > p.TokenBudget {
+		t.Errorf("pack uses %d tokens, over the %d budget", p.TokensUsed, p.TokenBudget)
+	}
+	md := p.Markdown()
+	for _, want := range []string{"# Context: pricing", "2 beat(s) on \"pricing\"", "### beat-20260301-001", "## Entities", "- bd-1 [open] Launch enterprise tier: Seats and SSO"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Lunch") {
+		t.Errorf("unrelated beat packed:\n%s", md)
+	}
+
+	if _, err := BuildPack(context.Background(), s, PackOptions{}); err == nil {
+		t.Error("BuildPack without a topic succeeded")
+	}
+}
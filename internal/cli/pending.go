@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// stageCommit holds a robot commit for approval when the approval hook is
+// on and the agent is not trusted, returning the pending entry or nil.
+func stageCommit(s *store.JSONLStore, in *CommitInput) (*pipeline.PendingBeat, error) {
+	if hooks.GetApprovalConfig(s.Dir()).Trusts(in.Agent) {
+		return nil, nil
+	}
+	agent := in.Agent
+	if agent == "" {
+		agent = "unnamed agent"
+	}
+	return pipeline.QueuePending(s.Dir(), pipeline.PendingBeat{
+		Source:  in.Impetus.Meta["source"],
+		Reason:  pipeline.PendingApproval,
+		Details: []string{agent + " is not trusted"},
+		Agent:   in.Agent,
+		Redact:  in.Redact,
+		Extract: in.Extract,
+		Beat:    in.ProposedBeat,
+	})
+}
+
+// Pending outputs the captures waiting for review as JSON.
+func (c *RobotCLI) Pending() error {
+	list, err := pipeline.LoadPending(c.store.Dir())
+	if err != nil {
+		return outputError("failed to read pending captures", err)
+	}
+	return outputJSON(map[string]interface{}{"pending": list})
+}
+
+// ReviewOptions configures Review. IDs are pending IDs, or "all".
+type ReviewOptions struct {
+	Accept  []string // Store these captures
+	Drop    []string // Discard these captures
+	Reason  string   // Limit "all" to captures held for this reason
+	Channel string   // Limit "all", and the captures listed, to this capture channel
+}
+
+// ReviewResult reports what a review did.
+type ReviewResult struct {
+	Accepted []beat.Beat            `json:"accepted"`
+	Dropped  []string               `json:"dropped"`
+	Pending  []pipeline.PendingBeat `json:"pending"` // Still waiting after the review
+}
+
+// ReviewPending stores the accepted captures and discards the dropped ones,
+// leaving the rest queued. Accepted captures skip the quota check but
+// otherwise commit as they would have.
+func (c *HumanCLI) ReviewPending(opts ReviewOptions) (*ReviewResult, error) {
+	defer pipeline.LockPending()()
+	list, err := pipeline.LoadPending(c.store.Dir())
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(list))
+	for _, pb := range list {
+		known[pb.ID] = true
+	}
+	for _, id := range append(append([]string{}, opts.Accept...), opts.Drop...) {
+		if id != "all" && !known[id] {
+			return nil, fmt.Errorf("no pending capture %s", id)
+		}
+	}
+	inChannel := func(pb pipeline.PendingBeat) bool {
+		return opts.Channel == "" || proposedChannel(pb.Beat) == opts.Channel
+	}
+	picked := func(ids []string, pb pipeline.PendingBeat) bool {
+		for _, x := range ids {
+			if x == pb.ID || x == "all" && (opts.Reason == "" || pb.Reason == opts.Reason) && inChannel(pb) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := &ReviewResult{Accepted: []beat.Beat{}, Dropped: []string{}, Pending: []pipeline.PendingBeat{}}
+	var commitErr error
+	for i, pb := range list {
+		switch {
+		case picked(opts.Accept, pb):
+			proposed := pb.Beat
+			b, err := c.commitBeat(&proposed, pipeline.Options{Redact: pb.Redact, Extract: pb.Extract, Reviewed: true})
+			if err != nil {
+				// Keep this capture and the rest queued
+				commitErr = fmt.Errorf("accepting %s: %w", pb.ID, err)
+				result.Pending = append(result.Pending, list[i:]...)
+			} else {
+				result.Accepted = append(result.Accepted, *b)
+			}
+		case picked(opts.Drop, pb):
+			result.Dropped = append(result.Dropped, pb.ID)
+		default:
+			result.Pending = append(result.Pending, pb)
+		}
+		if commitErr != nil {
+			break
+		}
+	}
+	if err := pipeline.SavePending(c.store.Dir(), result.Pending); err != nil {
+		return nil, err
+	}
+	return result, commitErr
+}
+
+// Approve stores robot commits staged for approval: the given pending IDs,
+// or with all every commit waiting for approval.
+func (c *HumanCLI) Approve(ids []string, all, jsonOut bool) error {
+	if all {
+		ids = append(ids, "all")
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: bt approve <pending-id>... | --all")
+	}
+	return c.Review(ReviewOptions{Accept: ids, Reason: pipeline.PendingApproval}, jsonOut)
+}
+
+// Review lists the pending queue, or accepts and drops captures from it.
+func (c *HumanCLI) Review(opts ReviewOptions, jsonOut bool) error {
+	result, err := c.ReviewPending(opts)
+	if err != nil {
+		return err
+	}
+	if opts.Channel != "" {
+		kept := []pipeline.PendingBeat{}
+		for _, pb := range result.Pending {
+			if proposedChannel(pb.Beat) == opts.Channel {
+				kept = append(kept, pb)
+			}
+		}
+		result.Pending = kept
+	}
+	if jsonOut {
+		return outputJSON(result)
+	}
+	for _, b := range result.Accepted {
+		fmt.Printf("Accepted %s: %s\n", b.ID, truncate(b.Content, 80))
+	}
+	for _, id := range result.Dropped {
+		fmt.Printf("Dropped %s\n", id)
+	}
+	if len(result.Accepted)+len(result.Dropped) > 0 {
+		fmt.Println()
+	}
+	if len(result.Pending) == 0 {
+		fmt.Println("No captures pending review.")
+		return nil
+	}
+
+	fmt.Printf("Pending review (%d):\n\n", len(result.Pending))
+	for _, pb := range result.Pending {
+		fmt.Printf("  %s  %s  %-8s %-8s %s\n", pb.ID, displayDate(pb.QueuedAt), proposedChannel(pb.Beat), pb.Source, strings.Join(append([]string{pb.Reason}, pb.Details...), ", "))
+		fmt.Printf("      %s\n", truncate(pb.Beat.Content, 100))
+	}
+	fmt.Println("\nStore with --accept <id>,... or discard with --drop <id>,... (or all).")
+	return nil
+}
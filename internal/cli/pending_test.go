@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestQuotaHoldsCaptures(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := `{"quota": {"enabled": true, "daily": {"bot": 1}, "noise_patterns": ["^build #\\d+ passed$"]}}`
+	if err := os.WriteFile(filepath.Join(s.Dir(), hooks.HooksConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewHumanCLI(s)
+	capture := func(source, content string) error {
+		p := &beat.ProposedBeat{Content: content}
+		if source != "" {
+			p.Impetus.Meta = map[string]string{"source": source}
+		}
+		_, err := c.commit(p, beat.ChannelWebhook)
+		return err
+	}
+
+	if err := capture("bot", "Deploy finished for the api service"); err != nil {
+		t.Fatalf("first bot capture: %v", err)
+	}
+	captures := []struct {
+		source, content string
+		reason          string // Empty when the capture is stored
+	}{
+		{"bot", "Deploy finished for the web service", pipeline.PendingQuota},
+		{"watch", "0x3f9a2c71", pipeline.PendingNoise},        // One word, mostly symbols
+		{"watch", "build #412 passed", pipeline.PendingNoise}, // Noise pattern
+		{"watch", "Deploy finished for the api service", ""},  // Repeats a beat from another source
+		{"", "ok", ""}, // Typed beats are never held
+	}
+	for _, tc := range captures {
+		err := capture(tc.source, tc.content)
+		var held *pipeline.PendingError
+		switch {
+		case tc.reason == "" && err != nil:
+			t.Errorf("capture %q: %v", tc.content, err)
+		case tc.reason != "" && !errors.As(err, &held):
+			t.Errorf("capture %q = %v; want it held", tc.content, err)
+		case held != nil && held.Pending.Reason != tc.reason:
+			t.Errorf("capture %q held for %s; want %s", tc.content, held.Pending.Reason, tc.reason)
+		}
+	}
+
+	list, err := pipeline.LoadPending(s.Dir())
+	if err != nil || len(list) != 3 {
+		t.Fatalf("pending = %+v, %v; want 3", list, err)
+	}
+	result, err := c.ReviewPending(ReviewOptions{Accept: []string{list[0].ID}, Drop: []string{list[1].ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Accepted) != 1 || result.Accepted[0].Content != "Deploy finished for the web service" {
+		t.Errorf("accepted = %+v", result.Accepted)
+	}
+	if len(result.Dropped) != 1 || len(result.Pending) != 1 || result.Pending[0].ID != list[2].ID {
+		t.Errorf("review = %+v; want one dropped and %s still pending", result, list[2].ID)
+	}
+	if _, err := c.ReviewPending(ReviewOptions{Drop: []string{"pending-19990101-001"}}); err == nil {
+		t.Error("review accepted an unknown pending ID")
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beats) != 4 {
+		t.Errorf("store has %d beats; want 4", len(beats))
+	}
+}
+
+func TestApprovalStagesRobotCommits(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := `{"approval": {"enabled": true, "trusted": ["planner"]}, "quota": {"enabled": true, "noise_threshold": 1}}`
+	if err := os.WriteFile(filepath.Join(s.Dir(), hooks.HooksConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stage := func(agent, content string) *pipeline.PendingBeat {
+		t.Helper()
+		pb, err := stageCommit(s, &CommitInput{ProposedBeat: beat.ProposedBeat{Content: content}, Agent: agent, Extract: "off"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pb
+	}
+	if pb := stage("planner", "Trusted agents commit directly"); pb != nil {
+		t.Errorf("trusted agent staged as %s", pb.ID)
+	}
+	first := stage("scraper", "Competitor launched a pricing page")
+	stage("", "Unnamed agents always need approval")
+	if first == nil || first.Reason != pipeline.PendingApproval || first.Extract != "off" {
+		t.Fatalf("stageCommit(scraper) = %+v; want an approval entry keeping its options", first)
+	}
+
+	// A capture held by the quota hook is not swept up by approve --all
+	c := NewHumanCLI(s)
+	if _, err := c.commit(&beat.ProposedBeat{Content: "#### ####", Impetus: beat.Impetus{Meta: map[string]string{"source": "bot"}}}, beat.ChannelWebhook); err == nil {
+		t.Fatal("noisy capture was stored")
+	}
+	result, err := c.ReviewPending(ReviewOptions{Accept: []string{"all"}, Reason: pipeline.PendingApproval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Accepted) != 2 || len(result.Pending) != 1 || result.Pending[0].Reason != pipeline.PendingNoise {
+		t.Errorf("approve all = %d accepted, pending %+v; want 2 and the noisy capture left", len(result.Accepted), result.Pending)
+	}
+}
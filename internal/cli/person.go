@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/entity"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// DefaultPersonThreads is how many commitments and open threads a person
+// brief lists.
+const DefaultPersonThreads = 10
+
+var (
+	// commitmentPattern finds promises and deadlines in a beat.
+	commitmentPattern = regexp.MustCompile(`(?i)\b(i'll|i will|we'll|we will|will send|promised|agreed to|committed to|commit to|owe|deadline|due|by (monday|tuesday|wednesday|thursday|friday|saturday|sunday|tomorrow|next week|end of|eod))\b`)
+	// openThreadPattern finds questions and loose ends.
+	openThreadPattern = regexp.MustCompile(`\?|(?i)\b(todo|follow[- ]?up|need to|needs to|waiting (on|for)|open question|tbd|pending|unresolved|to discuss|check in)\b`)
+)
+
+// PersonBriefOptions configures a person brief.
+type PersonBriefOptions struct {
+	Name    string
+	Threads int       // Commitments and open threads listed (default 10)
+	Now     time.Time // Defaults to the current time
+}
+
+// PersonBeat is a beat about a person, and how it was found.
+type PersonBeat struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Content   string    `json:"content"`
+	Via       string    `json:"via"` // entity, counterparty or mention
+}
+
+// PersonBrief gathers what the store knows about one person, for meeting
+// prep: every beat naming them, oldest first, and the ones that hold
+// commitments or open threads, newest first.
+type PersonBrief struct {
+	Person           string       `json:"person"`
+	Aliases          []string     `json:"aliases"`
+	BeatCount        int          `json:"beat_count"`
+	FirstInteraction *PersonBeat  `json:"first_interaction,omitempty"`
+	LastInteraction  *PersonBeat  `json:"last_interaction,omitempty"`
+	DaysSinceLast    int          `json:"days_since_last"`
+	Commitments      []PersonBeat `json:"commitments"`
+	OpenThreads      []PersonBeat `json:"open_threads"`
+	Timeline         []PersonBeat `json:"timeline"`
+}
+
+// BuildPersonBrief finds the beats about a person: those with them as an
+// entity (under their name or an alias from .beats/aliases.json), with them
+// as impetus.meta counterparty, or naming them in the content.
+func BuildPersonBrief(s *store.JSONLStore, opts PersonBriefOptions) (*PersonBrief, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return nil, fmt.Errorf("person name is required")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now()
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = DefaultPersonThreads
+	}
+	aliases, err := entity.LoadAliases(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	canonical, _ := aliases.Canonical(name)
+	names := []string{canonical}
+	for alias, c := range aliases {
+		if c == canonical && !strings.EqualFold(alias, canonical) {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names[1:])
+	if !strings.EqualFold(name, canonical) && !containsFold(names, name) {
+		names = append(names, name)
+	}
+	mentions := make([]*regexp.Regexp, len(names))
+	for i, n := range names {
+		mentions[i] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(n) + `\b`)
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	brief := &PersonBrief{Person: canonical, Aliases: names[1:], Commitments: []PersonBeat{}, OpenThreads: []PersonBeat{}, Timeline: []PersonBeat{}}
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] != "" {
+			continue // Digests and reviews restate other beats
+		}
+		via := personVia(b, names, mentions)
+		if via == "" {
+			continue
+		}
+		brief.Timeline = append(brief.Timeline, PersonBeat{ID: b.ID, CreatedAt: b.CreatedAt, Impetus: b.Impetus.Label, Content: b.Content, Via: via})
+	}
+	sort.SliceStable(brief.Timeline, func(i, j int) bool { return brief.Timeline[i].CreatedAt.Before(brief.Timeline[j].CreatedAt) })
+
+	brief.BeatCount = len(brief.Timeline)
+	if brief.BeatCount == 0 {
+		return brief, nil
+	}
+	first, last := brief.Timeline[0], brief.Timeline[brief.BeatCount-1]
+	brief.FirstInteraction, brief.LastInteraction = &first, &last
+	brief.DaysSinceLast = daysBetween(last.CreatedAt, opts.Now)
+	for i := brief.BeatCount - 1; i >= 0; i-- {
+		pb := brief.Timeline[i]
+		if commitmentPattern.MatchString(pb.Content) && len(brief.Commitments) < opts.Threads {
+			brief.Commitments = append(brief.Commitments, pb)
+		}
+		if openThreadPattern.MatchString(pb.Content) && len(brief.OpenThreads) < opts.Threads {
+			brief.OpenThreads = append(brief.OpenThreads, pb)
+		}
+	}
+	return brief, nil
+}
+
+// personVia reports how a beat refers to a person, or "" when it does not.
+func personVia(b beat.Beat, names []string, mentions []*regexp.Regexp) string {
+	for _, e := range b.Entities {
+		if e.Category == "person" && containsFold(names, e.Label) {
+			return "entity"
+		}
+	}
+	if cp := b.Impetus.Meta["counterparty"]; cp != "" && containsFold(names, cp) {
+		return "counterparty"
+	}
+	for _, re := range mentions {
+		if re.MatchString(b.Content) {
+			return "mention"
+		}
+	}
+	return ""
+}
+
+func containsFold(list []string, s string) bool {
+	s = strings.TrimSpace(s)
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// daysBetween counts calendar days in the display time zone from t to now.
+func daysBetween(t, now time.Time) int {
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(DisplayLocation).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(day(now).Sub(day(t)).Hours() / 24)
+}
+
+// Person prints a person brief.
+func (c *HumanCLI) Person(opts PersonBriefOptions, jsonOut bool) error {
+	brief, err := BuildPersonBrief(c.store, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(brief)
+	}
+	if brief.BeatCount == 0 {
+		fmt.Printf("No beats about %s.\n", brief.Person)
+		return nil
+	}
+
+	fmt.Printf("%s: %d beat(s), %s to %s\n", brief.Person, brief.BeatCount, displayDate(brief.FirstInteraction.CreatedAt), displayDate(brief.LastInteraction.CreatedAt))
+	if len(brief.Aliases) > 0 {
+		fmt.Printf("Also known as: %s\n", strings.Join(brief.Aliases, ", "))
+	}
+	last := brief.LastInteraction
+	fmt.Printf("Last interaction: %s (%d day(s) ago)  %s  %s\n", displayDate(last.CreatedAt), brief.DaysSinceLast, last.ID, truncate(last.Content, 80))
+
+	section := func(title string, list []PersonBeat) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Printf("\n%s:\n", title)
+		for _, pb := range list {
+			fmt.Printf("  - %s  %s  %s\n", displayDate(pb.CreatedAt), pb.ID, truncate(pb.Content, 100))
+		}
+	}
+	section("Commitments", brief.Commitments)
+	section("Open threads", brief.OpenThreads)
+
+	fmt.Printf("\nTimeline:\n")
+	for _, pb := range brief.Timeline {
+		fmt.Printf("  - %s  %s  (%s)  %s\n", displayDate(pb.CreatedAt), pb.ID, pb.Impetus, truncate(pb.Content, 80))
+	}
+	return nil
+}
+
+// PersonBriefInput is the input for --robot-person-brief.
+type PersonBriefInput struct {
+	Person  string `json:"person"`
+	Threads int    `json:"max_threads,omitempty"`
+}
+
+// PersonBrief outputs a person brief as JSON.
+func (c *RobotCLI) PersonBrief(input io.Reader) error {
+	var in PersonBriefInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
+		return outputError("invalid input JSON", err)
+	}
+	if strings.TrimSpace(in.Person) == "" {
+		return outputError("person is required", nil)
+	}
+	brief, err := BuildPersonBrief(c.store, PersonBriefOptions{Name: in.Person, Threads: in.Threads})
+	if err != nil {
+		return outputError("failed to build person brief", err)
+	}
+	return outputJSON(brief)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/entity"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestBuildPersonBrief(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(), entity.AliasesFile), []byte(`{"Jane Doe": ["Jane", "JD"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var beats []*beat.Beat
+	add := func(id string, daysAgo int, content string, meta map[string]string, entities ...beat.Entity) {
+		at := now.AddDate(0, 0, -daysAgo)
+		beats = append(beats, &beat.Beat{ID: id, CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Call", Meta: meta}, Content: content, Entities: entities})
+	}
+	add("beat-20260310-001", 10, "Call with Jane: I'll send the pricing deck by Friday", nil)
+	add("beat-20260301-001", 19, "Intro call went well", map[string]string{"counterparty": "jane doe"})
+	add("beat-20260315-001", 5, "Board meeting notes", nil, beat.Entity{Label: "JD", Category: "person"})
+	add("beat-20260318-001", 2, "Need to ask jd whether the pilot can start in April?", nil)
+	add("beat-20260319-001", 1, "Janet thinks the roadmap is fine", nil) // Not a word match
+	add("beat-20260319-002", 1, "Jane Doe's week in review", map[string]string{"kind": WeeklyKind})
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	brief, err := BuildPersonBrief(s, PersonBriefOptions{Name: "jane", Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brief.Person != "Jane Doe" || brief.BeatCount != 4 || len(brief.Aliases) != 2 {
+		t.Fatalf("brief = %+v", brief)
+	}
+	var ids, via []string
+	for _, pb := range brief.Timeline {
+		ids = append(ids, pb.ID)
+		via = append(via, pb.Via)
+	}
+	wantIDs := []string{"beat-20260301-001", "beat-20260310-001", "beat-20260315-001", "beat-20260318-001"}
+	wantVia := []string{"counterparty", "mention", "entity", "mention"}
+	for i := range wantIDs {
+		if ids[i] != wantIDs[i] || via[i] != wantVia[i] {
+			t.Fatalf("timeline = %v via %v; want %v via %v", ids, via, wantIDs, wantVia)
+		}
+	}
+	if brief.LastInteraction.ID != "beat-20260318-001" || brief.DaysSinceLast != 2 {
+		t.Errorf("last interaction = %+v, %d days", brief.LastInteraction, brief.DaysSinceLast)
+	}
+	if len(brief.Commitments) != 1 || brief.Commitments[0].ID != "beat-20260310-001" {
+		t.Errorf("commitments = %+v", brief.Commitments)
+	}
+	if len(brief.OpenThreads) != 1 || brief.OpenThreads[0].ID != "beat-20260318-001" {
+		t.Errorf("open threads = %+v", brief.OpenThreads)
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Prime output formats.
+const (
+	PrimeMarkdown = "md"
+	PrimeJSON     = "json"
+)
+
+// primeRecent is how many of the latest beats the context block lists.
+const primeRecent = 5
+
+// PrimeContext is the block that primes a new agent session with what the
+// store knows: where attention is heading, what is ripe and what was
+// captured last.
+type PrimeContext struct {
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Store         string           `json:"store"`
+	TotalBeats    int              `json:"total_beats"`
+	Orientation   *Orientation     `json:"orientation"`
+	Attention     *AttentionReport `json:"attention"`
+	Ripe          *RipeReport      `json:"ripe"`
+	Recent        []PrimeBeat      `json:"recent"`
+	QuickCommands []string         `json:"quick_commands"`
+}
+
+// PrimeBeat is a beat as listed in the context block.
+type PrimeBeat struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Preview   string    `json:"preview"`
+}
+
+var primeQuickCommands = []string{
+	"`bt add \"insight\"` — capture",
+	"`bt add -s \"note\"` — session-tagged",
+	"`bt search \"topic\"` — find earlier beats",
+	"`bt prime` — refresh this context",
+}
+
+// Prime assembles the session-priming context for a store.
+func Prime(s *store.JSONLStore, now time.Time) (*PrimeContext, error) {
+	if now.IsZero() {
+		now = clock.Now().UTC()
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	orientation, err := Orient(s, AttentionOptions{Now: now})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PrimeContext{
+		GeneratedAt:   now,
+		Store:         s.Dir(),
+		TotalBeats:    len(beats),
+		Orientation:   orientation,
+		Attention:     orientation.attention,
+		Ripe:          orientation.ripe,
+		Recent:        []PrimeBeat{},
+		QuickCommands: primeQuickCommands,
+	}
+	sortBeatsByRecency(beats)
+	for i := 0; i < len(beats) && i < primeRecent; i++ {
+		b := beats[i]
+		p.Recent = append(p.Recent, PrimeBeat{ID: b.ID, CreatedAt: b.CreatedAt, Impetus: b.Impetus.Label, Preview: truncate(b.Content, 80)})
+	}
+	return p, nil
+}
+
+// Markdown renders the context as a block to paste or inject into a session.
+func (p *PrimeContext) Markdown() string {
+	var out strings.Builder
+	out.WriteString("# Beats Context\n\n")
+	out.WriteString("> Run `bt prime` after new session when .beats/ detected\n\n")
+
+	if len(p.Attention.Activations) > 0 {
+		fmt.Fprintf(&out, "## Activating Topics (%s)\n", p.Attention.Window)
+		for _, a := range p.Attention.Activations {
+			fmt.Fprintf(&out, "- **%s** (%d beats, %s)\n", a.Topic, a.Beats, a.Trend)
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString("## Attention Direction\n")
+	fmt.Fprintf(&out, "%s\n%s\n\n", p.Orientation.Direction, p.Orientation.Summary)
+
+	if len(p.Ripe.Beats) > 0 {
+		out.WriteString("## Ripe Beats\n")
+		for _, b := range p.Ripe.Beats {
+			fmt.Fprintf(&out, "- %s: \"%s\"\n", b.ID, truncate(b.Preview, 63))
+		}
+		out.WriteString("\n")
+	}
+
+	if len(p.Recent) > 0 {
+		out.WriteString("## Recent Beats\n")
+		for _, b := range p.Recent {
+			fmt.Fprintf(&out, "- %s (%s, %s): %s\n", b.ID, displayDate(b.CreatedAt), b.Impetus, b.Preview)
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString("## Quick Commands\n")
+	for _, c := range p.QuickCommands {
+		fmt.Fprintf(&out, "- %s\n", c)
+	}
+	return out.String()
+}
+
+// Prime prints the session-priming context in the given format.
+func (c *HumanCLI) Prime(format string) error {
+	if format != PrimeMarkdown && format != PrimeJSON {
+		return fmt.Errorf("unknown format %q (use %s or %s)", format, PrimeMarkdown, PrimeJSON)
+	}
+	p, err := Prime(c.store, time.Time{})
+	if err != nil {
+		return err
+	}
+	if format == PrimeJSON {
+		return outputJSON(p)
+	}
+	fmt.Print(p.Markdown())
+	return nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// PromoteOptions contains options for the promote command.
+type PromoteOptions struct {
+	Title       string
+	Description string // Opening paragraph, above the seed beats
+	Type        string // Bead type (default epic)
+	ID          string // Link to this existing bead instead of creating one
+	Markdown    bool   // Write a markdown draft instead of calling the provider
+	Output      string // Markdown draft file (default stdout)
+	DryRun      bool
+}
+
+// Promote drafts a bead from beats: the provider creates it when it can,
+// otherwise a markdown draft is written. The beats are then linked to the
+// new bead and record the promotion in their impetus meta (promoted_to,
+// promoted_at).
+func (c *HumanCLI) Promote(beatIDs []string, opts PromoteOptions) error {
+	if len(beatIDs) == 0 {
+		return fmt.Errorf("promote requires at least one beat ID")
+	}
+	if opts.Title == "" && opts.ID == "" {
+		return fmt.Errorf("promote requires --title")
+	}
+	if opts.Type == "" {
+		opts.Type = "epic"
+	}
+
+	for i, ref := range beatIDs {
+		id, err := c.store.ResolveID(ref)
+		var ambiguous *store.AmbiguousIDError
+		if errors.As(err, &ambiguous) {
+			return err
+		}
+		if err == nil {
+			beatIDs[i] = id // Unresolved ones are reported together below
+		}
+	}
+	seeds, err := c.store.GetByIDs(beatIDs)
+	if err != nil {
+		return err
+	}
+	if len(seeds) != len(beatIDs) {
+		got := make(map[string]bool, len(seeds))
+		for _, b := range seeds {
+			got[b.ID] = true
+		}
+		var missing []string
+		for _, id := range beatIDs {
+			if !got[id] {
+				missing = append(missing, id)
+			}
+		}
+		return fmt.Errorf("beat(s) not found: %s", strings.Join(missing, ", "))
+	}
+
+	draft := beads.Draft{
+		Title:       opts.Title,
+		Description: promotionBody(seeds, opts.Description, clock.Now()),
+		Type:        opts.Type,
+	}
+
+	var creator beads.Creator
+	var providerName string
+	if !opts.Markdown && opts.ID == "" {
+		provider, err := beads.NewProvider(c.store.Dir())
+		if err != nil {
+			return err
+		}
+		if provider != nil {
+			providerName = provider.Name()
+			creator, _ = provider.(beads.Creator)
+		}
+		if creator == nil {
+			fmt.Fprintf(os.Stderr, "No bead provider that can create beads; writing a markdown draft.\n")
+		}
+	}
+
+	if opts.DryRun {
+		if creator != nil {
+			fmt.Printf("Would create %s in %s: %s\n\n", opts.Type, providerName, opts.Title)
+			fmt.Println(draft.Description)
+		} else if opts.ID == "" {
+			fmt.Print(promotionMarkdown(draft))
+		}
+		fmt.Printf("\nWould link %d beat(s): %s\n", len(seeds), strings.Join(beatIDs, ", "))
+		return nil
+	}
+
+	beadID := opts.ID
+	switch {
+	case creator != nil:
+		bead, err := creator.Create(draft)
+		if err != nil {
+			return fmt.Errorf("failed to create bead in %s: %w", providerName, err)
+		}
+		beadID = bead.ID
+		fmt.Printf("Created %s %s in %s: %s\n", opts.Type, bead.ID, providerName, bead.Title)
+		if bead.URL != "" {
+			fmt.Printf("  %s\n", bead.URL)
+		}
+	case opts.ID == "":
+		md := promotionMarkdown(draft)
+		if opts.Output == "" {
+			fmt.Print(md)
+		} else {
+			if err := os.WriteFile(opts.Output, []byte(md), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote draft to %s\n", opts.Output)
+		}
+	}
+
+	if beadID == "" {
+		fmt.Fprintf(os.Stderr, "Once the bead exists, link the beats with: bt promote --id <bead-id> %s\n", strings.Join(beatIDs, " "))
+		return nil
+	}
+
+	now := clock.Now().UTC().Format(time.RFC3339)
+	for _, id := range beatIDs {
+		if _, err := c.store.Update(id, func(b *beat.Beat) error {
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: beadID, CreatedAt: clock.Now().UTC(), CreatedBy: linkAuthor()}) // A new link is a seed
+			if b.Impetus.Meta == nil {
+				b.Impetus.Meta = make(map[string]string)
+			}
+			b.Impetus.Meta["promoted_to"] = beadID
+			b.Impetus.Meta["promoted_at"] = now
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to link %s: %w", id, err)
+		}
+	}
+	fmt.Printf("Linked %d beat(s) to %s\n", len(beatIDs), beadID)
+	return nil
+}
+
+// promotionBody is the bead description: the intro, then each seed beat as
+// narrative context, then a provenance line.
+func promotionBody(seeds []beat.Beat, intro string, now time.Time) string {
+	var sb strings.Builder
+	if intro = strings.TrimSpace(intro); intro != "" {
+		sb.WriteString(intro + "\n\n")
+	}
+	sb.WriteString("## Narrative context\n\n")
+	for _, b := range seeds {
+		fmt.Fprintf(&sb, "- **%s** (%s, %s): %s\n", b.ID, b.Impetus.Label, b.CreatedAt.Format("2006-01-02"),
+			strings.ReplaceAll(truncate(strings.TrimSpace(b.Content), 400), "\n", " "))
+		for _, ref := range b.References {
+			if ref.Kind == "url" {
+				fmt.Fprintf(&sb, "  - %s\n", ref.Locator)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "\n_Promoted from %d beat(s) on %s. Run `bt show <beat-id>` for the full context._\n",
+		len(seeds), now.Format("2006-01-02"))
+	return sb.String()
+}
+
+// promotionMarkdown renders a draft as a standalone markdown file.
+func promotionMarkdown(d beads.Draft) string {
+	return fmt.Sprintf("# %s\n\ntype: %s\n\n%s", d.Title, d.Type, d.Description)
+}
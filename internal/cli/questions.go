@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/questions"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// QuestionsOptions configures OpenQuestions.
+type QuestionsOptions struct {
+	MinAge time.Duration // Leave out questions younger than this
+	Max    int           // Oldest questions listed (0 for all)
+	Now    time.Time     // Defaults to the current time
+}
+
+// QuestionsReport lists the open loops in a store.
+type QuestionsReport struct {
+	Open      int                  `json:"open"` // Before Max is applied
+	Questions []questions.Question `json:"questions"`
+}
+
+// OpenQuestions finds the questions no beat has followed up on and no bead
+// has taken over, oldest first.
+func OpenQuestions(s *store.JSONLStore, opts QuestionsOptions) (*QuestionsReport, error) {
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	report := &QuestionsReport{Questions: []questions.Question{}}
+	for _, q := range questions.Open(beats, opts.Now) {
+		if opts.Now.Sub(q.CreatedAt) < opts.MinAge {
+			continue
+		}
+		report.Questions = append(report.Questions, q)
+	}
+	report.Open = len(report.Questions)
+	if opts.Max > 0 && len(report.Questions) > opts.Max {
+		report.Questions = report.Questions[:opts.Max]
+	}
+	return report, nil
+}
+
+// Questions prints the open questions.
+func (c *HumanCLI) Questions(opts QuestionsOptions, jsonOut bool) error {
+	report, err := OpenQuestions(c.store, opts)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(report)
+	}
+	if report.Open == 0 {
+		fmt.Println("No open questions.")
+		return nil
+	}
+
+	fmt.Printf("Open questions (%d):\n\n", report.Open)
+	for _, q := range report.Questions {
+		fmt.Printf("  %s  %3dd  %s\n", q.ID, q.AgeDays, truncate(q.Question, 100))
+	}
+	if n := report.Open - len(report.Questions); n > 0 {
+		fmt.Printf("\n... and %d newer\n", n)
+	}
+	fmt.Println("\nClose one with a beat that references it, or link it to a bead.")
+	return nil
+}
+
+// Questions outputs every open question as JSON.
+func (c *RobotCLI) Questions() error {
+	report, err := OpenQuestions(c.store, QuestionsOptions{})
+	if err != nil {
+		return outputError("failed to find open questions", err)
+	}
+	return outputJSON(report)
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/tokens"
+)
+
+const (
+	defaultTokenBudget = 2000
+	ragCandidates      = 50 // Results fetched per retriever before dedupe
+	minSnippetTokens   = 40 // Smallest truncated beat worth including
+
+	// scoreTie is how close two fused scores are to count as equal. The
+	// same text can score a few ulps apart, since vectors are summed in map
+	// order.
+	scoreTie = 1e-9
+)
+
+// RAGCitation identifies a beat included in an assembled context block.
+type RAGCitation struct {
+	ID        string    `json:"id"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// RAGContextOutput is the output of --robot-context when given a question.
+type RAGContextOutput struct {
+	Question    string              `json:"question"`
+	TokenBudget int                 `json:"token_budget"`
+	TokensUsed  int                 `json:"tokens_used"`
+	Context     string              `json:"context"`
+	Citations   []RAGCitation       `json:"citations"`
+	Mode        string              `json:"mode"`
+	Candidates  int                 `json:"candidates"`
+	Scoring     store.ScoringConfig `json:"scoring"`
+}
+
+// normalizeScores scales a result list so its best score is 1.0, letting
+// keyword and semantic scores be compared.
+func normalizeScores(results []beat.SearchResult) map[string]float64 {
+	var max float64
+	for _, r := range results {
+		if r.Score > max {
+			max = r.Score
+		}
+	}
+	scores := make(map[string]float64, len(results))
+	for _, r := range results {
+		if max > 0 {
+			scores[r.ID] = r.Score / max
+		}
+	}
+	return scores
+}
+
+// fuseScores merges retrievers' results, keeping each beat's best normalized
+// score.
+func fuseScores(lists ...[]beat.SearchResult) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, results := range lists {
+		for id, score := range normalizeScores(results) {
+			if score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+	return scores
+}
+
+// rankBeats retrieves the beats matching query with hybrid and keyword search,
+// most relevant first with newer beats winning ties, and drops beats that
+// repeat content already ranked (re-captures, imports). It also returns each
+// beat's fused score and the search mode used.
+func rankBeats(ctx context.Context, s *store.JSONLStore, query string, scoring store.ScoringConfig) ([]beat.Beat, map[string]float64, string, error) {
+	hybrid, err := store.HybridSearchWithScoringContext(ctx, s, query, ragCandidates, true, scoring)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	keyword, err := s.SearchWithScoring(query, ragCandidates, scoring)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	scores := fuseScores(hybrid.Results, keyword)
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	candidates, err := s.GetByIDs(ids)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to read beats: %w", err)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := scores[candidates[i].ID], scores[candidates[j].ID]
+		if math.Abs(si-sj) > scoreTie {
+			return si > sj
+		}
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+
+	seen := make(map[string]bool)
+	var unique []beat.Beat
+	for _, b := range candidates {
+		key := strings.ToLower(strings.Join(strings.Fields(b.Content), " "))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, b)
+	}
+	return unique, scores, hybrid.Mode, nil
+}
+
+// ragContext answers --robot-context {question}: retrieve beats with hybrid
+// search, dedupe, and assemble a citation-tagged block within the token budget.
+func (c *RobotCLI) ragContext(in ContextInput) error {
+	budget := in.TokenBudget
+	if budget <= 0 {
+		budget = defaultTokenBudget
+	}
+	if in.Order != "" && in.Order != "relevance" && in.Order != "time" {
+		return outputError("order must be 'relevance' or 'time'", nil)
+	}
+
+	scoring, err := c.scoring(in.Scoring)
+	if err != nil {
+		return outputError("invalid scoring", err)
+	}
+
+	unique, scores, mode, err := rankBeats(c.ctx, c.store, in.Question, scoring)
+	if err != nil {
+		return outputError("search failed", err)
+	}
+
+	type entry struct {
+		citation RAGCitation
+		block    string
+		tokens   int
+	}
+	var selected []entry
+	used := 0
+	for _, b := range unique {
+		header := fmt.Sprintf("[%s | %s | %s]\n", b.ID, b.CreatedAt.Format("2006-01-02"), b.Impetus.Label)
+		body := strings.TrimSpace(b.Content)
+		cost := tokens.Estimate(header+body) + 1
+
+		truncated := false
+		if used+cost > budget {
+			room := budget - used - tokens.Estimate(header) - 1
+			if room < minSnippetTokens {
+				break
+			}
+			body = truncate(body, room*4)
+			cost = tokens.Estimate(header+body) + 1
+			truncated = true
+		}
+
+		selected = append(selected, entry{
+			citation: RAGCitation{
+				ID:        b.ID,
+				Score:     scores[b.ID],
+				CreatedAt: b.CreatedAt,
+				Impetus:   b.Impetus.Label,
+				Truncated: truncated,
+			},
+			block:  header + body,
+			tokens: cost,
+		})
+		used += cost
+		if truncated {
+			break
+		}
+	}
+
+	if in.Order == "time" {
+		sort.SliceStable(selected, func(i, j int) bool {
+			return selected[i].citation.CreatedAt.Before(selected[j].citation.CreatedAt)
+		})
+	}
+
+	blocks := make([]string, 0, len(selected))
+	citations := make([]RAGCitation, 0, len(selected))
+	for _, e := range selected {
+		blocks = append(blocks, e.block)
+		citations = append(citations, e.citation)
+	}
+
+	return outputJSON(RAGContextOutput{
+		Question:    in.Question,
+		TokenBudget: budget,
+		TokensUsed:  used,
+		Context:     strings.Join(blocks, "\n\n"),
+		Citations:   citations,
+		Mode:        mode,
+		Candidates:  len(unique),
+		Scoring:     scoring,
+	})
+}
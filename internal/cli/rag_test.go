@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/tokens"
+)
+
+func TestRAGContext(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	rebalance := "Kafka rebalancing stalls consumers. " + strings.Repeat("The group coordinator waits for every member to rejoin. ", 8)
+	fixtures := []beat.Beat{
+		{ID: "beat-20260301-001", CreatedAt: at, Impetus: beat.Impetus{Label: "Research"}, Content: rebalance},
+		// The same note captured again, differently spaced and cased
+		{ID: "beat-20260302-001", CreatedAt: at.AddDate(0, 0, 1), Impetus: beat.Impetus{Label: "Import"}, Content: strings.ReplaceAll(strings.ToLower(rebalance), ". ", ".\n\n")},
+		{ID: "beat-20260303-001", CreatedAt: at.AddDate(0, 0, 2), Impetus: beat.Impetus{Label: "Idea"}, Content: "Kafka consumer lag alerts. " + strings.Repeat("Page when lag grows for ten minutes. ", 8)},
+		{ID: "beat-20260304-001", CreatedAt: at.AddDate(0, 0, 3), Impetus: beat.Impetus{Label: "Journal"}, Content: "Walked the dog"},
+	}
+	for i := range fixtures {
+		if err := s.Append(&fixtures[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content := map[string]string{}
+	for _, b := range fixtures {
+		content[b.ID] = strings.TrimSpace(b.Content)
+	}
+
+	ask := func(budget int) RAGContextOutput {
+		t.Helper()
+		var out bytes.Buffer
+		SetJSONOutput(&out)
+		defer SetJSONOutput(nil)
+		input := fmt.Sprintf(`{"question": "kafka rebalancing consumers", "token_budget": %d}`, budget)
+		if err := NewRobotCLI(s).Context(strings.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+		var got RAGContextOutput
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("%v: %s", err, out.String())
+		}
+		return got
+	}
+	// cost is what a beat's block takes of the budget, whole
+	header := func(c RAGCitation) string {
+		return fmt.Sprintf("[%s | %s | %s]\n", c.ID, c.CreatedAt.Format("2006-01-02"), c.Impetus)
+	}
+	cost := func(c RAGCitation) int { return tokens.Estimate(header(c)+content[c.ID]) + 1 }
+
+	// Everything fits: both kafka beats, the re-capture once
+	all := ask(10000)
+	if all.Candidates != 2 || len(all.Citations) != 2 {
+		t.Fatalf("candidates %d, citations %+v; want 2 after dedupe", all.Candidates, all.Citations)
+	}
+	if all.Citations[0].ID != "beat-20260302-001" || all.Citations[1].ID != "beat-20260303-001" {
+		t.Errorf("citations = %+v, want the newer copy of the re-captured note, then the lag note", all.Citations)
+	}
+	first, second := all.Citations[0], all.Citations[1]
+	if all.TokensUsed != cost(first)+cost(second) {
+		t.Errorf("tokens_used = %d, want %d", all.TokensUsed, cost(first)+cost(second))
+	}
+
+	tests := []struct {
+		name      string
+		budget    int
+		ids       []string
+		truncated string // ID of the beat cut short, if any
+	}{
+		{"exact fit", cost(first) + cost(second), []string{first.ID, second.ID}, ""},
+		{"second cut short", cost(first) + tokens.Estimate(header(second)) + 1 + minSnippetTokens + 5, []string{first.ID, second.ID}, second.ID},
+		{"no room for a snippet", cost(first) + tokens.Estimate(header(second)) + minSnippetTokens - 1, []string{first.ID}, ""},
+		{"first cut short", tokens.Estimate(header(first)) + 1 + minSnippetTokens + 10, []string{first.ID}, first.ID},
+		{"nothing fits", minSnippetTokens, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ask(tt.budget)
+			var ids []string
+			for _, c := range got.Citations {
+				ids = append(ids, c.ID)
+				if c.Truncated != (c.ID == tt.truncated) {
+					t.Errorf("%s truncated = %v", c.ID, c.Truncated)
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("citations = %v, want %v", ids, tt.ids)
+			}
+			if got.TokensUsed > tt.budget {
+				t.Errorf("tokens_used = %d, over the budget of %d", got.TokensUsed, tt.budget)
+			}
+			// About four bytes a token
+			if len(got.Context) > 4*got.TokensUsed {
+				t.Errorf("tokens_used = %d for a context of %d bytes", got.TokensUsed, len(got.Context))
+			}
+			if tt.truncated != "" && !strings.HasSuffix(got.Context, "...") {
+				t.Errorf("context does not end in the cut: %q", got.Context)
+			}
+		})
+	}
+}
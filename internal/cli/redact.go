@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// providerRedactor returns the redactor for output sent to provider (the
+// config's default_provider when empty), or nil for full content. Entity
+// names come from beats, or from the whole store when beats is nil.
+func providerRedactor(s *store.JSONLStore, provider string, beats []beat.Beat) (*redact.Redactor, *redact.Info, error) {
+	if p, err := config.Get().RedactionFor(provider); err != nil || p == nil {
+		return nil, nil, err
+	}
+	if beats == nil {
+		var err error
+		if beats, err = s.ReadAll(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return redact.ForOutput(provider, beats)
+}
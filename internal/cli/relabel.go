@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/pipeline"
+)
+
+// Impetus meta keys recording a relabel, so the original label is never lost.
+const (
+	RelabeledFromKey  = "relabeled_from"  // The label before
+	RelabeledAtKey    = "relabeled_at"    // RFC 3339
+	RelabeledModelKey = "relabeled_model" // The LLM that chose the label
+)
+
+// DefaultRelabelBatch is how many beats go to the LLM in one prompt.
+const DefaultRelabelBatch = 20
+
+// genericImpetusLabels are labels that say how a beat arrived rather than
+// what prompted it. Beats with these, or with an inferred label of zero
+// confidence, are what bt relabel picks by default.
+var genericImpetusLabels = []string{pipeline.DefaultImpetus, "Extracted from raw input", "Raw input", "Untitled", "Note"}
+
+// RelabelOptions configures bt relabel.
+type RelabelOptions struct {
+	LLM    bool   // Have the LLM choose labels (without it, only list the beats)
+	Filter string // impetus:"Label" selects beats with that label instead of the generic ones
+	Limit  int    // At most this many beats (0 for all)
+	Batch  int    // Beats per prompt (default DefaultRelabelBatch)
+	DryRun bool   // Show the new labels without applying them
+	JSON   bool
+}
+
+// Relabel is one beat's label change.
+type Relabel struct {
+	ID    string `json:"id"`
+	From  string `json:"from"`
+	To    string `json:"to,omitempty"`
+	Error string `json:"error,omitempty"` // Why the beat kept its label
+}
+
+// RelabelResult is the outcome of a relabel run.
+type RelabelResult struct {
+	Candidates int       `json:"candidates"`
+	Relabeled  int       `json:"relabeled"`
+	DryRun     bool      `json:"dry_run,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	Beats      []Relabel `json:"beats"`
+}
+
+// parseRelabelFilter reads a filter such as impetus:"Extracted from raw
+// input" and returns the label it selects.
+func parseRelabelFilter(filter string) (string, error) {
+	value, ok := strings.CutPrefix(strings.TrimSpace(filter), "impetus:")
+	if !ok {
+		return "", fmt.Errorf("invalid filter %q (use impetus:\"Label\")", filter)
+	}
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if value == "" {
+		return "", fmt.Errorf("invalid filter %q: no label", filter)
+	}
+	return value, nil
+}
+
+// genericImpetus reports whether a beat's label says nothing about it.
+func genericImpetus(imp beat.Impetus) bool {
+	if strings.TrimSpace(imp.Label) == "" || imp.Meta[pipeline.ImpetusConfidenceKey] == "0" {
+		return true
+	}
+	for _, l := range genericImpetusLabels {
+		if strings.EqualFold(imp.Label, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// relabelPrompt asks for a label per beat, as JSON keyed by beat ID.
+func relabelPrompt(beats []beat.Beat) string {
+	var sb strings.Builder
+	sb.WriteString("Each note below needs an impetus label: 3 to 7 words naming what prompted it, such as \"Call with a customer about pricing\" or \"Reading on vector databases\". Describe the occasion, not the conclusion.\n\n")
+	sb.WriteString("Reply with a JSON object only, mapping each note ID to its label, no other text:\n{\"beat-20250101-001\": \"Label\"}\n\nNotes:\n")
+	for _, b := range beats {
+		fmt.Fprintf(&sb, "- [%s] %s\n", b.ID, truncate(b.Content, 600))
+	}
+	return sb.String()
+}
+
+// parseRelabelReply reads the model's labels, keeping those of 3 to 7 words.
+func parseRelabelReply(text string) (map[string]string, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON in the model's reply")
+	}
+	var reply map[string]string
+	if err := json.Unmarshal([]byte(text[start:end+1]), &reply); err != nil {
+		return nil, fmt.Errorf("invalid JSON in the model's reply: %w", err)
+	}
+	labels := make(map[string]string, len(reply))
+	for id, label := range reply {
+		label = strings.TrimRight(strings.Join(strings.Fields(strings.Trim(label, `"' `)), " "), ".")
+		if n := len(strings.Fields(label)); n >= 3 && n <= 7 {
+			labels[id] = label
+		}
+	}
+	return labels, nil
+}
+
+// Relabel gives beats with generic impetus labels specific ones chosen by
+// the LLM, in batches. Each relabeled beat keeps its old label, the time and
+// the model in its impetus meta.
+func (c *HumanCLI) Relabel(opts RelabelOptions) error {
+	match := genericImpetus
+	if opts.Filter != "" {
+		label, err := parseRelabelFilter(opts.Filter)
+		if err != nil {
+			return err
+		}
+		match = func(imp beat.Impetus) bool { return strings.EqualFold(imp.Label, label) }
+	}
+	batch := opts.Batch
+	if batch <= 0 {
+		batch = DefaultRelabelBatch
+	}
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	var candidates []beat.Beat
+	for _, b := range all {
+		kind := b.Impetus.Meta["kind"]
+		if kind == DigestKind || kind == WeeklyKind || !match(b.Impetus) {
+			continue
+		}
+		candidates = append(candidates, b)
+		if opts.Limit > 0 && len(candidates) == opts.Limit {
+			break
+		}
+	}
+
+	r := &RelabelResult{Candidates: len(candidates), DryRun: opts.DryRun || !opts.LLM, Beats: []Relabel{}}
+	if !opts.LLM {
+		for _, b := range candidates {
+			r.Beats = append(r.Beats, Relabel{ID: b.ID, From: b.Impetus.Label})
+		}
+		return r.print(opts.JSON, "Run with --llm to have the LLM choose labels.")
+	}
+
+	now := clock.Now().UTC()
+	for start := 0; start < len(candidates); start += batch {
+		chunk := candidates[start:min(start+batch, len(candidates))]
+		text, model, err := llmGenerate(context.Background(), c.store.Dir(), relabelPrompt(chunk))
+		if err != nil {
+			return fmt.Errorf("relabel needs the LLM (llm_model %s): %w", model, err)
+		}
+		r.Model = model
+		labels, err := parseRelabelReply(text)
+		if err != nil {
+			return err
+		}
+		for _, b := range chunk {
+			rl := Relabel{ID: b.ID, From: b.Impetus.Label, To: labels[b.ID]}
+			switch {
+			case rl.To == "":
+				rl.Error = "no usable label in the reply"
+			case !opts.DryRun:
+				if _, err := c.store.Update(b.ID, func(u *beat.Beat) error {
+					applyRelabel(u, rl.To, model, now)
+					return nil
+				}); err != nil {
+					rl.Error = err.Error()
+				} else {
+					r.Relabeled++
+				}
+			}
+			r.Beats = append(r.Beats, rl)
+		}
+	}
+	return r.print(opts.JSON, "")
+}
+
+// applyRelabel sets a beat's label, recording the one it replaces. A beat
+// relabeled twice keeps its first label.
+func applyRelabel(b *beat.Beat, label, model string, at time.Time) {
+	if b.Impetus.Meta == nil {
+		b.Impetus.Meta = make(map[string]string)
+	}
+	if b.Impetus.Meta[RelabeledFromKey] == "" {
+		b.Impetus.Meta[RelabeledFromKey] = b.Impetus.Label
+	}
+	b.Impetus.Meta[RelabeledAtKey] = at.Format(time.RFC3339)
+	b.Impetus.Meta[RelabeledModelKey] = model
+	delete(b.Impetus.Meta, pipeline.ImpetusConfidenceKey)
+	b.Impetus.Label = label
+}
+
+// print reports a relabel run.
+func (r *RelabelResult) print(jsonOut bool, hint string) error {
+	if jsonOut {
+		return outputJSON(r)
+	}
+	if r.Candidates == 0 {
+		fmt.Println("No beats to relabel.")
+		return nil
+	}
+	for _, rl := range r.Beats {
+		switch {
+		case rl.Error != "":
+			fmt.Printf("  %s  %q: skipped, %s\n", rl.ID, rl.From, rl.Error)
+		case rl.To != "":
+			fmt.Printf("  %s  %q -> %q\n", rl.ID, rl.From, rl.To)
+		default:
+			fmt.Printf("  %s  %q\n", rl.ID, rl.From)
+		}
+	}
+	switch {
+	case hint != "":
+		fmt.Printf("\n%d beat(s) to relabel. %s\n", r.Candidates, hint)
+	case r.DryRun:
+		fmt.Printf("\n[dry-run] Would relabel %d of %d beat(s)\n", r.countProposed(), r.Candidates)
+	default:
+		fmt.Printf("\nRelabeled %d of %d beat(s) with %s\n", r.Relabeled, r.Candidates, r.Model)
+	}
+	return nil
+}
+
+// countProposed counts the beats given a new label.
+func (r *RelabelResult) countProposed() int {
+	n := 0
+	for _, rl := range r.Beats {
+		if rl.To != "" && rl.Error == "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestRelabel(t *testing.T) {
+	defer func(f func(context.Context, string, string) (string, string, error)) { llmGenerate = f }(llmGenerate)
+	var prompts []string
+	llmGenerate = func(_ context.Context, _, prompt string) (string, string, error) {
+		prompts = append(prompts, prompt)
+		return "Sure:\n{\"beat-20260301-001\": \"Call with Acme about pricing.\", \"beat-20260301-002\": \"Too short\"}", "test-model", nil
+	}
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, b := range []beat.Beat{
+		{ID: "beat-20260301-001", Content: "Acme wants seat pricing", CreatedAt: now,
+			Impetus: beat.Impetus{Label: "Manual entry", Meta: map[string]string{pipeline.ImpetusConfidenceKey: "0"}}},
+		{ID: "beat-20260301-002", Content: "Something", CreatedAt: now, Impetus: beat.Impetus{Label: "Extracted from raw input"}},
+		{ID: "beat-20260301-003", Content: "Reading", CreatedAt: now, Impetus: beat.Impetus{Label: "Web discovery"}},
+	} {
+		if err := s.Append(&b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewHumanCLI(s)
+
+	if err := c.Relabel(RelabelOptions{LLM: true, Batch: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[0], "beat-20260301-001") || strings.Contains(prompts[0], "beat-20260301-002") {
+		t.Errorf("want one prompt per beat of the batch, got %q", prompts)
+	}
+	b, _ := s.Get("beat-20260301-001")
+	meta := b.Impetus.Meta
+	if b.Impetus.Label != "Call with Acme about pricing" || meta[RelabeledFromKey] != "Manual entry" || meta[RelabeledModelKey] != "test-model" || meta[RelabeledAtKey] == "" {
+		t.Errorf("relabeled beat = %q %v", b.Impetus.Label, meta)
+	}
+	if _, ok := meta[pipeline.ImpetusConfidenceKey]; ok {
+		t.Error("inference confidence kept after relabel")
+	}
+	if b, _ := s.Get("beat-20260301-002"); b.Impetus.Label != "Extracted from raw input" {
+		t.Errorf("label under 3 words applied: %q", b.Impetus.Label)
+	}
+
+	prompts = nil
+	if err := c.Relabel(RelabelOptions{LLM: true, DryRun: true, Filter: `impetus:"Web discovery"`}); err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "beat-20260301-003") || strings.Contains(prompts[0], "beat-20260301-002") {
+		t.Errorf("filter prompt = %q", prompts)
+	}
+	if b, _ := s.Get("beat-20260301-003"); b.Impetus.Label != "Web discovery" {
+		t.Errorf("dry run changed the label to %q", b.Impetus.Label)
+	}
+	if _, err := parseRelabelFilter("label:x"); err == nil {
+		t.Error("filter without impetus: accepted")
+	}
+}
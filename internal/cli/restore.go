@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// BackupCreate snapshots the store.
+func (c *HumanCLI) BackupCreate(jsonOut bool) error {
+	b, err := c.store.Backup(store.BackupManual)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(b)
+	}
+	fmt.Printf("Backed up %d beats to %s/%s\n", b.Beats, store.BackupsDir, b.File)
+	return nil
+}
+
+// BackupList lists the store's backups, oldest first.
+func (c *HumanCLI) BackupList(jsonOut bool) error {
+	backups, err := c.store.Backups()
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		if backups == nil {
+			backups = []store.Backup{}
+		}
+		return outputJSON(backups)
+	}
+	if len(backups) == 0 {
+		fmt.Println("No backups yet; the first write to the store takes one.")
+		return nil
+	}
+	for _, b := range backups {
+		fmt.Printf("  %s  %-11s %5d beats  %s\n", displayTime(b.TakenAt), b.Reason, b.Beats, b.File)
+	}
+	return nil
+}
+
+// BackupVerify checks every backup against its recorded checksum and count.
+func (c *HumanCLI) BackupVerify(jsonOut bool) error {
+	checks, err := c.store.VerifyBackups()
+	if err != nil {
+		return err
+	}
+	bad := 0
+	for _, ch := range checks {
+		if !ch.OK {
+			bad++
+		}
+	}
+	if jsonOut {
+		if checks == nil {
+			checks = []store.BackupCheck{}
+		}
+		if err := outputJSON(checks); err != nil {
+			return err
+		}
+	} else {
+		for _, ch := range checks {
+			status := "ok"
+			if !ch.OK {
+				status = "!! " + ch.Problem
+			}
+			fmt.Printf("  %s  %s\n", ch.File, status)
+		}
+		fmt.Printf("%d backup(s), %d damaged\n", len(checks), bad)
+	}
+	if bad > 0 {
+		return fmt.Errorf("%d damaged backup(s)", bad)
+	}
+	return nil
+}
+
+// RestoreOptions configures a point-in-time restore.
+type RestoreOptions struct {
+	At     time.Time
+	DryRun bool // Verify and report without swapping files
+	Force  bool // Skip the confirmation prompt
+	JSON   bool
+}
+
+// Restore rebuilds the store as it was at opts.At from the backups and the
+// journal, verifies it and swaps it into place.
+func (c *HumanCLI) Restore(opts RestoreOptions) error {
+	plan, err := c.store.PlanRestore(opts.At)
+	if err != nil {
+		return err
+	}
+
+	if !opts.JSON {
+		fmt.Printf("Restoring %s as of %s\n", c.store.Path(), displayStamp(opts.At))
+		fmt.Printf("  from backup %s (%s, %s)\n", plan.Base.File, plan.Base.Reason, displayStamp(plan.Base.TakenAt))
+		for _, check := range plan.Checks {
+			fmt.Printf("  ok  %s\n", check)
+		}
+		fmt.Printf("  %d beats now, %d after: %d brought back, %d dropped, %d rolled back\n",
+			plan.Current, plan.Beats, plan.Added, plan.Removed, plan.Changed)
+	}
+	if opts.DryRun {
+		if opts.JSON {
+			return outputJSON(plan)
+		}
+		fmt.Println("[dry-run] Nothing changed")
+		return nil
+	}
+
+	if !opts.Force && !opts.JSON {
+		fmt.Print("\nReplace the store? The current one is backed up first. [y/N] ")
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Canceled.")
+			return nil
+		}
+	}
+	if err := c.store.ApplyRestore(plan); err != nil {
+		return err
+	}
+	if opts.JSON {
+		return outputJSON(plan)
+	}
+	fmt.Printf("Restored %d beats (sha256 %s)\n", plan.Beats, plan.SHA256[:12])
+	return nil
+}
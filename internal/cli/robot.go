@@ -1,25 +1,55 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/bierlingm/beats/internal/beads"
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/brief"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/embeddings"
 	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/secrets"
 	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/tokens"
 )
 
 // RobotCLI handles robot-facing CLI commands (JSON in/out).
 type RobotCLI struct {
 	store *store.JSONLStore
+
+	ctx    context.Context // Bounds slow work; see Input
+	cancel context.CancelFunc
+}
+
+// asOf returns a RobotCLI reading the store as it was at the given moment
+// (RFC 3339 or anything clock.ParseRelative takes).
+func (c *RobotCLI) asOf(at string) (*RobotCLI, error) {
+	t, err := clock.ParseRelative(at)
+	if err != nil {
+		return nil, err
+	}
+	view, err := c.store.AsOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return &RobotCLI{store: view, ctx: c.ctx}, nil
 }
 
 // NewRobotCLI creates a new RobotCLI.
 func NewRobotCLI(s *store.JSONLStore) *RobotCLI {
-	return &RobotCLI{store: s}
+	return &RobotCLI{store: s, ctx: context.Background()}
 }
 
 // Help outputs JSON describing all robot commands.
@@ -38,16 +68,18 @@ func (c *RobotCLI) Help() error {
 				"description": "Propose a structured beat from raw text (AI extracts entities, references, etc.)",
 				"input": map[string]interface{}{
 					"raw_text":     "string (required) - raw text to extract beat from",
-					"impetus_hint": "string (optional) - short phrase about why recording this",
+					"impetus_hint": "string (optional) - short phrase about why recording this (default: inferred from the text)",
 					"context": map[string]string{
 						"channel":      "coaching|web|journal|other",
 						"counterparty": "name of person involved",
 						"session_id":   "unique session identifier",
 					},
+					"provider": "string (optional) - LLM provider extraction_prompt is for; its redaction profile applies (default: default_provider)",
 				},
 				"output": map[string]interface{}{
 					"proposed_beat": "Beat object without id/timestamps",
 					"alternatives":  "array of alternative Beat proposals",
+					"redaction":     "object {provider, replaced} when a redaction profile was applied",
 				},
 			},
 			{
@@ -55,97 +87,239 @@ func (c *RobotCLI) Help() error {
 				"description": "Commit a proposed beat to storage, assigning ID and timestamps",
 				"input": map[string]interface{}{
 					"content":      "string (required) - the beat content",
-					"impetus":      "Impetus object (required)",
+					"impetus":      "Impetus object (optional) - label inferred from content when empty, with impetus_confidence in meta",
 					"references":   "array of Reference objects (optional)",
-					"entities":     "array of Entity objects (optional)",
+					"entities":     "array of Entity objects (optional) - entities found in the content are added (entity_extraction setting)",
 					"linked_beads": "array of bead IDs (optional)",
 					"created_at":   "RFC3339 timestamp (optional) - backdate the beat",
+					"context":      "{wald_directory} (optional) - file the beat under a WALD directory instead of inferring one",
+					"redact":       "bool (optional) - mask secrets and personal data found in content and impetus.raw",
+					"extract":      "string (optional) - entity extraction: heuristic, llm (adds typed entities and relations from the LLM) or off; default the entity_extraction setting",
+					"agent":        "string (optional) - who is committing; with the approval hook on, commits from agents not in approval.trusted are staged until bt approve (the name is taken on trust, not verified)",
+				},
+				"output": "Beat object with id, timestamps and context {capture_path, wald_directory, inference_method, confidence}, plus warnings [{kind, field, preview, offset, redacted}] from the secrets scan (an error with the warnings when the secrets hook action is block), possible_duplicates [{id, score, content}] and suggested_links [{bead_id, title, confidence}] when those hooks are enabled, with the applied thresholds; when the approval hook stages the commit or the quota hook holds an automated capture (impetus.meta.source set) for review, {status: pending, pending: {id, queued_at, source, reason (approval, quota or noise), details, agent, beat}} instead",
+			},
+			{
+				"name":        "--robot-pending",
+				"description": "Captures held for review by the quota hook or staged by the approval hook (bt review --pending to accept or drop them, bt approve for staged commits)",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"pending": "array of {id, queued_at, source, reason (quota, noise or approval), details, agent, beat}",
+				},
+			},
+			{
+				"name":        "--robot-suggest-links",
+				"description": "Suggest beads to link, ranked by embedding similarity against .beats/beads_cache.json",
+				"input": map[string]interface{}{
+					"beat_id":     "string (optional) - existing beat to suggest links for",
+					"content":     "string (optional) - free text, used when beat_id is absent",
+					"threshold":   "float (optional) - minimum similarity (default 0.75)",
+					"max_results": "int (optional, default 3)",
+				},
+				"output": map[string]interface{}{
+					"suggestions": "array of {bead_id, title, confidence}",
+					"threshold":   "float - applied threshold",
+					"beads_known": "int - beads in the cache",
+				},
+			},
+			{
+				"name":        "--robot-register-beads",
+				"description": "Store beads in .beats/beads_cache.json, the inventory mapping, link suggestions and lookups use when no provider lists them; beads already cached are updated by ID",
+				"input": map[string]interface{}{
+					"beads":   "array of {id, title, description, status} (required; a bare array is accepted too)",
+					"replace": "bool (optional) - drop cached beads not listed",
+				},
+				"output": map[string]interface{}{
+					"registered": "int",
+					"added":      "int - beads not cached before",
+					"updated":    "int",
+					"beads":      "int - beads now cached",
+					"open":       "int",
 				},
-				"output": "Beat object with id and timestamps",
 			},
 			{
 				"name":        "--robot-search",
 				"description": "Search beats by keyword or semantic query",
 				"input": map[string]interface{}{
-					"query":       "string (required) - search query",
-					"max_results": "int (optional, default 20)",
-					"semantic":    "bool (optional, default false) - use osgrep semantic search instead of keyword FTS5",
+					"query":               "string (required) - search query",
+					"max_results":         "int (optional, default 20)",
+					"semantic":            "bool (optional, default false) - use osgrep semantic search instead of keyword FTS5",
+					"scoring":             "object (optional) - override scoring.json fields for this call, e.g. {\"semantic_min_score\": 0.4}",
+					"wald":                "string (optional) - only beats filed under this WALD directory or its subdirectories",
+					"as_of":               "timestamp (optional) - search the store as it was then, rebuilt from backups and the journal (keyword only)",
+					"include_attachments": "bool (optional) - also search the extracted text of attachments (page text, PDF text, transcripts)",
+					"raw_fts":             "bool (optional) - treat query as FTS5 syntax (NEAR, OR, prefix*, column filters) and pass it through untouched; by default words are escaped",
+				},
+				"output": map[string]interface{}{
+					"results":            "array of {id, score, content, impetus}",
+					"attachment_matches": "array of {beat_id, locator, label, offset, snippet, score} - with include_attachments; offset is the byte offset of the first match in the attachment",
+					"mode":               "string - 'keyword', 'semantic', 'tfidf', or 'fts' (raw_fts)",
+					"fallback":           "bool - true if semantic was requested but no embedding provider was available (local TF-IDF used)",
+					"scoring":            "object - scoring settings applied to this search",
+				},
+			},
+			{
+				"name":        "--robot-person-brief",
+				"description": "Gather the beats about a person (person entity, counterparty meta or mention, aliases included) for meeting prep",
+				"input": map[string]interface{}{
+					"person":      "string (required) - the person's name or an alias",
+					"max_threads": "int (optional, default 10) - commitments and open threads to list",
 				},
 				"output": map[string]interface{}{
-					"results":  "array of {id, score, content, impetus}",
-					"mode":     "string - 'keyword' or 'semantic'",
-					"fallback": "bool - true if semantic was requested but fell back to keyword",
+					"person":            "string - canonical name",
+					"aliases":           "array of strings",
+					"beat_count":        "int",
+					"first_interaction": "{id, created_at, impetus, content, via} - via is entity, counterparty or mention",
+					"last_interaction":  "same shape, the most recent beat",
+					"days_since_last":   "int",
+					"commitments":       "array, newest first - beats with promises or deadlines",
+					"open_threads":      "array, newest first - beats with questions or loose ends",
+					"timeline":          "array, oldest first - every beat about the person",
 				},
 			},
 			{
 				"name":        "--robot-brief",
 				"description": "Generate a thematic brief from relevant beats",
 				"input": map[string]interface{}{
-					"topic":     "string (required) - topic to brief on",
-					"audience":  "string (LLM|human)",
-					"max_beats": "int (optional, default 30)",
+					"topic":      "string (required) - topic to brief on",
+					"audience":   "string (LLM|human)",
+					"max_beats":  "int (optional, default 30)",
+					"wald":       "string (optional) - only beats filed under this WALD directory",
+					"max_tokens": "int (optional) - prompt token budget, 0 for none (default: prompt_tokens setting, 6000)",
+					"template":   "string (optional) - name of a Go text/template in .beats/templates/briefs/<name>.tmpl to build brief_prompt with",
+					"sections":   "array of strings (optional) - sections the brief asks for, replacing the default six",
+					"as_of":      "timestamp (optional) - brief from the store as it was then: what an agent asking at that moment would have seen",
+					"provider":   "string (optional) - LLM provider the brief is for; its redaction profile applies to brief_prompt and beats_data (default: default_provider)",
 				},
 				"output": map[string]interface{}{
-					"beats_used": "array of beat IDs",
-					"outline":    "array of outline strings",
+					"beats_used":       "array of beat IDs",
+					"outline":          "array of outline strings",
+					"estimated_tokens": "int - estimated tokens of brief_prompt",
+					"token_budget":     "int - budget the prompt was fitted into (0: none)",
+					"beats_omitted":    "array of matching beat IDs left out to fit the budget",
+					"redaction":        "object {provider, replaced} when a redaction profile was applied",
 				},
 			},
 			{
 				"name":        "--robot-context-for-bead",
 				"description": "Get narrative context (beats) for a specific bead",
 				"input": map[string]interface{}{
-					"bead_id": "string (required) - the bead ID to get context for",
+					"bead_id":       "string (required) - the bead ID to get context for",
+					"expand":        "bool (optional) - also suggest semantically similar unlinked beats",
+					"title":         "string (optional) - bead title for expansion (default: from the bead provider)",
+					"description":   "string (optional) - bead description for expansion",
+					"max_suggested": "int (optional) - suggestions to return (default 5)",
+				},
+				"output": map[string]interface{}{
+					"bead_id":         "string",
+					"seed_beats":      "array of Beat objects (confirmed: linked to the bead)",
+					"suggested_beats": "array of Beat objects with context \"suggested\" and score (expand only)",
+					"expand_query":    "string - text searched for suggestions (expand only)",
+					"expand_mode":     "string - semantic or tfidf (expand only)",
+				},
+			},
+			{
+				"name":        "--robot-context",
+				"description": "Assemble a token-budgeted context block for a question, or WALD context for a path",
+				"input": map[string]interface{}{
+					"question":     "string (optional) - question to retrieve beats for",
+					"token_budget": "int (optional, default: 2000) - approximate token limit for the block",
+					"order":        "string (optional) - 'relevance' (default) or 'time'",
+					"scoring":      "object (optional) - override scoring.json fields for this call",
+					"path":         "string (optional) - WALD path, used when no question is given",
 				},
 				"output": map[string]interface{}{
-					"bead_id":    "string",
-					"seed_beats": "array of Beat objects",
+					"context":     "string - beats formatted as [id | date | impetus] blocks",
+					"citations":   "array of {id, score, created_at, impetus, truncated}",
+					"tokens_used": "int - estimated tokens in context",
+					"mode":        "string - retrieval mode: semantic or tfidf",
 				},
 			},
 			{
 				"name":        "--robot-map-beats-to-beads",
 				"description": "Suggest how beats might map to epics/beads",
 				"input": map[string]interface{}{
-					"beat_ids": "array of beat IDs to analyze",
+					"beat_ids":       "array of beat IDs to analyze",
+					"existing_beads": "array of {id, title, description} (default: open beads from the configured provider)",
+					"max_tokens":     "int (optional) - prompt token budget, 0 for none (default: prompt_tokens setting, 6000)",
 				},
 				"output": map[string]interface{}{
 					"proposed_new_epics":         "array of {title, seed_beats, confidence}",
 					"proposed_links_to_existing": "array of {bead_id, seed_beats, reason, confidence}",
+					"estimated_tokens":           "int - estimated tokens of mapping_prompt",
+					"beats_omitted":              "array of the oldest beat IDs left out to fit the budget; beads_omitted counts beads",
 				},
 			},
 			{
 				"name":        "--robot-diff",
 				"description": "Get changes since a given timestamp",
 				"input": map[string]interface{}{
-					"diff_since": "RFC3339 timestamp",
+					"diff_since":    "RFC3339 timestamp",
+					"include_beads": "bool (optional) - add bead_changes",
 				},
 				"output": map[string]interface{}{
 					"new_beats":             "array of new Beat objects",
 					"modified_beats":        "array of modified Beat objects",
 					"beats_linked_to_beads": "array of Beat objects with new links",
 					"deleted_ids":           "array of deleted beat IDs",
+					"bead_changes":          "array of {bead_id, gained, lost, linked_beats} with include_beads; gained/lost are {beat_id, relation, at, by}",
+					"pending_beats":         "array of {id, queued_at, source, reason (quota, noise or approval), details, agent, beat} - every capture waiting in the review queue",
 				},
 			},
 			{
 				"name":        "--robot-link-beat",
 				"description": "Link a beat to one or more beads (adds to existing links)",
 				"input": map[string]interface{}{
-					"beat_id":  "string (required) - the beat ID to update",
-					"bead_ids": "array of strings (required) - bead IDs to link",
+					"beat_id":             "string (required) - the beat ID to update",
+					"bead_ids":            "array of strings (required) - bead IDs to link",
+					"relation":            "string (optional) - seed (default), evidence, blocks or retrospective; changes the relation of existing links",
+					"created_by":          "string (optional) - who is linking (default robot)",
+					"expected_updated_at": "RFC3339 time (optional) - the updated_at you read; the write fails with code conflict if the beat changed since",
 				},
 				"output": "Beat object with updated linked_beads",
 			},
+			{
+				"name":        "--robot-annotate",
+				"description": "Add a note to a beat without changing its content",
+				"input": map[string]interface{}{
+					"beat_id":             "string (required) - the beat ID to annotate",
+					"note":                "string (required) - the note, up to 1000 bytes",
+					"author":              "string (optional) - who is annotating (default robot)",
+					"expected_updated_at": "RFC3339 time (optional) - the updated_at you read; the write fails with code conflict if the beat changed since",
+				},
+				"output": "Beat object with its annotations",
+			},
+			{
+				"name":        "--robot-synthesis-history",
+				"description": "List archived synthesis requests and their responses (newest first)",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"syntheses": "array of {id, request, response, responded_at}",
+					"count":     "int",
+				},
+			},
+			{
+				"name":        "--robot-synthesis-respond",
+				"description": "Record the response to a synthesis request and clear it if pending",
+				"input": map[string]interface{}{
+					"id":       "string (optional) - synthesis ID (default: pending request)",
+					"response": "string (required) - synthesis report",
+				},
+				"output": "SynthesisRecord object",
+			},
 			{
 				"name":        "--robot-edit",
 				"description": "Edit a beat by ID with JSON input",
 				"input": map[string]interface{}{
-					"id":        "string (required) - beat ID to edit",
-					"content":   "string (optional) - new content",
-					"impetus":   "Impetus object (optional) - new impetus",
-					"date":      "string (optional) - new date (YYYY-MM-DD or RFC3339)",
-					"add_refs":  "array of Reference objects (optional) - references to add",
-					"rm_refs":   "array of strings (optional) - locators to remove",
-					"add_beads": "array of strings (optional) - bead IDs to link",
-					"rm_beads":  "array of strings (optional) - bead IDs to unlink",
+					"id":                  "string (required) - beat ID to edit",
+					"content":             "string (optional) - new content",
+					"impetus":             "Impetus object (optional) - new impetus",
+					"date":                "string (optional) - new date (YYYY-MM-DD or RFC3339)",
+					"add_refs":            "array of Reference objects (optional) - references to add",
+					"rm_refs":             "array of strings (optional) - locators to remove",
+					"add_beads":           "array of strings (optional) - bead IDs to link",
+					"rm_beads":            "array of strings (optional) - bead IDs to unlink",
+					"expected_updated_at": "RFC3339 time (optional) - the updated_at you read; the write fails with code conflict if the beat changed since",
 				},
 				"output": "Beat object with updates applied",
 			},
@@ -153,51 +327,137 @@ func (c *RobotCLI) Help() error {
 				"name":        "--robot-amend",
 				"description": "Edit the most recent beat with JSON input",
 				"input": map[string]interface{}{
-					"content":   "string (optional) - new content",
-					"impetus":   "Impetus object (optional) - new impetus",
-					"date":      "string (optional) - new date (YYYY-MM-DD or RFC3339)",
-					"add_refs":  "array of Reference objects (optional) - references to add",
-					"rm_refs":   "array of strings (optional) - locators to remove",
-					"add_beads": "array of strings (optional) - bead IDs to link",
-					"rm_beads":  "array of strings (optional) - bead IDs to unlink",
+					"content":             "string (optional) - new content",
+					"impetus":             "Impetus object (optional) - new impetus",
+					"date":                "string (optional) - new date (YYYY-MM-DD or RFC3339)",
+					"add_refs":            "array of Reference objects (optional) - references to add",
+					"rm_refs":             "array of strings (optional) - locators to remove",
+					"add_beads":           "array of strings (optional) - bead IDs to link",
+					"rm_beads":            "array of strings (optional) - bead IDs to unlink",
+					"expected_updated_at": "RFC3339 time (optional) - the updated_at you read; the write fails with code conflict if the beat changed since",
 				},
 				"output": "Beat object with updates applied",
 			},
 			{
 				"name":        "--robot-import",
-				"description": "Bulk import beats with conflict resolution",
+				"description": "Bulk import beats with conflict resolution, or rebuild an empty store from a snapshot",
 				"input": map[string]interface{}{
-					"beats":       "array of Beat objects (required)",
+					"beats":       "array of Beat objects (required unless the input is a snapshot from --robot-export)",
 					"on_conflict": "string (optional) - error|skip|renumber (default: error)",
 					"source":      "string (optional) - source label for impetus.meta",
 				},
 				"output": map[string]interface{}{
-					"imported": "int - number of beats imported",
-					"skipped":  "int - number of beats skipped",
-					"errors":   "array of strings - error messages",
+					"imported":       "int - number of beats imported",
+					"skipped":        "int - number of beats skipped",
+					"errors":         "array of strings - error messages",
+					"schema_version": "int - set when a snapshot was imported",
 				},
 			},
 			{
 				"name":        "--robot-export",
-				"description": "Export beats with filters",
+				"description": "Export beats with filters, or the whole store as a snapshot",
 				"input": map[string]interface{}{
-					"format":  "string (optional) - json|jsonl (default: json)",
+					"format":  "string (optional) - json|jsonl|jsonld|snapshot|snapshot-ndjson (default: json)",
 					"since":   "string (optional) - filter created_at >= (YYYY-MM-DD or RFC3339)",
 					"until":   "string (optional) - filter created_at <= (YYYY-MM-DD or RFC3339)",
 					"impetus": "string (optional) - filter by impetus label substring",
 					"query":   "string (optional) - filter by content substring",
+					"base":    "string (optional) - IRI prefix for beat nodes with jsonld (default: urn:beats:)",
 				},
-				"output": "array of Beat objects (json) or JSONL lines",
+				"output": "array of Beat objects (json), JSONL lines, a schema.org JSON-LD document {@context, @graph} of CreativeWork/NoteDigitalDocument nodes (jsonld), a snapshot {schema_version, exported_at, beat_count, aliases, beats, entities [{label, category, beats}], links [{beat_id, bead_id, relation, created_at, created_by}]}, or the snapshot as NDJSON records {record: header|beat|entity|link, ...}",
 			},
 			{
 				"name":        "--robot-redate",
 				"description": "Change the creation date of a beat",
 				"input": map[string]interface{}{
-					"id":   "string (required) - beat ID",
-					"date": "string (required) - new date (YYYY-MM-DD or RFC3339)",
+					"id":                  "string (required) - beat ID",
+					"date":                "string (required) - new date (YYYY-MM-DD or RFC3339)",
+					"expected_updated_at": "RFC3339 time (optional) - the updated_at you read; the write fails with code conflict if the beat changed since",
 				},
 				"output": "Beat object with updated date",
 			},
+			{
+				"name":        "--robot-attention",
+				"description": "Topics active in the last 72h against the 72h before: embedding clusters, or entity mentions without embeddings",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"window":      "string - window length",
+					"mode":        "string - topics or entities",
+					"activations": "array of {topic, keywords, beats, previous, trend, beat_ids}",
+				},
+			},
+			{
+				"name":        "--robot-ripe",
+				"description": "Beats at least 14 days old, linked to no bead, ranked by signal (length, references, entities, revisits, coaching/insight impetus)",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"min_age_days": "int",
+					"candidates":   "int - unlinked beats old enough",
+					"beats":        "array of {id, preview, impetus, age_days, score, signals} (at most 10)",
+				},
+			},
+			{
+				"name":        "--robot-questions",
+				"description": "Open loops: beats of kind question, or asking a question, that no later beat references and no bead is linked to (oldest first)",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"open":      "int",
+					"questions": "array of {id, created_at, age_days, impetus, question, content, source (kind or detected)}",
+				},
+			},
+			{
+				"name":        "--robot-orientation",
+				"description": "Where attention is heading: emerging and fading topics, busiest WALD directories, ripe beats and pending synthesis",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"direction":         "string - one-sentence direction",
+					"summary":           "string - counts and context",
+					"recent_beats":      "int - beats in the last window",
+					"previous_beats":    "int - beats in the window before",
+					"emerging":          "array of topic names",
+					"fading":            "array of topic names",
+					"top_directories":   "array of WALD directories",
+					"ripe_count":        "int",
+					"pending_synthesis": "bool",
+				},
+			},
+			{
+				"name":        "--robot-stats",
+				"description": "Capture habit: counts, current and longest streak, and beats per day for the last 30 days (display time zone, digests excluded)",
+				"input":       nil,
+				"output": map[string]interface{}{
+					"total_beats":             "int",
+					"today":                   "int",
+					"last_7_days":             "int",
+					"current_streak":          "int - consecutive days with beats, up to today (or yesterday until today's first beat)",
+					"captured_today":          "bool",
+					"longest_streak":          "int",
+					"longest_streak_ended":    "string - YYYY-MM-DD",
+					"days_since_last_capture": "int",
+					"daily":                   "array of {day, beats}, oldest first",
+				},
+			},
+			{
+				"name":        "--robot-sample",
+				"description": "Random or stratified sample of beats, for building search and extraction evaluation sets",
+				"input": map[string]interface{}{
+					"size":     "int (optional, default 20)",
+					"stratify": "string (optional) - date (by month), impetus or kind; each stratum gets a proportional share and at least one beat when size allows",
+					"seed":     "int (optional) - fixes the sample; the same seed over the same beats returns the same sample",
+					"since":    "datetime (optional) - only beats created from then",
+					"until":    "datetime (optional) - only beats created before then",
+				},
+				"output": map[string]interface{}{
+					"seed":       "int - the seed used (random when none was given), to reproduce the sample",
+					"stratify":   "string",
+					"population": "int - beats sampled from",
+					"strata":     "array of {name, population, sampled} when stratified",
+					"beats":      "array of Beat, oldest first",
+				},
+			},
+		},
+		"common_input": map[string]string{
+			"timeout_ms": "int (optional) - on any command that reads input: stop store scans, embedding and LLM calls after this many milliseconds and return {error, details, code: \"timeout\", timeout_ms}",
 		},
 		"schemas": map[string]interface{}{
 			"Beat": map[string]string{
@@ -208,7 +468,13 @@ func (c *RobotCLI) Help() error {
 				"content":      "string",
 				"references":   "array of Reference",
 				"entities":     "array of Entity",
-				"linked_beads": "array of bead IDs",
+				"linked_beads": "array of BeadLink",
+			},
+			"BeadLink": map[string]string{
+				"bead_id":    "string",
+				"relation":   "string - seed, evidence, blocks or retrospective",
+				"created_at": "RFC3339 timestamp (absent on links made before relations existed)",
+				"created_by": "string - user or agent that made the link",
 			},
 			"Impetus": map[string]string{
 				"label": "string - human-readable label",
@@ -242,6 +508,7 @@ type ProposeBeatInput struct {
 		Counterparty string `json:"counterparty,omitempty"`
 		SessionID    string `json:"session_id,omitempty"`
 	} `json:"context,omitempty"`
+	Provider string `json:"provider,omitempty"` // LLM provider the extraction prompt is for
 }
 
 // ProposeBeatOutput is the output for --robot-propose-beat.
@@ -250,6 +517,7 @@ type ProposeBeatOutput struct {
 	ExtractedURLs    []string            `json:"extracted_urls"`
 	ExtractionPrompt string              `json:"extraction_prompt"`
 	Alternatives     []beat.ProposedBeat `json:"alternatives"`
+	Redaction        *redact.Info        `json:"redaction,omitempty"`
 }
 
 // ProposeBeat proposes a structured beat from raw text.
@@ -264,11 +532,6 @@ func (c *RobotCLI) ProposeBeat(input io.Reader) error {
 		return outputError("raw_text is required", nil)
 	}
 
-	impetusLabel := in.ImpetusHint
-	if impetusLabel == "" {
-		impetusLabel = "Extracted from raw input"
-	}
-
 	meta := make(map[string]string)
 	if in.Context.Channel != "" {
 		meta["channel"] = in.Context.Channel
@@ -280,31 +543,26 @@ func (c *RobotCLI) ProposeBeat(input io.Reader) error {
 		meta["session_id"] = in.Context.SessionID
 	}
 
-	// Extract URLs from raw text
-	urls := extractURLs(in.RawText)
-
-	// Build references from extracted URLs
-	var refs []beat.Reference
-	for _, url := range urls {
-		refs = append(refs, beat.Reference{
-			Kind:    "url",
-			Subtype: classifyURL(url),
-			Locator: url,
-			Label:   "",
-		})
-	}
-
 	proposed := beat.ProposedBeat{
-		Content: in.RawText,
-		Impetus: beat.Impetus{
-			Label: impetusLabel,
-			Raw:   truncate(in.RawText, 100),
-			Meta:  meta,
-		},
-		References:  refs,
+		Content:     in.RawText,
+		Impetus:     beat.Impetus{Label: in.ImpetusHint, Raw: truncate(in.RawText, 100), Meta: meta},
 		Entities:    []beat.Entity{},
 		LinkedBeads: []string{},
 	}
+	// The stages that need no store writes, as a commit would run them
+	warnings, err := pipeline.Prepare(c.ctx, c.store, &proposed, []string{pipeline.StageNormalize, pipeline.StageImpetus, pipeline.StageURLs})
+	if err != nil {
+		return outputError("failed to prepare proposal", err)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	urls := []string{}
+	for _, ref := range proposed.References {
+		if ref.Kind == "url" {
+			urls = append(urls, ref.Locator)
+		}
+	}
 
 	prompt := fmt.Sprintf(`Extract structured information from this beat:
 
@@ -331,83 +589,231 @@ Return a JSON object matching the ProposedBeat schema.`,
 		len(urls),
 	)
 
+	// Only the prompt goes to the provider; the proposal stays whole
+	r, redaction, err := providerRedactor(c.store, in.Provider, nil)
+	if err != nil {
+		return outputError("failed to load redaction profile", err)
+	}
+	if r != nil {
+		prompt = r.Text(prompt)
+		redaction.Replaced = r.Replaced
+	}
+
 	output := ProposeBeatOutput{
 		ProposedBeat:     proposed,
 		ExtractedURLs:    urls,
 		ExtractionPrompt: prompt,
 		Alternatives:     []beat.ProposedBeat{},
+		Redaction:        redaction,
 	}
 
 	return outputJSON(output)
 }
 
-// extractURLs finds URLs in text using a simple pattern.
-func extractURLs(text string) []string {
-	var urls []string
-	words := strings.Fields(text)
-	for _, word := range words {
-		// Clean punctuation from end
-		word = strings.TrimRight(word, ".,;:!?)")
-		if strings.HasPrefix(word, "http://") || strings.HasPrefix(word, "https://") {
-			urls = append(urls, word)
+// CommitBeat commits a proposed beat to storage.
+func (c *RobotCLI) CommitBeat(input io.Reader) error {
+	var in CommitInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
+		return outputError("invalid input JSON", err)
+	}
+
+	if in.Content == "" {
+		return outputError("content is required", nil)
+	}
+	if _, err := pipeline.ExtractionMode(in.Extract); err != nil {
+		return outputError("invalid extract", err)
+	}
+	in.SetChannel(beat.ChannelRobot) // Unless the caller names its own, e.g. "telegram"
+
+	pending, err := stageCommit(c.store, &in)
+	if err != nil {
+		return outputError("failed to stage beat for approval", err)
+	}
+	var b *beat.Beat
+	var run *pipeline.Run
+	if pending == nil {
+		b, run, err = pipeline.Capture(c.ctx, c.store, &in.ProposedBeat, pipeline.Options{Redact: in.Redact, Extract: in.Extract, Location: DisplayLocation})
+	}
+	var held *pipeline.PendingError
+	if errors.As(err, &held) {
+		pending, err = held.Pending, nil
+	}
+	if pending != nil {
+		return outputJSON(map[string]interface{}{
+			"status":  "pending",
+			"pending": pending,
+		})
+	}
+	if run != nil {
+		for _, w := range run.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
 		}
 	}
-	return urls
-}
 
-// classifyURL returns a subtype based on the URL domain.
-func classifyURL(url string) string {
+	var blocked *pipeline.SecretsError
+	var dup *pipeline.DuplicateError
+	var rejected *hooks.PreCommitError
+	var failed *pipeline.Error
 	switch {
-	case strings.Contains(url, "github.com"):
-		return "github"
-	case strings.Contains(url, "youtube.com") || strings.Contains(url, "youtu.be"):
-		return "youtube"
-	case strings.Contains(url, "twitter.com") || strings.Contains(url, "x.com"):
-		return "twitter"
-	case strings.Contains(url, ".pdf"):
-		return "pdf"
-	default:
-		return "web"
+	case errors.As(err, &blocked):
+		return outputJSON(map[string]interface{}{
+			"error":    "possible secrets",
+			"details":  blocked.Error(),
+			"warnings": blocked.Findings,
+		})
+	case errors.As(err, &dup):
+		return outputJSON(map[string]interface{}{
+			"error":               "possible duplicate",
+			"details":             dup.Error(),
+			"possible_duplicates": dup.Check.Duplicates,
+			"thresholds":          dup.Check.Thresholds,
+		})
+	case errors.As(err, &rejected):
+		return outputError("pre-commit hook rejected beat", errors.New(rejected.Reason))
+	case errors.As(err, &failed):
+		return outputError(failed.Msg, failed.Err)
+	case err != nil:
+		return outputError("capture failed", err)
+	}
+
+	out := CommitOutput{Beat: b, Warnings: run.Findings}
+	if out.Warnings == nil {
+		out.Warnings = []secrets.Finding{}
 	}
+	if check := run.Check; check != nil {
+		out.PossibleDuplicates = check.Duplicates
+		out.SuggestedLinks = check.SuggestedLinks
+		out.Thresholds = check.Thresholds
+	}
+	return outputJSON(out)
 }
 
-// CommitBeat commits a proposed beat to storage.
-func (c *RobotCLI) CommitBeat(input io.Reader) error {
-	var proposed beat.ProposedBeat
-	if err := json.NewDecoder(input).Decode(&proposed); err != nil {
+// CommitInput is the input for --robot-commit-beat: a proposed beat,
+// whether to mask the secrets found in it, how to extract entities and
+// which agent is committing.
+type CommitInput struct {
+	beat.ProposedBeat
+	Redact  bool   `json:"redact,omitempty"`
+	Extract string `json:"extract,omitempty"` // heuristic, llm or off (default: entity_extraction setting)
+	Agent   string `json:"agent,omitempty"`   // Checked against approval.trusted in hooks.json; self-declared, so not proof of identity
+}
+
+// CommitOutput is the output for --robot-commit-beat: the stored beat plus
+// what the secrets scan found (secrets hook), any similar beats (duplicates
+// hook) and bead suggestions (link_suggestions hook).
+type CommitOutput struct {
+	*beat.Beat
+	Warnings           []secrets.Finding      `json:"warnings"`
+	PossibleDuplicates []embeddings.Duplicate `json:"possible_duplicates,omitempty"`
+	SuggestedLinks     []beads.Suggestion     `json:"suggested_links,omitempty"`
+	Thresholds         map[string]float64     `json:"thresholds,omitempty"`
+}
+
+// SuggestLinksInput is the input for --robot-suggest-links.
+type SuggestLinksInput struct {
+	BeatID     string  `json:"beat_id,omitempty"`
+	Content    string  `json:"content,omitempty"`
+	Threshold  float64 `json:"threshold,omitempty"`
+	MaxResults int     `json:"max_results,omitempty"`
+}
+
+// SuggestLinksOutput is the output for --robot-suggest-links.
+type SuggestLinksOutput struct {
+	BeatID      string             `json:"beat_id,omitempty"`
+	Suggestions []beads.Suggestion `json:"suggestions"`
+	Threshold   float64            `json:"threshold"`
+	BeadsKnown  int                `json:"beads_known"`
+}
+
+// SuggestLinks proposes beads a beat (or free text) should link to, ranked by
+// embedding similarity against the local bead cache.
+func (c *RobotCLI) SuggestLinks(input io.Reader) error {
+	var in SuggestLinksInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
 		return outputError("invalid input JSON", err)
 	}
 
-	if proposed.Content == "" {
-		return outputError("content is required", nil)
+	text := in.Content
+	var exclude []string
+	if in.BeatID != "" {
+		b, err := c.store.Get(in.BeatID)
+		if err != nil {
+			return outputError("beat not found", err)
+		}
+		in.BeatID = b.ID
+		text = embeddings.BeatText(*b)
+		exclude = b.LinkedBeads.IDs()
+	}
+	if text == "" {
+		return outputError("beat_id or content is required", nil)
 	}
 
-	seq, err := c.store.NextSequence()
-	if err != nil {
-		return outputError("failed to get sequence", err)
+	config := hooks.GetLinkSuggestionsConfig(c.store.Dir())
+	if in.Threshold > 0 {
+		config.Threshold = in.Threshold
+	}
+	if in.MaxResults > 0 {
+		config.MaxResults = in.MaxResults
 	}
 
-	b := proposed.ToBeat(seq)
+	list, _, _ := beads.CachedInventory(c.store.Dir()) // Refills an empty or stale cache
+	out := SuggestLinksOutput{BeatID: in.BeatID, Suggestions: []beads.Suggestion{}, Threshold: config.Threshold, BeadsKnown: len(list)}
+	if len(list) == 0 {
+		return outputJSON(out)
+	}
 
-	if err := c.store.Append(b); err != nil {
-		return outputError("failed to save beat", err)
+	embStore, err := embeddings.NewStore(c.store.Dir())
+	if err != nil {
+		return outputError("failed to init embedding store", err)
+	}
+	ollama := embeddings.NewOllamaClient()
+	ollama.SetModel(embStore.Model())
+	if !ollama.IsAvailable() {
+		return outputError("ollama not available", nil)
 	}
 
-	return outputJSON(b)
+	ctx := c.ctx
+	var emb []float64
+	if in.BeatID != "" {
+		emb, _ = embStore.Get(in.BeatID)
+	}
+	if emb == nil {
+		if emb, err = ollama.GetEmbedding(ctx, text); err != nil {
+			return outputError("failed to embed beat", err)
+		}
+	}
+
+	vectors, err := beads.Embeddings(ctx, c.store.Dir(), list, ollama)
+	if err != nil {
+		return outputError("failed to embed beads", err)
+	}
+	if s := beads.Suggest(emb, list, vectors, config.Threshold, config.MaxResults, exclude); s != nil {
+		out.Suggestions = s
+	}
+	return outputJSON(out)
 }
 
 // SearchInput is the input for --robot-search.
 type SearchInput struct {
-	Query      string `json:"query"`
-	MaxResults int    `json:"max_results,omitempty"`
-	Semantic   bool   `json:"semantic,omitempty"`
+	Query      string          `json:"query"`
+	MaxResults int             `json:"max_results,omitempty"`
+	Semantic   bool            `json:"semantic,omitempty"`
+	Scoring    json.RawMessage `json:"scoring,omitempty"` // Per-call scoring.json overrides
+	Wald       string          `json:"wald,omitempty"`    // Only beats filed under this WALD directory
+	AsOf       string          `json:"as_of,omitempty"`   // Search the store as it was then
+
+	IncludeAttachments bool `json:"include_attachments,omitempty"` // Also search attachment text
+	RawFTS             bool `json:"raw_fts,omitempty"`             // Query is FTS5 syntax, passed through untouched
 }
 
 // SearchOutput is the output for --robot-search.
 type SearchOutput struct {
-	Results  []beat.SearchResult `json:"results"`
-	Mode     string              `json:"mode,omitempty"`
-	Fallback bool                `json:"fallback,omitempty"`
+	Results  []beat.SearchResult  `json:"results"`
+	Mode     string               `json:"mode,omitempty"`
+	Fallback bool                 `json:"fallback,omitempty"`
+	Scoring  *store.ScoringConfig `json:"scoring,omitempty"`
+
+	Attachments []store.AttachmentMatch `json:"attachment_matches,omitempty"`
 }
 
 // Search performs a search and returns JSON results.
@@ -421,44 +827,99 @@ func (c *RobotCLI) Search(input io.Reader) error {
 	if in.Query == "" {
 		return outputError("query is required", nil)
 	}
+	if in.AsOf != "" {
+		if in.Semantic || in.IncludeAttachments || in.RawFTS {
+			return outputError("as_of cannot be combined with semantic, include_attachments or raw_fts", nil)
+		}
+		view, err := c.asOf(in.AsOf)
+		if err != nil {
+			return outputError("failed to rebuild the store", err)
+		}
+		c = view
+	}
 
 	maxResults := in.MaxResults
 	if maxResults <= 0 {
 		maxResults = 20
 	}
 
-	output, err := store.HybridSearch(c.store, in.Query, maxResults, in.Semantic)
+	scoring, err := c.scoring(in.Scoring)
 	if err != nil {
-		return outputError("search failed", err)
+		return outputError("invalid scoring", err)
 	}
 
-	return outputJSON(SearchOutput{
+	limit := maxResults
+	if in.Wald != "" {
+		limit = math.MaxInt // Rank everything, then keep the directory's beats
+	}
+	var output *store.SemanticSearchOutput
+	switch {
+	case in.RawFTS && in.Semantic:
+		return outputError("raw_fts cannot be combined with semantic", nil)
+	case in.RawFTS:
+		results, err := searchFTS(c.store, in.Query, limit, "", "", true)
+		if err != nil {
+			return outputError("search failed", err)
+		}
+		output = &store.SemanticSearchOutput{Results: results, Mode: "fts"}
+	default:
+		if output, err = store.HybridSearchWithScoringContext(c.ctx, c.store, in.Query, limit, in.Semantic, scoring); err != nil {
+			return outputError("search failed", err)
+		}
+	}
+	if in.Wald != "" {
+		output.Results, err = c.resultsInWALD(output.Results, in.Wald, maxResults)
+		if err != nil {
+			return outputError("failed to read beats", err)
+		}
+	}
+
+	out := SearchOutput{
 		Results:  output.Results,
 		Mode:     output.Mode,
 		Fallback: output.Fallback,
-	})
+		Scoring:  &scoring,
+	}
+	if in.IncludeAttachments {
+		if out.Attachments, err = searchAttachments(c.store, in.Query, maxResults, "", in.Wald, in.RawFTS); err != nil {
+			return outputError("attachment search failed", err)
+		}
+	}
+	return outputJSON(out)
 }
 
-// BriefInput is the input for --robot-brief.
-type BriefInput struct {
-	Topic    string `json:"topic"`
-	Audience string `json:"audience,omitempty"`
-	MaxBeats int    `json:"max_beats,omitempty"`
+// resultsInWALD keeps the first max results filed under a WALD directory.
+func (c *RobotCLI) resultsInWALD(results []beat.SearchResult, dir string, max int) ([]beat.SearchResult, error) {
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	in := make(map[string]bool)
+	for _, b := range filterWALD(all, dir) {
+		in[b.ID] = true
+	}
+	kept := []beat.SearchResult{}
+	for _, r := range results {
+		if in[r.ID] && len(kept) < max {
+			kept = append(kept, r)
+		}
+	}
+	return kept, nil
 }
 
-// BriefOutput is the output for --robot-brief.
-type BriefOutput struct {
-	Topic       string      `json:"topic"`
-	Audience    string      `json:"audience"`
-	BeatsUsed   []string    `json:"beats_used"`
-	BeatsData   []beat.Beat `json:"beats_data"`
-	BriefPrompt string      `json:"brief_prompt"`
+// scoring loads scoring.json and applies a command's overrides.
+func (c *RobotCLI) scoring(override json.RawMessage) (store.ScoringConfig, error) {
+	cfg, err := store.LoadScoringConfig(c.store.Dir())
+	if err != nil {
+		return cfg, err
+	}
+	return cfg.Override(override)
 }
 
 // Brief generates a thematic brief from relevant beats.
 // Returns full beat data + synthesis prompt for LLM processing.
 func (c *RobotCLI) Brief(input io.Reader) error {
-	var in BriefInput
+	in := brief.Input{Location: DisplayLocation}
 	if err := json.NewDecoder(input).Decode(&in); err != nil {
 		return outputError("invalid input JSON", err)
 	}
@@ -466,84 +927,31 @@ func (c *RobotCLI) Brief(input io.Reader) error {
 	if in.Topic == "" {
 		return outputError("topic is required", nil)
 	}
-
-	audience := in.Audience
-	if audience == "" {
-		audience = "human"
-	}
-
-	maxBeats := in.MaxBeats
-	if maxBeats <= 0 {
-		maxBeats = 30
-	}
-
-	results, err := c.store.Search(in.Topic, maxBeats)
+	output, err := brief.Build(c.store, in)
 	if err != nil {
-		return outputError("search failed", err)
-	}
-
-	// Get full beat data
-	beatIDs := make([]string, len(results))
-	for i, r := range results {
-		beatIDs[i] = r.ID
-	}
-	beatsData, err := c.store.GetByIDs(beatIDs)
-	if err != nil {
-		return outputError("failed to get beats", err)
-	}
-
-	// Build beat summaries for prompt
-	var beatSummaries []string
-	for _, b := range beatsData {
-		summary := fmt.Sprintf("- [%s] (%s) %s", b.ID, b.Impetus.Label, truncate(b.Content, 200))
-		beatSummaries = append(beatSummaries, summary)
-	}
-
-	audienceGuidance := "Write for a human reader - clear, concise, actionable."
-	if audience == "LLM" {
-		audienceGuidance = "Write for an LLM agent - structured, machine-parseable, include metadata."
-	}
-
-	prompt := fmt.Sprintf(`Generate a thematic brief on: %s
-
-RELEVANT BEATS (%d found):
-%s
-
-AUDIENCE: %s
-%s
-
-BRIEF STRUCTURE:
-1. EXECUTIVE SUMMARY: 2-3 sentences capturing the core insight
-2. KEY THEMES: Major patterns or clusters in this material
-3. TIMELINE: How thinking evolved (if applicable)
-4. OPEN QUESTIONS: Unresolved items or areas needing exploration
-5. ACTION ITEMS: Concrete next steps that emerge from this material
-6. CONNECTIONS: Links to other topics, beads, or external resources
-
-Keep the brief focused and actionable. Cite beat IDs when referencing specific insights.`,
-		in.Topic,
-		len(beatsData),
-		strings.Join(beatSummaries, "\n"),
-		audience,
-		audienceGuidance,
-	)
-
-	output := BriefOutput{
-		Topic:       in.Topic,
-		Audience:    audience,
-		BeatsUsed:   beatIDs,
-		BeatsData:   beatsData,
-		BriefPrompt: prompt,
+		var failed *brief.Error
+		if errors.As(err, &failed) {
+			return outputError(failed.Msg, failed.Err)
+		}
+		return outputError("brief failed", err)
 	}
-
 	return outputJSON(output)
 }
 
 // ContextForBeadInput is the input for --robot-context-for-bead.
 type ContextForBeadInput struct {
 	BeadID string `json:"bead_id"`
+	// Expand adds semantically similar beats that are not linked yet,
+	// searched by the bead's title and description.
+	Expand       bool   `json:"expand,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Description  string `json:"description,omitempty"`
+	MaxSuggested int    `json:"max_suggested,omitempty"`
 }
 
+// maxExpandQuery bounds the bead text used as an expansion query.
+const maxExpandQuery = 1000
+
 // ContextForBead returns narrative context for a bead.
 func (c *RobotCLI) ContextForBead(input io.Reader) error {
 	var in ContextForBeadInput
@@ -564,10 +972,79 @@ func (c *RobotCLI) ContextForBead(input io.Reader) error {
 		BeadID:    in.BeadID,
 		SeedBeats: beats,
 	}
+	if in.Expand {
+		if err := c.expandBeadContext(&output, in); err != nil {
+			return outputError("failed to expand context", err)
+		}
+	}
 
 	return outputJSON(output)
 }
 
+// expandBeadContext searches for beats similar to the bead that are not
+// linked to it yet.
+func (c *RobotCLI) expandBeadContext(out *beat.ContextForBeadOutput, in ContextForBeadInput) error {
+	title, desc := in.Title, in.Description
+	if title == "" && desc == "" {
+		known, _, err := lookupBeads(c.store.Dir(), []string{in.BeadID})
+		if err != nil {
+			return err
+		}
+		b, ok := known[in.BeadID]
+		if !ok {
+			return fmt.Errorf("bead %s not found; pass title/description to expand", in.BeadID)
+		}
+		title, desc = b.Title, b.Description
+	}
+	query := truncate(strings.TrimSpace(title+"\n"+desc), maxExpandQuery)
+
+	linked := make(map[string]bool, len(out.SeedBeats))
+	for _, b := range out.SeedBeats {
+		linked[b.ID] = true
+	}
+	suggested, mode, err := similarBeats(c.store, query, linked, in.MaxSuggested)
+	if err != nil {
+		return err
+	}
+	out.SuggestedBeats = suggested
+	out.ExpandQuery = query
+	out.ExpandMode = mode
+	return nil
+}
+
+// similarBeats runs a hybrid search for query and returns up to max beats
+// (default 5) not in exclude, best first, with the search mode used.
+func similarBeats(s *store.JSONLStore, query string, exclude map[string]bool, max int) ([]beat.SuggestedBeat, string, error) {
+	if max <= 0 {
+		max = 5
+	}
+	results, err := store.HybridSearch(s, query, max+len(exclude), true)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var ids []string
+	scores := make(map[string]float64)
+	for _, r := range results.Results {
+		if !exclude[r.ID] && len(ids) < max {
+			ids = append(ids, r.ID)
+			scores[r.ID] = r.Score
+		}
+	}
+	found, err := s.GetByIDs(ids)
+	if err != nil {
+		return nil, "", err
+	}
+	suggested := make([]beat.SuggestedBeat, 0, len(found))
+	for _, b := range found {
+		suggested = append(suggested, beat.SuggestedBeat{Beat: b, Context: "suggested", Score: scores[b.ID]})
+	}
+	sort.SliceStable(suggested, func(i, j int) bool {
+		return suggested[i].Score > suggested[j].Score
+	})
+	return suggested, results.Mode, nil
+}
+
 // MapBeatsToBeadsInput is the input for --robot-map-beats-to-beads.
 type MapBeatsToBeadsInput struct {
 	BeatIDs       []string `json:"beat_ids"`
@@ -576,14 +1053,25 @@ type MapBeatsToBeadsInput struct {
 		Title       string `json:"title"`
 		Description string `json:"description,omitempty"`
 	} `json:"existing_beads,omitempty"`
+	MaxTokens *int `json:"max_tokens,omitempty"` // Prompt token budget; 0 for none (default prompt_tokens)
 }
 
 // MapBeatsToBeadsOutput is the output for --robot-map-beats-to-beads.
 type MapBeatsToBeadsOutput struct {
 	BeatsData     []beat.Beat `json:"beats_data"`
 	MappingPrompt string      `json:"mapping_prompt"`
+	// BeadsSource names the provider existing beads were read from when the
+	// input did not list them.
+	BeadsSource     string   `json:"beads_source,omitempty"`
+	EstimatedTokens int      `json:"estimated_tokens"`
+	TokenBudget     int      `json:"token_budget"`
+	BeatsOmitted    []string `json:"beats_omitted,omitempty"` // Oldest beats left out to fit the budget
+	BeadsOmitted    int      `json:"beads_omitted,omitempty"`
 }
 
+// maxMappedBeads bounds the resolved beads listed in the mapping prompt.
+const maxMappedBeads = 200
+
 // MapBeatsToBeads suggests how beats might map to epics/beads.
 // Returns beat data + mapping prompt for LLM processing.
 func (c *RobotCLI) MapBeatsToBeads(input io.Reader) error {
@@ -608,19 +1096,35 @@ func (c *RobotCLI) MapBeatsToBeads(input io.Reader) error {
 	}
 
 	// Build beat summaries
-	var beatSummaries []string
-	for _, b := range beatsData {
+	beatSummaries := make([]string, len(beatsData))
+	for i, b := range beatsData {
 		linkedStr := ""
 		if len(b.LinkedBeads) > 0 {
-			linkedStr = fmt.Sprintf(" [already linked to: %s]", strings.Join(b.LinkedBeads, ", "))
+			linkedStr = fmt.Sprintf(" [already linked to: %s]", strings.Join(b.LinkedBeads.IDs(), ", "))
+		}
+		beatSummaries[i] = fmt.Sprintf("- [%s] (%s) %s%s", b.ID, b.Impetus.Label, truncate(b.Content, 150), linkedStr)
+	}
+
+	existing := make([]beads.Bead, 0, len(in.ExistingBeads))
+	for _, bead := range in.ExistingBeads {
+		existing = append(existing, beads.Bead{ID: bead.ID, Title: bead.Title, Description: bead.Description})
+	}
+	// Without beads in the input, use the open ones from the provider, or
+	// from the local cache when it is unreachable
+	beadsSource := ""
+	if len(existing) == 0 {
+		list, source, _ := beads.Inventory(c.store.Dir())
+		for _, bead := range list {
+			if !bead.Closed() && len(existing) < maxMappedBeads {
+				existing = append(existing, bead)
+			}
 		}
-		summary := fmt.Sprintf("- [%s] (%s) %s%s", b.ID, b.Impetus.Label, truncate(b.Content, 150), linkedStr)
-		beatSummaries = append(beatSummaries, summary)
+		beadsSource = source
 	}
 
 	// Build existing beads context
 	var beadsSummaries []string
-	for _, bead := range in.ExistingBeads {
+	for _, bead := range existing {
 		desc := bead.Description
 		if len(desc) > 100 {
 			desc = desc[:100] + "..."
@@ -628,12 +1132,61 @@ func (c *RobotCLI) MapBeatsToBeads(input io.Reader) error {
 		beadsSummaries = append(beadsSummaries, fmt.Sprintf("- [%s] %s: %s", bead.ID, bead.Title, desc))
 	}
 
-	existingBeadsSection := "No existing beads provided. Propose new epics only."
-	if len(beadsSummaries) > 0 {
-		existingBeadsSection = fmt.Sprintf("EXISTING BEADS (%d):\n%s", len(beadsSummaries), strings.Join(beadsSummaries, "\n"))
+	// Over budget, beads get up to a third of it and the newest beats the rest
+	budget := brief.PromptBudget(in.MaxTokens)
+	build := func(beatLines, beadLines []string) string {
+		existingBeadsSection := "No existing beads provided. Propose new epics only."
+		if len(beadLines) > 0 {
+			existingBeadsSection = fmt.Sprintf("EXISTING BEADS (%d):\n%s", len(beadLines), strings.Join(beadLines, "\n"))
+		}
+		return fmt.Sprintf(mappingTemplate, len(beatLines), strings.Join(beatLines, "\n"), existingBeadsSection)
+	}
+	beatsBudget, beadsBudget := 0, 0
+	if budget > 0 {
+		left := max(budget-tokens.Estimate(build(nil, nil)), 2)
+		beadsBudget = min(tokens.Fit(beadsSummaries, nil, 0).Tokens, left/3)
+		beatsBudget = left - beadsBudget
+	}
+	newestFirst := make([]int, len(beatsData))
+	for i := range newestFirst {
+		newestFirst[i] = i
+	}
+	sort.SliceStable(newestFirst, func(i, j int) bool {
+		return beatsData[newestFirst[i]].CreatedAt.After(beatsData[newestFirst[j]].CreatedAt)
+	})
+	beatSel := tokens.Fit(beatSummaries, newestFirst, beatsBudget)
+	if budget > 0 {
+		// Beads may use what the beats left over
+		beadsBudget = max(budget-tokens.Estimate(build(beatSel.Lines, nil)), 1)
 	}
+	beadSel := tokens.Fit(beadsSummaries, nil, beadsBudget)
 
-	prompt := fmt.Sprintf(`Map these beats to actionable beads (epics/tasks).
+	used := make([]beat.Beat, 0, len(beatSel.Kept))
+	for _, i := range beatSel.Kept {
+		used = append(used, beatsData[i])
+	}
+	var omitted []string
+	for _, i := range beatSel.Omitted {
+		omitted = append(omitted, beatsData[i].ID)
+	}
+	prompt := build(beatSel.Lines, beadSel.Lines)
+
+	output := MapBeatsToBeadsOutput{
+		BeatsData:       used,
+		MappingPrompt:   prompt,
+		BeadsSource:     beadsSource,
+		EstimatedTokens: tokens.Estimate(prompt),
+		TokenBudget:     budget,
+		BeatsOmitted:    omitted,
+		BeadsOmitted:    len(beadSel.Omitted),
+	}
+
+	return outputJSON(output)
+}
+
+// mappingTemplate is the --robot-map-beats-to-beads prompt: beat count, beat
+// summaries and the existing beads section.
+const mappingTemplate = `Map these beats to actionable beads (epics/tasks).
 
 BEATS TO ANALYZE (%d):
 %s
@@ -654,23 +1207,13 @@ Return JSON with:
   "proposed_links_to_existing": [...],
   "orphan_beats": [...],
   "clusters": [{"theme": "...", "beat_ids": [...]}]
-}`,
-		len(beatsData),
-		strings.Join(beatSummaries, "\n"),
-		existingBeadsSection,
-	)
-
-	output := MapBeatsToBeadsOutput{
-		BeatsData:     beatsData,
-		MappingPrompt: prompt,
-	}
-
-	return outputJSON(output)
-}
+}`
 
 // DiffInput is the input for --robot-diff.
 type DiffInput struct {
 	DiffSince string `json:"diff_since"`
+	// IncludeBeads adds bead_changes: beads that gained or lost beat links.
+	IncludeBeads bool `json:"include_beads,omitempty"`
 }
 
 // Diff returns changes since a given timestamp.
@@ -690,11 +1233,25 @@ func (c *RobotCLI) Diff(input io.Reader) error {
 		return outputError("failed to get beats", err)
 	}
 
-	output := beat.DiffOutput{
+	output := struct {
+		beat.DiffOutput
+		// PendingBeats is the whole review queue, however old
+		PendingBeats []pipeline.PendingBeat `json:"pending_beats"`
+	}{DiffOutput: beat.DiffOutput{
 		NewBeats:           newBeats,
 		ModifiedBeats:      modified,
 		BeatsLinkedToBeads: linked,
 		DeletedIDs:         []string{},
+	}}
+	if in.IncludeBeads {
+		all, err := c.store.ReadAll()
+		if err != nil {
+			return outputError("failed to read beats", err)
+		}
+		output.BeadChanges = beat.BeadLinkChanges(all, since)
+	}
+	if output.PendingBeats, err = pipeline.LoadPending(c.store.Dir()); err != nil {
+		return outputError("failed to read pending beats", err)
 	}
 
 	return outputJSON(output)
@@ -702,8 +1259,12 @@ func (c *RobotCLI) Diff(input io.Reader) error {
 
 // LinkBeatInput is the input for --robot-link-beat.
 type LinkBeatInput struct {
-	BeatID  string   `json:"beat_id"`
-	BeadIDs []string `json:"bead_ids"`
+	BeatID    string   `json:"beat_id"`
+	BeadIDs   []string `json:"bead_ids"`
+	Relation  string   `json:"relation,omitempty"`   // seed (default), evidence, blocks, retrospective
+	CreatedBy string   `json:"created_by,omitempty"` // Agent or person linking (default "robot")
+
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"` // Fail with a conflict if the beat changed since
 }
 
 // LinkBeat links a beat to one or more beads.
@@ -719,18 +1280,14 @@ func (c *RobotCLI) LinkBeat(input io.Reader) error {
 	if len(in.BeadIDs) == 0 {
 		return outputError("bead_ids is required (at least one bead ID)", nil)
 	}
+	if in.Relation != "" && !beat.ValidRelation(in.Relation) {
+		return outputError("invalid relation", fmt.Errorf("%q is not one of %s", in.Relation, strings.Join(beat.Relations, ", ")))
+	}
 
-	updated, err := c.store.Update(in.BeatID, func(b *beat.Beat) error {
-		// Add new bead IDs, avoiding duplicates
-		existing := make(map[string]bool)
-		for _, id := range b.LinkedBeads {
-			existing[id] = true
-		}
+	now := clock.Now().UTC()
+	updated, err := updateBeat(c.store, in.BeatID, in.ExpectedUpdatedAt, func(b *beat.Beat) error {
 		for _, id := range in.BeadIDs {
-			if !existing[id] {
-				b.LinkedBeads = append(b.LinkedBeads, id)
-				existing[id] = true
-			}
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: id, Relation: in.Relation, CreatedAt: now, CreatedBy: robotAuthor(in.CreatedBy)})
 		}
 		return nil
 	})
@@ -773,9 +1330,48 @@ func (c *RobotCLI) SynthesisClear() error {
 	})
 }
 
+// SynthesisHistory returns all archived synthesis requests, newest first.
+func (c *RobotCLI) SynthesisHistory() error {
+	records, err := hooks.ListSyntheses(c.store.Dir())
+	if err != nil {
+		return outputError("failed to read synthesis archive", err)
+	}
+	return outputJSON(map[string]interface{}{
+		"syntheses": records,
+		"count":     len(records),
+	})
+}
+
+// SynthesisRespondInput is the input for --robot-synthesis-respond.
+type SynthesisRespondInput struct {
+	ID       string `json:"id,omitempty"`
+	Response string `json:"response"`
+}
+
+// SynthesisRespond archives the response to a synthesis and clears it if pending.
+func (c *RobotCLI) SynthesisRespond(input io.Reader) error {
+	var in SynthesisRespondInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil {
+		return outputError("invalid input JSON", err)
+	}
+	if in.Response == "" {
+		return outputError("response is required", nil)
+	}
+
+	record, err := hooks.RecordSynthesisResponse(c.store.Dir(), in.ID, in.Response)
+	if err != nil {
+		return outputError("failed to record synthesis response", err)
+	}
+	return outputJSON(record)
+}
+
 // ContextInput is the input for --robot-context.
 type ContextInput struct {
-	Path string `json:"path"`
+	Path        string          `json:"path,omitempty"`
+	Question    string          `json:"question,omitempty"`
+	TokenBudget int             `json:"token_budget,omitempty"`
+	Order       string          `json:"order,omitempty"`   // "relevance" (default) or "time"
+	Scoring     json.RawMessage `json:"scoring,omitempty"` // Per-call scoring.json overrides
 }
 
 // ContextBeatOutput represents a beat in context output.
@@ -833,9 +1429,13 @@ func (c *RobotCLI) Context(input io.Reader) error {
 		return outputError("invalid input JSON", err)
 	}
 
+	if in.Question != "" {
+		return c.ragContext(in)
+	}
+
 	path := in.Path
 	if path == "" {
-		return outputError("path is required", nil)
+		return outputError("path or question is required", nil)
 	}
 
 	// Resolve to WALD path
@@ -853,10 +1453,8 @@ func (c *RobotCLI) Context(input io.Reader) error {
 	// Find direct beats (matching wald_directory)
 	var directBeats []beat.Beat
 	for _, b := range beats {
-		if b.Context != nil && b.Context.WALDDirectory != "" {
-			if b.Context.WALDDirectory == waldPath || strings.HasPrefix(b.Context.WALDDirectory, waldPath+"/") {
-				directBeats = append(directBeats, b)
-			}
+		if b.InWALD(waldPath) {
+			directBeats = append(directBeats, b)
 		}
 	}
 
@@ -910,7 +1508,7 @@ func (c *RobotCLI) Context(input io.Reader) error {
 	}
 	sortBeatsByRecency(cooperatorBeats)
 
-	now := time.Now()
+	now := clock.Now()
 
 	// Build direct beats output
 	directOutput := make([]ContextBeatOutput, 0, len(directBeats))
@@ -976,6 +1574,8 @@ type EditInput struct {
 	RmRefs   []string         `json:"rm_refs,omitempty"`
 	AddBeads []string         `json:"add_beads,omitempty"`
 	RmBeads  []string         `json:"rm_beads,omitempty"`
+
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"` // Fail with a conflict if the beat changed since
 }
 
 // Edit edits a beat by ID with JSON input.
@@ -989,9 +1589,10 @@ func (c *RobotCLI) Edit(input io.Reader) error {
 		return outputError("id is required", nil)
 	}
 
-	updated, err := c.store.Update(in.ID, func(b *beat.Beat) error {
+	updated, err := updateBeat(c.store, in.ID, in.ExpectedUpdatedAt, func(b *beat.Beat) error {
 		if in.Content != "" {
 			b.Content = in.Content
+			b.References = beat.LinkCitations(b.References, b.Content)
 		}
 		if in.Impetus != nil {
 			b.Impetus = *in.Impetus
@@ -1022,31 +1623,12 @@ func (c *RobotCLI) Edit(input io.Reader) error {
 			}
 			b.References = kept
 		}
-		// Add beads
-		existingBeads := make(map[string]bool)
-		for _, id := range b.LinkedBeads {
-			existingBeads[id] = true
-		}
+		// Add and remove beads
+		now := clock.Now().UTC()
 		for _, id := range in.AddBeads {
-			if !existingBeads[id] {
-				b.LinkedBeads = append(b.LinkedBeads, id)
-				existingBeads[id] = true
-			}
-		}
-		// Remove beads
-		if len(in.RmBeads) > 0 {
-			rmSet := make(map[string]bool)
-			for _, id := range in.RmBeads {
-				rmSet[id] = true
-			}
-			var kept []string
-			for _, id := range b.LinkedBeads {
-				if !rmSet[id] {
-					kept = append(kept, id)
-				}
-			}
-			b.LinkedBeads = kept
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: id, CreatedAt: now, CreatedBy: robotAuthor("")})
 		}
+		b.UnlinkBeads(now, in.RmBeads...)
 		return nil
 	})
 	if err != nil {
@@ -1065,6 +1647,8 @@ type AmendInput struct {
 	RmRefs   []string         `json:"rm_refs,omitempty"`
 	AddBeads []string         `json:"add_beads,omitempty"`
 	RmBeads  []string         `json:"rm_beads,omitempty"`
+
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"` // Fail with a conflict if the beat changed since
 }
 
 // Amend edits the most recent beat with JSON input.
@@ -1089,11 +1673,14 @@ func (c *RobotCLI) Amend(input io.Reader) error {
 		RmRefs:   in.RmRefs,
 		AddBeads: in.AddBeads,
 		RmBeads:  in.RmBeads,
+
+		ExpectedUpdatedAt: in.ExpectedUpdatedAt,
 	}
 
-	updated, err := c.store.Update(editIn.ID, func(b *beat.Beat) error {
+	updated, err := updateBeat(c.store, editIn.ID, editIn.ExpectedUpdatedAt, func(b *beat.Beat) error {
 		if editIn.Content != "" {
 			b.Content = editIn.Content
+			b.References = beat.LinkCitations(b.References, b.Content)
 		}
 		if editIn.Impetus != nil {
 			b.Impetus = *editIn.Impetus
@@ -1122,29 +1709,11 @@ func (c *RobotCLI) Amend(input io.Reader) error {
 			}
 			b.References = kept
 		}
-		existingBeads := make(map[string]bool)
-		for _, id := range b.LinkedBeads {
-			existingBeads[id] = true
-		}
+		now := clock.Now().UTC()
 		for _, id := range editIn.AddBeads {
-			if !existingBeads[id] {
-				b.LinkedBeads = append(b.LinkedBeads, id)
-				existingBeads[id] = true
-			}
-		}
-		if len(editIn.RmBeads) > 0 {
-			rmSet := make(map[string]bool)
-			for _, id := range editIn.RmBeads {
-				rmSet[id] = true
-			}
-			var kept []string
-			for _, id := range b.LinkedBeads {
-				if !rmSet[id] {
-					kept = append(kept, id)
-				}
-			}
-			b.LinkedBeads = kept
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: id, CreatedAt: now, CreatedBy: robotAuthor("")})
 		}
+		b.UnlinkBeads(now, editIn.RmBeads...)
 		return nil
 	})
 	if err != nil {
@@ -1163,17 +1732,25 @@ type ImportInput struct {
 
 // ImportOutput is the output for --robot-import.
 type ImportOutput struct {
-	Imported int      `json:"imported"`
-	Skipped  int      `json:"skipped"`
-	Errors   []string `json:"errors"`
+	Imported      int      `json:"imported"`
+	Skipped       int      `json:"skipped"`
+	Errors        []string `json:"errors"`
+	SchemaVersion int      `json:"schema_version,omitempty"` // Set when a snapshot was imported
 }
 
 // Import bulk imports beats with conflict resolution.
 func (c *RobotCLI) Import(input io.Reader) error {
-	var in ImportInput
-	if err := json.NewDecoder(input).Decode(&in); err != nil {
+	snap, decoded, err := decodeImport(input)
+	if err != nil {
 		return outputError("invalid input JSON", err)
 	}
+	if snap != nil {
+		if err := restoreSnapshot(c.store, snap); err != nil {
+			return outputError("failed to import snapshot", err)
+		}
+		return outputJSON(ImportOutput{Imported: len(snap.Beats), Errors: []string{}, SchemaVersion: snap.SchemaVersion})
+	}
+	in := *decoded
 
 	if len(in.Beats) == 0 {
 		return outputError("beats array is required and must not be empty", nil)
@@ -1214,7 +1791,7 @@ func (c *RobotCLI) Import(input io.Reader) error {
 					output.Errors = append(output.Errors, fmt.Sprintf("failed to get sequence for %s: %v", b.ID, err))
 					continue
 				}
-				b.ID = fmt.Sprintf("beat-%s-%03d", b.CreatedAt.Format("20060102"), seq)
+				b.ID = beat.GenerateIDWithSequence(b.CreatedAt, seq)
 			default: // error
 				output.Errors = append(output.Errors, fmt.Sprintf("beat %s already exists", b.ID))
 				continue
@@ -1223,9 +1800,9 @@ func (c *RobotCLI) Import(input io.Reader) error {
 
 		// Set timestamps if missing
 		if b.CreatedAt.IsZero() {
-			b.CreatedAt = time.Now()
+			b.CreatedAt = clock.Now()
 		}
-		b.UpdatedAt = time.Now()
+		b.UpdatedAt = clock.Now()
 
 		if err := c.store.Append(&b); err != nil {
 			output.Errors = append(output.Errors, fmt.Sprintf("failed to import %s: %v", b.ID, err))
@@ -1240,11 +1817,12 @@ func (c *RobotCLI) Import(input io.Reader) error {
 
 // ExportInput is the input for --robot-export.
 type ExportInput struct {
-	Format  string `json:"format,omitempty"` // json, jsonl
+	Format  string `json:"format,omitempty"` // json, jsonl, jsonld, snapshot, snapshot-ndjson
 	Since   string `json:"since,omitempty"`
 	Until   string `json:"until,omitempty"`
 	Impetus string `json:"impetus,omitempty"`
 	Query   string `json:"query,omitempty"`
+	Base    string `json:"base,omitempty"` // IRI prefix for beat nodes in JSON-LD
 }
 
 // Export exports beats with filters.
@@ -1254,6 +1832,23 @@ func (c *RobotCLI) Export(input io.Reader) error {
 		return outputError("invalid input JSON", err)
 	}
 
+	if in.Format == FormatSnapshot || in.Format == FormatSnapshotNDJSON {
+		if in.Since != "" || in.Until != "" || in.Impetus != "" || in.Query != "" {
+			return outputError("a snapshot holds the whole store; filters are not supported", nil)
+		}
+		snap, err := buildSnapshot(c.store)
+		if err != nil {
+			return outputError("failed to build snapshot", err)
+		}
+		if in.Format == FormatSnapshotNDJSON {
+			if err := writeSnapshotNDJSON(jsonOutput, snap); err != nil {
+				return outputError("failed to write snapshot", err)
+			}
+			return nil
+		}
+		return outputJSON(snap)
+	}
+
 	beats, err := c.store.ReadAll()
 	if err != nil {
 		return outputError("failed to read beats", err)
@@ -1304,6 +1899,10 @@ func (c *RobotCLI) Export(input io.Reader) error {
 		format = "json"
 	}
 
+	if format == FormatJSONLD {
+		return outputJSON(buildJSONLD(filtered, in.Base))
+	}
+
 	if format == "jsonl" {
 		// Output JSONL
 		for _, b := range filtered {
@@ -1326,6 +1925,8 @@ func (c *RobotCLI) Export(input io.Reader) error {
 type RedateInput struct {
 	ID   string `json:"id"`
 	Date string `json:"date"`
+
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"` // Fail with a conflict if the beat changed since
 }
 
 // Redate changes the creation date of a beat.
@@ -1350,7 +1951,7 @@ func (c *RobotCLI) Redate(input io.Reader) error {
 		}
 	}
 
-	updated, err := c.store.Update(in.ID, func(b *beat.Beat) error {
+	updated, err := updateBeat(c.store, in.ID, in.ExpectedUpdatedAt, func(b *beat.Beat) error {
 		b.CreatedAt = t
 		return nil
 	})
@@ -1361,8 +1962,20 @@ func (c *RobotCLI) Redate(input io.Reader) error {
 	return outputJSON(updated)
 }
 
+// robotAuthor records who created a bead link through a robot command.
+func robotAuthor(by string) string {
+	if by != "" {
+		return by
+	}
+	return "robot"
+}
+
 func outputJSON(v interface{}) error {
-	enc := json.NewEncoder(jsonOutput)
+	w := jsonOutput
+	if w == nil {
+		w = os.Stdout
+	}
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
@@ -1374,9 +1987,35 @@ func outputError(msg string, err error) error {
 	if err != nil {
 		errObj["details"] = err.Error()
 	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		errObj["code"] = "timeout"
+		var te *TimeoutError
+		if errors.As(err, &te) {
+			errObj["timeout_ms"] = te.TimeoutMS
+		}
+	}
+	var conflict *store.ConflictError
+	if errors.As(err, &conflict) {
+		errObj["code"] = "conflict"
+		errObj["current_updated_at"] = conflict.Current
+	}
+	var ambiguous *store.AmbiguousIDError
+	if errors.As(err, &ambiguous) {
+		errObj["code"] = "ambiguous_id"
+		errObj["candidates"] = ambiguous.Candidates
+	}
 	return outputJSON(errObj)
 }
 
+// updateBeat updates a beat; with expected set, only if its updated_at is
+// still the one the caller read.
+func updateBeat(s *store.JSONLStore, id string, expected *time.Time, updater func(*beat.Beat) error) (*beat.Beat, error) {
+	if expected == nil {
+		return s.Update(id, updater)
+	}
+	return s.UpdateIfUnchanged(id, *expected, updater)
+}
+
 // jsonOutput is where JSON output is written (defaults to stdout).
 var jsonOutput io.Writer = nil
 
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestContextForBeadExpand(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1") // TF-IDF, no embeddings
+
+	s, setBeads := beadsFixture(t)
+	setBeads(`{"id":"bd-1","title":"Login timeout","description":"Sessions expire during login"}` + "\n")
+	created := time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)
+	for _, b := range []*beat.Beat{
+		{ID: "beat-20260315-001", Content: "Login timeout hits users on slow networks", LinkedBeads: linked("bd-1")},
+		{ID: "beat-20260315-002", Content: "Sessions expire while the login page loads"},
+		{ID: "beat-20260315-003", Content: "Login timeout again, sessions expire too early"},
+		{ID: "beat-20260315-004", Content: "Pricing tiers confuse people"},
+	} {
+		b.CreatedAt, b.UpdatedAt = created, created
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewRobotCLI(s)
+	contextFor := func(in string) (beat.ContextForBeadOutput, map[string]any) {
+		t.Helper()
+		var buf bytes.Buffer
+		SetJSONOutput(&buf)
+		defer SetJSONOutput(nil)
+		if err := c.ContextForBead(strings.NewReader(in)); err != nil {
+			t.Fatal(err)
+		}
+		var out beat.ContextForBeadOutput
+		var raw map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid output %s: %v", buf.String(), err)
+		}
+		if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+			t.Fatal(err)
+		}
+		return out, raw
+	}
+
+	// Without expand only the linked beats come back.
+	out, raw := contextFor(`{"bead_id":"bd-1"}`)
+	if len(out.SeedBeats) != 1 || out.SeedBeats[0].ID != "beat-20260315-001" {
+		t.Errorf("seed beats = %+v", out.SeedBeats)
+	}
+	if _, ok := raw["suggested_beats"]; ok || out.ExpandQuery != "" {
+		t.Errorf("suggestions without expand: %v", raw)
+	}
+
+	// Expanded by the bead's title and description from the provider: linked
+	// beats stay confirmed seeds and are never suggested again.
+	out, _ = contextFor(`{"bead_id":"bd-1","expand":true}`)
+	if len(out.SeedBeats) != 1 || out.SeedBeats[0].ID != "beat-20260315-001" {
+		t.Errorf("seed beats = %+v", out.SeedBeats)
+	}
+	if out.ExpandQuery != "Login timeout Sessions expire during login" || out.ExpandMode != "tfidf" {
+		t.Errorf("query %q, mode %q", out.ExpandQuery, out.ExpandMode)
+	}
+	var ids []string
+	for i, sb := range out.SuggestedBeats {
+		ids = append(ids, sb.ID)
+		if sb.Context != "suggested" || sb.Score <= 0 {
+			t.Errorf("suggestion %s: context %q, score %v", sb.ID, sb.Context, sb.Score)
+		}
+		if i > 0 && sb.Score > out.SuggestedBeats[i-1].Score {
+			t.Errorf("suggestions not best first: %v", out.SuggestedBeats)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "beat-20260315-003" || ids[1] != "beat-20260315-002" {
+		t.Errorf("suggested = %v, want beat-20260315-003 then beat-20260315-002", ids)
+	}
+
+	// Given text wins over the provider, and max_suggested caps the list.
+	out, _ = contextFor(`{"bead_id":"bd-1","expand":true,"title":"pricing tiers","max_suggested":1}`)
+	if len(out.SuggestedBeats) != 1 || out.SuggestedBeats[0].ID != "beat-20260315-004" {
+		t.Errorf("suggested = %+v, want only beat-20260315-004", out.SuggestedBeats)
+	}
+
+	// A bead the provider does not know needs its text passed in.
+	_, raw = contextFor(`{"bead_id":"bd-9","expand":true}`)
+	if raw["error"] != "failed to expand context" || !strings.Contains(raw["details"].(string), "bd-9 not found") {
+		t.Errorf("unknown bead = %v", raw)
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// DefaultSampleSize is how many beats --robot-sample returns by default.
+const DefaultSampleSize = 20
+
+// SampleInput is the input for --robot-sample.
+type SampleInput struct {
+	Size     int    `json:"size,omitempty"`     // Beats to return (default 20)
+	Stratify string `json:"stratify,omitempty"` // date (month), impetus or kind; empty for a simple random sample
+	Seed     *int64 `json:"seed,omitempty"`     // Same seed and store, same sample
+	Since    string `json:"since,omitempty"`    // Only beats created from then
+	Until    string `json:"until,omitempty"`    // ...and before then
+}
+
+// SampleStratum is one group of the population and its share of the sample.
+type SampleStratum struct {
+	Name       string `json:"name"`
+	Population int    `json:"population"`
+	Sampled    int    `json:"sampled"`
+}
+
+// SampleOutput is the output for --robot-sample.
+type SampleOutput struct {
+	Seed       int64           `json:"seed"`
+	Stratify   string          `json:"stratify,omitempty"`
+	Population int             `json:"population"`
+	Strata     []SampleStratum `json:"strata,omitempty"`
+	Beats      []beat.Beat     `json:"beats"`
+}
+
+// stratumOf returns the function naming a beat's stratum.
+func stratumOf(stratify string) (func(beat.Beat) string, error) {
+	switch stratify {
+	case "":
+		return func(beat.Beat) string { return "" }, nil
+	case "date":
+		return func(b beat.Beat) string { return b.CreatedAt.In(DisplayLocation).Format("2006-01") }, nil
+	case "impetus":
+		return func(b beat.Beat) string { return b.Impetus.Label }, nil
+	case "kind":
+		return func(b beat.Beat) string {
+			if kind := b.Impetus.Meta["kind"]; kind != "" {
+				return kind
+			}
+			return "beat"
+		}, nil
+	}
+	return nil, fmt.Errorf("stratify must be date, impetus or kind, not %q", stratify)
+}
+
+// sampleBeats draws size beats without replacement. Stratified, each stratum
+// gets a share proportional to its population (largest remainders first),
+// and at least one beat while the sample has room for every stratum, so
+// rare impetus labels and quiet months still show up.
+func sampleBeats(beats []beat.Beat, size int, stratify string, seed int64) ([]beat.Beat, []SampleStratum, error) {
+	name, err := stratumOf(stratify)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Draw from a fixed order so the seed alone decides the sample
+	sorted := append([]beat.Beat(nil), beats...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	groups := make(map[string][]beat.Beat)
+	var names []string
+	for _, b := range sorted {
+		n := name(b)
+		if groups[n] == nil {
+			names = append(names, n)
+		}
+		groups[n] = append(groups[n], b)
+	}
+	sort.Strings(names)
+	if size > len(sorted) {
+		size = len(sorted)
+	}
+
+	quota := make(map[string]int, len(names))
+	remainders := make(map[string]float64, len(names))
+	given := 0
+	for _, n := range names {
+		share := float64(size) * float64(len(groups[n])) / float64(max(len(sorted), 1))
+		quota[n] = int(share)
+		remainders[n] = share - float64(quota[n])
+		if quota[n] == 0 && size >= len(names) {
+			quota[n], remainders[n] = 1, 0
+		}
+		given += quota[n]
+	}
+	byRemainder := append([]string(nil), names...)
+	sort.SliceStable(byRemainder, func(i, j int) bool { return remainders[byRemainder[i]] > remainders[byRemainder[j]] })
+	for i := 0; given < size; i = (i + 1) % len(byRemainder) {
+		if n := byRemainder[i]; quota[n] < len(groups[n]) {
+			quota[n]++
+			given++
+		}
+	}
+	for given > size { // The minimum of one overshot; take from the largest
+		largest := names[0]
+		for _, n := range names {
+			if quota[n] > quota[largest] {
+				largest = n
+			}
+		}
+		quota[largest]--
+		given--
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+	var sample []beat.Beat
+	var strata []SampleStratum
+	for _, n := range names {
+		group := groups[n]
+		rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		sample = append(sample, group[:quota[n]]...)
+		strata = append(strata, SampleStratum{Name: n, Population: len(group), Sampled: quota[n]})
+	}
+	sort.Slice(sample, func(i, j int) bool { return sample[i].CreatedAt.Before(sample[j].CreatedAt) })
+	if stratify == "" {
+		strata = nil
+	}
+	return sample, strata, nil
+}
+
+// Sample returns a random, optionally stratified, sample of beats for
+// building evaluation sets.
+func (c *RobotCLI) Sample(input io.Reader) error {
+	var in SampleInput
+	if err := json.NewDecoder(input).Decode(&in); err != nil && err != io.EOF {
+		return outputError("invalid input JSON", err)
+	}
+	size := in.Size
+	if size <= 0 {
+		size = DefaultSampleSize
+	}
+	seed := clock.Now().UnixNano()
+	if in.Seed != nil {
+		seed = *in.Seed
+	}
+
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		return outputError("failed to read beats", err)
+	}
+	var since, until time.Time
+	if in.Since != "" {
+		if since, err = clock.ParseRelative(in.Since); err != nil {
+			return outputError("invalid since", err)
+		}
+	}
+	if in.Until != "" {
+		if until, err = clock.ParseRelative(in.Until); err != nil {
+			return outputError("invalid until", err)
+		}
+	}
+	var population []beat.Beat
+	for _, b := range beats {
+		if b.CreatedAt.Before(since) || !until.IsZero() && !b.CreatedAt.Before(until) {
+			continue
+		}
+		population = append(population, b)
+	}
+
+	sample, strata, err := sampleBeats(population, size, in.Stratify, seed)
+	if err != nil {
+		return outputError("invalid stratify", err)
+	}
+	if sample == nil {
+		sample = []beat.Beat{}
+	}
+	return outputJSON(SampleOutput{
+		Seed:       seed,
+		Stratify:   in.Stratify,
+		Population: len(population),
+		Strata:     strata,
+		Beats:      sample,
+	})
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestSampleBeats(t *testing.T) {
+	var beats []beat.Beat
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 40; i++ {
+		label := "note"
+		if i%10 == 0 {
+			label = "coaching" // 4 of 40
+		}
+		beats = append(beats, beat.Beat{
+			ID:        fmt.Sprintf("beat-202601%02d-%03d", i%28+1, i),
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+			Impetus:   beat.Impetus{Label: label},
+		})
+	}
+
+	sample, strata, err := sampleBeats(beats, 10, "impetus", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sample) != 10 || len(strata) != 2 {
+		t.Fatalf("sample = %d beats, strata = %+v", len(sample), strata)
+	}
+	for _, s := range strata {
+		want := map[string]int{"coaching": 1, "note": 9}[s.Name]
+		if s.Sampled != want {
+			t.Errorf("stratum %s sampled %d, want %d", s.Name, s.Sampled, want)
+		}
+	}
+	for i := 1; i < len(sample); i++ {
+		if sample[i].CreatedAt.Before(sample[i-1].CreatedAt) {
+			t.Errorf("sample not oldest first")
+		}
+	}
+
+	// The seed fixes the sample, whatever order the beats come in
+	reversed := make([]beat.Beat, len(beats))
+	for i, b := range beats {
+		reversed[len(beats)-1-i] = b
+	}
+	again, _, _ := sampleBeats(reversed, 10, "impetus", 42)
+	other, _, _ := sampleBeats(beats, 10, "impetus", 43)
+	same, differs := true, false
+	for i := range sample {
+		same = same && again[i].ID == sample[i].ID
+		differs = differs || other[i].ID != sample[i].ID
+	}
+	if !same || !differs {
+		t.Errorf("seed 42 twice same = %v, seed 43 differs = %v", same, differs)
+	}
+
+	if all, _, _ := sampleBeats(beats, 100, "", 1); len(all) != 40 {
+		t.Errorf("oversized sample = %d beats, want all 40", len(all))
+	}
+	if _, _, err := sampleBeats(beats, 5, "author", 1); err == nil {
+		t.Error("stratify by author: want an error")
+	}
+}
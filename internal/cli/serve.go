@@ -0,0 +1,468 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/impetus"
+	"github.com/bierlingm/beats/internal/metrics"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/webhook"
+)
+
+// ServeTokenFile holds the capture server's bearer token, relative to the beats directory.
+const ServeTokenFile = "serve_token"
+
+// maxCaptureBody bounds request bodies accepted by the capture server.
+const maxCaptureBody = 1 << 20
+
+// ServeOptions configures the capture server.
+type ServeOptions struct {
+	Addr  string // Listen address (default 127.0.0.1:7777)
+	Token string // Bearer token; read or generated in .beats/serve_token when empty
+}
+
+// CaptureRequest is a capture sent by a bookmarklet, browser extension or
+// iOS Shortcut. Text alone is enough; a bare URL in it is captured as a page.
+type CaptureRequest struct {
+	Text      string `json:"text,omitempty"` // The beat's text, e.g. from the share sheet
+	URL       string `json:"url,omitempty"`
+	Title     string `json:"title,omitempty"`     // Tab title, used when the page cannot be fetched
+	Selection string `json:"selection,omitempty"` // Highlighted text
+	Note      string `json:"note,omitempty"`
+	Snapshot  bool   `json:"snapshot,omitempty"` // Archive the page as HTML and markdown
+	Source    string `json:"source,omitempty"`   // impetus.meta source (default "browser")
+}
+
+// CaptureResponse is returned for a created beat. Message is a one-line
+// confirmation clients can show as is (a Shortcut's notification).
+type CaptureResponse struct {
+	ID        string    `json:"id"`
+	Impetus   string    `json:"impetus"`
+	CreatedAt time.Time `json:"created_at"`
+	Message   string    `json:"message"`
+}
+
+func newCaptureResponse(b *beat.Beat) CaptureResponse {
+	return CaptureResponse{
+		ID:        b.ID,
+		Impetus:   b.Impetus.Label,
+		CreatedAt: b.CreatedAt,
+		Message:   fmt.Sprintf("Saved %s (%s)", b.ID, b.Impetus.Label),
+	}
+}
+
+// serveToken returns the configured token, creating a random one on first use.
+func (c *HumanCLI) serveToken() (string, error) {
+	path := filepath.Join(c.store.Dir(), ServeTokenFile)
+	if data, err := os.ReadFile(path); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", ServeTokenFile, err)
+	}
+	return token, nil
+}
+
+// captureSourceRe limits the client-chosen source label.
+var captureSourceRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// captureFromRequest runs a browser capture through the same pipeline as
+// `bt add -w`: page extraction, impetus inference and the commit checks.
+func (c *HumanCLI) captureFromRequest(req CaptureRequest) (*beat.Beat, error) {
+	req.URL = strings.TrimSpace(req.URL)
+	req.Text = strings.TrimSpace(req.Text)
+	// The share sheet hands over a shared page as its bare URL
+	if req.URL == "" && isHTTPURL(req.Text) && !strings.ContainsAny(req.Text, " \t\n") {
+		req.URL, req.Text = req.Text, ""
+	}
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source == "" {
+		source = "browser"
+	}
+	if !captureSourceRe.MatchString(source) {
+		return nil, fmt.Errorf("invalid source %q (lowercase letters, digits and dashes)", req.Source)
+	}
+
+	var extra []string
+	if req.Text != "" {
+		extra = append(extra, req.Text)
+	}
+	if note := strings.TrimSpace(req.Note); note != "" {
+		extra = append(extra, note)
+	}
+	if sel := strings.TrimSpace(req.Selection); sel != "" {
+		extra = append(extra, "> "+strings.ReplaceAll(sel, "\n", "\n> "))
+	}
+	additional := strings.Join(extra, "\n\n")
+
+	var p *beat.ProposedBeat
+	if req.URL != "" {
+		if !isHTTPURL(req.URL) {
+			return nil, fmt.Errorf("url must be http(s): %s", req.URL)
+		}
+		web, err := capture.CaptureFromURL(req.URL, additional)
+		if err != nil {
+			return nil, fmt.Errorf("web capture failed: %w", err)
+		}
+		if web.Title == "" && req.Title != "" {
+			web.SetTitle(strings.TrimSpace(req.Title), additional)
+		}
+		meta, refs := c.webCaptureDetails(web, c.wantSnapshot(req.Snapshot))
+		meta["source"] = source
+		if req.Selection != "" {
+			meta["selection"] = "true"
+		}
+		p = &beat.ProposedBeat{
+			Content:    web.Content,
+			Impetus:    beat.Impetus{Label: web.Impetus, Raw: req.URL, Meta: meta},
+			References: refs,
+		}
+	} else {
+		if additional == "" {
+			return nil, fmt.Errorf("text or url is required")
+		}
+		label := impetus.Infer(additional)
+		if label == "" {
+			label = "Manual entry"
+		}
+		p = &beat.ProposedBeat{
+			Content:    additional,
+			Impetus:    beat.Impetus{Label: label, Meta: map[string]string{"source": source}},
+			References: []beat.Reference{},
+		}
+	}
+	p.LinkedBeads = []string{}
+	return c.commit(p, beat.ChannelWeb)
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// pendingResponse is the body for a capture held for review.
+func pendingResponse(held *pipeline.PendingError) map[string]interface{} {
+	return map[string]interface{}{
+		"pending": held.Pending.ID,
+		"reason":  held.Pending.Reason,
+		"details": held.Pending.Details,
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// decodeCaptureRequest accepts a JSON object, a text/plain body (the text
+// itself, for Shortcuts that post a file or raw text) or, for plain HTML
+// forms and curl's default content type, url-encoded fields.
+func decodeCaptureRequest(body io.Reader, contentType string) (CaptureRequest, error) {
+	var req CaptureRequest
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return req, err
+	}
+	if strings.HasPrefix(strings.ToLower(contentType), "text/plain") {
+		return CaptureRequest{Text: string(data)}, nil
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &req); err != nil {
+			return req, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return req, nil
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return req, fmt.Errorf("invalid form body: %w", err)
+	}
+	return CaptureRequest{
+		Text:      form.Get("text"),
+		URL:       form.Get("url"),
+		Title:     form.Get("title"),
+		Selection: form.Get("selection"),
+		Note:      form.Get("note"),
+		Snapshot:  form.Get("snapshot") == "true" || form.Get("snapshot") == "1",
+		Source:    form.Get("source"),
+	}, nil
+}
+
+// captureHandler serves POST /capture. CORS is open because extensions and
+// bookmarklets call from arbitrary origins; the token is what grants access.
+func (c *HumanCLI) captureHandler(auth *serveAuth, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Beats-Token")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST, OPTIONS")
+			writeJSONError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+		t := auth.authorize(w, r, ScopeCapture, false)
+		if t == nil {
+			return
+		}
+
+		req, err := decodeCaptureRequest(http.MaxBytesReader(w, r.Body, maxCaptureBody), r.Header.Get("Content-Type"))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d MB", maxCaptureBody>>20))
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		b, err := c.captureFromRequest(req)
+		var held *pipeline.PendingError
+		if errors.As(err, &held) {
+			logger.Printf("capture %s", held)
+			writeJSON(w, http.StatusAccepted, pendingResponse(held))
+			return
+		}
+		if err != nil {
+			logger.Printf("capture failed: %v", err)
+			writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		logger.Printf("captured %s (%s, token %s)", b.ID, b.Impetus.Label, t.name)
+		writeJSON(w, http.StatusCreated, newCaptureResponse(b))
+	})
+}
+
+// decodeWebhookPayload parses a JSON body or, for services that post forms,
+// url-encoded fields as a flat object.
+func decodeWebhookPayload(data []byte) (interface{}, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var payload interface{}
+		if err := json.Unmarshal(trimmed, &payload); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return payload, nil
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid form body: %w", err)
+	}
+	payload := map[string]interface{}{}
+	for k := range form {
+		payload[k] = form.Get(k)
+	}
+	return payload, nil
+}
+
+// webhookBeat maps a delivery onto a beat and commits it. Deliveries whose
+// mapped ID was already captured for this webhook return the existing beat,
+// so retries from the sending service do not create duplicates.
+func (c *HumanCLI) webhookBeat(name string, endpoint webhook.Endpoint, payload interface{}) (*beat.Beat, bool, error) {
+	p, err := endpoint.Apply(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	p.Impetus.Meta["source"] = "webhook"
+	p.Impetus.Meta["webhook"] = name
+
+	if id := p.Impetus.Meta["source_id"]; id != "" {
+		existing, err := c.store.ReadAll()
+		if err != nil {
+			return nil, false, err
+		}
+		for _, b := range existing {
+			if b.Impetus.Meta["webhook"] == name && b.Impetus.Meta["source_id"] == id {
+				return &b, false, nil
+			}
+		}
+	}
+
+	if p.Impetus.Label == "" {
+		p.Impetus.Label = impetus.Infer(p.Content)
+		if p.Impetus.Label == "" {
+			p.Impetus.Label = "Webhook"
+		}
+	}
+	b, err := c.commit(p, beat.ChannelWebhook)
+	return b, err == nil, err
+}
+
+// webhookHandler serves POST /capture/webhook/<name>. Endpoints are read from
+// webhooks.json on every delivery, so edits apply without a restart.
+func (c *HumanCLI) webhookHandler(logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJSONError(w, http.StatusMethodNotAllowed, "use POST")
+			return
+		}
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/capture/webhook/"), "/")
+		cfg, err := webhook.LoadConfig(c.store.Dir())
+		if err != nil {
+			logger.Printf("webhook %s: %v", name, err)
+			writeJSONError(w, http.StatusInternalServerError, "invalid webhook configuration")
+			return
+		}
+		endpoint, ok := cfg.Webhooks[name]
+		if !ok || name == "" {
+			writeJSONError(w, http.StatusNotFound, "unknown webhook")
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCaptureBody))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if !endpoint.Verify(r, data) {
+			writeJSONError(w, http.StatusUnauthorized, "missing or invalid secret")
+			return
+		}
+		payload, err := decodeWebhookPayload(data)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		b, created, err := c.webhookBeat(name, endpoint, payload)
+		var held *pipeline.PendingError
+		if errors.As(err, &held) {
+			logger.Printf("webhook %s %s", name, held)
+			writeJSON(w, http.StatusAccepted, pendingResponse(held))
+			return
+		}
+		if err != nil {
+			logger.Printf("webhook %s failed: %v", name, err)
+			writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+			logger.Printf("webhook %s captured %s (%s)", name, b.ID, b.Impetus.Label)
+		}
+		writeJSON(w, status, newCaptureResponse(b))
+	})
+}
+
+// Bookmarklet returns a javascript: URL that posts the current tab to the server.
+func Bookmarklet(addr, token string) string {
+	return "javascript:(()=>{fetch('http://" + addr + "/capture',{method:'POST'," +
+		"headers:{'Authorization':'Bearer " + token + "','Content-Type':'application/json'}," +
+		"body:JSON.stringify({url:location.href,title:document.title,selection:String(getSelection()),note:prompt('beats note (optional)')||''})})" +
+		".then(r=>r.json()).then(j=>alert(j.id?'Saved '+j.id:'beats: '+j.error)).catch(e=>alert('beats: '+e))})()"
+}
+
+// serveMux routes the capture server's endpoints.
+func (c *HumanCLI) serveMux(auth *serveAuth, logger *log.Logger) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/capture", c.captureHandler(auth, logger))
+	mux.Handle("/capture/webhook/", c.webhookHandler(logger))
+	mux.Handle("/beats", c.beatsHandler(auth, logger))
+	mux.Handle("/beats/", c.beatsHandler(auth, logger))
+	mux.Handle("/feed/digests.xml", c.digestFeedHandler(auth))
+	mux.Handle("/digests/", c.digestPageHandler(auth))
+	metrics.Register(mux, c.store)
+	return mux
+}
+
+// ServeCapture runs the localhost capture endpoint until ctx is cancelled.
+func (c *HumanCLI) ServeCapture(ctx context.Context, opts ServeOptions, logger *log.Logger) error {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:7777"
+	}
+	token := opts.Token
+	if token == "" {
+		var err error
+		if token, err = c.serveToken(); err != nil {
+			return err
+		}
+	}
+
+	auth, err := newServeAuth(token, config.Get().ServeTokens)
+	if err != nil {
+		return fmt.Errorf("invalid serve_tokens in %s: %w", config.Path(), err)
+	}
+	srv := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           c.serveMux(auth, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	fmt.Printf("Capture endpoint: POST http://%s/capture\n", opts.Addr)
+	if host, _, err := net.SplitHostPort(opts.Addr); err == nil && !isLoopback(host) {
+		fmt.Println("Listening beyond localhost: anyone on the network can reach it with the token; use a VPN or HTTPS tunnel outside your LAN")
+	}
+	fmt.Printf("Health: GET http://%s/healthz, metrics: GET http://%s/metrics\n", opts.Addr, opts.Addr)
+	fmt.Printf("Digest feed: GET http://%s/feed/digests.xml?token=<token>\n", opts.Addr)
+	fmt.Printf("Beats API: GET http://%s/beats, GET /beats/<id>, POST /beats/<id>/links\n", opts.Addr)
+	fmt.Printf("Token: %s\n", filepath.Join(c.store.Dir(), ServeTokenFile))
+	fmt.Printf("Tokens: %s\n", auth.describe())
+	fmt.Printf("Bookmarklet:\n%s\n", Bookmarklet(opts.Addr, token))
+	if cfg, err := webhook.LoadConfig(c.store.Dir()); err != nil {
+		logger.Printf("%v", err)
+	} else {
+		for name := range cfg.Webhooks {
+			fmt.Printf("Webhook: POST http://%s/capture/webhook/%s\n", opts.Addr, name)
+		}
+	}
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		logger.Printf("stopping")
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestCaptureHandler(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota := `{"quota": {"enabled": true, "daily": {"phone": 1}}}`
+	if err := os.WriteFile(filepath.Join(s.Dir(), hooks.HooksConfigFile), []byte(quota), 0644); err != nil {
+		t.Fatal(err)
+	}
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Queue sharding at scale</title></head><body><p>Shard by tenant.</p></body></html>`)
+	}))
+	defer page.Close()
+
+	c := NewHumanCLI(s)
+	auth, err := newServeAuth("secret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(c.captureHandler(auth, log.New(io.Discard, "", 0)))
+	defer srv.Close()
+
+	post := func(t *testing.T, header map[string]string, body string) (int, map[string]string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		out := map[string]string{}
+		_ = json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+	bearer := func(contentType string) map[string]string {
+		return map[string]string{"Authorization": "Bearer secret", "Content-Type": contentType}
+	}
+
+	tests := []struct {
+		name    string
+		header  map[string]string
+		body    string
+		status  int
+		content string // Of the beat created
+		url     string // Its impetus.raw, for page captures
+	}{
+		{name: "no token", header: map[string]string{"Content-Type": "application/json"}, body: `{"text": "x"}`, status: http.StatusUnauthorized},
+		{name: "wrong token", header: map[string]string{"Authorization": "Bearer nope"}, body: `{"text": "x"}`, status: http.StatusUnauthorized},
+		{name: "json", header: bearer("application/json"), body: `{"text": "Shard the queue by tenant"}`, status: http.StatusCreated, content: "Shard the queue by tenant"},
+		{name: "token header", header: map[string]string{"X-Beats-Token": "secret", "Content-Type": "application/json"}, body: `{"text": "From a Shortcut"}`, status: http.StatusCreated, content: "From a Shortcut"},
+		{name: "text/plain", header: bearer("text/plain; charset=utf-8"), body: "Read about {braces} later\n", status: http.StatusCreated, content: "Read about {braces} later"},
+		{name: "form", header: bearer("application/x-www-form-urlencoded"), body: "text=Call+the+vendor&note=before+Friday", status: http.StatusCreated, content: "Call the vendor\n\nbefore Friday"},
+		{name: "bare url", header: bearer("text/plain"), body: page.URL + "/post\n", status: http.StatusCreated, url: page.URL + "/post"},
+		{name: "empty", header: bearer("application/json"), body: `{"note": "  "}`, status: http.StatusUnprocessableEntity},
+		{name: "invalid json", header: bearer("application/json"), body: `{"text": `, status: http.StatusBadRequest},
+		{name: "too large", header: bearer("text/plain"), body: strings.Repeat("a", maxCaptureBody+1), status: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, out := post(t, tt.header, tt.body)
+			if status != tt.status {
+				t.Fatalf("status = %d (%v), want %d", status, out, tt.status)
+			}
+			if status != http.StatusCreated {
+				if out["error"] == "" {
+					t.Errorf("response = %v, want an error", out)
+				}
+				return
+			}
+			b, err := s.Get(out["id"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.content != "" && b.Content != tt.content {
+				t.Errorf("content = %q, want %q", b.Content, tt.content)
+			}
+			if tt.url != "" && (b.Impetus.Raw != tt.url || !strings.Contains(b.Content, "Queue sharding at scale")) {
+				t.Errorf("beat = %+v, want a capture of %s", b, tt.url)
+			}
+			if !strings.HasPrefix(out["message"], "Saved "+b.ID) {
+				t.Errorf("message = %q", out["message"])
+			}
+		})
+	}
+
+	// The second capture from a source over its quota is held, not stored
+	if status, _ := post(t, bearer("application/json"), `{"text": "Battery low", "source": "phone"}`); status != http.StatusCreated {
+		t.Fatalf("first phone capture = %d, want 201", status)
+	}
+	status, out := post(t, bearer("application/json"), `{"text": "Battery critical", "source": "phone"}`)
+	if status != http.StatusAccepted || out["pending"] == "" || out["reason"] != pipeline.PendingQuota {
+		t.Errorf("second phone capture = %d %v, want 202 held for quota", status, out)
+	}
+	if all, _ := s.ReadAll(); strings.Contains(all[len(all)-1].Content, "critical") {
+		t.Error("held capture was stored")
+	}
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+)
+
+// Token scopes for bt serve-capture. The token in .beats/serve_token (or
+// --token) has all of them; serve_tokens in the config file adds tokens with
+// fewer, e.g. capture for a browser extension and read and link for an
+// agent.
+const (
+	ScopeRead    = "read"    // GET /beats, the digest feed and pages
+	ScopeCapture = "capture" // POST /capture
+	ScopeLink    = "link"    // POST /beats/<id>/links
+	ScopeFull    = "full"    // All of the above
+)
+
+// ServeScopes lists the scopes a token can be given.
+var ServeScopes = []string{ScopeRead, ScopeCapture, ScopeLink, ScopeFull}
+
+// maxAPIResults bounds GET /beats.
+const maxAPIResults = 200
+
+// scopedToken is a token the server accepts and what it may do.
+type scopedToken struct {
+	name   string
+	token  string
+	scopes []string
+}
+
+func (t *scopedToken) allows(scope string) bool {
+	return slices.Contains(t.scopes, ScopeFull) || slices.Contains(t.scopes, scope)
+}
+
+// serveAuth holds the tokens the server accepts.
+type serveAuth struct {
+	tokens []scopedToken
+}
+
+// newServeAuth combines the store's token, which has full access, with the
+// configured scoped ones. Tokens must be unique and name known scopes.
+func newServeAuth(primary string, configured []config.ServeToken) (*serveAuth, error) {
+	a := &serveAuth{tokens: []scopedToken{{name: "default", token: primary, scopes: []string{ScopeFull}}}}
+	for i, t := range configured {
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("serve_tokens[%d]", i)
+		}
+		token := strings.TrimSpace(t.Token)
+		if token == "" {
+			return nil, fmt.Errorf("%s: token is empty", name)
+		}
+		if len(t.Scopes) == 0 {
+			return nil, fmt.Errorf("%s: no scopes (use %s)", name, strings.Join(ServeScopes, ", "))
+		}
+		var scopes []string
+		for _, scope := range t.Scopes {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if !slices.Contains(ServeScopes, scope) {
+				return nil, fmt.Errorf("%s: unknown scope %q (use %s)", name, scope, strings.Join(ServeScopes, ", "))
+			}
+			scopes = append(scopes, scope)
+		}
+		for _, other := range a.tokens {
+			if other.token == token {
+				return nil, fmt.Errorf("%s: same token as %s", name, other.name)
+			}
+		}
+		a.tokens = append(a.tokens, scopedToken{name: name, token: token, scopes: scopes})
+	}
+	return a, nil
+}
+
+// match returns the token a request carries as a bearer token or
+// X-Beats-Token header or, where query is set (feed readers cannot set
+// headers), a ?token= parameter. Every token is compared in constant time.
+func (a *serveAuth) match(r *http.Request, query bool) *scopedToken {
+	// Tokens pasted into Shortcuts often pick up a trailing newline
+	got := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if got == "" {
+		got = strings.TrimSpace(r.Header.Get("X-Beats-Token"))
+	}
+	if got == "" && query {
+		got = strings.TrimSpace(r.URL.Query().Get("token"))
+	}
+	var found *scopedToken
+	for i := range a.tokens {
+		if got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(a.tokens[i].token)) == 1 {
+			found = &a.tokens[i]
+		}
+	}
+	return found
+}
+
+// authorize returns the request's token if it has scope, and otherwise
+// answers 401 for a missing or unknown token and 403 for one without the
+// scope.
+func (a *serveAuth) authorize(w http.ResponseWriter, r *http.Request, scope string, query bool) *scopedToken {
+	t := a.match(r, query)
+	switch {
+	case t == nil:
+		writeJSONError(w, http.StatusUnauthorized, "missing or invalid token")
+		return nil
+	case !t.allows(scope):
+		writeJSONError(w, http.StatusForbidden, fmt.Sprintf("token %q lacks the %s scope", t.name, scope))
+		return nil
+	}
+	return t
+}
+
+// describe lists the tokens and their scopes for the startup banner.
+func (a *serveAuth) describe() string {
+	var parts []string
+	for _, t := range a.tokens {
+		parts = append(parts, fmt.Sprintf("%s (%s)", t.name, strings.Join(t.scopes, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LinkRequest is the body of POST /beats/<id>/links.
+type LinkRequest struct {
+	Beads    []string `json:"beads"`
+	Relation string   `json:"relation,omitempty"` // Default seed
+}
+
+// beatsHandler serves the read and link API: GET /beats (the newest beats,
+// or with ?q= the best matches; ?limit= caps them, default 20), GET
+// /beats/<id> and POST /beats/<id>/links. Bead IDs are linked as given,
+// without checking the bead provider.
+func (c *HumanCLI) beatsHandler(auth *serveAuth, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/beats"), "/")
+		id, sub, _ := strings.Cut(path, "/")
+		switch {
+		case sub == "links" && r.Method == http.MethodPost:
+			if t := auth.authorize(w, r, ScopeLink, false); t != nil {
+				c.linkFromRequest(w, r, id, t, logger)
+			}
+		case sub == "" && r.Method == http.MethodGet:
+			if auth.authorize(w, r, ScopeRead, false) == nil {
+				return
+			}
+			if id == "" {
+				c.listBeats(w, r)
+				return
+			}
+			b, err := c.store.Get(id)
+			if err != nil {
+				writeJSONError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, b)
+		case sub == "" || sub == "links":
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		default:
+			writeJSONError(w, http.StatusNotFound, "not found")
+		}
+	})
+}
+
+// listBeats answers GET /beats.
+func (c *HumanCLI) listBeats(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = min(n, maxAPIResults)
+	}
+
+	if q := strings.TrimSpace(r.URL.Query().Get("q")); q != "" {
+		results, err := searchStore(c.store, q, "", "")
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		if results == nil {
+			results = []beat.SearchResult{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"query": q, "results": results})
+		return
+	}
+
+	beats, err := c.store.ReadAll()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	newest := make([]beat.Beat, 0, limit)
+	for i := len(beats) - 1; i >= 0 && len(newest) < limit; i-- {
+		newest = append(newest, beats[i])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"beats": newest})
+}
+
+// linkFromRequest answers POST /beats/<id>/links.
+func (c *HumanCLI) linkFromRequest(w http.ResponseWriter, r *http.Request, id string, t *scopedToken, logger *log.Logger) {
+	var req LinkRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCaptureBody)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %v", err))
+		return
+	}
+	if req.Relation != "" && !beat.ValidRelation(req.Relation) {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid relation %q (use %s)", req.Relation, strings.Join(beat.Relations, ", ")))
+		return
+	}
+	var beads []string
+	for _, bead := range req.Beads {
+		if bead = strings.TrimSpace(bead); bead != "" {
+			beads = append(beads, bead)
+		}
+	}
+	if len(beads) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "beads is required")
+		return
+	}
+
+	now := clock.Now().UTC()
+	updated, err := c.store.Update(id, func(b *beat.Beat) error {
+		for _, bead := range beads {
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: bead, Relation: req.Relation, CreatedAt: now, CreatedBy: "serve:" + t.name})
+		}
+		return nil
+	})
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if _, gerr := c.store.Get(id); gerr != nil {
+			status = http.StatusNotFound
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+	logger.Printf("linked %s to %s (token %s)", updated.ID, strings.Join(beads, ", "), t.name)
+	writeJSON(w, http.StatusOK, updated)
+}
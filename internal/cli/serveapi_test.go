@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestServeScopes(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewHumanCLI(s)
+	b, err := c.commit(&beat.ProposedBeat{Content: "Queue sharding plan"}, beat.ChannelManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := newServeAuth("admin", []config.ServeToken{
+		{Name: "extension", Token: "cap", Scopes: []string{"capture"}},
+		{Name: "agent", Token: "agent", Scopes: []string{"read", "link"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(c.serveMux(auth, log.New(io.Discard, "", 0)))
+	defer srv.Close()
+
+	do := func(method, path, token, body string) int {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	link := `{"beads": ["bd-7"], "relation": "seed"}`
+	for _, tc := range []struct {
+		method, path, token, body string
+		want                      int
+	}{
+		{"POST", "/capture", "cap", `{"text": "from the extension"}`, http.StatusCreated},
+		{"GET", "/beats", "cap", "", http.StatusForbidden},
+		{"POST", "/beats/" + b.ID + "/links", "cap", link, http.StatusForbidden},
+		{"POST", "/capture", "agent", `{"text": "from the agent"}`, http.StatusForbidden},
+		{"GET", "/beats?q=sharding", "agent", "", http.StatusOK},
+		{"GET", "/beats/" + b.ID, "agent", "", http.StatusOK},
+		{"POST", "/beats/" + b.ID + "/links", "agent", link, http.StatusOK},
+		{"POST", "/beats/beat-19990101-001/links", "agent", link, http.StatusNotFound},
+		{"GET", "/beats", "nope", "", http.StatusUnauthorized},
+		{"GET", "/beats?limit=1", "admin", "", http.StatusOK},
+	} {
+		if got := do(tc.method, tc.path, tc.token, tc.body); got != tc.want {
+			t.Errorf("%s %s with %q = %d, want %d", tc.method, tc.path, tc.token, got, tc.want)
+		}
+	}
+
+	linked, err := s.Get(b.ID)
+	if err != nil || !linked.LinkedBeads.Has("bd-7") || linked.LinkedBeads[0].CreatedBy != "serve:agent" {
+		t.Errorf("linked beat = %+v, %v; want bd-7 linked by the agent token", linked, err)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/beats?limit=1", nil)
+	req.Header.Set("X-Beats-Token", "agent")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var list struct{ Beats []beat.Beat }
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil || len(list.Beats) != 1 || list.Beats[0].Content != "from the extension" {
+		t.Errorf("GET /beats?limit=1 = %+v, %v; want the newest beat", list, err)
+	}
+
+	for _, bad := range [][]config.ServeToken{
+		{{Name: "x", Token: "t", Scopes: []string{"write"}}},
+		{{Name: "x", Token: "t"}},
+		{{Name: "x", Token: "admin", Scopes: []string{"read"}}},
+	} {
+		if _, err := newServeAuth("admin", bad); err == nil {
+			t.Errorf("newServeAuth(%+v) succeeded, want an error", bad)
+		}
+	}
+}
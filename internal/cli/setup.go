@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/importer"
+	"github.com/bierlingm/beats/internal/llm"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// SetupOptions configures bt setup.
+type SetupOptions struct {
+	In      io.Reader // Answers, one per line; at the end of input every default is taken
+	Yes     bool      // Take every default without asking
+	Project string    // Directory a per-project store would be created in (default: the working directory)
+}
+
+// setupPrompt asks the questions of bt setup. An empty answer takes the
+// default.
+type setupPrompt struct {
+	in  *bufio.Reader
+	yes bool
+}
+
+func (p *setupPrompt) ask(question, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	if p.yes {
+		fmt.Println(def)
+		return def
+	}
+	answer, err := p.in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err != nil && answer == "" {
+		fmt.Println(def)
+		return def
+	}
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+func (p *setupPrompt) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		switch strings.ToLower(p.ask(question+" ("+hint+")", "")) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
+
+// choose offers numbered options and returns the index picked.
+func (p *setupPrompt) choose(question string, options []string, def int) int {
+	fmt.Println(question)
+	for i, o := range options {
+		fmt.Printf("  %d) %s\n", i+1, o)
+	}
+	for {
+		n, err := strconv.Atoi(p.ask("Choose", strconv.Itoa(def+1)))
+		if err == nil && n >= 1 && n <= len(options) {
+			return n - 1
+		}
+	}
+}
+
+// Setup walks through a first configuration: where beats are stored,
+// whether Ollama answers and has the models, which hooks to turn on and
+// whether to import existing notes. Settings go to the config file and the
+// global hooks.json, so they apply to every store.
+func Setup(opts SetupOptions) error {
+	if opts.In == nil {
+		opts.In = os.Stdin
+	}
+	p := &setupPrompt{in: bufio.NewReader(opts.In), yes: opts.Yes}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Setting up beats. Settings are saved to %s.\n\n", cfg.Path)
+
+	// 1. Store
+	settings := map[string]string{}
+	var dir string
+	where := p.choose("Where should beats be stored?", []string{
+		"One global store for everything, " + cfg.Store,
+		"A store for this project, with captures from here going to it",
+	}, 0)
+	if where == 0 {
+		dir = config.ExpandHome(p.ask("Global store directory", cfg.Store))
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		settings["store"] = dir
+		if cfg.Sources["store"] != config.SourceFile && cfg.Sources["store"] != config.SourceDefault {
+			fmt.Printf("Note: %s is set and overrides the store setting.\n", strings.TrimPrefix(cfg.Sources["store"], config.SourceEnv+":"))
+		}
+	} else {
+		project := opts.Project
+		if project == "" {
+			if project, err = os.Getwd(); err != nil {
+				return err
+			}
+		}
+		dir = filepath.Join(project, store.DefaultBeatsDir)
+	}
+	s, err := store.NewJSONLStore(dir)
+	if err != nil {
+		return fmt.Errorf("failed to create the store: %w", err)
+	}
+	if where == 1 {
+		reg, err := store.LoadRegistry()
+		if err != nil {
+			return err
+		}
+		name := ""
+		for _, e := range reg.Stores {
+			if e.Path == dir {
+				name = e.Name
+			}
+		}
+		if name == "" {
+			entry, err := reg.Add("", dir)
+			if err != nil {
+				return err
+			}
+			if err := reg.Save(); err != nil {
+				return err
+			}
+			name = entry.Name
+			fmt.Printf("Registered %s as store %q.\n", dir, name)
+		}
+		fmt.Printf("bt add in %s writes to it; other commands take --store %s.\n", filepath.Dir(dir), name)
+	}
+	fmt.Printf("Store: %s\n\n", dir)
+
+	// 2. Ollama
+	ollamaURL := p.ask("Ollama server (for semantic search and summaries)", cfg.OllamaURL)
+	settings["ollama_url"] = ollamaURL
+	client := llm.New(ollamaURL).WithTimeout(3 * time.Second)
+	client.Retries = 0
+	models, err := client.Models(context.Background())
+	if err != nil {
+		fmt.Printf("Ollama does not answer at %s. Beats works without it, but semantic search,\n", ollamaURL)
+		fmt.Println("duplicate detection and session summaries need it: install it from https://ollama.com")
+		fmt.Println("and run bt setup again.")
+	} else {
+		fmt.Printf("Ollama answers with %d model(s).\n", len(models))
+		for _, m := range []struct{ key, question, def string }{
+			{"embed_model", "Embedding model", cfg.EmbedModel},
+			{"llm_model", "Model for summaries", cfg.LLMModel},
+		} {
+			model := p.ask(m.question, m.def)
+			settings[m.key] = model
+			if llm.HasModel(models, model) {
+				continue
+			}
+			if !p.confirm(fmt.Sprintf("%s is not pulled. Pull it now?", model), true) {
+				fmt.Printf("Skipped; run 'ollama pull %s' before using it.\n", model)
+				continue
+			}
+			fmt.Printf("Pulling %s...\n", model)
+			if err := llm.New(ollamaURL).Pull(context.Background(), model); err != nil {
+				fmt.Printf("Pull failed: %v\n", err)
+				continue
+			}
+			fmt.Printf("Pulled %s.\n", model)
+		}
+	}
+	if err := config.Update(settings); err != nil {
+		return fmt.Errorf("failed to save %s: %w", cfg.Path, err)
+	}
+	fmt.Println()
+
+	// 3. Hooks
+	for _, h := range []struct{ name, question string }{
+		{"session_end", "Turn finished coding sessions into beats (session_end hook)?"},
+		{"synthesis", "Flag when enough beats have piled up to synthesize (synthesis hook)?"},
+	} {
+		enabled := p.confirm(h.question, true)
+		if err := hooks.SetGlobalHookEnabled(h.name, enabled); err != nil {
+			return fmt.Errorf("failed to set the %s hook: %w", h.name, err)
+		}
+	}
+	fmt.Printf("Hooks saved to %s, shared by every store.\n\n", hooks.GlobalHooksPath())
+
+	// 4. Import
+	source := p.choose("Import existing notes?", []string{"No", "An Obsidian vault", "A folder of Markdown files", "A Logseq graph"}, 0)
+	if source > 0 {
+		path := config.ExpandHome(p.ask("Path", ""))
+		if path == "" {
+			fmt.Println("No path given; skipped. See bt import-obsidian, import-md and import-logseq.")
+		} else {
+			c := NewHumanCLI(s)
+			switch source {
+			case 1:
+				err = c.ImportObsidian(path, importer.ObsidianOptions{}, false)
+			case 2:
+				err = c.ImportMarkdown(path, importer.MarkdownOptions{Recursive: true}, false)
+			case 3:
+				err = c.ImportLogseq(path, importer.OutlineOptions{}, false)
+			}
+			if err != nil {
+				fmt.Printf("Import failed: %v\n", err)
+			}
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Done. Next:")
+	fmt.Println(`  bt add "first thought"    Capture a beat`)
+	if models != nil {
+		fmt.Println("  bt embed                  Index beats for semantic search")
+	}
+	fmt.Println("  bt status                 Store, Ollama and hooks at a glance")
+	fmt.Println("  bt config show            See every setting and where it comes from")
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+func TestSetup(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+	t.Setenv("BEATS_DIR", "")
+	t.Setenv("BEATS_OLLAMA_URL", "")
+	t.Setenv("OLLAMA_HOST", "")
+
+	var pulled []string
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models": [{"name": "nomic-embed-text:latest"}]}`))
+		case "/api/pull":
+			var in struct{ Model string }
+			json.NewDecoder(r.Body).Decode(&in)
+			pulled = append(pulled, in.Model)
+			w.Write([]byte(`{"status": "success"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ollama.Close()
+
+	dir := filepath.Join(t.TempDir(), "beats")
+	answers := strings.Join([]string{
+		"1",        // One global store
+		dir,        // in dir
+		ollama.URL, // Ollama server
+		"",         // Embedding model: the default, already pulled
+		"",         // Model for summaries: the default
+		"y",        // Pull it
+		"n",        // No session_end hook
+		"",         // The synthesis hook, by default
+		"1",        // Import nothing
+	}, "\n") + "\n"
+	if err := Setup(SetupOptions{In: strings.NewReader(answers)}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Store != dir || cfg.OllamaURL != ollama.URL || cfg.LLMModel != config.DefaultLLMModel || cfg.Sources["store"] != config.SourceFile {
+		t.Errorf("config = store %q, ollama %q, llm %q (%v)", cfg.Store, cfg.OllamaURL, cfg.LLMModel, cfg.Sources)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("store not created: %v", err)
+	}
+	if len(pulled) != 1 || pulled[0] != config.DefaultLLMModel {
+		t.Errorf("pulled %v, want only %s", pulled, config.DefaultLLMModel)
+	}
+	effective, err := hooks.LoadEffectiveHooks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synthesis, _ := effective.Config["synthesis"].(map[string]interface{})
+	if hooks.GetSessionEndConfig(dir).Enabled || synthesis["enabled"] != true || effective.Sources["synthesis"] != hooks.SourceGlobal {
+		t.Errorf("hooks = %+v, want synthesis on and session_end off, globally", effective)
+	}
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/entity"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// SnapshotSchemaVersion is the version of the snapshot format. It changes
+// when a field is removed or changes meaning; new fields keep it.
+const SnapshotSchemaVersion = 1
+
+// Export formats producing a snapshot of the whole store.
+const (
+	FormatSnapshot       = "snapshot"        // One JSON document
+	FormatSnapshotNDJSON = "snapshot-ndjson" // A header line, then one record per line
+)
+
+// Snapshot is a whole store as one document: every beat in file order, plus
+// the entity registry and bead links derived from them and the alias
+// registry. Importing it into an empty store rebuilds the same beats.jsonl.
+type Snapshot struct {
+	SnapshotHeader
+	Beats    []beat.Beat      `json:"beats"`
+	Entities []SnapshotEntity `json:"entities"`
+	Links    []SnapshotLink   `json:"links"`
+}
+
+// SnapshotHeader is what a snapshot holds besides its beats, entities and
+// links: the first line of an NDJSON snapshot.
+type SnapshotHeader struct {
+	SchemaVersion int                 `json:"schema_version"`
+	ExportedAt    time.Time           `json:"exported_at"`
+	BeatCount     int                 `json:"beat_count"`
+	Aliases       map[string][]string `json:"aliases"`
+}
+
+// SnapshotEntity is an entity named by the store's beats.
+type SnapshotEntity struct {
+	Label    string   `json:"label"`
+	Category string   `json:"category"`
+	Beats    []string `json:"beats"`
+}
+
+// SnapshotLink is a beat's link to a bead.
+type SnapshotLink struct {
+	BeatID string `json:"beat_id"`
+	beat.BeadLink
+}
+
+// snapshotRecord is one line of an NDJSON snapshot. The first line is the
+// header, holding everything but the beats, entities and links; each later
+// line holds one of them.
+type snapshotRecord struct {
+	Record string          `json:"record"` // header, beat, entity or link
+	Header *SnapshotHeader `json:"header,omitempty"`
+	Beat   *beat.Beat      `json:"beat,omitempty"`
+	Entity *SnapshotEntity `json:"entity,omitempty"`
+	Link   *SnapshotLink   `json:"link,omitempty"`
+}
+
+// buildSnapshot collects the whole store. Entities are sorted by category
+// and label, links by beat and bead, so the same store always gives the
+// same snapshot.
+func buildSnapshot(s *store.JSONLStore) (*Snapshot, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := entity.LoadAliasNames(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	if aliases == nil {
+		aliases = map[string][]string{}
+	}
+	snap := &Snapshot{
+		SnapshotHeader: SnapshotHeader{
+			SchemaVersion: SnapshotSchemaVersion,
+			ExportedAt:    clock.Now().UTC(),
+			BeatCount:     len(beats),
+			Aliases:       aliases,
+		},
+		Beats:    beats,
+		Entities: []SnapshotEntity{},
+		Links:    []SnapshotLink{},
+	}
+	if snap.Beats == nil {
+		snap.Beats = []beat.Beat{}
+	}
+
+	registry := make(map[string]*SnapshotEntity)
+	for _, b := range beats {
+		for _, e := range b.Entities {
+			key := entity.Key(e)
+			se, ok := registry[key]
+			if !ok {
+				se = &SnapshotEntity{Label: e.Label, Category: e.Category}
+				registry[key] = se
+			}
+			if n := len(se.Beats); n == 0 || se.Beats[n-1] != b.ID {
+				se.Beats = append(se.Beats, b.ID)
+			}
+		}
+		for _, link := range b.LinkedBeads {
+			snap.Links = append(snap.Links, SnapshotLink{BeatID: b.ID, BeadLink: link})
+		}
+	}
+	for _, se := range registry {
+		snap.Entities = append(snap.Entities, *se)
+	}
+	sort.Slice(snap.Entities, func(i, j int) bool {
+		a, b := snap.Entities[i], snap.Entities[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return strings.ToLower(a.Label) < strings.ToLower(b.Label)
+	})
+	sort.SliceStable(snap.Links, func(i, j int) bool {
+		a, b := snap.Links[i], snap.Links[j]
+		if a.BeatID != b.BeatID {
+			return a.BeatID < b.BeatID
+		}
+		return a.BeadID < b.BeadID
+	})
+	return snap, nil
+}
+
+// writeSnapshotNDJSON streams a snapshot as a header line followed by one
+// line per beat, entity and link.
+func writeSnapshotNDJSON(w io.Writer, snap *Snapshot) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(snapshotRecord{Record: "header", Header: &snap.SnapshotHeader}); err != nil {
+		return err
+	}
+	for i := range snap.Beats {
+		if err := enc.Encode(snapshotRecord{Record: "beat", Beat: &snap.Beats[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range snap.Entities {
+		if err := enc.Encode(snapshotRecord{Record: "entity", Entity: &snap.Entities[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range snap.Links {
+		if err := enc.Encode(snapshotRecord{Record: "link", Link: &snap.Links[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSnapshotNDJSON reads the records after an NDJSON snapshot's header.
+func readSnapshotNDJSON(dec *json.Decoder, header *SnapshotHeader) (*Snapshot, error) {
+	snap := Snapshot{SnapshotHeader: *header}
+	for {
+		var rec snapshotRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid snapshot record: %w", err)
+		}
+		switch {
+		case rec.Record == "beat" && rec.Beat != nil:
+			snap.Beats = append(snap.Beats, *rec.Beat)
+		case rec.Record == "entity" && rec.Entity != nil:
+			snap.Entities = append(snap.Entities, *rec.Entity)
+		case rec.Record == "link" && rec.Link != nil:
+			snap.Links = append(snap.Links, *rec.Link)
+		default:
+			return nil, fmt.Errorf("invalid snapshot record %q", rec.Record)
+		}
+	}
+	return &snap, nil
+}
+
+// decodeImport reads --robot-import input: a snapshot document, an NDJSON
+// snapshot, or a list of beats to add. Exactly one of the results is set.
+func decodeImport(input io.Reader) (*Snapshot, *ImportInput, error) {
+	dec := json.NewDecoder(input)
+	var first json.RawMessage
+	if err := dec.Decode(&first); err != nil {
+		return nil, nil, err
+	}
+	var probe struct {
+		SchemaVersion int             `json:"schema_version"`
+		Record        string          `json:"record"`
+		Header        *SnapshotHeader `json:"header"`
+	}
+	if err := json.Unmarshal(first, &probe); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case probe.Record == "header" && probe.Header != nil:
+		snap, err := readSnapshotNDJSON(dec, probe.Header)
+		return snap, nil, err
+	case probe.SchemaVersion > 0:
+		var snap Snapshot
+		err := json.Unmarshal(first, &snap)
+		return &snap, nil, err
+	}
+	var in ImportInput
+	if err := json.Unmarshal(first, &in); err != nil {
+		return nil, nil, err
+	}
+	return nil, &in, nil
+}
+
+// restoreSnapshot rebuilds a store from a snapshot. The store must be
+// empty: a snapshot is a copy, not a merge. Beats keep their IDs and
+// timestamps, and the alias registry is written when the snapshot has one.
+// Entities and links are derived from the beats, so they are not read back.
+func restoreSnapshot(s *store.JSONLStore, snap *Snapshot) error {
+	if snap.SchemaVersion > SnapshotSchemaVersion {
+		return fmt.Errorf("snapshot schema version %d is newer than this bt supports (%d)", snap.SchemaVersion, SnapshotSchemaVersion)
+	}
+	if snap.BeatCount != len(snap.Beats) {
+		return fmt.Errorf("snapshot is incomplete: %d of %d beats", len(snap.Beats), snap.BeatCount)
+	}
+	existing, err := s.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("store already has %d beats; import a snapshot into an empty store", len(existing))
+	}
+	seen := make(map[string]bool, len(snap.Beats))
+	beats := make([]*beat.Beat, len(snap.Beats))
+	for i := range snap.Beats {
+		b := &snap.Beats[i]
+		if b.ID == "" || seen[b.ID] {
+			return fmt.Errorf("snapshot beat %d has a missing or duplicate ID %q", i+1, b.ID)
+		}
+		seen[b.ID] = true
+		beats[i] = b
+	}
+	if err := s.AppendBulk(beats); err != nil {
+		return err
+	}
+	if len(snap.Aliases) > 0 {
+		return entity.SaveAliasNames(s.Dir(), snap.Aliases)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/entity"
+	"github.com/bierlingm/beats/internal/fixtures"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	clock.Set(time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC))
+	defer clock.Reset()
+
+	src, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AppendBulk(fixtures.Generate(fixtures.Options{Seed: 7, Count: 20})); err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SaveAliasNames(src.Dir(), map[string][]string{"Ada Lovelace": {"Ada"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	export := func(s *store.JSONLStore, format string) []byte {
+		t.Helper()
+		var out bytes.Buffer
+		SetJSONOutput(&out)
+		defer SetJSONOutput(nil)
+		if err := NewRobotCLI(s).Export(strings.NewReader(`{"format":"` + format + `"}`)); err != nil {
+			t.Fatal(err)
+		}
+		return out.Bytes()
+	}
+	restore := func(snapshot []byte) *store.JSONLStore {
+		t.Helper()
+		dst, err := store.NewJSONLStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		SetJSONOutput(&out)
+		defer SetJSONOutput(nil)
+		if err := NewRobotCLI(dst).Import(bytes.NewReader(snapshot)); err != nil || !strings.Contains(out.String(), `"imported": 20`) {
+			t.Fatalf("import: %v %s", err, out.String())
+		}
+		return dst
+	}
+	read := func(path string) []byte {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	doc := export(src, FormatSnapshot)
+	for _, format := range []string{FormatSnapshot, FormatSnapshotNDJSON} {
+		dst := restore(export(src, format))
+		if !bytes.Equal(read(dst.Path()), read(src.Path())) {
+			t.Errorf("%s: restored beats.jsonl differs", format)
+		}
+		if !bytes.Equal(read(filepath.Join(dst.Dir(), entity.AliasesFile)), read(filepath.Join(src.Dir(), entity.AliasesFile))) {
+			t.Errorf("%s: restored aliases differ", format)
+		}
+		if got := export(dst, FormatSnapshot); !bytes.Equal(got, doc) {
+			t.Errorf("%s: snapshot of the restored store differs", format)
+		}
+	}
+
+	// A snapshot is not merged into a store with beats
+	var out bytes.Buffer
+	SetJSONOutput(&out)
+	defer SetJSONOutput(nil)
+	NewRobotCLI(src).Import(bytes.NewReader(doc))
+	if !strings.Contains(out.String(), "empty store") {
+		t.Errorf("import into a full store: %s", out.String())
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// ChannelStats is what one capture channel brought in and how much of it
+// went on to be used. A beat counts as signal once it is linked to a bead
+// or referred to by another beat.
+type ChannelStats struct {
+	Channel     string     `json:"channel"`
+	Beats       int        `json:"beats"`
+	Recent      int        `json:"recent"`    // Captured in the last Days
+	Linked      int        `json:"linked"`    // Linked to a bead
+	Cited       int        `json:"cited"`     // Referred to by another beat
+	Annotated   int        `json:"annotated"` // With at least one annotation
+	Signal      int        `json:"signal"`    // Linked or cited
+	SignalRatio float64    `json:"signal_ratio"`
+	Pending     int        `json:"pending"` // Held for review now
+	LastCapture *time.Time `json:"last_capture,omitempty"`
+}
+
+// ChannelReport breaks a store down by capture channel, busiest first.
+type ChannelReport struct {
+	Days     int            `json:"days"`
+	Channels []ChannelStats `json:"channels"`
+}
+
+// proposedChannel is the channel a pending capture was recorded with.
+func proposedChannel(p beat.ProposedBeat) string {
+	if c := p.Impetus.Meta[beat.ChannelMeta]; c != "" {
+		return c
+	}
+	return beat.ChannelUnknown
+}
+
+// StatsByChannel counts beats and their signal per capture channel.
+func StatsByChannel(s *store.JSONLStore, days int, now time.Time) (*ChannelReport, error) {
+	if days <= 0 {
+		days = DefaultStatsDays
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := pipeline.LoadPending(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	backlinks := store.BuildBacklinks(beats)
+	since := now.AddDate(0, 0, -days)
+	byChannel := make(map[string]*ChannelStats)
+	get := func(channel string) *ChannelStats {
+		cs, ok := byChannel[channel]
+		if !ok {
+			cs = &ChannelStats{Channel: channel}
+			byChannel[channel] = cs
+		}
+		return cs
+	}
+	for i := range beats {
+		b := &beats[i]
+		cs := get(b.Channel())
+		cs.Beats++
+		if b.CreatedAt.After(since) {
+			cs.Recent++
+		}
+		linked, cited := len(b.LinkedBeads) > 0, len(backlinks[b.ID]) > 0
+		if linked {
+			cs.Linked++
+		}
+		if cited {
+			cs.Cited++
+		}
+		if linked || cited {
+			cs.Signal++
+		}
+		if len(b.Annotations) > 0 {
+			cs.Annotated++
+		}
+		if cs.LastCapture == nil || b.CreatedAt.After(*cs.LastCapture) {
+			at := b.CreatedAt
+			cs.LastCapture = &at
+		}
+	}
+	for _, pb := range pending {
+		get(proposedChannel(pb.Beat)).Pending++
+	}
+
+	r := &ChannelReport{Days: days, Channels: []ChannelStats{}}
+	for _, cs := range byChannel {
+		if cs.Beats > 0 {
+			cs.SignalRatio = float64(cs.Signal) / float64(cs.Beats)
+		}
+		r.Channels = append(r.Channels, *cs)
+	}
+	sort.Slice(r.Channels, func(i, j int) bool {
+		if r.Channels[i].Beats != r.Channels[j].Beats {
+			return r.Channels[i].Beats > r.Channels[j].Beats
+		}
+		return r.Channels[i].Channel < r.Channels[j].Channel
+	})
+	return r, nil
+}
+
+// StatsByChannel prints beats and signal per capture channel.
+func (c *HumanCLI) StatsByChannel(days int, jsonOut bool) error {
+	r, err := StatsByChannel(c.store, days, clock.Now())
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(r)
+	}
+	if len(r.Channels) == 0 {
+		fmt.Println("No beats yet.")
+		return nil
+	}
+	fmt.Printf("%-10s %6s %6s %6s %6s %7s %7s  %s\n", "CHANNEL", "BEATS", fmt.Sprintf("%dD", r.Days), "LINKED", "CITED", "SIGNAL", "PENDING", "LAST CAPTURE")
+	for _, cs := range r.Channels {
+		last := "-"
+		if cs.LastCapture != nil {
+			last = displayDate(*cs.LastCapture)
+		}
+		fmt.Printf("%-10s %6d %6d %6d %6d %6.0f%% %7d  %s\n", cs.Channel, cs.Beats, cs.Recent, cs.Linked, cs.Cited, cs.SignalRatio*100, cs.Pending, last)
+	}
+	fmt.Println("\nSignal: linked to a bead or referred to by another beat.")
+	return nil
+}
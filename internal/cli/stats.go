@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// DefaultStatsDays is how many days of daily counts stats report.
+const DefaultStatsDays = 30
+
+// StatsOptions configures capture statistics.
+type StatsOptions struct {
+	Days int       // Daily counts for this many days, today included
+	Now  time.Time // Defaults to the current time
+}
+
+// DayCount is the number of beats captured on one day.
+type DayCount struct {
+	Day   string `json:"day"`
+	Beats int    `json:"beats"`
+}
+
+// CaptureStats describes the capture habit: counts, streaks and recent days.
+// Days are calendar days in the display time zone; digest beats are not
+// captures and are left out.
+type CaptureStats struct {
+	TotalBeats    int        `json:"total_beats"`
+	Today         int        `json:"today"`
+	LastSevenDays int        `json:"last_7_days"`
+	CurrentStreak int        `json:"current_streak"` // Consecutive days up to today, or yesterday until today's first beat
+	CapturedToday bool       `json:"captured_today"`
+	LongestStreak int        `json:"longest_streak"`
+	LongestEnded  string     `json:"longest_streak_ended,omitempty"`
+	ActiveDays    int        `json:"active_days"`
+	PerActiveDay  float64    `json:"beats_per_active_day"`
+	LastCapture   *time.Time `json:"last_capture,omitempty"`
+	DaysSinceLast int        `json:"days_since_last_capture"`
+	Daily         []DayCount `json:"daily"` // Oldest first
+}
+
+// Stats computes capture statistics for a store.
+func Stats(s *store.JSONLStore, opts StatsOptions) (*CaptureStats, error) {
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now()
+	}
+	if opts.Days <= 0 {
+		opts.Days = DefaultStatsDays
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	now := opts.Now.In(DisplayLocation)
+	today := now.Format("2006-01-02")
+	perDay := make(map[string]int)
+	st := &CaptureStats{Daily: []DayCount{}}
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] == DigestKind {
+			continue
+		}
+		st.TotalBeats++
+		perDay[displayDate(b.CreatedAt)]++
+		if st.LastCapture == nil || b.CreatedAt.After(*st.LastCapture) {
+			at := b.CreatedAt
+			st.LastCapture = &at
+		}
+	}
+
+	st.Today = perDay[today]
+	st.CapturedToday = st.Today > 0
+	for i := 0; i < 7; i++ {
+		st.LastSevenDays += perDay[now.AddDate(0, 0, -i).Format("2006-01-02")]
+	}
+	for i := opts.Days - 1; i >= 0; i-- {
+		day := now.AddDate(0, 0, -i).Format("2006-01-02")
+		st.Daily = append(st.Daily, DayCount{Day: day, Beats: perDay[day]})
+	}
+
+	// The streak stays alive through a day until that day's first beat is due
+	start := now
+	if !st.CapturedToday {
+		start = now.AddDate(0, 0, -1)
+	}
+	for d := start; perDay[d.Format("2006-01-02")] > 0; d = d.AddDate(0, 0, -1) {
+		st.CurrentStreak++
+	}
+
+	st.ActiveDays = len(perDay)
+	if st.ActiveDays > 0 {
+		st.PerActiveDay = float64(st.TotalBeats) / float64(st.ActiveDays)
+	}
+	st.LongestStreak, st.LongestEnded = longestStreak(perDay)
+	if st.LastCapture != nil {
+		last, _ := time.ParseInLocation("2006-01-02", displayDate(*st.LastCapture), DisplayLocation)
+		midnight, _ := time.ParseInLocation("2006-01-02", today, DisplayLocation)
+		st.DaysSinceLast = int(midnight.Sub(last).Hours()/24 + 0.5)
+	}
+	return st, nil
+}
+
+// longestStreak finds the longest run of consecutive days with beats and
+// the day it ended; the latest run wins a tie.
+func longestStreak(perDay map[string]int) (int, string) {
+	longest, ended := 0, ""
+	for day := range perDay {
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			continue
+		}
+		// Count runs from their first day only
+		if perDay[t.AddDate(0, 0, -1).Format("2006-01-02")] > 0 {
+			continue
+		}
+		n, last := 0, day
+		for d := t; perDay[d.Format("2006-01-02")] > 0; d = d.AddDate(0, 0, 1) {
+			n, last = n+1, d.Format("2006-01-02")
+		}
+		if n > longest || (n == longest && last > ended) {
+			longest, ended = n, last
+		}
+	}
+	return longest, ended
+}
+
+// StreakLine is the short streak note shown after bt add.
+func (st *CaptureStats) StreakLine() string {
+	if st.CurrentStreak <= 1 {
+		return ""
+	}
+	line := fmt.Sprintf("Day %d streak", st.CurrentStreak)
+	if st.CurrentStreak >= st.LongestStreak {
+		line += " (your longest)"
+	}
+	return line
+}
+
+// Stats prints capture statistics.
+func (c *HumanCLI) Stats(days int, jsonOut bool) error {
+	st, err := Stats(c.store, StatsOptions{Days: days})
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(st)
+	}
+
+	fmt.Printf("Beats: %d total, %d today, %d in the last 7 days\n", st.TotalBeats, st.Today, st.LastSevenDays)
+	streak := fmt.Sprintf("Streak: %d day(s)", st.CurrentStreak)
+	if st.CurrentStreak > 0 && !st.CapturedToday {
+		streak += ", nothing captured today yet"
+	}
+	if st.LongestStreak > 0 {
+		streak += fmt.Sprintf(" (longest %d, ended %s)", st.LongestStreak, st.LongestEnded)
+	}
+	fmt.Println(streak)
+	if st.LastCapture != nil {
+		fmt.Printf("Last capture: %s (%d day(s) ago)\n", displayTime(*st.LastCapture), st.DaysSinceLast)
+	}
+	if st.ActiveDays > 0 {
+		fmt.Printf("Active days: %d, %.1f beats per active day\n", st.ActiveDays, st.PerActiveDay)
+	}
+
+	peak := 0
+	for _, d := range st.Daily {
+		if d.Beats > peak {
+			peak = d.Beats
+		}
+	}
+	fmt.Printf("\nLast %d days:\n", len(st.Daily))
+	for _, d := range st.Daily {
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("#", (d.Beats*30+peak-1)/peak)
+		}
+		fmt.Printf("  %s  %3d %s\n", d.Day, d.Beats, bar)
+	}
+	return nil
+}
+
+// Stats outputs capture statistics as JSON, for agents that nudge when
+// capturing drops off.
+func (c *RobotCLI) Stats() error {
+	st, err := Stats(c.store, StatsOptions{})
+	if err != nil {
+		return outputError("failed to compute stats", err)
+	}
+	return outputJSON(st)
+}
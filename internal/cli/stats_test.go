@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestStatsStreaks(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var beats []*beat.Beat
+	add := func(id string, daysAgo int, meta map[string]string) {
+		at := now.AddDate(0, 0, -daysAgo)
+		beats = append(beats, &beat.Beat{ID: id, CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Label: "Manual entry", Meta: meta}, Content: id})
+	}
+	// A four-day run ending 10 days ago, and the current run of yesterday and the day before
+	for i, d := range []int{13, 12, 11, 10, 2, 1, 1} {
+		add(beat.GenerateIDWithSequence(now.AddDate(0, 0, -d), i+1), d, nil)
+	}
+	add("beat-20260320-900", 0, map[string]string{"kind": DigestKind}) // Not a capture
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := Stats(s, StatsOptions{Now: now, Days: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.TotalBeats != 7 || st.Today != 0 || st.CapturedToday || st.LastSevenDays != 3 {
+		t.Errorf("counts = %+v", st)
+	}
+	if st.CurrentStreak != 2 || st.LongestStreak != 4 || st.LongestEnded != "2026-03-10" || st.DaysSinceLast != 1 {
+		t.Errorf("streaks = current %d, longest %d ended %s, %d day(s) since last", st.CurrentStreak, st.LongestStreak, st.LongestEnded, st.DaysSinceLast)
+	}
+	if len(st.Daily) != 7 || st.Daily[6].Day != "2026-03-20" || st.Daily[5].Beats != 2 {
+		t.Errorf("daily = %+v", st.Daily)
+	}
+	if line := st.StreakLine(); line != "Day 2 streak" {
+		t.Errorf("StreakLine() = %q", line)
+	}
+}
+
+func TestTrends(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ten beats 31-59 days ago, six in the last 30; half of the recent ones linked
+	var beats []*beat.Beat
+	for i := range 16 {
+		daysAgo := 31 + i*3
+		if i >= 10 {
+			daysAgo = (i - 10) * 4
+		}
+		at := now.AddDate(0, 0, -daysAgo)
+		b := &beat.Beat{ID: beat.GenerateIDWithSequence(at, i+1), CreatedAt: at, UpdatedAt: at, Content: "x"}
+		if i >= 13 {
+			b.LinkedBeads = beat.BeadLinks{{BeadID: "bd-1"}}
+		}
+		beats = append(beats, b)
+	}
+	if err := s.AppendBulk(beats[:10]); err != nil {
+		t.Fatal(err)
+	}
+
+	// A snapshot a month ago, before any recent beat or link
+	old, err := TakeStatsSnapshot(s, now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.TotalBeats != 10 || old.LinkRatio != 0 {
+		t.Errorf("old snapshot = %+v", old)
+	}
+	data, _ := json.Marshal(old)
+	if err := os.WriteFile(filepath.Join(s.Dir(), MetricsFile), append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendBulk(beats[10:]); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Trends(s, 30, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Baseline == nil || len(r.Trends) != 7 {
+		t.Fatalf("report = %+v", r)
+	}
+	byMetric := map[string]Trend{}
+	for _, tr := range r.Trends {
+		byMetric[tr.Metric] = tr
+	}
+	capture := byMetric["capture_rate"]
+	if capture.Now != 6 || capture.Then != 10 || capture.Change == nil || *capture.Change != -40 {
+		t.Errorf("capture_rate = %+v", capture)
+	}
+	if want := "Capture rate down 40% vs last month (6 beats in the last 30 days, was 10)"; capture.Summary != want {
+		t.Errorf("summary = %q, want %q", capture.Summary, want)
+	}
+	if link := byMetric["link_ratio"]; link.Then != 0 || link.Now != 3.0/16 {
+		t.Errorf("link_ratio = %+v", link)
+	}
+}
+
+func TestStatsByChannel(t *testing.T) {
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var beats []*beat.Beat
+	add := func(daysAgo int, meta map[string]string, edit func(*beat.Beat)) *beat.Beat {
+		at := now.AddDate(0, 0, -daysAgo)
+		b := &beat.Beat{ID: beat.GenerateIDWithSequence(at, len(beats)+1), CreatedAt: at, UpdatedAt: at, Impetus: beat.Impetus{Meta: meta}}
+		if edit != nil {
+			edit(b)
+		}
+		beats = append(beats, b)
+		return b
+	}
+	manual := map[string]string{beat.ChannelMeta: beat.ChannelManual}
+	web := map[string]string{beat.ChannelMeta: beat.ChannelWeb}
+	cited := add(40, manual, func(b *beat.Beat) { b.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1"}) })
+	add(1, manual, func(b *beat.Beat) {
+		b.References = []beat.Reference{{Kind: "beat", Locator: cited.ID}}
+	})
+	add(2, web, nil)
+	add(3, web, nil)
+	add(4, web, nil)
+	add(5, map[string]string{"kind": DigestKind}, nil) // Inferred as generated
+	add(6, nil, nil)                                   // Nothing to infer from
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := StatsByChannel(s, 30, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byChannel := make(map[string]ChannelStats)
+	var order []string
+	for _, cs := range r.Channels {
+		byChannel[cs.Channel] = cs
+		order = append(order, cs.Channel)
+	}
+	if len(order) != 4 || order[0] != beat.ChannelWeb || order[1] != beat.ChannelManual {
+		t.Fatalf("channels = %v, want web and manual first, then generated and unknown", order)
+	}
+	if m := byChannel[beat.ChannelManual]; m.Beats != 2 || m.Recent != 1 || m.Linked != 1 || m.Cited != 1 || m.Signal != 1 || m.SignalRatio != 0.5 {
+		t.Errorf("manual = %+v", m)
+	}
+	if w := byChannel[beat.ChannelWeb]; w.Beats != 3 || w.Signal != 0 {
+		t.Errorf("web = %+v", w)
+	}
+	if byChannel[beat.ChannelGenerated].Beats != 1 || byChannel[beat.ChannelUnknown].Beats != 1 {
+		t.Errorf("generated and unknown = %+v, %+v", byChannel[beat.ChannelGenerated], byChannel[beat.ChannelUnknown])
+	}
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/daemon"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/llm"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// statusProbeTimeout bounds each network check bt status makes.
+const statusProbeTimeout = 3 * time.Second
+
+// StatusReport is the state of every part of the pipeline, for bt status.
+type StatusReport struct {
+	Store      StoreStatus     `json:"store"`
+	Index      IndexStatus     `json:"index"`
+	Embeddings EmbeddingStatus `json:"embeddings"`
+	Ollama     OllamaStatus    `json:"ollama"`
+	Hooks      []HookStatus    `json:"hooks"`
+	Beads      BeadsStatus     `json:"beads"`
+	Daemon     DaemonStatus    `json:"daemon"`
+	Synthesis  SynthesisStatus `json:"synthesis"`
+	Pending    int             `json:"pending_review"` // Captures held for bt review --pending
+	Problems   []string        `json:"problems"`
+	Healthy    bool            `json:"healthy"`
+}
+
+// StoreStatus describes beats.jsonl.
+type StoreStatus struct {
+	Path      string `json:"path"`
+	Backend   string `json:"backend"` // jsonl or files
+	Bytes     int64  `json:"bytes"`
+	Beats     int    `json:"beats"`
+	Malformed int    `json:"malformed_lines"`
+}
+
+// IndexStatus describes the SQLite index.
+type IndexStatus struct {
+	Path     string     `json:"path"`
+	Exists   bool       `json:"exists"`
+	LastSync *time.Time `json:"last_sync,omitempty"`
+	Fresh    bool       `json:"fresh"` // No change to beats.jsonl since the last sync
+}
+
+// EmbeddingStatus describes the embedding index.
+type EmbeddingStatus struct {
+	Embedded   int     `json:"embedded"`
+	Coverage   float64 `json:"coverage"` // Percent of beats
+	Model      string  `json:"model,omitempty"`
+	Configured string  `json:"configured_model"` // embed_model; new embeddings use it
+}
+
+// OllamaStatus describes the Ollama server.
+type OllamaStatus struct {
+	URL       string   `json:"url"`
+	Available bool     `json:"available"`
+	Models    []string `json:"models,omitempty"`
+	Missing   []string `json:"missing_models,omitempty"` // Configured but not pulled
+	Error     string   `json:"error,omitempty"`
+}
+
+// HookStatus is one hook and when it last fired.
+type HookStatus struct {
+	Name     string     `json:"name"`
+	Enabled  bool       `json:"enabled"`
+	Detail   string     `json:"detail,omitempty"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	LastExit *int       `json:"last_exit_code,omitempty"` // Scripted hooks only
+}
+
+// BeadsStatus describes the bead provider.
+type BeadsStatus struct {
+	Provider  string `json:"provider,omitempty"` // Empty when none is configured
+	Reachable bool   `json:"reachable"`
+	Beads     int    `json:"beads"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DaemonStatus describes the background daemon.
+type DaemonStatus struct {
+	Running bool `json:"running"`
+	PID     int  `json:"pid,omitempty"`
+}
+
+// SynthesisStatus describes the synthesis trigger.
+type SynthesisStatus struct {
+	Pending           bool       `json:"pending"`
+	TriggeredAt       *time.Time `json:"triggered_at,omitempty"`
+	BeatsSinceLast    int        `json:"beats_since_last"`
+	BeatsUntilTrigger int        `json:"beats_until_trigger"`
+}
+
+// Status checks the store, its indexes, Ollama, hooks, the bead provider
+// and the daemon. Unlike bt doctor it changes nothing: a missing index is
+// reported, not built.
+func Status(s *store.JSONLStore) (*StatusReport, error) {
+	r := &StatusReport{Hooks: []HookStatus{}, Problems: []string{}}
+	dir := s.Dir()
+	problem := func(format string, args ...interface{}) {
+		r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+	}
+
+	beats, bad, err := s.ReadAllTolerant()
+	if err != nil {
+		return nil, err
+	}
+	r.Store = StoreStatus{Path: s.Path(), Backend: s.Backend(), Beats: len(beats), Malformed: len(bad)}
+	info, statErr := s.Stat()
+	if statErr == nil {
+		r.Store.Bytes = info.Size()
+	}
+	if len(bad) > 0 {
+		problem("%d malformed line(s) in %s (run 'bt doctor')", len(bad), filepath.Base(s.Path()))
+	}
+
+	r.Index.Path = filepath.Join(dir, store.DefaultDBFile)
+	if _, err := os.Stat(r.Index.Path); err == nil {
+		r.Index.Exists = true
+		sqlite, err := store.NewSQLiteStore(s)
+		if err != nil {
+			return nil, err
+		}
+		last, err := sqlite.LastSync()
+		_ = sqlite.Close()
+		if err != nil {
+			problem("index unreadable: %v", err)
+		} else if !last.IsZero() {
+			r.Index.LastSync = &last
+			// last_sync has whole-second precision
+			r.Index.Fresh = statErr != nil || !info.ModTime().After(last.Add(time.Second))
+		}
+	}
+
+	cfg := config.Get()
+	r.Embeddings.Configured = cfg.EmbedModel
+	if embStore, err := embeddings.NewStore(dir); err == nil {
+		for _, b := range beats {
+			if embStore.Has(b.ID) {
+				r.Embeddings.Embedded++
+			}
+		}
+		r.Embeddings.Coverage = 100
+		if len(beats) > 0 {
+			r.Embeddings.Coverage = float64(r.Embeddings.Embedded) / float64(len(beats)) * 100
+		}
+		r.Embeddings.Model = embStore.Model()
+		if r.Embeddings.Model != "" && r.Embeddings.Model != cfg.EmbedModel {
+			problem("embeddings were made by %s but embed_model is %s (run 'bt embed --migrate %s')", r.Embeddings.Model, cfg.EmbedModel, cfg.EmbedModel)
+		}
+	} else {
+		problem("embedding index unreadable: %v", err)
+	}
+
+	sessionEnd := hooks.GetSessionEndConfig(dir)
+	r.Ollama.URL = cfg.OllamaURL
+	ctx, cancel := context.WithTimeout(context.Background(), statusProbeTimeout)
+	models, err := llm.New(cfg.OllamaURL).Models(ctx)
+	cancel()
+	if err != nil {
+		r.Ollama.Error = err.Error()
+		problem("Ollama at %s is unreachable: embeddings, semantic search and summaries fall back or are skipped", cfg.OllamaURL)
+	} else {
+		r.Ollama.Available = true
+		r.Ollama.Models = models
+		for _, m := range []string{cfg.EmbedModel, sessionEnd.OllamaModel} {
+			if m != "" && !llm.HasModel(models, m) && !slices.Contains(r.Ollama.Missing, m) {
+				r.Ollama.Missing = append(r.Ollama.Missing, m)
+			}
+		}
+		if len(r.Ollama.Missing) > 0 {
+			problem("model(s) not pulled: %s (run 'ollama pull <model>')", strings.Join(r.Ollama.Missing, ", "))
+		}
+	}
+
+	hookStatus, err := hooks.GetStatus(dir)
+	if err != nil {
+		return nil, err
+	}
+	lastRuns := map[string]hooks.HookLogEntry{}
+	entries, err := hooks.ReadHookLog(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if prev, ok := lastRuns[e.Hook]; !ok || e.Time.After(prev.Time) {
+			lastRuns[e.Hook] = e
+		}
+	}
+	for _, h := range hookStatus.Hooks {
+		hs := HookStatus{Name: h.Name, Enabled: h.Enabled, Detail: h.Detail}
+		if e, ok := lastRuns[h.Name]; ok {
+			t, code := e.Time, e.ExitCode
+			hs.LastRun, hs.LastExit = &t, &code
+			if code != 0 && h.Enabled {
+				problem("hook %s failed on its last run (exit %d, see hooks.log)", h.Name, code)
+			}
+		}
+		switch h.Name {
+		case "synthesis":
+			if t := hookStatus.LastSynthesisAt; t != nil && (hs.LastRun == nil || t.After(*hs.LastRun)) {
+				hs.LastRun = t
+			}
+		case "session_end":
+			if fi, err := os.Stat(sessionEnd.ProcessedFile); err == nil {
+				t := fi.ModTime()
+				hs.LastRun = &t
+			}
+		}
+		r.Hooks = append(r.Hooks, hs)
+	}
+	r.Synthesis = SynthesisStatus{
+		BeatsSinceLast:    hookStatus.BeatsSinceLast,
+		BeatsUntilTrigger: hookStatus.BeatsUntilTrigger,
+	}
+	if req := hookStatus.PendingSynthesis; req != nil {
+		t := req.TriggeredAt
+		r.Synthesis.Pending, r.Synthesis.TriggeredAt = true, &t
+	}
+
+	provider, err := beads.NewProvider(dir)
+	switch {
+	case err != nil:
+		r.Beads.Error = err.Error()
+		problem("bead provider: %v", err)
+	case provider != nil:
+		r.Beads.Provider = provider.Name()
+		if list, err := provider.List(); err != nil {
+			r.Beads.Error = err.Error()
+			problem("bead provider %s is unreachable: %v", provider.Name(), err)
+		} else {
+			r.Beads.Reachable, r.Beads.Beads = true, len(list)
+		}
+	}
+
+	if pid, ok := daemon.RunningPID(dir); ok {
+		r.Daemon = DaemonStatus{Running: true, PID: pid}
+	}
+	if pending, err := pipeline.LoadPending(dir); err == nil {
+		r.Pending = len(pending)
+	}
+
+	r.Healthy = len(r.Problems) == 0
+	return r, nil
+}
+
+// Status prints the state of the whole pipeline.
+func (c *HumanCLI) Status(jsonOut bool) error {
+	r, err := Status(c.store)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(r)
+	}
+	printStatusReport(r)
+	return nil
+}
+
+func printStatusReport(r *StatusReport) {
+	mark := func(ok bool) string {
+		if ok {
+			return "ok"
+		}
+		return "!!"
+	}
+	fmt.Printf("  %s  store       %s: %d beats, %s\n", mark(r.Store.Malformed == 0), r.Store.Path, r.Store.Beats, formatBytes(r.Store.Bytes))
+	switch {
+	case !r.Index.Exists:
+		fmt.Println("  --  index       not built yet (built on the first search)")
+	case r.Index.LastSync == nil:
+		fmt.Println("  --  index       never synced")
+	case r.Index.Fresh:
+		fmt.Printf("  ok  index       synced %s\n", displayStamp(*r.Index.LastSync))
+	default:
+		fmt.Printf("  --  index       synced %s, behind beats.jsonl (syncs on the next search)\n", displayStamp(*r.Index.LastSync))
+	}
+	model := r.Embeddings.Model
+	if model == "" {
+		model = r.Embeddings.Configured
+	}
+	embMark := "ok"
+	switch {
+	case r.Embeddings.Model != "" && r.Embeddings.Model != r.Embeddings.Configured:
+		embMark = "!!"
+	case r.Embeddings.Embedded < r.Store.Beats:
+		embMark = "--"
+	}
+	fmt.Printf("  %s  embeddings  %d/%d (%.1f%%), %s\n", embMark, r.Embeddings.Embedded, r.Store.Beats, r.Embeddings.Coverage, model)
+	if r.Ollama.Available {
+		fmt.Printf("  %s  ollama      %s, %d model(s)", mark(len(r.Ollama.Missing) == 0), r.Ollama.URL, len(r.Ollama.Models))
+		if len(r.Ollama.Missing) > 0 {
+			fmt.Printf(", missing %s", strings.Join(r.Ollama.Missing, ", "))
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("  !!  ollama      %s unreachable\n", r.Ollama.URL)
+	}
+	switch {
+	case r.Beads.Provider == "" && r.Beads.Error == "":
+		fmt.Println("  --  beads       no provider configured")
+	case r.Beads.Reachable:
+		fmt.Printf("  ok  beads       %s: %d beads\n", r.Beads.Provider, r.Beads.Beads)
+	default:
+		fmt.Printf("  !!  beads       %s\n", r.Beads.Error)
+	}
+	if r.Daemon.Running {
+		fmt.Printf("  ok  daemon      running (pid %d)\n", r.Daemon.PID)
+	} else {
+		fmt.Println("  --  daemon      not running")
+	}
+	if r.Synthesis.Pending {
+		fmt.Printf("  !!  synthesis   pending since %s (bt synthesis show)\n", displayStamp(*r.Synthesis.TriggeredAt))
+	} else {
+		fmt.Printf("  ok  synthesis   %d beat(s) since the last, %d until the next\n", r.Synthesis.BeatsSinceLast, r.Synthesis.BeatsUntilTrigger)
+	}
+	if r.Pending > 0 {
+		fmt.Printf("  --  review      %d capture(s) held (bt review --pending)\n", r.Pending)
+	}
+
+	fmt.Println("\nHooks:")
+	for _, h := range r.Hooks {
+		state := "disabled"
+		if h.Enabled {
+			state = "enabled"
+		}
+		last := "no runs recorded"
+		if h.LastRun != nil {
+			last = "last run " + displayStamp(*h.LastRun)
+			if h.LastExit != nil && *h.LastExit != 0 {
+				last += fmt.Sprintf(" (exit %d)", *h.LastExit)
+			}
+		}
+		fmt.Printf("  %-16s %-9s %s\n", h.Name, state, last)
+	}
+
+	if len(r.Problems) > 0 {
+		fmt.Println("\nProblems:")
+		for _, p := range r.Problems {
+			fmt.Printf("  - %s\n", p)
+		}
+	}
+}
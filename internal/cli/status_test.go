@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestStatus(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"models": [{"name": "nomic-embed-text:latest"}]}`)
+	}))
+	defer ollama.Close()
+	t.Setenv("BEATS_OLLAMA_URL", ollama.URL)
+	t.Setenv("BEATS_EMBED_MODEL", "nomic-embed-text")
+	t.Setenv("BEATS_LLM_MODEL", "llama3.2")
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(&beat.Beat{ID: "beat-20260101-001", Content: "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(), "beads.json"), []byte(`{"provider": "none"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Status(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Store.Beats != 1 || r.Store.Bytes == 0 || r.Index.Exists {
+		t.Errorf("store/index = %+v %+v", r.Store, r.Index)
+	}
+	if !r.Ollama.Available || len(r.Ollama.Missing) != 1 || r.Ollama.Missing[0] != "llama3.2" {
+		t.Errorf("ollama = %+v, want llama3.2 missing", r.Ollama)
+	}
+	if r.Healthy || len(r.Problems) != 1 {
+		t.Errorf("problems = %q", r.Problems)
+	}
+	if r.Beads.Provider != "" || r.Beads.Error != "" {
+		t.Errorf("beads = %+v, want none configured", r.Beads)
+	}
+	if len(r.Hooks) == 0 || r.Embeddings.Embedded != 0 || r.Embeddings.Configured != "nomic-embed-text" {
+		t.Errorf("hooks/embeddings = %+v %+v", r.Hooks, r.Embeddings)
+	}
+}
@@ -0,0 +1,372 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// StoreSummary describes a registered store for bt stores list.
+type StoreSummary struct {
+	store.StoreEntry
+	Beats   int  `json:"beats"`
+	Exists  bool `json:"exists"`
+	Current bool `json:"current,omitempty"` // Where bt add would write from here
+}
+
+// StoresList prints the registered stores with their beat counts.
+func StoresList(jsonOut bool) error {
+	reg, err := store.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	cwd, _ := os.Getwd()
+	current, err := store.RouteDir(cwd)
+	if err != nil {
+		return err
+	}
+
+	var out []StoreSummary
+	for _, s := range reg.All() {
+		sum := StoreSummary{StoreEntry: s, Current: s.Path == current}
+		if _, err := os.Stat(s.Path); err == nil {
+			sum.Exists = true
+			if beats, err := readStore(s); err == nil {
+				sum.Beats = len(beats)
+			}
+		}
+		out = append(out, sum)
+	}
+
+	if jsonOut {
+		return outputJSON(out)
+	}
+	for _, s := range out {
+		marker := " "
+		if s.Current {
+			marker = "*"
+		}
+		count := fmt.Sprintf("%4d beats", s.Beats)
+		if !s.Exists {
+			count = "   (empty)"
+		}
+		fmt.Printf("%s %-20s %s  %s\n", marker, s.Name, count, s.Path)
+	}
+	fmt.Printf("\n* receives bt add from %s\n", cwd)
+	return nil
+}
+
+// FederatedBeat is a beat together with the store it lives in.
+type FederatedBeat struct {
+	Store string `json:"store"`
+	beat.Beat
+}
+
+// FederatedResult is a search result together with the store it came from.
+type FederatedResult struct {
+	Store string `json:"store"`
+	beat.SearchResult
+}
+
+// FederatedList lists the beats of several stores, oldest first, optionally
+// filtered by session and WALD directory.
+func FederatedList(stores []store.StoreEntry, sessionFilter, waldFilter string, jsonOut bool) error {
+	if sessionFilter == "current" {
+		sessionFilter = os.Getenv("FACTORY_SESSION_ID")
+	}
+
+	var all []FederatedBeat
+	for _, s := range stores {
+		beats, err := readStore(s)
+		if err != nil {
+			return fmt.Errorf("store %s: %w", s.Name, err)
+		}
+		for _, b := range filterWALD(beats, waldFilter) {
+			if strings.HasPrefix(b.SessionID, sessionFilter) {
+				all = append(all, FederatedBeat{Store: s.Name, Beat: b})
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt.Before(all[j].CreatedAt) })
+
+	if jsonOut {
+		if all == nil {
+			all = []FederatedBeat{}
+		}
+		return outputJSON(all)
+	}
+	if len(all) == 0 {
+		fmt.Println("No beats found.")
+		return nil
+	}
+
+	fmt.Printf("Found %d beat(s) across %d store(s):\n\n", len(all), len(stores))
+	for _, b := range all {
+		fmt.Printf("  [%s] %s  %s  %s\n", b.Store, b.ID, displayTime(b.CreatedAt), b.Impetus.Label)
+		fmt.Printf("            %s\n\n", truncate(b.Content, 60))
+	}
+	return nil
+}
+
+// FederatedSearch searches several stores and merges the results by score.
+func FederatedSearch(stores []store.StoreEntry, query string, maxResults int, sessionFilter, waldFilter string, jsonOut bool) error {
+	if maxResults <= 0 {
+		maxResults = 20
+	}
+
+	var all []FederatedResult
+	for _, s := range stores {
+		if _, err := os.Stat(s.Path); err != nil {
+			continue
+		}
+		st, err := store.NewJSONLStore(s.Path)
+		if err != nil {
+			return fmt.Errorf("store %s: %w", s.Name, err)
+		}
+		results, err := searchStore(st, query, sessionFilter, waldFilter)
+		if err != nil {
+			return fmt.Errorf("store %s: %w", s.Name, err)
+		}
+		for _, r := range results {
+			all = append(all, FederatedResult{Store: s.Name, SearchResult: r})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Score > all[j].Score })
+	if len(all) > maxResults {
+		all = all[:maxResults]
+	}
+
+	if jsonOut {
+		if all == nil {
+			all = []FederatedResult{}
+		}
+		return outputJSON(all)
+	}
+	if len(all) == 0 {
+		fmt.Printf("No beats found matching \"%s\" across %d store(s)\n", query, len(stores))
+		return nil
+	}
+
+	fmt.Printf("Found %d result(s) for \"%s\" across %d store(s):\n\n", len(all), query, len(stores))
+	for _, r := range all {
+		fmt.Printf("  [%.2f] [%s] %s  %s\n", r.Score, r.Store, r.ID, r.Impetus.Label)
+		fmt.Printf("              %s\n\n", truncate(r.Content, 60))
+	}
+	return nil
+}
+
+// StoresDiffResult compares two stores by beat ID.
+type StoresDiffResult struct {
+	A       string         `json:"a"`
+	B       string         `json:"b"`
+	APath   string         `json:"a_path"`
+	BPath   string         `json:"b_path"`
+	OnlyInA []DiffEntry    `json:"only_in_a"`
+	OnlyInB []DiffEntry    `json:"only_in_b"`
+	Differ  []DiffConflict `json:"differ"` // Same ID, different beat
+	Links   []LinkDiff     `json:"links"`  // Same ID, different bead links
+	Same    int            `json:"same"`
+}
+
+// Converged reports whether the stores hold the same beats.
+func (r *StoresDiffResult) Converged() bool {
+	return len(r.OnlyInA) == 0 && len(r.OnlyInB) == 0 && len(r.Differ) == 0 && len(r.Links) == 0
+}
+
+// DiffEntry is a beat present in only one store.
+type DiffEntry struct {
+	ID        string    `json:"id"`
+	Impetus   string    `json:"impetus"`
+	Preview   string    `json:"preview"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DiffConflict is an ID both stores hold with different content.
+type DiffConflict struct {
+	ID     string    `json:"id"`
+	Fields []string  `json:"fields"` // What differs: content, impetus, created_at, ...
+	A      DiffEntry `json:"a"`
+	B      DiffEntry `json:"b"`
+}
+
+// LinkDiff is an ID both stores hold linked to different beads. Links are
+// written "<bead> (<relation>)".
+type LinkDiff struct {
+	ID      string   `json:"id"`
+	OnlyInA []string `json:"only_in_a"`
+	OnlyInB []string `json:"only_in_b"`
+}
+
+// StoresDiff compares the beats of two stores. With check, stores that have
+// not converged are an error, for scripts verifying a migration, sync or
+// backup.
+func StoresDiff(a, b store.StoreEntry, jsonOut, check bool) error {
+	beatsA, err := readStore(a)
+	if err != nil {
+		return fmt.Errorf("store %s: %w", a.Name, err)
+	}
+	beatsB, err := readStore(b)
+	if err != nil {
+		return fmt.Errorf("store %s: %w", b.Name, err)
+	}
+	res := diffBeats(beatsA, beatsB)
+	res.A, res.B = a.Name, b.Name
+	res.APath, res.BPath = a.Path, b.Path
+
+	if jsonOut {
+		if err := outputJSON(res); err != nil {
+			return err
+		}
+	} else {
+		printStoresDiff(a, b, res)
+	}
+	if check && !res.Converged() {
+		return fmt.Errorf("%s and %s differ in %d beat(s)", a.Name, b.Name, len(res.OnlyInA)+len(res.OnlyInB)+len(res.Differ)+len(res.Links))
+	}
+	return nil
+}
+
+func printStoresDiff(a, b store.StoreEntry, res StoresDiffResult) {
+	fmt.Printf("%s: %s\n%s: %s\n\n", a.Name, a.Path, b.Name, b.Path)
+	if res.Converged() {
+		fmt.Printf("The stores match: %d beat(s)\n", res.Same)
+		return
+	}
+	fmt.Printf("%d identical, %d only in %s, %d only in %s, %d differ, %d with different links\n",
+		res.Same, len(res.OnlyInA), a.Name, len(res.OnlyInB), b.Name, len(res.Differ), len(res.Links))
+	printDiffEntries("Only in "+a.Name, res.OnlyInA)
+	printDiffEntries("Only in "+b.Name, res.OnlyInB)
+	if len(res.Differ) > 0 {
+		fmt.Printf("\nDiffer:\n")
+		for _, d := range res.Differ {
+			fmt.Printf("  %s  %s\n", d.ID, strings.Join(d.Fields, ", "))
+			fmt.Printf("    %-10s %s (updated %s)\n", a.Name, d.A.Preview, displayTime(d.A.UpdatedAt))
+			fmt.Printf("    %-10s %s (updated %s)\n", b.Name, d.B.Preview, displayTime(d.B.UpdatedAt))
+		}
+	}
+	if len(res.Links) > 0 {
+		fmt.Printf("\nLinks differ:\n")
+		for _, l := range res.Links {
+			fmt.Printf("  %s\n", l.ID)
+			if len(l.OnlyInA) > 0 {
+				fmt.Printf("    %-10s %s\n", a.Name, strings.Join(l.OnlyInA, ", "))
+			}
+			if len(l.OnlyInB) > 0 {
+				fmt.Printf("    %-10s %s\n", b.Name, strings.Join(l.OnlyInB, ", "))
+			}
+		}
+	}
+}
+
+func printDiffEntries(title string, entries []DiffEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, e := range entries {
+		fmt.Printf("  %s  %s\n", e.ID, e.Impetus)
+		fmt.Printf("            %s\n", e.Preview)
+	}
+}
+
+// diffBeats compares two sets of beats by ID.
+func diffBeats(a, b []beat.Beat) StoresDiffResult {
+	res := StoresDiffResult{OnlyInA: []DiffEntry{}, OnlyInB: []DiffEntry{}, Differ: []DiffConflict{}, Links: []LinkDiff{}}
+	inB := make(map[string]beat.Beat, len(b))
+	for _, x := range b {
+		inB[x.ID] = x
+	}
+	inA := make(map[string]bool, len(a))
+	for _, x := range a {
+		inA[x.ID] = true
+		y, ok := inB[x.ID]
+		if !ok {
+			res.OnlyInA = append(res.OnlyInA, diffEntry(x))
+			continue
+		}
+		fields := differingFields(x, y)
+		if len(fields) > 0 {
+			res.Differ = append(res.Differ, DiffConflict{ID: x.ID, Fields: fields, A: diffEntry(x), B: diffEntry(y)})
+		}
+		onlyA, onlyB := linkNames(x.LinkedBeads, y.LinkedBeads), linkNames(y.LinkedBeads, x.LinkedBeads)
+		if len(onlyA) > 0 || len(onlyB) > 0 {
+			res.Links = append(res.Links, LinkDiff{ID: x.ID, OnlyInA: onlyA, OnlyInB: onlyB})
+		}
+		if len(fields) == 0 && len(onlyA) == 0 && len(onlyB) == 0 {
+			res.Same++
+		}
+	}
+	for _, y := range b {
+		if !inA[y.ID] {
+			res.OnlyInB = append(res.OnlyInB, diffEntry(y))
+		}
+	}
+	return res
+}
+
+// differingFields names the parts of two versions of a beat that differ.
+// Bead links are compared on their own, and updated_at is left out: two
+// stores can agree on a beat written at different times.
+func differingFields(x, y beat.Beat) []string {
+	var fields []string
+	for _, f := range []struct {
+		name string
+		same bool
+	}{
+		{"content", x.Content == y.Content},
+		{"impetus", x.Impetus.Label == y.Impetus.Label && x.Impetus.Raw == y.Impetus.Raw && maps.Equal(x.Impetus.Meta, y.Impetus.Meta)},
+		{"created_at", x.CreatedAt.Equal(y.CreatedAt)},
+		{"references", sameList(x.References, y.References)},
+		{"entities", sameList(x.Entities, y.Entities)},
+		{"relations", sameList(x.Relations, y.Relations)},
+		{"annotations", sameList(x.Annotations, y.Annotations)},
+		{"session_id", x.SessionID == y.SessionID},
+		{"context", reflect.DeepEqual(x.Context, y.Context)},
+	} {
+		if !f.same {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// sameList compares two lists, taking nil and empty as the same.
+func sameList[T any](x, y []T) bool {
+	return len(x) == 0 && len(y) == 0 || reflect.DeepEqual(x, y)
+}
+
+// linkNames lists the links in x that y does not have, by bead and relation.
+func linkNames(x, y beat.BeadLinks) []string {
+	names := []string{}
+	for _, l := range x {
+		if !slices.ContainsFunc(y, func(m beat.BeadLink) bool { return m.BeadID == l.BeadID && m.Relation == l.Relation }) {
+			names = append(names, fmt.Sprintf("%s (%s)", l.BeadID, l.Relation))
+		}
+	}
+	return names
+}
+
+func diffEntry(b beat.Beat) DiffEntry {
+	return DiffEntry{ID: b.ID, Impetus: b.Impetus.Label, Preview: truncate(b.Content, 60), CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt}
+}
+
+// readStore reads a store's beats; a store that does not exist yet is empty.
+func readStore(s store.StoreEntry) ([]beat.Beat, error) {
+	if _, err := os.Stat(s.Path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	st, err := store.NewJSONLStore(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return st.ReadAll()
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestDiffBeats(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mk := func(id, content string) beat.Beat {
+		return beat.Beat{ID: id, Content: content, CreatedAt: at, Impetus: beat.Impetus{Label: "Note"}}
+	}
+	a := []beat.Beat{mk("beat-1", "same"), mk("beat-2", "only a"), mk("beat-3", "mine")}
+	b := []beat.Beat{mk("beat-1", "same"), mk("beat-3", "theirs"), mk("beat-4", "only b")}
+
+	res := diffBeats(a, b)
+	if res.Same != 1 {
+		t.Errorf("Same = %d, want 1", res.Same)
+	}
+	if len(res.OnlyInA) != 1 || res.OnlyInA[0].ID != "beat-2" {
+		t.Errorf("OnlyInA = %+v, want beat-2", res.OnlyInA)
+	}
+	if len(res.OnlyInB) != 1 || res.OnlyInB[0].ID != "beat-4" {
+		t.Errorf("OnlyInB = %+v, want beat-4", res.OnlyInB)
+	}
+	if len(res.Differ) != 1 || res.Differ[0].ID != "beat-3" || res.Differ[0].B.Preview != "theirs" {
+		t.Errorf("Differ = %+v, want beat-3", res.Differ)
+	}
+	if res.Converged() {
+		t.Error("Converged() = true for differing stores")
+	}
+
+	linked := mk("beat-1", "same")
+	linked.LinkedBeads = beat.BeadLinks{{BeadID: "bd-7", Relation: beat.RelationSeed}}
+	retagged := mk("beat-1", "same")
+	retagged.Entities = []beat.Entity{{Label: "Acme", Category: "organization"}}
+	res = diffBeats([]beat.Beat{linked}, []beat.Beat{retagged})
+	if len(res.Links) != 1 || len(res.Links[0].OnlyInA) != 1 || res.Links[0].OnlyInA[0] != "bd-7 (seed)" || len(res.Links[0].OnlyInB) != 0 {
+		t.Errorf("Links = %+v, want bd-7 only in a", res.Links)
+	}
+	if len(res.Differ) != 1 || strings.Join(res.Differ[0].Fields, ",") != "entities" || res.Same != 0 {
+		t.Errorf("Differ = %+v, Same = %d; want entities to differ", res.Differ, res.Same)
+	}
+
+	if res := diffBeats([]beat.Beat{mk("beat-1", "same")}, []beat.Beat{mk("beat-1", "same")}); !res.Converged() || res.Same != 1 {
+		t.Errorf("identical stores = %+v, want converged", res)
+	}
+}
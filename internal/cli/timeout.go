@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TimeoutError is the cause of a robot command cut short by its timeout_ms.
+// It matches context.DeadlineExceeded under errors.Is.
+type TimeoutError struct {
+	TimeoutMS int64
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %dms", e.TimeoutMS)
+}
+
+// Is makes errors.Is(err, context.DeadlineExceeded) hold.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// timeoutInput reads a robot command's input, starting its timeout once the
+// input has been read.
+type timeoutInput struct {
+	c    *RobotCLI
+	src  io.Reader
+	data *bytes.Reader
+}
+
+func (r *timeoutInput) Read(p []byte) (int, error) {
+	if r.data == nil {
+		data, err := io.ReadAll(r.src)
+		if err != nil {
+			return 0, err
+		}
+		r.data = bytes.NewReader(data)
+		var in struct {
+			TimeoutMS int64 `json:"timeout_ms"`
+		}
+		if json.Unmarshal(data, &in) == nil && in.TimeoutMS > 0 {
+			r.c.setTimeout(in.TimeoutMS)
+		}
+	}
+	return r.data.Read(p)
+}
+
+// Input wraps a command's input so a timeout_ms field in it bounds the
+// command: store scans, embedding and LLM calls stop at the deadline, and
+// the command answers with a timeout error. Commands that never read their
+// input are not affected.
+func (c *RobotCLI) Input(r io.Reader) io.Reader {
+	return &timeoutInput{c: c, src: r}
+}
+
+// setTimeout starts the command's deadline.
+func (c *RobotCLI) setTimeout(ms int64) {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.ctx, c.cancel = context.WithTimeoutCause(context.Background(), time.Duration(ms)*time.Millisecond, &TimeoutError{TimeoutMS: ms})
+}
+
+// Close releases the command's timeout.
+func (c *RobotCLI) Close() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestRobotTimeout(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			fmt.Fprint(w, `{"models": [{"name": "nomic-embed-text:latest"}]}`)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body) // Lets the server notice the client going away
+		select {                           // Embedding never finishes in time
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer ollama.Close()
+	t.Setenv("BEATS_OLLAMA_URL", ollama.URL)
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(&beat.Beat{ID: "beat-20260101-001", Content: "pricing notes"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	SetJSONOutput(&out)
+	defer SetJSONOutput(nil)
+	c := NewRobotCLI(s)
+	defer c.Close()
+	start := time.Now()
+	if err := c.Search(c.Input(strings.NewReader(`{"query": "pricing", "semantic": true, "timeout_ms": 100}`))); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("search took %v, want it cut short", elapsed)
+	}
+	var got struct {
+		Code      string `json:"code"`
+		TimeoutMS int64  `json:"timeout_ms"`
+		Error     string `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != "timeout" || got.TimeoutMS != 100 || got.Error == "" {
+		t.Errorf("output = %s, want a timeout error", out.String())
+	}
+
+	// Without timeout_ms the keyword search is untouched
+	out.Reset()
+	c = NewRobotCLI(s)
+	if err := c.Search(c.Input(strings.NewReader(`{"query": "pricing"}`))); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "beat-20260101-001") {
+		t.Errorf("output = %s, want the beat", out.String())
+	}
+}
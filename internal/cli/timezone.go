@@ -0,0 +1,22 @@
+package cli
+
+import "time"
+
+// DisplayLocation is the time zone human output shows times in: the
+// configured timezone, or UTC with --utc. JSON output is always UTC.
+var DisplayLocation = time.Local
+
+// displayTime formats t to the minute in the display time zone.
+func displayTime(t time.Time) string {
+	return t.In(DisplayLocation).Format("2006-01-02 15:04")
+}
+
+// displayDate formats the calendar day of t in the display time zone.
+func displayDate(t time.Time) string {
+	return t.In(DisplayLocation).Format("2006-01-02")
+}
+
+// displayStamp formats t in full, with its offset, in the display time zone.
+func displayStamp(t time.Time) string {
+	return t.In(DisplayLocation).Format(time.RFC3339)
+}
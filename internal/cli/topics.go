@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/topics"
+)
+
+// TopicsOptions contains options for the topics command.
+type TopicsOptions struct {
+	Window    time.Duration
+	Threshold float64 // 0 = topic_similarity from scoring.json
+	MinBeats  int
+	JSON      bool
+}
+
+// ParseWindow parses a window such as "30d", "2w" or "72h".
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) {
+			if n <= 0 {
+				return 0, fmt.Errorf("window must be positive: %s", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q (use e.g. 30d, 2w, 72h)", s)
+	}
+	return d, nil
+}
+
+// Topics reports emerging, steady and fading themes in the current window
+// compared with the one before it.
+func (c *HumanCLI) Topics(opts TopicsOptions) error {
+	report, err := topics.ForStore(c.store, topics.Options{
+		Window:    opts.Window,
+		Threshold: opts.Threshold,
+		MinBeats:  opts.MinBeats,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		return outputJSON(report)
+	}
+
+	fmt.Printf("Topics: last %s vs the %s before (%d beats clustered, threshold %.2f)\n",
+		report.Window, report.Window, report.Clustered, report.Threshold)
+	if report.Unembedded > 0 {
+		fmt.Printf("%d beat(s) in range have no embeddings; run 'bt embed' to include them\n", report.Unembedded)
+	}
+	if len(report.Topics) == 0 {
+		fmt.Println("\nNo recurring topics found.")
+		return nil
+	}
+
+	for _, trend := range []string{topics.Emerging, topics.Steady, topics.Fading} {
+		header := false
+		for _, t := range report.Topics {
+			if t.Trend != trend {
+				continue
+			}
+			if !header {
+				fmt.Printf("\n%s:\n", strings.ToUpper(trend[:1])+trend[1:])
+				header = true
+			}
+			fmt.Printf("  %s  (%d now, %d before)\n", t.Label, t.Current, t.Previous)
+			for _, r := range t.Representatives {
+				fmt.Printf("    %s  %s\n", r.ID, truncate(r.Preview, 60))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// MetricsFile holds store snapshots, one JSON object per line, oldest first.
+const MetricsFile = "metrics.jsonl"
+
+// DefaultTrendDays is the period trends compare.
+const DefaultTrendDays = 30
+
+// StatsSnapshot is the state of a store at one moment, kept so trends can be
+// computed later. Counts leave digests out, as Stats does.
+type StatsSnapshot struct {
+	At                time.Time `json:"at"`
+	TotalBeats        int       `json:"total_beats"`
+	Last7Days         int       `json:"last_7_days"`
+	Last30Days        int       `json:"last_30_days"`
+	ActiveDays30      int       `json:"active_days_30"` // Days with a capture in the last 30
+	Embedded          int       `json:"embedded"`
+	EmbeddingCoverage float64   `json:"embedding_coverage"` // Fractions of all beats, 0 to 1
+	LinkRatio         float64   `json:"link_ratio"`         // Linked to a bead
+	EntityRatio       float64   `json:"entity_ratio"`       // With at least one entity
+	ReferenceRatio    float64   `json:"reference_ratio"`    // With at least one reference
+}
+
+// TakeStatsSnapshot computes a store snapshot at now.
+func TakeStatsSnapshot(s *store.JSONLStore, now time.Time) (*StatsSnapshot, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	snap := &StatsSnapshot{At: now.UTC()}
+	emb, _ := embeddings.NewStore(s.Dir())
+	active := make(map[string]bool)
+	var linked, withEntities, withRefs int
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] == DigestKind || b.CreatedAt.After(now) {
+			continue
+		}
+		snap.TotalBeats++
+		age := now.Sub(b.CreatedAt)
+		if age < 7*24*time.Hour {
+			snap.Last7Days++
+		}
+		if age < 30*24*time.Hour {
+			snap.Last30Days++
+			active[displayDate(b.CreatedAt)] = true
+		}
+		if len(b.LinkedBeads) > 0 {
+			linked++
+		}
+		if len(b.Entities) > 0 {
+			withEntities++
+		}
+		if len(b.References) > 0 {
+			withRefs++
+		}
+		if emb != nil && emb.Has(b.ID) {
+			snap.Embedded++
+		}
+	}
+	snap.ActiveDays30 = len(active)
+	if n := float64(snap.TotalBeats); n > 0 {
+		snap.EmbeddingCoverage = float64(snap.Embedded) / n
+		snap.LinkRatio = float64(linked) / n
+		snap.EntityRatio = float64(withEntities) / n
+		snap.ReferenceRatio = float64(withRefs) / n
+	}
+	return snap, nil
+}
+
+// SaveStatsSnapshot takes a snapshot now and appends it to metrics.jsonl.
+func SaveStatsSnapshot(s *store.JSONLStore) (*StatsSnapshot, error) {
+	snap, err := TakeStatsSnapshot(s, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(s.Dir(), MetricsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// LoadStatsSnapshots reads metrics.jsonl, oldest first.
+func LoadStatsSnapshots(beatsDir string) ([]StatsSnapshot, error) {
+	f, err := os.Open(filepath.Join(beatsDir, MetricsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var list []StatsSnapshot
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var snap StatsSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", MetricsFile, err)
+		}
+		list = append(list, snap)
+	}
+	return list, scanner.Err()
+}
+
+// Trend is one metric now and a period ago.
+type Trend struct {
+	Metric  string   `json:"metric"`
+	Now     float64  `json:"now"`
+	Then    float64  `json:"then"`
+	Change  *float64 `json:"change_pct,omitempty"` // Relative change of a count; absent when Then is 0
+	Summary string   `json:"summary"`
+}
+
+// TrendReport compares the store now with a period ago.
+type TrendReport struct {
+	Days     int            `json:"days"`
+	Current  StatsSnapshot  `json:"current"`
+	Baseline *StatsSnapshot `json:"baseline,omitempty"` // Latest snapshot at least a period old
+	Trends   []Trend        `json:"trends"`
+}
+
+// Trends compares the last days with the days before. Capture rate and
+// active days come from the beats' dates, so they need no history; ratios
+// need a snapshot in metrics.jsonl at least that old.
+func Trends(s *store.JSONLStore, days int, now time.Time) (*TrendReport, error) {
+	if days <= 0 {
+		days = DefaultTrendDays
+	}
+	period := time.Duration(days) * 24 * time.Hour
+	current, err := TakeStatsSnapshot(s, now)
+	if err != nil {
+		return nil, err
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var recent, before int
+	recentDays, beforeDays := make(map[string]bool), make(map[string]bool)
+	for _, b := range beats {
+		if b.Impetus.Meta["kind"] == DigestKind {
+			continue
+		}
+		switch age := now.Sub(b.CreatedAt); {
+		case age < 0:
+		case age < period:
+			recent++
+			recentDays[displayDate(b.CreatedAt)] = true
+		case age < 2*period:
+			before++
+			beforeDays[displayDate(b.CreatedAt)] = true
+		}
+	}
+
+	r := &TrendReport{Days: days, Current: *current, Trends: []Trend{}}
+	vs := trendPeriodName(days)
+	r.Trends = append(r.Trends,
+		countTrend("capture_rate", "Capture rate", recent, before, fmt.Sprintf("beats in the last %d days", days), vs),
+		countTrend("active_days", "Active days", len(recentDays), len(beforeDays), fmt.Sprintf("of the last %d", days), vs),
+	)
+
+	snaps, err := LoadStatsSnapshots(s.Dir())
+	if err != nil {
+		return nil, err
+	}
+	for i := len(snaps) - 1; i >= 0; i-- {
+		if !snaps[i].At.After(now.Add(-period)) {
+			r.Baseline = &snaps[i]
+			break
+		}
+	}
+	if base := r.Baseline; base != nil {
+		r.Trends = append(r.Trends,
+			countTrend("total_beats", "Store size", current.TotalBeats, base.TotalBeats, "beats", vs),
+			ratioTrend("embedding_coverage", "Embedding coverage", current.EmbeddingCoverage, base.EmbeddingCoverage, vs),
+			ratioTrend("link_ratio", "Beats linked to beads", current.LinkRatio, base.LinkRatio, vs),
+			ratioTrend("entity_ratio", "Beats with entities", current.EntityRatio, base.EntityRatio, vs),
+			ratioTrend("reference_ratio", "Beats with references", current.ReferenceRatio, base.ReferenceRatio, vs),
+		)
+	}
+	return r, nil
+}
+
+// trendPeriodName names the period compared against.
+func trendPeriodName(days int) string {
+	switch days {
+	case 7:
+		return "last week"
+	case 30:
+		return "last month"
+	}
+	return fmt.Sprintf("the %d days before", days)
+}
+
+// countTrend compares two counts as a relative change.
+func countTrend(metric, label string, now, then int, unit, vs string) Trend {
+	t := Trend{Metric: metric, Now: float64(now), Then: float64(then)}
+	if then == 0 {
+		t.Summary = fmt.Sprintf("%s: %d %s (0 %s)", label, now, unit, vs)
+		return t
+	}
+	pct := math.Round(float64(now-then) / float64(then) * 100)
+	t.Change = &pct
+	switch {
+	case pct > 0:
+		t.Summary = fmt.Sprintf("%s up %.0f%% vs %s (%d %s, was %d)", label, pct, vs, now, unit, then)
+	case pct < 0:
+		t.Summary = fmt.Sprintf("%s down %.0f%% vs %s (%d %s, was %d)", label, -pct, vs, now, unit, then)
+	default:
+		t.Summary = fmt.Sprintf("%s unchanged vs %s (%d %s)", label, vs, now, unit)
+	}
+	return t
+}
+
+// ratioTrend compares two fractions in percentage points.
+func ratioTrend(metric, label string, now, then float64, vs string) Trend {
+	t := Trend{Metric: metric, Now: now, Then: then}
+	points := math.Round((now - then) * 100)
+	switch {
+	case points > 0:
+		t.Summary = fmt.Sprintf("%s up %.0f points vs %s (%.0f%%, was %.0f%%)", label, points, vs, now*100, then*100)
+	case points < 0:
+		t.Summary = fmt.Sprintf("%s down %.0f points vs %s (%.0f%%, was %.0f%%)", label, -points, vs, now*100, then*100)
+	default:
+		t.Summary = fmt.Sprintf("%s unchanged vs %s (%.0f%%)", label, vs, now*100)
+	}
+	return t
+}
+
+// StatsSnapshot appends a store snapshot to metrics.jsonl.
+func (c *HumanCLI) StatsSnapshot(jsonOut bool) error {
+	snap, err := SaveStatsSnapshot(c.store)
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(snap)
+	}
+	fmt.Printf("Snapshot saved to %s: %d beats, %d in the last 30 days, %.0f%% embedded, %.0f%% linked\n",
+		filepath.Join(c.store.Dir(), MetricsFile), snap.TotalBeats, snap.Last30Days, snap.EmbeddingCoverage*100, snap.LinkRatio*100)
+	return nil
+}
+
+// Trends prints how the store changed over the last days.
+func (c *HumanCLI) Trends(days int, jsonOut bool) error {
+	r, err := Trends(c.store, days, clock.Now())
+	if err != nil {
+		return err
+	}
+	if jsonOut {
+		return outputJSON(r)
+	}
+	for _, t := range r.Trends {
+		fmt.Println(t.Summary)
+	}
+	if r.Baseline == nil {
+		fmt.Printf("\nNo snapshot from %d or more days ago yet; coverage and link trends need one (bt stats --snapshot, or bt daemon takes one daily).\n", r.Days)
+	}
+	return nil
+}
@@ -0,0 +1,535 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// WatchDirOptions configures the drop-folder watcher.
+type WatchDirOptions struct {
+	Interval time.Duration // How often the folder is scanned
+	Settle   time.Duration // Files modified more recently are still being written
+	Archive  string        // Subfolder for ingested files (default "archive")
+	Once     bool          // Scan once and exit
+}
+
+// dropFileBeat converts a parsed note into a proposed beat.
+func dropFileBeat(name string, note *capture.Note) *beat.ProposedBeat {
+	meta := note.Meta
+	meta["source"] = "drop-folder"
+	meta["file"] = name
+
+	label := note.Impetus
+	if label == "" {
+		label = note.Title // The filename is the impetus unless front matter says otherwise
+	}
+
+	p := &beat.ProposedBeat{
+		Content: note.Content,
+		Impetus: beat.Impetus{
+			Label: label,
+			Raw:   name,
+			Meta:  meta,
+		},
+		References:  []beat.Reference{},
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+		CreatedAt:   note.Date,
+	}
+	if p.Impetus.Label == "" {
+		p.Impetus.Label = "Drop folder"
+	}
+	if note.URL != "" {
+		p.References = append(p.References, beat.Reference{Kind: "url", Locator: note.URL})
+	}
+	for _, tag := range note.Tags {
+		p.Entities = append(p.Entities, beat.Entity{
+			Label:    tag,
+			Category: "topic",
+			Meta:     map[string]string{"confidence": "1.0"},
+		})
+	}
+	p.LinkedBeads = append(p.LinkedBeads, note.Beads...)
+	return p
+}
+
+// ingestDropFile creates a beat from one file and moves it into the archive.
+func (c *HumanCLI) ingestDropFile(path, archiveDir string) (*beat.Beat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(path)
+	note, err := capture.ParseNote(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.commit(dropFileBeat(name, note), beat.ChannelDrop)
+	var held *pipeline.PendingError
+	if errors.As(err, &held) {
+		// The file is in the pending queue now; archive it under that ID
+		if err := archiveDropFile(path, archiveDir, held.Pending.ID+"-"+name); err != nil {
+			return nil, fmt.Errorf("%s, but archiving failed: %w", held.Error(), err)
+		}
+		return nil, held
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dest := name
+	if _, err := os.Stat(filepath.Join(archiveDir, name)); err == nil {
+		// Keep earlier drops with the same name
+		dest = b.ID + "-" + name
+	}
+	if err := archiveDropFile(path, archiveDir, dest); err != nil {
+		return b, fmt.Errorf("beat %s created but archiving failed: %w", b.ID, err)
+	}
+	return b, nil
+}
+
+// archiveDropFile moves an ingested file into the archive as name.
+func archiveDropFile(path, archiveDir, name string) error {
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return err
+	}
+	return os.Rename(path, filepath.Join(archiveDir, name))
+}
+
+// WatchDir ingests markdown and text files dropped into dir as beats and moves
+// them to an archive subfolder. Files that fail to parse stay in place and are
+// retried only after they change.
+func (c *HumanCLI) WatchDir(ctx context.Context, dir string, opts WatchDirOptions, logger *log.Logger) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Settle <= 0 {
+		opts.Settle = 2 * time.Second
+	}
+	if opts.Archive == "" {
+		opts.Archive = "archive"
+	}
+	archiveDir := opts.Archive
+	if !filepath.IsAbs(archiveDir) {
+		archiveDir = filepath.Join(dir, archiveDir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+
+	failed := make(map[string]time.Time) // Path -> modification time that failed
+	scan := func(settle time.Duration) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			logger.Printf("reading %s: %v", dir, err)
+			return
+		}
+		for _, e := range entries {
+			if e.IsDir() || !capture.IsNoteFile(e.Name()) {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if time.Since(info.ModTime()) < settle {
+				continue // Still syncing; pick it up next scan
+			}
+			if mod, ok := failed[path]; ok && mod.Equal(info.ModTime()) {
+				continue
+			}
+
+			b, err := c.ingestDropFile(path, archiveDir)
+			var held *pipeline.PendingError
+			if errors.As(err, &held) {
+				delete(failed, path)
+				logger.Printf("%s %s", e.Name(), held)
+				continue
+			}
+			if err != nil {
+				logger.Printf("%s: %v", e.Name(), err)
+				if b == nil {
+					failed[path] = info.ModTime()
+				}
+				continue
+			}
+			delete(failed, path)
+			logger.Printf("%s -> %s (%s)", e.Name(), b.ID, b.Impetus.Label)
+		}
+	}
+
+	if opts.Once {
+		scan(0)
+		return nil
+	}
+
+	logger.Printf("watching %s (every %s, archiving to %s)", dir, opts.Interval, archiveDir)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		scan(opts.Settle)
+		select {
+		case <-ctx.Done():
+			logger.Printf("stopping")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchSessionsOptions configures the Factory session watcher.
+type WatchSessionsOptions struct {
+	Interval time.Duration // How often the sessions directory is scanned
+	Idle     time.Duration // A session untouched this long is considered ended
+	Workers  int           // Workspaces summarized concurrently
+	Once     bool          // Process every ended session once and exit
+	Route    bool          // Send each session to its workspace's project store
+}
+
+// sessionBeat converts a summarized session into a proposed beat dated when
+// the session file was last written.
+func sessionBeat(session *hooks.FactorySession, summary string, endedAt time.Time) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source":     "factory-session",
+		"session_id": session.ID,
+		"title":      session.Title,
+		"messages":   fmt.Sprint(len(session.Messages)),
+	}
+	if session.Workspace != "" {
+		meta["workspace"] = session.Workspace
+	}
+	return &beat.ProposedBeat{
+		Content: summary,
+		Impetus: beat.Impetus{
+			Label: "Session",
+			Raw:   session.Title,
+			Meta:  meta,
+		},
+		References:  []beat.Reference{},
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+		CreatedAt:   &endedAt,
+	}
+}
+
+// WatchSessions summarizes every ended Factory session that has no beat yet,
+// not just the newest one. Workspaces are processed concurrently (sessions
+// within one workspace in order), and each session is claimed while in flight
+// and recorded in the session_end processed file once its beat is written.
+// With Route, a session whose workspace lies in a registered project goes
+// to that project's store instead.
+func (c *HumanCLI) WatchSessions(ctx context.Context, opts WatchSessionsOptions, logger *log.Logger) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.Idle <= 0 {
+		opts.Idle = 10 * time.Minute
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	config := hooks.GetSessionEndConfig(c.store.Dir())
+	if !config.Enabled {
+		return fmt.Errorf("session-end hook is disabled (bt hooks enable session_end)")
+	}
+	runner := hooks.NewSessionEndRunner(c.store.Dir(), config)
+
+	var (
+		mu       sync.Mutex
+		inFlight = make(map[string]bool)      // Session path -> being processed
+		skipped  = make(map[string]time.Time) // Session path -> modification time that was too short or failed
+		commitMu sync.Mutex                   // Sequence numbers are assigned on write
+	)
+
+	stores := map[string]*HumanCLI{c.store.Dir(): c}
+	target := func(session *hooks.FactorySession) (*HumanCLI, error) {
+		if !opts.Route {
+			return c, nil
+		}
+		dir, err := store.RouteWorkspace(session.Workspace)
+		if err != nil || dir == "" {
+			return c, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if t, ok := stores[dir]; ok {
+			return t, nil
+		}
+		s, err := store.NewJSONLStore(dir)
+		if err != nil {
+			return nil, err
+		}
+		stores[dir] = NewHumanCLI(s)
+		return stores[dir], nil
+	}
+
+	existing := func(t *HumanCLI, sessionID string) bool {
+		beats, err := t.store.ReadAll()
+		if err != nil {
+			return false
+		}
+		for _, b := range beats {
+			if b.Impetus.Meta["source"] == "factory-session" && b.Impetus.Meta["session_id"] == sessionID {
+				return true
+			}
+		}
+		return false
+	}
+
+	process := func(path string, mod time.Time) {
+		session, err := runner.ParseSession(path)
+		dest := c
+		if err == nil {
+			dest, err = target(session)
+		}
+		if err == nil && (runner.IsProcessed(session.ID) || existing(dest, session.ID)) {
+			runner.MarkProcessed(session.ID)
+			return
+		}
+		var summary string
+		if err == nil {
+			summary, err = runner.Summarize(session)
+		}
+		if err != nil {
+			if !errors.Is(err, hooks.ErrSessionTooShort) {
+				logger.Printf("%s: %v", filepath.Base(path), err)
+			}
+			mu.Lock()
+			skipped[path] = mod
+			mu.Unlock()
+			return
+		}
+
+		commitMu.Lock()
+		b, err := dest.commit(sessionBeat(session, summary, mod.UTC()), beat.ChannelSession)
+		var held *pipeline.PendingError
+		if err == nil || errors.As(err, &held) {
+			runner.MarkProcessed(session.ID)
+		}
+		commitMu.Unlock()
+		if held != nil {
+			logger.Printf("%s %s", session.ID, held)
+			return
+		}
+		if err != nil {
+			logger.Printf("%s: %v", filepath.Base(path), err)
+			mu.Lock()
+			skipped[path] = mod
+			mu.Unlock()
+			return
+		}
+		if dest != c {
+			logger.Printf("%s -> %s in %s (%s)", session.ID, b.ID, dest.store.Dir(), session.Title)
+			return
+		}
+		logger.Printf("%s -> %s (%s)", session.ID, b.ID, session.Title)
+	}
+
+	sem := make(chan struct{}, opts.Workers)
+	var wg sync.WaitGroup
+	scan := func() {
+		files, err := runner.SessionFiles()
+		if err != nil {
+			logger.Printf("%v", err)
+			return
+		}
+
+		byWorkspace := make(map[string][]string)
+		mods := make(map[string]time.Time)
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil || time.Since(info.ModTime()) < opts.Idle {
+				continue // Session still active
+			}
+			id := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+			mu.Lock()
+			busy := inFlight[path]
+			mod, wasSkipped := skipped[path]
+			mu.Unlock()
+			if busy || (wasSkipped && mod.Equal(info.ModTime())) || runner.IsProcessed(id) {
+				continue
+			}
+			mu.Lock()
+			inFlight[path] = true
+			mu.Unlock()
+			mods[path] = info.ModTime()
+			dir := filepath.Dir(path)
+			byWorkspace[dir] = append(byWorkspace[dir], path)
+		}
+
+		for _, paths := range byWorkspace {
+			sort.Slice(paths, func(i, j int) bool { return mods[paths[i]].Before(mods[paths[j]]) })
+			wg.Add(1)
+			go func(paths []string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				for _, path := range paths {
+					if ctx.Err() == nil {
+						process(path, mods[path])
+					}
+					mu.Lock()
+					delete(inFlight, path)
+					mu.Unlock()
+				}
+			}(paths)
+		}
+	}
+
+	if opts.Once {
+		scan()
+		wg.Wait()
+		return nil
+	}
+
+	logger.Printf("watching %s (every %s, sessions idle for %s)", config.SessionsDir, opts.Interval, opts.Idle)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		scan()
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logger.Printf("stopping")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// WatchBeadsOptions configures the bead provider watcher.
+type WatchBeadsOptions struct {
+	Interval time.Duration // How often the provider is polled
+	Once     bool          // Compare with the cached inventory once and exit
+}
+
+// WatchBeads polls the bead provider and fires the on_bead_changed hook for
+// every bead that appears or changes status, with the beats linked to it and
+// unlinked beats that look related. The first poll compares against
+// beads_cache.json, so changes made while the watcher was stopped are still
+// reported; without a cache it only records a baseline.
+func (c *HumanCLI) WatchBeads(ctx context.Context, opts WatchBeadsOptions, logger *log.Logger) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	manager, err := hooks.NewManager(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	config := manager.BeadChangedConfig()
+	if !config.Enabled {
+		return fmt.Errorf("on_bead_changed hook is disabled (bt hooks enable on_bead_changed)")
+	}
+	provider, err := beads.NewProvider(c.store.Dir())
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		return fmt.Errorf("no bead provider configured (set provider in .beats/%s)", beads.ConfigFile)
+	}
+
+	prev, err := beads.LoadCache(c.store.Dir())
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", beads.CacheFile, err)
+	}
+	baseline := prev == nil
+
+	poll := func() {
+		cur, err := beads.Refresh(c.store.Dir(), provider)
+		if err != nil {
+			logger.Printf("%s: %v", provider.Name(), err)
+			return
+		}
+		if baseline {
+			logger.Printf("baseline: %d beads in %s", len(cur), provider.Name())
+			prev, baseline = cur, false
+			return
+		}
+		for _, change := range beads.Diff(prev, cur) {
+			event := c.beadChangedEvent(change, config.MaxSuggested, logger)
+			if err := manager.OnBeadChanged(event); err != nil {
+				logger.Printf("%s: %v", change.Bead.ID, err)
+				continue
+			}
+			if change.Kind == beads.ChangeCreated {
+				logger.Printf("%s created: %s (%d linked, %d suggested beats)", change.Bead.ID, change.Bead.Title, len(event.LinkedBeats), len(event.SuggestedBeats))
+			} else {
+				logger.Printf("%s %s -> %s: %s (%d linked, %d suggested beats)", change.Bead.ID, change.PreviousStatus, change.Bead.Status, change.Bead.Title, len(event.LinkedBeats), len(event.SuggestedBeats))
+			}
+		}
+		prev = cur
+	}
+
+	if opts.Once {
+		poll()
+		return nil
+	}
+
+	logger.Printf("watching %s (every %s)", provider.Name(), opts.Interval)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		poll()
+		select {
+		case <-ctx.Done():
+			logger.Printf("stopping")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// beadChangedEvent gathers the beats linked to a changed bead and searches
+// for related ones by its title and description. A failed search only costs
+// the suggestions.
+func (c *HumanCLI) beadChangedEvent(change beads.Change, maxSuggested int, logger *log.Logger) hooks.BeadChangedEvent {
+	b := change.Bead
+	event := hooks.BeadChangedEvent{
+		Kind:           change.Kind,
+		BeadID:         b.ID,
+		Title:          b.Title,
+		Status:         b.Status,
+		PreviousStatus: change.PreviousStatus,
+		URL:            b.URL,
+		DetectedAt:     clock.Now().UTC(),
+		LinkedBeats:    []beat.Beat{},
+		SuggestedBeats: []beat.SuggestedBeat{},
+	}
+
+	linked, err := c.store.GetByLinkedBead(b.ID)
+	if err != nil {
+		logger.Printf("%s: linked beats: %v", b.ID, err)
+	} else if linked != nil {
+		event.LinkedBeats = linked
+	}
+
+	exclude := make(map[string]bool, len(event.LinkedBeats))
+	for _, lb := range event.LinkedBeats {
+		exclude[lb.ID] = true
+	}
+	suggested, _, err := similarBeats(c.store, truncate(strings.TrimSpace(b.Text()), maxExpandQuery), exclude, maxSuggested)
+	if err != nil {
+		logger.Printf("%s: related beats: %v", b.ID, err)
+	} else {
+		event.SuggestedBeats = suggested
+	}
+	return event
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestWatchSessionsOnce(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	var summaries atomic.Int32
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := summaries.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]string{"response": fmt.Sprintf("Summary %d", n)})
+	}))
+	defer ollama.Close()
+
+	s, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := t.TempDir()
+	config, _ := json.Marshal(map[string]interface{}{"session_end": map[string]interface{}{
+		"enabled":        true,
+		"ollama_url":     ollama.URL,
+		"min_messages":   1,
+		"sessions_dir":   sessions,
+		"processed_file": filepath.Join(t.TempDir(), "processed"),
+	}})
+	if err := os.WriteFile(filepath.Join(s.Dir(), hooks.HooksConfigFile), config, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ended := time.Now().Add(-time.Hour)
+	for _, f := range []struct {
+		workspace, id string
+		mod           time.Time
+	}{
+		{"-home-me-api", "s1", ended},
+		{"-home-me-api", "s2", ended.Add(time.Minute)},
+		{"-home-me-web", "s3", ended},
+		{"-home-me-web", "live", time.Now()}, // Still being written
+	} {
+		dir := filepath.Join(sessions, f.workspace)
+		path := filepath.Join(dir, f.id+".jsonl")
+		data := `{"title":"Session ` + f.id + `"}` + "\n" +
+			`{"type":"message","message":{"role":"user","content":[{"type":"text","text":"Fix the flaky sync test"}]}}` + "\n"
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.mod, f.mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewHumanCLI(s)
+	opts := WatchSessionsOptions{Once: true, Idle: 10 * time.Minute}
+	logger := log.New(io.Discard, "", 0)
+	if err := c.WatchSessions(context.Background(), opts, logger); err != nil {
+		t.Fatal(err)
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, b := range beats {
+		if b.Impetus.Meta["source"] != "factory-session" {
+			t.Errorf("beat %s has source %q", b.ID, b.Impetus.Meta["source"])
+		}
+		if _, dup := got[b.Impetus.Meta["session_id"]]; dup {
+			t.Errorf("session %s captured twice", b.Impetus.Meta["session_id"])
+		}
+		got[b.Impetus.Meta["session_id"]] = b.Impetus.Meta["workspace"]
+	}
+	want := map[string]string{"s1": "-home-me-api", "s2": "-home-me-api", "s3": "-home-me-web"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("sessions captured = %v, want %v", got, want)
+	}
+
+	// Nothing new on a second run, without asking the model again
+	calls := summaries.Load()
+	if err := c.WatchSessions(context.Background(), opts, logger); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.ReadAll(); len(again) != len(beats) || summaries.Load() != calls {
+		t.Errorf("second run: %d beat(s) after %d, %d more summaries; want none", len(again), len(beats), summaries.Load()-calls)
+	}
+}
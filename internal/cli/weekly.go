@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// WeeklyKind marks a weekly review beat in impetus.meta["kind"].
+const WeeklyKind = "weekly_review"
+
+// ReviewsDir holds weekly review files, relative to the beats directory.
+const ReviewsDir = "reviews"
+
+// WeeklyOptions configures bt weekly.
+type WeeklyOptions struct {
+	Week   string // ISO week, e.g. 2025-W49 (default: the current week)
+	Output string // Review file (default .beats/reviews/<week>.md or .html)
+	Format string // "md" (default) or FormatHTML
+	NoBeat bool   // Only write the file
+	JSON   bool
+}
+
+// WeeklyReview is the result of a weekly review run.
+type WeeklyReview struct {
+	Week    string    `json:"week"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"` // Exclusive
+	Sources []string  `json:"sources"`
+	Model   string    `json:"model"`
+	File    string    `json:"file"`
+	ID      string    `json:"id,omitempty"` // The review beat
+	Review  string    `json:"review"`
+}
+
+var isoWeekRe = regexp.MustCompile(`^(\d{4})-?W(\d{2})$`)
+
+// ParseISOWeek returns the Monday that starts an ISO week such as 2025-W49,
+// at midnight in loc.
+func ParseISOWeek(s string, loc *time.Location) (time.Time, error) {
+	m := isoWeekRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid week %q (use YYYY-Www, e.g. 2025-W49)", s)
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	// January 4th is always in week 1
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid week %q: %d has no week %d", s, year, week)
+	}
+	return monday, nil
+}
+
+// isoWeekOf names the ISO week containing t.
+func isoWeekOf(t time.Time) string {
+	y, w := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", y, w)
+}
+
+// weeklyPrompt asks for the retrospective in fixed markdown sections.
+func weeklyPrompt(week string, beats []beat.Beat) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "These are the notes I captured during week %s. Write a weekly retrospective in markdown with exactly these sections:\n\n", week)
+	sb.WriteString("## Themes\nThe recurring threads of the week.\n\n")
+	sb.WriteString("## Decisions\nWhat was decided, and why.\n\n")
+	sb.WriteString("## Open Loops\nQuestions and commitments still unresolved.\n\n")
+	sb.WriteString("## Proposed Beads\nConcrete work items worth tracking, one per bullet, as \"title: why\".\n\n")
+	sb.WriteString("Cite the notes you draw on by their ID in brackets, e.g. [beat-20250101-001]. Be specific and brief, no preamble.\n\nNotes:\n")
+	for _, b := range beats {
+		fmt.Fprintf(&sb, "- [%s] %s (%s): %s\n", b.ID, displayDate(b.CreatedAt), b.Impetus.Label, truncate(strings.Join(strings.Fields(b.Content), " "), 600))
+	}
+	return sb.String()
+}
+
+// Weekly assembles a week's beats, runs the retrospective prompt through the
+// configured LLM, writes the review to a markdown file and stores it as a
+// "Weekly review" beat that references the file and its sources.
+func (c *HumanCLI) Weekly(opts WeeklyOptions) error {
+	format := opts.Format
+	if format == "" {
+		format = "md"
+	}
+	if format != "md" && format != FormatHTML {
+		return fmt.Errorf("unknown format %q (use md or %s)", format, FormatHTML)
+	}
+	week := opts.Week
+	if week == "" {
+		week = isoWeekOf(clock.Now().In(DisplayLocation))
+	}
+	start, err := ParseISOWeek(week, DisplayLocation)
+	if err != nil {
+		return err
+	}
+	week = isoWeekOf(start)
+	end := start.AddDate(0, 0, 7)
+
+	all, err := c.store.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read beats: %w", err)
+	}
+	var beats []beat.Beat
+	for _, b := range all {
+		switch kind := b.Impetus.Meta["kind"]; {
+		case kind == WeeklyKind && b.Impetus.Meta["week"] == week && !opts.NoBeat:
+			return fmt.Errorf("week %s was already reviewed in %s (use --no-beat to only rewrite the file)", week, b.ID)
+		case kind == DigestKind || kind == WeeklyKind:
+			continue
+		}
+		if !b.CreatedAt.Before(start) && b.CreatedAt.Before(end) {
+			beats = append(beats, b)
+		}
+	}
+	if len(beats) == 0 {
+		return fmt.Errorf("no beats in week %s (%s to %s)", week, start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+	sort.SliceStable(beats, func(i, j int) bool { return beats[i].CreatedAt.Before(beats[j].CreatedAt) })
+
+	review, model, err := llmGenerate(context.Background(), c.store.Dir(), weeklyPrompt(week, beats))
+	if err != nil {
+		return fmt.Errorf("weekly review needs the LLM (llm_model %s): %w", model, err)
+	}
+
+	r := &WeeklyReview{Week: week, Start: start.UTC(), End: end.UTC(), Sources: []string{}, Model: model, Review: review, File: opts.Output}
+	for _, b := range beats {
+		r.Sources = append(r.Sources, b.ID)
+	}
+	if r.File == "" {
+		r.File = filepath.Join(c.store.Dir(), ReviewsDir, week+"."+format)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.File), 0755); err != nil {
+		return err
+	}
+	data := []byte(r.markdown())
+	if format == FormatHTML {
+		if data, err = r.htmlPage().Render(); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(r.File, data, 0644); err != nil {
+		return fmt.Errorf("failed to write review: %w", err)
+	}
+
+	if !opts.NoBeat {
+		b, err := c.commit(r.proposedBeat(), beat.ChannelGenerated)
+		if err != nil {
+			return fmt.Errorf("review written to %s, but the beat failed: %w", r.File, err)
+		}
+		r.ID = b.ID
+	}
+
+	if opts.JSON {
+		return outputJSON(r)
+	}
+	fmt.Printf("Wrote weekly review for %s (%d beats) to %s\n", week, len(beats), r.File)
+	if r.ID != "" {
+		fmt.Printf("Created beat: %s\n", r.ID)
+	}
+	return nil
+}
+
+// markdown renders the review file.
+func (r *WeeklyReview) markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Weekly Review %s\n\n", r.Week)
+	fmt.Fprintf(&sb, "%s to %s, %d beats, generated by %s\n\n", displayDate(r.Start), displayDate(r.End.Add(-time.Second)), len(r.Sources), r.Model)
+	sb.WriteString(strings.TrimSpace(r.Review))
+	sb.WriteString("\n\n## Sources\n\n")
+	for _, id := range r.Sources {
+		fmt.Fprintf(&sb, "- %s\n", id)
+	}
+	return sb.String()
+}
+
+// htmlPage renders the review as a standalone page.
+func (r *WeeklyReview) htmlPage() HTMLPage {
+	var sources strings.Builder
+	for _, id := range r.Sources {
+		fmt.Fprintf(&sources, "- %s\n", id)
+	}
+	return HTMLPage{
+		Title:    "Weekly Review " + r.Week,
+		Subtitle: fmt.Sprintf("%s to %s, %d beats, generated by %s", displayDate(r.Start), displayDate(r.End.Add(-time.Second)), len(r.Sources), r.Model),
+		Sections: []HTMLSection{
+			{Body: markdownHTML(r.Review)},
+			{Title: "Sources", Body: markdownHTML(sources.String())},
+		},
+	}
+}
+
+// proposedBeat is the review as a beat, dated at the end of its week (or
+// now, for the current week) so it sorts after its sources.
+func (r *WeeklyReview) proposedBeat() *beat.ProposedBeat {
+	createdAt := clock.Now().UTC()
+	if createdAt.After(r.End) {
+		createdAt = r.End.Add(-time.Second)
+	}
+	refs := []beat.Reference{{Kind: "file", Locator: r.File, Label: "Weekly review " + r.Week}}
+	for _, id := range r.Sources {
+		refs = append(refs, beat.Reference{Kind: "beat", Locator: id})
+	}
+	return &beat.ProposedBeat{
+		Content: fmt.Sprintf("Weekly review %s\n\n%s", r.Week, strings.TrimSpace(r.Review)),
+		Impetus: beat.Impetus{
+			Label: "Weekly review",
+			Raw:   r.Week,
+			Meta: map[string]string{
+				"kind":          WeeklyKind,
+				"source":        "weekly",
+				"week":          r.Week,
+				"sources":       strings.Join(r.Sources, ","),
+				"review_file":   r.File,
+				"summary_model": r.Model,
+			},
+		},
+		References:  refs,
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+		CreatedAt:   &createdAt,
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestParseISOWeek(t *testing.T) {
+	for week, want := range map[string]string{
+		"2025-W49": "2025-12-01",
+		"2025-W01": "2024-12-30", // Week 1 starts in the previous year
+		"2026-W53": "2026-12-28",
+		"2020W01":  "2019-12-30",
+	} {
+		got, err := ParseISOWeek(week, time.UTC)
+		if err != nil || got.Format("2006-01-02") != want {
+			t.Errorf("ParseISOWeek(%q) = %v, %v; want %s", week, got, err, want)
+		}
+	}
+	for _, week := range []string{"2025-W00", "2025-W53", "2025-12", "W49"} {
+		if _, err := ParseISOWeek(week, time.UTC); err == nil {
+			t.Errorf("ParseISOWeek(%q) succeeded; want an error", week)
+		}
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	defer func(loc *time.Location) { DisplayLocation = loc }(DisplayLocation)
+	DisplayLocation = time.UTC
+	defer func(f func(context.Context, string, string) (string, string, error)) { llmGenerate = f }(llmGenerate)
+	var prompts []string
+	llmGenerate = func(_ context.Context, _, prompt string) (string, string, error) {
+		prompts = append(prompts, prompt)
+		return "## Themes\n- Pricing [beat-20260310-001]", "test-model", nil
+	}
+
+	dir := t.TempDir()
+	s, err := store.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(v string) time.Time { ts, _ := time.Parse(time.RFC3339, v); return ts }
+	var beats []*beat.Beat
+	for _, b := range []struct{ id, at, kind string }{
+		{"beat-20260308-001", "2026-03-08T23:00:00Z", ""}, // Sunday of the week before
+		{"beat-20260310-001", "2026-03-10T09:00:00Z", ""},
+		{"beat-20260310-002", "2026-03-10T17:00:00Z", DigestKind},
+		{"beat-20260315-001", "2026-03-15T22:00:00Z", ""},
+	} {
+		beats = append(beats, &beat.Beat{ID: b.id, CreatedAt: at(b.at), UpdatedAt: at(b.at),
+			Impetus: beat.Impetus{Label: "Manual entry", Meta: map[string]string{"kind": b.kind}}, Content: "notes " + b.id})
+	}
+	if err := s.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewHumanCLI(s)
+	if err := c.Weekly(WeeklyOptions{Week: "2026-W11"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "beat-20260315-001") ||
+		strings.Contains(prompts[0], "beat-20260308-001") || strings.Contains(prompts[0], "beat-20260310-002") {
+		t.Errorf("prompt = %q; want the week's two captures only", prompts)
+	}
+
+	data, err := os.ReadFile(dir + "/reviews/2026-W11.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "## Themes") || !strings.Contains(string(data), "- beat-20260310-001\n") {
+		t.Errorf("review file = %q", data)
+	}
+
+	all, err := s.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := all[len(all)-1]
+	if r.Impetus.Meta["kind"] != WeeklyKind || r.Impetus.Meta["week"] != "2026-W11" ||
+		r.Impetus.Meta["sources"] != "beat-20260310-001,beat-20260315-001" || len(r.References) != 3 || r.References[0].Kind != "file" {
+		t.Errorf("review beat = %+v", r)
+	}
+	if !r.CreatedAt.Before(at("2026-03-16T00:00:00Z")) {
+		t.Errorf("review beat dated %v; want within the week", r.CreatedAt)
+	}
+
+	// The week is reviewed; only the file may be rewritten
+	if err := c.Weekly(WeeklyOptions{Week: "2026-W11"}); err == nil {
+		t.Error("second review succeeded; want an error")
+	}
+	if err := c.Weekly(WeeklyOptions{Week: "2026-W11", NoBeat: true}); err != nil {
+		t.Errorf("--no-beat rerun: %v", err)
+	}
+	if err := c.Weekly(WeeklyOptions{Week: "2026-W11", NoBeat: true, Format: FormatHTML}); err != nil {
+		t.Fatalf("--format html: %v", err)
+	}
+	page, err := os.ReadFile(dir + "/reviews/2026-W11.html")
+	if err != nil || !strings.Contains(string(page), "<title>Weekly Review 2026-W11</title>") {
+		t.Errorf("html review = %q, %v; want a page titled with the week", page, err)
+	}
+}
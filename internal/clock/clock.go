@@ -0,0 +1,158 @@
+// Package clock is the current time as beats sees it. Setting BEATS_FAKE_NOW
+// (or passing --fixed-time) freezes it, so the IDs and timestamps commands
+// print are the same on every run: for golden-file tests and for agents
+// testing against stable output.
+package clock
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeNowEnvVar freezes the clock at the time it holds.
+const FakeNowEnvVar = "BEATS_FAKE_NOW"
+
+var (
+	mu      sync.RWMutex
+	fixed   *time.Time
+	envOnce sync.Once
+)
+
+// loadEnv freezes the clock from BEATS_FAKE_NOW. A value that does not parse
+// is reported and ignored.
+func loadEnv() {
+	v := strings.TrimSpace(os.Getenv(FakeNowEnvVar))
+	if v == "" {
+		return
+	}
+	t, err := Parse(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", FakeNowEnvVar, err)
+		return
+	}
+	fixed = &t
+}
+
+// Now returns the frozen time if there is one, else the current time.
+func Now() time.Time {
+	envOnce.Do(loadEnv)
+	mu.RLock()
+	defer mu.RUnlock()
+	if fixed != nil {
+		return *fixed
+	}
+	return time.Now()
+}
+
+// Fixed reports whether the clock is frozen.
+func Fixed() bool {
+	envOnce.Do(loadEnv)
+	mu.RLock()
+	defer mu.RUnlock()
+	return fixed != nil
+}
+
+// Set freezes the clock at t, overriding BEATS_FAKE_NOW.
+func Set(t time.Time) {
+	envOnce.Do(loadEnv)
+	mu.Lock()
+	defer mu.Unlock()
+	fixed = &t
+}
+
+// Reset lets the clock run again.
+func Reset() {
+	envOnce.Do(loadEnv)
+	mu.Lock()
+	defer mu.Unlock()
+	fixed = nil
+}
+
+// Parse reads a fixed time: RFC 3339, a date (midnight UTC) or Unix seconds.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use RFC 3339, YYYY-MM-DD or Unix seconds)", s)
+}
+
+// ParseRelative parses a date string that can be:
+// - ISO8601 datetime (e.g., "2024-01-15", "2024-01-15T10:30:00Z")
+// - Relative string (e.g., "yesterday", "3d ago", "1 week ago")
+// Returns error if date is in the future.
+func ParseRelative(s string) (time.Time, error) {
+	now := Now().UTC()
+	s = strings.TrimSpace(s)
+
+	// Try ISO8601 formats first
+	formats := []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, s); err == nil {
+			t = t.UTC()
+			if t.After(now) {
+				return time.Time{}, fmt.Errorf("date cannot be in the future")
+			}
+			return t, nil
+		}
+	}
+	s = strings.ToLower(s)
+
+	// Handle relative dates
+	var result time.Time
+	switch s {
+	case "yesterday":
+		result = now.AddDate(0, 0, -1)
+	case "today":
+		result = now
+	default:
+		// Parse patterns like "3h ago", "3d ago", "1 week ago", "2 weeks ago", "1w ago"
+		s = strings.TrimSuffix(s, " ago")
+		s = strings.TrimSpace(s)
+
+		// Try patterns: "3d", "3 days", "1w", "1 week", "2 weeks"
+		var num int
+		var unit string
+
+		// Try "Nd" or "Nw" format
+		if n, err := fmt.Sscanf(s, "%d%s", &num, &unit); err == nil && n == 2 {
+			unit = strings.TrimSpace(unit)
+		} else if n, err := fmt.Sscanf(s, "%d %s", &num, &unit); err == nil && n == 2 {
+			// "N unit" format
+		} else {
+			return time.Time{}, fmt.Errorf("unrecognized date format: %s", s)
+		}
+
+		switch unit {
+		case "h", "hour", "hours":
+			result = now.Add(-time.Duration(num) * time.Hour)
+		case "d", "day", "days":
+			result = now.AddDate(0, 0, -num)
+		case "w", "week", "weeks":
+			result = now.AddDate(0, 0, -num*7)
+		case "m", "month", "months":
+			result = now.AddDate(0, -num, 0)
+		default:
+			return time.Time{}, fmt.Errorf("unrecognized time unit: %s", unit)
+		}
+	}
+
+	if result.After(now) {
+		return time.Time{}, fmt.Errorf("date cannot be in the future")
+	}
+	return result, nil
+}
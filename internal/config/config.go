@@ -0,0 +1,385 @@
+// Package config holds the settings shared across beats: where the store
+// lives, which Ollama server and models to use, and which time zone dates
+// are shown and IDs are dated in. Each setting has a
+// default, can be set in the user's config file, and can be overridden by
+// an environment variable.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the user config file, in the beats directory under
+// os.UserConfigDir (e.g. ~/.config/beats/config.json).
+const FileName = "config.json"
+
+// PathEnvVar points at a config file to use instead of the default one.
+const PathEnvVar = "BEATS_CONFIG"
+
+// Defaults for settings that are not configured.
+const (
+	DefaultOllamaURL    = "http://localhost:11434"
+	DefaultEmbedModel   = "nomic-embed-text"
+	DefaultLLMModel     = "mistral:latest"
+	DefaultTimezone     = "Local"
+	DefaultIDDates      = IDDatesUTC
+	DefaultPromptTokens = 6000
+	DefaultEntities     = EntitiesHeuristic
+)
+
+// Values of the entity_extraction setting: how entities are pulled from a
+// beat's content when it is committed.
+const (
+	EntitiesHeuristic = "heuristic" // URLs, WALD cooperators and directories, capitalized phrases
+	EntitiesLLM       = "llm"       // Heuristics plus typed entities and relations from the LLM
+	EntitiesOff       = "off"
+)
+
+// Values of the id_dates setting: the calendar day in a new beat's ID is the
+// UTC day of its creation, or the day in the display time zone.
+const (
+	IDDatesUTC   = "utc"
+	IDDatesLocal = "local"
+)
+
+// Sources a setting's value can come from.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env" // Shown as env:NAME
+)
+
+// Config is the effective configuration.
+type Config struct {
+	Store        string `json:"store,omitempty"`             // Global beats store
+	Root         string `json:"root,omitempty"`              // Werk root for cross-project commands; empty to detect
+	OllamaURL    string `json:"ollama_url,omitempty"`        // Ollama server for embeddings and generation
+	EmbedModel   string `json:"embed_model,omitempty"`       // Embedding model for new stores, search and inference
+	LLMModel     string `json:"llm_model,omitempty"`         // Generation model for summaries
+	Timezone     string `json:"timezone,omitempty"`          // IANA zone times are shown in; Local for the system's
+	IDDates      string `json:"id_dates,omitempty"`          // utc or local: which day new IDs are dated by
+	ShowStreak   string `json:"show_streak,omitempty"`       // true to show the capture streak after bt add
+	PromptTokens string `json:"prompt_tokens,omitempty"`     // Token budget of generated prompts; 0 for none
+	Entities     string `json:"entity_extraction,omitempty"` // heuristic, llm or off: entity extraction on commit
+	LLMLog       string `json:"llm_log,omitempty"`           // File Ollama requests are logged to as JSON lines; - for stderr
+
+	// Redaction, URLSubtypes and ServeTokens are read from the file only:
+	// they have no environment override.
+	Redaction   Redaction    `json:"redaction,omitzero"`
+	URLSubtypes []URLSubtype `json:"url_subtypes,omitempty"`
+	ServeTokens []ServeToken `json:"serve_tokens,omitempty"`
+
+	// Path is the config file read, and Sources where each setting came from.
+	Path    string            `json:"-"`
+	Sources map[string]string `json:"-"`
+}
+
+// Redaction names what is stripped from text sent to an LLM provider.
+// Providers map a provider ("ollama", "remote", ...) to one of the profiles;
+// a provider without a profile gets full content.
+type Redaction struct {
+	Profiles  map[string]RedactionProfile `json:"profiles,omitempty"`
+	Providers map[string]string           `json:"providers,omitempty"`
+	Default   string                      `json:"default_provider,omitempty"` // Provider of robot outputs that name none
+}
+
+// RedactionProfile is one set of redaction rules.
+type RedactionProfile struct {
+	Entities []string `json:"entities,omitempty"` // Entity categories whose labels are stripped, e.g. person
+	Terms    []string `json:"terms,omitempty"`    // Names always stripped, e.g. clients
+	Amounts  bool     `json:"amounts,omitempty"`  // Strip money amounts
+	Patterns []string `json:"patterns,omitempty"` // Extra regular expressions
+}
+
+// URLSubtype classifies URLs found in captured text. A URL matches when its
+// host is Host or a subdomain of it and, if set, the whole URL matches the
+// regular expression Pattern. Configured rules are tried before the built-in
+// ones (github, youtube, twitter, pdf).
+type URLSubtype struct {
+	Host    string `json:"host,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Subtype string `json:"subtype"`
+}
+
+// ServeToken is an extra token for bt serve-capture, limited to scopes:
+// read, capture, link, or full for all three.
+type ServeToken struct {
+	Name   string   `json:"name"`
+	Token  string   `json:"token"`
+	Scopes []string `json:"scopes"`
+}
+
+// Setting is one configured value and its source, for display.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Env    string `json:"env"` // Variables that override it, highest first
+}
+
+// setting describes how a key is resolved.
+type setting struct {
+	key   string
+	env   []string // Highest precedence first
+	field func(*Config) *string
+	def   func() string
+}
+
+var settings = []setting{
+	{"store", []string{"BEATS_DIR"}, func(c *Config) *string { return &c.Store }, defaultStore},
+	{"root", []string{"BEATS_ROOT"}, func(c *Config) *string { return &c.Root }, func() string { return "" }},
+	{"ollama_url", []string{"BEATS_OLLAMA_URL", "OLLAMA_HOST"}, func(c *Config) *string { return &c.OllamaURL }, func() string { return DefaultOllamaURL }},
+	{"embed_model", []string{"BEATS_EMBED_MODEL"}, func(c *Config) *string { return &c.EmbedModel }, func() string { return DefaultEmbedModel }},
+	{"llm_model", []string{"BEATS_LLM_MODEL"}, func(c *Config) *string { return &c.LLMModel }, func() string { return DefaultLLMModel }},
+	{"timezone", []string{"BEATS_TZ"}, func(c *Config) *string { return &c.Timezone }, func() string { return DefaultTimezone }},
+	{"id_dates", []string{"BEATS_ID_DATES"}, func(c *Config) *string { return &c.IDDates }, func() string { return DefaultIDDates }},
+	{"show_streak", []string{"BEATS_SHOW_STREAK"}, func(c *Config) *string { return &c.ShowStreak }, func() string { return "false" }},
+	{"prompt_tokens", []string{"BEATS_PROMPT_TOKENS"}, func(c *Config) *string { return &c.PromptTokens }, func() string { return strconv.Itoa(DefaultPromptTokens) }},
+	{"entity_extraction", []string{"BEATS_ENTITY_EXTRACTION"}, func(c *Config) *string { return &c.Entities }, func() string { return DefaultEntities }},
+	{"llm_log", []string{"BEATS_LLM_LOG"}, func(c *Config) *string { return &c.LLMLog }, func() string { return "" }},
+}
+
+// defaultStore is the global store under the werk directory in $HOME.
+func defaultStore() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".beats"
+	}
+	return filepath.Join(home, "werk", ".beats")
+}
+
+// Path returns the config file: BEATS_CONFIG, else config.json in the user
+// config directory, or "" when there is none.
+func Path() string {
+	if p := os.Getenv(PathEnvVar); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "beats", FileName)
+}
+
+// Load resolves every setting: environment, then the config file, then the
+// default. A missing file is not an error.
+func Load() (*Config, error) {
+	path := Path()
+	var file Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &file); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", path, err)
+			}
+		}
+	}
+	return resolve(file, path), nil
+}
+
+// Update writes settings to the config file, keeping everything else in
+// it; an empty value removes the setting. Keys are the ones config show
+// lists.
+func Update(values map[string]string) error {
+	path := Path()
+	if path == "" {
+		return fmt.Errorf("no user config directory to write %s to", FileName)
+	}
+	raw := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+	for key, value := range values {
+		known := false
+		for _, s := range settings {
+			known = known || s.key == key
+		}
+		if !known {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+		if value == "" {
+			delete(raw, key)
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		raw[key] = encoded
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0644)
+}
+
+var warnOnce sync.Once
+
+// Get returns the effective configuration. A config file that cannot be
+// read is reported once on stderr and ignored.
+func Get() *Config {
+	cfg, err := Load()
+	if err != nil {
+		warnOnce.Do(func() { fmt.Fprintf(os.Stderr, "Warning: ignoring config file: %v\n", err) })
+		return resolve(Config{}, Path())
+	}
+	return cfg
+}
+
+// resolve applies the environment and defaults to the file's settings.
+func resolve(file Config, path string) *Config {
+	cfg := &Config{Path: path, Sources: make(map[string]string)}
+	for _, s := range settings {
+		value, source := "", SourceDefault
+		for _, name := range s.env {
+			if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+				value, source = v, SourceEnv+":"+name
+				break
+			}
+		}
+		if value == "" {
+			if v := *s.field(&file); v != "" {
+				value, source = v, SourceFile
+			}
+		}
+		if value == "" {
+			value = s.def()
+		}
+		*s.field(cfg) = value
+		cfg.Sources[s.key] = source
+	}
+	cfg.Store = ExpandHome(cfg.Store)
+	cfg.Root = ExpandHome(cfg.Root)
+	cfg.OllamaURL = normalizeURL(cfg.OllamaURL)
+	cfg.IDDates = strings.ToLower(cfg.IDDates)
+	cfg.Entities = strings.ToLower(cfg.Entities)
+	cfg.Redaction = file.Redaction
+	cfg.URLSubtypes = file.URLSubtypes
+	cfg.ServeTokens = file.ServeTokens
+	return cfg
+}
+
+// Location returns the display time zone. An unknown zone name is an error.
+func (c *Config) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// IDLocation returns the time zone whose calendar day new beat IDs carry.
+func (c *Config) IDLocation() (*time.Location, error) {
+	switch c.IDDates {
+	case IDDatesUTC:
+		return time.UTC, nil
+	case IDDatesLocal:
+		return c.Location()
+	default:
+		return nil, fmt.Errorf("invalid id_dates %q: use %s or %s", c.IDDates, IDDatesUTC, IDDatesLocal)
+	}
+}
+
+// Streak reports whether bt add shows the capture streak. Anything but a
+// true value (true, 1, yes, on) leaves it off.
+func (c *Config) Streak() bool {
+	switch strings.ToLower(c.ShowStreak) {
+	case "true", "1", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// PromptBudget returns the token budget prompts built from beats are fitted
+// into, 0 for no limit. A value that is not a number gets the default.
+func (c *Config) PromptBudget() int {
+	n, err := strconv.Atoi(strings.TrimSpace(c.PromptTokens))
+	if err != nil || n < 0 {
+		return DefaultPromptTokens
+	}
+	return n
+}
+
+// EntityExtraction returns how entities are extracted on commit. An unknown
+// value gets the default.
+func (c *Config) EntityExtraction() string {
+	switch c.Entities {
+	case EntitiesHeuristic, EntitiesLLM, EntitiesOff:
+		return c.Entities
+	}
+	return DefaultEntities
+}
+
+// RedactionFor returns the profile for text sent to provider, or nil when
+// it gets full content. A provider mapped to an unknown profile is an error
+// rather than a silent pass-through.
+func (c *Config) RedactionFor(provider string) (*RedactionProfile, error) {
+	if provider == "" {
+		provider = c.Redaction.Default
+	}
+	name, ok := c.Redaction.Providers[strings.ToLower(provider)]
+	if !ok || name == "" {
+		return nil, nil
+	}
+	p, ok := c.Redaction.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("provider %q uses unknown redaction profile %q", provider, name)
+	}
+	return &p, nil
+}
+
+// ExpandHome replaces a leading ~ with the home directory. Both ~/ and, for
+// Windows users, ~\ are recognized.
+func ExpandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, `~\`) {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
+
+// Settings lists every setting with its value and source.
+func (c *Config) Settings() []Setting {
+	out := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		out = append(out, Setting{
+			Key:    s.key,
+			Value:  *s.field(c),
+			Source: c.Sources[s.key],
+			Env:    strings.Join(s.env, ", "),
+		})
+	}
+	return out
+}
+
+// normalizeURL accepts OLLAMA_HOST-style host:port values.
+func normalizeURL(u string) string {
+	u = strings.TrimRight(u, "/")
+	if u != "" && !strings.Contains(u, "://") {
+		u = "http://" + u
+	}
+	return u
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	for _, name := range []string{"BEATS_DIR", "BEATS_ROOT", "BEATS_OLLAMA_URL", "OLLAMA_HOST", "BEATS_EMBED_MODEL", "BEATS_LLM_MODEL", "BEATS_TZ", "BEATS_ID_DATES"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HOME", "/home/tester")
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(PathEnvVar, path)
+
+	// No file: defaults
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OllamaURL != DefaultOllamaURL || cfg.EmbedModel != DefaultEmbedModel || cfg.Store != "/home/tester/werk/.beats" || cfg.Sources["store"] != SourceDefault {
+		t.Errorf("defaults = %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"llm_model": "llama3.2", "ollama_url": "http://gpu:11434/", "store": "/data/beats"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OLLAMA_HOST", "127.0.0.1:11435")
+	t.Setenv("BEATS_DIR", "/tmp/beats")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][2]string{
+		"store":       {"/tmp/beats", "env:BEATS_DIR"},
+		"ollama_url":  {"http://127.0.0.1:11435", "env:OLLAMA_HOST"},
+		"llm_model":   {"llama3.2", SourceFile},
+		"embed_model": {DefaultEmbedModel, SourceDefault},
+	}
+	for _, s := range cfg.Settings() {
+		if w, ok := want[s.Key]; ok && (s.Value != w[0] || s.Source != w[1]) {
+			t.Errorf("%s = %q from %s, want %q from %s", s.Key, s.Value, s.Source, w[0], w[1])
+		}
+	}
+
+	// BEATS_OLLAMA_URL outranks OLLAMA_HOST
+	t.Setenv("BEATS_OLLAMA_URL", "http://other:1/")
+	if cfg, _ := Load(); cfg.OllamaURL != "http://other:1" {
+		t.Errorf("ollama_url = %q, want BEATS_OLLAMA_URL without the trailing slash", cfg.OllamaURL)
+	}
+
+	// A broken file fails Load; Get falls back to env and defaults
+	if err := os.WriteFile(path, []byte(`{"store": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Load accepted invalid JSON")
+	}
+	if cfg := Get(); cfg.Store != "/tmp/beats" || cfg.LLMModel != DefaultLLMModel {
+		t.Errorf("Get with a broken file = %+v", cfg)
+	}
+}
+
+func TestTimezones(t *testing.T) {
+	t.Setenv(PathEnvVar, filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_TZ", "")
+	t.Setenv("BEATS_ID_DATES", "")
+	cfg, _ := Load()
+	if loc, err := cfg.IDLocation(); err != nil || loc != time.UTC {
+		t.Errorf("default IDLocation() = %v, %v; want UTC", loc, err)
+	}
+
+	t.Setenv("BEATS_TZ", "America/New_York")
+	t.Setenv("BEATS_ID_DATES", "Local")
+	cfg, _ = Load()
+	loc, err := cfg.IDLocation()
+	if err != nil || loc.String() != "America/New_York" {
+		t.Errorf("local IDLocation() = %v, %v; want the display zone", loc, err)
+	}
+
+	t.Setenv("BEATS_TZ", "Mars/Olympus")
+	cfg, _ = Load()
+	if _, err := cfg.Location(); err == nil {
+		t.Error("Location() accepted an unknown zone")
+	}
+	t.Setenv("BEATS_ID_DATES", "tomorrow")
+	cfg, _ = Load()
+	if _, err := cfg.IDLocation(); err == nil {
+		t.Error("IDLocation() accepted an unknown id_dates")
+	}
+}
+
+func TestRedactionFor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv(PathEnvVar, path)
+	data := `{"redaction": {
+		"profiles": {"clients": {"entities": ["person"], "terms": ["Acme"], "amounts": true}},
+		"providers": {"openai": "clients", "broken": "missing"},
+		"default_provider": "openai"
+	}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := cfg.RedactionFor("")
+	if err != nil || p == nil || !p.Amounts || len(p.Terms) != 1 {
+		t.Errorf("default provider profile = %+v, %v", p, err)
+	}
+	if p, err := cfg.RedactionFor("ollama"); p != nil || err != nil {
+		t.Errorf("unmapped provider = %+v, %v; want full content", p, err)
+	}
+	if _, err := cfg.RedactionFor("broken"); err == nil {
+		t.Error("unknown profile accepted")
+	}
+}
@@ -0,0 +1,252 @@
+// Package daemon keeps derived indexes (SQLite FTS, embeddings) in sync with
+// beats.jsonl in the background and, optionally, is the single writer of the
+// store for every other process.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/metrics"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// PIDFile marks a running daemon inside the .beats directory.
+const PIDFile = "daemon.pid"
+
+// Options configures the daemon.
+type Options struct {
+	Interval time.Duration  // How often beats.jsonl is checked for changes
+	Embed    bool           // Compute missing embeddings when Ollama is available
+	Model    string         // Embedding model (default: embed_model in the config)
+	Socket   bool           // Accept other processes' writes on .beats/daemon.sock
+	Metrics  string         // Serve /healthz and /metrics on this address when set
+	Daily    func()         // Called at startup and whenever the date changes
+	Location *time.Location // Whose date Daily follows (default: local time)
+}
+
+// Daemon watches beats.jsonl and incrementally maintains the indexes.
+type Daemon struct {
+	jsonl  *store.JSONLStore
+	opts   Options
+	logger *log.Logger
+
+	lastMod  time.Time
+	lastSize int64
+	lastDay  string
+}
+
+// New creates a daemon for the given store.
+func New(jsonl *store.JSONLStore, opts Options, logger *log.Logger) *Daemon {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	return &Daemon{jsonl: jsonl, opts: opts, logger: logger}
+}
+
+// Run indexes once, then polls for changes until ctx is cancelled.
+func (d *Daemon) Run(ctx context.Context) error {
+	pidPath := filepath.Join(d.jsonl.Dir(), PIDFile)
+	if pid, running := RunningPID(d.jsonl.Dir()); running {
+		return fmt.Errorf("daemon already running (pid %d)", pid)
+	}
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	defer os.Remove(pidPath)
+
+	sqlite, err := store.NewSQLiteStore(d.jsonl)
+	if err != nil {
+		return err
+	}
+	defer sqlite.Close()
+
+	if d.opts.Socket {
+		stop, err := d.serveWrites()
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+	if d.opts.Metrics != "" {
+		stop, err := d.serveMetrics()
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	d.logger.Printf("watching %s (every %s)", d.jsonl.Path(), d.opts.Interval)
+
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if d.changed() {
+			d.index(ctx, sqlite)
+		}
+		d.daily()
+		select {
+		case <-ctx.Done():
+			d.logger.Printf("stopping")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// serveWrites listens on the store's write socket until stop is called.
+func (d *Daemon) serveWrites() (stop func(), err error) {
+	path := d.jsonl.SocketPath()
+	os.Remove(path) // Left behind by a daemon that was killed
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil && runtime.GOOS != "windows" {
+		ln.Close()
+		return nil, err
+	}
+	srv := &http.Server{Handler: d.jsonl.WriteHandler()}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("write socket: %v", err)
+		}
+	}()
+	d.logger.Printf("accepting writes on %s", path)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+		os.Remove(path)
+	}, nil
+}
+
+// serveMetrics serves /healthz and /metrics until stop is called.
+func (d *Daemon) serveMetrics() (stop func(), err error) {
+	ln, err := net.Listen("tcp", d.opts.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", d.opts.Metrics, err)
+	}
+	mux := http.NewServeMux()
+	metrics.Register(mux, d.jsonl)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("metrics: %v", err)
+		}
+	}()
+	d.logger.Printf("serving http://%s/healthz and /metrics", ln.Addr())
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
+
+// daily runs the Daily callback once per calendar day.
+func (d *Daemon) daily() {
+	if d.opts.Daily == nil {
+		return
+	}
+	loc := d.opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	if day := time.Now().In(loc).Format("2006-01-02"); day != d.lastDay {
+		d.lastDay = day
+		d.opts.Daily()
+	}
+}
+
+// changed reports whether beats.jsonl was modified since the last check.
+func (d *Daemon) changed() bool {
+	info, err := d.jsonl.Stat()
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(d.lastMod) && info.Size() == d.lastSize {
+		return false
+	}
+	d.lastMod = info.ModTime()
+	d.lastSize = info.Size()
+	return true
+}
+
+func (d *Daemon) index(ctx context.Context, sqlite *store.SQLiteStore) {
+	start := time.Now()
+	upserted, deleted, err := sqlite.SyncIncremental()
+	if err != nil {
+		d.logger.Printf("index sync failed: %v", err)
+		return
+	}
+	if upserted > 0 || deleted > 0 {
+		d.logger.Printf("index: %d upserted, %d deleted (%s)", upserted, deleted, time.Since(start).Round(time.Millisecond))
+	}
+
+	if !d.opts.Embed {
+		return
+	}
+	beats, err := d.jsonl.ReadAll()
+	if err != nil {
+		d.logger.Printf("failed to read beats: %v", err)
+		return
+	}
+	embStore, err := embeddings.NewStore(d.jsonl.Dir())
+	if err != nil {
+		d.logger.Printf("failed to open embedding store: %v", err)
+		return
+	}
+	if err := embStore.SetModel(d.opts.Model); err != nil {
+		d.logger.Printf("%v", err)
+		return
+	}
+	ollama := embeddings.NewOllamaClient()
+	ollama.SetModel(embStore.Model())
+	if !ollama.IsAvailable() {
+		d.logger.Printf("ollama not available, skipping embeddings until the next change")
+		return
+	}
+	result, err := embeddings.ComputeMissingWithOptions(ctx, beats, embStore, ollama, embeddings.ComputeOptions{BatchSize: 32})
+	if err != nil {
+		d.logger.Printf("embedding failed: %v", err)
+		return
+	}
+	if result.Computed > 0 || result.Errors > 0 {
+		d.logger.Printf("embeddings: %d computed, %d errors", result.Computed, result.Errors)
+	}
+}
+
+// Serving reports whether a live daemon accepts writes for beatsDir.
+func Serving(beatsDir string) bool {
+	if _, ok := RunningPID(beatsDir); !ok {
+		return false
+	}
+	return store.IsSocket(filepath.Join(beatsDir, store.WriteSocket))
+}
+
+// RunningPID returns the PID of a live daemon for beatsDir, if any.
+func RunningPID(beatsDir string) (int, bool) {
+	data, err := os.ReadFile(filepath.Join(beatsDir, PIDFile))
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	if !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
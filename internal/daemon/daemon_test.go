@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestIndexEditedBeat(t *testing.T) {
+	jsonl, err := store.NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().UTC()
+	if err := jsonl.Append(&beat.Beat{ID: "beat-20260101-001", Content: "alpha content", CreatedAt: at, UpdatedAt: at}); err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := store.NewSQLiteStore(jsonl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	d := New(jsonl, Options{}, log.New(io.Discard, "", 0))
+	poll := func() {
+		t.Helper()
+		if !d.changed() {
+			t.Fatal("changed() = false after a write")
+		}
+		d.index(context.Background(), sqlite)
+	}
+	poll()
+
+	if _, err := jsonl.Update("beat-20260101-001", func(b *beat.Beat) error {
+		b.Content = "bravo content"
+		b.UpdatedAt = at.Add(time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	edited := time.Now()
+	poll()
+	if d.changed() {
+		t.Error("changed() = true with nothing written since the last poll")
+	}
+
+	if last, err := sqlite.LastSync(); err != nil || last.Before(edited) {
+		t.Fatalf("LastSync = %v, %v; want the poll to have synced after the edit", last, err)
+	}
+	for query, want := range map[string]int{"alpha": 0, "bravo": 1} {
+		results, err := sqlite.Search(query, 10)
+		if err != nil || len(results) != want {
+			t.Errorf("Search(%q) = %+v, %v; want %d result(s)", query, results, err, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package embeddings
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCacheEntries bounds the text embedding cache. Beat vectors are read
+// from the embedding store, so the cache only holds search queries and its
+// size does not grow with the store.
+const DefaultCacheEntries = 1000
+
+// cachePath is the shared query cache inside the .beats directory.
+var cachePath = filepath.Join(".semantic_cache", "query_cache.json")
+
+// legacyCachePath held beat texts as well, before semantic search read the
+// embedding store; it is removed on first use of the new cache.
+var legacyCachePath = filepath.Join(".semantic_cache", "embeddings_cache.json")
+
+// OpenDefaultCache opens the query embedding cache shared by all semantic
+// search paths of a store.
+func OpenDefaultCache(beatsDir string) *TextCache {
+	_ = os.Remove(filepath.Join(beatsDir, legacyCachePath))
+	return OpenTextCache(filepath.Join(beatsDir, cachePath), DefaultCacheEntries)
+}
+
+// CacheKey identifies an embedding by model and the full text it was computed from.
+func CacheKey(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+type cacheEntry struct {
+	Vector   []float64 `json:"vector"`
+	LastUsed int64     `json:"last_used"` // Unix seconds, for LRU eviction
+}
+
+// TextCache is a size-bounded, persistent cache of text embeddings keyed by
+// a content hash. It is safe for concurrent use.
+type TextCache struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	entries    map[string]cacheEntry
+	dirty      bool
+}
+
+// OpenTextCache loads the cache at path (a missing or unreadable file is an
+// empty cache). maxEntries <= 0 uses DefaultCacheEntries.
+func OpenTextCache(path string, maxEntries int) *TextCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	c := &TextCache{path: path, maxEntries: maxEntries, entries: make(map[string]cacheEntry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var stored struct {
+		Entries map[string]cacheEntry `json:"entries"`
+	}
+	if json.Unmarshal(data, &stored) == nil && stored.Entries != nil {
+		c.entries = stored.Entries
+	}
+	return c
+}
+
+// Get returns the cached embedding for text under model.
+func (c *TextCache) Get(model, text string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := CacheKey(model, text)
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e.LastUsed = time.Now().Unix()
+	c.entries[key] = e
+	return e.Vector, true
+}
+
+// Put stores an embedding, evicting least recently used entries over the limit.
+func (c *TextCache) Put(model, text string, vector []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[CacheKey(model, text)] = cacheEntry{Vector: vector, LastUsed: time.Now().Unix()}
+	c.dirty = true
+	c.evict()
+}
+
+// Len returns the number of cached embeddings.
+func (c *TextCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *TextCache) evict() {
+	over := len(c.entries) - c.maxEntries
+	if over <= 0 {
+		return
+	}
+	keys := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].LastUsed < c.entries[keys[j]].LastUsed
+	})
+	for _, k := range keys[:over] {
+		delete(c.entries, k)
+	}
+}
+
+// Save writes the cache to disk if it changed.
+func (c *TextCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(struct {
+		Entries map[string]cacheEntry `json:"entries"`
+	}{c.entries})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
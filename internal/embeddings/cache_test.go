@@ -0,0 +1,74 @@
+package embeddings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextCache_NoPrefixCollision(t *testing.T) {
+	cache := OpenTextCache(filepath.Join(t.TempDir(), "cache.json"), 0)
+
+	prefix := strings.Repeat("same opening words ", 4)
+	cache.Put("m", prefix+"first ending", []float64{1})
+	cache.Put("m", prefix+"second ending", []float64{2})
+
+	got, ok := cache.Get("m", prefix+"first ending")
+	if !ok || got[0] != 1 {
+		t.Errorf("Get() = %v, %v; want [1], true", got, ok)
+	}
+	if _, ok := cache.Get("other-model", prefix+"first ending"); ok {
+		t.Error("Get() with a different model should miss")
+	}
+}
+
+func TestTextCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := OpenTextCache(filepath.Join(t.TempDir(), "cache.json"), 2)
+
+	cache.Put("m", "a", []float64{1})
+	cache.entries[CacheKey("m", "a")] = cacheEntry{Vector: []float64{1}, LastUsed: 1}
+	cache.Put("m", "b", []float64{2})
+	cache.Put("m", "c", []float64{3})
+
+	if cache.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get("m", "a"); ok {
+		t.Error("oldest entry should have been evicted")
+	}
+}
+
+func TestTextCache_SaveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "cache.json")
+	cache := OpenTextCache(path, 0)
+	cache.Put("m", "text", []float64{0.5, 0.25})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded := OpenTextCache(path, 0)
+	got, ok := reloaded.Get("m", "text")
+	if !ok || len(got) != 2 || got[1] != 0.25 {
+		t.Errorf("reloaded Get() = %v, %v", got, ok)
+	}
+}
+
+func TestOpenDefaultCache_RemovesLegacyCache(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, legacyCachePath)
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte(`{"entries": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := OpenDefaultCache(dir)
+	if cache.maxEntries != DefaultCacheEntries {
+		t.Errorf("maxEntries = %d, want %d", cache.maxEntries, DefaultCacheEntries)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy cache still present (stat error %v)", err)
+	}
+}
@@ -0,0 +1,115 @@
+package embeddings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// ChunkWords is the chunk size in words, roughly 512 tokens.
+const ChunkWords = 380
+
+// chunkSep separates a beat ID from its chunk number in the index.
+const chunkSep = "#"
+
+// chunkKey is the index key of chunk i of a beat. Chunk 0 is stored under
+// the bare beat ID so single-vector beats and older stores are unaffected.
+func chunkKey(beatID string, i int) string {
+	if i == 0 {
+		return beatID
+	}
+	return fmt.Sprintf("%s%s%d", beatID, chunkSep, i)
+}
+
+// baseID strips a chunk suffix from an index key.
+func baseID(key string) string {
+	if i := strings.Index(key, chunkSep); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// ChunkText splits text into chunks of at most maxWords words, breaking on
+// paragraph boundaries where possible. Short text is returned as one chunk.
+func ChunkText(text string, maxWords int) []string {
+	if maxWords <= 0 {
+		maxWords = ChunkWords
+	}
+	if len(strings.Fields(text)) <= maxWords {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	for _, para := range strings.Split(text, "\n\n") {
+		words := strings.Fields(para)
+		if len(current)+len(words) > maxWords {
+			flush()
+		}
+		// Paragraphs longer than a chunk are split on word boundaries
+		for len(words) > maxWords {
+			chunks = append(chunks, strings.Join(words[:maxWords], " "))
+			words = words[maxWords:]
+		}
+		current = append(current, words...)
+	}
+	flush()
+	return chunks
+}
+
+// BeatChunks returns the texts embedded for a beat, one per chunk. The
+// impetus label prefixes every chunk so each keeps its context.
+func BeatChunks(b beat.Beat) []string {
+	chunks := ChunkText(b.Content, ChunkWords)
+	if b.Impetus.Label == "" {
+		return chunks
+	}
+	for i, c := range chunks {
+		chunks[i] = b.Impetus.Label + ": " + c
+	}
+	return chunks
+}
+
+// GetChunks returns every stored vector of a beat (at least one, or an error).
+func (s *Store) GetChunks(beatID string) ([][]float64, error) {
+	first, err := s.Get(beatID)
+	if err != nil {
+		return nil, err
+	}
+	vectors := [][]float64{first}
+	for i := 1; ; i++ {
+		key := chunkKey(beatID, i)
+		if !s.Has(key) {
+			break
+		}
+		v, err := s.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// maxSimilarity scores a query against a beat by its best-matching chunk.
+func (s *Store) maxSimilarity(query []float64, beatID string) (float64, bool) {
+	vectors, err := s.GetChunks(beatID)
+	if err != nil {
+		return 0, false
+	}
+	best := -1.0
+	for _, v := range vectors {
+		if sim := cosineSimilarity(query, v); sim > best {
+			best = sim
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,24 @@
+package embeddings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkText(t *testing.T) {
+	short := "a few words"
+	if got := ChunkText(short, 10); len(got) != 1 || got[0] != short {
+		t.Errorf("ChunkText(short) = %q, want single chunk", got)
+	}
+
+	long := strings.Repeat("word ", 25) + "\n\n" + strings.Repeat("other ", 5)
+	got := ChunkText(long, 10)
+	if len(got) != 3 {
+		t.Fatalf("ChunkText(long) returned %d chunks, want 3: %q", len(got), got)
+	}
+	for i, c := range got {
+		if n := len(strings.Fields(c)); n > 10 {
+			t.Errorf("chunk %d has %d words, want <= 10", i, n)
+		}
+	}
+}
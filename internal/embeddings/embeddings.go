@@ -1,50 +1,132 @@
 package embeddings
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/llm"
+	"github.com/bierlingm/beats/internal/profile"
 )
 
 const (
 	EmbeddingDimensions = 768 // nomic-embed-text
-	embeddingsFile      = "embeddings.bin"
-	indexFile           = "embeddings.idx"
-	DefaultOllamaURL    = "http://localhost:11434"
-	EmbeddingModel      = "nomic-embed-text"
+	storeName           = "embeddings"
+	// EmbeddingModel produced the vectors of stores written before the
+	// model was recorded. New stores use embed_model from the config.
+	EmbeddingModel = "nomic-embed-text"
 )
 
+// Meta records which model produced the vectors in a store.
+type Meta struct {
+	Model      string    `json:"model"`
+	Dimensions int       `json:"dimensions"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 // Store manages embedding storage
 type Store struct {
 	dir   string
+	name  string
 	index map[string]int64
+	meta  Meta
 }
 
 // NewStore creates or loads an embedding store
 func NewStore(beatsDir string) (*Store, error) {
+	return openStore(beatsDir, storeName)
+}
+
+func openStore(dir, name string) (*Store, error) {
 	s := &Store{
-		dir:   beatsDir,
+		dir:   dir,
+		name:  name,
 		index: make(map[string]int64),
 	}
 	if err := s.loadIndex(); err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
+	if err := s.loadMeta(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if s.meta.Model == "" && len(s.index) > 0 {
+		// Stores written before metadata existed used the default model
+		s.meta = Meta{Model: EmbeddingModel, Dimensions: EmbeddingDimensions}
+	}
 	return s, nil
 }
 
-func (s *Store) binPath() string { return filepath.Join(s.dir, embeddingsFile) }
-func (s *Store) idxPath() string { return filepath.Join(s.dir, indexFile) }
+func (s *Store) binPath() string  { return filepath.Join(s.dir, s.name+".bin") }
+func (s *Store) idxPath() string  { return filepath.Join(s.dir, s.name+".idx") }
+func (s *Store) metaPath() string { return filepath.Join(s.dir, s.name+".meta.json") }
+
+// Model returns the model that produced this store's vectors ("" if empty).
+func (s *Store) Model() string { return s.meta.Model }
+
+// Dimensions returns the vector size of this store (0 if empty).
+func (s *Store) Dimensions() int { return s.meta.Dimensions }
+
+// Meta returns the store's model metadata.
+func (s *Store) Meta() Meta { return s.meta }
+
+func (s *Store) loadMeta() error {
+	data, err := os.ReadFile(s.metaPath())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.meta)
+}
+
+func (s *Store) saveMeta() error {
+	data, err := json.MarshalIndent(s.meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.metaPath(), data, 0644)
+}
+
+// SetModel records the model for an empty store. A non-empty store keeps its
+// model; switching models goes through Migrate.
+func (s *Store) SetModel(model string) error {
+	if model == "" || model == s.meta.Model {
+		return nil
+	}
+	if len(s.index) > 0 {
+		return fmt.Errorf("embedding store uses %s; run 'beats embed --migrate %s' to switch models", s.meta.Model, model)
+	}
+	s.meta = Meta{Model: model, CreatedAt: time.Now().UTC()}
+	return s.saveMeta()
+}
+
+// checkDimensions validates a vector size, fixing the store's size on first write.
+func (s *Store) checkDimensions(n int) error {
+	if s.meta.Dimensions == 0 {
+		if n == 0 {
+			return fmt.Errorf("empty embedding")
+		}
+		s.meta.Dimensions = n
+		if s.meta.Model == "" {
+			s.meta.Model = config.Get().EmbedModel
+		}
+		if s.meta.CreatedAt.IsZero() {
+			s.meta.CreatedAt = time.Now().UTC()
+		}
+		return s.saveMeta()
+	}
+	if n != s.meta.Dimensions {
+		return fmt.Errorf("expected %d dimensions, got %d", s.meta.Dimensions, n)
+	}
+	return nil
+}
 
 func (s *Store) loadIndex() error {
 	data, err := os.ReadFile(s.idxPath())
@@ -92,8 +174,8 @@ func (s *Store) Has(beatID string) bool {
 }
 
 func (s *Store) Store(beatID string, embedding []float64) error {
-	if len(embedding) != EmbeddingDimensions {
-		return fmt.Errorf("expected %d dimensions, got %d", EmbeddingDimensions, len(embedding))
+	if err := s.checkDimensions(len(embedding)); err != nil {
+		return err
 	}
 	f, err := os.OpenFile(s.binPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -107,7 +189,7 @@ func (s *Store) Store(beatID string, embedding []float64) error {
 	}
 	offset := info.Size()
 
-	buf := make([]byte, EmbeddingDimensions*8)
+	buf := make([]byte, len(embedding)*8)
 	for i, v := range embedding {
 		binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(v))
 	}
@@ -118,6 +200,43 @@ func (s *Store) Store(beatID string, embedding []float64) error {
 	return s.saveIndex()
 }
 
+// StoreBatch appends several embeddings and saves the index once, so a batch
+// is either fully recorded or not at all.
+func (s *Store) StoreBatch(beatIDs []string, embeddings [][]float64) error {
+	if len(beatIDs) != len(embeddings) {
+		return fmt.Errorf("got %d embeddings for %d beats", len(embeddings), len(beatIDs))
+	}
+	for _, emb := range embeddings {
+		if err := s.checkDimensions(len(emb)); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(s.binPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size()
+
+	buf := make([]byte, s.meta.Dimensions*8)
+	for i, emb := range embeddings {
+		for j, v := range emb {
+			binary.LittleEndian.PutUint64(buf[j*8:], math.Float64bits(v))
+		}
+		if _, err := f.Write(buf); err != nil {
+			return err
+		}
+		s.index[beatIDs[i]] = offset
+		offset += int64(len(buf))
+	}
+	return s.saveIndex()
+}
+
 func (s *Store) Get(beatID string) ([]float64, error) {
 	offset, ok := s.index[beatID]
 	if !ok {
@@ -131,11 +250,11 @@ func (s *Store) Get(beatID string) ([]float64, error) {
 	if _, err := f.Seek(offset, io.SeekStart); err != nil {
 		return nil, err
 	}
-	buf := make([]byte, EmbeddingDimensions*8)
+	buf := make([]byte, s.meta.Dimensions*8)
 	if _, err := io.ReadFull(f, buf); err != nil {
 		return nil, err
 	}
-	embedding := make([]float64, EmbeddingDimensions)
+	embedding := make([]float64, s.meta.Dimensions)
 	for i := range embedding {
 		bits := binary.LittleEndian.Uint64(buf[i*8:])
 		embedding[i] = math.Float64frombits(bits)
@@ -143,58 +262,156 @@ func (s *Store) Get(beatID string) ([]float64, error) {
 	return embedding, nil
 }
 
-func (s *Store) Count() int { return len(s.index) }
+// CompactResult reports what Compact removed.
+type CompactResult struct {
+	Kept        int
+	Removed     int
+	BytesBefore int64
+	BytesAfter  int64
+}
+
+// Compact rewrites the store keeping only vectors for the given beat IDs.
+// Orphaned vectors (deleted beats) and superseded ones (re-embedded beats,
+// whose older copies are never referenced by the index) are dropped.
+func (s *Store) Compact(keep map[string]bool) (*CompactResult, error) {
+	result := &CompactResult{}
+	if info, err := os.Stat(s.binPath()); err == nil {
+		result.BytesBefore = info.Size()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(s.index))
+	for id := range s.index {
+		if keep[baseID(id)] {
+			ids = append(ids, id)
+		} else if baseID(id) == id {
+			result.Removed++
+		}
+	}
+	// Preserve on-disk order
+	sort.Slice(ids, func(i, j int) bool { return s.index[ids[i]] < s.index[ids[j]] })
+
+	vectors := make([][]float64, len(ids))
+	for i, id := range ids {
+		emb, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		vectors[i] = emb
+	}
+
+	compacted := &Store{dir: s.dir, name: s.name + ".compact", index: make(map[string]int64), meta: s.meta}
+	removeStore(s.dir, compacted.name)
+	var err error
+	if len(ids) > 0 {
+		err = compacted.StoreBatch(ids, vectors)
+	} else if err = os.WriteFile(compacted.binPath(), nil, 0644); err == nil {
+		err = compacted.saveIndex()
+	}
+	if err == nil {
+		err = compacted.saveMeta()
+	}
+	if err != nil {
+		removeStore(s.dir, compacted.name)
+		return nil, err
+	}
+
+	if err := renameStore(s.dir, compacted.name, s.name); err != nil {
+		return nil, err
+	}
+	s.index = compacted.index
+	result.Kept = s.Count()
+	if info, err := os.Stat(s.binPath()); err == nil {
+		result.BytesAfter = info.Size()
+	}
+	return result, nil
+}
+
+// Count returns the number of beats with embeddings (chunks count once).
+func (s *Store) Count() int {
+	n := 0
+	for key := range s.index {
+		if baseID(key) == key {
+			n++
+		}
+	}
+	return n
+}
+
 func (s *Store) Coverage(total int) float64 {
 	if total == 0 {
 		return 100.0
 	}
-	return float64(len(s.index)) / float64(total) * 100.0
+	return float64(s.Count()) / float64(total) * 100.0
 }
 
 // OllamaClient for embeddings
 type OllamaClient struct {
-	baseURL string
-	client  *http.Client
+	llm   *llm.Client
+	model string
+	cache *TextCache
 }
 
 func NewOllamaClient() *OllamaClient {
+	cfg := config.Get()
 	return &OllamaClient{
-		baseURL: DefaultOllamaURL,
-		client:  &http.Client{Timeout: 30 * time.Second},
+		llm:   llm.New(cfg.OllamaURL).WithTimeout(30 * time.Second),
+		model: cfg.EmbedModel,
+	}
+}
+
+// SetModel overrides the embedding model (default: embed_model in the config).
+func (c *OllamaClient) SetModel(model string) {
+	if model != "" {
+		c.model = model
 	}
 }
 
+// SetCache makes GetEmbedding consult and fill a text cache.
+func (c *OllamaClient) SetCache(cache *TextCache) { c.cache = cache }
+
+// Model returns the embedding model in use.
+func (c *OllamaClient) Model() string { return c.model }
+
 func (c *OllamaClient) IsAvailable() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	req, _ := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", nil)
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return false
-	}
-	defer func() { _ = resp.Body.Close() }()
-	return resp.StatusCode == http.StatusOK
+	return c.llm.Available(ctx)
 }
 
 func (c *OllamaClient) GetEmbedding(ctx context.Context, text string) ([]float64, error) {
-	reqBody, _ := json.Marshal(map[string]string{"model": EmbeddingModel, "prompt": text})
-	req, _ := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/embeddings", bytes.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.client.Do(req)
+	if c.cache != nil {
+		if emb, ok := c.cache.Get(c.model, text); ok {
+			return emb, nil
+		}
+	}
+	emb, err := c.llm.Embed(ctx, c.model, text)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama returned %d", resp.StatusCode)
+	if c.cache != nil {
+		c.cache.Put(c.model, text, emb)
 	}
-	var result struct {
-		Embedding []float64 `json:"embedding"`
+	return emb, nil
+}
+
+// GetEmbeddings embeds several texts in one request using Ollama's /api/embed.
+// Older Ollama versions without that endpoint fall back to one request per text.
+func (c *OllamaClient) GetEmbeddings(ctx context.Context, texts []string) ([][]float64, error) {
+	result, err := c.llm.EmbedBatch(ctx, c.model, texts)
+	if !llm.IsNotFound(err) {
+		return result, err
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	result = make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		emb, err := c.GetEmbedding(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, emb)
 	}
-	return result.Embedding, nil
+	return result, nil
 }
 
 // ComputeResult for batch computation
@@ -204,30 +421,110 @@ type ComputeResult struct {
 	Errors   int
 }
 
+// ComputeOptions tunes batch embedding computation.
+type ComputeOptions struct {
+	BatchSize int                   // Beats per Ollama request (default 1)
+	Interval  time.Duration         // Minimum delay between requests (rate limit)
+	Progress  func(done, total int) // Called after each batch with chunks processed so far
+}
+
+// BeatText is the text embedded for a beat: impetus label plus content.
+func BeatText(b beat.Beat) string {
+	if b.Impetus.Label != "" {
+		return b.Impetus.Label + ": " + b.Content
+	}
+	return b.Content
+}
+
 func ComputeMissing(ctx context.Context, beats []beat.Beat, store *Store, ollama *OllamaClient) (*ComputeResult, error) {
+	return ComputeMissingWithOptions(ctx, beats, store, ollama, ComputeOptions{})
+}
+
+// ComputeMissingWithOptions embeds beats that have no stored embedding yet.
+// Long beats are split into chunks (see ChunkText), one vector each. Each
+// batch is persisted as soon as it is computed, so an interrupted run
+// resumes where it stopped. On context cancellation the partial result is
+// returned together with the context error.
+func ComputeMissingWithOptions(ctx context.Context, beats []beat.Beat, store *Store, ollama *OllamaClient, opts ComputeOptions) (*ComputeResult, error) {
 	result := &ComputeResult{}
 	if !ollama.IsAvailable() {
 		return nil, fmt.Errorf("ollama not available")
 	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	// Flatten beats into chunk texts. A beat's extra chunks are queued before
+	// its base key, so Has(beatID) only turns true once all chunks are stored
+	// and an interrupted beat is redone in full on resume.
+	type item struct{ key, text string }
+	var items []item
 	for _, b := range beats {
 		if store.Has(b.ID) {
 			result.Skipped++
 			continue
 		}
-		text := b.Content
-		if b.Impetus.Label != "" {
-			text = b.Impetus.Label + ": " + text
+		chunks := BeatChunks(b)
+		for i := len(chunks) - 1; i >= 0; i-- {
+			items = append(items, item{key: chunkKey(b.ID, i), text: chunks[i]})
 		}
-		embedding, err := ollama.GetEmbedding(ctx, text)
-		if err != nil {
-			result.Errors++
-			continue
+	}
+
+	total := len(items)
+	failed := make(map[string]bool)
+	var lastRequest time.Time
+	for start := 0; start < total; start += batchSize {
+		end := start + batchSize
+		if end > total {
+			end = total
 		}
-		if err := store.Store(b.ID, embedding); err != nil {
-			result.Errors++
+		var keys, texts []string
+		for _, it := range items[start:end] {
+			if failed[baseID(it.key)] {
+				continue // Never complete a beat whose other chunks failed
+			}
+			keys = append(keys, it.key)
+			texts = append(texts, it.text)
+		}
+		if len(keys) == 0 {
 			continue
 		}
-		result.Computed++
+
+		if opts.Interval > 0 && !lastRequest.IsZero() {
+			if wait := opts.Interval - time.Since(lastRequest); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return result, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		lastRequest = time.Now()
+		embs, err := ollama.GetEmbeddings(ctx, texts)
+		if err == nil {
+			err = store.StoreBatch(keys, embs)
+		}
+		if err != nil && ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		for _, k := range keys {
+			switch {
+			case err != nil && !failed[baseID(k)]:
+				failed[baseID(k)] = true
+				result.Errors++
+			case err == nil && baseID(k) == k:
+				result.Computed++
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(end, total)
+		}
 	}
 	return result, nil
 }
@@ -241,17 +538,17 @@ type SearchResult struct {
 }
 
 func SemanticSearch(ctx context.Context, query string, beats []beat.Beat, store *Store, ollama *OllamaClient, limit int) ([]SearchResult, error) {
+	defer profile.Start(profile.SemanticSearch)()
 	queryEmb, err := ollama.GetEmbedding(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	var results []SearchResult
 	for _, b := range beats {
-		beatEmb, err := store.Get(b.ID)
-		if err != nil {
+		sim, ok := store.maxSimilarity(queryEmb, b.ID)
+		if !ok {
 			continue
 		}
-		sim := cosineSimilarity(queryEmb, beatEmb)
 		results = append(results, SearchResult{
 			ID:      b.ID,
 			Score:   sim,
@@ -268,6 +565,41 @@ func SemanticSearch(ctx context.Context, query string, beats []beat.Beat, store
 	return results, nil
 }
 
+// Duplicate is an existing beat that is semantically close to new text.
+type Duplicate struct {
+	ID      string  `json:"id"`
+	Score   float64 `json:"score"`
+	Content string  `json:"content"`
+}
+
+// FindDuplicates returns stored beats whose similarity to emb is at or above
+// threshold, best first.
+func FindDuplicates(emb []float64, beats []beat.Beat, store *Store, threshold float64, limit int) []Duplicate {
+	var dups []Duplicate
+	for _, b := range beats {
+		sim, ok := store.maxSimilarity(emb, b.ID)
+		if !ok {
+			continue
+		}
+		if sim >= threshold {
+			dups = append(dups, Duplicate{ID: b.ID, Score: sim, Content: b.Content})
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		return dups[i].Score > dups[j].Score
+	})
+	if limit > 0 && len(dups) > limit {
+		dups = dups[:limit]
+	}
+	return dups
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors
+// (0 when their sizes differ).
+func CosineSimilarity(a, b []float64) float64 {
+	return cosineSimilarity(a, b)
+}
+
 func cosineSimilarity(a, b []float64) float64 {
 	if len(a) != len(b) {
 		return 0
@@ -0,0 +1,223 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// fakeOllama serves embeddings computed by embed and returns a client for
+// it using model. It records every text embedded.
+func fakeOllama(t *testing.T, model string, embed func(text string) []float64) (*OllamaClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var embedded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			fmt.Fprintf(w, `{"models": [{"name": %q}]}`, model)
+		case "/api/embed":
+			var req struct{ Input []string }
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			var out struct {
+				Embeddings [][]float64 `json:"embeddings"`
+			}
+			mu.Lock()
+			embedded = append(embedded, req.Input...)
+			mu.Unlock()
+			for _, text := range req.Input {
+				out.Embeddings = append(out.Embeddings, embed(text))
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("BEATS_OLLAMA_URL", server.URL)
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	client := NewOllamaClient()
+	client.SetModel(model)
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), embedded...)
+	}
+}
+
+// lengthVector embeds text as its length and word count.
+func lengthVector(text string) []float64 {
+	return []float64{float64(len(text)), float64(len(strings.Fields(text))), 1}
+}
+
+func TestComputeMissing_ResumesAfterPartialBatch(t *testing.T) {
+	ollama, embedded := fakeOllama(t, "test-embed", lengthVector)
+	dir := t.TempDir()
+	long := beat.Beat{ID: "beat-20260101-001", Content: strings.Repeat("word ", ChunkWords) + "\n\ntail"}
+	short := beat.Beat{ID: "beat-20260101-002", Content: "short"}
+	beats := []beat.Beat{long, short}
+
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stop after the first batch: one of the long beat's two chunks
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := ComputeMissingWithOptions(ctx, beats, s, ollama, ComputeOptions{
+		BatchSize: 1,
+		Progress:  func(done, total int) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if result.Computed != 0 || len(embedded()) != 1 {
+		t.Fatalf("interrupted run computed %d beat(s) from %d text(s), want 0 from 1", result.Computed, len(embedded()))
+	}
+
+	s, err = NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Has(long.ID) || s.Count() != 0 {
+		t.Fatalf("a beat with only some chunks stored counts as embedded")
+	}
+	result, err = ComputeMissingWithOptions(context.Background(), beats, s, ollama, ComputeOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Computed != 2 || result.Skipped != 0 || result.Errors != 0 {
+		t.Errorf("resumed run = %+v, want both beats computed", result)
+	}
+	chunks, err := s.GetChunks(long.ID)
+	if err != nil || len(chunks) != 2 {
+		t.Fatalf("long beat has %d chunk vector(s) (%v), want 2", len(chunks), err)
+	}
+	for i, text := range BeatChunks(long) {
+		if !reflect.DeepEqual(chunks[i], lengthVector(text)) {
+			t.Errorf("chunk %d = %v, want %v", i, chunks[i], lengthVector(text))
+		}
+	}
+
+	// A third run has nothing left to do
+	before := len(embedded())
+	result, err = ComputeMissingWithOptions(context.Background(), beats, s, ollama, ComputeOptions{BatchSize: 2})
+	if err != nil || result.Skipped != 2 || len(embedded()) != before {
+		t.Errorf("third run = %+v, %v after %d request(s); want both skipped without requests", result, err, len(embedded())-before)
+	}
+}
+
+func TestMigrate_FailedVerificationKeepsOldStore(t *testing.T) {
+	dir := t.TempDir()
+	beats := []beat.Beat{
+		{ID: "beat-20260101-001", Content: "first"},
+		{ID: "beat-20260101-002", Content: "second"},
+	}
+	old, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := old.SetModel("old-embed"); err != nil {
+		t.Fatal(err)
+	}
+	oldVectors := [][]float64{{1, 0}, {0, 1}}
+	if err := old.StoreBatch([]string{beats[0].ID, beats[1].ID}, oldVectors); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new model returns an all-zero vector for the second beat
+	ollama, _ := fakeOllama(t, "new-embed", func(text string) []float64 {
+		if text == "second" {
+			return []float64{0, 0, 0}
+		}
+		return []float64{1, 1, 1}
+	})
+	result, err := Migrate(context.Background(), dir, beats, ollama, MigrateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "verification failed") {
+		t.Fatalf("Migrate() error = %v, want a verification failure", err)
+	}
+	if result.Verified != 1 {
+		t.Errorf("verified %d beat(s), want 1", result.Verified)
+	}
+
+	current, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Model() != "old-embed" || current.Dimensions() != 2 {
+		t.Errorf("current store is %s with %d dimensions, want the old-embed one", current.Model(), current.Dimensions())
+	}
+	for i, b := range beats {
+		if got, err := current.Get(b.ID); err != nil || !reflect.DeepEqual(got, oldVectors[i]) {
+			t.Errorf("%s = %v, %v; want the old vector %v", b.ID, got, err, oldVectors[i])
+		}
+	}
+	staging, err := openStore(dir, stagingName)
+	if err != nil || staging.Model() != "new-embed" || !staging.Has(beats[0].ID) {
+		t.Errorf("staging index = %+v, %v; want it kept for a rerun", staging, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, backupName+".idx")); !os.IsNotExist(err) {
+		t.Errorf("a backup index exists (stat error %v), want no switch attempted", err)
+	}
+}
+
+func TestCompact_PreservesVectors(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	dir := t.TempDir()
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const a, b, deleted = "beat-20260101-001", "beat-20260101-002", "beat-20260101-003"
+	if err := s.StoreBatch(
+		[]string{a, chunkKey(b, 1), b, deleted},
+		[][]float64{{1, 0}, {0.5, 0.5}, {0, 1}, {1, 1}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	// Re-embedded: the first vector of a is superseded
+	if err := s.Store(a, []float64{0.25, 0.75}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][][]float64{
+		a: {{0.25, 0.75}},
+		b: {{0, 1}, {0.5, 0.5}},
+	}
+
+	result, err := s.Compact(map[string]bool{a: true, b: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Kept != 2 || result.Removed != 1 || result.BytesAfter != 3*2*8 || result.BytesBefore != 5*2*8 {
+		t.Errorf("Compact() = %+v, want 2 kept, 1 removed, 80 bytes down to 48", result)
+	}
+
+	reopened, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []*Store{s, reopened} {
+		for id, vectors := range want {
+			if got, err := store.GetChunks(id); err != nil || !reflect.DeepEqual(got, vectors) {
+				t.Errorf("%s = %v, %v; want %v", id, got, err, vectors)
+			}
+		}
+		if store.Has(deleted) {
+			t.Errorf("vector of deleted beat %s kept", deleted)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, storeName+".compact.bin")); !os.IsNotExist(err) {
+		t.Errorf("compaction left its working copy behind (stat error %v)", err)
+	}
+}
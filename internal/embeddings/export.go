@@ -0,0 +1,168 @@
+package embeddings
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// ExportRow pairs one stored vector with the beat it belongs to.
+type ExportRow struct {
+	ID        string    `json:"id"`
+	Chunk     int       `json:"chunk"`
+	CreatedAt time.Time `json:"created_at"`
+	Impetus   string    `json:"impetus"`
+	Model     string    `json:"model"`
+	Vector    []float64 `json:"vector,omitempty"`
+}
+
+// ExportFormats lists the supported export formats by file extension.
+var ExportFormats = []string{"jsonl", "npy", "parquet"}
+
+// ExportFormat infers the export format from a file name.
+func ExportFormat(path string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	for _, f := range ExportFormats {
+		if ext == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format %q (use .jsonl, .npy or .parquet)", filepath.Ext(path))
+}
+
+// ExportRows collects every stored vector for the given beats, one row per
+// chunk, in beat order. Beats without embeddings are skipped.
+func ExportRows(beats []beat.Beat, store *Store) ([]ExportRow, error) {
+	var rows []ExportRow
+	for _, b := range beats {
+		if !store.Has(b.ID) {
+			continue
+		}
+		vectors, err := store.GetChunks(b.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vectors {
+			rows = append(rows, ExportRow{
+				ID:        b.ID,
+				Chunk:     i,
+				CreatedAt: b.CreatedAt,
+				Impetus:   b.Impetus.Label,
+				Model:     store.Model(),
+				Vector:    v,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// Export writes rows to path in the format implied by its extension.
+// NPY files hold only the vector matrix, so row metadata is written next to
+// them as <name>.rows.jsonl in the same order.
+func Export(path string, rows []ExportRow, meta Meta) error {
+	format, err := ExportFormat(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "jsonl":
+		return writeFile(path, func(w io.Writer) error { return writeJSONL(w, rows) })
+	case "npy":
+		if err := writeFile(path, func(w io.Writer) error { return writeNPY(w, rows, meta.Dimensions) }); err != nil {
+			return err
+		}
+		return writeFile(NPYSidecarPath(path), func(w io.Writer) error {
+			stripped := make([]ExportRow, len(rows))
+			for i, r := range rows {
+				r.Vector = nil
+				stripped[i] = r
+			}
+			return writeJSONL(w, stripped)
+		})
+	default:
+		return writeFile(path, func(w io.Writer) error { return writeParquet(w, rows, meta) })
+	}
+}
+
+// NPYSidecarPath returns the row metadata file written alongside an NPY export.
+func NPYSidecarPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".rows.jsonl"
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSONL(w io.Writer, rows []ExportRow) error {
+	enc := json.NewEncoder(w)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeNPY writes vectors as a little-endian float64 matrix in NumPy's
+// .npy v1.0 format, loadable with numpy.load.
+func writeNPY(w io.Writer, rows []ExportRow, dims int) error {
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d, %d), }", len(rows), dims)
+	// Pad so the data starts on a 64-byte boundary, ending the header with \n
+	const preamble = 10
+	pad := 64 - (preamble+len(header)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	header += strings.Repeat(" ", pad) + "\n"
+
+	if _, err := w.Write([]byte("\x93NUMPY\x01\x00")); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(header))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 8)
+	for _, r := range rows {
+		if len(r.Vector) != dims {
+			return errDimensions(r.ID, dims, len(r.Vector))
+		}
+		for _, v := range r.Vector {
+			binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func errDimensions(id string, want, got int) error {
+	return fmt.Errorf("%s: expected %d dimensions, got %d", id, want, got)
+}
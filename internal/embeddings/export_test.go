@@ -0,0 +1,86 @@
+package embeddings
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportFixture() []ExportRow {
+	at := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	return []ExportRow{
+		{ID: "beat-20260115-001", CreatedAt: at, Impetus: "Research", Vector: []float64{0.1, 0.2, 0.3}},
+		{ID: "beat-20260115-001", Chunk: 1, CreatedAt: at, Impetus: "Research", Vector: []float64{0.4, 0.5, 0.6}},
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	for path, want := range map[string]string{"out.parquet": "parquet", "OUT.NPY": "npy", "a/b.jsonl": "jsonl"} {
+		if got, err := ExportFormat(path); err != nil || got != want {
+			t.Errorf("ExportFormat(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	if _, err := ExportFormat("out.csv"); err == nil {
+		t.Error("ExportFormat(out.csv) succeeded, want error")
+	}
+}
+
+func TestWriteNPY(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeNPY(&buf, exportFixture(), 3); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("\x93NUMPY\x01\x00")) {
+		t.Fatalf("missing NPY magic: %q", data[:8])
+	}
+	headerLen := int(binary.LittleEndian.Uint16(data[8:10]))
+	if (10+headerLen)%64 != 0 {
+		t.Errorf("data offset %d is not 64-byte aligned", 10+headerLen)
+	}
+	if header := string(data[10 : 10+headerLen]); !strings.Contains(header, "'shape': (2, 3)") {
+		t.Errorf("header = %q, want shape (2, 3)", header)
+	}
+	if got := len(data) - 10 - headerLen; got != 2*3*8 {
+		t.Errorf("payload is %d bytes, want %d", got, 2*3*8)
+	}
+
+	if err := writeNPY(&buf, exportFixture(), 4); err == nil {
+		t.Error("writeNPY with wrong dimensions succeeded, want error")
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeParquet(&buf, exportFixture(), Meta{Model: "test-model", Dimensions: 3}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footerLen <= 0 || footerLen > len(data)-12 {
+		t.Fatalf("footer length %d out of range", footerLen)
+	}
+	footer := data[len(data)-8-footerLen : len(data)-8]
+	for _, want := range []string{"beat-20260115-001", "element", "test-model"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if !bytes.Contains(footer, []byte("vector")) {
+		t.Error("footer schema missing vector column")
+	}
+}
+
+func TestLevelRuns(t *testing.T) {
+	// Two runs at bit width 1: (3 x 0), (5 x 1)
+	got := levelRuns([][2]int{{3, 0}, {5, 1}})
+	want := []byte{4, 0, 0, 0, 3 << 1, 0, 5 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("levelRuns = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+const (
+	stagingName = storeName + ".next" // Parallel index built during a migration
+	backupName  = storeName + ".prev" // Previous index kept until the switch is verified
+)
+
+// MigrateOptions configures a model migration.
+type MigrateOptions struct {
+	Compute ComputeOptions
+	KeepOld bool // Keep embeddings.prev.* after a successful switch
+}
+
+// MigrateResult summarizes a model migration.
+type MigrateResult struct {
+	OldModel   string
+	NewModel   string
+	Dimensions int
+	Compute    *ComputeResult
+	Verified   int
+}
+
+// Migrate re-embeds every beat with the client's model into a parallel
+// index and switches over only after every vector has been verified. The
+// staging index survives interruption, so rerunning resumes the migration.
+func Migrate(ctx context.Context, beatsDir string, beats []beat.Beat, ollama *OllamaClient, opts MigrateOptions) (*MigrateResult, error) {
+	current, err := NewStore(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrateResult{OldModel: current.Model(), NewModel: ollama.Model()}
+
+	staging, err := openStore(beatsDir, stagingName)
+	if err != nil {
+		return nil, err
+	}
+	if staging.Model() != "" && staging.Model() != ollama.Model() {
+		// Leftover from a migration to a different model
+		removeStore(beatsDir, stagingName)
+		if staging, err = openStore(beatsDir, stagingName); err != nil {
+			return nil, err
+		}
+	}
+	if err := staging.SetModel(ollama.Model()); err != nil {
+		return nil, err
+	}
+
+	result.Compute, err = ComputeMissingWithOptions(ctx, beats, staging, ollama, opts.Compute)
+	if err != nil {
+		return result, err
+	}
+
+	verified, err := verifyStore(staging, beats)
+	result.Verified = verified
+	if err != nil {
+		return result, fmt.Errorf("verification failed, keeping %s index: %w", result.OldModel, err)
+	}
+	result.Dimensions = staging.Dimensions()
+
+	if err := swapStores(beatsDir); err != nil {
+		return result, err
+	}
+
+	// Re-open the switched index and make sure it is the one we built
+	switched, err := NewStore(beatsDir)
+	if err == nil {
+		_, err = verifyStore(switched, beats)
+	}
+	if err != nil || switched.Model() != ollama.Model() {
+		if rbErr := rollbackStores(beatsDir); rbErr != nil {
+			return result, fmt.Errorf("switched index failed verification and rollback failed: %w", rbErr)
+		}
+		return result, fmt.Errorf("switched index failed verification, restored %s index", result.OldModel)
+	}
+
+	if !opts.KeepOld {
+		removeStore(beatsDir, backupName)
+	}
+	return result, nil
+}
+
+// verifyStore checks every beat has a readable, non-zero vector.
+func verifyStore(s *Store, beats []beat.Beat) (int, error) {
+	verified := 0
+	var missing []string
+	for _, b := range beats {
+		emb, err := s.Get(b.ID)
+		if err != nil || len(emb) != s.Dimensions() || vectorNorm(emb) == 0 {
+			missing = append(missing, b.ID)
+			continue
+		}
+		verified++
+	}
+	if len(missing) > 0 {
+		return verified, fmt.Errorf("%d of %d beats have no valid embedding (first: %s)", len(missing), len(beats), missing[0])
+	}
+	return verified, nil
+}
+
+func vectorNorm(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+// swapStores moves the current index to the backup name and the staging
+// index into place, undoing the moves if any rename fails.
+func swapStores(beatsDir string) error {
+	removeStore(beatsDir, backupName)
+	if err := renameStore(beatsDir, storeName, backupName); err != nil {
+		_ = renameStore(beatsDir, backupName, storeName)
+		return fmt.Errorf("failed to back up current index: %w", err)
+	}
+	if err := renameStore(beatsDir, stagingName, storeName); err != nil {
+		_ = rollbackStores(beatsDir)
+		return fmt.Errorf("failed to switch index: %w", err)
+	}
+	return nil
+}
+
+// rollbackStores restores the backup index as the current one.
+func rollbackStores(beatsDir string) error {
+	return renameStore(beatsDir, backupName, storeName)
+}
+
+func renameStore(beatsDir, from, to string) error {
+	src := &Store{dir: beatsDir, name: from}
+	dst := &Store{dir: beatsDir, name: to}
+	paths := [][2]string{
+		{src.binPath(), dst.binPath()},
+		{src.idxPath(), dst.idxPath()},
+		{src.metaPath(), dst.metaPath()},
+	}
+	for _, p := range paths {
+		if err := os.Rename(p[0], p[1]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeStore(beatsDir, name string) {
+	s := &Store{dir: beatsDir, name: name}
+	os.Remove(s.binPath())
+	os.Remove(s.idxPath())
+	os.Remove(s.metaPath())
+}
@@ -0,0 +1,304 @@
+package embeddings
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Minimal Parquet writer for embedding exports: one row group, one
+// uncompressed PLAIN-encoded data page per column, footer in Thrift compact
+// protocol. Vectors are a standard three-level LIST<DOUBLE> column, so
+// pyarrow/pandas/polars read them as one array per row.
+
+// Parquet enum values used by the writer.
+const (
+	pqInt32     = 1
+	pqInt64     = 2
+	pqDouble    = 5
+	pqByteArray = 6
+
+	pqRequired = 0
+	pqRepeated = 2
+
+	pqUTF8            = 0
+	pqList            = 3
+	pqTimestampMillis = 9
+
+	pqPlain = 0
+	pqRLE   = 3
+)
+
+// Thrift compact protocol type IDs.
+const (
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// thriftWriter encodes structs in the Thrift compact protocol.
+type thriftWriter struct {
+	buf  bytes.Buffer
+	last []int16 // Last field ID per open struct
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	t.buf.Write(b[:n])
+}
+
+func (t *thriftWriter) zigzag(v int64) { t.varint(uint64((v << 1) ^ (v >> 63))) }
+
+func (t *thriftWriter) begin() { t.last = append(t.last, 0) }
+
+func (t *thriftWriter) end() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	top := len(t.last) - 1
+	if delta := id - t.last[top]; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.zigzag(int64(id))
+	}
+	t.last[top] = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) { t.field(id, tI32); t.zigzag(int64(v)) }
+func (t *thriftWriter) i64(id int16, v int64) { t.field(id, tI64); t.zigzag(v) }
+
+func (t *thriftWriter) str(id int16, s string) {
+	t.field(id, tBinary)
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thriftWriter) list(id int16, elem byte, n int) {
+	t.field(id, tList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xf0 | elem)
+		t.varint(uint64(n))
+	}
+}
+
+func (t *thriftWriter) structField(id int16) {
+	t.field(id, tStruct)
+	t.begin()
+}
+
+// pqColumn is one leaf column ready to be written.
+type pqColumn struct {
+	path     []string
+	typ      int32
+	values   []byte // PLAIN-encoded values
+	levels   []byte // Repetition + definition levels, empty for flat columns
+	count    int    // Number of leaf values
+	offset   int64  // File offset of the page header, set while writing
+	pageSize int64  // Header + page bytes, set while writing
+}
+
+// levelRuns encodes levels in the RLE/bit-packed hybrid with a 4-byte length
+// prefix; runs is a list of (count, value) pairs at bit width 1.
+func levelRuns(runs [][2]int) []byte {
+	var body bytes.Buffer
+	var b [binary.MaxVarintLen64]byte
+	for _, r := range runs {
+		n := binary.PutUvarint(b[:], uint64(r[0])<<1)
+		body.Write(b[:n])
+		body.WriteByte(byte(r[1]))
+	}
+	out := make([]byte, 4, 4+body.Len())
+	binary.LittleEndian.PutUint32(out, uint32(body.Len()))
+	return append(out, body.Bytes()...)
+}
+
+func buildColumns(rows []ExportRow, dims int) []*pqColumn {
+	id := &pqColumn{path: []string{"id"}, typ: pqByteArray, count: len(rows)}
+	chunk := &pqColumn{path: []string{"chunk"}, typ: pqInt32, count: len(rows)}
+	created := &pqColumn{path: []string{"created_at"}, typ: pqInt64, count: len(rows)}
+	impetus := &pqColumn{path: []string{"impetus"}, typ: pqByteArray, count: len(rows)}
+	vector := &pqColumn{path: []string{"vector", "list", "element"}, typ: pqDouble, count: len(rows) * dims}
+
+	var ids, chunks, times, labels, vecs bytes.Buffer
+	b := make([]byte, 8)
+	putBytes := func(w *bytes.Buffer, s string) {
+		binary.LittleEndian.PutUint32(b, uint32(len(s)))
+		w.Write(b[:4])
+		w.WriteString(s)
+	}
+	for _, r := range rows {
+		putBytes(&ids, r.ID)
+		putBytes(&labels, r.Impetus)
+		binary.LittleEndian.PutUint32(b, uint32(r.Chunk))
+		chunks.Write(b[:4])
+		binary.LittleEndian.PutUint64(b, uint64(r.CreatedAt.UnixMilli()))
+		times.Write(b)
+		for _, v := range r.Vector {
+			binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+			vecs.Write(b)
+		}
+	}
+	id.values, chunk.values, created.values = ids.Bytes(), chunks.Bytes(), times.Bytes()
+	impetus.values, vector.values = labels.Bytes(), vecs.Bytes()
+
+	// Each row is one non-empty list: repetition level 0 starts a row and
+	// 1 continues it; every element is defined at the maximum level (1).
+	var rep, def [][2]int
+	for range rows {
+		rep = append(rep, [2]int{1, 0})
+		if dims > 1 {
+			rep = append(rep, [2]int{dims - 1, 1})
+		}
+	}
+	if n := len(rows) * dims; n > 0 {
+		def = [][2]int{{n, 1}}
+	}
+	if dims == 0 && len(rows) > 0 {
+		// Empty lists still take one level entry per row, defined only up
+		// to the vector itself (0).
+		vector.count = len(rows)
+		rep = [][2]int{{len(rows), 0}}
+		def = [][2]int{{len(rows), 0}}
+	}
+	vector.levels = append(levelRuns(rep), levelRuns(def)...)
+
+	return []*pqColumn{id, chunk, created, impetus, vector}
+}
+
+func writeParquet(w io.Writer, rows []ExportRow, meta Meta) error {
+	for _, r := range rows {
+		if len(r.Vector) != meta.Dimensions {
+			return errDimensions(r.ID, meta.Dimensions, len(r.Vector))
+		}
+	}
+	columns := buildColumns(rows, meta.Dimensions)
+
+	var out bytes.Buffer
+	out.WriteString("PAR1")
+	for _, c := range columns {
+		page := append(append([]byte{}, c.levels...), c.values...)
+
+		var h thriftWriter
+		h.begin()
+		h.i32(1, 0) // DATA_PAGE
+		h.i32(2, int32(len(page)))
+		h.i32(3, int32(len(page)))
+		h.structField(5)
+		h.i32(1, int32(c.count))
+		h.i32(2, pqPlain)
+		h.i32(3, pqRLE)
+		h.i32(4, pqRLE)
+		h.end()
+		h.end()
+
+		c.offset = int64(out.Len())
+		c.pageSize = int64(h.buf.Len() + len(page))
+		out.Write(h.buf.Bytes())
+		out.Write(page)
+	}
+	dataEnd := int64(out.Len())
+
+	var f thriftWriter
+	f.begin()
+	f.i32(1, 1)
+	writeSchema(&f)
+	f.i64(3, int64(len(rows)))
+
+	f.list(4, tStruct, 1)
+	f.begin() // RowGroup
+	f.list(1, tStruct, len(columns))
+	for _, c := range columns {
+		f.begin() // ColumnChunk
+		f.i64(2, c.offset)
+		f.structField(3) // ColumnMetaData
+		f.i32(1, c.typ)
+		f.list(2, tI32, 2)
+		f.zigzag(pqPlain)
+		f.zigzag(pqRLE)
+		f.list(3, tBinary, len(c.path))
+		for _, p := range c.path {
+			f.varint(uint64(len(p)))
+			f.buf.WriteString(p)
+		}
+		f.i32(4, 0) // UNCOMPRESSED
+		f.i64(5, int64(c.count))
+		f.i64(6, c.pageSize)
+		f.i64(7, c.pageSize)
+		f.i64(9, c.offset)
+		f.end()
+		f.end()
+	}
+	f.i64(2, dataEnd-4)
+	f.i64(3, int64(len(rows)))
+	f.end()
+
+	f.list(5, tStruct, 2)
+	for _, kv := range [][2]string{{"model", meta.Model}, {"dimensions", strconv.Itoa(meta.Dimensions)}} {
+		f.begin()
+		f.str(1, kv[0])
+		f.str(2, kv[1])
+		f.end()
+	}
+	f.str(6, "beats embed --export")
+	f.end()
+
+	out.Write(f.buf.Bytes())
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(f.buf.Len()))
+	out.Write(n[:])
+	out.WriteString("PAR1")
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeSchema writes the flattened schema: a root, four flat columns and the
+// three-level vector list.
+func writeSchema(f *thriftWriter) {
+	type element struct {
+		name      string
+		typ       int32 // -1 for groups
+		rep       int32
+		children  int32
+		converted int32 // -1 for none
+	}
+	elements := []element{
+		{"schema", -1, -1, 5, -1},
+		{"id", pqByteArray, pqRequired, 0, pqUTF8},
+		{"chunk", pqInt32, pqRequired, 0, -1},
+		{"created_at", pqInt64, pqRequired, 0, pqTimestampMillis},
+		{"impetus", pqByteArray, pqRequired, 0, pqUTF8},
+		{"vector", -1, pqRequired, 1, pqList},
+		{"list", -1, pqRepeated, 1, -1},
+		{"element", pqDouble, pqRequired, 0, -1},
+	}
+
+	f.list(2, tStruct, len(elements))
+	for _, e := range elements {
+		f.begin()
+		if e.typ >= 0 {
+			f.i32(1, e.typ)
+		}
+		if e.rep >= 0 {
+			f.i32(3, e.rep)
+		}
+		f.str(4, e.name)
+		if e.children > 0 {
+			f.i32(5, e.children)
+		}
+		if e.converted >= 0 {
+			f.i32(6, e.converted)
+		}
+		f.end()
+	}
+}
@@ -0,0 +1,215 @@
+package embeddings
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// thriftReader decodes the Thrift compact protocol as writeParquet produces
+// it: structs become maps by field ID, integers int64 and binaries strings.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(t *testing.T, typ byte) interface{} {
+	switch typ {
+	case tI32, tI64:
+		return r.zigzag()
+	case tBinary:
+		n := int(r.varint())
+		r.pos += n
+		return string(r.data[r.pos-n : r.pos])
+	case tList:
+		h := r.byte()
+		n, elem := int(h>>4), h&0x0f
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(t, elem)
+		}
+		return list
+	case tStruct:
+		fields := map[int16]interface{}{}
+		var id int16
+		for {
+			h := r.byte()
+			if h == 0 {
+				return fields
+			}
+			if delta := int16(h >> 4); delta > 0 {
+				id += delta
+			} else {
+				id = int16(r.zigzag())
+			}
+			fields[id] = r.value(t, h&0x0f)
+		}
+	}
+	t.Fatalf("unexpected Thrift type %d at offset %d", typ, r.pos)
+	return nil
+}
+
+func (r *thriftReader) structAt(t *testing.T, pos int) map[int16]interface{} {
+	r.pos = pos
+	return r.value(t, tStruct).(map[int16]interface{})
+}
+
+// readLevels expands an RLE-encoded level block at the start of page and
+// returns the levels and the rest of the page.
+func readLevels(t *testing.T, page []byte) ([]int, []byte) {
+	n := int(binary.LittleEndian.Uint32(page))
+	r := thriftReader{data: page[4 : 4+n]}
+	var levels []int
+	for r.pos < len(r.data) {
+		header := r.varint()
+		if header&1 != 0 {
+			t.Fatal("unexpected bit-packed level run")
+		}
+		value := int(r.byte())
+		for i := uint64(0); i < header>>1; i++ {
+			levels = append(levels, value)
+		}
+	}
+	return levels, page[4+n:]
+}
+
+// readParquet reads back what writeParquet wrote, going only by the footer:
+// the row count, key-value metadata and, from each column's data page, the
+// rows.
+func readParquet(t *testing.T, data []byte) ([]ExportRow, map[string]string) {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &thriftReader{data: data}
+	footer := r.structAt(t, len(data)-8-footerLen)
+	if r.pos != len(data)-8 {
+		t.Fatalf("footer ends at %d, want %d", r.pos, len(data)-8)
+	}
+
+	meta := map[string]string{}
+	for _, kv := range footer[5].([]interface{}) {
+		kv := kv.(map[int16]interface{})
+		meta[kv[1].(string)] = kv[2].(string)
+	}
+	numRows := int(footer[3].(int64))
+	rows := make([]ExportRow, numRows)
+
+	groups := footer[4].([]interface{})
+	if len(groups) != 1 {
+		t.Fatalf("%d row groups, want 1", len(groups))
+	}
+	for _, c := range groups[0].(map[int16]interface{})[1].([]interface{}) {
+		cm := c.(map[int16]interface{})[3].(map[int16]interface{})
+		var path []string
+		for _, p := range cm[3].([]interface{}) {
+			path = append(path, p.(string))
+		}
+		header := r.structAt(t, int(cm[9].(int64)))
+		page := data[r.pos : r.pos+int(header[2].(int64))]
+		count := int(header[5].(map[int16]interface{})[1].(int64))
+		if int(cm[5].(int64)) != count {
+			t.Errorf("%v: column has %d values, page %d", path, cm[5], count)
+		}
+
+		str := func() string {
+			n := int(binary.LittleEndian.Uint32(page))
+			s := string(page[4 : 4+n])
+			page = page[4+n:]
+			return s
+		}
+		switch name := strings.Join(path, "."); name {
+		case "id", "impetus":
+			for i := 0; i < count; i++ {
+				if name == "id" {
+					rows[i].ID = str()
+				} else {
+					rows[i].Impetus = str()
+				}
+			}
+		case "chunk":
+			for i := 0; i < count; i++ {
+				rows[i].Chunk = int(int32(binary.LittleEndian.Uint32(page[4*i:])))
+			}
+		case "created_at":
+			for i := 0; i < count; i++ {
+				rows[i].CreatedAt = time.UnixMilli(int64(binary.LittleEndian.Uint64(page[8*i:]))).UTC()
+			}
+		case "vector.list.element":
+			rep, rest := readLevels(t, page)
+			def, values := readLevels(t, rest)
+			if len(rep) != count || len(def) != count {
+				t.Fatalf("vector has %d values but %d repetition and %d definition levels", count, len(rep), len(def))
+			}
+			row := -1
+			for i := range rep {
+				if rep[i] == 0 {
+					row++
+				}
+				if def[i] == 1 {
+					rows[row].Vector = append(rows[row].Vector, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+					values = values[8:]
+				}
+			}
+			if row != numRows-1 {
+				t.Errorf("vector levels start %d rows, want %d", row+1, numRows)
+			}
+		default:
+			t.Errorf("unexpected column %q", name)
+		}
+	}
+	return rows, meta
+}
+
+func TestParquetReadBack(t *testing.T) {
+	at := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name string
+		rows []ExportRow
+		dims int
+	}{
+		{"vectors", exportFixture(), 3},
+		{"zero dimensions", []ExportRow{{ID: "beat-20260115-001", CreatedAt: at, Impetus: "Research"}, {ID: "beat-20260115-002", CreatedAt: at}}, 0},
+		{"zero rows", nil, 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeParquet(&buf, tt.rows, Meta{Model: "test-model", Dimensions: tt.dims}); err != nil {
+				t.Fatal(err)
+			}
+			rows, meta := readParquet(t, buf.Bytes())
+			if len(rows) != len(tt.rows) || (len(rows) > 0 && !reflect.DeepEqual(rows, tt.rows)) {
+				t.Errorf("rows = %+v, want %+v", rows, tt.rows)
+			}
+			if meta["model"] != "test-model" || meta["dimensions"] != strconv.Itoa(tt.dims) {
+				t.Errorf("metadata = %v", meta)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AliasesFile in the beats directory maps each entity's canonical label to
+// the other names it goes by, e.g. {"Ada Lovelace": ["Ada", "A. Lovelace"]}.
+const AliasesFile = "aliases.json"
+
+// Aliases resolves entity labels to their canonical form. Keys are lowercase
+// aliases and canonical labels.
+type Aliases map[string]string
+
+// LoadAliases reads the alias registry of a beats directory. A missing file
+// is an empty registry.
+func LoadAliases(beatsDir string) (Aliases, error) {
+	names, err := LoadAliasNames(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	a := make(Aliases)
+	for canonical, aliases := range names {
+		a[strings.ToLower(canonical)] = canonical
+		for _, alias := range aliases {
+			a[strings.ToLower(strings.TrimSpace(alias))] = canonical
+		}
+	}
+	return a, nil
+}
+
+// LoadAliasNames reads the alias registry as written: canonical labels and
+// their aliases. A missing file is an empty registry.
+func LoadAliasNames(beatsDir string) (map[string][]string, error) {
+	data, err := os.ReadFile(filepath.Join(beatsDir, AliasesFile))
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names map[string][]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", AliasesFile, err)
+	}
+	return names, nil
+}
+
+// SaveAliasNames writes the alias registry of a beats directory.
+func SaveAliasNames(beatsDir string, names map[string][]string) error {
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(beatsDir, AliasesFile), append(data, '\n'), 0644)
+}
+
+// Canonical returns the canonical label for label, and whether the registry
+// knows it. Unknown labels are returned as they are.
+func (a Aliases) Canonical(label string) (string, bool) {
+	if c, ok := a[strings.ToLower(strings.TrimSpace(label))]; ok {
+		return c, true
+	}
+	return label, false
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	dir := t.TempDir()
+	if a, err := LoadAliases(dir); err != nil || len(a) != 0 {
+		t.Fatalf("LoadAliases(no file) = %v, %v", a, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AliasesFile), []byte(`{"Ada Lovelace": ["Ada", " A. Lovelace "]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := LoadAliases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for label, want := range map[string]string{"ada": "Ada Lovelace", "a. lovelace": "Ada Lovelace", "ADA LOVELACE": "Ada Lovelace", "Grace": "Grace"} {
+		if got, _ := a.Canonical(label); got != want {
+			t.Errorf("Canonical(%q) = %q; want %q", label, got, want)
+		}
+	}
+}
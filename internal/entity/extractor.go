@@ -6,24 +6,13 @@ import (
 	"regexp"
 	"strings"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/wald"
 )
 
 var urlPattern = regexp.MustCompile(`https?://[^\s<>\[\]"']+`)
 var capitalizedNamePattern = regexp.MustCompile(`\b([A-Z][a-z]+(?:\s+[A-Z][a-z]+)+)\b`)
 
-// WALDConfig represents the WALD.yaml structure for entity extraction
-type WALDConfig struct {
-	Directories []WALDDirectory `yaml:"directories"`
-}
-
-type WALDDirectory struct {
-	Path    string `yaml:"path"`
-	Purpose string `yaml:"purpose"`
-}
-
 // ExtractEntities extracts entities from beat content using WALD.yaml data
 func ExtractEntities(content string, werkRoot string) []beat.Entity {
 	var entities []beat.Entity
@@ -46,10 +35,10 @@ func ExtractEntities(content string, werkRoot string) []beat.Entity {
 	}
 
 	// Load WALD config for cooperators and directories
-	wald := loadWALDConfig(werkRoot)
-	if wald != nil {
+	cfg := loadWALDConfig(werkRoot)
+	if cfg != nil {
 		// Extract cooperator mentions
-		cooperators := extractCooperators(wald)
+		cooperators := extractCooperators(cfg)
 		for slug, displayName := range cooperators {
 			if containsPersonName(content, displayName) || containsPersonName(content, slug) {
 				key := "person:" + slug
@@ -68,7 +57,7 @@ func ExtractEntities(content string, werkRoot string) []beat.Entity {
 		}
 
 		// Extract project/directory mentions
-		for _, dir := range wald.Directories {
+		for _, dir := range cfg.Directories {
 			dirName := filepath.Base(dir.Path)
 			if containsProject(content, dirName, dir.Purpose) {
 				key := "project:" + dir.Path
@@ -106,7 +95,13 @@ func ExtractEntities(content string, werkRoot string) []beat.Entity {
 	return entities
 }
 
-func loadWALDConfig(werkRoot string) *WALDConfig {
+// Key identifies an entity regardless of case: two beats mention the same
+// entity when their keys match.
+func Key(e beat.Entity) string {
+	return strings.ToLower(e.Category) + ":" + strings.ToLower(strings.TrimSpace(e.Label))
+}
+
+func loadWALDConfig(werkRoot string) *wald.Config {
 	if werkRoot == "" {
 		werkRoot = findWerkRoot()
 	}
@@ -114,56 +109,21 @@ func loadWALDConfig(werkRoot string) *WALDConfig {
 		return nil
 	}
 
-	waldPath := filepath.Join(werkRoot, "WALD.yaml")
-	data, err := os.ReadFile(waldPath)
+	config, err := wald.Load(werkRoot)
 	if err != nil {
 		return nil
 	}
-
-	var config WALDConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil
-	}
-
-	return &config
+	return config
 }
 
 func findWerkRoot() string {
-	if root := os.Getenv("BEATS_ROOT"); root != "" {
-		if _, err := os.Stat(filepath.Join(root, "WALD.yaml")); err == nil {
-			return root
-		}
-	}
-
-	cwd, err := os.Getwd()
-	if err != nil {
-		return ""
-	}
-
-	dir := cwd
-	for {
-		if _, err := os.Stat(filepath.Join(dir, "WALD.yaml")); err == nil {
-			return dir
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
-	}
-
-	home, _ := os.UserHomeDir()
-	werkPath := filepath.Join(home, "werk")
-	if _, err := os.Stat(filepath.Join(werkPath, "WALD.yaml")); err == nil {
-		return werkPath
-	}
-
-	return ""
+	cwd, _ := os.Getwd()
+	return wald.FindWerkRoot(cwd)
 }
 
-func extractCooperators(wald *WALDConfig) map[string]string {
+func extractCooperators(cfg *wald.Config) map[string]string {
 	cooperators := make(map[string]string)
-	for _, dir := range wald.Directories {
+	for _, dir := range cfg.Directories {
 		if strings.HasPrefix(dir.Path, "cooperators/") {
 			slug := strings.TrimPrefix(dir.Path, "cooperators/")
 			displayName := slugToDisplayName(slug)
@@ -0,0 +1,125 @@
+// Package fixtures generates reproducible stores: the same seed and options
+// always give the same beats, IDs and timestamps. With the clock frozen
+// (BEATS_FAKE_NOW), robot commands run against such a store print the same
+// output every time, for golden-file tests.
+package fixtures
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// Options configures a generated store.
+type Options struct {
+	Seed  int64
+	Count int       // Beats (default 50)
+	Start time.Time // Day of the first beats (default 2025-01-01 UTC)
+	Days  int       // Days the beats are spread over (default 30)
+}
+
+// Defaults for unset options.
+const (
+	DefaultCount = 50
+	DefaultDays  = 30
+)
+
+// DefaultStart is the first day of generated beats unless set.
+var DefaultStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	topics = []struct {
+		name, dir string
+		notes     []string
+	}{
+		{"pricing", "projects/pricing", []string{
+			"Customers compare our pricing page to %s and leave when the tiers are unclear",
+			"Annual pricing discount came up again in the call with %s",
+			"%s suggested usage-based pricing for the API tier",
+		}},
+		{"onboarding", "projects/onboarding", []string{
+			"New users stall at the onboarding import step; %s saw the same drop",
+			"%s walked through onboarding and skipped the tutorial entirely",
+			"An onboarding checklist would answer most of what %s asked",
+		}},
+		{"search", "projects/search", []string{
+			"Search misses beats that only mention %s in passing",
+			"%s wants search results grouped by project",
+			"Semantic search found the old note about %s that keyword search missed",
+		}},
+		{"retention", "projects/retention", []string{
+			"Weekly retention dips after the second week; %s thinks reminders help",
+			"%s churned after the export broke; retention needs a safety net",
+			"Retention cohort from %s's team is the strongest so far",
+		}},
+	}
+	people  = []string{"Ada", "Grace", "Linus", "Margaret", "Alan"}
+	orgs    = []string{"Acme", "Globex", "Initech"}
+	impetus = []string{"Manual entry", "Manual entry", "Coaching insight", "Session insight", "Web discovery", "Code change"}
+)
+
+// Generate returns the beats of a store for opts, oldest first, with IDs
+// numbered per day as the store would number them.
+func Generate(opts Options) []*beat.Beat {
+	if opts.Count <= 0 {
+		opts.Count = DefaultCount
+	}
+	if opts.Days <= 0 {
+		opts.Days = DefaultDays
+	}
+	if opts.Start.IsZero() {
+		opts.Start = DefaultStart
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	// Times first, so IDs can be numbered in order
+	times := make([]time.Time, opts.Count)
+	span := opts.Days * 24 * 60 // Minutes
+	for i := range times {
+		times[i] = opts.Start.UTC().Add(time.Duration(rng.Intn(span)) * time.Minute)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	beats := make([]*beat.Beat, 0, opts.Count)
+	seq := make(map[string]int)
+	for i, at := range times {
+		topic := topics[rng.Intn(len(topics))]
+		person := people[rng.Intn(len(people))]
+		org := orgs[rng.Intn(len(orgs))]
+		mentioned := person
+		if rng.Intn(3) == 0 {
+			mentioned = org
+		}
+		content := fmt.Sprintf(topic.notes[rng.Intn(len(topic.notes))], mentioned)
+
+		day := beat.IDDate(at)
+		seq[day]++
+		b := &beat.Beat{
+			ID:          beat.GenerateIDWithSequence(at, seq[day]),
+			CreatedAt:   at,
+			UpdatedAt:   at,
+			Impetus:     beat.Impetus{Label: impetus[rng.Intn(len(impetus))], Meta: map[string]string{"fixture_seed": fmt.Sprint(opts.Seed)}},
+			Content:     content,
+			References:  []beat.Reference{},
+			Entities:    []beat.Entity{{Label: person, Category: "person"}, {Label: topic.name, Category: "concept"}},
+			LinkedBeads: beat.BeadLinks{},
+			Context:     &beat.Context{CapturePath: "/fixtures/" + topic.dir, WALDDirectory: topic.dir, InferenceMethod: "manual", Confidence: 1},
+		}
+		if mentioned == org {
+			b.Entities = append(b.Entities, beat.Entity{Label: org, Category: "organization"})
+		}
+		if b.Impetus.Label == "Web discovery" {
+			url := fmt.Sprintf("https://example.com/%s/%d", topic.name, i+1)
+			b.Content += "\n\n" + url
+			b.References = append(b.References, beat.Reference{Kind: "url", Subtype: "web", Locator: url})
+		}
+		if rng.Intn(5) == 0 {
+			b.LinkedBeads = beat.NewBeadLinks([]string{fmt.Sprintf("bd-%s-%d", topic.name, rng.Intn(3)+1)}, beat.RelationSeed, "fixtures", at)
+		}
+		beats = append(beats, b)
+	}
+	return beats
+}
@@ -0,0 +1,59 @@
+package hooks
+
+import (
+	"encoding/json"
+)
+
+// ApprovalHook stages --robot-commit-beat captures from agents not listed
+// as trusted, so they only enter the store once approved with bt approve.
+// The agent name is self-declared in the commit's input, so the list is
+// advisory: it sorts cooperating agents, it does not authenticate them.
+type ApprovalHook struct {
+	Enabled bool     `json:"enabled"`
+	Trusted []string `json:"trusted"` // Agent names whose commits are stored directly
+}
+
+// DefaultApprovalHook returns the defaults used for unset fields.
+func DefaultApprovalHook() ApprovalHook {
+	return ApprovalHook{
+		Enabled: false,
+		Trusted: []string{},
+	}
+}
+
+// GetApprovalConfig reads the approval section of hooks.json, filling
+// defaults.
+func GetApprovalConfig(beatsDir string) ApprovalHook {
+	config := DefaultApprovalHook()
+
+	data, err := readHooksConfig(beatsDir)
+	if err != nil {
+		return config
+	}
+	var fullConfig struct {
+		Approval ApprovalHook `json:"approval"`
+	}
+	if err := json.Unmarshal(data, &fullConfig); err != nil {
+		return config
+	}
+
+	config.Enabled = fullConfig.Approval.Enabled
+	if fullConfig.Approval.Trusted != nil {
+		config.Trusted = fullConfig.Approval.Trusted
+	}
+	return config
+}
+
+// Trusts reports whether an agent's commits skip approval. With the hook
+// off every agent is trusted; an agent that gives no name never is.
+func (a ApprovalHook) Trusts(agent string) bool {
+	if !a.Enabled {
+		return true
+	}
+	for _, name := range a.Trusted {
+		if agent != "" && name == agent {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// SynthesesDir is the directory (inside .beats) holding archived syntheses.
+const SynthesesDir = "syntheses"
+
+// SynthesisRecord is an archived synthesis request and its eventual response.
+type SynthesisRecord struct {
+	ID          string           `json:"id"`
+	Request     SynthesisRequest `json:"request"`
+	Response    string           `json:"response,omitempty"`
+	RespondedAt *time.Time       `json:"responded_at,omitempty"`
+}
+
+// synthesisID derives a stable, sortable archive ID from the trigger time.
+func synthesisID(t time.Time) string {
+	return "synthesis-" + t.UTC().Format("20060102T150405Z")
+}
+
+func synthesisPath(beatsDir, id string) string {
+	return filepath.Join(beatsDir, SynthesesDir, id+".json")
+}
+
+// ArchiveSynthesis stores a triggered synthesis request under .beats/syntheses/.
+func ArchiveSynthesis(beatsDir string, req SynthesisRequest) (*SynthesisRecord, error) {
+	if req.ID == "" {
+		req.ID = synthesisID(req.TriggeredAt)
+	}
+	record := &SynthesisRecord{ID: req.ID, Request: req}
+	if err := saveSynthesisRecord(beatsDir, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func saveSynthesisRecord(beatsDir string, record *SynthesisRecord) error {
+	if err := os.MkdirAll(filepath.Join(beatsDir, SynthesesDir), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(synthesisPath(beatsDir, record.ID), data, 0644)
+}
+
+// GetSynthesis reads an archived synthesis by ID.
+func GetSynthesis(beatsDir, id string) (*SynthesisRecord, error) {
+	data, err := os.ReadFile(synthesisPath(beatsDir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("synthesis not found: %s", id)
+		}
+		return nil, err
+	}
+	var record SynthesisRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListSyntheses returns all archived syntheses, newest first.
+func ListSyntheses(beatsDir string) ([]SynthesisRecord, error) {
+	entries, err := os.ReadDir(filepath.Join(beatsDir, SynthesesDir))
+	if os.IsNotExist(err) {
+		return []SynthesisRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []SynthesisRecord
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		record, err := GetSynthesis(beatsDir, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		records = append(records, *record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Request.TriggeredAt.After(records[j].Request.TriggeredAt)
+	})
+	return records, nil
+}
+
+// RecordSynthesisResponse attaches a response to an archived synthesis and
+// clears the pending request. If id is empty, the pending request (or the most
+// recent unanswered synthesis) is used.
+func RecordSynthesisResponse(beatsDir, id, response string) (*SynthesisRecord, error) {
+	if id == "" {
+		if pending, err := GetSynthesisRequest(beatsDir); err == nil && pending.ID != "" {
+			id = pending.ID
+		}
+	}
+	if id == "" {
+		records, err := ListSyntheses(beatsDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			if r.Response == "" {
+				id = r.ID
+				break
+			}
+		}
+	}
+	if id == "" {
+		return nil, fmt.Errorf("no unanswered synthesis found")
+	}
+
+	record, err := GetSynthesis(beatsDir, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now().UTC()
+	record.Response = response
+	record.RespondedAt = &now
+	if err := saveSynthesisRecord(beatsDir, record); err != nil {
+		return nil, err
+	}
+
+	if pending, err := GetSynthesisRequest(beatsDir); err == nil && pending.ID == id {
+		if err := ClearSynthesisNeeded(beatsDir); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// BeadChangesFile records every on_bead_changed event, one JSON object per line.
+const BeadChangesFile = "bead_changes.jsonl"
+
+// BeadChangedHook configures the reaction to changes in the bead provider,
+// detected by bt watch beads.
+type BeadChangedHook struct {
+	Enabled      bool   `json:"enabled"`
+	Script       string `json:"script,omitempty"`        // Receives the BeadChangedEvent as JSON on stdin
+	MaxSuggested int    `json:"max_suggested,omitempty"` // Related beats suggested per event (default 5)
+}
+
+// BeadChangedEvent describes a bead that appeared or changed status, with
+// the beats already linked to it and unlinked beats that look related.
+type BeadChangedEvent struct {
+	Kind           string               `json:"kind"` // "created" or "status"
+	BeadID         string               `json:"bead_id"`
+	Title          string               `json:"title"`
+	Status         string               `json:"status,omitempty"`
+	PreviousStatus string               `json:"previous_status,omitempty"`
+	URL            string               `json:"url,omitempty"`
+	DetectedAt     time.Time            `json:"detected_at"`
+	LinkedBeats    []beat.Beat          `json:"linked_beats"`
+	SuggestedBeats []beat.SuggestedBeat `json:"suggested_beats"`
+}
+
+// BeadChangedConfig returns the on_bead_changed section with defaults filled.
+func (m *Manager) BeadChangedConfig() BeadChangedHook {
+	config := m.config.BeadChanged
+	if config.MaxSuggested <= 0 {
+		config.MaxSuggested = 5
+	}
+	return config
+}
+
+// OnBeadChanged records the event in bead_changes.jsonl, pipes it to the
+// configured script and sends a bead_changed notification. It does nothing
+// when the hook is disabled.
+func (m *Manager) OnBeadChanged(event BeadChangedEvent) error {
+	if !m.config.BeadChanged.Enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := appendBeadChange(m.beatsDir, data); err != nil {
+		return fmt.Errorf("failed to record bead change: %w", err)
+	}
+
+	if script := m.config.BeadChanged.Script; script != "" {
+		if stdout, stderr, err := m.runHookScript("on_bead_changed", script, data); err != nil {
+			return fmt.Errorf("on_bead_changed script failed: %w\nOutput: %s%s", err, stdout, stderr)
+		}
+	}
+
+	message := fmt.Sprintf("Bead %s %s: %s", event.BeadID, event.Status, event.Title)
+	if event.Kind == "created" {
+		message = fmt.Sprintf("New bead %s: %s", event.BeadID, event.Title)
+	}
+	if n := len(event.SuggestedBeats); n > 0 {
+		message += fmt.Sprintf(" (%d related beats)", n)
+	}
+	m.notify(EventBeadChanged, message)
+	return nil
+}
+
+func appendBeadChange(beatsDir string, data []byte) error {
+	f, err := os.OpenFile(filepath.Join(beatsDir, BeadChangesFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,51 @@
+package hooks
+
+import (
+	"encoding/json"
+)
+
+// DuplicatesHook configures semantic duplicate detection when a beat is committed.
+type DuplicatesHook struct {
+	Enabled    bool    `json:"enabled"`
+	Threshold  float64 `json:"threshold"`   // Cosine similarity at or above which beats are flagged
+	Action     string  `json:"action"`      // "warn" (default) or "reject"
+	MaxResults int     `json:"max_results"` // Candidates reported
+}
+
+// DefaultDuplicatesHook returns the defaults used for unset fields.
+func DefaultDuplicatesHook() DuplicatesHook {
+	return DuplicatesHook{
+		Enabled:    false,
+		Threshold:  0.92,
+		Action:     "warn",
+		MaxResults: 3,
+	}
+}
+
+// GetDuplicatesConfig reads the duplicates section of hooks.json, filling defaults.
+func GetDuplicatesConfig(beatsDir string) DuplicatesHook {
+	config := DefaultDuplicatesHook()
+
+	data, err := readHooksConfig(beatsDir)
+	if err != nil {
+		return config
+	}
+	var fullConfig struct {
+		Duplicates DuplicatesHook `json:"duplicates"`
+	}
+	if err := json.Unmarshal(data, &fullConfig); err != nil {
+		return config
+	}
+
+	config.Enabled = fullConfig.Duplicates.Enabled
+	if fullConfig.Duplicates.Threshold > 0 {
+		config.Threshold = fullConfig.Duplicates.Threshold
+	}
+	if fullConfig.Duplicates.Action != "" {
+		config.Action = fullConfig.Duplicates.Action
+	}
+	if fullConfig.Duplicates.MaxResults > 0 {
+		config.MaxResults = fullConfig.Duplicates.MaxResults
+	}
+	return config
+}
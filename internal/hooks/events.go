@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// Event targets: where on_event delivers.
+const (
+	TargetHook    = "hook"    // The on_event script, with the event as JSON on stdin
+	TargetWebhook = "webhook" // A POST of the event as JSON to the on_event URL
+)
+
+// EventHook configures a downstream consumer of store events: a script, a
+// webhook URL or both receive every beat_added and beat_linked event as
+// JSON. bt events replay re-sends past events to the same places.
+type EventHook struct {
+	Enabled bool     `json:"enabled"`
+	Script  string   `json:"script,omitempty"`
+	URL     string   `json:"url,omitempty"`
+	Events  []string `json:"events,omitempty"` // Default: beat_added and beat_linked
+}
+
+// BeatEvent is one store event as a consumer receives it.
+type BeatEvent struct {
+	Event    string     `json:"event"` // beat_added or beat_linked
+	At       time.Time  `json:"at"`
+	BeatID   string     `json:"beat_id"`
+	BeadID   string     `json:"bead_id,omitempty"`  // For beat_linked
+	Relation string     `json:"relation,omitempty"` // For beat_linked
+	Beat     *beat.Beat `json:"beat,omitempty"`     // The beat as it was then
+	Replay   bool       `json:"replay,omitempty"`   // Re-sent by bt events replay
+}
+
+// wants reports whether the hook is subscribed to the event.
+func (h EventHook) wants(event string) bool {
+	if len(h.Events) == 0 {
+		return event == EventBeatAdded || event == EventBeatLinked
+	}
+	for _, e := range h.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// EventConfig returns the on_event section of hooks.json.
+func (m *Manager) EventConfig() EventHook {
+	return m.config.Events
+}
+
+// emit delivers a live event to the script and URL of an enabled on_event
+// hook. Failures are returned but never block beat storage.
+func (m *Manager) emit(event BeatEvent) error {
+	h := m.config.Events
+	if !h.Enabled || !h.wants(event.Event) {
+		return nil
+	}
+	var errs []string
+	if h.Script != "" {
+		if err := m.DeliverEvent(event, TargetHook, ""); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if h.URL != "" {
+		if err := m.DeliverEvent(event, TargetWebhook, ""); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("on_event failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// DeliverEvent sends one event to a target: the on_event script, or a
+// webhook at url (the on_event URL when empty). It does not check whether
+// the hook is enabled, so a replay can backfill a consumer before it is
+// switched on.
+func (m *Manager) DeliverEvent(event BeatEvent, target, url string) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	switch target {
+	case TargetHook:
+		script := m.config.Events.Script
+		if script == "" {
+			return fmt.Errorf("no on_event script configured in %s", HooksConfigFile)
+		}
+		if stdout, stderr, err := m.runHookScript("on_event", script, data, event.Event); err != nil {
+			return fmt.Errorf("on_event script failed: %w\nOutput: %s%s", err, stdout, stderr)
+		}
+		return nil
+	case TargetWebhook:
+		if url == "" {
+			url = m.config.Events.URL
+		}
+		if url == "" {
+			return fmt.Errorf("no on_event url configured in %s (or pass --url)", HooksConfigFile)
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Beats-Event", event.Event)
+		if err := doNotifyRequest(req); err != nil {
+			return fmt.Errorf("webhook %s: %w", url, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown event target %q (use %s or %s)", target, TargetHook, TargetWebhook)
+}
+
+// OnBeatLinked is called after an update links a beat to beads it was not
+// linked to before.
+func (m *Manager) OnBeatLinked(b *beat.Beat, beadIDs []string, at time.Time) error {
+	var errs []string
+	for _, id := range beadIDs {
+		link, _ := b.LinkedBeads.Get(id)
+		if err := m.emit(BeatEvent{Event: EventBeatLinked, At: at, BeatID: b.ID, BeadID: id, Relation: link.Relation, Beat: b}); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/profile"
+)
+
+const (
+	// HookLogFile records every hook script run, including its output.
+	HookLogFile = "hooks.log"
+
+	defaultScriptTimeout = 30 * time.Second
+	maxLoggedOutput      = 4096
+
+	// scriptWaitDelay bounds the wait for output after a script is killed,
+	// in case something it started still holds stdout or stderr open.
+	scriptWaitDelay = 2 * time.Second
+)
+
+// defaultEnvAllowlist is passed through to hook scripts in addition to BEATS_*.
+// The second line is what programs on Windows expect to find.
+var defaultEnvAllowlist = []string{"PATH", "HOME", "USER", "LANG", "TMPDIR", "TZ",
+	"USERPROFILE", "USERNAME", "APPDATA", "LOCALAPPDATA", "SYSTEMROOT", "COMSPEC", "PATHEXT", "TEMP", "TMP"}
+
+// ScriptsConfig controls how hook scripts are executed.
+type ScriptsConfig struct {
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"` // Default 30
+	EnvAllowlist   []string `json:"env_allowlist,omitempty"`   // Extra variables passed through
+	WorkDir        string   `json:"work_dir,omitempty"`        // Relative to .beats (default: .beats)
+}
+
+// HookLogEntry is one line of hooks.log.
+type HookLogEntry struct {
+	Time       time.Time `json:"time"`
+	Hook       string    `json:"hook"`
+	Script     string    `json:"script"`
+	ExitCode   int       `json:"exit_code"`
+	DurationMs int64     `json:"duration_ms"`
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// scriptEnv builds the restricted environment for a hook script: BEATS_*
+// variables from the parent, the allowlist, and injected hook metadata.
+func (m *Manager) scriptEnv(hook string) []string {
+	// Variable names are case-insensitive on Windows ("Path")
+	key := func(name string) string { return name }
+	if runtime.GOOS == "windows" {
+		key = strings.ToUpper
+	}
+	allowed := map[string]bool{}
+	for _, name := range defaultEnvAllowlist {
+		allowed[key(name)] = true
+	}
+	for _, name := range m.config.Scripts.EnvAllowlist {
+		allowed[key(name)] = true
+	}
+
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		name = key(name)
+		if name == "BEATS_DIR" || name == "BEATS_HOOK" {
+			continue // Injected below
+		}
+		if strings.HasPrefix(name, "BEATS_") || allowed[name] {
+			env = append(env, kv)
+		}
+	}
+	return append(env, "BEATS_DIR="+m.beatsDir, "BEATS_HOOK="+hook)
+}
+
+func (m *Manager) scriptDir() string {
+	dir := m.config.Scripts.WorkDir
+	if dir == "" {
+		return m.beatsDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(m.beatsDir, dir)
+	}
+	return dir
+}
+
+// runHookScript executes a hook script with a timeout, restricted environment
+// and configured working directory, and records the run in hooks.log.
+func (m *Manager) runHookScript(hook, script string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	defer profile.Start(profile.Hooks)()
+	timeout := defaultScriptTimeout
+	if m.config.Scripts.TimeoutSeconds > 0 {
+		timeout = time.Duration(m.config.Scripts.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Relative script paths are resolved against the directory of the
+	// hooks.json that set them: .beats, or the user config directory for a
+	// global hook. work_dir does not change this.
+	path := script
+	if strings.ContainsAny(path, `/`+string(filepath.Separator)) && !filepath.IsAbs(path) {
+		base := m.beatsDir
+		if dir := m.scriptDirs[hook]; dir != "" {
+			base = dir
+		}
+		path = filepath.Join(base, filepath.FromSlash(path))
+	}
+
+	var stdout, stderr bytes.Buffer
+	name, args := scriptCommand(runtime.GOOS, path, args)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = m.scriptDir()
+	cmd.Env = m.scriptEnv(hook)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = scriptWaitDelay
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	entry := HookLogEntry{
+		Time:       start.UTC(),
+		Hook:       hook,
+		Script:     script,
+		DurationMs: time.Since(start).Milliseconds(),
+		Stdout:     truncate(stdout.String(), maxLoggedOutput),
+		Stderr:     truncate(stderr.String(), maxLoggedOutput),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			entry.ExitCode = exitErr.ExitCode()
+		}
+	}
+	_ = appendHookLog(m.beatsDir, entry)
+
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// scriptCommand returns the program and arguments that run a hook script on
+// goos. Windows cannot execute scripts directly, so they are handed to the
+// interpreter their extension names: PowerShell for .ps1, cmd for .cmd and
+// .bat, and sh (from Git for Windows, MSYS2 or similar) for .sh.
+func scriptCommand(goos, path string, args []string) (string, []string) {
+	if goos != "windows" {
+		return path, args
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		return "powershell.exe", append([]string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", path}, args...)
+	case ".cmd", ".bat":
+		return "cmd.exe", append([]string{"/C", path}, args...)
+	case ".sh":
+		return "sh", append([]string{path}, args...)
+	default:
+		return path, args
+	}
+}
+
+func appendHookLog(beatsDir string, entry HookLogEntry) error {
+	f, err := os.OpenFile(filepath.Join(beatsDir, HookLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ReadHookLog returns the entries of hooks.log, oldest first. A missing log
+// is empty; lines that do not parse are skipped.
+func ReadHookLog(beatsDir string) ([]HookLogEntry, error) {
+	data, err := os.ReadFile(filepath.Join(beatsDir, HookLogFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []HookLogEntry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var entry HookLogEntry
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,149 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// scriptStore returns a store whose hooks.json is config, with script
+// written to .beats/hook.sh.
+func scriptStore(t *testing.T, config, script string) *Manager {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the hook")
+	}
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, HooksConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hook.sh"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestScriptEnv(t *testing.T) {
+	m := scriptStore(t, `{"scripts": {"env_allowlist": ["EXTRA_VAR"]}}`, "env\n")
+	t.Setenv("BEATS_CUSTOM", "kept")
+	t.Setenv("EXTRA_VAR", "allowed")
+	t.Setenv("SECRET_TOKEN", "leaked")
+	t.Setenv("BEATS_DIR", "/elsewhere")
+
+	stdout, _, err := m.runHookScript("test", "./hook.sh", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{}
+	for _, line := range strings.Split(string(stdout), "\n") {
+		if name, value, ok := strings.Cut(line, "="); ok {
+			env[name] = value
+		}
+	}
+	for name, want := range map[string]string{
+		"BEATS_CUSTOM": "kept",
+		"EXTRA_VAR":    "allowed",
+		"BEATS_DIR":    m.beatsDir,
+		"BEATS_HOOK":   "test",
+		"PATH":         os.Getenv("PATH"),
+	} {
+		if env[name] != want {
+			t.Errorf("%s = %q, want %q", name, env[name], want)
+		}
+	}
+	if _, ok := env["SECRET_TOKEN"]; ok {
+		t.Error("SECRET_TOKEN was passed to the script")
+	}
+}
+
+func TestScriptTimeout(t *testing.T) {
+	// The background sleep inherits stdout and would keep the wait going
+	// if only the script itself were killed
+	m := scriptStore(t, `{"scripts": {"timeout_seconds": 1}}`, "echo started\nsleep 30 &\nsleep 30\n")
+
+	start := time.Now()
+	stdout, _, err := m.runHookScript("test", "./hook.sh", nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1s") {
+		t.Fatalf("err = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1*time.Second+scriptWaitDelay {
+		t.Errorf("run took %s; the forked child held it open", elapsed)
+	}
+	if string(stdout) != "started\n" {
+		t.Errorf("stdout = %q, want the output from before the timeout", stdout)
+	}
+
+	entries, err := ReadHookLog(m.beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ExitCode != -1 || !strings.Contains(entries[0].Error, "timed out") {
+		t.Errorf("hooks.log = %+v, want one timed-out run", entries)
+	}
+}
+
+func TestHookLog(t *testing.T) {
+	m := scriptStore(t, `{}`, "cat\necho oops >&2\nexit 3\n")
+
+	if _, _, err := m.runHookScript("pre_commit", "./hook.sh", []byte("input")); err == nil {
+		t.Fatal("want an error for exit status 3")
+	}
+	if _, _, err := m.runHookScript("on_event", "./hook.sh", []byte("second")); err == nil {
+		t.Fatal("want an error for exit status 3")
+	}
+
+	entries, err := ReadHookLog(m.beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("hooks.log has %d entries, want 2", len(entries))
+	}
+	got := entries[0]
+	if got.Hook != "pre_commit" || got.Script != "./hook.sh" || got.ExitCode != 3 || got.Stdout != "input" || got.Stderr != "oops\n" {
+		t.Errorf("first entry = %+v", got)
+	}
+	if entries[1].Hook != "on_event" || entries[1].Stdout != "second" {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+}
+
+func TestGlobalHookScriptPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the hook")
+	}
+	configDir := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+	write := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(configDir, HooksConfigFile), `{"pre_commit": {"enabled": true, "script": "./hook.sh"}}`, 0644)
+	write(filepath.Join(configDir, "hook.sh"), "#!/bin/sh\necho global >&2\nexit 1\n", 0755)
+
+	// Every store runs the script next to the global hooks.json; one that
+	// sets its own script has it resolved against .beats.
+	bare, own := t.TempDir(), t.TempDir()
+	write(filepath.Join(own, HooksConfigFile), `{"pre_commit": {"script": "./hook.sh"}}`, 0644)
+	write(filepath.Join(own, "hook.sh"), "#!/bin/sh\necho store >&2\nexit 1\n", 0755)
+	for dir, want := range map[string]string{bare: "global", own: "store"} {
+		_, err := RunPreCommit(dir, &beat.ProposedBeat{Content: "draft"})
+		var rejected *PreCommitError
+		if !errors.As(err, &rejected) || rejected.Reason != want {
+			t.Errorf("err = %v, want the %s script to reject", err, want)
+		}
+	}
+}
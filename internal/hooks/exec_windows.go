@@ -0,0 +1,9 @@
+//go:build windows
+
+package hooks
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows: the script alone is killed on
+// timeout, and WaitDelay stops the wait on anything it left running.
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitHookMarker identifies the line beats adds to a git post-commit hook.
+const gitHookMarker = "# beats: capture commit as beat"
+
+// gitHookScript is installed next to post-commit and does the capture, so the
+// post-commit hook itself only gains a line calling it.
+const gitHookScript = "post-commit.beats"
+
+// gitHookCall is the line added to post-commit. It runs the script next to
+// the hook, wherever core.hooksPath puts it.
+const gitHookCall = `sh "$(dirname "$0")/` + gitHookScript + `" || true`
+
+// GitHookError reports an existing post-commit hook beats cannot safely
+// extend. Line is what to add to it by hand.
+type GitHookError struct {
+	Path   string
+	Reason string
+	Line   string
+}
+
+func (e *GitHookError) Error() string {
+	return fmt.Sprintf("%s %s; add this line where it runs on every commit:\n  %s", e.Path, e.Reason, e.Line)
+}
+
+// InstallGitHook installs the post-commit hook of the repository at repoDir
+// so every commit is captured as a beat in beatsDir. The capture lives in
+// post-commit.beats; an existing post-commit hook gains a line calling it if
+// it is a plain shell script that runs to its end, and is left alone with a
+// *GitHookError otherwise.
+// Returns the hook path and whether it was already installed.
+func InstallGitHook(repoDir, beatsDir, beatsBin string) (string, bool, error) {
+	gitDir := filepath.Join(repoDir, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil || !info.IsDir() {
+		return "", false, fmt.Errorf("%s is not a git repository root", repoDir)
+	}
+
+	hooksDir := filepath.Join(gitDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", false, err
+	}
+	hookPath := filepath.Join(hooksDir, "post-commit")
+
+	absBeatsDir, err := filepath.Abs(beatsDir)
+	if err != nil {
+		absBeatsDir = beatsDir
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\n%q hooks --dir %q git-commit >/dev/null 2>&1\n", gitHookMarker, beatsBin, absBeatsDir)
+	if err := os.WriteFile(filepath.Join(hooksDir, gitHookScript), []byte(script), 0755); err != nil {
+		return "", false, err
+	}
+
+	existing, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", false, err
+	}
+	if strings.Contains(string(existing), gitHookMarker) {
+		return hookPath, true, nil
+	}
+
+	line := gitHookMarker + "\n" + gitHookCall + "\n"
+	var content string
+	if len(existing) == 0 {
+		content = "#!/bin/sh\n" + line
+	} else {
+		if reason := unextendableHook(string(existing)); reason != "" {
+			return "", false, &GitHookError{Path: hookPath, Reason: reason, Line: gitHookCall}
+		}
+		content = strings.TrimRight(string(existing), "\n") + "\n\n" + line
+	}
+
+	if err := os.WriteFile(hookPath, []byte(content), 0755); err != nil {
+		return "", false, err
+	}
+	return hookPath, false, nil
+}
+
+// unextendableHook says why a line appended to hook would not run (or would
+// not be shell), or returns "" if appending is safe.
+func unextendableHook(hook string) string {
+	lines := strings.Split(strings.TrimSpace(hook), "\n")
+	if shebang := lines[0]; strings.HasPrefix(shebang, "#!") {
+		fields := strings.Fields(strings.TrimPrefix(shebang, "#!"))
+		interp := ""
+		if len(fields) > 0 {
+			interp = filepath.Base(fields[0])
+		}
+		if interp == "env" && len(fields) > 1 {
+			interp = fields[1]
+		}
+		switch interp {
+		case "sh", "bash", "dash", "zsh", "ksh":
+		default:
+			return fmt.Sprintf("is not a shell script (%s)", shebang)
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		last := strings.TrimSpace(lines[i])
+		if last == "" || strings.HasPrefix(last, "#") {
+			continue
+		}
+		if cmd := strings.Fields(last)[0]; cmd == "exit" || cmd == "exec" {
+			return fmt.Sprintf("ends in %q, so nothing after it runs", last)
+		}
+		break
+	}
+	return ""
+}
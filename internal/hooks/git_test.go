@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func gitRepo(t *testing.T) (repo, hookPath string) {
+	t.Helper()
+	repo = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".git", "hooks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return repo, filepath.Join(repo, ".git", "hooks", "post-commit")
+}
+
+func TestInstallGitHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs the installed hook with sh")
+	}
+	repo, hookPath := gitRepo(t)
+	beatsDir := t.TempDir()
+	// Stands in for bt, recording how the hook called it
+	calls := filepath.Join(t.TempDir(), "calls")
+	bin := filepath.Join(t.TempDir(), "bt")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\necho \"$@\" >> "+calls+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, existed, err := InstallGitHook(repo, beatsDir, bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != hookPath || existed {
+		t.Errorf("InstallGitHook() = %s, %v; want %s, false", path, existed, hookPath)
+	}
+	hook, _ := os.ReadFile(hookPath)
+	if !strings.HasPrefix(string(hook), "#!/bin/sh\n") || strings.Count(string(hook), gitHookMarker) != 1 {
+		t.Errorf("post-commit =\n%s", hook)
+	}
+
+	// Re-installing changes nothing
+	if _, existed, err := InstallGitHook(repo, beatsDir, bin); err != nil || !existed {
+		t.Errorf("second InstallGitHook() = %v, %v; want already installed", existed, err)
+	}
+	if again, _ := os.ReadFile(hookPath); string(again) != string(hook) {
+		t.Errorf("post-commit changed on re-install:\n%s", again)
+	}
+
+	cmd := exec.Command("sh", hookPath)
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("running the hook: %v\n%s", err, out)
+	}
+	got, _ := os.ReadFile(calls)
+	if want := "hooks --dir " + beatsDir + " git-commit\n"; string(got) != want {
+		t.Errorf("bt called with %q, want %q", got, want)
+	}
+}
+
+func TestInstallGitHook_ExtendsShellHook(t *testing.T) {
+	repo, hookPath := gitRepo(t)
+	existing := "#!/usr/bin/env bash\nset -e\nnotify-send committed\n"
+	if err := os.WriteFile(hookPath, []byte(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, existed, err := InstallGitHook(repo, t.TempDir(), "bt"); err != nil || existed {
+		t.Fatalf("InstallGitHook() = %v, %v", existed, err)
+	}
+	hook, _ := os.ReadFile(hookPath)
+	if !strings.HasPrefix(string(hook), existing) || !strings.HasSuffix(string(hook), gitHookCall+"\n") {
+		t.Errorf("post-commit =\n%s\nwant the original followed by the beats call", hook)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", "hooks", gitHookScript)); err != nil {
+		t.Errorf("%s not installed: %v", gitHookScript, err)
+	}
+}
+
+func TestInstallGitHook_LeavesOtherHooksAlone(t *testing.T) {
+	for name, existing := range map[string]string{
+		"python": "#!/usr/bin/env python3\nprint('committed')\n",
+		"node":   "#!/usr/local/bin/node\nconsole.log('committed')\n",
+		"exit":   "#!/bin/sh\nmake lint\nexit 0\n",
+		"exec":   "#!/bin/sh\nexec lefthook run post-commit \"$@\"\n\n# trailing comment\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo, hookPath := gitRepo(t)
+			if err := os.WriteFile(hookPath, []byte(existing), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err := InstallGitHook(repo, t.TempDir(), "bt")
+			var hookErr *GitHookError
+			if !errors.As(err, &hookErr) || hookErr.Line != gitHookCall {
+				t.Fatalf("InstallGitHook() error = %v, want a *GitHookError with the line to add", err)
+			}
+			if hook, _ := os.ReadFile(hookPath); string(hook) != existing {
+				t.Errorf("post-commit was modified:\n%s", hook)
+			}
+		})
+	}
+}
@@ -1,14 +1,20 @@
 package hooks
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/questions"
+	"github.com/bierlingm/beats/internal/tokens"
 )
 
 const (
@@ -19,7 +25,12 @@ const (
 
 // HooksConfig defines hook triggers and actions.
 type HooksConfig struct {
-	Synthesis SynthesisHook `json:"synthesis"`
+	Synthesis   SynthesisHook   `json:"synthesis"`
+	PreCommit   PreCommitHook   `json:"pre_commit"`
+	Notify      NotifyHook      `json:"notify"`
+	Scripts     ScriptsConfig   `json:"scripts"`
+	BeadChanged BeadChangedHook `json:"on_bead_changed"`
+	Events      EventHook       `json:"on_event"`
 }
 
 // SynthesisHook configures when synthesis should be triggered.
@@ -30,6 +41,13 @@ type SynthesisHook struct {
 	Script    string `json:"script"`    // Path to script (if action is "script")
 }
 
+// PreCommitHook configures a script that validates or transforms a beat
+// before it is written to the store.
+type PreCommitHook struct {
+	Enabled bool   `json:"enabled"`
+	Script  string `json:"script"` // Receives the ProposedBeat as JSON on stdin
+}
+
 // HookState tracks hook execution state.
 type HookState struct {
 	LastSynthesisAt    time.Time `json:"last_synthesis_at"`
@@ -39,18 +57,26 @@ type HookState struct {
 
 // SynthesisRequest is written to synthesis_needed.json when triggered.
 type SynthesisRequest struct {
-	TriggeredAt     time.Time   `json:"triggered_at"`
-	BeatsSinceLast  int         `json:"beats_since_last"`
-	TotalBeats      int         `json:"total_beats"`
-	RecentBeats     []beat.Beat `json:"recent_beats"`
-	SynthesisPrompt string      `json:"synthesis_prompt"`
+	ID              string               `json:"id,omitempty"` // Archive ID under .beats/syntheses/
+	TriggeredAt     time.Time            `json:"triggered_at"`
+	BeatsSinceLast  int                  `json:"beats_since_last"`
+	TotalBeats      int                  `json:"total_beats"`
+	RecentBeats     []beat.Beat          `json:"recent_beats"`
+	OpenQuestions   []questions.Question `json:"open_questions,omitempty"` // Oldest open loops in the whole store
+	SynthesisPrompt string               `json:"synthesis_prompt"`
+	EstimatedTokens int                  `json:"estimated_tokens,omitempty"` // Of the prompt
+	BeatsOmitted    int                  `json:"beats_omitted,omitempty"`    // Oldest recent beats left out of the prompt to fit prompt_tokens
 }
 
+// synthesisQuestions is how many open questions a synthesis request lists.
+const synthesisQuestions = 10
+
 // Manager handles hook execution.
 type Manager struct {
-	beatsDir string
-	config   *HooksConfig
-	state    *HookState
+	beatsDir   string
+	config     *HooksConfig
+	state      *HookState
+	scriptDirs map[string]string // Hook -> directory its relative script path is resolved against
 }
 
 // NewManager creates a new hooks manager.
@@ -72,13 +98,20 @@ func NewManager(beatsDir string) (*Manager, error) {
 }
 
 func (m *Manager) loadConfig() error {
-	path := filepath.Join(m.beatsDir, HooksConfigFile)
-	data, err := os.ReadFile(path)
+	e, err := LoadEffectiveHooks(m.beatsDir)
+	if err != nil {
+		return err
+	}
+	if !e.GlobalExists && !e.StoreExists {
+		return &os.PathError{Op: "open", Path: e.StoreFile, Err: os.ErrNotExist}
+	}
+	data, err := json.Marshal(e.Config)
 	if err != nil {
 		return err
 	}
 
 	m.config = &HooksConfig{}
+	m.scriptDirs = e.scriptDirs
 	return json.Unmarshal(data, m.config)
 }
 
@@ -111,9 +144,85 @@ func (m *Manager) OnBeatAdded(newBeat *beat.Beat, allBeats []beat.Beat) error {
 		return fmt.Errorf("synthesis hook failed: %w", err)
 	}
 
+	m.notify(EventBeatAdded, fmt.Sprintf("Captured %s (%d beats today)", newBeat.ID, countToday(allBeats)))
+	_ = m.emit(BeatEvent{Event: EventBeatAdded, At: clock.Now().UTC(), BeatID: newBeat.ID, Beat: newBeat})
+
 	return m.saveState()
 }
 
+// countToday returns how many beats were created on the current day in the
+// configured time zone.
+func countToday(allBeats []beat.Beat) int {
+	loc, err := config.Get().Location()
+	if err != nil {
+		loc = time.Local
+	}
+	today := clock.Now().In(loc).Format("2006-01-02")
+	count := 0
+	for _, b := range allBeats {
+		if b.CreatedAt.In(loc).Format("2006-01-02") == today {
+			count++
+		}
+	}
+	return count
+}
+
+// PreCommitError is returned when the pre_commit script rejects a beat.
+// Reason is what the script wrote to stderr, or how it failed.
+type PreCommitError struct {
+	Reason string
+}
+
+func (e *PreCommitError) Error() string {
+	return "pre-commit hook rejected beat: " + e.Reason
+}
+
+// RunPreCommit pipes a proposed beat through the pre_commit script.
+// The script reads the ProposedBeat JSON from stdin. A non-zero exit rejects
+// the beat with a *PreCommitError; JSON written to stdout replaces the
+// proposal, empty stdout keeps it.
+func (m *Manager) RunPreCommit(proposed *beat.ProposedBeat) (*beat.ProposedBeat, error) {
+	if !m.config.PreCommit.Enabled || m.config.PreCommit.Script == "" {
+		return proposed, nil
+	}
+
+	input, err := json.Marshal(proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr, err := m.runHookScript("pre_commit", m.config.PreCommit.Script, input)
+	if err != nil {
+		reason := strings.TrimSpace(string(stderr))
+		if reason == "" {
+			reason = err.Error()
+		}
+		return nil, &PreCommitError{Reason: reason}
+	}
+
+	if len(bytes.TrimSpace(stdout)) == 0 {
+		return proposed, nil
+	}
+
+	var transformed beat.ProposedBeat
+	if err := json.Unmarshal(stdout, &transformed); err != nil {
+		return nil, fmt.Errorf("pre-commit hook returned invalid JSON: %w", err)
+	}
+	if transformed.Content == "" {
+		return nil, fmt.Errorf("pre-commit hook returned a beat without content")
+	}
+	return &transformed, nil
+}
+
+// RunPreCommit loads the hooks config for beatsDir and runs the pre_commit hook.
+func RunPreCommit(beatsDir string, proposed *beat.ProposedBeat) (*beat.ProposedBeat, error) {
+	m, err := NewManager(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	return m.RunPreCommit(proposed)
+}
+
 func (m *Manager) checkSynthesisHook(allBeats []beat.Beat) error {
 	if !m.config.Synthesis.Enabled {
 		return nil
@@ -146,12 +255,27 @@ func (m *Manager) triggerSynthesis(allBeats []beat.Beat, beatsSinceLast int) err
 		}
 	}
 
+	triggeredAt := clock.Now().UTC()
+	open := questions.Open(allBeats, triggeredAt)
+	if len(open) > synthesisQuestions {
+		open = open[:synthesisQuestions]
+	}
+	prompt, omitted := generateSynthesisPrompt(recentBeats, open, config.Get().PromptBudget())
 	request := SynthesisRequest{
-		TriggeredAt:     time.Now().UTC(),
+		ID:              synthesisID(triggeredAt),
+		TriggeredAt:     triggeredAt,
 		BeatsSinceLast:  beatsSinceLast,
 		TotalBeats:      m.state.TotalBeats,
 		RecentBeats:     recentBeats,
-		SynthesisPrompt: generateSynthesisPrompt(recentBeats),
+		OpenQuestions:   open,
+		SynthesisPrompt: prompt,
+		EstimatedTokens: tokens.Estimate(prompt),
+		BeatsOmitted:    omitted,
+	}
+
+	// Keep a permanent record; synthesis_needed.json is cleared after processing
+	if _, err := ArchiveSynthesis(m.beatsDir, request); err != nil {
+		return fmt.Errorf("failed to archive synthesis: %w", err)
 	}
 
 	switch m.config.Synthesis.Action {
@@ -165,8 +289,10 @@ func (m *Manager) triggerSynthesis(allBeats []beat.Beat, beatsSinceLast int) err
 		}
 	}
 
+	m.notify(EventSynthesisPending, fmt.Sprintf("Synthesis pending: %d new beats since last synthesis", beatsSinceLast))
+
 	// Update state
-	m.state.LastSynthesisAt = time.Now().UTC()
+	m.state.LastSynthesisAt = clock.Now().UTC()
 	m.state.LastSynthesisCount = m.state.TotalBeats
 
 	return nil
@@ -196,11 +322,9 @@ func (m *Manager) runScript(request SynthesisRequest) error {
 		return err
 	}
 
-	cmd := exec.Command(m.config.Synthesis.Script, tempFile)
-	cmd.Dir = m.beatsDir
-	output, err := cmd.CombinedOutput()
+	stdout, stderr, err := m.runHookScript("synthesis", m.config.Synthesis.Script, nil, tempFile)
 	if err != nil {
-		return fmt.Errorf("script failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("script failed: %w\nOutput: %s%s", err, stdout, stderr)
 	}
 
 	// Clean up temp file
@@ -208,34 +332,54 @@ func (m *Manager) runScript(request SynthesisRequest) error {
 	return nil
 }
 
-func generateSynthesisPrompt(recentBeats []beat.Beat) string {
-	var beatSummaries []string
-	for _, b := range recentBeats {
-		summary := fmt.Sprintf("- [%s] %s: %s", b.ID, b.Impetus.Label, truncate(b.Content, 100))
-		beatSummaries = append(beatSummaries, summary)
+// generateSynthesisPrompt builds the synthesis prompt. Over a token budget
+// (0 for none) the newest beats are kept, and the number left out returned.
+// The open questions are always listed.
+func generateSynthesisPrompt(recentBeats []beat.Beat, open []questions.Question, budget int) (string, int) {
+	beatSummaries := make([]string, len(recentBeats))
+	newestFirst := make([]int, len(recentBeats))
+	for i, b := range recentBeats {
+		beatSummaries[i] = fmt.Sprintf("- [%s] %s: %s", b.ID, b.Impetus.Label, truncate(b.Content, 100))
+		newestFirst[i] = i
+	}
+	sort.SliceStable(newestFirst, func(i, j int) bool {
+		return recentBeats[newestFirst[i]].CreatedAt.After(recentBeats[newestFirst[j]].CreatedAt)
+	})
+	loops := "(none)"
+	if len(open) > 0 {
+		lines := make([]string, len(open))
+		for i, q := range open {
+			lines[i] = fmt.Sprintf("- [%s] %d days old: %s", q.ID, q.AgeDays, truncate(q.Question, 100))
+		}
+		loops = joinStrings(lines, "\n")
 	}
+	if budget > 0 {
+		budget = max(budget-tokens.Estimate(fmt.Sprintf(synthesisTemplate, len(recentBeats), "", loops)), 1)
+	}
+	sel := tokens.Fit(beatSummaries, newestFirst, budget)
+	return fmt.Sprintf(synthesisTemplate, len(recentBeats), joinStrings(sel.Lines, "\n"), loops), len(sel.Omitted)
+}
 
-	prompt := fmt.Sprintf(`You are the Lattice Weaver - a synthesis agent for the beats/beads system.
+// synthesisTemplate is the synthesis prompt: beat count, beat summaries and
+// open questions.
+const synthesisTemplate = `You are the Lattice Weaver - a synthesis agent for the beats/beads system.
 
 %d new beats have accumulated since the last synthesis. Review them and help "close loops" and "weave things together":
 
 RECENT BEATS:
 %s
 
+OPEN QUESTIONS (oldest first, no follow-up beat or linked bead yet):
+%s
+
 YOUR TASK:
 1. CLUSTERS: Identify thematic clusters or patterns among these beats
 2. CONNECTIONS: Suggest links between beats that relate to each other
-3. OPEN LOOPS: Identify beats that mention something unresolved or that should be followed up
+3. OPEN LOOPS: For each open question, say whether any recent beat answers or advances it; then flag other beats that mention something unresolved
 4. BEAD CANDIDATES: Propose any actionable items (beads) that emerge from these beats
 5. LINKS TO EXISTING: If you know of existing beads, suggest which beats should link to them
 
-Output a concise synthesis report that helps maintain coherence across the knowledge substrate.`,
-		len(recentBeats),
-		joinStrings(beatSummaries, "\n"),
-	)
-
-	return prompt
-}
+Output a concise synthesis report that helps maintain coherence across the knowledge substrate.`
 
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -0,0 +1,49 @@
+package hooks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestRunPreCommit(t *testing.T) {
+	const config = `{"pre_commit": {"enabled": true, "script": "./hook.sh"}}`
+	tests := []struct {
+		name    string
+		script  string
+		content string // Of the beat returned
+		reason  string // Of a *PreCommitError
+		err     string // In any other error
+	}{
+		{name: "keep", script: "cat >/dev/null\n", content: "draft"},
+		{name: "rewrite", script: "sed 's/draft/final/'\n", content: "final"},
+		{name: "reject", script: "echo 'beats must cite a source' >&2\nexit 1\n", reason: "beats must cite a source"},
+		{name: "reject silently", script: "exit 2\n", reason: "exit status 2"},
+		{name: "invalid JSON", script: "echo 'not json'\n", err: "pre-commit hook returned invalid JSON"},
+		{name: "no content", script: `echo '{"content": ""}'` + "\n", err: "without content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := scriptStore(t, config, tt.script)
+			got, err := m.RunPreCommit(&beat.ProposedBeat{Content: "draft"})
+
+			var rejected *PreCommitError
+			switch {
+			case tt.reason != "":
+				if !errors.As(err, &rejected) || rejected.Reason != tt.reason {
+					t.Fatalf("err = %v, want a rejection with reason %q", err, tt.reason)
+				}
+			case tt.err != "":
+				if err == nil || errors.As(err, &rejected) || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case got.Content != tt.content:
+				t.Errorf("content = %q, want %q", got.Content, tt.content)
+			}
+		})
+	}
+}
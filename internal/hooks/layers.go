@@ -0,0 +1,158 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bierlingm/beats/internal/config"
+)
+
+// Where an effective hook setting comes from.
+const (
+	SourceGlobal = "global"
+	SourceStore  = "store"
+	SourceBoth   = "store over global"
+)
+
+// GlobalHooksPath is the hooks.json shared by every store, next to the
+// config file in the user config directory, or "" when there is none.
+func GlobalHooksPath() string {
+	p := config.Path()
+	if p == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(p), HooksConfigFile)
+}
+
+// readLayer reads one hooks.json as top-level sections; a missing file is
+// nil without an error.
+func readLayer(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	layer := map[string]interface{}{}
+	if err := json.Unmarshal(data, &layer); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return layer, nil
+}
+
+// mergeLayer lays over on top of base: objects are merged key by key, so a
+// store can turn off a global hook with {"enabled": false} and keep the
+// rest of its settings, while lists and other values are replaced whole.
+func mergeLayer(base, over map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(over))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		b, baseIsObject := merged[k].(map[string]interface{})
+		o, overIsObject := v.(map[string]interface{})
+		if baseIsObject && overIsObject {
+			merged[k] = mergeLayer(b, o)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// EffectiveHooks is the hooks configuration a store runs with: the global
+// hooks.json with the store's laid over it.
+type EffectiveHooks struct {
+	GlobalFile   string                 `json:"global_file,omitempty"`
+	GlobalExists bool                   `json:"global_exists"`
+	StoreFile    string                 `json:"store_file"`
+	StoreExists  bool                   `json:"store_exists"`
+	Config       map[string]interface{} `json:"config"`
+	Sources      map[string]string      `json:"sources"` // Section -> SourceGlobal, SourceStore or SourceBoth
+
+	// scriptDirs holds, by section, the directory a relative script path
+	// is resolved against: that of the layer the script was set in.
+	scriptDirs map[string]string
+}
+
+// Sections lists the configured sections in order.
+func (e *EffectiveHooks) Sections() []string {
+	var names []string
+	for name := range e.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadEffectiveHooks merges the global and store hooks.json. Either may be
+// missing; one that cannot be parsed is an error.
+func LoadEffectiveHooks(beatsDir string) (*EffectiveHooks, error) {
+	e := &EffectiveHooks{
+		GlobalFile: GlobalHooksPath(),
+		StoreFile:  filepath.Join(beatsDir, HooksConfigFile),
+		Sources:    map[string]string{},
+	}
+	global, err := readLayer(e.GlobalFile)
+	if err != nil {
+		return nil, err
+	}
+	store, err := readLayer(e.StoreFile)
+	if err != nil {
+		return nil, err
+	}
+	e.GlobalExists, e.StoreExists = global != nil, store != nil
+	e.Config = mergeLayer(global, store)
+	e.scriptDirs = map[string]string{}
+	for name := range e.Config {
+		if _, ok := sectionValue(store, name, "script"); ok {
+			e.scriptDirs[name] = beatsDir
+		} else if _, ok := sectionValue(global, name, "script"); ok {
+			e.scriptDirs[name] = filepath.Dir(e.GlobalFile)
+		}
+	}
+	for name := range e.Config {
+		_, inGlobal := global[name]
+		_, inStore := store[name]
+		switch {
+		case inGlobal && inStore:
+			e.Sources[name] = SourceBoth
+		case inGlobal:
+			e.Sources[name] = SourceGlobal
+		default:
+			e.Sources[name] = SourceStore
+		}
+	}
+	return e, nil
+}
+
+// sectionValue returns a key of a section in one layer.
+func sectionValue(layer map[string]interface{}, section, key string) (interface{}, bool) {
+	values, ok := layer[section].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := values[key]
+	return v, ok
+}
+
+// readHooksConfig returns the effective hooks.json of a store for the
+// section readers to decode. With neither file present it returns an
+// os.ErrNotExist error, so they fall back to their defaults.
+func readHooksConfig(beatsDir string) ([]byte, error) {
+	e, err := LoadEffectiveHooks(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	if !e.GlobalExists && !e.StoreExists {
+		return nil, &os.PathError{Op: "open", Path: e.StoreFile, Err: os.ErrNotExist}
+	}
+	return json.Marshal(e.Config)
+}
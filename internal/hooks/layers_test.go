@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGlobalHooksLayer(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+	global := `{"session_end": {"enabled": true, "extract": "conclusions", "min_messages": 7}, "quota": {"enabled": true}}`
+	if err := os.WriteFile(filepath.Join(configDir, HooksConfigFile), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bare := t.TempDir()
+	if hook := GetSessionEndConfig(bare); !hook.Enabled || hook.Extract != "conclusions" || hook.MinMessages != 7 {
+		t.Errorf("store without hooks.json: session_end = %+v, want the global one", hook)
+	}
+
+	store := t.TempDir()
+	if err := os.WriteFile(filepath.Join(store, HooksConfigFile), []byte(`{"session_end": {"enabled": false}, "secrets": {"action": "block"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hook := GetSessionEndConfig(store); hook.Enabled || hook.MinMessages != 7 {
+		t.Errorf("store override: session_end = %+v, want disabled with the global min_messages", hook)
+	}
+
+	e, err := LoadEffectiveHooks(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"session_end": SourceBoth, "quota": SourceGlobal, "secrets": SourceStore}
+	for name, source := range want {
+		if e.Sources[name] != source {
+			t.Errorf("source of %s = %q, want %q", name, e.Sources[name], source)
+		}
+	}
+
+	if err := SetGlobalHookEnabled("session_end", false); err != nil {
+		t.Fatal(err)
+	}
+	if hook := GetSessionEndConfig(bare); hook.Enabled || hook.Extract != "conclusions" {
+		t.Errorf("after disabling globally: session_end = %+v", hook)
+	}
+}
+
+func TestSetHookEnabledKeepsGlobalSettings(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+	global := `{"synthesis": {"enabled": false, "threshold": 10, "action": "script", "script": "synth.sh"}}`
+	if err := os.WriteFile(filepath.Join(configDir, HooksConfigFile), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := t.TempDir()
+	for _, enabled := range []bool{true, false, true} {
+		if err := SetHookEnabled(store, "synthesis", enabled); err != nil {
+			t.Fatal(err)
+		}
+		m, err := NewManager(store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.config.Synthesis; got.Enabled != enabled || got.Threshold != 10 || got.Action != "script" {
+			t.Errorf("after setting enabled=%v: synthesis = %+v, want the global threshold and action", enabled, got)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(store, HooksConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"synthesis\": {\n    \"enabled\": true\n  }\n}\n"; string(data) != want {
+		t.Errorf("store hooks.json = %s, want only the flag", data)
+	}
+}
@@ -0,0 +1,46 @@
+package hooks
+
+import (
+	"encoding/json"
+)
+
+// LinkSuggestionsHook configures bead link suggestions when a beat is committed.
+type LinkSuggestionsHook struct {
+	Enabled    bool    `json:"enabled"`
+	Threshold  float64 `json:"threshold"`   // Minimum similarity to suggest a bead
+	MaxResults int     `json:"max_results"` // Suggestions reported
+}
+
+// DefaultLinkSuggestionsHook returns the defaults used for unset fields.
+func DefaultLinkSuggestionsHook() LinkSuggestionsHook {
+	return LinkSuggestionsHook{
+		Enabled:    false,
+		Threshold:  0.75,
+		MaxResults: 3,
+	}
+}
+
+// GetLinkSuggestionsConfig reads the link_suggestions section of hooks.json, filling defaults.
+func GetLinkSuggestionsConfig(beatsDir string) LinkSuggestionsHook {
+	config := DefaultLinkSuggestionsHook()
+
+	data, err := readHooksConfig(beatsDir)
+	if err != nil {
+		return config
+	}
+	var fullConfig struct {
+		LinkSuggestions LinkSuggestionsHook `json:"link_suggestions"`
+	}
+	if err := json.Unmarshal(data, &fullConfig); err != nil {
+		return config
+	}
+
+	config.Enabled = fullConfig.LinkSuggestions.Enabled
+	if fullConfig.LinkSuggestions.Threshold > 0 {
+		config.Threshold = fullConfig.LinkSuggestions.Threshold
+	}
+	if fullConfig.LinkSuggestions.MaxResults > 0 {
+		config.MaxResults = fullConfig.LinkSuggestions.MaxResults
+	}
+	return config
+}
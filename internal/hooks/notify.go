@@ -0,0 +1,152 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notification events.
+const (
+	EventBeatAdded        = "beat_added"
+	EventBeatLinked       = "beat_linked"
+	EventSynthesisPending = "synthesis_pending"
+	EventBeadChanged      = "bead_changed"
+)
+
+// NotifyHook sends short notifications when hook events fire.
+type NotifyHook struct {
+	Enabled bool           `json:"enabled"`
+	Targets []NotifyTarget `json:"targets"`
+}
+
+// NotifyTarget is a single notification destination.
+type NotifyTarget struct {
+	Kind   string   `json:"kind"`             // "ntfy", "slack", or "desktop"
+	URL    string   `json:"url,omitempty"`    // ntfy topic URL or Slack incoming webhook URL
+	Events []string `json:"events,omitempty"` // Events to notify on (default: synthesis_pending)
+}
+
+// notifyTimeout bounds each delivery. Notifications are sent while a
+// capture waits, so a target that hangs must not hold it up for long.
+var notifyTimeout = 3 * time.Second
+
+var notifyClient = &http.Client{}
+
+// wants reports whether the target is subscribed to the event.
+func (t NotifyTarget) wants(event string) bool {
+	if len(t.Events) == 0 {
+		return event == EventSynthesisPending
+	}
+	for _, e := range t.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// notify delivers a message to every target subscribed to the event, all at
+// once. Each delivery is recorded in hooks.log, failures with their error;
+// they never block beat storage.
+func (m *Manager) notify(event, message string) {
+	if !m.config.Notify.Enabled {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, target := range m.config.Notify.Targets {
+		if !target.wants(event) {
+			continue
+		}
+		wg.Add(1)
+		go func(target NotifyTarget) {
+			defer wg.Done()
+			start := time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			err := sendNotification(ctx, target, message)
+			entry := HookLogEntry{
+				Time:       start.UTC(),
+				Hook:       "notify",
+				Script:     target.Kind + " " + event, // Not the URL: a Slack webhook URL is a secret
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				entry.Error, entry.ExitCode = err.Error(), -1
+			}
+			_ = appendHookLog(m.beatsDir, entry)
+		}(target)
+	}
+	wg.Wait()
+}
+
+func sendNotification(ctx context.Context, target NotifyTarget, message string) error {
+	switch target.Kind {
+	case "ntfy":
+		if target.URL == "" {
+			return fmt.Errorf("ntfy target requires url")
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", target.URL, strings.NewReader(message))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Title", "beats")
+		return doNotifyRequest(req)
+
+	case "slack":
+		if target.URL == "" {
+			return fmt.Errorf("slack target requires url")
+		}
+		body, _ := json.Marshal(map[string]string{"text": message})
+		req, err := http.NewRequestWithContext(ctx, "POST", target.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return doNotifyRequest(req)
+
+	case "desktop":
+		return desktopNotification(ctx, "beats", message)
+
+	default:
+		return fmt.Errorf("unknown notify kind %q (use ntfy, slack, desktop)", target.Kind)
+	}
+}
+
+func doNotifyRequest(req *http.Request) error {
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", notifyTimeout)
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func desktopNotification(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", title, message)
+	default:
+		return fmt.Errorf("desktop notifications not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,155 @@
+package hooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// notifyStore returns a manager whose notify hook sends every event to
+// targets.
+func notifyStore(t *testing.T, targets ...NotifyTarget) *Manager {
+	t.Helper()
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	for i := range targets {
+		targets[i].Events = []string{"*"}
+	}
+	data, err := json.Marshal(map[string]NotifyHook{"notify": {Enabled: true, Targets: targets}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, HooksConfigFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestNotifyTargets(t *testing.T) {
+	type received struct {
+		contentType, title, body string
+	}
+	got := make(chan received, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{r.Header.Get("Content-Type"), r.Header.Get("Title"), string(body)}
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		kind string
+		want received
+	}{
+		{"ntfy", received{title: "beats", body: "Captured beat-1"}},
+		{"slack", received{contentType: "application/json", body: `{"text":"Captured beat-1"}`}},
+	} {
+		t.Run(tt.kind, func(t *testing.T) {
+			m := notifyStore(t, NotifyTarget{Kind: tt.kind, URL: server.URL})
+			m.notify(EventBeatAdded, "Captured beat-1")
+			select {
+			case r := <-got:
+				if r.contentType != tt.want.contentType || r.title != tt.want.title || r.body != tt.want.body {
+					t.Errorf("received %+v, want %+v", r, tt.want)
+				}
+			default:
+				t.Fatal("nothing delivered")
+			}
+			entries, _ := ReadHookLog(m.beatsDir)
+			if len(entries) != 1 || entries[0].Hook != "notify" || entries[0].Error != "" || strings.Contains(entries[0].Script, server.URL) {
+				t.Errorf("hooks.log = %+v, want one successful delivery without the URL", entries)
+			}
+		})
+	}
+}
+
+func TestNotifyDesktop(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses a fake notify-send")
+	}
+	bin := t.TempDir()
+	out := filepath.Join(bin, "args")
+	script := "#!/bin/sh\nprintf '%s|' \"$@\" > " + out + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "notify-send"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	m := notifyStore(t, NotifyTarget{Kind: "desktop"})
+	m.notify(EventSynthesisPending, "Synthesis pending")
+	if args, err := os.ReadFile(out); err != nil || string(args) != "beats|Synthesis pending|" {
+		t.Errorf("notify-send got %q, %v", args, err)
+	}
+}
+
+func TestNotifyFailuresLogged(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hanging.Close()
+	defer close(release)
+	defer func(d time.Duration) { notifyTimeout = d }(notifyTimeout)
+	notifyTimeout = 200 * time.Millisecond
+
+	m := notifyStore(t,
+		NotifyTarget{Kind: "ntfy", URL: failing.URL},
+		NotifyTarget{Kind: "slack", URL: hanging.URL},
+		NotifyTarget{Kind: "ntfy"},
+	)
+	start := time.Now()
+	m.notify(EventBeatAdded, "Captured beat-1")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("notify took %s; targets should be sent at once and time out", elapsed)
+	}
+
+	entries, err := ReadHookLog(m.beatsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := map[string]bool{}
+	for _, e := range entries {
+		errs[e.Error] = true
+		if e.Hook != "notify" || e.ExitCode != -1 {
+			t.Errorf("log entry %+v", e)
+		}
+	}
+	for _, want := range []string{"returned status 503", "timed out after 200ms", "ntfy target requires url"} {
+		if !errs[want] {
+			t.Errorf("hooks.log errors %v, missing %q", errs, want)
+		}
+	}
+}
+
+func TestCountTodayTimezone(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_TZ", "America/New_York")
+	clock.Set(time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)) // 22:00 on the 9th in New York
+	defer clock.Reset()
+
+	beats := []beat.Beat{
+		{CreatedAt: time.Date(2026, 3, 9, 15, 0, 0, 0, time.UTC)}, // The 9th in New York
+		{CreatedAt: time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)}, // Still the 9th there
+		{CreatedAt: time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)}, // The 8th there
+	}
+	if n := countToday(beats); n != 2 {
+		t.Errorf("countToday = %d, want 2 for the New York day", n)
+	}
+}
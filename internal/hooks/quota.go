@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"encoding/json"
+)
+
+// QuotaHook holds automated captures (those with a source in impetus.meta)
+// for review when their source is over its daily cap or they look like
+// noise, so hooks, watchers and bots cannot flood the store.
+type QuotaHook struct {
+	Enabled        bool           `json:"enabled"`
+	Daily          map[string]int `json:"daily"`           // Captures per day by source; "*" for sources not listed
+	NoiseThreshold int            `json:"noise_threshold"` // Noise score at which a capture is held
+	MinWords       int            `json:"min_words"`       // Captures with fewer words score as noise
+	NoisePatterns  []string       `json:"noise_patterns"`  // Regexes; content matching one scores as noise
+}
+
+// DefaultQuotaHook returns the defaults used for unset fields.
+func DefaultQuotaHook() QuotaHook {
+	return QuotaHook{
+		Enabled:        false,
+		Daily:          map[string]int{},
+		NoiseThreshold: 2,
+		MinWords:       3,
+		NoisePatterns:  []string{},
+	}
+}
+
+// GetQuotaConfig reads the quota section of hooks.json, filling defaults.
+func GetQuotaConfig(beatsDir string) QuotaHook {
+	config := DefaultQuotaHook()
+
+	data, err := readHooksConfig(beatsDir)
+	if err != nil {
+		return config
+	}
+	var fullConfig struct {
+		Quota QuotaHook `json:"quota"`
+	}
+	if err := json.Unmarshal(data, &fullConfig); err != nil {
+		return config
+	}
+
+	quota := fullConfig.Quota
+	config.Enabled = quota.Enabled
+	if quota.Daily != nil {
+		config.Daily = quota.Daily
+	}
+	if quota.NoiseThreshold > 0 {
+		config.NoiseThreshold = quota.NoiseThreshold
+	}
+	if quota.MinWords > 0 {
+		config.MinWords = quota.MinWords
+	}
+	if quota.NoisePatterns != nil {
+		config.NoisePatterns = quota.NoisePatterns
+	}
+	return config
+}
+
+// Limit returns the daily cap for a source, or 0 for none.
+func (q QuotaHook) Limit(source string) int {
+	if n, ok := q.Daily[source]; ok {
+		return n
+	}
+	return q.Daily["*"]
+}
@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"encoding/json"
+)
+
+// SecretsHook configures the scan for credentials and personal data that
+// runs on every beat before it is stored. Unlike the other hooks it is on
+// unless disabled.
+type SecretsHook struct {
+	Enabled  bool              `json:"enabled"`
+	Action   string            `json:"action"`   // "warn" (default), "block" or "redact"
+	Patterns map[string]string `json:"patterns"` // Extra regexes by kind, e.g. {"ticket": "ACME-\\d+"}
+	Ignore   []string          `json:"ignore"`   // Built-in kinds not to scan for, e.g. ["email", "phone"]
+}
+
+// DefaultSecretsHook returns the defaults used for unset fields.
+func DefaultSecretsHook() SecretsHook {
+	return SecretsHook{
+		Enabled:  true,
+		Action:   "warn",
+		Patterns: map[string]string{},
+		Ignore:   []string{},
+	}
+}
+
+// GetSecretsConfig reads the secrets section of hooks.json, filling defaults.
+func GetSecretsConfig(beatsDir string) SecretsHook {
+	config := DefaultSecretsHook()
+
+	data, err := readHooksConfig(beatsDir)
+	if err != nil {
+		return config
+	}
+	var fullConfig struct {
+		Secrets struct {
+			Enabled  *bool             `json:"enabled"`
+			Action   string            `json:"action"`
+			Patterns map[string]string `json:"patterns"`
+			Ignore   []string          `json:"ignore"`
+		} `json:"secrets"`
+	}
+	if err := json.Unmarshal(data, &fullConfig); err != nil {
+		return config
+	}
+
+	secrets := fullConfig.Secrets
+	if secrets.Enabled != nil {
+		config.Enabled = *secrets.Enabled
+	}
+	if secrets.Action != "" {
+		config.Action = secrets.Action
+	}
+	if secrets.Patterns != nil {
+		config.Patterns = secrets.Patterns
+	}
+	if secrets.Ignore != nil {
+		config.Ignore = secrets.Ignore
+	}
+	return config
+}
@@ -2,16 +2,21 @@ package hooks
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/llm"
+	"github.com/bierlingm/beats/internal/redact"
 )
 
 // SessionEndHook configures session-end beat creation
@@ -22,45 +27,117 @@ type SessionEndHook struct {
 	MinMessages   int    `json:"min_messages"`
 	MaxContentLen int    `json:"max_content_len"`
 	ProcessedFile string `json:"processed_file"`
+	SessionsDir   string `json:"sessions_dir"`
+	Extract       string `json:"extract"` // user (default), conclusions or full
 }
 
+// What of a session is sent to the LLM to summarize.
+const (
+	ExtractUser        = "user"        // The user's messages
+	ExtractConclusions = "conclusions" // Also the assistant's last replies
+	ExtractFull        = "full"        // Also every tool call and its result
+)
+
+// Limits on what is extracted from a session, in bytes or items.
+const (
+	userMessageLen    = 200
+	conclusionLen     = 400
+	maxConclusions    = 5
+	toolInputLen      = 120
+	toolResultLen     = 200
+	maxToolCalls      = 30
+	extractedTotalLen = 12000
+)
+
+// ErrSessionTooShort is returned for sessions below MinMessages user messages.
+var ErrSessionTooShort = errors.New("session too short")
+
 // DefaultSessionEndHook returns sensible defaults
 func DefaultSessionEndHook() SessionEndHook {
+	cfg := config.Get()
 	return SessionEndHook{
 		Enabled:       true,
-		OllamaModel:   "mistral:latest",
-		OllamaURL:     "http://localhost:11434",
+		OllamaModel:   cfg.LLMModel,
+		OllamaURL:     cfg.OllamaURL,
 		MinMessages:   5,
 		MaxContentLen: 500,
-		ProcessedFile: filepath.Join(os.Getenv("HOME"), ".factory/.processed-session-beats"),
+		ProcessedFile: filepath.Join(factoryDir(), ".processed-session-beats"),
+		SessionsDir:   filepath.Join(factoryDir(), "sessions"),
+		Extract:       ExtractUser,
+	}
+}
+
+// factoryDir is the Factory/Droid data directory, ~/.factory.
+func factoryDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".factory"
 	}
+	return filepath.Join(home, ".factory")
 }
 
 // FactorySession represents a Factory/Droid session file
 type FactorySession struct {
-	ID       string
-	Title    string
-	FilePath string
-	Messages []SessionMessage
+	ID        string
+	Title     string
+	FilePath  string
+	Workspace string           // Session subdirectory (the encoded working directory), if any
+	Messages  []SessionMessage // User messages, including tool results
+	Replies   []SessionMessage // Assistant messages, including tool calls
+}
+
+// EncodeWorkspace returns the session subdirectory Factory uses for a
+// working directory: the path with separators replaced by dashes.
+func EncodeWorkspace(dir string) string {
+	return strings.TrimPrefix(strings.ReplaceAll(dir, "/", "-"), "-")
 }
 
 // SessionMessage represents a message from a Factory session
 type SessionMessage struct {
 	Type    string `json:"type"`
 	Message struct {
-		Role    string `json:"role"`
-		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		} `json:"content"`
+		Role    string         `json:"role"`
+		Content []SessionBlock `json:"content"`
 	} `json:"message"`
 }
 
+// SessionBlock is one part of a message: text, a tool call (tool_use) or a
+// tool's result (tool_result).
+type SessionBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`    // Of a tool call
+	Name      string          `json:"name,omitempty"`  // Tool called
+	Input     json.RawMessage `json:"input,omitempty"` // Tool arguments
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"` // Tool result: a string or text blocks
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// resultText returns a tool result's text.
+func (b SessionBlock) resultText() string {
+	var text string
+	if json.Unmarshal(b.Content, &text) == nil {
+		return text
+	}
+	var blocks []SessionBlock
+	if json.Unmarshal(b.Content, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, c := range blocks {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 // SessionEndRunner handles session-end beat creation
 type SessionEndRunner struct {
-	config     SessionEndHook
-	beatsDir   string
-	httpClient *http.Client
+	config   SessionEndHook
+	beatsDir string
+	mu       sync.Mutex // Guards the processed file
 }
 
 // NewSessionEndRunner creates a new runner
@@ -68,9 +145,6 @@ func NewSessionEndRunner(beatsDir string, config SessionEndHook) *SessionEndRunn
 	return &SessionEndRunner{
 		config:   config,
 		beatsDir: beatsDir,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
 	}
 }
 
@@ -85,7 +159,7 @@ func (r *SessionEndRunner) Run() error {
 		return fmt.Errorf("finding session: %w", err)
 	}
 
-	if r.isProcessed(session.ID) {
+	if r.IsProcessed(session.ID) {
 		fmt.Printf("Session %s already processed\n", session.ID)
 		return nil
 	}
@@ -95,56 +169,109 @@ func (r *SessionEndRunner) Run() error {
 		return nil
 	}
 
-	content := r.extractContent(session)
-	if content == "" {
-		return fmt.Errorf("no content extracted from session")
-	}
-
-	summary, err := r.generateSummary(content)
+	summary, err := r.Summarize(session)
 	if err != nil {
-		return fmt.Errorf("generating summary: %w", err)
+		return err
 	}
 
-	if summary == "" {
-		return fmt.Errorf("empty summary generated")
-	}
-
-	b := &beat.Beat{
-		ID:        beat.GenerateIDWithSequence(time.Now().UTC(), 1),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		SessionID: session.ID,
-		Impetus: beat.Impetus{
-			Label: "Session",
-			Meta: map[string]string{
-				"session_id": session.ID,
-				"title":      session.Title,
-			},
+	impetus := beat.Impetus{
+		Label: "Session",
+		Meta: map[string]string{
+			"session_id":     session.ID,
+			"title":          session.Title,
+			beat.ChannelMeta: beat.ChannelSession,
 		},
-		Content:     summary,
-		References:  []beat.Reference{},
-		Entities:    []beat.Entity{},
-		LinkedBeads: []string{},
 	}
-
-	// Write directly to JSONL to avoid import cycle
-	if err := r.appendBeat(b); err != nil {
+	var b *beat.Beat
+	if CaptureBeat != nil {
+		// Through the capture pipeline, like every other capture
+		b, err = CaptureBeat(r.beatsDir, &beat.ProposedBeat{Content: summary, Impetus: impetus})
+	} else {
+		b = &beat.Beat{
+			ID:          beat.GenerateIDWithSequence(clock.Now().UTC(), 1),
+			CreatedAt:   clock.Now().UTC(),
+			UpdatedAt:   clock.Now().UTC(),
+			SessionID:   session.ID,
+			Impetus:     impetus,
+			Content:     summary,
+			References:  []beat.Reference{},
+			Entities:    []beat.Entity{},
+			LinkedBeads: beat.BeadLinks{},
+		}
+		err = r.appendBeat(b)
+	}
+	if err != nil {
 		return fmt.Errorf("saving beat: %w", err)
 	}
 
-	r.markProcessed(session.ID)
+	r.MarkProcessed(session.ID)
 
 	fmt.Printf("Created beat %s from session: %s\n", b.ID, session.Title)
 	return nil
 }
 
+// Summarize extracts a session's user messages and condenses them with the
+// configured Ollama model. Sessions below MinMessages yield ErrSessionTooShort.
+func (r *SessionEndRunner) Summarize(session *FactorySession) (string, error) {
+	if len(session.Messages) < r.config.MinMessages {
+		return "", fmt.Errorf("%w: %d messages (min: %d)", ErrSessionTooShort, len(session.Messages), r.config.MinMessages)
+	}
+
+	content, err := r.extractContent(session)
+	if err != nil {
+		return "", err
+	}
+	if content == "" {
+		return "", fmt.Errorf("no content extracted from session")
+	}
+
+	summary, err := r.generateSummary(content)
+	if err != nil {
+		return "", fmt.Errorf("generating summary: %w", err)
+	}
+	if summary == "" {
+		return "", fmt.Errorf("empty summary generated")
+	}
+	return summary, nil
+}
+
+// SessionFiles lists every session file in the sessions directory and its
+// per-workspace subdirectories.
+func (r *SessionEndRunner) SessionFiles() ([]string, error) {
+	root := r.config.SessionsDir
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading sessions directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".jsonl") && !e.IsDir() {
+			files = append(files, filepath.Join(root, e.Name()))
+			continue
+		}
+		if !e.IsDir() {
+			continue
+		}
+		sub, err := os.ReadDir(filepath.Join(root, e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, f := range sub {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), ".jsonl") {
+				files = append(files, filepath.Join(root, e.Name(), f.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
 func (r *SessionEndRunner) findCurrentSession() (*FactorySession, error) {
-	sessionsDir := filepath.Join(os.Getenv("HOME"), ".factory/sessions")
+	sessionsDir := r.config.SessionsDir
 
 	// Get CWD-specific session directory
 	cwd, _ := os.Getwd()
-	cwdEncoded := strings.TrimPrefix(strings.ReplaceAll(cwd, "/", "-"), "-")
-	sessionDir := filepath.Join(sessionsDir, cwdEncoded)
+	sessionDir := filepath.Join(sessionsDir, EncodeWorkspace(cwd))
 
 	if _, err := os.Stat(sessionDir); os.IsNotExist(err) {
 		sessionDir = sessionsDir
@@ -176,10 +303,12 @@ func (r *SessionEndRunner) findCurrentSession() (*FactorySession, error) {
 		return nil, fmt.Errorf("no session files found in %s", sessionDir)
 	}
 
-	return r.parseSession(newest)
+	return r.ParseSession(newest)
 }
 
-func (r *SessionEndRunner) parseSession(path string) (*FactorySession, error) {
+// ParseSession reads a session file's title, user messages and assistant
+// replies.
+func (r *SessionEndRunner) ParseSession(path string) (*FactorySession, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -190,6 +319,9 @@ func (r *SessionEndRunner) parseSession(path string) (*FactorySession, error) {
 		ID:       strings.TrimSuffix(filepath.Base(path), ".jsonl"),
 		FilePath: path,
 	}
+	if dir := filepath.Dir(path); filepath.Clean(dir) != filepath.Clean(r.config.SessionsDir) {
+		session.Workspace = filepath.Base(dir)
+	}
 
 	scanner := bufio.NewScanner(file)
 	buf := make([]byte, 1024*1024)
@@ -213,8 +345,14 @@ func (r *SessionEndRunner) parseSession(path string) (*FactorySession, error) {
 			continue
 		}
 
-		if msg.Type == "message" && msg.Message.Role == "user" {
+		if msg.Type != "message" {
+			continue
+		}
+		switch msg.Message.Role {
+		case "user":
 			session.Messages = append(session.Messages, msg)
+		case "assistant":
+			session.Replies = append(session.Replies, msg)
 		}
 	}
 
@@ -225,7 +363,19 @@ func (r *SessionEndRunner) parseSession(path string) (*FactorySession, error) {
 	return session, scanner.Err()
 }
 
-func (r *SessionEndRunner) extractContent(session *FactorySession) string {
+// extractContent lays out what the summary is made from: the user's
+// messages, and depending on the extract strategy the assistant's last
+// replies and the tool calls made, each truncated.
+func (r *SessionEndRunner) extractContent(session *FactorySession) (string, error) {
+	strategy := r.config.Extract
+	switch strategy {
+	case "":
+		strategy = ExtractUser
+	case ExtractUser, ExtractConclusions, ExtractFull:
+	default:
+		return "", fmt.Errorf("unknown session_end extract %q (user, conclusions or full)", strategy)
+	}
+
 	var parts []string
 
 	parts = append(parts, fmt.Sprintf("Session: %s", session.Title))
@@ -246,15 +396,77 @@ func (r *SessionEndRunner) extractContent(session *FactorySession) string {
 			if len(text) < 5 {
 				continue
 			}
-			// Truncate very long messages
-			if len(text) > 200 {
-				text = text[:200] + "..."
+			parts = append(parts, "- "+clip(text, userMessageLen))
+		}
+	}
+
+	if strategy == ExtractConclusions || strategy == ExtractFull {
+		var replies []string
+		for _, msg := range session.Replies {
+			for _, content := range msg.Message.Content {
+				if text := strings.TrimSpace(content.Text); content.Type == "text" && len(text) >= 5 {
+					replies = append(replies, "- "+clip(text, conclusionLen))
+				}
 			}
-			parts = append(parts, "- "+text)
+		}
+		if len(replies) > 0 {
+			// The last replies are where the assistant says what was done
+			parts = append(parts, "", "Assistant conclusions:")
+			parts = append(parts, replies[max(len(replies)-maxConclusions, 0):]...)
 		}
 	}
 
-	return strings.Join(parts, "\n")
+	if strategy == ExtractFull {
+		if calls := toolCalls(session); len(calls) > 0 {
+			parts = append(parts, "", "Tool calls:")
+			parts = append(parts, calls[max(len(calls)-maxToolCalls, 0):]...)
+		}
+	}
+
+	return clip(strings.Join(parts, "\n"), extractedTotalLen), nil
+}
+
+// toolCalls lists a session's tool calls in order, each with its result.
+func toolCalls(session *FactorySession) []string {
+	results := make(map[string]SessionBlock)
+	for _, msg := range session.Messages {
+		for _, content := range msg.Message.Content {
+			if content.Type == "tool_result" {
+				results[content.ToolUseID] = content
+			}
+		}
+	}
+	var calls []string
+	for _, msg := range session.Replies {
+		for _, content := range msg.Message.Content {
+			if content.Type != "tool_use" {
+				continue
+			}
+			line := fmt.Sprintf("- %s %s", content.Name, clip(oneLine(string(content.Input)), toolInputLen))
+			if res, ok := results[content.ID]; ok {
+				outcome := "->"
+				if res.IsError {
+					outcome = "-> error:"
+				}
+				line += fmt.Sprintf(" %s %s", outcome, clip(oneLine(res.resultText()), toolResultLen))
+			}
+			calls = append(calls, line)
+		}
+	}
+	return calls
+}
+
+// clip truncates s to n bytes, marking the cut.
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// oneLine collapses whitespace so a tool's input or output fits one line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 func (r *SessionEndRunner) generateSummary(content string) (string, error) {
@@ -262,60 +474,58 @@ func (r *SessionEndRunner) generateSummary(content string) (string, error) {
 
 %s`, content)
 
-	reqBody := map[string]interface{}{
-		"model":  r.config.OllamaModel,
-		"prompt": prompt,
-		"stream": false,
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
+	summary, err := r.config.Generate(prompt)
 	if err != nil {
 		return "", err
 	}
-
-	resp, err := r.httpClient.Post(
-		r.config.OllamaURL+"/api/generate",
-		"application/json",
-		strings.NewReader(string(jsonBody)),
-	)
-	if err != nil {
-		return "", fmt.Errorf("ollama request failed: %w", err)
+	if len(summary) > r.config.MaxContentLen {
+		summary = summary[:r.config.MaxContentLen]
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned status %d", resp.StatusCode)
-	}
+	return summary, nil
+}
+
+// Generate sends a prompt to the configured Ollama model and returns the
+// trimmed response. It is the LLM other features use for summaries too.
+func (h SessionEndHook) Generate(prompt string) (string, error) {
+	return h.GenerateContext(context.Background(), prompt)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GenerateContext is Generate under a context that can cancel the request.
+func (h SessionEndHook) GenerateContext(ctx context.Context, prompt string) (string, error) {
+	// A remote server gets the prompt through the ollama redaction profile.
+	// Entity names are stripped by callers that know the beats involved.
+	r, err := redact.ForOllama(h.OllamaURL, nil)
 	if err != nil {
 		return "", err
 	}
-
-	var result struct {
-		Response string `json:"response"`
-	}
-	if err := json.Unmarshal(body, &result); err != nil {
+	response, err := llm.New(h.OllamaURL).Generate(ctx, h.OllamaModel, r.Text(prompt))
+	if err != nil {
 		return "", err
 	}
-
-	summary := strings.TrimSpace(result.Response)
-	if len(summary) > r.config.MaxContentLen {
-		summary = summary[:r.config.MaxContentLen]
-	}
-
-	return summary, nil
+	return strings.TrimSpace(response), nil
 }
 
-func (r *SessionEndRunner) isProcessed(sessionID string) bool {
+// IsProcessed reports whether a beat was already created for the session.
+func (r *SessionEndRunner) IsProcessed(sessionID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	data, err := os.ReadFile(r.config.ProcessedFile)
 	if err != nil {
 		return false
 	}
-	return strings.Contains(string(data), sessionID)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == sessionID {
+			return true
+		}
+	}
+	return false
 }
 
-func (r *SessionEndRunner) markProcessed(sessionID string) {
+// MarkProcessed records the session so it is not summarized again.
+func (r *SessionEndRunner) MarkProcessed(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	dir := filepath.Dir(r.config.ProcessedFile)
 	_ = os.MkdirAll(dir, 0755)
 
@@ -327,8 +537,21 @@ func (r *SessionEndRunner) markProcessed(sessionID string) {
 	_, _ = f.WriteString(sessionID + "\n")
 }
 
-// appendBeat writes a beat directly to the JSONL file (avoids import cycle with store)
+// CaptureBeat stores a proposed beat in beatsDir through the capture
+// pipeline. The cli package sets it; without it, AppendBeat is used.
+var CaptureBeat func(beatsDir string, p *beat.ProposedBeat) (*beat.Beat, error)
+
+// AppendBeat writes a beat to the store in beatsDir. The store package sets
+// it, so session beats are journaled and go through a running daemon like
+// any other write; it is a variable to avoid an import cycle with store.
+var AppendBeat func(beatsDir string, b *beat.Beat) error
+
+// appendBeat writes a beat with AppendBeat, or straight to the JSONL file
+// when no store is linked in.
 func (r *SessionEndRunner) appendBeat(b *beat.Beat) error {
+	if AppendBeat != nil {
+		return AppendBeat(r.beatsDir, b)
+	}
 	beatsFile := filepath.Join(r.beatsDir, "beats.jsonl")
 
 	// Ensure directory exists
@@ -353,8 +576,7 @@ func (r *SessionEndRunner) appendBeat(b *beat.Beat) error {
 
 // GetSessionEndConfig reads config or returns defaults
 func GetSessionEndConfig(beatsDir string) SessionEndHook {
-	path := filepath.Join(beatsDir, HooksConfigFile)
-	data, err := os.ReadFile(path)
+	data, err := readHooksConfig(beatsDir)
 	if err != nil {
 		return DefaultSessionEndHook()
 	}
@@ -366,39 +588,63 @@ func GetSessionEndConfig(beatsDir string) SessionEndHook {
 		return DefaultSessionEndHook()
 	}
 
-	config := fullConfig.SessionEnd
-	if config.OllamaModel == "" {
-		config.OllamaModel = DefaultSessionEndHook().OllamaModel
+	hook := fullConfig.SessionEnd
+	if hook.OllamaModel == "" {
+		hook.OllamaModel = DefaultSessionEndHook().OllamaModel
+	}
+	if hook.OllamaURL == "" {
+		hook.OllamaURL = DefaultSessionEndHook().OllamaURL
+	}
+	if hook.MinMessages == 0 {
+		hook.MinMessages = DefaultSessionEndHook().MinMessages
 	}
-	if config.OllamaURL == "" {
-		config.OllamaURL = DefaultSessionEndHook().OllamaURL
+	if hook.MaxContentLen == 0 {
+		hook.MaxContentLen = DefaultSessionEndHook().MaxContentLen
 	}
-	if config.MinMessages == 0 {
-		config.MinMessages = DefaultSessionEndHook().MinMessages
+	if hook.ProcessedFile == "" {
+		hook.ProcessedFile = DefaultSessionEndHook().ProcessedFile
 	}
-	if config.MaxContentLen == 0 {
-		config.MaxContentLen = DefaultSessionEndHook().MaxContentLen
+	if hook.SessionsDir == "" {
+		hook.SessionsDir = DefaultSessionEndHook().SessionsDir
 	}
-	if config.ProcessedFile == "" {
-		config.ProcessedFile = DefaultSessionEndHook().ProcessedFile
+	if hook.Extract == "" {
+		hook.Extract = ExtractUser
 	}
+	hook.ProcessedFile = config.ExpandHome(hook.ProcessedFile)
+	hook.SessionsDir = config.ExpandHome(hook.SessionsDir)
 
-	return config
+	return hook
 }
 
 // ShowConfig displays current hooks configuration
 func ShowConfig(beatsDir string) error {
 	config := struct {
-		Synthesis  SynthesisHook  `json:"synthesis"`
-		SessionEnd SessionEndHook `json:"session_end"`
+		Synthesis  SynthesisHook       `json:"synthesis"`
+		PreCommit  PreCommitHook       `json:"pre_commit"`
+		Notify     NotifyHook          `json:"notify"`
+		Scripts    ScriptsConfig       `json:"scripts"`
+		Duplicates DuplicatesHook      `json:"duplicates"`
+		Links      LinkSuggestionsHook `json:"link_suggestions"`
+		SessionEnd SessionEndHook      `json:"session_end"`
+		Secrets    SecretsHook         `json:"secrets"`
+		Quota      QuotaHook           `json:"quota"`
+		Approval   ApprovalHook        `json:"approval"`
 	}{
+		Duplicates: GetDuplicatesConfig(beatsDir),
+		Links:      GetLinkSuggestionsConfig(beatsDir),
 		SessionEnd: GetSessionEndConfig(beatsDir),
+		Secrets:    GetSecretsConfig(beatsDir),
+		Quota:      GetQuotaConfig(beatsDir),
+		Approval:   GetApprovalConfig(beatsDir),
 	}
 
 	// Load synthesis config
 	mgr, err := NewManager(beatsDir)
 	if err == nil {
 		config.Synthesis = mgr.config.Synthesis
+		config.PreCommit = mgr.config.PreCommit
+		config.Notify = mgr.config.Notify
+		config.Scripts = mgr.config.Scripts
 	}
 
 	configJSON, err := json.MarshalIndent(config, "", "  ")
@@ -409,5 +655,10 @@ func ShowConfig(beatsDir string) error {
 	fmt.Println("Current hooks configuration:")
 	fmt.Println(string(configJSON))
 	fmt.Printf("\nConfig file: %s/%s\n", beatsDir, HooksConfigFile)
+	if global := GlobalHooksPath(); global != "" {
+		if _, err := os.Stat(global); err == nil {
+			fmt.Printf("Global config: %s (the store's settings win)\n", global)
+		}
+	}
 	return nil
 }
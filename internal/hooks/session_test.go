@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSession = `{"title":"Fix flaky sync"}
+{"type":"message","message":{"role":"user","content":[{"type":"text","text":"The nightly sync fails about once a week"}]}}
+{"type":"message","message":{"role":"assistant","content":[{"type":"text","text":"Let me look at the sync code."},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./internal/store"}}]}}
+{"type":"message","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":[{"type":"text","text":"--- FAIL: TestSync\n    lock held"}]}]}}
+{"type":"message","message":{"role":"assistant","content":[{"type":"text","text":"Fixed: the lock is now released before the index rebuild."}]}}
+`
+
+func TestExtractContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s1.jsonl")
+	if err := os.WriteFile(path, []byte(testSession), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewSessionEndRunner(dir, SessionEndHook{SessionsDir: dir})
+	session, err := r.ParseSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Messages) != 2 || len(session.Replies) != 2 {
+		t.Fatalf("parsed %d messages and %d replies; want 2 and 2", len(session.Messages), len(session.Replies))
+	}
+
+	for _, tt := range []struct {
+		extract     string
+		want, never []string
+	}{
+		{"", []string{"nightly sync fails"}, []string{"Fixed:", "Bash"}},
+		{ExtractConclusions, []string{"nightly sync fails", "Fixed: the lock"}, []string{"Bash"}},
+		{ExtractFull, []string{"Fixed: the lock", `- Bash {"command":"go test ./internal/store"} -> error: --- FAIL: TestSync lock held`}, nil},
+	} {
+		r.config.Extract = tt.extract
+		content, err := r.extractContent(session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(content, w) {
+				t.Errorf("extract %q: missing %q in\n%s", tt.extract, w, content)
+			}
+		}
+		for _, n := range tt.never {
+			if strings.Contains(content, n) {
+				t.Errorf("extract %q: unexpected %q in\n%s", tt.extract, n, content)
+			}
+		}
+	}
+
+	r.config.Extract = "everything"
+	if _, err := r.extractContent(session); err == nil {
+		t.Error("unknown strategy accepted")
+	}
+}
@@ -0,0 +1,189 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HookNames lists the hooks that can be enabled or disabled by name.
+var HookNames = []string{"synthesis", "pre_commit", "notify", "duplicates", "link_suggestions", "session_end", "on_bead_changed", "on_event", "secrets", "quota", "approval"}
+
+// normalizeHookName accepts both "pre_commit" and "pre-commit" spellings.
+func normalizeHookName(name string) (string, error) {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	for _, n := range HookNames {
+		if n == key {
+			return n, nil
+		}
+	}
+	return "", fmt.Errorf("unknown hook %q (use: %s)", name, strings.Join(HookNames, ", "))
+}
+
+// SetHookEnabled flips the "enabled" flag of a hook in the store's
+// hooks.json. Other keys, including ones this version does not know about,
+// are preserved.
+func SetHookEnabled(beatsDir, name string, enabled bool) error {
+	return setHookEnabled(filepath.Join(beatsDir, HooksConfigFile), name, enabled)
+}
+
+// SetGlobalHookEnabled flips the "enabled" flag of a hook in the global
+// hooks.json, for every store that does not set it itself.
+func SetGlobalHookEnabled(name string, enabled bool) error {
+	path := GlobalHooksPath()
+	if path == "" {
+		return fmt.Errorf("no user config directory for the global %s", HooksConfigFile)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return setHookEnabled(path, name, enabled)
+}
+
+func setHookEnabled(path, name string, enabled bool) error {
+	key, err := normalizeHookName(name)
+	if err != nil {
+		return err
+	}
+
+	raw := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("invalid %s: %w", HooksConfigFile, err)
+		}
+	}
+
+	section := map[string]interface{}{}
+	if existing, ok := raw[key]; ok {
+		if err := json.Unmarshal(existing, &section); err != nil {
+			return fmt.Errorf("invalid %q section in %s: %w", key, HooksConfigFile, err)
+		}
+	}
+	// Only the flag is written: defaults here would override the settings a
+	// lower layer (the global hooks.json) gives the hook.
+	section["enabled"] = enabled
+
+	encoded, err := json.Marshal(section)
+	if err != nil {
+		return err
+	}
+	raw[key] = encoded
+
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a concurrent reader never sees a half-written config
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(out, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// HookInfo summarizes a single hook for status output.
+type HookInfo struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Status is the current hook configuration and synthesis counter.
+type Status struct {
+	Hooks             []HookInfo        `json:"hooks"`
+	Threshold         int               `json:"threshold"`
+	BeatsSinceLast    int               `json:"beats_since_last"`
+	BeatsUntilTrigger int               `json:"beats_until_trigger"`
+	TotalBeats        int               `json:"total_beats"`
+	LastSynthesisAt   *time.Time        `json:"last_synthesis_at,omitempty"`
+	PendingSynthesis  *SynthesisRequest `json:"pending_synthesis,omitempty"`
+	ConfigFile        string            `json:"config_file"`
+	ConfigFileExists  bool              `json:"config_file_exists"`
+	GlobalFile        string            `json:"global_file,omitempty"` // Shared hooks.json the store's is laid over
+	GlobalFileExists  bool              `json:"global_file_exists"`
+}
+
+// GetStatus reports which hooks are enabled and how close synthesis is to triggering.
+func GetStatus(beatsDir string) (*Status, error) {
+	m, err := NewManager(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath := filepath.Join(beatsDir, HooksConfigFile)
+	_, statErr := os.Stat(configPath)
+
+	threshold := m.config.Synthesis.Threshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+
+	action := m.config.Synthesis.Action
+	if action == "" {
+		action = "file"
+	}
+	sessionEnd := GetSessionEndConfig(beatsDir)
+	duplicates := GetDuplicatesConfig(beatsDir)
+	links := GetLinkSuggestionsConfig(beatsDir)
+	secrets := GetSecretsConfig(beatsDir)
+	quota := GetQuotaConfig(beatsDir)
+	approval := GetApprovalConfig(beatsDir)
+
+	s := &Status{
+		Hooks: []HookInfo{
+			{Name: "synthesis", Enabled: m.config.Synthesis.Enabled, Detail: fmt.Sprintf("every %d beats, action=%s", threshold, action)},
+			{Name: "pre_commit", Enabled: m.config.PreCommit.Enabled, Detail: m.config.PreCommit.Script},
+			{Name: "notify", Enabled: m.config.Notify.Enabled, Detail: fmt.Sprintf("%d target(s)", len(m.config.Notify.Targets))},
+			{Name: "duplicates", Enabled: duplicates.Enabled, Detail: fmt.Sprintf("similarity >= %.2f, action=%s", duplicates.Threshold, duplicates.Action)},
+			{Name: "link_suggestions", Enabled: links.Enabled, Detail: fmt.Sprintf("similarity >= %.2f", links.Threshold)},
+			{Name: "session_end", Enabled: sessionEnd.Enabled, Detail: fmt.Sprintf("model=%s, extract=%s", sessionEnd.OllamaModel, sessionEnd.Extract)},
+			{Name: "on_bead_changed", Enabled: m.config.BeadChanged.Enabled, Detail: m.config.BeadChanged.Script},
+			{Name: "on_event", Enabled: m.config.Events.Enabled, Detail: eventHookDetail(m.config.Events)},
+			{Name: "secrets", Enabled: secrets.Enabled, Detail: fmt.Sprintf("action=%s, %d custom pattern(s)", secrets.Action, len(secrets.Patterns))},
+			{Name: "quota", Enabled: quota.Enabled, Detail: fmt.Sprintf("%d daily cap(s), noise score >= %d", len(quota.Daily), quota.NoiseThreshold)},
+			{Name: "approval", Enabled: approval.Enabled, Detail: fmt.Sprintf("%d trusted agent(s)", len(approval.Trusted))},
+		},
+		Threshold:        threshold,
+		BeatsSinceLast:   m.state.TotalBeats - m.state.LastSynthesisCount,
+		TotalBeats:       m.state.TotalBeats,
+		ConfigFile:       configPath,
+		ConfigFileExists: statErr == nil,
+		GlobalFile:       GlobalHooksPath(),
+	}
+	if s.GlobalFile != "" {
+		_, err := os.Stat(s.GlobalFile)
+		s.GlobalFileExists = err == nil
+	}
+	if !m.state.LastSynthesisAt.IsZero() {
+		t := m.state.LastSynthesisAt
+		s.LastSynthesisAt = &t
+	}
+	if remaining := threshold - s.BeatsSinceLast; remaining > 0 {
+		s.BeatsUntilTrigger = remaining
+	}
+	if req, err := GetSynthesisRequest(beatsDir); err == nil {
+		s.PendingSynthesis = req
+	}
+
+	return s, nil
+}
+
+// eventHookDetail names where on_event delivers.
+func eventHookDetail(h EventHook) string {
+	var parts []string
+	if h.Script != "" {
+		parts = append(parts, "script="+h.Script)
+	}
+	if h.URL != "" {
+		parts = append(parts, "url="+h.URL)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetHookEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global string // Global hooks.json, if any
+		store  string // Store hooks.json before toggling, if any
+
+		threshold int      // Effective synthesis threshold after each toggle
+		keep      []string // Store sections that must survive
+	}{
+		{name: "missing file", threshold: 0, keep: []string{"synthesis"}},
+		{
+			name:      "existing file",
+			store:     `{"synthesis": {"enabled": false, "threshold": 8}, "future_hook": {"mode": "x"}}`,
+			threshold: 8,
+			keep:      []string{"synthesis", "future_hook"},
+		},
+		{
+			name:      "global layer",
+			global:    `{"synthesis": {"enabled": true, "threshold": 10, "action": "script"}}`,
+			threshold: 10,
+			keep:      []string{"synthesis"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir := t.TempDir()
+			t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+			if tt.global != "" {
+				if err := os.WriteFile(filepath.Join(configDir, HooksConfigFile), []byte(tt.global), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			store := t.TempDir()
+			path := filepath.Join(store, HooksConfigFile)
+			if tt.store != "" {
+				if err := os.WriteFile(path, []byte(tt.store), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, enabled := range []bool{true, false} {
+				if err := SetHookEnabled(store, "synthesis", enabled); err != nil {
+					t.Fatal(err)
+				}
+				m, err := NewManager(store)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := m.config.Synthesis; got.Enabled != enabled || got.Threshold != tt.threshold {
+					t.Errorf("enabled=%v: synthesis = %+v, want threshold %d", enabled, got, tt.threshold)
+				}
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var raw map[string]json.RawMessage
+				if err := json.Unmarshal(data, &raw); err != nil {
+					t.Fatal(err)
+				}
+				if len(raw) != len(tt.keep) {
+					t.Errorf("enabled=%v: store sections %s, want %v", enabled, data, tt.keep)
+				}
+				for _, section := range tt.keep {
+					if raw[section] == nil {
+						t.Errorf("enabled=%v: section %q lost", enabled, section)
+					}
+				}
+			}
+		})
+	}
+
+	if err := SetHookEnabled(t.TempDir(), "pre-commit", true); err != nil {
+		t.Errorf("hyphenated name: %v", err)
+	}
+	if err := SetHookEnabled(t.TempDir(), "nonsense", true); err == nil {
+		t.Error("unknown hook enabled without an error")
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(configDir, "config.json"))
+
+	bare := t.TempDir()
+	s, err := GetStatus(bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ConfigFileExists || s.GlobalFileExists || s.Threshold != 5 || s.BeatsUntilTrigger != 5 {
+		t.Errorf("bare store: %+v, want no files and the default threshold", s)
+	}
+
+	global := `{"approval": {"enabled": true, "trusted": ["scout", "scribe"]}}`
+	if err := os.WriteFile(filepath.Join(configDir, HooksConfigFile), []byte(global), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := t.TempDir()
+	config := `{"synthesis": {"enabled": true, "threshold": 3}, "duplicates": {"enabled": true, "threshold": 0.85, "action": "reject"}}`
+	if err := os.WriteFile(filepath.Join(store, HooksConfigFile), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := `{"total_beats": 5, "last_synthesis_count": 4}`
+	if err := os.WriteFile(filepath.Join(store, HookStateFile), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = GetStatus(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.ConfigFileExists || !s.GlobalFileExists || s.Threshold != 3 || s.TotalBeats != 5 || s.BeatsSinceLast != 1 || s.BeatsUntilTrigger != 2 {
+		t.Errorf("status = %+v", s)
+	}
+	if len(s.Hooks) != len(HookNames) {
+		t.Fatalf("%d hooks listed, want %d", len(s.Hooks), len(HookNames))
+	}
+	want := map[string]HookInfo{
+		"synthesis":  {Name: "synthesis", Enabled: true, Detail: "every 3 beats, action=file"},
+		"notify":     {Name: "notify", Detail: "0 target(s)"},
+		"duplicates": {Name: "duplicates", Enabled: true, Detail: "similarity >= 0.85, action=reject"},
+		"approval":   {Name: "approval", Enabled: true, Detail: "2 trusted agent(s)"},
+	}
+	for i, h := range s.Hooks {
+		if h.Name != HookNames[i] {
+			t.Errorf("hook %d is %q, want %q", i, h.Name, HookNames[i])
+		}
+		if w, ok := want[h.Name]; ok && h != w {
+			t.Errorf("hook %s = %+v, want %+v", h.Name, h, w)
+		}
+	}
+}
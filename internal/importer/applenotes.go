@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// appleNote is one note as printed by appleNotesScript.
+type appleNote struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Folder   string    `json:"folder"`
+	Body     string    `json:"body"`
+	Created  time.Time `json:"created"`
+	Modified time.Time `json:"modified"`
+}
+
+// appleNotesScript reads notes through the Notes scripting bridge (JXA) and
+// prints them as JSON; argv[0] optionally limits it to one folder.
+const appleNotesScript = `function run(argv) {
+  const want = argv.length > 0 ? argv[0] : "";
+  const out = [];
+  Application("Notes").folders().forEach(function (f) {
+    const folder = f.name();
+    if (folder === "Recently Deleted" || (want !== "" && folder !== want)) return;
+    const ids = f.notes.id(), names = f.notes.name(), bodies = f.notes.plaintext();
+    const created = f.notes.creationDate(), modified = f.notes.modificationDate();
+    for (let i = 0; i < ids.length; i++) {
+      out.push({id: ids[i], name: names[i], folder: folder, body: bodies[i],
+        created: created[i].toISOString(), modified: modified[i].toISOString()});
+    }
+  });
+  return JSON.stringify(out);
+}`
+
+// readAppleNotes runs the bridge script; tests replace it.
+var readAppleNotes = func(folder string) ([]byte, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, fmt.Errorf("Apple Notes import requires macOS (running on %s)", runtime.GOOS)
+	}
+	args := []string{"-l", "JavaScript", "-e", appleNotesScript}
+	if folder != "" {
+		args = append(args, folder)
+	}
+	out, err := exec.Command("osascript", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			// Usually missing Automation permission for the terminal
+			return nil, fmt.Errorf("osascript: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("osascript: %w", err)
+	}
+	return out, nil
+}
+
+// AppleNotesOptions selects which notes are imported.
+type AppleNotesOptions struct {
+	Folder string // Only this Notes folder (all folders when empty)
+}
+
+// AppleNotes pulls notes from the macOS Notes app, keeping their creation dates.
+func AppleNotes(opts AppleNotesOptions) ([]*beat.ProposedBeat, []string, error) {
+	out, err := readAppleNotes(opts.Folder)
+	if err != nil {
+		return nil, nil, err
+	}
+	var notes []appleNote
+	if err := json.Unmarshal(out, &notes); err != nil {
+		return nil, nil, fmt.Errorf("unexpected Notes output: %w", err)
+	}
+	if opts.Folder != "" && len(notes) == 0 {
+		return nil, []string{fmt.Sprintf("no notes in folder %q", opts.Folder)}, nil
+	}
+
+	var proposed []*beat.ProposedBeat
+	for _, n := range notes {
+		// The first line of a note's text is its name
+		body := strings.TrimSpace(n.Body)
+		if first, rest, ok := strings.Cut(body, "\n"); ok && strings.TrimSpace(first) == strings.TrimSpace(n.Name) {
+			body = strings.TrimSpace(rest)
+		} else if !ok && body == strings.TrimSpace(n.Name) {
+			body = ""
+		}
+		content := n.Name
+		if body != "" {
+			content = n.Name + "\n\n" + body
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		created := n.Created.UTC()
+		if created.IsZero() {
+			created = n.Modified.UTC()
+		}
+		p := &beat.ProposedBeat{
+			Content: content,
+			Impetus: beat.Impetus{
+				Label: "Apple Note",
+				Raw:   n.Name,
+				Meta: map[string]string{
+					"source":    "apple-notes",
+					SourceIDKey: n.ID,
+					"folder":    n.Folder,
+					"title":     n.Name,
+				},
+			},
+			References:  URLReferences(body),
+			Entities:    linkEntities(nil, InlineTags(body)),
+			LinkedBeads: []string{},
+		}
+		if !created.IsZero() {
+			p.CreatedAt = &created
+		}
+		proposed = append(proposed, p)
+	}
+	return proposed, nil, nil
+}
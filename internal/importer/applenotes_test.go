@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"testing"
+)
+
+func TestAppleNotes(t *testing.T) {
+	orig := readAppleNotes
+	defer func() { readAppleNotes = orig }()
+
+	var gotFolder string
+	readAppleNotes = func(folder string) ([]byte, error) {
+		gotFolder = folder
+		return []byte(`[
+  {"id": "x-coredata://A/ICNote/p12", "name": "Pricing thoughts", "folder": "Ideas",
+   "body": "Pricing thoughts\nUsage-based fits the long tail #pricing\nhttps://example.com/post",
+   "created": "2023-05-04T09:10:00Z", "modified": "2024-01-01T00:00:00Z"},
+  {"id": "x-coredata://A/ICNote/p13", "name": "Groceries", "folder": "Ideas",
+   "body": "Groceries", "created": "2023-06-01T00:00:00Z", "modified": "2023-06-01T00:00:00Z"}
+]`), nil
+	}
+
+	proposed, _, err := AppleNotes(AppleNotesOptions{Folder: "Ideas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotFolder != "Ideas" {
+		t.Errorf("folder passed to bridge = %q", gotFolder)
+	}
+	if len(proposed) != 2 {
+		t.Fatalf("got %d beats, want 2", len(proposed))
+	}
+
+	p := proposed[0]
+	if p.Content != "Pricing thoughts\n\nUsage-based fits the long tail #pricing\nhttps://example.com/post" {
+		t.Errorf("content = %q", p.Content)
+	}
+	if p.CreatedAt == nil || p.CreatedAt.Format("2006-01-02") != "2023-05-04" {
+		t.Errorf("created_at = %v, want the note's creation date", p.CreatedAt)
+	}
+	if p.Impetus.Meta[SourceIDKey] != "x-coredata://A/ICNote/p12" || p.Impetus.Meta["folder"] != "Ideas" {
+		t.Errorf("meta = %v", p.Impetus.Meta)
+	}
+	if len(p.Entities) != 1 || p.Entities[0].Label != "pricing" || len(p.References) != 1 {
+		t.Errorf("entities = %+v, references = %+v", p.Entities, p.References)
+	}
+	if proposed[1].Content != "Groceries" {
+		t.Errorf("title-only note content = %q", proposed[1].Content)
+	}
+}
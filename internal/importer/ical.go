@@ -0,0 +1,615 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// ICalOptions selects which calendar events are imported.
+type ICalOptions struct {
+	Since  time.Time // Only events starting at or after (zero for all)
+	Until  time.Time // Only events starting before (default now: past meetings only)
+	AllDay bool      // Include all-day events (holidays, out-of-office)
+}
+
+// ICalEvent is one occurrence of a calendar event.
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Organizer   ICalPerson
+	Attendees   []ICalPerson
+	Calendar    string
+}
+
+// ICalPerson is an organizer or attendee.
+type ICalPerson struct {
+	Name  string
+	Email string
+}
+
+// Label is the display name, falling back to the email address.
+func (p ICalPerson) Label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Email
+}
+
+// icalProp is a content line: NAME;PARAM=V:VALUE.
+type icalProp struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+type icalComponent struct {
+	Props []icalProp
+}
+
+func (c *icalComponent) get(name string) *icalProp {
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+func (c *icalComponent) all(name string) []icalProp {
+	var out []icalProp
+	for _, p := range c.Props {
+		if p.Name == name {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// maxOccurrences bounds recurrence expansion per event.
+const maxOccurrences = 2000
+
+// ReadICal loads calendar data from a file, an http(s)/webcal URL, or a CalDAV
+// collection (queried with REPORT when a plain GET does not return iCalendar).
+// Credentials come from the URL or BEATS_CALDAV_USER/BEATS_CALDAV_PASSWORD.
+func ReadICal(source string, opts ICalOptions) ([]byte, error) {
+	if !strings.Contains(source, "://") {
+		return os.ReadFile(source)
+	}
+	u, err := url.Parse(strings.Replace(source, "webcal://", "https://", 1))
+	if err != nil {
+		return nil, err
+	}
+	user, pass := os.Getenv("BEATS_CALDAV_USER"), os.Getenv("BEATS_CALDAV_PASSWORD")
+	if u.User != nil {
+		user = u.User.Username()
+		pass, _ = u.User.Password()
+		u.User = nil
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+	do := func(method string, body []byte, headers map[string]string) ([]byte, int, error) {
+		req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+		return data, resp.StatusCode, err
+	}
+
+	data, status, err := do(http.MethodGet, nil, nil)
+	if err == nil && status == http.StatusOK && bytes.Contains(data, []byte("BEGIN:VCALENDAR")) {
+		return data, nil
+	}
+
+	// CalDAV calendar-query for events in the requested range
+	rangeAttrs := ""
+	if !opts.Since.IsZero() {
+		rangeAttrs += ` start="` + opts.Since.UTC().Format("20060102T150405Z") + `"`
+	}
+	until := opts.Until
+	if until.IsZero() {
+		until = clock.Now()
+	}
+	rangeAttrs += ` end="` + until.UTC().Format("20060102T150405Z") + `"`
+	query := `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range` + rangeAttrs + `/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+	data, status, err = do("REPORT", []byte(query), map[string]string{"Depth": "1", "Content-Type": "application/xml; charset=utf-8"})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", u.Redacted(), err)
+	}
+	if status != http.StatusMultiStatus && status != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d (not an iCalendar feed or CalDAV collection)", u.Redacted(), status)
+	}
+
+	var ms struct {
+		Responses []struct {
+			Data string `xml:"propstat>prop>calendar-data"`
+		} `xml:"response"`
+	}
+	if err := xml.Unmarshal(data, &ms); err != nil {
+		return nil, fmt.Errorf("invalid CalDAV response: %w", err)
+	}
+	var all bytes.Buffer
+	for _, r := range ms.Responses {
+		all.WriteString(r.Data)
+		all.WriteString("\r\n")
+	}
+	return all.Bytes(), nil
+}
+
+// ParseICal parses VEVENTs and expands recurring ones into occurrences within
+// the options' range, sorted by start time. Cancelled events are dropped.
+func ParseICal(data []byte, opts ICalOptions) ([]ICalEvent, error) {
+	until := opts.Until
+	if until.IsZero() {
+		until = clock.Now()
+	}
+
+	var events []*icalComponent
+	var current *icalComponent
+	calendar := ""
+	depth := 0
+	for _, line := range unfoldICal(data) {
+		p := parseICalLine(line)
+		switch {
+		case p.Name == "BEGIN":
+			depth++
+			if p.Value == "VEVENT" {
+				current = &icalComponent{}
+			}
+		case p.Name == "END":
+			depth--
+			if p.Value == "VEVENT" && current != nil {
+				events = append(events, current)
+				current = nil
+			}
+		case current != nil:
+			current.Props = append(current.Props, p)
+		case p.Name == "X-WR-CALNAME" && calendar == "":
+			calendar = icalText(p.Value)
+		}
+	}
+	if len(events) == 0 && !bytes.Contains(data, []byte("BEGIN:VCALENDAR")) {
+		return nil, fmt.Errorf("no iCalendar data found")
+	}
+
+	// Modified occurrences (RECURRENCE-ID) replace the generated ones
+	overrides := make(map[string]bool)
+	for _, e := range events {
+		if rid := e.get("RECURRENCE-ID"); rid != nil {
+			if t, _, err := icalTime(*rid); err == nil {
+				overrides[icalValue(e, "UID")+"|"+t.UTC().Format(time.RFC3339)] = true
+			}
+		}
+	}
+
+	var out []ICalEvent
+	for _, e := range events {
+		if strings.EqualFold(icalValue(e, "STATUS"), "CANCELLED") {
+			continue
+		}
+		startProp := e.get("DTSTART")
+		if startProp == nil {
+			continue
+		}
+		start, allDay, err := icalTime(*startProp)
+		if err != nil {
+			continue
+		}
+		if allDay && !opts.AllDay {
+			continue
+		}
+		duration := time.Hour
+		if allDay {
+			duration = 24 * time.Hour
+		}
+		if endProp := e.get("DTEND"); endProp != nil {
+			if end, _, err := icalTime(*endProp); err == nil && end.After(start) {
+				duration = end.Sub(start)
+			}
+		} else if d := icalValue(e, "DURATION"); d != "" {
+			if parsed, err := icalDuration(d); err == nil {
+				duration = parsed
+			}
+		}
+
+		base := ICalEvent{
+			UID:         icalValue(e, "UID"),
+			Summary:     icalText(icalValue(e, "SUMMARY")),
+			Description: icalText(icalValue(e, "DESCRIPTION")),
+			Location:    icalText(icalValue(e, "LOCATION")),
+			AllDay:      allDay,
+			Calendar:    calendar,
+		}
+		if org := e.get("ORGANIZER"); org != nil {
+			base.Organizer = icalPerson(*org)
+		}
+		for _, a := range e.all("ATTENDEE") {
+			if strings.EqualFold(a.Params["CUTYPE"], "ROOM") || strings.EqualFold(a.Params["CUTYPE"], "RESOURCE") {
+				continue
+			}
+			base.Attendees = append(base.Attendees, icalPerson(a))
+		}
+
+		starts := []time.Time{start}
+		isOverride := e.get("RECURRENCE-ID") != nil
+		if rule := icalValue(e, "RRULE"); rule != "" && !isOverride {
+			starts = expandRRule(start, rule, until)
+			excluded := make(map[time.Time]bool)
+			for _, ex := range e.all("EXDATE") {
+				for _, v := range strings.Split(ex.Value, ",") {
+					if t, _, err := icalTime(icalProp{Params: ex.Params, Value: v}); err == nil {
+						excluded[t.UTC()] = true
+					}
+				}
+			}
+			filtered := starts[:0]
+			for _, s := range starts {
+				if !excluded[s.UTC()] && !overrides[base.UID+"|"+s.UTC().Format(time.RFC3339)] {
+					filtered = append(filtered, s)
+				}
+			}
+			starts = filtered
+		}
+
+		for _, s := range starts {
+			if !s.Before(until) || (!opts.Since.IsZero() && s.Before(opts.Since)) {
+				continue
+			}
+			ev := base
+			ev.Start = s.UTC()
+			ev.End = s.Add(duration).UTC()
+			out = append(out, ev)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out, nil
+}
+
+// ICal converts past calendar events into "Meeting" beats with attendees as
+// person entities. Occurrences are identified by UID and start time.
+func ICal(source string, opts ICalOptions) ([]*beat.ProposedBeat, []string, error) {
+	data, err := ReadICal(source, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := ParseICal(data, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var proposed []*beat.ProposedBeat
+	for _, ev := range events {
+		proposed = append(proposed, meetingBeat(ev))
+	}
+	return proposed, nil, nil
+}
+
+func meetingBeat(ev ICalEvent) *beat.ProposedBeat {
+	title := ev.Summary
+	if title == "" {
+		title = "Untitled event"
+	}
+
+	people := []ICalPerson{}
+	seen := make(map[string]bool)
+	for _, p := range append([]ICalPerson{ev.Organizer}, ev.Attendees...) {
+		key := strings.ToLower(p.Email)
+		if key == "" {
+			key = strings.ToLower(p.Name)
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		people = append(people, p)
+	}
+
+	var content strings.Builder
+	content.WriteString("Meeting: " + title)
+	if ev.AllDay {
+		content.WriteString("\n" + ev.Start.Format("Mon Jan 2, 2006") + " (all day)")
+	} else {
+		content.WriteString("\n" + ev.Start.Format("Mon Jan 2, 2006 15:04") + "-" + ev.End.Format("15:04") + " UTC")
+	}
+	if len(people) > 0 {
+		names := make([]string, len(people))
+		for i, p := range people {
+			names[i] = p.Label()
+		}
+		content.WriteString("\nWith: " + strings.Join(names, ", "))
+	}
+	if ev.Location != "" {
+		content.WriteString("\nLocation: " + ev.Location)
+	}
+	if ev.Description != "" {
+		content.WriteString("\n\n" + ev.Description)
+	}
+
+	meta := map[string]string{
+		"source":    "ical",
+		SourceIDKey: ev.UID + "@" + ev.Start.Format(time.RFC3339),
+		"uid":       ev.UID,
+		"title":     title,
+		"start":     ev.Start.Format(time.RFC3339),
+		"end":       ev.End.Format(time.RFC3339),
+		"attendees": strconv.Itoa(len(people)),
+	}
+	if ev.Location != "" {
+		meta["location"] = ev.Location
+	}
+	if ev.Calendar != "" {
+		meta["calendar"] = ev.Calendar
+	}
+
+	entities := []beat.Entity{}
+	for _, p := range people {
+		e := beat.Entity{
+			Label:    p.Label(),
+			Category: "person",
+			Meta:     map[string]string{"confidence": "1.0"},
+		}
+		if p.Email != "" {
+			e.Meta["email"] = p.Email
+		}
+		entities = append(entities, e)
+	}
+
+	start := ev.Start
+	return &beat.ProposedBeat{
+		Content: content.String(),
+		Impetus: beat.Impetus{
+			Label: "Meeting",
+			Raw:   title,
+			Meta:  meta,
+		},
+		References:  URLReferences(ev.Location + "\n" + ev.Description),
+		Entities:    entities,
+		LinkedBeads: []string{},
+		CreatedAt:   &start,
+	}
+}
+
+// unfoldICal splits content lines, joining folded continuation lines.
+func unfoldICal(data []byte) []string {
+	raw := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	var lines []string
+	for _, l := range raw {
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// parseICalLine splits NAME;PARAM=VALUE;...:VALUE, honoring quoted parameters.
+func parseICalLine(line string) icalProp {
+	p := icalProp{Params: map[string]string{}}
+	inQuote := false
+	split := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == ':' && !inQuote {
+			split = i
+			break
+		}
+	}
+	if split < 0 {
+		p.Name = strings.ToUpper(line)
+		return p
+	}
+	head, value := line[:split], line[split+1:]
+	parts := strings.Split(head, ";")
+	p.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	p.Value = value
+	return p
+}
+
+func icalValue(c *icalComponent, name string) string {
+	if p := c.get(name); p != nil {
+		return p.Value
+	}
+	return ""
+}
+
+// icalText unescapes a TEXT value.
+func icalText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s))
+}
+
+func icalPerson(p icalProp) ICalPerson {
+	email := p.Value
+	if len(email) > 7 && strings.EqualFold(email[:7], "mailto:") {
+		email = email[7:]
+	}
+	return ICalPerson{Name: strings.TrimSpace(p.Params["CN"]), Email: strings.TrimSpace(email)}
+}
+
+// icalTime parses DATE-TIME (UTC, floating or TZID) and DATE values.
+func icalTime(p icalProp) (time.Time, bool, error) {
+	v := strings.TrimSpace(p.Value)
+	if p.Params["VALUE"] == "DATE" || len(v) == 8 {
+		t, err := time.Parse("20060102", v)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+// icalDuration parses DURATION values like PT1H30M or P1D.
+func icalDuration(s string) (time.Duration, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "+"), "P")
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r == 'T':
+			inTime = true
+		case r >= '0' && r <= '9':
+			num += string(r)
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %s", s)
+			}
+			num = ""
+			switch {
+			case r == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration: %s", s)
+			}
+		}
+	}
+	return d, nil
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// expandRRule generates occurrence starts before limit. It supports FREQ
+// DAILY/WEEKLY/MONTHLY/YEARLY with INTERVAL, COUNT, UNTIL and weekly BYDAY,
+// which covers the recurring meetings calendars typically produce.
+func expandRRule(start time.Time, rule string, limit time.Time) []time.Time {
+	params := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			params[strings.ToUpper(k)] = v
+		}
+	}
+	interval, _ := strconv.Atoi(params["INTERVAL"])
+	if interval < 1 {
+		interval = 1
+	}
+	count, _ := strconv.Atoi(params["COUNT"])
+	if u := params["UNTIL"]; u != "" {
+		if t, _, err := icalTime(icalProp{Params: map[string]string{}, Value: u}); err == nil {
+			if len(u) == 8 {
+				t = t.Add(24*time.Hour - time.Second) // UNTIL as a DATE includes that day
+			}
+			if t.Before(limit) {
+				limit = t.Add(time.Second)
+			}
+		}
+	}
+
+	var byDay []time.Weekday
+	for _, d := range strings.Split(params["BYDAY"], ",") {
+		if wd, ok := icalWeekdays[strings.ToUpper(strings.TrimLeft(d, "+-0123456789"))]; ok {
+			byDay = append(byDay, wd)
+		}
+	}
+
+	var out []time.Time
+	emit := func(t time.Time) bool {
+		if !t.Before(limit) || (count > 0 && len(out) >= count) || len(out) >= maxOccurrences {
+			return false
+		}
+		out = append(out, t)
+		return true
+	}
+
+	switch strings.ToUpper(params["FREQ"]) {
+	case "DAILY":
+		for t := start; emit(t); t = t.AddDate(0, 0, interval) {
+		}
+	case "WEEKLY":
+		if len(byDay) == 0 {
+			for t := start; emit(t); t = t.AddDate(0, 0, 7*interval) {
+			}
+			break
+		}
+		// Walk week by week from the start's week, emitting each BYDAY in order
+		weekStart := start.AddDate(0, 0, -int(start.Weekday()))
+		for week := 0; ; week += interval {
+			done := false
+			for offset := 0; offset < 7 && !done; offset++ {
+				day := weekStart.AddDate(0, 0, 7*week+offset)
+				for _, wd := range byDay {
+					if day.Weekday() == wd && !day.Before(start) {
+						if !emit(day) {
+							done = true
+						}
+						break
+					}
+				}
+			}
+			if done || !weekStart.AddDate(0, 0, 7*week).Before(limit) {
+				break
+			}
+		}
+	case "MONTHLY":
+		for i := 0; emit(start.AddDate(0, i*interval, 0)); i++ {
+		}
+	case "YEARLY":
+		for i := 0; emit(start.AddDate(i*interval, 0, 0)); i++ {
+		}
+	default:
+		out = []time.Time{start}
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Work\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:pricing-1\r\n" +
+	"SUMMARY:Pricing review\\, Q2\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240305T100000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20240305T110000\r\n" +
+	"ORGANIZER;CN=Ada Lovelace:mailto:ada@example.com\r\n" +
+	"ATTENDEE;CN=\"Grace Hopper\";PARTSTAT=ACCEPTED:mailto:grace@example.com\r\n" +
+	"ATTENDEE;CUTYPE=ROOM;CN=Room 1:mailto:room1@example.com\r\n" +
+	"LOCATION:https://meet.example.com/abc\r\n" +
+	"DESCRIPTION:Agenda:\\n- usage tiers\\n- long\r\n" +
+	"  tail\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly-1\r\n" +
+	"SUMMARY:1:1\r\n" +
+	"DTSTART:20240101T150000Z\r\n" +
+	"DURATION:PT30M\r\n" +
+	"RRULE:FREQ=WEEKLY;COUNT=4\r\n" +
+	"EXDATE:20240108T150000Z\r\n" +
+	"ATTENDEE;CN=Grace Hopper:mailto:grace@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly-1\r\n" +
+	"RECURRENCE-ID:20240115T150000Z\r\n" +
+	"SUMMARY:1:1 (moved)\r\n" +
+	"DTSTART:20240116T090000Z\r\n" +
+	"DTEND:20240116T093000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled-1\r\n" +
+	"SUMMARY:Cancelled sync\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20240201T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday-1\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20240401\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:future-1\r\n" +
+	"SUMMARY:Future planning\r\n" +
+	"DTSTART:20990101T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	events, err := ParseICal([]byte(testICS), ICalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, ev := range events {
+		got = append(got, ev.Start.Format("2006-01-02T15:04")+" "+ev.Summary)
+	}
+	want := []string{
+		"2024-01-01T15:00 1:1",
+		"2024-01-16T09:00 1:1 (moved)",
+		"2024-01-22T15:00 1:1",
+		"2024-03-05T09:00 Pricing review, Q2",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+
+	pricing := events[3]
+	if pricing.End.Sub(pricing.Start) != time.Hour {
+		t.Errorf("duration = %v", pricing.End.Sub(pricing.Start))
+	}
+	if pricing.Organizer.Email != "ada@example.com" || len(pricing.Attendees) != 1 || pricing.Attendees[0].Name != "Grace Hopper" {
+		t.Errorf("people = %+v %+v", pricing.Organizer, pricing.Attendees)
+	}
+	if pricing.Description != "Agenda:\n- usage tiers\n- long tail" {
+		t.Errorf("description = %q", pricing.Description)
+	}
+	if events[0].End.Sub(events[0].Start) != 30*time.Minute {
+		t.Errorf("DURATION not applied: %v", events[0].End.Sub(events[0].Start))
+	}
+
+	withAllDay, _ := ParseICal([]byte(testICS), ICalOptions{AllDay: true, Since: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if len(withAllDay) != 2 || withAllDay[1].Summary != "Holiday" || !withAllDay[1].AllDay {
+		t.Errorf("--all-day --since = %+v", withAllDay)
+	}
+}
+
+func TestExpandRRuleByDay(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC) // Monday
+	starts := expandRRule(start, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20240131", time.Now())
+	var got []string
+	for _, s := range starts {
+		got = append(got, s.Format("01-02"))
+	}
+	if strings.Join(got, ",") != "01-01,01-04,01-15,01-18,01-29" {
+		t.Errorf("occurrences = %v", got)
+	}
+}
+
+func TestICal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.ics")
+	if err := os.WriteFile(path, []byte(testICS), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proposed, _, err := ICal(path, ICalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposed) != 4 {
+		t.Fatalf("got %d beats, want 4", len(proposed))
+	}
+
+	p := proposed[3]
+	if p.Impetus.Label != "Meeting" || p.Impetus.Meta["calendar"] != "Work" {
+		t.Errorf("impetus = %+v", p.Impetus)
+	}
+	if p.Impetus.Meta[SourceIDKey] != "pricing-1@2024-03-05T09:00:00Z" {
+		t.Errorf("source_id = %q", p.Impetus.Meta[SourceIDKey])
+	}
+	if !strings.Contains(p.Content, "With: Ada Lovelace, Grace Hopper") {
+		t.Errorf("content = %q", p.Content)
+	}
+	if len(p.Entities) != 2 || p.Entities[1].Category != "person" || p.Entities[1].Meta["email"] != "grace@example.com" {
+		t.Errorf("entities = %+v", p.Entities)
+	}
+	if len(p.References) != 1 || p.References[0].Locator != "https://meet.example.com/abc" {
+		t.Errorf("references = %+v", p.References)
+	}
+	if p.CreatedAt == nil || !p.CreatedAt.Equal(time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("created_at = %v", p.CreatedAt)
+	}
+}
+
+func TestReadICalCalDAV(t *testing.T) {
+	var gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _, _ = r.BasicAuth()
+		if r.Method != "REPORT" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+ <d:response><d:href>/cal/a.ics</d:href><d:propstat><d:prop>
+  <cal:calendar-data>BEGIN:VCALENDAR&#13;
+BEGIN:VEVENT&#13;
+UID:a&#13;
+SUMMARY:Standup&#13;
+DTSTART:20240102T090000Z&#13;
+END:VEVENT&#13;
+END:VCALENDAR&#13;
+</cal:calendar-data></d:prop></d:propstat></d:response>
+</d:multistatus>`))
+	}))
+	defer srv.Close()
+
+	u := strings.Replace(srv.URL, "http://", "http://ada:secret@", 1) + "/cal/"
+	data, err := ReadICal(u, ICalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotUser != "ada" {
+		t.Errorf("basic auth user = %q", gotUser)
+	}
+	events, err := ParseICal(data, ICalOptions{})
+	if err != nil || len(events) != 1 || events[0].Summary != "Standup" {
+		t.Errorf("events = %+v, err = %v", events, err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package importer converts notes from other tools (Obsidian vaults, Logseq
+// graphs, Roam exports) into proposed beats.
+package importer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// SourceIDKey is the impetus.meta key holding an imported item's identity in
+// its source (e.g. a vault-relative path), used to skip it on re-runs.
+const SourceIDKey = "source_id"
+
+var (
+	wikiLinkRegex  = regexp.MustCompile(`\[\[([^\]\|#\^]+)(?:[#\^][^\]\|]*)?(?:\|[^\]]*)?\]\]`)
+	mdLinkRegex    = regexp.MustCompile(`\[([^\]]*)\]\((https?://[^\s)]+)\)`)
+	bareURLRegex   = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+	inlineTagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+)
+
+// WikiLinks returns the distinct page names linked with [[Page]],
+// [[Page|alias]] or [[Page#heading]], ignoring embedded non-note files.
+func WikiLinks(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range wikiLinkRegex.FindAllStringSubmatch(text, -1) {
+		target := strings.TrimSpace(m[1])
+		if i := strings.LastIndex(target, "/"); i >= 0 {
+			target = target[i+1:]
+		}
+		if ext := strings.LastIndex(target, "."); ext > 0 && !strings.EqualFold(target[ext:], ".md") {
+			continue // ![[image.png]] and other attachments
+		}
+		target = strings.TrimSuffix(target, ".md")
+		if target != "" && !seen[strings.ToLower(target)] {
+			seen[strings.ToLower(target)] = true
+			out = append(out, target)
+		}
+	}
+	return out
+}
+
+// InlineTags returns the distinct #tags in text.
+func InlineTags(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range inlineTagRegex.FindAllStringSubmatch(text, -1) {
+		tag := strings.TrimRight(m[1], "/-")
+		if tag != "" && !seen[strings.ToLower(tag)] {
+			seen[strings.ToLower(tag)] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// URLReferences returns url references for markdown links (labelled) and bare URLs.
+func URLReferences(text string) []beat.Reference {
+	refs := []beat.Reference{}
+	seen := make(map[string]bool)
+	for _, m := range mdLinkRegex.FindAllStringSubmatch(text, -1) {
+		if !seen[m[2]] {
+			seen[m[2]] = true
+			refs = append(refs, beat.Reference{Kind: "url", Locator: m[2], Label: strings.TrimSpace(m[1])})
+		}
+	}
+	for _, u := range bareURLRegex.FindAllString(text, -1) {
+		u = strings.TrimRight(u, ".,;:!?")
+		if !seen[u] {
+			seen[u] = true
+			refs = append(refs, beat.Reference{Kind: "url", Locator: u})
+		}
+	}
+	return refs
+}
+
+// linkEntities turns linked page names and tags into topic entities.
+func linkEntities(links, tags []string) []beat.Entity {
+	entities := []beat.Entity{}
+	seen := make(map[string]bool)
+	add := func(label, source string) {
+		if seen[strings.ToLower(label)] {
+			return
+		}
+		seen[strings.ToLower(label)] = true
+		entities = append(entities, beat.Entity{
+			Label:    label,
+			Category: "topic",
+			Meta:     map[string]string{"confidence": "1.0", "source": source},
+		})
+	}
+	for _, l := range links {
+		add(l, "wikilink")
+	}
+	for _, t := range tags {
+		add(t, "tag")
+	}
+	return entities
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+var (
+	logseqPropRegex    = regexp.MustCompile(`^([A-Za-z][\w-]*)::\s*(.*)$`)
+	logseqJournalRegex = regexp.MustCompile(`^(\d{4})[_-](\d{2})[_-](\d{2})$`)
+)
+
+// Logseq reads a graph directory's pages/ and journals/ and converts them to
+// beats. Journal dates come from the file name; other pages use a
+// created-at property or the file's modification time.
+func Logseq(graph string, opts OutlineOptions) ([]*beat.ProposedBeat, []string, error) {
+	var pages []*outlinePage
+	var warnings []string
+	for _, dir := range []string{"pages", "journals"} {
+		entries, err := os.ReadDir(filepath.Join(graph, dir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".md") {
+				continue
+			}
+			rel := dir + "/" + e.Name()
+			data, err := os.ReadFile(filepath.Join(graph, rel))
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+				continue
+			}
+			page := parseLogseqPage(rel, dir == "journals", string(data))
+			if page.Created == nil {
+				if info, err := e.Info(); err == nil {
+					mod := info.ModTime().UTC()
+					page.Created = &mod
+				}
+			}
+			pages = append(pages, page)
+		}
+	}
+	if pages == nil {
+		return nil, nil, fmt.Errorf("%s is not a Logseq graph (no pages/ or journals/)", graph)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].SourceID < pages[j].SourceID })
+	return outlineBeats("logseq", pages, opts), warnings, nil
+}
+
+// logseqTitle decodes a page file name: "a___b" and "a%2Fb" are namespace "a/b".
+func logseqTitle(stem string) string {
+	stem = strings.ReplaceAll(stem, "___", "/")
+	if decoded, err := url.PathUnescape(stem); err == nil {
+		stem = decoded
+	}
+	return stem
+}
+
+func parseLogseqPage(rel string, journal bool, text string) *outlinePage {
+	stem := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+	page := &outlinePage{
+		Title:    logseqTitle(stem),
+		SourceID: rel,
+		Journal:  journal,
+		Props:    map[string]string{},
+	}
+	if m := logseqJournalRegex.FindStringSubmatch(stem); m != nil {
+		if t, err := time.Parse("2006-01-02", m[1]+"-"+m[2]+"-"+m[3]); err == nil {
+			page.Created = &t
+			page.Title = t.Format("Jan 2, 2006")
+		}
+	}
+
+	type frame struct {
+		depth int
+		block *outlineBlock
+	}
+	var stack []frame
+	var current *outlineBlock
+	inBlocks := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimLeft(line, "\t ")
+		indent := line[:len(line)-len(trimmed)]
+		depth := strings.Count(indent, "\t") + strings.Count(indent, " ")/2
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			inBlocks = true
+			b := &outlineBlock{Text: strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))}
+			for len(stack) > 0 && stack[len(stack)-1].depth >= depth {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				page.Blocks = append(page.Blocks, b)
+			} else {
+				parent := stack[len(stack)-1].block
+				parent.Children = append(parent.Children, b)
+			}
+			stack = append(stack, frame{depth, b})
+			current = b
+			if m := logseqPropRegex.FindStringSubmatch(b.Text); m != nil && len(page.Blocks) == 1 && len(stack) == 1 {
+				// A leading properties-only block holds page properties
+				page.Props[strings.ToLower(m[1])] = m[2]
+				b.Text = ""
+			}
+			continue
+		}
+
+		if m := logseqPropRegex.FindStringSubmatch(trimmed); m != nil {
+			key := strings.ToLower(m[1])
+			switch {
+			case !inBlocks || (current != nil && current.Text == "" && len(page.Blocks) == 1 && len(stack) == 1):
+				page.Props[key] = m[2]
+			case key == "id" && current != nil:
+				current.UID = m[2]
+			}
+			// collapsed::, id:: and other block properties are not content
+			continue
+		}
+		if current != nil && trimmed != "" {
+			current.Text += "\n" + trimmed
+		}
+	}
+
+	if title := page.Props["title"]; title != "" {
+		page.Title = title
+		delete(page.Props, "title")
+	}
+	if created := page.Props["created-at"]; created != "" && page.Created == nil {
+		if t, err := time.Parse("2006-01-02", created); err == nil {
+			page.Created = &t
+		}
+	}
+	return page
+}
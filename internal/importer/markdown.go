@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+)
+
+// MarkdownOptions configures a plain markdown folder import.
+type MarkdownOptions struct {
+	Recursive     bool // Include subdirectories
+	SplitHeadings bool // One beat per "##" section instead of per file
+}
+
+// markdownExts are the file extensions treated as markdown notes.
+var markdownExts = map[string]bool{".md": true, ".markdown": true, ".txt": true}
+
+// Markdown reads a folder of plain markdown notes and converts each file, or
+// each "##" section of it, into a beat dated by the file's modification time.
+// The path relative to the folder is kept in impetus.meta. Files that cannot
+// be read are returned as warnings.
+func Markdown(dir string, opts MarkdownOptions) ([]*beat.ProposedBeat, []string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	var paths []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (!opts.Recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if markdownExts[strings.ToLower(filepath.Ext(path))] && !strings.HasPrefix(d.Name(), ".") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(paths)
+
+	var proposed []*beat.ProposedBeat
+	var warnings []string
+	for _, path := range paths {
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+
+		data, err := os.ReadFile(path)
+		if err == nil {
+			info, err = os.Stat(path)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		_, body, err := capture.SplitFrontMatter(data)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		mod := info.ModTime().UTC()
+		title := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+
+		if !opts.SplitHeadings {
+			if content := strings.TrimSpace(body); content != "" {
+				proposed = append(proposed, markdownBeat(root, rel, title, content, mod))
+			}
+			continue
+		}
+		seen := make(map[string]int)
+		for _, s := range SplitHeadings(body) {
+			id := s.Heading
+			if seen[id]++; seen[id] > 1 {
+				id = fmt.Sprintf("%s (%d)", id, seen[id])
+			}
+			heading := title
+			if s.Heading != "" {
+				heading = s.Heading
+			}
+			p := markdownBeat(root, rel, heading, s.Content, mod)
+			// Sections are told apart by heading; the preamble has none
+			p.Impetus.Meta[SourceIDKey] += "#" + id
+			if s.Heading != "" {
+				p.Impetus.Meta["heading"] = s.Heading
+			}
+			proposed = append(proposed, p)
+		}
+	}
+	return proposed, warnings, nil
+}
+
+// Section is a "##" section of a markdown note. The text before the first
+// such heading is a section with no heading.
+type Section struct {
+	Heading string
+	Content string // Including the heading line
+}
+
+// SplitHeadings splits markdown at its level-two headings, ignoring lines in
+// fenced code blocks. Empty sections are dropped, as is a preamble holding
+// only the note's "#" title.
+func SplitHeadings(text string) []Section {
+	var sections []Section
+	cur := Section{}
+	var lines []string
+	flush := func() {
+		content := strings.TrimSpace(strings.Join(lines, "\n"))
+		body := content
+		if cur.Heading == "" {
+			// A lone "# Title" is the file's name, not a note
+			first, rest, _ := strings.Cut(body, "\n")
+			if strings.HasPrefix(first, "# ") {
+				body = strings.TrimSpace(rest)
+			}
+		} else {
+			_, body, _ = strings.Cut(body, "\n")
+			body = strings.TrimSpace(body)
+		}
+		if body != "" {
+			cur.Content = content
+			sections = append(sections, cur)
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			flush()
+			cur = Section{Heading: strings.TrimSpace(strings.TrimLeft(line, "# "))}
+			lines = nil
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// markdownBeat builds the beat for a note, or a section of it, at rel in the
+// folder root. Its source ID is the note's absolute path.
+func markdownBeat(root, rel, title, content string, mod time.Time) *beat.ProposedBeat {
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	meta := map[string]string{
+		"source":    "markdown",
+		SourceIDKey: path,
+		"path":      rel,
+		"folder":    filepath.Base(root),
+		"title":     title,
+	}
+
+	refs := []beat.Reference{{Kind: "file", Subtype: "markdown", Locator: path, Label: rel}}
+	refs = append(refs, URLReferences(content)...)
+
+	return &beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: "Markdown note",
+			Raw:   title,
+			Meta:  meta,
+		},
+		References: refs,
+		Entities:   linkEntities(WikiLinks(content), InlineTags(content)),
+		CreatedAt:  &mod,
+	}
+}
@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitHeadings(t *testing.T) {
+	text := "# Journal\n\n## Monday\nShipped it.\n\n```sh\n## not a heading\n```\n\n## Empty\n\n## Tuesday\nRested #health\n"
+	got := SplitHeadings(text)
+	if len(got) != 2 {
+		t.Fatalf("SplitHeadings = %+v, want Monday and Tuesday", got)
+	}
+	if got[0].Heading != "Monday" || got[0].Content != "## Monday\nShipped it.\n\n```sh\n## not a heading\n```" {
+		t.Errorf("section 0 = %+v", got[0])
+	}
+	if got[1].Heading != "Tuesday" {
+		t.Errorf("section 1 = %+v", got[1])
+	}
+
+	if got := SplitHeadings("Intro line\n## A\nbody"); len(got) != 2 || got[0].Heading != "" || got[0].Content != "Intro line" {
+		t.Errorf("preamble = %+v", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ideas.md"), "---\ntags: [x]\n---\nA note with https://example.com\n")
+	writeFile(t, filepath.Join(dir, "journal.md"), "# Journal\n## Mon\none\n## Mon\ntwo\n")
+	writeFile(t, filepath.Join(dir, "sub", "deep.md"), "deep note")
+	writeFile(t, filepath.Join(dir, ".hidden.md"), "hidden")
+	writeFile(t, filepath.Join(dir, "image.png"), "png")
+
+	proposed, warnings, err := Markdown(dir, MarkdownOptions{})
+	if err != nil || len(warnings) > 0 {
+		t.Fatalf("Markdown() = %v, %v", warnings, err)
+	}
+	if len(proposed) != 2 {
+		t.Fatalf("got %d beats, want 2 (top-level files only)", len(proposed))
+	}
+	p := proposed[0]
+	if p.Content != "A note with https://example.com" || p.Impetus.Meta["path"] != "ideas.md" {
+		t.Errorf("beat = %q %v", p.Content, p.Impetus.Meta)
+	}
+	if p.CreatedAt == nil || time.Since(*p.CreatedAt) > time.Minute {
+		t.Errorf("CreatedAt = %v, want the file's mtime", p.CreatedAt)
+	}
+	if len(p.References) != 2 || p.References[0].Kind != "file" || p.References[1].Locator != "https://example.com" {
+		t.Errorf("References = %+v", p.References)
+	}
+
+	proposed, _, err = Markdown(dir, MarkdownOptions{Recursive: true, SplitHeadings: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, p := range proposed {
+		ids[p.Impetus.Meta[SourceIDKey]] = true
+	}
+	journal := filepath.Join(dir, "journal.md")
+	for _, want := range []string{journal + "#Mon", journal + "#Mon (2)", filepath.Join(dir, "sub", "deep.md") + "#"} {
+		if !ids[want] {
+			t.Errorf("missing source ID %q in %v", want, ids)
+		}
+	}
+	if len(proposed) != 4 {
+		t.Errorf("got %d beats, want 4", len(proposed))
+	}
+}
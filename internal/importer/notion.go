@@ -0,0 +1,486 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// ColumnMapping names the CSV columns that feed each part of a beat. Empty
+// fields are not mapped; Content may list several columns separated by commas.
+type ColumnMapping struct {
+	Title   string
+	Content string
+	Date    string
+	Tags    string
+	URL     string
+}
+
+// Columns returns the mapped content columns.
+func (m ColumnMapping) Columns() []string {
+	var out []string
+	for _, c := range strings.Split(m.Content, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// NotionOptions configures a Notion export or CSV import.
+type NotionOptions struct {
+	Mapping ColumnMapping // Explicit columns; unset fields are guessed from the headers
+	Impetus string        // Impetus label (default "Notion note")
+	// Ask, when set, is called per database with its headers and the proposed
+	// mapping, and returns the mapping to use (interactive mode).
+	Ask func(database string, headers []string, proposed ColumnMapping) ColumnMapping
+}
+
+var (
+	notionIDSuffix  = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	notionProperty  = regexp.MustCompile(`^([^:\n]{1,60}):\s(.*)$`)
+	notionDateForms = []string{
+		time.RFC3339,
+		"January 2, 2006 3:04 PM",
+		"January 2, 2006",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		"2006/01/02",
+		"01/02/2006 15:04",
+		"01/02/2006",
+		"Jan 2, 2006",
+		"2 January 2006",
+	}
+	guessNames = map[string][]string{
+		"title":   {"name", "title", "page", "bookmark"},
+		"content": {"content", "notes", "note", "comment", "comments", "body", "text", "description", "summary", "excerpt"},
+		"date":    {"created", "created time", "created at", "date", "date added", "added", "saved", "saved at", "timestamp"},
+		"tags":    {"tags", "tag", "labels", "categories", "category", "topics"},
+		"url":     {"url", "link", "source", "website", "href"},
+	}
+)
+
+// notionName strips the 32-hex page ID Notion appends to exported names.
+func notionName(name string) string {
+	return strings.TrimSpace(notionIDSuffix.ReplaceAllString(name, ""))
+}
+
+// GuessMapping proposes a mapping from common column names.
+func GuessMapping(headers []string) ColumnMapping {
+	find := func(field string) string {
+		for _, want := range guessNames[field] {
+			for _, h := range headers {
+				if strings.EqualFold(strings.TrimSpace(h), want) {
+					return h
+				}
+			}
+		}
+		return ""
+	}
+	m := ColumnMapping{
+		Title:   find("title"),
+		Content: find("content"),
+		Date:    find("date"),
+		Tags:    find("tags"),
+		URL:     find("url"),
+	}
+	if m.Title == "" && len(headers) > 0 && headers[0] != m.URL && headers[0] != m.Content && headers[0] != m.Date {
+		m.Title = headers[0] // Notion puts the title property first
+	}
+	return m
+}
+
+// merge fills unset fields of m from guess, validating explicit columns.
+func (m ColumnMapping) merge(guess ColumnMapping, headers []string) (ColumnMapping, error) {
+	has := make(map[string]bool)
+	for _, h := range headers {
+		has[h] = true
+	}
+	pick := func(explicit, guessed string) (string, error) {
+		if explicit == "" {
+			return guessed, nil
+		}
+		if explicit == "-" {
+			return "", nil // Explicitly unmapped
+		}
+		if !has[explicit] {
+			return "", fmt.Errorf("no column %q (columns: %s)", explicit, strings.Join(headers, ", "))
+		}
+		return explicit, nil
+	}
+	var out ColumnMapping
+	var err error
+	if out.Title, err = pick(m.Title, guess.Title); err != nil {
+		return out, err
+	}
+	if out.Date, err = pick(m.Date, guess.Date); err != nil {
+		return out, err
+	}
+	if out.Tags, err = pick(m.Tags, guess.Tags); err != nil {
+		return out, err
+	}
+	if out.URL, err = pick(m.URL, guess.URL); err != nil {
+		return out, err
+	}
+	if m.Content == "" {
+		out.Content = guess.Content
+	} else if m.Content != "-" {
+		for _, c := range m.Columns() {
+			if !has[c] {
+				return out, fmt.Errorf("no column %q (columns: %s)", c, strings.Join(headers, ", "))
+			}
+		}
+		out.Content = m.Content
+	}
+	return out, nil
+}
+
+// notionPage is an exported markdown page: "# Title", optional "Key: value"
+// property lines, then the body.
+type notionPage struct {
+	Title string
+	Props map[string]string
+	Body  string
+}
+
+func parseNotionPage(name string, data []byte) notionPage {
+	text := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+	page := notionPage{Title: notionName(strings.TrimSuffix(path.Base(name), path.Ext(name))), Props: map[string]string{}}
+	lines := strings.Split(text, "\n")
+	i := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		page.Title = strings.TrimSpace(lines[0][2:])
+		i = 1
+	}
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	// A block of property lines directly under the title
+	j := i
+	props := map[string]string{}
+	for j < len(lines) && lines[j] != "" {
+		m := notionProperty.FindStringSubmatch(lines[j])
+		if m == nil {
+			break
+		}
+		props[m[1]] = strings.TrimSpace(m[2])
+		j++
+	}
+	if len(props) > 0 && (j == len(lines) || lines[j] == "") {
+		page.Props = props
+		i = j
+	}
+	page.Body = strings.TrimSpace(strings.Join(lines[i:], "\n"))
+	return page
+}
+
+func parseNotionDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "→"); i >= 0 {
+		s = strings.TrimSpace(s[:i]) // Date ranges start at the first date
+	}
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, form := range notionDateForms {
+		if t, err := time.Parse(form, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ReadCSV parses a CSV file (Notion database exports included) into headers and rows.
+func ReadCSV(data []byte) ([]string, [][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("empty CSV")
+	}
+	headers := records[0]
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+	return headers, records[1:], nil
+}
+
+// csvBeats converts database rows into beats. Unmapped non-empty columns are
+// appended to the content as "Column: value" lines so nothing is lost; pages
+// holds the exported body of each row, keyed by title.
+func csvBeats(database string, headers []string, rows [][]string, m ColumnMapping, pages map[string]notionPage, label string) ([]*beat.ProposedBeat, []string) {
+	index := make(map[string]int)
+	for i, h := range headers {
+		index[h] = i
+	}
+	get := func(row []string, col string) string {
+		if i, ok := index[col]; ok && col != "" && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	mapped := map[string]bool{m.Title: true, m.Date: true, m.Tags: true, m.URL: true}
+	for _, c := range m.Columns() {
+		mapped[c] = true
+	}
+
+	var proposed []*beat.ProposedBeat
+	var warnings []string
+	seen := make(map[string]int)
+	for n, row := range rows {
+		title := get(row, m.Title)
+
+		var parts []string
+		for _, c := range m.Columns() {
+			if v := get(row, c); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		if page, ok := pages[title]; ok && page.Body != "" {
+			parts = append(parts, page.Body)
+		}
+		var extra []string
+		for _, h := range headers {
+			if v := get(row, h); v != "" && !mapped[h] {
+				extra = append(extra, h+": "+v)
+			}
+		}
+		if len(extra) > 0 {
+			parts = append(parts, strings.Join(extra, "\n"))
+		}
+		if len(parts) == 0 && title == "" {
+			continue // Blank row
+		}
+
+		content := strings.Join(parts, "\n\n")
+		if title != "" {
+			content = strings.TrimSpace(title + "\n\n" + content)
+		}
+
+		// Titles identify rows across re-exports; repeats get a counter
+		id := title
+		if id == "" {
+			sum := sha1.Sum([]byte(strings.Join(row, "\x1f")))
+			id = hex.EncodeToString(sum[:6])
+		}
+		seen[id]++
+		if seen[id] > 1 {
+			id = fmt.Sprintf("%s#%d", id, seen[id])
+		}
+
+		meta := map[string]string{
+			"source":    "notion",
+			SourceIDKey: database + "/" + id,
+			"database":  database,
+		}
+		if title != "" {
+			meta["title"] = title
+		}
+
+		p := &beat.ProposedBeat{
+			Content: content,
+			Impetus: beat.Impetus{
+				Label: label,
+				Raw:   title,
+				Meta:  meta,
+			},
+			References:  URLReferences(content),
+			LinkedBeads: []string{},
+		}
+		if u := get(row, m.URL); u != "" {
+			p.Impetus.Raw = u
+			refs := []beat.Reference{{Kind: "url", Locator: u, Label: title}}
+			for _, r := range p.References {
+				if r.Locator != u {
+					refs = append(refs, r)
+				}
+			}
+			p.References = refs
+		}
+		if raw := get(row, m.Date); raw != "" {
+			if t, ok := parseNotionDate(raw); ok {
+				p.CreatedAt = &t
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s row %d: unrecognized date %q", database, n+2, raw))
+			}
+		}
+		var tags []string
+		for _, t := range strings.Split(get(row, m.Tags), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tags = append(tags, strings.TrimPrefix(t, "#"))
+			}
+		}
+		p.Entities = linkEntities(nil, append(tags, InlineTags(content)...))
+		proposed = append(proposed, p)
+	}
+	return proposed, warnings
+}
+
+// pageBeat converts a standalone exported page into a beat.
+func pageBeat(rel string, page notionPage, label string) *beat.ProposedBeat {
+	content := page.Title
+	if page.Body != "" {
+		content += "\n\n" + page.Body
+	}
+	p := &beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: label,
+			Raw:   page.Title,
+			Meta: map[string]string{
+				"source":    "notion",
+				SourceIDKey: rel,
+				"title":     page.Title,
+			},
+		},
+		References:  URLReferences(content),
+		Entities:    linkEntities(nil, InlineTags(content)),
+		LinkedBeads: []string{},
+	}
+	for _, key := range []string{"Created", "Created time", "Date"} {
+		if t, ok := parseNotionDate(page.Props[key]); ok {
+			p.CreatedAt = &t
+			break
+		}
+	}
+	return p
+}
+
+// Notion imports a Notion export (.zip of markdown pages and CSV databases)
+// or a single CSV file. Each database row becomes a beat through the column
+// mapping, enriched with the row's exported page body; standalone pages
+// become one beat each. Rows and pages are identified by database and title
+// so re-running the import only adds new ones.
+func Notion(source string, opts NotionOptions) ([]*beat.ProposedBeat, []string, error) {
+	label := opts.Impetus
+	if label == "" {
+		label = "Notion note"
+	}
+
+	files := make(map[string][]byte) // Slash path -> content
+	if strings.EqualFold(path.Ext(source), ".zip") {
+		zr, err := zip.OpenReader(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() { _ = zr.Close() }()
+		for _, f := range zr.File {
+			ext := strings.ToLower(path.Ext(f.Name))
+			if f.FileInfo().IsDir() || (ext != ".csv" && ext != ".md") || strings.HasPrefix(path.Base(f.Name), ".") {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, nil, err
+			}
+			data, err := io.ReadAll(io.LimitReader(rc, 64<<20))
+			_ = rc.Close()
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			files[f.Name] = data
+		}
+	} else {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[path.Base(source)] = data
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Newer exports write both "DB.csv" and "DB_all.csv"; the latter has every row
+	var databases []string
+	dbDirs := make(map[string]bool) // Row page folders ("<dir>/<DB name>")
+	for _, name := range names {
+		if !strings.EqualFold(path.Ext(name), ".csv") {
+			continue
+		}
+		stem := strings.TrimSuffix(name, path.Ext(name))
+		if !strings.HasSuffix(stem, "_all") {
+			if _, ok := files[stem+"_all.csv"]; ok {
+				continue
+			}
+		}
+		databases = append(databases, name)
+		dbDirs[notionDir(strings.TrimSuffix(stem, "_all"))] = true
+	}
+
+	var proposed []*beat.ProposedBeat
+	var warnings []string
+	rowPages := make(map[string]map[string]notionPage) // DB dir -> title -> page
+	for _, name := range names {
+		if !strings.EqualFold(path.Ext(name), ".md") {
+			continue
+		}
+		page := parseNotionPage(name, files[name])
+		if dir := notionDir(path.Dir(name)); dbDirs[dir] {
+			if rowPages[dir] == nil {
+				rowPages[dir] = make(map[string]notionPage)
+			}
+			rowPages[dir][page.Title] = page
+			continue
+		}
+		if page.Body == "" && len(page.Props) == 0 {
+			continue // Empty page
+		}
+		rel := notionDir(strings.TrimSuffix(name, path.Ext(name)))
+		proposed = append(proposed, pageBeat(rel, page, label))
+	}
+
+	for _, name := range databases {
+		headers, rows, err := ReadCSV(files[name])
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		stem := strings.TrimSuffix(strings.TrimSuffix(name, path.Ext(name)), "_all")
+		database := notionName(path.Base(stem))
+
+		mapping, err := opts.Mapping.merge(GuessMapping(headers), headers)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%s: %w", database, err)
+		}
+		if opts.Ask != nil {
+			if mapping, err = opts.Ask(database, headers, mapping).merge(ColumnMapping{}, headers); err != nil {
+				return nil, warnings, fmt.Errorf("%s: %w", database, err)
+			}
+		}
+
+		beats, rowWarnings := csvBeats(database, headers, rows, mapping, rowPages[notionDir(stem)], label)
+		proposed = append(proposed, beats...)
+		warnings = append(warnings, rowWarnings...)
+	}
+	return proposed, warnings, nil
+}
+
+// notionDir normalizes an export path by stripping page IDs from every segment.
+func notionDir(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = notionName(part)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+	return path
+}
+
+func TestNotionExport(t *testing.T) {
+	const id = " 0123456789abcdef0123456789abcdef"
+	path := writeZip(t, map[string]string{
+		"Export/Reading List" + id + ".csv": "Name,Created,Tags,URL,Status\n" +
+			"Pricing essay,\"March 4, 2024 9:15 AM\",\"pricing, strategy\",https://example.com/pricing,Read\n" +
+			"Pricing essay,\"March 5, 2024\",,,\n" +
+			",,,,\n",
+		"Export/Reading List" + id + "_all.csv": "Name,Created,Tags,URL,Status\n" +
+			"Pricing essay,\"March 4, 2024 9:15 AM\",\"pricing, strategy\",https://example.com/pricing,Read\n" +
+			"Pricing essay,\"March 5, 2024\",,,\n" +
+			"Unread,2024-03-06,,,\n",
+		"Export/Reading List" + id + "/Pricing essay" + id + ".md": "# Pricing essay\n\nStatus: Read\nURL: https://example.com/pricing\n\nUsage-based wins for the long tail.",
+		"Export/Ideas" + id + ".md":                                "# Ideas\n\nCreated: January 2, 2024 10:00 AM\n\nShip a #cli for beats.",
+		"Export/Empty" + id + ".md":                                "# Empty\n",
+	})
+
+	proposed, warnings, err := Notion(path, NotionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v", warnings)
+	}
+	if len(proposed) != 4 {
+		for _, p := range proposed {
+			t.Logf("%s", p.Impetus.Meta[SourceIDKey])
+		}
+		t.Fatalf("got %d beats, want 4 (page + 3 rows from _all.csv)", len(proposed))
+	}
+
+	page := proposed[0]
+	if page.Impetus.Meta[SourceIDKey] != "Export/Ideas" || page.CreatedAt == nil || page.CreatedAt.Format("2006-01-02") != "2024-01-02" {
+		t.Errorf("page = %+v", page.Impetus)
+	}
+	if len(page.Entities) != 1 || page.Entities[0].Label != "cli" {
+		t.Errorf("page entities = %+v", page.Entities)
+	}
+
+	row := proposed[1]
+	if row.Impetus.Meta[SourceIDKey] != "Reading List/Pricing essay" || row.Impetus.Label != "Notion note" {
+		t.Errorf("row impetus = %+v", row.Impetus)
+	}
+	for _, want := range []string{"Pricing essay", "Usage-based wins for the long tail.", "Status: Read"} {
+		if !strings.Contains(row.Content, want) {
+			t.Errorf("row content missing %q:\n%s", want, row.Content)
+		}
+	}
+	if row.CreatedAt == nil || row.CreatedAt.Format("2006-01-02 15:04") != "2024-03-04 09:15" {
+		t.Errorf("row created_at = %v", row.CreatedAt)
+	}
+	if len(row.Entities) != 2 || row.Entities[1].Label != "strategy" {
+		t.Errorf("row entities = %+v", row.Entities)
+	}
+	if len(row.References) != 1 || row.References[0].Locator != "https://example.com/pricing" {
+		t.Errorf("row references = %+v", row.References)
+	}
+	if proposed[2].Impetus.Meta[SourceIDKey] != "Reading List/Pricing essay#2" {
+		t.Errorf("duplicate title source_id = %q", proposed[2].Impetus.Meta[SourceIDKey])
+	}
+}
+
+func TestNotionCSVMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.csv")
+	csv := "\ufeffLink,Comment,Saved,Folder\nhttps://go.dev,Go home,2023-01-02,dev\n"
+	if err := os.WriteFile(path, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Notion(path, NotionOptions{Mapping: ColumnMapping{Content: "Missing"}}); err == nil {
+		t.Error("mapping an unknown column succeeded, want error")
+	}
+
+	var asked []string
+	proposed, _, err := Notion(path, NotionOptions{
+		Mapping: ColumnMapping{Title: "-", Content: "Comment", Date: "Saved", Tags: "Folder"},
+		Impetus: "Bookmark",
+		Ask: func(db string, headers []string, m ColumnMapping) ColumnMapping {
+			asked = headers
+			m.URL = "Link"
+			return m
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(asked, ",") != "Link,Comment,Saved,Folder" {
+		t.Errorf("headers = %v", asked)
+	}
+	if len(proposed) != 1 {
+		t.Fatalf("got %d beats, want 1", len(proposed))
+	}
+	p := proposed[0]
+	if p.Content != "Go home" || p.Impetus.Label != "Bookmark" || p.Impetus.Raw != "https://go.dev" {
+		t.Errorf("beat = %+v", p)
+	}
+	if p.CreatedAt == nil || p.CreatedAt.Format("2006-01-02") != "2023-01-02" || len(p.Entities) != 1 || p.Entities[0].Label != "dev" {
+		t.Errorf("date/tags = %v %+v", p.CreatedAt, p.Entities)
+	}
+}
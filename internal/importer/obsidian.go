@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+)
+
+// ObsidianOptions selects which notes of a vault are imported.
+type ObsidianOptions struct {
+	Folders []string // Vault-relative folders to include (all when empty)
+	Tags    []string // Only notes carrying one of these tags (all when empty)
+}
+
+// Obsidian reads the markdown notes of a vault and converts the selected ones
+// into beats: front matter dates become created_at (falling back to the file's
+// modification time), wiki-links and tags become entities, URLs references.
+// Notes that cannot be parsed are returned as warnings rather than failing the run.
+func Obsidian(vault string, opts ObsidianOptions) ([]*beat.ProposedBeat, []string, error) {
+	info, err := os.Stat(vault)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("not a directory: %s", vault)
+	}
+	vaultName := filepath.Base(filepath.Clean(vault))
+
+	var folders []string
+	for _, f := range opts.Folders {
+		folders = append(folders, strings.Trim(filepath.ToSlash(f), "/")+"/")
+	}
+	wantTags := make(map[string]bool)
+	for _, t := range opts.Tags {
+		wantTags[strings.ToLower(strings.TrimPrefix(t, "#"))] = true
+	}
+
+	var paths []string
+	err = filepath.WalkDir(vault, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// .obsidian holds settings, .trash deleted notes
+			if path != vault && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".md") && !strings.HasPrefix(d.Name(), ".") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(paths)
+
+	var proposed []*beat.ProposedBeat
+	var warnings []string
+	for _, path := range paths {
+		rel, _ := filepath.Rel(vault, path)
+		rel = filepath.ToSlash(rel)
+		if len(folders) > 0 && !hasFolderPrefix(rel, folders) {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+		note, err := capture.ParseNote(filepath.Base(path), data)
+		if errors.Is(err, capture.ErrEmptyNote) {
+			continue // Stub pages created by following a link
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+
+		tags := append(note.Tags, InlineTags(note.Content)...)
+		if len(wantTags) > 0 && !anyTag(tags, wantTags) {
+			continue
+		}
+
+		p := obsidianBeat(vaultName, rel, note, tags)
+		if p.CreatedAt == nil {
+			if fi, err := os.Stat(path); err == nil {
+				mod := fi.ModTime().UTC()
+				p.CreatedAt = &mod
+			}
+		}
+		proposed = append(proposed, p)
+	}
+	return proposed, warnings, nil
+}
+
+func obsidianBeat(vaultName, rel string, note *capture.Note, tags []string) *beat.ProposedBeat {
+	title := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+	meta := note.Meta
+	meta["source"] = "obsidian"
+	meta[SourceIDKey] = rel
+	meta["vault"] = vaultName
+	meta["title"] = title
+
+	label := note.Impetus
+	if label == "" {
+		label = "Obsidian note"
+	}
+
+	refs := []beat.Reference{{
+		Kind:    "file",
+		Subtype: "obsidian",
+		Locator: "obsidian://open?vault=" + url.PathEscape(vaultName) + "&file=" + url.PathEscape(strings.TrimSuffix(rel, filepath.Ext(rel))),
+		Label:   title,
+	}}
+	if note.URL != "" {
+		refs = append(refs, beat.Reference{Kind: "url", Locator: note.URL})
+	}
+	for _, r := range URLReferences(note.Content) {
+		if r.Locator != note.URL {
+			refs = append(refs, r)
+		}
+	}
+
+	return &beat.ProposedBeat{
+		Content: note.Content,
+		Impetus: beat.Impetus{
+			Label: label,
+			Raw:   title,
+			Meta:  meta,
+		},
+		References:  refs,
+		Entities:    linkEntities(WikiLinks(note.Content), tags),
+		LinkedBeads: append([]string{}, note.Beads...),
+		CreatedAt:   note.Date,
+	}
+}
+
+func hasFolderPrefix(rel string, folders []string) bool {
+	for _, f := range folders {
+		if strings.HasPrefix(rel, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyTag(tags []string, want map[string]bool) bool {
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		// Nested tags: selecting "project" also matches "project/beats"
+		for !want[t] {
+			i := strings.LastIndex(t, "/")
+			if i < 0 {
+				break
+			}
+			t = t[:i]
+		}
+		if want[t] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWikiLinks(t *testing.T) {
+	got := WikiLinks("See [[Roadmap|the plan]], [[folder/Ideas#Big]], [[roadmap]] and ![[diagram.png]] [[Notes.md]]")
+	if want := []string{"Roadmap", "Ideas", "Notes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WikiLinks = %v, want %v", got, want)
+	}
+}
+
+func TestInlineTags(t *testing.T) {
+	got := InlineTags("# Heading\nSlept badly #health #2024 and #project/beats, not a#tag")
+	if want := []string{"health", "project/beats"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("InlineTags = %v, want %v", got, want)
+	}
+}
+
+func TestURLReferences(t *testing.T) {
+	refs := URLReferences("Read [the docs](https://example.com/docs) and https://go.dev. Also https://example.com/docs")
+	if len(refs) != 2 || refs[0].Label != "the docs" || refs[1].Locator != "https://go.dev" {
+		t.Errorf("refs = %+v", refs)
+	}
+}
+
+func TestObsidian(t *testing.T) {
+	vault := filepath.Join(t.TempDir(), "Vault")
+	writeFile(t, filepath.Join(vault, ".obsidian", "workspace.md"), "settings")
+	writeFile(t, filepath.Join(vault, "Projects", "Plan.md"), "---\ncreated: 2025-11-03\ntags: [project/beats]\n---\nSee [[Roadmap]] at https://example.com\n")
+	writeFile(t, filepath.Join(vault, "Daily", "2025-12-01.md"), "Slept badly #health\n")
+	writeFile(t, filepath.Join(vault, "Stub.md"), "")
+
+	proposed, warnings, err := Obsidian(vault, ObsidianOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v (empty stubs are skipped silently)", warnings)
+	}
+	if len(proposed) != 2 {
+		t.Fatalf("got %d beats, want 2", len(proposed))
+	}
+
+	daily, plan := proposed[0], proposed[1]
+	if daily.CreatedAt == nil || daily.CreatedAt.Format("2006-01-02") != "2025-12-01" {
+		t.Errorf("daily created_at = %v", daily.CreatedAt)
+	}
+	if plan.Impetus.Meta[SourceIDKey] != "Projects/Plan.md" || plan.Impetus.Meta["vault"] != "Vault" {
+		t.Errorf("plan meta = %v", plan.Impetus.Meta)
+	}
+	if plan.CreatedAt == nil || plan.CreatedAt.Format("2006-01-02") != "2025-11-03" {
+		t.Errorf("plan created_at = %v, want front matter date", plan.CreatedAt)
+	}
+	if len(plan.Entities) != 2 || plan.Entities[0].Label != "Roadmap" || plan.Entities[1].Label != "project/beats" {
+		t.Errorf("plan entities = %+v", plan.Entities)
+	}
+	if len(plan.References) != 2 || plan.References[1].Locator != "https://example.com" {
+		t.Errorf("plan references = %+v", plan.References)
+	}
+
+	byFolder, _, _ := Obsidian(vault, ObsidianOptions{Folders: []string{"Daily"}})
+	byTag, _, _ := Obsidian(vault, ObsidianOptions{Tags: []string{"#project"}})
+	if len(byFolder) != 1 || byFolder[0].Impetus.Raw != "2025-12-01" {
+		t.Errorf("--folder Daily selected %d beats", len(byFolder))
+	}
+	if len(byTag) != 1 || byTag[0].Impetus.Raw != "Plan" {
+		t.Errorf("--tag project selected %d beats (nested tags should match)", len(byTag))
+	}
+}
@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// outlinePage is a page of an outliner graph (Logseq, Roam).
+type outlinePage struct {
+	Title    string
+	SourceID string // Stable identity within the graph
+	Journal  bool
+	Created  *time.Time
+	Props    map[string]string
+	Blocks   []*outlineBlock
+}
+
+// outlineBlock is a bullet and its children.
+type outlineBlock struct {
+	UID      string
+	Text     string
+	Created  *time.Time
+	Children []*outlineBlock
+}
+
+// OutlineOptions controls how outliner pages become beats.
+type OutlineOptions struct {
+	Blocks bool // One beat per top-level block instead of per page
+}
+
+var (
+	blockRefRegex = regexp.MustCompile(`\(\(([\w-]{6,})\)\)`)
+	// Outliner markers that look like links but are not topics
+	outlineMarkers = map[string]bool{"todo": true, "done": true, "later": true, "now": true, "doing": true}
+)
+
+// render writes a block tree as an indented markdown outline.
+func (b *outlineBlock) render(sb *strings.Builder, depth int) {
+	if b.Text != "" {
+		sb.WriteString(strings.Repeat("  ", depth) + "- " + strings.ReplaceAll(b.Text, "\n", "\n"+strings.Repeat("  ", depth+1)) + "\n")
+	}
+	for _, c := range b.Children {
+		c.render(sb, depth+1)
+	}
+}
+
+func (b *outlineBlock) walk(fn func(*outlineBlock)) {
+	fn(b)
+	for _, c := range b.Children {
+		c.walk(fn)
+	}
+}
+
+func renderBlocks(blocks []*outlineBlock) string {
+	var sb strings.Builder
+	for _, b := range blocks {
+		b.render(&sb, 0)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// pageLinks returns the pages a text links to via [[Page]], #[[Page]] or #tag.
+func pageLinks(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, l := range append(WikiLinks(text), InlineTags(text)...) {
+		key := strings.ToLower(l)
+		if !seen[key] && !outlineMarkers[key] {
+			seen[key] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// outlineBeats converts pages to beats. Entities come from outgoing links and
+// from backlinks, the pages whose blocks link to the page.
+func outlineBeats(source string, pages []*outlinePage, opts OutlineOptions) []*beat.ProposedBeat {
+	// Resolve ((block refs)) to the referenced text
+	blockText := make(map[string]string)
+	for _, p := range pages {
+		for _, b := range p.Blocks {
+			b.walk(func(b *outlineBlock) {
+				if b.UID != "" {
+					blockText[b.UID] = b.Text
+				}
+			})
+		}
+	}
+	for _, p := range pages {
+		for _, b := range p.Blocks {
+			b.walk(func(b *outlineBlock) {
+				b.Text = blockRefRegex.ReplaceAllStringFunc(b.Text, func(ref string) string {
+					if text, ok := blockText[ref[2:len(ref)-2]]; ok {
+						return text
+					}
+					return ref
+				})
+			})
+		}
+	}
+
+	backlinks := make(map[string][]string) // Lower-cased page title -> linking pages
+	for _, p := range pages {
+		for _, l := range pageLinks(renderBlocks(p.Blocks)) {
+			key := strings.ToLower(l)
+			if !strings.EqualFold(l, p.Title) {
+				backlinks[key] = append(backlinks[key], p.Title)
+			}
+		}
+	}
+	for key := range backlinks {
+		sort.Strings(backlinks[key])
+	}
+
+	label := strings.ToUpper(source[:1]) + source[1:] + " note"
+	var proposed []*beat.ProposedBeat
+	for _, p := range pages {
+		if !opts.Blocks {
+			content := renderBlocks(p.Blocks)
+			if content == "" {
+				continue
+			}
+			proposed = append(proposed, outlineBeat(source, label, p, p.SourceID, content, p.Created, backlinks[strings.ToLower(p.Title)]))
+			continue
+		}
+		for _, b := range p.Blocks {
+			content := renderBlocks([]*outlineBlock{b})
+			if content == "" {
+				continue
+			}
+			id := b.UID
+			if id == "" {
+				sum := sha256.Sum256([]byte(content))
+				id = hex.EncodeToString(sum[:6])
+			}
+			created := b.Created
+			if created == nil {
+				created = p.Created
+			}
+			// Blocks link back to their page rather than inheriting its backlinks
+			proposed = append(proposed, outlineBeat(source, label, p, p.SourceID+"#"+id, content, created, []string{p.Title}))
+		}
+	}
+	return proposed
+}
+
+func outlineBeat(source, label string, p *outlinePage, sourceID, content string, created *time.Time, backlinks []string) *beat.ProposedBeat {
+	meta := map[string]string{
+		"source":    source,
+		SourceIDKey: sourceID,
+		"page":      p.Title,
+	}
+	if p.Journal {
+		meta["journal"] = "true"
+	}
+	for k, v := range p.Props {
+		if _, taken := meta[k]; !taken {
+			meta[k] = v
+		}
+	}
+
+	entities := linkEntities(pageLinks(content), nil)
+	seen := make(map[string]bool)
+	for _, e := range entities {
+		seen[strings.ToLower(e.Label)] = true
+	}
+	for _, title := range backlinks {
+		if !seen[strings.ToLower(title)] {
+			seen[strings.ToLower(title)] = true
+			entities = append(entities, beat.Entity{
+				Label:    title,
+				Category: "topic",
+				Meta:     map[string]string{"confidence": "1.0", "source": "backlink"},
+			})
+		}
+	}
+
+	return &beat.ProposedBeat{
+		Content: content,
+		Impetus: beat.Impetus{
+			Label: label,
+			Raw:   p.Title,
+			Meta:  meta,
+		},
+		References:  URLReferences(content),
+		Entities:    entities,
+		LinkedBeads: []string{},
+		CreatedAt:   created,
+	}
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogseq(t *testing.T) {
+	graph := t.TempDir()
+	writeFile(t, filepath.Join(graph, "pages", "Beats.md"), "title:: Beats\ntype:: project\n\n- Capture layer for [[Pricing]] ideas\n  collapsed:: true\n\t- nested detail https://example.com\n- second block\n  id:: 65a1b2c3-0000-4000-8000-000000000001\n")
+	writeFile(t, filepath.Join(graph, "pages", "work___Pricing.md"), "- see ((65a1b2c3-0000-4000-8000-000000000001))\n")
+	writeFile(t, filepath.Join(graph, "journals", "2024_01_15.md"), "- TODO call about #Beats\n- Walked #[[Long Walks]]\n")
+	writeFile(t, filepath.Join(graph, "logseq", "config.edn"), "{}")
+
+	proposed, _, err := Logseq(graph, OutlineOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposed) != 3 {
+		t.Fatalf("got %d page beats, want 3", len(proposed))
+	}
+	byPage := make(map[string]int)
+	for i, p := range proposed {
+		byPage[p.Impetus.Raw] = i
+	}
+
+	beats := proposed[byPage["Beats"]]
+	if want := "- Capture layer for [[Pricing]] ideas\n  - nested detail https://example.com\n- second block"; beats.Content != want {
+		t.Errorf("content = %q, want %q", beats.Content, want)
+	}
+	if beats.Impetus.Meta["type"] != "project" || beats.Impetus.Meta[SourceIDKey] != "pages/Beats.md" {
+		t.Errorf("meta = %v", beats.Impetus.Meta)
+	}
+	got := make(map[string]string)
+	for _, e := range beats.Entities {
+		got[e.Label] = e.Meta["source"]
+	}
+	// Pricing is linked from here; the journal links back via #Beats
+	if got["Pricing"] != "wikilink" || got["Jan 15, 2024"] != "backlink" {
+		t.Errorf("entities = %v", got)
+	}
+	if len(beats.References) != 1 {
+		t.Errorf("references = %+v", beats.References)
+	}
+
+	pricing := proposed[byPage["work/Pricing"]]
+	if pricing.Content != "- see second block" {
+		t.Errorf("block ref not resolved: %q", pricing.Content)
+	}
+
+	journal := proposed[byPage["Jan 15, 2024"]]
+	if journal.CreatedAt == nil || journal.CreatedAt.Format("2006-01-02") != "2024-01-15" || journal.Impetus.Meta["journal"] != "true" {
+		t.Errorf("journal = %+v", journal)
+	}
+	for _, e := range journal.Entities {
+		if strings.EqualFold(e.Label, "todo") {
+			t.Errorf("TODO marker became an entity")
+		}
+	}
+
+	blocks, _, _ := Logseq(graph, OutlineOptions{Blocks: true})
+	if len(blocks) != 5 {
+		t.Fatalf("got %d block beats, want 5", len(blocks))
+	}
+	for _, b := range blocks {
+		if b.Impetus.Meta[SourceIDKey] == "pages/Beats.md#65a1b2c3-0000-4000-8000-000000000001" {
+			return
+		}
+	}
+	t.Error("block with id:: did not use it as source_id")
+}
+
+func TestRoam(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	writeFile(t, path, `[
+  {"title": "January 2nd, 2024", "uid": "01-02-2024", "children": [
+    {"string": "Met [[Ana]] about pricing", "uid": "aaa111", "create-time": 1704200000000,
+     "children": [{"string": "She prefers usage-based", "uid": "bbb222"}]}
+  ]},
+  {"title": "Ana", "uid": "pageAna", "create-time": 1700000000000, "children": [
+    {"string": "Founder at Acme", "uid": "ccc333"}
+  ]},
+  {"title": "Empty", "uid": "empty"}
+]`)
+
+	proposed, _, err := Roam(path, OutlineOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposed) != 2 {
+		t.Fatalf("got %d beats, want 2 (empty page skipped)", len(proposed))
+	}
+	daily, ana := proposed[0], proposed[1]
+	if daily.CreatedAt.Format("2006-01-02") != "2024-01-02" || daily.Impetus.Label != "Roam note" {
+		t.Errorf("daily = %+v", daily)
+	}
+	if daily.Content != "- Met [[Ana]] about pricing\n  - She prefers usage-based" {
+		t.Errorf("content = %q", daily.Content)
+	}
+	if len(ana.Entities) != 1 || ana.Entities[0].Label != "January 2nd, 2024" || ana.Entities[0].Meta["source"] != "backlink" {
+		t.Errorf("ana entities = %+v", ana.Entities)
+	}
+
+	blocks, _, _ := Roam(path, OutlineOptions{Blocks: true})
+	if len(blocks) != 2 || blocks[0].Impetus.Meta[SourceIDKey] != "01-02-2024#aaa111" {
+		t.Errorf("blocks = %+v", blocks)
+	}
+	if blocks[0].CreatedAt.UnixMilli() != 1704200000000 {
+		t.Errorf("block created_at = %v, want its create-time", blocks[0].CreatedAt)
+	}
+}
@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// roamNode is a page or block in a Roam JSON export.
+type roamNode struct {
+	Title      string     `json:"title"`
+	String     string     `json:"string"`
+	UID        string     `json:"uid"`
+	CreateTime int64      `json:"create-time"` // Unix milliseconds
+	EditTime   int64      `json:"edit-time"`
+	Children   []roamNode `json:"children"`
+}
+
+var roamDailyRegex = regexp.MustCompile(`^([A-Z][a-z]+) (\d{1,2})(?:st|nd|rd|th), (\d{4})$`)
+
+// Roam converts a Roam Research JSON export into beats. Daily pages are
+// dated by their title; other pages and blocks use their create-time.
+func Roam(path string, opts OutlineOptions) ([]*beat.ProposedBeat, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var nodes []roamNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, nil, fmt.Errorf("not a Roam JSON export: %w", err)
+	}
+
+	var pages []*outlinePage
+	for _, n := range nodes {
+		if n.Title == "" {
+			continue
+		}
+		page := &outlinePage{
+			Title:    n.Title,
+			SourceID: n.UID,
+			Created:  roamTime(n.CreateTime, n.EditTime),
+			Props:    map[string]string{},
+		}
+		if page.SourceID == "" {
+			page.SourceID = n.Title
+		}
+		if m := roamDailyRegex.FindStringSubmatch(n.Title); m != nil {
+			if t, err := time.Parse("January 2 2006", m[1]+" "+m[2]+" "+m[3]); err == nil {
+				page.Journal = true
+				page.Created = &t
+			}
+		}
+		for _, c := range n.Children {
+			page.Blocks = append(page.Blocks, roamBlock(c))
+		}
+		pages = append(pages, page)
+	}
+	return outlineBeats("roam", pages, opts), nil, nil
+}
+
+func roamBlock(n roamNode) *outlineBlock {
+	b := &outlineBlock{UID: n.UID, Text: n.String, Created: roamTime(n.CreateTime, n.EditTime)}
+	for _, c := range n.Children {
+		b.Children = append(b.Children, roamBlock(c))
+	}
+	return b
+}
+
+func roamTime(create, edit int64) *time.Time {
+	ms := create
+	if ms == 0 {
+		ms = edit
+	}
+	if ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
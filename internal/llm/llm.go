@@ -0,0 +1,414 @@
+// Package llm is the one client beats talks to Ollama through: embeddings,
+// generation and availability checks. Requests share connections, a busy
+// server is retried with backoff, a server that keeps failing is skipped for
+// a while instead of slowing every call, and each request can be logged.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/profile"
+)
+
+// Defaults for a new client.
+const (
+	DefaultTimeout       = 60 * time.Second // Per attempt
+	DefaultRetries       = 2                // After the first attempt
+	DefaultBackoff       = 250 * time.Millisecond
+	DefaultMaxConcurrent = 4 // Requests in flight per server
+)
+
+// The circuit opens after breakerThreshold failed calls in a row and stays
+// open for breakerCooldown, after which one call is let through to probe.
+const (
+	breakerThreshold = 3
+	breakerCooldown  = 30 * time.Second
+)
+
+// ErrUnavailable is returned without a request while the circuit for a
+// server is open.
+var ErrUnavailable = errors.New("ollama unavailable (circuit open after repeated failures)")
+
+// StatusError is a non-200 response.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("ollama returned status %d", e.Code)
+}
+
+// IsNotFound reports whether err is a 404, e.g. an endpoint an older Ollama
+// does not have.
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
+
+// transport is shared by every client so connections are reused.
+var transport = &http.Transport{
+	Proxy:               http.ProxyFromEnvironment,
+	DialContext:         (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	MaxIdleConnsPerHost: DefaultMaxConcurrent,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// server is the state shared by every client of one base URL.
+type server struct {
+	slots chan struct{} // Concurrency limit
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+var (
+	serversMu sync.Mutex
+	servers   = make(map[string]*server)
+)
+
+func serverFor(baseURL string) *server {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	s, ok := servers[baseURL]
+	if !ok {
+		s = &server{slots: make(chan struct{}, DefaultMaxConcurrent)}
+		servers[baseURL] = s
+	}
+	return s
+}
+
+// allow reports whether the circuit lets a call through.
+func (s *server) allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !time.Now().Before(s.openUntil)
+}
+
+// record notes a call's outcome, opening the circuit after enough failures.
+func (s *server) record(ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ok {
+		s.failures = 0
+		s.openUntil = time.Time{}
+		return
+	}
+	s.failures++
+	if s.failures >= breakerThreshold {
+		s.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+// Client talks to one Ollama server.
+type Client struct {
+	BaseURL string
+	Timeout time.Duration // Per attempt
+	Retries int
+	Backoff time.Duration // Doubled after each retry
+
+	http   *http.Client
+	server *server
+}
+
+// New returns a client for the Ollama server at baseURL.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		Timeout: DefaultTimeout,
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+		http:    &http.Client{Transport: transport},
+		server:  serverFor(baseURL),
+	}
+}
+
+// Default returns a client for the configured ollama_url.
+func Default() *Client {
+	return New(config.Get().OllamaURL)
+}
+
+// WithTimeout returns a copy of c with a different per-attempt timeout.
+func (c *Client) WithTimeout(d time.Duration) *Client {
+	cp := *c
+	cp.Timeout = d
+	return &cp
+}
+
+// Available reports whether the server answers. It is a single attempt: a
+// probe should not wait out retries.
+func (c *Client) Available(ctx context.Context) bool {
+	_, err := c.Models(ctx)
+	return err == nil
+}
+
+// Models lists the models the server has pulled, in one attempt.
+func (c *Client) Models(ctx context.Context) ([]string, error) {
+	if !c.server.allow() {
+		return nil, ErrUnavailable
+	}
+	var out struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	_, err := c.attempt(ctx, http.MethodGet, "/api/tags", nil, &out)
+	c.server.record(!serverFault(err))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(out.Models))
+	for _, m := range out.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+// HasModel reports whether model is among names, where a name without a
+// tag means :latest.
+func HasModel(names []string, model string) bool {
+	if !strings.Contains(model, ":") {
+		model += ":latest"
+	}
+	for _, n := range names {
+		if !strings.Contains(n, ":") {
+			n += ":latest"
+		}
+		if n == model {
+			return true
+		}
+	}
+	return false
+}
+
+// Embed returns the embedding of one text.
+func (c *Client) Embed(ctx context.Context, model, text string) ([]float64, error) {
+	var out struct {
+		Embedding []float64 `json:"embedding"`
+	}
+	if err := c.do(ctx, "/api/embeddings", map[string]string{"model": model, "prompt": text}, &out); err != nil {
+		return nil, err
+	}
+	return out.Embedding, nil
+}
+
+// EmbedBatch embeds several texts in one request. Servers without
+// /api/embed return an error IsNotFound recognizes.
+func (c *Client) EmbedBatch(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	var out struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := c.do(ctx, "/api/embed", map[string]interface{}{"model": model, "input": texts}, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(out.Embeddings), len(texts))
+	}
+	return out.Embeddings, nil
+}
+
+// Generate runs a prompt and returns the model's response.
+func (c *Client) Generate(ctx context.Context, model, prompt string) (string, error) {
+	var out struct {
+		Response string `json:"response"`
+	}
+	body := map[string]interface{}{"model": model, "prompt": prompt, "stream": false}
+	if err := c.do(ctx, "/api/generate", body, &out); err != nil {
+		return "", err
+	}
+	return out.Response, nil
+}
+
+// Pull downloads a model to the server. That can take minutes, so it is a
+// single attempt with no per-attempt timeout: ctx bounds it.
+func (c *Client) Pull(ctx context.Context, model string) error {
+	var out struct {
+		Status string `json:"status"`
+	}
+	pull := c.WithTimeout(0)
+	pull.Retries = 0
+	body := map[string]interface{}{"model": model, "name": model, "stream": false}
+	if err := pull.do(ctx, "/api/pull", body, &out); err != nil {
+		return err
+	}
+	if out.Status != "success" {
+		return fmt.Errorf("pulling %s: %s", model, out.Status)
+	}
+	return nil
+}
+
+// do POSTs a request, retrying while the server is busy, and decodes the
+// response into out.
+func (c *Client) do(ctx context.Context, path string, in, out interface{}) error {
+	if !c.server.allow() {
+		return ErrUnavailable
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	backoff := c.Backoff
+	for try := 0; ; try++ {
+		wait, err := c.attempt(ctx, http.MethodPost, path, body, out)
+		if err == nil || !retryable(err) || try >= c.Retries || ctx.Err() != nil {
+			c.server.record(!serverFault(err))
+			if ctx.Err() != nil {
+				err = context.Cause(ctx) // The caller's deadline, not this attempt's
+			}
+			if err != nil && !errors.As(err, new(*StatusError)) {
+				err = fmt.Errorf("ollama request failed: %w", err)
+			}
+			return err
+		}
+		if wait < backoff {
+			wait = backoff
+		}
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// attempt makes one request. It returns how long the server asked to wait
+// (Retry-After) along with any error.
+func (c *Client) attempt(ctx context.Context, method, path string, body []byte, out interface{}) (time.Duration, error) {
+	defer profile.Start(profile.Ollama)()
+	select {
+	case c.server.slots <- struct{}{}:
+		defer func() { <-c.server.slots }()
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	status := 0
+	var wait time.Duration
+	if err == nil {
+		defer func() { _ = resp.Body.Close() }()
+		status = resp.StatusCode
+		if status != http.StatusOK {
+			if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil {
+				wait = time.Duration(secs) * time.Second
+			}
+			err = &StatusError{Code: status}
+		} else if out != nil {
+			err = json.NewDecoder(resp.Body).Decode(out)
+		}
+	}
+	logRequest(method, path, status, time.Since(start), len(body), err)
+	return wait, err
+}
+
+// retryable reports whether an error means the server may answer if asked
+// again: it was busy, timed out or dropped the connection. A server that is
+// not running (connection refused) is not retried.
+func retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		switch se.Code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return isNetwork(err)
+}
+
+// serverFault reports whether an error counts against the server's circuit:
+// it failed to answer, or answered that it could not serve. A rejected
+// request or a caller giving up does not.
+func serverFault(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return retryable(err) || isNetwork(err)
+}
+
+// isNetwork reports whether err came from the connection rather than the
+// response.
+func isNetwork(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// LogEntry is one logged request.
+type LogEntry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status,omitempty"` // 0 when no response came back
+	DurationMS int64     `json:"duration_ms"`
+	Bytes      int       `json:"bytes"` // Request body size
+	Error      string    `json:"error,omitempty"`
+}
+
+var logMu sync.Mutex
+
+// logRequest appends an entry to the llm_log file, if one is configured.
+// Logging never fails a request.
+func logRequest(method, path string, status int, d time.Duration, size int, err error) {
+	target := config.Get().LLMLog
+	if target == "" {
+		return
+	}
+	entry := LogEntry{Time: clock.Now().UTC(), Method: method, Path: path, Status: status, DurationMS: d.Milliseconds(), Bytes: size}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	line, jerr := json.Marshal(entry)
+	if jerr != nil {
+		return
+	}
+	logMu.Lock()
+	defer logMu.Unlock()
+	if target == "-" {
+		fmt.Fprintln(os.Stderr, string(line))
+		return
+	}
+	f, ferr := os.OpenFile(config.ExpandHome(target), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if ferr != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.Write(append(line, '\n'))
+}
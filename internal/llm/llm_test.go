@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBusyServer(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"response": "  hello "})
+	}))
+	defer srv.Close()
+
+	logPath := filepath.Join(t.TempDir(), "llm.log")
+	t.Setenv("BEATS_LLM_LOG", logPath)
+
+	c := New(srv.URL)
+	c.Backoff = time.Millisecond
+	got, err := c.Generate(context.Background(), "m", "hi")
+	if err != nil || got != "  hello " {
+		t.Fatalf("Generate = %q, %v", got, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"status":503`) || !strings.Contains(lines[2], `"path":"/api/generate"`) {
+		t.Errorf("log = %q", data)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.Backoff = time.Millisecond
+	_, err := c.EmbedBatch(context.Background(), "m", []string{"a"})
+	if !IsNotFound(err) {
+		t.Errorf("err = %v, want a 404", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+	// Rejected requests do not open the circuit
+	for range breakerThreshold {
+		_, _ = c.Embed(context.Background(), "m", "a")
+	}
+	if !c.server.allow() {
+		t.Error("circuit opened on 404s")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.Retries = 0
+	for range breakerThreshold {
+		if _, err := c.Embed(context.Background(), "m", "a"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	_, err := c.Embed(context.Background(), "m", "a")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+	if calls.Load() != breakerThreshold {
+		t.Errorf("calls = %d, want %d: the open circuit still sent a request", calls.Load(), breakerThreshold)
+	}
+	// Another client of the same server shares the circuit
+	if New(srv.URL).Available(context.Background()) {
+		t.Error("Available with the circuit open")
+	}
+}
@@ -0,0 +1,191 @@
+// Package metrics exposes a store's health and Prometheus metrics over HTTP,
+// for `bt serve-capture` and `bt daemon --metrics-addr`.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// probeQuery is the full-text query timed on every scrape.
+const probeQuery = "beat"
+
+// Snapshot is the state of a store at one scrape.
+type Snapshot struct {
+	Beats            int
+	LinkedBeats      int
+	Embedded         int
+	SearchSeconds    float64        // Time the probe query took
+	IndexLagSeconds  float64        // How long a change has waited for the index
+	LastSync         time.Time      // When the index was last synced
+	HookRuns         map[string]int // By hook, from hooks.log
+	HookFailures     map[string]int
+	MalformedLines   int
+	CollectedAt      time.Time
+	CollectDuration  time.Duration
+	embeddingsFailed bool
+}
+
+// Collect reads the store, its index, embeddings and hook log.
+func Collect(jsonl *store.JSONLStore, now time.Time) (*Snapshot, error) {
+	start := time.Now()
+	snap := &Snapshot{CollectedAt: now, HookRuns: map[string]int{}, HookFailures: map[string]int{}}
+
+	beats, bad, err := jsonl.ReadAllTolerant()
+	if err != nil {
+		return nil, err
+	}
+	snap.Beats = len(beats)
+	snap.MalformedLines = len(bad)
+	for _, b := range beats {
+		if len(b.LinkedBeads) > 0 {
+			snap.LinkedBeats++
+		}
+	}
+
+	if embStore, err := embeddings.NewStore(jsonl.Dir()); err == nil {
+		for _, b := range beats {
+			if embStore.Has(b.ID) {
+				snap.Embedded++
+			}
+		}
+	} else {
+		snap.embeddingsFailed = true
+	}
+
+	entries, err := hooks.ReadHookLog(jsonl.Dir())
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		snap.HookRuns[e.Hook]++
+		if e.ExitCode != 0 {
+			snap.HookFailures[e.Hook]++
+		}
+	}
+
+	sqlite, err := store.NewSQLiteStore(jsonl)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlite.Close()
+	// Lag is measured before the probe, which brings the index up to date
+	if snap.LastSync, err = sqlite.LastSync(); err != nil {
+		return nil, err
+	}
+	if info, err := jsonl.Stat(); err == nil {
+		// last_sync has whole-second precision
+		if modified := info.ModTime(); modified.After(snap.LastSync.Add(time.Second)) {
+			snap.IndexLagSeconds = now.Sub(modified).Seconds()
+		}
+	}
+	probeStart := time.Now()
+	if _, err := sqlite.Search(probeQuery, 10); err != nil {
+		return nil, err
+	}
+	snap.SearchSeconds = time.Since(probeStart).Seconds()
+
+	snap.CollectDuration = time.Since(start)
+	return snap, nil
+}
+
+// EmbeddingCoverage is the fraction of beats with an embedding.
+func (s *Snapshot) EmbeddingCoverage() float64 {
+	if s.Beats == 0 {
+		return 1
+	}
+	return float64(s.Embedded) / float64(s.Beats)
+}
+
+// WriteText writes the snapshot in the Prometheus text exposition format.
+func (s *Snapshot) WriteText(w io.Writer) error {
+	var out strings.Builder
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(&out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	value := func(name string, v float64) {
+		fmt.Fprintf(&out, "%s %s\n", name, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	byHook := func(name string, counts map[string]int) {
+		names := make([]string, 0, len(counts))
+		for hook := range counts {
+			names = append(names, hook)
+		}
+		sort.Strings(names)
+		for _, hook := range names {
+			fmt.Fprintf(&out, "%s{hook=%q} %d\n", name, hook, counts[hook])
+		}
+	}
+
+	metric("beats_stored", "gauge", "Beats in the store.")
+	value("beats_stored", float64(s.Beats))
+	metric("beats_linked", "gauge", "Beats linked to at least one bead.")
+	value("beats_linked", float64(s.LinkedBeats))
+	metric("beats_malformed_lines", "gauge", "Lines of beats.jsonl that could not be parsed.")
+	value("beats_malformed_lines", float64(s.MalformedLines))
+	if !s.embeddingsFailed {
+		metric("beats_embedded", "gauge", "Beats with an embedding.")
+		value("beats_embedded", float64(s.Embedded))
+		metric("beats_embedding_coverage_ratio", "gauge", "Fraction of beats with an embedding.")
+		value("beats_embedding_coverage_ratio", s.EmbeddingCoverage())
+	}
+	metric("beats_search_probe_seconds", "gauge", "Time a full-text search of the index took during this scrape.")
+	value("beats_search_probe_seconds", s.SearchSeconds)
+	metric("beats_index_lag_seconds", "gauge", "How long the newest change to beats.jsonl has waited for the search index (0 when in sync).")
+	value("beats_index_lag_seconds", s.IndexLagSeconds)
+	if !s.LastSync.IsZero() {
+		metric("beats_index_last_sync_timestamp_seconds", "gauge", "Unix time the search index was last synced.")
+		value("beats_index_last_sync_timestamp_seconds", float64(s.LastSync.Unix()))
+	}
+	metric("beats_hook_runs_total", "counter", "Hook script runs recorded in hooks.log.")
+	byHook("beats_hook_runs_total", s.HookRuns)
+	metric("beats_hook_failures_total", "counter", "Hook script runs that failed or timed out.")
+	byHook("beats_hook_failures_total", s.HookFailures)
+	metric("beats_scrape_duration_seconds", "gauge", "Time collecting these metrics took.")
+	value("beats_scrape_duration_seconds", s.CollectDuration.Seconds())
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Handler serves GET /metrics for the store.
+func Handler(jsonl *store.JSONLStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap, err := Collect(jsonl, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = snap.WriteText(w)
+	})
+}
+
+// HealthHandler serves GET /healthz: 200 with "ok" when beats.jsonl can be
+// read, 503 with the error otherwise.
+func HealthHandler(jsonl *store.JSONLStore) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := jsonl.ReadAll(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "unhealthy: %v\n", err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// Register adds /healthz and /metrics to mux.
+func Register(mux *http.ServeMux, jsonl *store.JSONLStore) {
+	mux.Handle("/healthz", HealthHandler(jsonl))
+	mux.Handle("/metrics", Handler(jsonl))
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+func TestMetricsAndHealth(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	linked := &beat.Beat{ID: "beat-20260101-001", CreatedAt: now, UpdatedAt: now, Content: "a linked beat"}
+	linked.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1"})
+	if err := s.AppendBulk([]*beat.Beat{linked,
+		{ID: "beat-20260101-002", CreatedAt: now, UpdatedAt: now, Content: "another beat"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	log := `{"hook":"on_beat_created","exit_code":0}
+{"hook":"on_beat_created","exit_code":1}
+{"hook":"on_synthesis_needed","exit_code":-1}
+`
+	if err := os.WriteFile(filepath.Join(dir, hooks.HookLogFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler(s).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != 200 {
+		t.Fatalf("/metrics status %d: %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE beats_stored gauge",
+		"beats_stored 2\n",
+		"beats_linked 1\n",
+		"beats_embedding_coverage_ratio 0\n",
+		`beats_hook_runs_total{hook="on_beat_created"} 2`,
+		`beats_hook_failures_total{hook="on_beat_created"} 1`,
+		`beats_hook_failures_total{hook="on_synthesis_needed"} 1`,
+		"beats_search_probe_seconds ",
+		"beats_index_lag_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics is missing %q:\n%s", want, body)
+		}
+	}
+
+	// The probe synced the index, so a second scrape shows no lag
+	snap, err := Collect(s, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.IndexLagSeconds != 0 || snap.LastSync.IsZero() {
+		t.Errorf("after sync: lag %v, last sync %v", snap.IndexLagSeconds, snap.LastSync)
+	}
+
+	rec = httptest.NewRecorder()
+	HealthHandler(s).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != 200 || strings.TrimSpace(rec.Body.String()) != "ok" {
+		t.Errorf("/healthz = %d %q", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beads"
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Check is the outcome of the semantic checks run on a proposed beat:
+// duplicate detection and bead link suggestions. Both share one embedding.
+type Check struct {
+	Duplicates     []embeddings.Duplicate
+	SuggestedLinks []beads.Suggestion
+	Reject         bool
+	Thresholds     map[string]float64 // Applied similarity cutoffs by hook name
+
+	embStore  *embeddings.Store
+	embedding []float64
+}
+
+// checkProposed runs the duplicates and link_suggestions hooks for a proposed
+// beat. It returns nil when neither is enabled or Ollama is unavailable.
+func checkProposed(s *store.JSONLStore, p *beat.ProposedBeat) *Check {
+	dupConfig := hooks.GetDuplicatesConfig(s.Dir())
+	linkConfig := hooks.GetLinkSuggestionsConfig(s.Dir())
+	if !dupConfig.Enabled && !linkConfig.Enabled {
+		return nil
+	}
+
+	embStore, err := embeddings.NewStore(s.Dir())
+	if err != nil {
+		return nil
+	}
+	ollama := embeddings.NewOllamaClient()
+	ollama.SetModel(embStore.Model())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	proposedBeat := beat.Beat{Content: p.Content, Impetus: p.Impetus}
+	emb, err := ollama.GetEmbedding(ctx, embeddings.BeatText(proposedBeat))
+	if err != nil {
+		return nil
+	}
+	check := &Check{embStore: embStore, Thresholds: map[string]float64{}}
+	if len(embeddings.BeatChunks(proposedBeat)) == 1 {
+		// Long beats are left for `beats embed` to store chunk by chunk
+		check.embedding = emb
+	}
+
+	if dupConfig.Enabled {
+		check.Thresholds["duplicates"] = dupConfig.Threshold
+	}
+	if linkConfig.Enabled {
+		check.Thresholds["link_suggestions"] = linkConfig.Threshold
+	}
+
+	if dupConfig.Enabled && embStore.Count() > 0 {
+		if all, err := s.ReadAll(); err == nil {
+			check.Duplicates = embeddings.FindDuplicates(emb, all, embStore, dupConfig.Threshold, dupConfig.MaxResults)
+			check.Reject = dupConfig.Action == "reject" && len(check.Duplicates) > 0
+		}
+	}
+
+	if linkConfig.Enabled {
+		check.SuggestedLinks = suggestLinks(ctx, s.Dir(), emb, ollama, linkConfig.Threshold, linkConfig.MaxResults, p.LinkedBeads)
+	}
+	return check
+}
+
+// suggestLinks ranks cached beads against a beat embedding.
+func suggestLinks(ctx context.Context, beatsDir string, emb []float64, ollama *embeddings.OllamaClient, threshold float64, limit int, exclude []string) []beads.Suggestion {
+	list, err := beads.LoadCache(beatsDir)
+	if err != nil || len(list) == 0 {
+		return nil
+	}
+	// Beads not embedded before the deadline are left for the next capture
+	vectors, _ := beads.Embeddings(ctx, beatsDir, list, ollama)
+	return beads.Suggest(emb, list, vectors, threshold, limit, exclude)
+}
+
+// storeEmbedding saves the vector computed during the check for the new beat,
+// so it does not need to be embedded again.
+func (c *Check) storeEmbedding(beatID string) {
+	if c == nil || c.embedding == nil {
+		return
+	}
+	_ = c.embStore.Store(beatID, c.embedding)
+}
+
+func (c *Check) error() error {
+	top := c.Duplicates[0]
+	return fmt.Errorf("possible duplicate of %s (similarity %.2f); beat not saved", top.ID, top.Score)
+}
@@ -0,0 +1,131 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// fakeEmbedder serves Ollama embeddings that place text by whether it
+// mentions a queue or billing, so similarity is known in advance.
+func fakeEmbedder(t *testing.T) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct{ Prompt string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		text := strings.ToLower(req.Prompt)
+		vec := []float64{0, 0, 0.1}
+		if strings.Contains(text, "queue") {
+			vec[0] = 1
+		}
+		if strings.Contains(text, "billing") {
+			vec[1] = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]float64{"embedding": vec})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("BEATS_OLLAMA_URL", server.URL)
+}
+
+func TestDuplicateCheck(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	setup := func(t *testing.T, action string) *store.JSONLStore {
+		t.Helper()
+		s, err := store.NewJSONLStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		config := `{"duplicates": {"enabled": true, "threshold": 0.9, "action": "` + action + `"}}`
+		if err := os.WriteFile(filepath.Join(s.Dir(), hooks.HooksConfigFile), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		existing := &beat.Beat{ID: "beat-20260301-001", Content: "Shard the job queue by tenant"}
+		if err := s.Append(existing); err != nil {
+			t.Fatal(err)
+		}
+		emb, err := embeddings.NewStore(s.Dir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := emb.Store(existing.ID, []float64{1, 0, 0.1}); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	capture := func(s *store.JSONLStore, content string) (*beat.Beat, *Run, error) {
+		return Capture(context.Background(), s, &beat.ProposedBeat{Content: content, Impetus: beat.Impetus{Label: "Note"}}, Options{Channel: beat.ChannelManual})
+	}
+
+	t.Run("reject above the threshold", func(t *testing.T) {
+		fakeEmbedder(t)
+		s := setup(t, "reject")
+		b, _, err := capture(s, "Split the queue per tenant")
+		var dup *DuplicateError
+		if !errors.As(err, &dup) || b != nil {
+			t.Fatalf("capture = %v, %v; want a *DuplicateError", b, err)
+		}
+		if len(dup.Check.Duplicates) != 1 || dup.Check.Duplicates[0].ID != "beat-20260301-001" || dup.Check.Thresholds["duplicates"] != 0.9 {
+			t.Errorf("check = %+v", dup.Check)
+		}
+		if all, _ := s.ReadAll(); len(all) != 1 {
+			t.Errorf("%d beats stored, want the rejected one left out", len(all))
+		}
+	})
+
+	t.Run("warn above the threshold", func(t *testing.T) {
+		fakeEmbedder(t)
+		s := setup(t, "warn")
+		b, run, err := capture(s, "Split the queue per tenant")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if run.Check == nil || len(run.Check.Duplicates) != 1 || run.Check.Duplicates[0].Score < 0.9 {
+			t.Errorf("check = %+v, want the existing beat as a duplicate", run.Check)
+		}
+		if _, err := s.Get(b.ID); err != nil {
+			t.Errorf("warned beat not stored: %v", err)
+		}
+	})
+
+	t.Run("below the threshold", func(t *testing.T) {
+		fakeEmbedder(t)
+		s := setup(t, "reject")
+		_, run, err := capture(s, "Billing for the queue workers") // Similarity 0.71
+		if err != nil {
+			t.Fatal(err)
+		}
+		if run.Check == nil || len(run.Check.Duplicates) != 0 {
+			t.Errorf("check = %+v, want no duplicates", run.Check)
+		}
+	})
+
+	t.Run("embedder unavailable", func(t *testing.T) {
+		t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+		s := setup(t, "reject")
+		b, run, err := capture(s, "Split the queue per tenant")
+		if err != nil {
+			t.Fatalf("capture without Ollama = %v, want it stored", err)
+		}
+		if run.Check != nil {
+			t.Errorf("check = %+v, want none without an embedding", run.Check)
+		}
+		if _, err := s.Get(b.ID); err != nil {
+			t.Error(err)
+		}
+	})
+}
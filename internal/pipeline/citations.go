@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// linkCitations turns the [[beat-id]] citations in a proposed beat into beat
+// references and returns the cited IDs that are not in the store. Those are
+// still linked, since the beat may be imported or restored later.
+func linkCitations(s *store.JSONLStore, p *beat.ProposedBeat) []string {
+	cited := beat.Citations(p.Content)
+	if len(cited) == 0 {
+		return nil
+	}
+	p.References = beat.LinkCitations(p.References, p.Content)
+	found, err := s.GetByIDs(cited)
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]bool, len(found))
+	for _, b := range found {
+		known[b.ID] = true
+	}
+	var unknown []string
+	for _, id := range cited {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
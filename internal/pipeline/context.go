@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"path/filepath"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+	"github.com/bierlingm/beats/internal/wald"
+)
+
+// Context inference methods, in the order they are tried.
+const (
+	InferenceManual    = "manual"
+	InferenceWorkspace = "session_workspace"
+	InferenceLocation  = "capture_location"
+	InferenceSemantic  = "semantic"
+)
+
+// semanticContext infers a WALD directory from content; replaced in tests.
+var semanticContext = func(beatsDir, werkRoot, content string) (string, float64) {
+	inference := capture.NewSemanticInference(werkRoot)
+	if scoring, err := store.LoadScoringConfig(beatsDir); err == nil {
+		inference.SetMinScore(scoring.ContextInferenceMin)
+	}
+	inferred, err := inference.InferContext(content)
+	if err != nil || inferred == nil {
+		return "", 0
+	}
+	return inferred.WALDDirectory, inferred.Confidence
+}
+
+// backgroundChannels are the capture channels whose beats come from a
+// long-running process (bt serve, bt watch, the daemon, the session hook),
+// so the working directory says nothing about what they are about.
+var backgroundChannels = map[string]bool{
+	beat.ChannelSession:   true,
+	beat.ChannelWeb:       true,
+	beat.ChannelWebhook:   true,
+	beat.ChannelDrop:      true,
+	beat.ChannelGenerated: true,
+}
+
+// inferContext fills in where a beat was captured and which WALD directory
+// it belongs to. A context that already names a directory (--context, or
+// one passed to --robot-commit-beat) is kept as a manual assignment.
+// Otherwise the directory is the entry whose workspace a session beat came
+// from, then the entry containing the working directory (unless the beat
+// came in on a background channel), then the entry whose purpose is
+// semantically closest to the content.
+func inferContext(beatsDir string, p *beat.ProposedBeat, cwd string) *beat.Context {
+	ctx := &beat.Context{CapturePath: cwd}
+	if p.Context != nil {
+		*ctx = *p.Context
+		if ctx.CapturePath == "" {
+			ctx.CapturePath = cwd
+		}
+		if ctx.WALDDirectory != "" {
+			ctx.WALDDirectory = filepath.ToSlash(filepath.Clean(ctx.WALDDirectory))
+			if ctx.InferenceMethod == "" {
+				ctx.InferenceMethod = InferenceManual
+			}
+			if ctx.Confidence == 0 {
+				ctx.Confidence = 1.0
+			}
+			return ctx
+		}
+	}
+
+	werkRoot := wald.FindWerkRoot(ctx.CapturePath)
+	if werkRoot == "" {
+		return ctx
+	}
+	cfg, err := wald.Load(werkRoot)
+	if err != nil {
+		return ctx
+	}
+
+	// A session beat names its workspace: the session's encoded working
+	// directory. It says more than where bt happens to run.
+	if workspace := p.Impetus.Meta["workspace"]; workspace != "" {
+		for _, d := range cfg.Directories {
+			if hooks.EncodeWorkspace(filepath.Join(werkRoot, d.Path)) == workspace {
+				ctx.WALDDirectory, ctx.InferenceMethod, ctx.Confidence = d.Path, InferenceWorkspace, 0.9
+				return ctx
+			}
+		}
+	}
+
+	// The working directory is inside a listed directory
+	if rel, ok := wald.RelativeTo(werkRoot, ctx.CapturePath); ok && !backgroundChannels[p.Impetus.Meta[beat.ChannelMeta]] {
+		if d := cfg.Owner(rel); d != nil {
+			ctx.WALDDirectory, ctx.InferenceMethod, ctx.Confidence = d.Path, InferenceLocation, 1.0
+			if rel != filepath.ToSlash(filepath.Clean(d.Path)) {
+				ctx.Confidence = 0.9 // A subdirectory of the entry
+			}
+			return ctx
+		}
+	}
+
+	if dir, score := semanticContext(beatsDir, werkRoot, p.Content); dir != "" {
+		ctx.WALDDirectory, ctx.InferenceMethod, ctx.Confidence = dir, InferenceSemantic, score
+	}
+	return ctx
+}
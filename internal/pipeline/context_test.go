@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/wald"
+)
+
+func TestInferContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BEATS_ROOT", "")
+	werk := t.TempDir()
+	manifest := `directories:
+  - path: projects/beats
+    purpose: Narrative substrate
+  - {path: projects, purpose: All projects}
+  - path: cooperators/jane
+    purpose: Coaching
+`
+	if err := os.WriteFile(filepath.Join(werk, "WALD.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(werk, "projects", "beats", "internal")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var semanticCalls int
+	orig := semanticContext
+	semanticContext = func(beatsDir, werkRoot, content string) (string, float64) {
+		semanticCalls++
+		if werkRoot != werk {
+			t.Errorf("semantic werk root = %q, want %q", werkRoot, werk)
+		}
+		return "cooperators/jane", 0.42
+	}
+	t.Cleanup(func() { semanticContext = orig })
+
+	tests := []struct {
+		name       string
+		proposed   beat.ProposedBeat
+		cwd        string
+		dir        string
+		method     string
+		confidence float64
+	}{
+		{
+			name:     "manual",
+			proposed: beat.ProposedBeat{Context: &beat.Context{WALDDirectory: "cooperators/jane/"}},
+			cwd:      nested,
+			dir:      "cooperators/jane", method: InferenceManual, confidence: 1.0,
+		},
+		{
+			name: "cwd is the entry",
+			cwd:  filepath.Join(werk, "projects", "beats"),
+			dir:  "projects/beats", method: InferenceLocation, confidence: 1.0,
+		},
+		{
+			name: "cwd below the most specific entry",
+			cwd:  nested,
+			dir:  "projects/beats", method: InferenceLocation, confidence: 0.9,
+		},
+		{
+			name: "session workspace",
+			proposed: beat.ProposedBeat{Impetus: beat.Impetus{Meta: map[string]string{
+				"workspace": hooks.EncodeWorkspace(filepath.Join(werk, "cooperators", "jane")),
+			}}},
+			cwd: werk,
+			dir: "cooperators/jane", method: InferenceWorkspace, confidence: 0.9,
+		},
+		{
+			name: "session workspace over the working directory",
+			proposed: beat.ProposedBeat{Impetus: beat.Impetus{Meta: map[string]string{
+				"workspace":      hooks.EncodeWorkspace(filepath.Join(werk, "cooperators", "jane")),
+				beat.ChannelMeta: beat.ChannelSession,
+			}}},
+			cwd: nested,
+			dir: "cooperators/jane", method: InferenceWorkspace, confidence: 0.9,
+		},
+		{
+			name:     "background channel ignores the working directory",
+			proposed: beat.ProposedBeat{Impetus: beat.Impetus{Meta: map[string]string{beat.ChannelMeta: beat.ChannelWeb}}},
+			cwd:      nested,
+			dir:      "cooperators/jane", method: InferenceSemantic, confidence: 0.42,
+		},
+		{
+			name: "semantic",
+			cwd:  werk,
+			dir:  "cooperators/jane", method: InferenceSemantic, confidence: 0.42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			semanticCalls = 0
+			got := inferContext(t.TempDir(), &tt.proposed, tt.cwd)
+			if got.CapturePath != tt.cwd || got.WALDDirectory != tt.dir || got.InferenceMethod != tt.method || got.Confidence != tt.confidence {
+				t.Errorf("inferContext = %+v, want %s via %s (%.2f) from %s", got, tt.dir, tt.method, tt.confidence, tt.cwd)
+			}
+			if tt.method != InferenceSemantic && semanticCalls != 0 {
+				t.Errorf("semantic inference ran for a %s match", tt.method)
+			}
+		})
+	}
+
+	// Outside any werk root only the capture path is recorded
+	outside := t.TempDir()
+	if got := inferContext(t.TempDir(), &beat.ProposedBeat{}, outside); got.CapturePath != outside || got.WALDDirectory != "" {
+		t.Errorf("outside werk: %+v", got)
+	}
+
+	if got := wald.ResolveDir(nested); got != "projects/beats/internal" {
+		t.Errorf("wald.ResolveDir(path) = %q", got)
+	}
+	if got := wald.ResolveDir("cooperators/jane"); got != "cooperators/jane" {
+		t.Errorf("wald.ResolveDir(listed) = %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/entity"
+)
+
+// ExtractorKey is the entity meta key naming what found an entity.
+const ExtractorKey = "extractor"
+
+// llmEntityConfidence is recorded for entities the LLM names.
+const llmEntityConfidence = "0.80"
+
+// ExtractionMode checks an --extract value, falling back to the configured
+// entity_extraction when it is empty.
+func ExtractionMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "":
+		return config.Get().EntityExtraction(), nil
+	case config.EntitiesHeuristic, config.EntitiesLLM, config.EntitiesOff:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid extraction %q: use %s, %s or %s", mode, config.EntitiesHeuristic, config.EntitiesLLM, config.EntitiesOff)
+}
+
+// extractEntities adds the entities found in a beat's content to the ones
+// it already has and returns those it added. mode is heuristic, llm or off,
+// empty for the entity_extraction setting. Labels are resolved through the
+// store's alias registry. Entities the caller gave are kept as they are, and
+// generated beats (digests, reviews) restate other beats and are left alone.
+// When the LLM pass fails, the heuristic entities are still added and the
+// error is returned.
+func extractEntities(ctx context.Context, beatsDir string, p *beat.ProposedBeat, mode string) ([]beat.Entity, error) {
+	mode, err := ExtractionMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if p.Impetus.Meta["kind"] != "" || mode == config.EntitiesOff {
+		return nil, nil
+	}
+	aliases, err := entity.LoadAliases(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	found := entity.ExtractEntities(p.Content, "")
+	for i := range found {
+		if found[i].Meta == nil {
+			found[i].Meta = make(map[string]string)
+		}
+		found[i].Meta[ExtractorKey] = config.EntitiesHeuristic
+	}
+	added := mergeEntities(p, resolveAliases(aliases, found))
+	if mode != config.EntitiesLLM {
+		return added, nil
+	}
+
+	typed, relations, err := extractWithLLM(ctx, beatsDir, p.Content)
+	if err != nil {
+		return added, fmt.Errorf("LLM entity extraction failed: %w", err)
+	}
+	added = append(added, mergeTyped(p, resolveAliases(aliases, typed))...)
+	p.Relations = append(p.Relations, resolveRelations(aliases, p.Entities, relations)...)
+	return added, nil
+}
+
+// mergeEntities appends the entities not yet on the beat, comparing
+// category and label case-insensitively, and returns them.
+func mergeEntities(p *beat.ProposedBeat, found []beat.Entity) []beat.Entity {
+	seen := make(map[string]bool, len(p.Entities))
+	for _, e := range p.Entities {
+		seen[entity.Key(e)] = true
+	}
+	var added []beat.Entity
+	for _, e := range found {
+		if key := entity.Key(e); !seen[key] {
+			seen[key] = true
+			added = append(added, e)
+		}
+	}
+	p.Entities = append(p.Entities, added...)
+	return added
+}
+
+// mergeTyped merges typed entities from the LLM: a label the beat already
+// has is not added again, but a heuristic topic takes the LLM's category.
+func mergeTyped(p *beat.ProposedBeat, typed []beat.Entity) []beat.Entity {
+	var rest []beat.Entity
+	for _, e := range typed {
+		known := false
+		for i := range p.Entities {
+			have := &p.Entities[i]
+			if !strings.EqualFold(have.Label, e.Label) {
+				continue
+			}
+			known = true
+			if have.Category == "topic" && have.Meta[ExtractorKey] == config.EntitiesHeuristic {
+				have.Category = e.Category
+				have.Meta[ExtractorKey] = config.EntitiesLLM
+			}
+		}
+		if !known {
+			rest = append(rest, e)
+		}
+	}
+	return mergeEntities(p, rest)
+}
+
+// resolveAliases replaces each label with its canonical form, keeping the
+// name found in meta alias.
+func resolveAliases(aliases entity.Aliases, found []beat.Entity) []beat.Entity {
+	for i, e := range found {
+		if canonical, ok := aliases.Canonical(e.Label); ok && canonical != e.Label {
+			if found[i].Meta == nil {
+				found[i].Meta = make(map[string]string)
+			}
+			found[i].Meta["alias"] = e.Label
+			found[i].Label = canonical
+		}
+	}
+	return found
+}
+
+// resolveRelations keeps the relations between entities the beat has,
+// using their labels as stored.
+func resolveRelations(aliases entity.Aliases, entities []beat.Entity, relations []beat.Relation) []beat.Relation {
+	label := func(name string) string {
+		name, _ = aliases.Canonical(name)
+		for _, e := range entities {
+			if strings.EqualFold(e.Label, name) {
+				return e.Label
+			}
+		}
+		return ""
+	}
+	var out []beat.Relation
+	for _, r := range relations {
+		from, to := label(r.From), label(r.To)
+		kind := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(r.Type)), " ", "_")
+		if from != "" && to != "" && from != to && kind != "" {
+			out = append(out, beat.Relation{From: from, To: to, Type: kind})
+		}
+	}
+	return out
+}
+
+const llmEntityPrompt = `Extract the named entities in this note and how they relate to each other.
+
+NOTE:
+%s
+
+Reply with a JSON object only, no other text:
+{"entities": [{"label": "name as written", "category": "person|organization|project|tool|place|concept"}],
+ "relations": [{"from": "entity label", "to": "entity label", "type": "works_at|works_with|uses|part_of|about"}]}
+Only include entities that are named in the note.`
+
+// extractWithLLM asks the configured LLM for the typed entities and the
+// relations in content.
+func extractWithLLM(ctx context.Context, beatsDir, content string) ([]beat.Entity, []beat.Relation, error) {
+	text, model, err := llmGenerate(ctx, beatsDir, fmt.Sprintf(llmEntityPrompt, content))
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, nil, fmt.Errorf("no JSON in the model's reply")
+	}
+	var reply struct {
+		Entities  []beat.Entity   `json:"entities"`
+		Relations []beat.Relation `json:"relations"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &reply); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON in the model's reply: %w", err)
+	}
+	var entities []beat.Entity
+	for _, e := range reply.Entities {
+		e.Label = strings.TrimSpace(e.Label)
+		e.Category = strings.ToLower(strings.TrimSpace(e.Category))
+		if e.Label == "" || e.Category == "" {
+			continue
+		}
+		e.Meta = map[string]string{"confidence": llmEntityConfidence, ExtractorKey: config.EntitiesLLM, "model": model}
+		entities = append(entities, e)
+	}
+	return entities, reply.Relations, nil
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/entity"
+)
+
+func TestExtractEntities(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	dir := t.TempDir()
+
+	p := &beat.ProposedBeat{
+		Content:  "met Ada Lovelace about https://example.com/engine",
+		Entities: []beat.Entity{{Label: "ada lovelace", Category: "topic"}},
+	}
+	added, err := extractEntities(context.Background(), dir, p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0].Category != "url" || added[0].Meta[ExtractorKey] != "heuristic" || added[0].Meta["confidence"] == "" {
+		t.Errorf("added = %+v; want only the URL", added)
+	}
+	if len(p.Entities) != 2 || p.Entities[0].Label != "ada lovelace" {
+		t.Errorf("entities = %+v; want the given one kept first", p.Entities)
+	}
+
+	digest := &beat.ProposedBeat{Content: p.Content, Impetus: beat.Impetus{Meta: map[string]string{"kind": "digest"}}}
+	if added, _ := extractEntities(context.Background(), dir, digest, ""); len(added) != 0 {
+		t.Errorf("digest got entities %+v", added)
+	}
+	if added, _ := extractEntities(context.Background(), dir, &beat.ProposedBeat{Content: p.Content}, "off"); len(added) != 0 {
+		t.Errorf("extract off added %+v", added)
+	}
+	if _, err := extractEntities(context.Background(), dir, &beat.ProposedBeat{Content: p.Content}, "regex"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+
+	t.Setenv("BEATS_ENTITY_EXTRACTION", "off")
+	if added, _ := extractEntities(context.Background(), dir, &beat.ProposedBeat{Content: p.Content}, ""); len(added) != 0 {
+		t.Errorf("entity_extraction off added %+v", added)
+	}
+}
+
+func TestExtractEntitiesLLM(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	defer func(f func(context.Context, string, string) (string, string, error)) { llmGenerate = f }(llmGenerate)
+	llmGenerate = func(context.Context, string, string) (string, string, error) {
+		return "Here you go:\n```json\n" + `{"entities": [
+			{"label": "Grace Hopper", "category": "Person"},
+			{"label": "Navy", "category": "organization"},
+			{"label": "Ada", "category": "person"},
+			{"label": "", "category": "tool"}],
+		 "relations": [
+			{"from": "Grace Hopper", "to": "Navy", "type": "works at"},
+			{"from": "Grace Hopper", "to": "COBOL", "type": "created"}]}` + "\n```", "test-model", nil
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, entity.AliasesFile), []byte(`{"Ada Lovelace": ["Ada"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &beat.ProposedBeat{Content: "lunch with Grace Hopper and Ada, who joined the Navy"}
+	if _, err := extractEntities(context.Background(), dir, p, "llm"); err != nil {
+		t.Fatal(err)
+	}
+	byLabel := make(map[string]beat.Entity)
+	for _, e := range p.Entities {
+		if _, dup := byLabel[e.Label]; dup {
+			t.Errorf("%q extracted twice: %+v", e.Label, p.Entities)
+		}
+		byLabel[e.Label] = e
+	}
+	// The heuristic topic takes the LLM's category
+	if e := byLabel["Grace Hopper"]; e.Category != "person" || e.Meta[ExtractorKey] != "llm" {
+		t.Errorf("Grace Hopper = %+v", e)
+	}
+	if e := byLabel["Ada Lovelace"]; e.Category != "person" || e.Meta["alias"] != "Ada" || e.Meta["model"] != "test-model" {
+		t.Errorf("Ada Lovelace = %+v", e)
+	}
+	if _, ok := byLabel["Navy"]; !ok {
+		t.Errorf("entities = %+v; want Navy", p.Entities)
+	}
+	want := beat.Relation{From: "Grace Hopper", To: "Navy", Type: "works_at"}
+	if len(p.Relations) != 1 || p.Relations[0] != want {
+		t.Errorf("relations = %+v; want only %+v", p.Relations, want)
+	}
+
+	// Without the LLM, the heuristic entities are kept
+	llmGenerate = func(context.Context, string, string) (string, string, error) { return "", "", os.ErrDeadlineExceeded }
+	p = &beat.ProposedBeat{Content: "see https://example.com"}
+	if added, err := extractEntities(context.Background(), dir, p, "llm"); err == nil || len(added) != 1 {
+		t.Errorf("extractEntities(LLM down) = %+v, %v; want the URL and an error", added, err)
+	}
+}
@@ -0,0 +1,34 @@
+package pipeline
+
+import (
+	"strconv"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/impetus"
+)
+
+// ImpetusConfidenceKey is the impetus.meta key recording how sure inference
+// was of a label: 1 for a specific pattern, 0.5 for a generic URL, 0 when
+// nothing matched and the fallback was used. Labels given by the caller
+// have no confidence recorded.
+const ImpetusConfidenceKey = "impetus_confidence"
+
+// DefaultImpetus labels a beat whose content matches no inference pattern.
+const DefaultImpetus = "Manual entry"
+
+// inferImpetus fills in an empty label from the content, leaving a label
+// the caller gave untouched.
+func inferImpetus(imp *beat.Impetus, content string) {
+	if imp.Label != "" {
+		return
+	}
+	label, confidence := impetus.InferWithConfidence(content)
+	if label == "" {
+		label = DefaultImpetus
+	}
+	imp.Label = label
+	if imp.Meta == nil {
+		imp.Meta = make(map[string]string)
+	}
+	imp.Meta[ImpetusConfidenceKey] = strconv.FormatFloat(confidence, 'f', -1, 64)
+}
@@ -0,0 +1,24 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestInferImpetus(t *testing.T) {
+	for _, tt := range []struct {
+		label, content, want, confidence string
+	}{
+		{"", "https://github.com/bierlingm/beats looks useful", "GitHub discovery", "1"},
+		{"", "read https://example.com/post", "Web discovery", "0.5"},
+		{"", "just a thought", DefaultImpetus, "0"},
+		{"Call with Ada", "https://github.com/x/y", "Call with Ada", ""}, // Given labels win
+	} {
+		imp := beat.Impetus{Label: tt.label}
+		inferImpetus(&imp, tt.content)
+		if imp.Label != tt.want || imp.Meta[ImpetusConfidenceKey] != tt.confidence {
+			t.Errorf("inferImpetus(%q, %q) = %q %v; want %q with confidence %q", tt.label, tt.content, imp.Label, imp.Meta, tt.want, tt.confidence)
+		}
+	}
+}
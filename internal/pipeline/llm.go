@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Generate runs a prompt through the LLM configured for session summaries
+// and returns the model used. A remote server gets the prompt redacted.
+func Generate(ctx context.Context, beatsDir, prompt string) (string, string, error) {
+	llm := hooks.GetSessionEndConfig(beatsDir)
+	prompt, err := redactPrompt(beatsDir, llm.OllamaURL, prompt)
+	if err != nil {
+		return "", llm.OllamaModel, err
+	}
+	summary, err := llm.GenerateContext(ctx, prompt)
+	return summary, llm.OllamaModel, err
+}
+
+// llmGenerate is Generate; replaced in tests.
+var llmGenerate = Generate
+
+// redactPrompt strips the store's entity names from a prompt bound for a
+// remote Ollama server; a local one gets it whole.
+func redactPrompt(beatsDir, ollamaURL, prompt string) (string, error) {
+	if redact.Local(ollamaURL) {
+		return prompt, nil
+	}
+	if p, err := config.Get().RedactionFor(redact.ProviderOllama); err != nil || p == nil {
+		return prompt, err
+	}
+	s, err := store.NewJSONLStore(beatsDir)
+	if err != nil {
+		return "", err
+	}
+	all, err := s.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	r, err := redact.ForOllama(ollamaURL, all)
+	if err != nil {
+		return "", err
+	}
+	return r.Text(prompt), nil
+}
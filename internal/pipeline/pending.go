@@ -0,0 +1,220 @@
+package pipeline
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// PendingFile holds captures waiting for review, one per line.
+const PendingFile = "pending.jsonl"
+
+// Why a capture is pending.
+const (
+	PendingQuota    = "quota"    // Its source was over the daily cap
+	PendingNoise    = "noise"    // It scored as noise
+	PendingApproval = "approval" // A robot commit from an agent not trusted
+)
+
+// PendingBeat is a capture held for review instead of being stored.
+type PendingBeat struct {
+	ID       string            `json:"id"`
+	QueuedAt time.Time         `json:"queued_at"`
+	Source   string            `json:"source"`
+	Reason   string            `json:"reason"`
+	Details  []string          `json:"details,omitempty"`
+	Agent    string            `json:"agent,omitempty"`   // Who made a staged robot commit
+	Redact   bool              `json:"redact,omitempty"`  // Commit with redaction when accepted
+	Extract  string            `json:"extract,omitempty"` // Entity extraction mode to commit with
+	Beat     beat.ProposedBeat `json:"beat"`
+}
+
+// PendingError is returned when a capture was held for review.
+type PendingError struct {
+	Pending *PendingBeat
+}
+
+func (e *PendingError) Error() string {
+	return fmt.Sprintf("held for review as %s (%s: %s); see bt review --pending", e.Pending.ID, e.Pending.Reason, strings.Join(e.Pending.Details, ", "))
+}
+
+// pendingMu serializes changes to the pending file within a process.
+var pendingMu sync.Mutex
+
+// LockPending holds changes to the pending queue back until the returned
+// function is called, for a caller that reads and rewrites it.
+func LockPending() (unlock func()) {
+	pendingMu.Lock()
+	return pendingMu.Unlock
+}
+
+// LoadPending reads the pending queue, oldest first.
+func LoadPending(beatsDir string) ([]PendingBeat, error) {
+	f, err := os.Open(filepath.Join(beatsDir, PendingFile))
+	if os.IsNotExist(err) {
+		return []PendingBeat{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := []PendingBeat{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var pb PendingBeat
+		if err := json.Unmarshal(scanner.Bytes(), &pb); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", PendingFile, err)
+		}
+		list = append(list, pb)
+	}
+	return list, scanner.Err()
+}
+
+// SavePending rewrites the pending queue.
+func SavePending(beatsDir string, list []PendingBeat) error {
+	var sb strings.Builder
+	for _, pb := range list {
+		data, err := json.Marshal(pb)
+		if err != nil {
+			return err
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+	}
+	path := filepath.Join(beatsDir, PendingFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// QueuePending adds a capture to the pending queue, giving it an ID like
+// pending-20260301-002.
+func QueuePending(beatsDir string, pb PendingBeat) (*PendingBeat, error) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	list, err := LoadPending(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	pb.QueuedAt = clock.Now().UTC()
+	prefix := "pending-" + beat.IDDate(pb.QueuedAt) + "-"
+	seq := 0
+	for _, q := range list {
+		var n int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(q.ID, prefix), "%d", &n); err == nil && strings.HasPrefix(q.ID, prefix) {
+			seq = max(seq, n)
+		}
+	}
+	pb.ID = fmt.Sprintf("%s%03d", prefix, seq+1)
+	if err := os.MkdirAll(beatsDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := SavePending(beatsDir, append(list, pb)); err != nil {
+		return nil, err
+	}
+	return &pb, nil
+}
+
+// gateCapture holds an automated capture for review when the quota hook is
+// on and its source has reached the daily cap, counted in days starting in
+// loc, or it scores as noise. It returns the pending entry, or nil when the
+// capture may be stored. Only captures with a source in impetus.meta are
+// automated; beats typed at the prompt and generated beats such as digests
+// are never held.
+func gateCapture(s *store.JSONLStore, p *beat.ProposedBeat, loc *time.Location) (*PendingBeat, error) {
+	config := hooks.GetQuotaConfig(s.Dir())
+	source := p.Impetus.Meta["source"]
+	if !config.Enabled || source == "" || p.Impetus.Meta["kind"] != "" {
+		return nil, nil
+	}
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	if limit := config.Limit(source); limit > 0 {
+		y, m, d := now.In(loc).Date()
+		today := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		count := 0
+		for _, b := range beats {
+			if b.Impetus.Meta["source"] == source && !b.CreatedAt.Before(today) {
+				count++
+			}
+		}
+		if count >= limit {
+			return QueuePending(s.Dir(), PendingBeat{Source: source, Reason: PendingQuota, Details: []string{fmt.Sprintf("%d of %d today", count, limit)}, Beat: *p})
+		}
+	}
+
+	score, reasons, err := noiseScore(p, beats, config, now)
+	if err != nil {
+		return nil, err
+	}
+	if score >= config.NoiseThreshold {
+		return QueuePending(s.Dir(), PendingBeat{Source: source, Reason: PendingNoise, Details: reasons, Beat: *p})
+	}
+	return nil, nil
+}
+
+// noiseScore scores how likely a capture is noise: too few words, mostly
+// symbols and digits (log output, hashes), a match for a noise pattern, or a
+// repeat of a beat from the same source in the last day.
+func noiseScore(p *beat.ProposedBeat, beats []beat.Beat, config hooks.QuotaHook, now time.Time) (int, []string, error) {
+	score := 0
+	var reasons []string
+	content := strings.TrimSpace(p.Content)
+	if words := len(strings.Fields(content)); words < config.MinWords {
+		score++
+		reasons = append(reasons, fmt.Sprintf("%d word(s)", words))
+	}
+	letters := 0
+	for _, r := range content {
+		if unicode.IsLetter(r) || unicode.IsSpace(r) {
+			letters++
+		}
+	}
+	if n := len([]rune(content)); n > 0 && letters*2 < n {
+		score++
+		reasons = append(reasons, "mostly symbols")
+	}
+	for _, pattern := range config.NoisePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid quota noise pattern %q: %w", pattern, err)
+		}
+		if re.MatchString(content) {
+			score += 2
+			reasons = append(reasons, "matches "+pattern)
+			break
+		}
+	}
+	since := now.Add(-24 * time.Hour)
+	for _, b := range beats {
+		if b.CreatedAt.After(since) && b.Impetus.Meta["source"] == p.Impetus.Meta["source"] && strings.EqualFold(strings.TrimSpace(b.Content), content) {
+			score += 2
+			reasons = append(reasons, "repeats "+b.ID)
+			break
+		}
+	}
+	return score, reasons, nil
+}
@@ -0,0 +1,330 @@
+// Package pipeline takes proposed beats into a store: the capture stages
+// that enrich and check them, the pre_commit hook, and the quota that holds
+// automated captures in the pending queue for review.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/capture"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/secrets"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Capture pipeline stages, in the order they run. Every way into the store
+// (bt add, the capture commands, the watchers, serve-capture and
+// --robot-commit-beat) takes a proposed beat through the same stages;
+// pipeline.disabled in capture.json turns stages off for a store.
+const (
+	StageNormalize = "normalize" // Line endings, blank lines around the content, repeated bead links
+	StageImpetus   = "impetus"   // Infer a label when none was given
+	StageURLs      = "urls"      // URLs in the content become references
+	StageEntities  = "entities"  // Heuristic or LLM entities, per entity_extraction
+	StageContext   = "context"   // WALD directory from the working directory or the meta
+	StageSecrets   = "secrets"   // The secrets hook
+	StageDedupe    = "dedupe"    // The duplicates and link_suggestions hooks
+)
+
+// Stages lists the capture stages in the order they run.
+var Stages = []string{StageNormalize, StageImpetus, StageURLs, StageEntities, StageContext, StageSecrets, StageDedupe}
+
+// Options are the choices bt add offers over other capture paths.
+type Options struct {
+	Redact   bool           // Mask secrets found in the beat
+	Extract  string         // Entity extraction mode; empty for the configured one
+	Reviewed bool           // Accepted from the pending queue, so not held again
+	Channel  string         // Capture channel to record (beat.Channel*)
+	Location *time.Location // Zone the quota's days start in; nil for the configured timezone
+}
+
+// location is the zone the quota counts days in.
+func (o Options) location() *time.Location {
+	if o.Location != nil {
+		return o.Location
+	}
+	if loc, err := config.Get().Location(); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Run is a proposed beat on its way through the pipeline, with what the
+// stages found along the way.
+type Run struct {
+	Findings []secrets.Finding // Secrets scan
+	Check    *Check            // Duplicates and link suggestions
+	Warnings []string          // Problems that did not stop the capture
+
+	store *store.JSONLStore
+	beat  *beat.ProposedBeat
+	opts  Options
+}
+
+// captureStages are the stage implementations by name.
+var captureStages = map[string]func(ctx context.Context, r *Run) error{
+	StageNormalize: func(_ context.Context, r *Run) error {
+		normalizeProposed(r.beat)
+		if r.beat.Content == "" {
+			return &Error{Msg: "content is required", Err: fmt.Errorf("content is empty")}
+		}
+		return nil
+	},
+	StageImpetus: func(_ context.Context, r *Run) error {
+		inferImpetus(&r.beat.Impetus, r.beat.Content)
+		return nil
+	},
+	StageURLs: func(_ context.Context, r *Run) error {
+		if w := addURLReferences(r.beat); w != "" {
+			r.Warnings = append(r.Warnings, w)
+		}
+		return nil
+	},
+	StageEntities: func(ctx context.Context, r *Run) error {
+		_, err := extractEntities(ctx, r.store.Dir(), r.beat, r.opts.Extract)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &Error{Msg: "entity extraction timed out", Err: err}
+		}
+		if err != nil {
+			r.Warnings = append(r.Warnings, err.Error())
+		}
+		return nil
+	},
+	StageContext: func(_ context.Context, r *Run) error {
+		cwd, _ := os.Getwd()
+		r.beat.Context = inferContext(r.store.Dir(), r.beat, cwd)
+		return nil
+	},
+	StageSecrets: func(_ context.Context, r *Run) error {
+		findings, err := scanSecrets(r.store.Dir(), r.beat, r.opts.Redact)
+		r.Findings = findings
+		var blocked *SecretsError
+		if err != nil && !errors.As(err, &blocked) {
+			return &Error{Msg: "secrets scan failed", Err: err}
+		}
+		return err
+	},
+	StageDedupe: func(_ context.Context, r *Run) error {
+		r.Check = checkProposed(r.store, r.beat)
+		if r.Check != nil && r.Check.Reject {
+			return &DuplicateError{Check: r.Check}
+		}
+		return nil
+	},
+}
+
+// Error names the step a capture failed at, for robot output. Its message
+// is the underlying error's.
+type Error struct {
+	Msg string
+	Err error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// DuplicateError is returned when the duplicates hook rejects a beat.
+type DuplicateError struct {
+	Check *Check
+}
+
+func (e *DuplicateError) Error() string { return e.Check.error().Error() }
+
+// disabledStages reads the stages turned off in capture.json. An unknown
+// stage name is an error, so a typo does not leave a stage running.
+func disabledStages(beatsDir string) (map[string]bool, error) {
+	cfg, err := capture.LoadConfig(beatsDir)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool)
+	for _, name := range cfg.Pipeline.Disabled {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Stages, name) {
+			return nil, fmt.Errorf("unknown pipeline stage %q in %s (stages: %s)", name, capture.ConfigFile, strings.Join(Stages, ", "))
+		}
+		disabled[name] = true
+	}
+	return disabled, nil
+}
+
+// runStages takes a proposed beat through the enabled stages. With redact
+// set, the secrets stage runs even when disabled, since the caller asked.
+func (r *Run) runStages(ctx context.Context, stages []string) error {
+	disabled, err := disabledStages(r.store.Dir())
+	if err != nil {
+		return &Error{Msg: "invalid capture pipeline", Err: err}
+	}
+	for _, name := range stages {
+		if disabled[name] && !(name == StageSecrets && r.opts.Redact) {
+			continue
+		}
+		if err := captureStages[name](ctx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prepare runs some of the stages on a proposed beat, as a commit would run
+// them, without storing it. It returns the warnings.
+func Prepare(ctx context.Context, s *store.JSONLStore, p *beat.ProposedBeat, stages []string) ([]string, error) {
+	r := &Run{store: s, beat: p}
+	err := r.runStages(ctx, stages)
+	return r.Warnings, err
+}
+
+// Capture stores a proposed beat. An automated capture over its quota is
+// held for review (a *PendingError); otherwise the pre_commit hook runs,
+// [[beat-id]] citations are linked and the pipeline stages enrich and check
+// the beat before it gets its ID. The returned run carries the warnings,
+// secrets found and duplicate check for the caller to report.
+func Capture(ctx context.Context, s *store.JSONLStore, p *beat.ProposedBeat, opts Options) (*beat.Beat, *Run, error) {
+	p.SetChannel(opts.Channel)
+	if !opts.Reviewed {
+		pending, err := gateCapture(s, p, opts.location())
+		if err != nil {
+			return nil, nil, &Error{Msg: "quota check failed", Err: err}
+		}
+		if pending != nil {
+			return nil, nil, &PendingError{Pending: pending}
+		}
+	}
+
+	// Let the pre_commit hook validate or transform the beat before it is stored
+	proposed, err := hooks.RunPreCommit(s.Dir(), p)
+	var rejected *hooks.PreCommitError
+	if errors.As(err, &rejected) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, &Error{Msg: "pre-commit hook failed", Err: err}
+	}
+	r := &Run{store: s, beat: proposed, opts: opts}
+	for _, id := range linkCitations(s, proposed) {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("cites %s, which is not in this store", id))
+	}
+	if err := r.runStages(ctx, Stages); err != nil {
+		return nil, r, err
+	}
+
+	createdAt := clock.Now().UTC()
+	if proposed.CreatedAt != nil {
+		createdAt = proposed.CreatedAt.UTC()
+	}
+	seq, err := s.NextSequenceForDate(createdAt)
+	if err != nil {
+		return nil, r, &Error{Msg: "failed to get sequence", Err: fmt.Errorf("failed to get sequence: %w", err)}
+	}
+
+	b := proposed.ToBeat(seq)
+	b.UpdatedAt = clock.Now().UTC()
+	if b.References == nil {
+		b.References = []beat.Reference{}
+	}
+	if b.Entities == nil {
+		b.Entities = []beat.Entity{}
+	}
+	if b.LinkedBeads == nil {
+		b.LinkedBeads = beat.BeadLinks{}
+	}
+	if sessionID := os.Getenv("FACTORY_SESSION_ID"); sessionID != "" {
+		b.SessionID = sessionID
+	} else if sessionID := b.Impetus.Meta["session_id"]; sessionID != "" {
+		b.SessionID = sessionID // Beats created on behalf of a session (e.g. the session watcher)
+	}
+
+	if err := s.Append(b); err != nil {
+		return nil, r, &Error{Msg: "failed to save beat", Err: fmt.Errorf("failed to save beat: %w", err)}
+	}
+	r.Check.storeEmbedding(b.ID)
+	return b, r, nil
+}
+
+// Commit stores a proposed beat in s through the capture pipeline, as bt add
+// does, for Go programs embedding the store. Instead of being printed, what
+// did not stop the capture (citations of unknown beats, possible secrets and
+// duplicates, suggested links) comes back as warnings.
+func Commit(ctx context.Context, s *store.JSONLStore, p *beat.ProposedBeat, channel string) (*beat.Beat, []string, error) {
+	b, run, err := Capture(ctx, s, p, Options{Channel: channel})
+	if run == nil {
+		return b, nil, err
+	}
+	warnings := append([]string{}, run.Warnings...)
+	for _, f := range run.Findings {
+		if f.Redacted {
+			warnings = append(warnings, fmt.Sprintf("redacted %s", f))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("possible secret, %s", f))
+		}
+	}
+	if err == nil && run.Check != nil {
+		for _, d := range run.Check.Duplicates {
+			warnings = append(warnings, fmt.Sprintf("possible duplicate of %s (similarity %.2f)", d.ID, d.Score))
+		}
+		for _, l := range run.Check.SuggestedLinks {
+			warnings = append(warnings, fmt.Sprintf("suggested link: %s %s (%.2f)", l.BeadID, l.Title, l.Confidence))
+		}
+	}
+	return b, warnings, err
+}
+
+// normalizeProposed tidies a proposed beat: LF line endings, no byte order
+// mark or blank lines around the content, a label on one line and each
+// bead linked once. Indentation inside the content is left alone.
+func normalizeProposed(p *beat.ProposedBeat) {
+	content := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(strings.TrimPrefix(p.Content, "\ufeff"))
+	lines := strings.Split(strings.TrimRight(content, " \t\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	p.Content = strings.Join(lines, "\n")
+	p.Impetus.Label = strings.Join(strings.Fields(p.Impetus.Label), " ")
+
+	var links []string
+	for _, id := range p.LinkedBeads {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(links, id) {
+			links = append(links, id)
+		}
+	}
+	if p.LinkedBeads != nil {
+		p.LinkedBeads = append([]string{}, links...)
+	}
+}
+
+// addURLReferences gives each URL in the content a url reference, unless
+// the beat already has one for it. Invalid url_subtypes rules are reported
+// and the built-in ones used.
+func addURLReferences(p *beat.ProposedBeat) string {
+	found := findURLs(p.Content)
+	if len(found) == 0 {
+		return ""
+	}
+	warning := ""
+	classifier, err := NewURLClassifier(config.Get().URLSubtypes)
+	if err != nil {
+		warning = fmt.Sprintf("ignoring url_subtypes: %v", err)
+		classifier, _ = NewURLClassifier(nil)
+	}
+	for _, f := range found {
+		if slices.ContainsFunc(p.References, func(r beat.Reference) bool { return r.Locator == f.URL }) {
+			continue
+		}
+		p.References = append(p.References, beat.Reference{
+			Kind:    "url",
+			Subtype: classifier.Classify(f.URL),
+			Locator: f.URL,
+			Label:   f.Label,
+		})
+	}
+	return warning
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/secrets"
+)
+
+// SecretsError is returned when the secrets hook blocks a beat.
+type SecretsError struct {
+	Findings []secrets.Finding
+}
+
+func (e *SecretsError) Error() string {
+	found := make([]string, len(e.Findings))
+	for i, f := range e.Findings {
+		found[i] = f.String()
+	}
+	return fmt.Sprintf("beat looks like it contains secrets (%s); use --redact to mask them", strings.Join(found, "; "))
+}
+
+// scanSecrets runs the secrets hook on a proposed beat's content and raw
+// impetus. Matches are masked in place, along with any copies in references
+// and entity labels, when redact is set or the hook's action is "redact";
+// with action "block" any match is a *SecretsError.
+func scanSecrets(beatsDir string, p *beat.ProposedBeat, redact bool) ([]secrets.Finding, error) {
+	config := hooks.GetSecretsConfig(beatsDir)
+	if !config.Enabled && !redact {
+		return nil, nil
+	}
+	scanner, err := secrets.NewScanner(config.Patterns, config.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := append(scanner.Scan("content", p.Content), scanner.Scan("impetus.raw", p.Impetus.Raw)...)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	if redact || config.Action == "redact" {
+		p.Content = scanner.Redact(p.Content)
+		p.Impetus.Raw = scanner.Redact(p.Impetus.Raw)
+		// URLs and entities taken from the content by earlier stages too
+		for i := range p.References {
+			p.References[i].Locator = scanner.Redact(p.References[i].Locator)
+			p.References[i].Label = scanner.Redact(p.References[i].Label)
+		}
+		for i := range p.Entities {
+			p.Entities[i].Label = scanner.Redact(p.Entities[i].Label)
+		}
+		for i := range findings {
+			findings[i].Redacted = true
+		}
+		return findings, nil
+	}
+	if config.Action == "block" {
+		return findings, &SecretsError{Findings: findings}
+	}
+	return findings, nil
+}
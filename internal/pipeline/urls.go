@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/config"
+)
+
+var (
+	// urlRegex finds http(s) URLs, stopping at whitespace, quotes and the
+	// brackets that wrap links in markdown and mail (<https://...>).
+	urlRegex = regexp.MustCompile(`(?i)\bhttps?://[^\s<>\[\]"'` + "`" + `]+`)
+	// mdLinkRegex finds markdown links, to keep their text as a label.
+	mdLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\(\s*<?(https?://[^\s<>)]+(?:\([^\s<>)]*\)[^\s<>)]*)*)>?(?:\s+"[^"]*")?\s*\)`)
+)
+
+// builtinURLSubtypes classify URLs no configured rule matches.
+var builtinURLSubtypes = []config.URLSubtype{
+	{Host: "github.com", Subtype: "github"},
+	{Host: "youtube.com", Subtype: "youtube"},
+	{Host: "youtu.be", Subtype: "youtube"},
+	{Host: "twitter.com", Subtype: "twitter"},
+	{Host: "x.com", Subtype: "twitter"},
+	{Pattern: `(?i)\.pdf(?:$|[?#])`, Subtype: "pdf"},
+}
+
+// foundURL is a URL in captured text, with the text of the markdown link
+// it came from, if any.
+type foundURL struct {
+	URL   string
+	Label string
+}
+
+// findURLs returns the URLs in text in order, each once. Bare URLs,
+// markdown links, angle-bracketed links and URLs in parentheses or at the
+// end of a sentence are recognized; parentheses that are part of a URL,
+// as in Wikipedia links, are kept.
+func findURLs(text string) []foundURL {
+	labels := make(map[string]string)
+	for _, m := range mdLinkRegex.FindAllStringSubmatch(text, -1) {
+		if u := trimURL(m[2]); labels[u] == "" {
+			labels[u] = strings.TrimSpace(m[1])
+		}
+	}
+
+	var found []foundURL
+	seen := make(map[string]bool)
+	for _, u := range urlRegex.FindAllString(text, -1) {
+		u = trimURL(u)
+		if parsed, err := url.Parse(u); err != nil || parsed.Host == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		found = append(found, foundURL{URL: u, Label: labels[u]})
+	}
+	return found
+}
+
+// trimURL drops the punctuation a URL is followed by in prose, including
+// closing parentheses that have no opening one in the URL.
+func trimURL(u string) string {
+	for {
+		trimmed := strings.TrimRight(u, ".,;:!?*_~")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == u {
+			return u
+		}
+		u = trimmed
+	}
+}
+
+// URLClassifier gives URLs a reference subtype from the url_subtypes rules
+// in the config, then the built-in ones, falling back to "web".
+type URLClassifier struct {
+	rules []urlRule
+}
+
+type urlRule struct {
+	host    string
+	pattern *regexp.Regexp
+	subtype string
+}
+
+// NewURLClassifier compiles the configured rules ahead of the built-in
+// ones. A rule with neither a host nor a pattern, or without a subtype, or
+// with a pattern that does not compile, is an error.
+func NewURLClassifier(configured []config.URLSubtype) (*URLClassifier, error) {
+	c := &URLClassifier{}
+	for i, r := range append(append([]config.URLSubtype{}, configured...), builtinURLSubtypes...) {
+		if r.Subtype == "" || (r.Host == "" && r.Pattern == "") {
+			return nil, fmt.Errorf("url_subtypes[%d]: needs a subtype and a host or pattern", i)
+		}
+		rule := urlRule{host: strings.ToLower(strings.TrimPrefix(r.Host, ".")), subtype: r.Subtype}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("url_subtypes[%d]: invalid pattern: %w", i, err)
+			}
+			rule.pattern = re
+		}
+		c.rules = append(c.rules, rule)
+	}
+	return c, nil
+}
+
+// Classify returns the subtype of the first rule the URL matches.
+func (c *URLClassifier) Classify(rawURL string) string {
+	host := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	for _, r := range c.rules {
+		if r.host != "" && host != r.host && !strings.HasSuffix(host, "."+r.host) {
+			continue
+		}
+		if r.pattern != nil && !r.pattern.MatchString(rawURL) {
+			continue
+		}
+		return r.subtype
+	}
+	return "web"
+}
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/config"
+)
+
+func TestFindURLs(t *testing.T) {
+	text := "See [the RFC](https://example.com/rfc \"title\"), <https://intranet/wiki/Page>, " +
+		"(https://en.wikipedia.org/wiki/Go_(programming_language)) and https://example.com/rfc. " +
+		"Also HTTPS://Docs.example.org/a?b=1#c!"
+	want := []foundURL{
+		{URL: "https://example.com/rfc", Label: "the RFC"},
+		{URL: "https://intranet/wiki/Page"},
+		{URL: "https://en.wikipedia.org/wiki/Go_(programming_language)"},
+		{URL: "HTTPS://Docs.example.org/a?b=1#c"},
+	}
+	if got := findURLs(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("findURLs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestURLClassifier(t *testing.T) {
+	c, err := NewURLClassifier([]config.URLSubtype{
+		{Host: "jira.corp.example.com", Subtype: "jira"},
+		{Host: "github.com", Pattern: `/pull/\d+`, Subtype: "pull-request"},
+		{Pattern: `^https?://intranet/`, Subtype: "wiki"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for url, want := range map[string]string{
+		"https://jira.corp.example.com/browse/OPS-1": "jira",
+		"https://github.com/a/b/pull/12":             "pull-request",
+		"https://github.com/a/b":                     "github",
+		"https://gist.github.com/a/1":                "github",
+		"https://intranet/wiki/Page":                 "wiki",
+		"https://youtu.be/abc":                       "youtube",
+		"https://x.com/someone":                      "twitter",
+		"https://dropbox.com/s/x":                    "web",
+		"https://example.com/paper.pdf?dl=1":         "pdf",
+		"https://example.com/pdf-tools":              "web",
+	} {
+		if got := c.Classify(url); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", url, got, want)
+		}
+	}
+
+	if _, err := NewURLClassifier([]config.URLSubtype{{Pattern: "(", Subtype: "x"}}); err == nil {
+		t.Error("NewURLClassifier() accepted an invalid pattern")
+	}
+	if _, err := NewURLClassifier([]config.URLSubtype{{Subtype: "x"}}); err == nil {
+		t.Error("NewURLClassifier() accepted a rule that matches everything")
+	}
+}
@@ -0,0 +1,150 @@
+// Package profile times where a command spends its time, for bt --profile,
+// and warns on stderr when operations known to grow with the store (index
+// rebuilds, semantic search) cross a threshold, profiling or not.
+package profile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Phases timed across beats. Phases can nest (a sync parses the JSONL file),
+// so their times can add up to more than the command took.
+const (
+	JSONLParse     = "jsonl_parse"     // Reading and decoding beats.jsonl
+	SQLiteSync     = "sqlite_sync"     // Rebuilding the SQLite index
+	Ollama         = "ollama"          // Requests to the Ollama server
+	Hooks          = "hooks"           // Hook scripts and post-add hooks
+	SemanticSearch = "semantic_search" // Embedding a query and ranking beats
+)
+
+// order is the order phases are reported in; others follow by name.
+var order = []string{JSONLParse, SQLiteSync, Ollama, Hooks, SemanticSearch}
+
+// Thresholds over which a phase is reported as slow.
+var Thresholds = map[string]time.Duration{
+	SQLiteSync:     2 * time.Second,
+	SemanticSearch: 3 * time.Second,
+}
+
+// Phase is the time spent in one phase.
+type Phase struct {
+	Name  string        `json:"name"`
+	Calls int           `json:"calls"`
+	Total time.Duration `json:"total"`
+	Max   time.Duration `json:"max"`
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	phases            = make(map[string]*Phase)
+	warn    io.Writer = os.Stderr
+)
+
+// Enable starts recording phases.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
+
+// Enabled reports whether phases are recorded.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Start times a phase until the returned function is called:
+//
+//	defer profile.Start(profile.Ollama)()
+func Start(name string) func() {
+	start := time.Now()
+	return func() { Record(name, time.Since(start)) }
+}
+
+// Record adds one call of d to a phase and warns when it is over the
+// phase's threshold.
+func Record(name string, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled {
+		p := phases[name]
+		if p == nil {
+			p = &Phase{Name: name}
+			phases[name] = p
+		}
+		p.Calls++
+		p.Total += d
+		p.Max = max(p.Max, d)
+	}
+	if limit, ok := Thresholds[name]; ok && d > limit {
+		fmt.Fprintf(warn, "Warning: slow %s took %s (threshold %s)\n", name, round(d), limit)
+	}
+}
+
+// Phases returns the recorded phases in report order.
+func Phases() []Phase {
+	mu.Lock()
+	defer mu.Unlock()
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i + 1
+	}
+	out := make([]Phase, 0, len(phases))
+	for _, p := range phases {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ri, rj := rank[out[i].Name], rank[out[j].Name]
+		if ri == 0 || rj == 0 {
+			if ri != rj {
+				return rj == 0
+			}
+			return out[i].Name < out[j].Name
+		}
+		return ri < rj
+	})
+	return out
+}
+
+// Report writes the time a command took and the phases recorded.
+func Report(w io.Writer, total time.Duration) {
+	fmt.Fprintf(w, "\nProfile: %s total\n", round(total))
+	list := Phases()
+	if len(list) == 0 {
+		fmt.Fprintln(w, "  (no timed phases)")
+		return
+	}
+	for _, p := range list {
+		calls := "calls"
+		if p.Calls == 1 {
+			calls = "call "
+		}
+		fmt.Fprintf(w, "  %-16s %4d %s %10s  (max %s)\n", p.Name, p.Calls, calls, round(p.Total), round(p.Max))
+	}
+}
+
+// round shortens a duration for display.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
+
+// reset forgets recorded phases, for tests.
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = false
+	phases = make(map[string]*Phase)
+}
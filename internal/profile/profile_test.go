@@ -0,0 +1,43 @@
+package profile
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	defer reset()
+	var warnings bytes.Buffer
+	defer func(w io.Writer) { warn = w }(warn)
+	warn = &warnings
+
+	Record(Ollama, time.Second) // Not profiling: only thresholds apply
+	if len(Phases()) != 0 {
+		t.Fatalf("recorded while disabled: %+v", Phases())
+	}
+
+	Enable()
+	Record("custom", time.Millisecond)
+	Record(Ollama, 30*time.Millisecond)
+	Record(Ollama, 10*time.Millisecond)
+	Record(SQLiteSync, 3*time.Second)
+	got := Phases()
+	if len(got) != 3 || got[0].Name != SQLiteSync || got[1].Name != Ollama || got[2].Name != "custom" {
+		t.Fatalf("Phases() = %+v; want sync, ollama, custom", got)
+	}
+	if o := got[1]; o.Calls != 2 || o.Total != 40*time.Millisecond || o.Max != 30*time.Millisecond {
+		t.Errorf("ollama = %+v", o)
+	}
+	if !strings.Contains(warnings.String(), "slow sqlite_sync took 3s") || strings.Contains(warnings.String(), "ollama") {
+		t.Errorf("warnings = %q", warnings.String())
+	}
+
+	var report bytes.Buffer
+	Report(&report, 4*time.Second)
+	if !strings.Contains(report.String(), "Profile: 4s total") || !strings.Contains(report.String(), "ollama              2 calls") {
+		t.Errorf("report:\n%s", report.String())
+	}
+}
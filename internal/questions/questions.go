@@ -0,0 +1,106 @@
+// Package questions tracks open loops: beats that ask something no later
+// beat has followed up on and no bead has taken over.
+package questions
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// Kind marks a beat that is a question in impetus.meta["kind"].
+const Kind = "question"
+
+// Question is a beat with an open question.
+type Question struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	AgeDays   int       `json:"age_days"`
+	Impetus   string    `json:"impetus"`
+	Question  string    `json:"question"`
+	Content   string    `json:"content"`
+	Source    string    `json:"source"` // kind or detected
+}
+
+var (
+	// questionPattern finds a sentence ending in a question mark.
+	questionPattern = regexp.MustCompile(`[^.!?\n]*[^\s.!?\n][^.!?\n]*\?+`)
+	// idPattern finds beat IDs named in content.
+	idPattern = regexp.MustCompile(`\bbeat-\d{8}-\d{3,}\b`)
+)
+
+// Detect returns the question a beat asks: its content when it is of kind
+// question, else its first sentence ending in a question mark. Question
+// marks inside a word, such as a URL query, do not count, and digests and
+// other generated beats ask nothing.
+func Detect(b beat.Beat) (question, source string) {
+	switch b.Impetus.Meta["kind"] {
+	case Kind:
+		return firstLine(b.Content), "kind"
+	case "":
+	default:
+		return "", ""
+	}
+	for _, loc := range questionPattern.FindAllStringIndex(b.Content, -1) {
+		if loc[1] < len(b.Content) && !strings.ContainsAny(b.Content[loc[1]:loc[1]+1], " \t\n\"')") {
+			continue
+		}
+		return strings.TrimSpace(b.Content[loc[0]:loc[1]]), "detected"
+	}
+	return "", ""
+}
+
+// Open returns the questions in beats that are still open, oldest first. A
+// question is closed once it is linked to a bead, or a later beat refers to
+// it by a beat reference or by naming its ID.
+func Open(beats []beat.Beat, now time.Time) []Question {
+	followed := make(map[string]bool)
+	for _, b := range beats {
+		if kind := b.Impetus.Meta["kind"]; kind != "" && kind != Kind {
+			continue // Digests cite beats without following them up
+		}
+		for _, ref := range b.References {
+			if ref.Kind == "beat" && ref.Locator != b.ID {
+				followed[ref.Locator] = true
+			}
+		}
+		for _, id := range idPattern.FindAllString(b.Content, -1) {
+			if id != b.ID {
+				followed[id] = true
+			}
+		}
+	}
+
+	out := []Question{}
+	for _, b := range beats {
+		if len(b.LinkedBeads) > 0 || followed[b.ID] {
+			continue
+		}
+		q, source := Detect(b)
+		if q == "" {
+			continue
+		}
+		out = append(out, Question{
+			ID:        b.ID,
+			CreatedAt: b.CreatedAt,
+			AgeDays:   int(now.Sub(b.CreatedAt).Hours() / 24),
+			Impetus:   b.Impetus.Label,
+			Question:  q,
+			Content:   b.Content,
+			Source:    source,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
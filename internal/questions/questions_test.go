@@ -0,0 +1,40 @@
+package questions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestOpen(t *testing.T) {
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+	beats := []beat.Beat{
+		{ID: "beat-20260301-001", CreatedAt: at(0), Content: "Should the sync run nightly? Leaning yes."},
+		{ID: "beat-20260302-001", CreatedAt: at(1), Content: "Pricing page at https://example.com/?plan=pro looks off."},
+		{ID: "beat-20260303-001", CreatedAt: at(2), Content: "Which queue do we use?"},
+		{ID: "beat-20260304-001", CreatedAt: at(3), Content: "Picked SQS, answering beat-20260303-001."},
+		{ID: "beat-20260305-001", CreatedAt: at(4), Content: "Why is the build slow?", LinkedBeads: beat.BeadLinks{{BeadID: "bd-1"}}},
+		{ID: "beat-20260306-001", CreatedAt: at(5), Content: "Pick a logo", Impetus: beat.Impetus{Meta: map[string]string{"kind": Kind}}},
+		{ID: "beat-20260307-001", CreatedAt: at(6), Content: "What a week?", Impetus: beat.Impetus{Meta: map[string]string{"kind": "digest"}},
+			References: []beat.Reference{{Kind: "beat", Locator: "beat-20260301-001"}}},
+	}
+
+	got := Open(beats, at(10))
+	want := []struct{ id, question, source string }{
+		{"beat-20260301-001", "Should the sync run nightly?", "detected"},
+		{"beat-20260306-001", "Pick a logo", "kind"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Open = %+v; want %d questions", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].ID != w.id || got[i].Question != w.question || got[i].Source != w.source {
+			t.Errorf("question %d = %+v; want %+v", i, got[i], w)
+		}
+	}
+	if got[0].AgeDays != 10 {
+		t.Errorf("age = %d; want 10", got[0].AgeDays)
+	}
+}
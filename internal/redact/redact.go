@@ -0,0 +1,233 @@
+// Package redact strips names and amounts from text before it leaves the
+// machine for an LLM provider. What is stripped is set by a profile in the
+// config file; providers without one, and a local Ollama, get full content.
+package redact
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+)
+
+// ProviderOllama is the provider name LLM calls made by beats itself are
+// redacted under when the Ollama server is not on this machine.
+const ProviderOllama = "ollama"
+
+// amountPattern matches money: a currency symbol before the number, or a
+// currency code or word after it, with an optional k/m/bn scale.
+var amountPattern = regexp.MustCompile(`(?i)[$€£¥]\s?\d(?:[\d,.]*\d)?(?:\s?(?:k|m|bn|thousand|million|billion)\b)?|\b\d(?:[\d,.]*\d)?(?:\s?(?:k|m|bn|thousand|million|billion))?\s?(?:usd|eur|gbp|chf|dollars?|euros?|pounds)\b`)
+
+// Redactor applies one profile. Each distinct name gets a numbered
+// placeholder such as [PERSON 1], kept stable across every text it redacts,
+// so the model can still tell people apart.
+type Redactor struct {
+	names    *regexp.Regexp
+	kinds    map[string]string // Lowercased name -> placeholder kind
+	amounts  bool
+	patterns []*regexp.Regexp
+
+	placeholders map[string]string
+	counts       map[string]int
+
+	// Replaced counts the substitutions made so far.
+	Replaced int
+}
+
+// New builds a redactor for a profile. Labels of the beats' entities in the
+// profile's categories are stripped along with its terms.
+func New(p config.RedactionProfile, beats []beat.Beat) (*Redactor, error) {
+	r := &Redactor{
+		kinds:        make(map[string]string),
+		amounts:      p.Amounts,
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+	categories := make(map[string]bool, len(p.Entities))
+	for _, c := range p.Entities {
+		categories[strings.ToLower(c)] = true
+	}
+	add := func(name, kind string) {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if len(name) < 2 || r.kinds[key] != "" {
+			return
+		}
+		r.kinds[key] = kind
+	}
+	for _, t := range p.Terms {
+		add(t, "name")
+	}
+	for _, b := range beats {
+		for _, e := range b.Entities {
+			if categories[strings.ToLower(e.Category)] {
+				add(e.Label, strings.ToLower(e.Category))
+			}
+		}
+	}
+	if len(r.kinds) > 0 {
+		names := make([]string, 0, len(r.kinds))
+		for n := range r.kinds {
+			names = append(names, n)
+		}
+		// Longest first, so "Ada Lovelace" wins over "Ada"
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) > len(names[j])
+			}
+			return names[i] < names[j]
+		})
+		for i, n := range names {
+			names[i] = wordBounded(n)
+		}
+		r.names = regexp.MustCompile(`(?i)` + strings.Join(names, "|"))
+	}
+	for _, expr := range p.Patterns {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// wordBounded quotes a name for a pattern, anchoring each end that is a word
+// character to a word boundary: "Ada" must not match inside "Adam", while
+// "Acme Inc." still matches before a space.
+func wordBounded(name string) string {
+	isWord := func(c byte) bool {
+		return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	expr := regexp.QuoteMeta(name)
+	if isWord(name[0]) {
+		expr = `\b` + expr
+	}
+	if isWord(name[len(name)-1]) {
+		expr += `\b`
+	}
+	return expr
+}
+
+// ForProvider returns the redactor for text sent to provider, or nil when
+// the provider gets full content. beats supplies the entity names.
+func ForProvider(provider string, beats []beat.Beat) (*Redactor, error) {
+	p, err := config.Get().RedactionFor(provider)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return New(*p, beats)
+}
+
+// Info reports the redaction applied to output bound for a provider.
+type Info struct {
+	Provider string `json:"provider"`
+	Replaced int    `json:"replaced"`
+}
+
+// ForOutput is ForProvider for output that reports its redaction: with the
+// redactor comes the Info to fill in, naming the configured default when
+// provider is empty.
+func ForOutput(provider string, beats []beat.Beat) (*Redactor, *Info, error) {
+	r, err := ForProvider(provider, beats)
+	if err != nil || r == nil {
+		return nil, nil, err
+	}
+	if provider == "" {
+		provider = config.Get().Redaction.Default
+	}
+	return r, &Info{Provider: provider}, nil
+}
+
+// ForOllama returns the redactor for a prompt sent to the Ollama server at
+// ollamaURL: nil when the server is local or no profile is configured.
+func ForOllama(ollamaURL string, beats []beat.Beat) (*Redactor, error) {
+	if Local(ollamaURL) {
+		return nil, nil
+	}
+	return ForProvider(ProviderOllama, beats)
+}
+
+// Local reports whether a server URL points at this machine.
+func Local(serverURL string) bool {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Text redacts s. A nil redactor returns it unchanged.
+func (r *Redactor) Text(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	if r.names != nil {
+		s = r.names.ReplaceAllStringFunc(s, func(m string) string {
+			return r.placeholder(r.kinds[strings.ToLower(m)], m)
+		})
+	}
+	if r.amounts {
+		s = amountPattern.ReplaceAllStringFunc(s, func(string) string {
+			r.Replaced++
+			return "[AMOUNT]"
+		})
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllStringFunc(s, func(string) string {
+			r.Replaced++
+			return "[REDACTED]"
+		})
+	}
+	return s
+}
+
+// Beat returns a copy of b with its text and entity labels redacted.
+func (r *Redactor) Beat(b beat.Beat) beat.Beat {
+	if r == nil {
+		return b
+	}
+	b.Content = r.Text(b.Content)
+	b.Impetus.Label = r.Text(b.Impetus.Label)
+	b.Impetus.Raw = r.Text(b.Impetus.Raw)
+	if len(b.Entities) > 0 {
+		entities := make([]beat.Entity, len(b.Entities))
+		for i, e := range b.Entities {
+			e.Label = r.Text(e.Label)
+			entities[i] = e
+		}
+		b.Entities = entities
+	}
+	if len(b.Relations) > 0 {
+		relations := make([]beat.Relation, len(b.Relations))
+		for i, rel := range b.Relations {
+			rel.From, rel.To = r.Text(rel.From), r.Text(rel.To)
+			relations[i] = rel
+		}
+		b.Relations = relations
+	}
+	return b
+}
+
+// placeholder returns the stable placeholder for one name.
+func (r *Redactor) placeholder(kind, name string) string {
+	r.Replaced++
+	key := strings.ToLower(name)
+	if p, ok := r.placeholders[key]; ok {
+		return p
+	}
+	r.counts[kind]++
+	p := fmt.Sprintf("[%s %d]", strings.ToUpper(kind), r.counts[kind])
+	r.placeholders[key] = p
+	return p
+}
@@ -0,0 +1,73 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/config"
+)
+
+func TestRedactor(t *testing.T) {
+	beats := []beat.Beat{{
+		ID:      "beat-20250101-001",
+		Content: "Call with Ada Lovelace about the Acme Inc. renewal",
+		Entities: []beat.Entity{
+			{Label: "Ada Lovelace", Category: "person"},
+			{Label: "Ada", Category: "person"},
+			{Label: "Acme Inc.", Category: "organization"},
+			{Label: "Go", Category: "topic"},
+		},
+	}}
+	r, err := New(config.RedactionProfile{
+		Entities: []string{"person", "organization"},
+		Terms:    []string{"Globex"},
+		Amounts:  true,
+		Patterns: []string{`INV-\d+`},
+	}, beats)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := r.Text("Ada Lovelace and ada met Acme Inc. and Globex (not Adam) in Go: $12,500 and 3k EUR, see INV-42.")
+	want := "[PERSON 1] and [PERSON 2] met [ORGANIZATION 1] and [NAME 1] (not Adam) in Go: [AMOUNT] and [AMOUNT], see [REDACTED]."
+	if got != want {
+		t.Errorf("Text:\n got %q\nwant %q", got, want)
+	}
+	// Placeholders stay the same across texts
+	if got := r.Text("ADA LOVELACE again"); got != "[PERSON 1] again" {
+		t.Errorf("second text = %q", got)
+	}
+	if r.Replaced != 8 {
+		t.Errorf("Replaced = %d, want 8", r.Replaced)
+	}
+
+	b := r.Beat(beats[0])
+	if b.Content != "Call with [PERSON 1] about the [ORGANIZATION 1] renewal" || b.Entities[0].Label != "[PERSON 1]" {
+		t.Errorf("Beat = %+v", b)
+	}
+	if beats[0].Entities[0].Label != "Ada Lovelace" {
+		t.Error("Beat changed the original's entities")
+	}
+
+	var none *Redactor
+	if got := none.Text("Ada"); got != "Ada" {
+		t.Errorf("nil redactor changed text: %q", got)
+	}
+	if _, err := New(config.RedactionProfile{Patterns: []string{"("}}, nil); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
+
+func TestLocal(t *testing.T) {
+	for url, want := range map[string]bool{
+		"http://localhost:11434":     true,
+		"http://127.0.0.1:11434":     true,
+		"http://[::1]:11434":         true,
+		"http://gpu.lan:11434":       false,
+		"https://ollama.example.com": false,
+	} {
+		if got := Local(url); got != want {
+			t.Errorf("Local(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package secrets finds credentials and personal data in beat text before it
+// is stored: API keys, tokens, private keys, emails and phone numbers, plus
+// any patterns the user configures.
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Pattern is a named regular expression a scan looks for.
+type Pattern struct {
+	Kind  string
+	Regex *regexp.Regexp
+}
+
+// Builtin lists the patterns scanned by default, most specific first so an
+// overlapping generic match does not hide a known key format.
+var Builtin = []Pattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github_token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"api_key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"slack_token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"stripe_key", regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"credential", regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d)\s*[:=]\s*['"]?[^\s'"]{8,}`)},
+	{"email", regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{"phone", regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b|\+\d{1,3}(?:[\s.-]?\d{2,4}){3,5}\b`)},
+}
+
+// Finding is one match. The matched text itself is never reported, only a
+// masked preview of it.
+type Finding struct {
+	Kind     string `json:"kind"`
+	Field    string `json:"field"` // content or impetus.raw
+	Preview  string `json:"preview"`
+	Offset   int    `json:"offset"` // Byte offset in the field
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// String describes a finding for warnings.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s in %s: %s", f.Kind, f.Field, f.Preview)
+}
+
+// Scanner matches a set of patterns.
+type Scanner struct {
+	patterns []Pattern
+}
+
+// NewScanner builds a scanner from the built-in patterns, minus the kinds in
+// ignore, plus custom patterns keyed by kind.
+func NewScanner(custom map[string]string, ignore []string) (*Scanner, error) {
+	skip := make(map[string]bool, len(ignore))
+	for _, kind := range ignore {
+		skip[kind] = true
+	}
+	s := &Scanner{}
+	for _, p := range Builtin {
+		if !skip[p.Kind] {
+			s.patterns = append(s.patterns, p)
+		}
+	}
+	kinds := make([]string, 0, len(custom))
+	for kind := range custom {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		re, err := regexp.Compile(custom[kind])
+		if err != nil {
+			return nil, fmt.Errorf("invalid secrets pattern %q: %w", kind, err)
+		}
+		s.patterns = append(s.patterns, Pattern{Kind: kind, Regex: re})
+	}
+	return s, nil
+}
+
+type match struct {
+	kind       string
+	start, end int
+}
+
+// matches finds non-overlapping matches in text, in order. Where patterns
+// overlap, the earlier pattern wins.
+func (s *Scanner) matches(text string) []match {
+	var out []match
+	taken := func(start, end int) bool {
+		for _, m := range out {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range s.patterns {
+		for _, loc := range p.Regex.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] && !taken(loc[0], loc[1]) {
+				out = append(out, match{p.Kind, loc[0], loc[1]})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].start < out[j].start })
+	return out
+}
+
+// Scan reports the matches in one field's text.
+func (s *Scanner) Scan(field, text string) []Finding {
+	var out []Finding
+	for _, m := range s.matches(text) {
+		out = append(out, Finding{Kind: m.kind, Field: field, Preview: Mask(text[m.start:m.end]), Offset: m.start})
+	}
+	return out
+}
+
+// Redact replaces every match in text with [REDACTED:<kind>].
+func (s *Scanner) Redact(text string) string {
+	ms := s.matches(text)
+	if len(ms) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range ms {
+		sb.WriteString(text[last:m.start])
+		fmt.Fprintf(&sb, "[REDACTED:%s]", m.kind)
+		last = m.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Mask keeps enough of a match to recognize it: the first and last few
+// characters of long ones, nothing of short ones.
+func Mask(s string) string {
+	r := []rune(s)
+	switch {
+	case len(r) <= 6:
+		return strings.Repeat("*", len(r))
+	case len(r) <= 12:
+		return string(r[:2]) + strings.Repeat("*", len(r)-2)
+	default:
+		return string(r[:4]) + strings.Repeat("*", len(r)-6) + string(r[len(r)-2:])
+	}
+}
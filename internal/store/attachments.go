@@ -0,0 +1,192 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// BlobsDir holds files attached to beats, relative to the beats directory.
+// Each is named by the sha256 of its content and sharded by the first two
+// hex digits, so the directory can be synced or left out of backups on its
+// own.
+const BlobsDir = "blobs"
+
+// AttachmentsDir held attachments before the blob store, named by a
+// shortened hash. Its files are still read and garbage collected.
+const AttachmentsDir = "attachments"
+
+// SaveAttachment stores data in the blob store under its content hash, so
+// re-capturing the same page does not duplicate it. It returns the path
+// relative to the beats directory, for use as a reference locator.
+func (s *JSONLStore) SaveAttachment(data []byte, ext string) (string, error) {
+	if err := s.writable(); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	rel := filepath.Join(BlobsDir, hash[:2], hash+ext)
+	path := filepath.Join(s.dir, rel)
+
+	if _, err := os.Stat(path); err == nil {
+		// Touch it so a collection running now treats it as new
+		now := clock.Now()
+		os.Chtimes(path, now, now)
+		return rel, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create blobs directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return rel, nil
+}
+
+// ReadAttachment reads an attachment by the locator SaveAttachment returned.
+func (s *JSONLStore) ReadAttachment(rel string) ([]byte, error) {
+	clean, ok := blobPath(rel)
+	if !ok {
+		return nil, fmt.Errorf("invalid attachment path: %s", rel)
+	}
+	return os.ReadFile(filepath.Join(s.dir, clean))
+}
+
+// blobPath cleans a reference locator and reports whether it names a file in
+// the blob store or the old attachments directory.
+func blobPath(locator string) (string, bool) {
+	if locator == "" || strings.Contains(locator, "://") {
+		return "", false
+	}
+	clean := filepath.Clean(filepath.FromSlash(locator))
+	if filepath.IsAbs(clean) {
+		return "", false
+	}
+	dir := filepath.Dir(clean)
+	switch {
+	case dir == AttachmentsDir:
+	case filepath.Dir(dir) == BlobsDir && len(filepath.Base(dir)) == 2:
+	default:
+		return "", false
+	}
+	return clean, true
+}
+
+// BlobRefs counts the references to each stored blob, by path relative to
+// the beats directory.
+func BlobRefs(beats []beat.Beat) map[string]int {
+	refs := make(map[string]int)
+	for _, b := range beats {
+		CountBlobRefs(refs, b.References)
+	}
+	return refs
+}
+
+// CountBlobRefs adds the blobs among references to refs.
+func CountBlobRefs(refs map[string]int, references []beat.Reference) {
+	for _, r := range references {
+		if rel, ok := blobPath(r.Locator); ok {
+			refs[rel]++
+		}
+	}
+}
+
+// Blob is a file in the blob store.
+type Blob struct {
+	Path    string    `json:"path"` // Relative to the beats directory
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modified_at"`
+	Refs    int       `json:"refs"`
+}
+
+// GCReport is the result of a blob garbage collection.
+type GCReport struct {
+	Blobs      int    `json:"blobs"`       // Files examined
+	Bytes      int64  `json:"bytes"`       // Their total size
+	Referenced int    `json:"referenced"`  // Files some beat refers to
+	Young      int    `json:"young"`       // Unreferenced but inside the grace period
+	Removed    []Blob `json:"removed"`     // Collected, or collectable with dry run
+	Freed      int64  `json:"freed_bytes"` // Size of Removed
+	DryRun     bool   `json:"dry_run"`
+}
+
+// CollectGarbage removes blobs with no reference in refs. Blobs changed
+// within grace are kept, since a capture saves its attachments before its
+// beat is written. With dryRun nothing is removed.
+func (s *JSONLStore) CollectGarbage(refs map[string]int, grace time.Duration, dryRun bool) (*GCReport, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	report := &GCReport{Removed: []Blob{}, DryRun: dryRun}
+	cutoff := clock.Now().Add(-grace)
+	for _, dir := range []string{BlobsDir, AttachmentsDir} {
+		root := filepath.Join(s.dir, dir)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			if err != nil || d.IsDir() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(s.dir, path)
+			blob := Blob{Path: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime(), Refs: refs[rel]}
+			report.Blobs++
+			report.Bytes += blob.Size
+			switch {
+			case blob.Refs > 0:
+				report.Referenced++
+			case blob.ModTime.After(cutoff):
+				report.Young++
+			default:
+				if !dryRun {
+					if err := os.Remove(path); err != nil {
+						return err
+					}
+				}
+				report.Removed = append(report.Removed, blob)
+				report.Freed += blob.Size
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(report.Removed, func(i, j int) bool { return report.Removed[i].Path < report.Removed[j].Path })
+	if !dryRun {
+		s.removeEmptyShards()
+	}
+	return report, nil
+}
+
+// removeEmptyShards deletes blob shard directories left empty by a
+// collection.
+func (s *JSONLStore) removeEmptyShards() {
+	entries, err := os.ReadDir(filepath.Join(s.dir, BlobsDir))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			os.Remove(filepath.Join(s.dir, BlobsDir, e.Name())) // Fails unless empty
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := s.SaveAttachment([]byte("full text"), ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.SaveAttachment([]byte("full text"), ".txt"); again != kept {
+		t.Errorf("saving the same content twice gave %s and %s", kept, again)
+	}
+	if data, err := s.ReadAttachment(filepath.ToSlash(kept)); err != nil || string(data) != "full text" {
+		t.Errorf("ReadAttachment(%s) = %q, %v", kept, data, err)
+	}
+	orphan, _ := s.SaveAttachment([]byte("<html>old snapshot</html>"), ".html")
+	young, _ := s.SaveAttachment([]byte("captured a moment ago"), ".txt")
+	legacy := filepath.Join(AttachmentsDir, "0123456789abcdef.txt")
+	if err := os.MkdirAll(filepath.Join(s.Dir(), AttachmentsDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(), legacy), []byte("old layout"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for _, rel := range []string{kept, orphan, legacy} {
+		if err := os.Chtimes(filepath.Join(s.Dir(), rel), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refs := BlobRefs([]beat.Beat{{References: []beat.Reference{
+		{Kind: "attachment", Locator: filepath.ToSlash(kept)},
+		{Kind: "url", Locator: "https://example.com/" + filepath.ToSlash(orphan)},
+	}}})
+	report, err := s.CollectGarbage(refs, 24*time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blobs != 4 || report.Referenced != 1 || report.Young != 1 || len(report.Removed) != 2 {
+		t.Fatalf("dry run = %+v; want 4 blobs, 1 referenced, 1 young, 2 to remove", report)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir(), orphan)); err != nil {
+		t.Errorf("dry run removed %s", orphan)
+	}
+
+	if _, err := s.CollectGarbage(refs, 24*time.Hour, false); err != nil {
+		t.Fatal(err)
+	}
+	for rel, want := range map[string]bool{kept: true, young: true, orphan: false, legacy: false} {
+		if _, err := os.Stat(filepath.Join(s.Dir(), rel)); (err == nil) != want {
+			t.Errorf("%s exists = %v; want %v", rel, err == nil, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Dir(filepath.Join(s.Dir(), orphan))); err == nil && filepath.Dir(orphan) != filepath.Dir(kept) && filepath.Dir(orphan) != filepath.Dir(young) {
+		t.Errorf("empty shard %s left behind", filepath.Dir(orphan))
+	}
+}
+
+func TestSearchAttachments(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "Chapter one.\n\nThe vendor quoted a renewal price well above last year's, so we asked for a discount.\n"
+	rel, err := s.SaveAttachment([]byte(text), ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, _ := s.SaveAttachment([]byte("<p>renewal price</p>"), ".html")
+	b := beat.Beat{ID: "beat-20260101-001", Content: "Vendor call", References: []beat.Reference{
+		{Kind: "attachment", Subtype: "text/plain", Locator: rel, Label: "Full text"},
+		{Kind: "attachment", Subtype: "text/html", Locator: html, Label: "Snapshot"},
+	}}
+	if err := s.Append(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlite, err := NewSQLiteStore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+	matches, err := sqlite.SearchAttachments("renewal PRICE", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("matches = %+v, want the text attachment only", matches)
+	}
+	m := matches[0]
+	if m.BeatID != b.ID || m.Locator != rel || m.Offset != strings.Index(text, "renewal") {
+		t.Errorf("match = %+v", m)
+	}
+	if !strings.Contains(m.Snippet, "renewal price") || strings.Contains(m.Snippet, "\n") {
+		t.Errorf("snippet = %q", m.Snippet)
+	}
+	if none, err := sqlite.SearchAttachments(`discount "unquoted`, 10); err != nil || len(none) != 0 {
+		t.Errorf("SearchAttachments(quote) = %+v, %v; want no matches", none, err)
+	}
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// BacklinksFile caches which beats refer to each beat. It is rebuilt
+// whenever beats.jsonl has changed since it was written.
+const BacklinksFile = "backlinks.json"
+
+// Backlink is a beat referring to another.
+type Backlink struct {
+	ID  string `json:"id"`  // The referring beat
+	Via string `json:"via"` // citation for [[id]] in its content, reference otherwise
+}
+
+// backlinkIndex is the content of BacklinksFile.
+type backlinkIndex struct {
+	Size    int64                 `json:"source_size"` // beats.jsonl when the index was built
+	ModTime time.Time             `json:"source_modified_at"`
+	Links   map[string][]Backlink `json:"links"`
+}
+
+// BuildBacklinks indexes the beat references in beats by the beat referred
+// to, each list in beat order.
+func BuildBacklinks(beats []beat.Beat) map[string][]Backlink {
+	links := make(map[string][]Backlink)
+	for _, b := range beats {
+		seen := make(map[string]bool)
+		for _, r := range b.References {
+			if r.Kind != "beat" || r.Locator == b.ID || seen[r.Locator] {
+				continue
+			}
+			seen[r.Locator] = true
+			via := "reference"
+			if r.Subtype == beat.CitationSubtype {
+				via = beat.CitationSubtype
+			}
+			links[r.Locator] = append(links[r.Locator], Backlink{ID: b.ID, Via: via})
+		}
+	}
+	for _, list := range links {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return links
+}
+
+// Backlinks returns the backlink index, from BacklinksFile when it is
+// current and otherwise rebuilt and saved.
+func (s *JSONLStore) Backlinks() (map[string][]Backlink, error) {
+	if !s.asOf.IsZero() {
+		beats, err := s.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		return BuildBacklinks(beats), nil
+	}
+
+	info, err := s.Stat()
+	if os.IsNotExist(err) {
+		return map[string][]Backlink{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(s.dir, BacklinksFile)
+	var index backlinkIndex
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &index) == nil &&
+		index.Size == info.Size() && index.ModTime.Equal(info.ModTime()) && index.Links != nil {
+		return index.Links, nil
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	index = backlinkIndex{Size: info.Size(), ModTime: info.ModTime(), Links: BuildBacklinks(beats)}
+	if data, err := json.Marshal(index); err == nil {
+		// A cache; failing to write it only costs a rebuild next time
+		tmp := path + ".tmp"
+		if os.WriteFile(tmp, data, 0644) == nil {
+			os.Rename(tmp, path)
+		}
+	}
+	return index.Links, nil
+}
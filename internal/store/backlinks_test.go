@@ -0,0 +1,42 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestBacklinks(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cited := &beat.Beat{ID: "beat-20251204-001", Content: "Decision: ship weekly"}
+	citing := &beat.Beat{ID: "beat-20251205-001", Content: "Per [[beat-20251204-001]]", References: []beat.Reference{
+		{Kind: "beat", Subtype: beat.CitationSubtype, Locator: cited.ID},
+	}}
+	if err := s.AppendBulk([]*beat.Beat{cited, citing}); err != nil {
+		t.Fatal(err)
+	}
+
+	links, err := s.Backlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := links[cited.ID]; len(got) != 1 || got[0] != (Backlink{ID: citing.ID, Via: beat.CitationSubtype}) {
+		t.Errorf("backlinks of %s = %+v", cited.ID, got)
+	}
+
+	// A write makes the cached index stale
+	digest := &beat.Beat{ID: "beat-20251206-001", Content: "Digest", References: []beat.Reference{{Kind: "beat", Locator: cited.ID}}}
+	if err := s.Append(digest); err != nil {
+		t.Fatal(err)
+	}
+	links, err = s.Backlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := links[cited.ID]; len(got) != 2 || got[1] != (Backlink{ID: digest.ID, Via: "reference"}) {
+		t.Errorf("backlinks after a write = %+v; want the digest too", got)
+	}
+}
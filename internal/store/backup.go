@@ -0,0 +1,423 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+const (
+	// BackupsDir holds snapshots of beats.jsonl inside the .beats directory.
+	BackupsDir = "backups"
+	// BackupManifest lists the snapshots with their counts and checksums.
+	BackupManifest = "manifest.jsonl"
+)
+
+// Why a backup was taken.
+const (
+	BackupBaseline   = "baseline"    // Before the first journaled write
+	BackupManual     = "manual"      // bt backup
+	BackupPreRestore = "pre-restore" // The store a restore replaced
+	BackupRestored   = "restored"    // The store a restore produced
+	BackupPreConvert = "pre-convert" // The store before a backend conversion
+)
+
+// Backup is a snapshot of beats.jsonl.
+type Backup struct {
+	File    string    `json:"file"` // Relative to the backups directory
+	TakenAt time.Time `json:"taken_at"`
+	Reason  string    `json:"reason"`
+	Beats   int       `json:"beats"`
+	SHA256  string    `json:"sha256"`
+}
+
+// BackupCheck is the result of verifying one backup.
+type BackupCheck struct {
+	Backup
+	OK      bool   `json:"ok"`
+	Problem string `json:"problem,omitempty"`
+}
+
+func (s *JSONLStore) backupsDir() string {
+	return filepath.Join(s.dir, BackupsDir)
+}
+
+// Backup snapshots beats.jsonl.
+func (s *JSONLStore) Backup(reason string) (*Backup, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	defer s.lock()()
+	return s.backupUnlocked(reason)
+}
+
+// backupUnlocked copies beats.jsonl into the backups directory and records
+// it in the manifest. Caller must hold the write lock.
+func (s *JSONLStore) backupUnlocked(reason string) (*Backup, error) {
+	data, err := s.jsonlUnlocked()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.backupsDir(), 0755); err != nil {
+		return nil, err
+	}
+	now := clock.Now().UTC()
+	b := &Backup{
+		File:    "beats-" + now.Format("20060102T150405.000000000Z") + ".jsonl",
+		TakenAt: now,
+		Reason:  reason,
+		Beats:   len(parseLines(data)),
+		SHA256:  checksum(data),
+	}
+	if err := os.WriteFile(filepath.Join(s.backupsDir(), b.File), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	entry, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(s.backupsDir(), BackupManifest), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup manifest: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(entry, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return b, nil
+}
+
+// Backups lists the store's backups, oldest first.
+func (s *JSONLStore) Backups() ([]Backup, error) {
+	data, err := os.ReadFile(filepath.Join(s.backupsDir(), BackupManifest))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Backup
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var b Backup
+		if len(bytes.TrimSpace(line)) > 0 && json.Unmarshal(line, &b) == nil && b.File != "" {
+			out = append(out, b)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].TakenAt.Before(out[j].TakenAt) })
+	return out, nil
+}
+
+// VerifyBackups checks every backup against the checksum and beat count
+// recorded when it was taken.
+func (s *JSONLStore) VerifyBackups() ([]BackupCheck, error) {
+	backups, err := s.Backups()
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]BackupCheck, 0, len(backups))
+	for _, b := range backups {
+		_, err := s.readBackup(b)
+		check := BackupCheck{Backup: b, OK: err == nil}
+		if err != nil {
+			check.Problem = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// readBackup returns a backup's contents after checking them against the
+// manifest.
+func (s *JSONLStore) readBackup(b Backup) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.backupsDir(), b.File))
+	if err != nil {
+		return nil, err
+	}
+	if sum := checksum(data); sum != b.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch: %s, manifest says %s", sum[:12], shortSum(b.SHA256))
+	}
+	if n := len(parseLines(data)); n != b.Beats {
+		return nil, fmt.Errorf("holds %d beats, manifest says %d", n, b.Beats)
+	}
+	return data, nil
+}
+
+// RestorePlan is a store rebuilt as of a moment, verified and ready to be
+// swapped into place.
+type RestorePlan struct {
+	At       time.Time `json:"at"`
+	Base     Backup    `json:"base"`     // Backup the journal was replayed onto
+	Replayed int       `json:"replayed"` // Journal entries applied
+	Beats    int       `json:"beats"`
+	SHA256   string    `json:"sha256"`
+	Checks   []string  `json:"checks"` // Verification steps that passed
+
+	// Against the current store
+	Current int `json:"current"`
+	Added   int `json:"added"`   // Beats the restore brings back
+	Removed int `json:"removed"` // Beats the restore drops
+	Changed int `json:"changed"` // Beats the restore rolls back
+
+	data []byte
+}
+
+// PlanRestore rebuilds the store as it was at the given moment from the
+// newest backup taken before it plus the journal entries up to it, and
+// verifies the result. Nothing is written.
+func (s *JSONLStore) PlanRestore(at time.Time) (*RestorePlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, err := s.rebuildUnlocked(at)
+	if err != nil {
+		return nil, err
+	}
+	plan := &RestorePlan{At: at, Base: r.base, Replayed: r.replayed, data: r.data}
+	plan.Checks = append(plan.Checks, fmt.Sprintf("backup %s matches its checksum and count (%d beats)", r.base.File, r.base.Beats))
+	plan.SHA256 = checksum(plan.data)
+	want := make(map[string]bool, len(r.ids))
+	for _, id := range r.ids {
+		want[id] = true
+	}
+
+	// Verify: every line parses, the IDs are exactly the expected set
+	got := parseLines(plan.data)
+	if len(got) != len(want) || bytes.Count(plan.data, []byte("\n")) != len(want) {
+		return nil, fmt.Errorf("verification failed: rebuilt %d beats from %d expected", len(got), len(want))
+	}
+	for _, l := range got {
+		if !want[l.id] {
+			return nil, fmt.Errorf("verification failed: unexpected beat %s", l.id)
+		}
+	}
+	plan.Beats = len(got)
+	plan.Checks = append(plan.Checks,
+		fmt.Sprintf("replayed %d journal entries", plan.Replayed),
+		fmt.Sprintf("%d beats, every line a valid beat with a unique ID", plan.Beats))
+
+	current, _, err := s.readTolerantUnlocked()
+	if err != nil {
+		return nil, err
+	}
+	plan.Current = len(current)
+	restored := make(map[string]beat.Beat, len(got))
+	for _, l := range got {
+		restored[l.id] = l.beat
+	}
+	for _, b := range current {
+		r, ok := restored[b.ID]
+		switch {
+		case !ok:
+			plan.Removed++
+		case !r.UpdatedAt.Equal(b.UpdatedAt) || r.Content != b.Content:
+			plan.Changed++
+		}
+		delete(restored, b.ID)
+	}
+	plan.Added = len(restored)
+	return plan, nil
+}
+
+// AsOf returns a read-only view of the store as it was at the given moment,
+// rebuilt like a restore from the backups and the journal. Reads see the
+// beats as they were then; writes fail.
+func (s *JSONLStore) AsOf(at time.Time) (*JSONLStore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, err := s.rebuildUnlocked(at)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLStore{dir: s.dir, filePath: s.filePath, files: s.files, asOf: at, history: r.data}, nil
+}
+
+// AsOfTime returns the moment a view made by AsOf shows, or the zero time
+// for the live store.
+func (s *JSONLStore) AsOfTime() time.Time {
+	return s.asOf
+}
+
+// writable fails on a view of the past.
+func (s *JSONLStore) writable() error {
+	if !s.asOf.IsZero() {
+		return fmt.Errorf("the store as of %s is read-only", s.asOf.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// rebuilt is beats.jsonl as it was at some moment.
+type rebuilt struct {
+	base     Backup   // The backup it starts from
+	replayed int      // Journal entries replayed onto it
+	ids      []string // The beats it should hold, in file order
+	data     []byte
+}
+
+// rebuildUnlocked returns beats.jsonl as it was at the given moment: the
+// newest backup taken before it with the journal entries up to it replayed,
+// keeping the file order. Before an empty oldest backup the store is empty.
+func (s *JSONLStore) rebuildUnlocked(at time.Time) (*rebuilt, error) {
+	backups, err := s.Backups()
+	if err != nil {
+		return nil, err
+	}
+	var base *Backup
+	for i := range backups {
+		if !backups[i].TakenAt.After(at) {
+			base = &backups[i]
+		}
+	}
+	if base == nil {
+		if len(backups) == 0 {
+			return nil, fmt.Errorf("no backups in %s; the store's history starts with its next write", s.backupsDir())
+		}
+		// An empty oldest backup means the store was empty before it too;
+		// beats it already held have no history to rebuild from.
+		if backups[0].Beats > 0 {
+			return nil, fmt.Errorf("no backup at or before %s; the oldest is from %s and already holds %d beats", at.Format(time.RFC3339), backups[0].TakenAt.Format(time.RFC3339), backups[0].Beats)
+		}
+		base = &backups[0]
+	}
+
+	data, err := s.readBackup(*base)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is damaged: %w", base.File, err)
+	}
+
+	// Replay the journal onto the backup, keeping the file order
+	var order []string
+	lines := make(map[string][]byte)
+	for _, l := range parseLines(data) {
+		if _, seen := lines[l.id]; !seen {
+			order = append(order, l.id)
+		}
+		lines[l.id] = l.raw
+	}
+	journal, err := s.ReadJournal()
+	if err != nil {
+		return nil, err
+	}
+	replayed := 0
+	for _, e := range journal {
+		if !e.At.After(base.TakenAt) || e.At.After(at) {
+			continue
+		}
+		switch e.Op {
+		case JournalAdd, JournalUpdate:
+			if _, seen := lines[e.ID]; !seen {
+				order = append(order, e.ID)
+			}
+			lines[e.ID] = e.Beat
+		case JournalDelete:
+			delete(lines, e.ID)
+		default:
+			continue // A remap is recorded for the record; its beats follow as updates
+		}
+		replayed++
+	}
+
+	r := &rebuilt{base: *base, replayed: replayed}
+	var buf bytes.Buffer
+	written := make(map[string]bool, len(lines))
+	for _, id := range order {
+		if raw, ok := lines[id]; ok && !written[id] {
+			written[id] = true
+			r.ids = append(r.ids, id)
+			buf.Write(raw)
+			buf.WriteByte('\n')
+		}
+	}
+	r.data = buf.Bytes()
+	return r, nil
+}
+
+// ApplyRestore swaps a planned restore into place. The current store is
+// backed up first, and the restored one after, so later restores replay
+// from it. The new file is checked against the plan before the swap.
+func (s *JSONLStore) ApplyRestore(plan *RestorePlan) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if s.forwarding() {
+		return fmt.Errorf("a daemon is writing this store; stop it before restoring")
+	}
+	defer s.lock()()
+
+	if _, err := s.backupUnlocked(BackupPreRestore); err != nil {
+		return fmt.Errorf("failed to back up the current store: %w", err)
+	}
+	if s.files {
+		lines := parseLines(plan.data)
+		raw := make([][]byte, 0, len(lines))
+		for _, l := range lines {
+			raw = append(raw, l.raw)
+		}
+		if err := writeBeatFiles(s.filesDir(), raw); err != nil {
+			return fmt.Errorf("failed to write restored store: %w", err)
+		}
+		if _, err := s.backupUnlocked(BackupRestored); err != nil {
+			return fmt.Errorf("restored, but failed to back up the result: %w", err)
+		}
+		return nil
+	}
+	tmpPath := s.filePath + ".restore"
+	if err := os.WriteFile(tmpPath, plan.data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write restored store: %w", err)
+	}
+	written, err := os.ReadFile(tmpPath)
+	if err != nil || checksum(written) != plan.SHA256 {
+		os.Remove(tmpPath)
+		return fmt.Errorf("restored store does not match the verified plan")
+	}
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to swap in restored store: %w", err)
+	}
+	if _, err := s.backupUnlocked(BackupRestored); err != nil {
+		return fmt.Errorf("restored, but failed to back up the result: %w", err)
+	}
+	return nil
+}
+
+// parsedLine is a valid beat line of a JSONL file.
+type parsedLine struct {
+	id   string
+	raw  []byte
+	beat beat.Beat
+}
+
+// parseLines returns the lines of data that are beats, skipping the rest.
+func parseLines(data []byte) []parsedLine {
+	var out []parsedLine
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		var b beat.Beat
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &b) != nil || b.ID == "" {
+			continue
+		}
+		out = append(out, parsedLine{id: b.ID, raw: line, beat: b})
+	}
+	return out
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func shortSum(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
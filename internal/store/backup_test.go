@@ -0,0 +1,167 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// tick makes sure later writes are journaled after the returned moment.
+func tick() time.Time {
+	time.Sleep(5 * time.Millisecond)
+	at := time.Now().UTC()
+	time.Sleep(5 * time.Millisecond)
+	return at
+}
+
+func TestRestoreAt(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := beat.NewBeat("first", beat.Impetus{Label: "test"})
+	b := beat.NewBeat("second", beat.Impetus{Label: "test"})
+	a.ID, b.ID = "beat-20250101-001", "beat-20250101-002"
+	if err := s.AppendBulk([]*beat.Beat{a, b}); err != nil {
+		t.Fatal(err)
+	}
+	afterAdd := tick()
+	if _, err := s.Update(a.ID, func(x *beat.Beat) error {
+		x.Content = "first, edited"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := s.PlanRestore(afterAdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Base.Reason != BackupBaseline || plan.Beats != 2 || plan.Added != 1 || plan.Changed != 1 {
+		t.Fatalf("plan = %+v; want 2 beats from the baseline, 1 added back, 1 changed", plan)
+	}
+	if err := s.ApplyRestore(plan); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.Get(a.ID)
+	if got == nil || got.Content != "first" {
+		t.Errorf("restored %s = %+v, want the original content", a.ID, got)
+	}
+	if _, err := s.Get(b.ID); err != nil {
+		t.Errorf("deleted beat not restored: %v", err)
+	}
+
+	// The store before the restore is still reachable
+	backups, _ := s.Backups()
+	if len(backups) != 3 || backups[1].Reason != BackupPreRestore || backups[1].Beats != 1 {
+		t.Errorf("backups = %+v; want baseline, pre-restore (1 beat), restored", backups)
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := beat.NewBeat("use postgres", beat.Impetus{Label: "test"})
+	a.ID = "beat-20250101-001"
+	if err := s.Append(a); err != nil {
+		t.Fatal(err)
+	}
+	decided := tick()
+	if _, err := s.Update(a.ID, func(x *beat.Beat) error {
+		x.Content = "use sqlite"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(beat.NewBeat("later", beat.Impetus{Label: "test"})); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := s.AsOf(decided)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beats, err := view.ReadAll()
+	if err != nil || len(beats) != 1 || beats[0].Content != "use postgres" {
+		t.Errorf("ReadAll as of then = %+v, %v; want only the original beat", beats, err)
+	}
+	if results, _ := view.Search("sqlite", 0); len(results) != 0 {
+		t.Errorf("Search as of then found %d later edits", len(results))
+	}
+	if err := view.Append(beat.NewBeat("x", beat.Impetus{Label: "test"})); err == nil {
+		t.Error("Append to a view of the past succeeded")
+	}
+	if now, _ := s.ReadAll(); len(now) != 2 || now[0].Content != "use sqlite" {
+		t.Errorf("live store = %+v; want it untouched", now)
+	}
+}
+
+func TestRestoreBeforeHistory(t *testing.T) {
+	// A store that starts empty was empty before its baseline backup.
+	s, _ := NewJSONLStore(t.TempDir())
+	before := tick()
+	if err := s.Append(beat.NewBeat("x", beat.Impetus{Label: "test"})); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := s.PlanRestore(before.Add(-time.Hour))
+	if err != nil || plan.Beats != 0 || plan.Removed != 1 {
+		t.Fatalf("PlanRestore before an empty baseline = %+v, %v; want an empty store", plan, err)
+	}
+	view, err := s.AsOf(before.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beats, err := view.ReadAll(); err != nil || len(beats) != 0 {
+		t.Errorf("ReadAll before an empty baseline = %+v, %v", beats, err)
+	}
+
+	// Beats the store held before its first backup have no history.
+	dir := t.TempDir()
+	existing := `{"id":"beat-20250101-001","content":"from before backups"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "beats.jsonl"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = NewJSONLStore(dir)
+	before = tick()
+	if err := s.Append(beat.NewBeat("y", beat.Impetus{Label: "test"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AsOf(before.Add(-time.Hour)); err == nil || !strings.Contains(err.Error(), "already holds 1 beats") {
+		t.Errorf("AsOf before a non-empty first backup = %v, want an error naming its beats", err)
+	}
+}
+
+func TestVerifyBackupsDetectsTampering(t *testing.T) {
+	s, _ := NewJSONLStore(t.TempDir())
+	if err := s.Append(beat.NewBeat("x", beat.Impetus{Label: "test"})); err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Backup(BackupManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(s.Dir(), BackupsDir, b.File)
+	if err := os.WriteFile(path, []byte(`{"id":"beat-20250101-001","content":"forged"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := s.VerifyBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 || !checks[0].OK || checks[1].OK {
+		t.Errorf("checks = %+v; want the baseline ok and the manual backup flagged", checks)
+	}
+	if _, err := s.PlanRestore(time.Now()); err == nil {
+		t.Error("PlanRestore used a tampered backup")
+	}
+}
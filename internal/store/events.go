@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+// JournalEvents rebuilds the beat_added and beat_linked events recorded in
+// the journal since the given moment, oldest first, for bt events replay. A
+// beat_linked event is an update that links a bead the beat was not linked
+// to before; a bead remap renames links without linking anything new. An
+// update to a beat whose earlier state is unknown, from before the oldest
+// backup, is not counted as linking anything.
+func (s *JSONLStore) JournalEvents(since time.Time) ([]hooks.BeatEvent, error) {
+	entries, err := s.ReadJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	// links holds each beat's bead links as of the entry being replayed.
+	// The store as of since covers beats the journal never touched; replaying
+	// every entry in order brings the rest up to date.
+	links := make(map[string]beat.BeadLinks)
+	if view, err := s.AsOf(since); err == nil {
+		if beats, err := view.ReadAll(); err == nil {
+			for _, b := range beats {
+				links[b.ID] = b.LinkedBeads
+			}
+		}
+	}
+
+	var events []hooks.BeatEvent
+	for _, e := range entries {
+		replay := !e.At.Before(since)
+		switch e.Op {
+		case JournalDelete:
+			delete(links, e.ID)
+		case JournalRemap:
+			for id, l := range links {
+				l = append(beat.BeadLinks(nil), l...)
+				l.Rename(e.ID, e.To)
+				links[id] = l
+			}
+		case JournalAdd, JournalUpdate:
+			var b beat.Beat
+			if json.Unmarshal(e.Beat, &b) != nil {
+				continue
+			}
+			before, known := links[b.ID]
+			links[b.ID] = b.LinkedBeads
+			if !replay {
+				continue
+			}
+			if e.Op == JournalAdd {
+				// As live: the new beat's links travel in beat_added
+				events = append(events, hooks.BeatEvent{Event: hooks.EventBeatAdded, At: e.At, BeatID: b.ID, Beat: &b, Replay: true})
+				continue
+			}
+			if !known {
+				continue
+			}
+			for _, id := range newLinks(before, b.LinkedBeads) {
+				link, _ := b.LinkedBeads.Get(id)
+				events = append(events, hooks.BeatEvent{Event: hooks.EventBeatLinked, At: e.At, BeatID: b.ID, BeadID: id, Relation: link.Relation, Beat: &b, Replay: true})
+			}
+		}
+	}
+	return events, nil
+}
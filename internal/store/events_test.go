@@ -0,0 +1,73 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+func TestJournalEvents(t *testing.T) {
+	t.Cleanup(clock.Reset)
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	clock.Set(day)
+	early := beat.NewBeat("captured before the consumer", beat.Impetus{Label: "test"})
+	early.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1"})
+	if err := s.Append(early); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Set(day.Add(48 * time.Hour))
+	late := beat.NewBeat("captured after", beat.Impetus{Label: "test"})
+	if err := s.Append(late); err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(day.Add(49 * time.Hour))
+	link := func(id, bead string) {
+		if _, err := s.Update(id, func(b *beat.Beat) error {
+			b.LinkedBeads.Add(beat.BeadLink{BeadID: bead, Relation: beat.RelationEvidence})
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link(early.ID, "bd-2")
+	link(late.ID, "bd-1")
+	if _, err := s.Update(early.ID, func(b *beat.Beat) error { b.Content += "."; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RemapBead("bd-1", "gh-7", false); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.JournalEvents(day.Add(24 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []hooks.BeatEvent{
+		{Event: hooks.EventBeatAdded, BeatID: late.ID},
+		{Event: hooks.EventBeatLinked, BeatID: early.ID, BeadID: "bd-2", Relation: beat.RelationEvidence},
+		{Event: hooks.EventBeatLinked, BeatID: late.ID, BeadID: "bd-1", Relation: beat.RelationEvidence},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("JournalEvents() = %+v, want %d events", events, len(want))
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Event != w.Event || e.BeatID != w.BeatID || e.BeadID != w.BeadID || e.Relation != w.Relation || !e.Replay || e.Beat == nil {
+			t.Errorf("event %d = %+v, want %+v", i, e, w)
+		}
+	}
+
+	all, err := s.JournalEvents(time.Time{})
+	if err != nil || len(all) != 4 || all[0].BeatID != early.ID || all[0].Event != hooks.EventBeatAdded {
+		t.Errorf("JournalEvents(zero) = %+v, %v, want the first add too", all, err)
+	}
+}
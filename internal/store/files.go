@@ -0,0 +1,369 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// BeatFilesDir holds the beats of a store using the files backend, one
+// indented JSON file per beat, sharded by the date in its ID:
+// beats.d/2025/12/beat-20251204-001.json. A store with this directory uses
+// it instead of beats.jsonl.
+const BeatFilesDir = "beats.d"
+
+// Storage backends.
+const (
+	BackendJSONL = "jsonl" // beats.jsonl, one beat per line (the default)
+	BackendFiles = "files" // One file per beat under beats.d/
+)
+
+// miscShard holds the files of beats whose ID carries no date.
+const miscShard = "misc"
+
+var datedIDRe = regexp.MustCompile(`^beat-(\d{4})(\d{2})\d{2}-`)
+
+// Backend returns the storage backend the store uses.
+func (s *JSONLStore) Backend() string {
+	if s.files {
+		return BackendFiles
+	}
+	return BackendJSONL
+}
+
+// filesDir is the directory of the files backend.
+func (s *JSONLStore) filesDir() string {
+	return filepath.Join(s.dir, BeatFilesDir)
+}
+
+// beatFilePath returns the path of a beat's file relative to beats.d.
+func beatFilePath(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("beat ID %q cannot be used as a file name", id)
+	}
+	if m := datedIDRe.FindStringSubmatch(id); m != nil {
+		return filepath.Join(m[1], m[2], id+".json"), nil
+	}
+	return filepath.Join(miscShard, id+".json"), nil
+}
+
+// scanFiles reads beats.d in path order, which is ID order within each
+// month. Files that do not parse are kept as bad lines, numbered by their
+// position and named in the error.
+func (s *JSONLStore) scanFiles() ([]scannedLine, error) {
+	paths, err := beatFiles(s.filesDir())
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]scannedLine, 0, len(paths))
+	for i, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(s.filesDir(), rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read beat file: %w", err)
+		}
+		var raw bytes.Buffer
+		l := scannedLine{file: rel}
+		if err := json.Compact(&raw, data); err != nil {
+			l.bad = &BadLine{Line: i + 1, Text: string(data), Error: rel + ": " + err.Error()}
+		} else if l.raw = raw.Bytes(); json.Unmarshal(l.raw, &l.beat) != nil || l.beat.ID == "" {
+			l.bad = &BadLine{Line: i + 1, Text: string(data), Error: rel + ": not a beat with an id"}
+		} else if want, _ := beatFilePath(l.beat.ID); want != rel {
+			l.bad = &BadLine{Line: i + 1, Text: string(data), Error: fmt.Sprintf("%s: beat %s belongs in %s", rel, l.beat.ID, want)}
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+// beatFiles lists the beat files under root, relative to it, sorted.
+func beatFiles(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list beat files: %w", err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// writeBeatFile writes one beat under root, indented so it diffs line by
+// line, through a temporary file so a reader never sees it half written.
+func writeBeatFile(root, rel string, raw []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	path := filepath.Join(root, rel)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, out.Bytes()) {
+		return nil // Unchanged files keep their mtime and stay out of diffs
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, out.Bytes(), 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// appendFilesUnlocked writes new beats as files. A beat whose ID already
+// has a file is refused: unlike beats.jsonl, the directory cannot hold two
+// beats with one ID. Caller must hold the write lock.
+func (s *JSONLStore) appendFilesUnlocked(lines [][]byte) error {
+	for _, data := range lines {
+		var b beat.Beat
+		_ = json.Unmarshal(data, &b)
+		rel, err := beatFilePath(b.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(s.filesDir(), rel)); err == nil {
+			return fmt.Errorf("beat %s already exists in %s", b.ID, BeatFilesDir)
+		}
+		if err := writeBeatFile(s.filesDir(), rel, data); err != nil {
+			return fmt.Errorf("failed to write beat %s: %w", b.ID, err)
+		}
+	}
+	return nil
+}
+
+// writeBeatFiles makes root hold exactly the given beats: changed files are
+// rewritten, files of beats no longer there removed, and the rest left
+// alone. The caller of a store's beats.d must hold its write lock.
+func writeBeatFiles(root string, lines [][]byte) error {
+	want := make(map[string]bool, len(lines))
+	for _, data := range lines {
+		var b beat.Beat
+		if err := json.Unmarshal(data, &b); err != nil {
+			return fmt.Errorf("failed to write beats: %w", err)
+		}
+		rel, err := beatFilePath(b.ID)
+		if err != nil {
+			return err
+		}
+		if want[rel] {
+			return fmt.Errorf("beat %s appears twice; %s holds one file per beat", b.ID, BeatFilesDir)
+		}
+		want[rel] = true
+		if err := writeBeatFile(root, rel, data); err != nil {
+			return fmt.Errorf("failed to write beat %s: %w", b.ID, err)
+		}
+	}
+	existing, err := beatFiles(root)
+	if err != nil {
+		return err
+	}
+	for _, rel := range existing {
+		if want[rel] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(root, rel)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		// Drop the shard directories the removal emptied
+		for dir := filepath.Dir(rel); dir != "."; dir = filepath.Dir(dir) {
+			if os.Remove(filepath.Join(root, dir)) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// quarantineFilesUnlocked moves beat files that are not valid beats into
+// quarantine.jsonl and removes them. Caller must hold the write lock.
+func (s *JSONLStore) quarantineFilesUnlocked(lines []scannedLine) ([]BadLine, error) {
+	var bad []BadLine
+	var moved []QuarantinedLine
+	now := clock.Now().UTC()
+	for _, l := range lines {
+		if l.bad != nil {
+			bad = append(bad, *l.bad)
+			moved = append(moved, QuarantinedLine{BadLine: *l.bad, File: filepath.Join(BeatFilesDir, l.file), QuarantinedAt: now})
+		}
+	}
+	if len(bad) == 0 {
+		return nil, nil
+	}
+	if err := s.writeQuarantine(moved); err != nil {
+		return nil, err
+	}
+	for _, q := range moved {
+		if err := os.Remove(filepath.Join(s.dir, q.File)); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %w", q.File, err)
+		}
+	}
+	return bad, nil
+}
+
+// jsonlUnlocked returns the valid beats of the store as beats.jsonl would
+// hold them, for backups. Caller must hold the lock.
+func (s *JSONLStore) jsonlUnlocked() ([]byte, error) {
+	if !s.files {
+		data, err := os.ReadFile(s.filePath)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	}
+	lines, err := s.scanFiles()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, l := range lines {
+		if l.bad == nil {
+			buf.Write(l.raw)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// storeInfo is the os.FileInfo Stat returns for beats.d: the total size of
+// the beat files and the latest modification among them.
+type storeInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i storeInfo) Name() string       { return i.name }
+func (i storeInfo) Size() int64        { return i.size }
+func (i storeInfo) Mode() fs.FileMode  { return fs.ModeDir | 0755 }
+func (i storeInfo) ModTime() time.Time { return i.modTime }
+func (i storeInfo) IsDir() bool        { return true }
+func (i storeInfo) Sys() interface{}   { return nil }
+
+// Stat describes the store's data for change detection: beats.jsonl itself,
+// or for the files backend the beat files taken together, so a beat edited
+// by hand is noticed like any other write.
+func (s *JSONLStore) Stat() (os.FileInfo, error) {
+	if !s.files {
+		return os.Stat(s.filePath)
+	}
+	root, err := os.Stat(s.filesDir())
+	if err != nil {
+		return nil, err
+	}
+	info := storeInfo{name: BeatFilesDir, modTime: root.ModTime()}
+	err = filepath.WalkDir(s.filesDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if fi.ModTime().After(info.modTime) {
+			info.modTime = fi.ModTime()
+		}
+		if !d.IsDir() {
+			info.size += fi.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ConvertBackend moves the store to another backend and returns the number
+// of beats moved. The old data is kept beside the new as beats.jsonl.bak or
+// beats.d.bak. Malformed lines or files are quarantined first.
+func (s *JSONLStore) ConvertBackend(to string) (int, error) {
+	if to != BackendJSONL && to != BackendFiles {
+		return 0, fmt.Errorf("unknown backend %q (use %s or %s)", to, BackendJSONL, BackendFiles)
+	}
+	if err := s.writable(); err != nil {
+		return 0, err
+	}
+	if s.forwarding() {
+		return 0, fmt.Errorf("a daemon is writing this store; stop it before converting")
+	}
+	if s.Backend() == to {
+		return 0, fmt.Errorf("the store already uses the %s backend", to)
+	}
+	defer s.lock()()
+
+	if _, err := s.quarantineUnlocked(); err != nil {
+		return 0, err
+	}
+	if _, err := s.backupUnlocked(BackupPreConvert); err != nil {
+		return 0, fmt.Errorf("failed to back up the store: %w", err)
+	}
+	data, err := s.jsonlUnlocked()
+	if err != nil {
+		return 0, err
+	}
+	lines := parseLines(data)
+	raw := make([][]byte, 0, len(lines))
+	for _, l := range lines {
+		raw = append(raw, l.raw)
+	}
+
+	files := to == BackendFiles
+	oldPath, newPath := s.filePath, s.filesDir()
+	if !files {
+		oldPath, newPath = newPath, oldPath
+	}
+	staging := newPath + ".converting"
+	os.RemoveAll(staging)
+	if files {
+		err = writeBeatFiles(staging, raw)
+	} else {
+		err = os.WriteFile(staging, data, 0644)
+	}
+	if err != nil {
+		os.RemoveAll(staging)
+		return 0, fmt.Errorf("failed to write the converted store: %w", err)
+	}
+
+	bak := oldPath + ".bak"
+	os.RemoveAll(bak)
+	if _, err := os.Stat(oldPath); err == nil {
+		if err := os.Rename(oldPath, bak); err != nil {
+			os.RemoveAll(staging)
+			return 0, fmt.Errorf("failed to set aside %s: %w", oldPath, err)
+		}
+	}
+	if err := os.Rename(staging, newPath); err != nil {
+		return 0, fmt.Errorf("failed to move the converted store into place (the old data is in %s): %w", bak, err)
+	}
+	s.files = files
+	return len(raw), nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestFilesBackend(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2025, 12, 4, 9, 0, 0, 0, time.UTC)
+	for i, content := range []string{"first", "second", "third"} {
+		b := &beat.Beat{ID: beat.GenerateIDWithSequence(at, i+1), Content: content, CreatedAt: at, UpdatedAt: at}
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := s.ConvertBackend(BackendFiles)
+	if err != nil || n != 3 {
+		t.Fatalf("ConvertBackend(files) = %d, %v", n, err)
+	}
+	if s.Backend() != BackendFiles || s.Path() != filepath.Join(dir, BeatFilesDir) {
+		t.Fatalf("backend = %s at %s", s.Backend(), s.Path())
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultBeatsFile+".bak")); err != nil {
+		t.Errorf("beats.jsonl not kept aside: %v", err)
+	}
+	file := filepath.Join(dir, BeatFilesDir, "2025", "12", "beat-20251204-001.json")
+	data, err := os.ReadFile(file)
+	if err != nil || !strings.Contains(string(data), "\n  \"content\": \"first\"") {
+		t.Fatalf("beat file = %q, %v", data, err)
+	}
+
+	// A fresh store finds the backend, and writes touch only their beat
+	s, err = NewJSONLStore(dir)
+	if err != nil || s.Backend() != BackendFiles {
+		t.Fatalf("reopened store backend = %s, %v", s.Backend(), err)
+	}
+	if _, err := s.Update("beat-20251204-002", func(b *beat.Beat) error { b.Content = "second, edited"; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(file); string(after) != string(data) {
+		t.Error("update of another beat rewrote beat-20251204-001.json")
+	}
+	if err := s.Delete("beat-20251204-003"); err != nil {
+		t.Fatal(err)
+	}
+	other := &beat.Beat{ID: "legacy-note", Content: "no date", CreatedAt: at}
+	if err := s.Append(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(&beat.Beat{ID: "legacy-note", Content: "again"}); err == nil {
+		t.Error("second beat with one ID accepted")
+	}
+
+	// Hand edits are read back; a broken file is skipped and quarantined
+	if err := os.WriteFile(file, []byte(strings.Replace(string(data), "first", "first, by hand", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, BeatFilesDir, "2025", "12", "beat-20251204-009.json")
+	if err := os.WriteFile(broken, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	beats, bad, err := s.ReadAllTolerant()
+	if err != nil || len(beats) != 3 || len(bad) != 1 {
+		t.Fatalf("ReadAllTolerant = %d beats, %v, %v", len(beats), bad, err)
+	}
+	if b, _ := s.Get("beat-20251204-001"); b.Content != "first, by hand" {
+		t.Errorf("hand edit not read: %q", b.Content)
+	}
+	if moved, err := s.Quarantine(); err != nil || len(moved) != 1 {
+		t.Fatalf("Quarantine = %v, %v", moved, err)
+	}
+	if _, err := os.Stat(broken); !os.IsNotExist(err) {
+		t.Error("broken file left in place")
+	}
+
+	n, err = s.ConvertBackend(BackendJSONL)
+	if err != nil || n != 3 || s.Backend() != BackendJSONL {
+		t.Fatalf("ConvertBackend(jsonl) = %d, %v (backend %s)", n, err, s.Backend())
+	}
+	s, _ = NewJSONLStore(dir)
+	if b, err := s.Get("beat-20251204-002"); err != nil || b.Content != "second, edited" || s.Backend() != BackendJSONL {
+		t.Errorf("after converting back: %+v, %v", b, err)
+	}
+	if _, err := s.Get("beat-20251204-003"); err == nil {
+		t.Error("deleted beat came back")
+	}
+}
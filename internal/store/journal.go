@@ -0,0 +1,116 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// JournalFile is the append-only log of every write to beats.jsonl. With the
+// backups it lets a store be rebuilt as of any moment since journaling began.
+const JournalFile = "journal.jsonl"
+
+// Journal operations.
+const (
+	JournalAdd    = "add"
+	JournalUpdate = "update"
+	JournalDelete = "delete"
+	JournalRemap  = "remap" // A bead renamed across the store; replay skips it
+)
+
+// JournalEntry records one beat written to or removed from the store, or a
+// store-wide change such as a remap.
+type JournalEntry struct {
+	At   time.Time       `json:"at"`
+	Op   string          `json:"op"`
+	ID   string          `json:"id"`
+	Beat json.RawMessage `json:"beat,omitempty"` // The line as written, for add and update
+	To   string          `json:"to,omitempty"`   // For remap, the new bead ID; ID is the old one
+}
+
+// JournalPath returns the store's journal.
+func (s *JSONLStore) JournalPath() string {
+	return filepath.Join(s.dir, JournalFile)
+}
+
+// startJournalUnlocked makes sure the journal exists before a write. The
+// first write to a store takes a baseline backup, so the journal always has
+// a state to replay from. Caller must hold the write lock.
+func (s *JSONLStore) startJournalUnlocked() error {
+	if _, err := os.Stat(s.JournalPath()); !os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := s.backupUnlocked(BackupBaseline); err != nil {
+		return fmt.Errorf("failed to take baseline backup: %w", err)
+	}
+	f, err := os.OpenFile(s.JournalPath(), os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create journal: %w", err)
+	}
+	return f.Close()
+}
+
+// journalUnlocked appends entries to the journal.
+// Caller must hold the write lock.
+func (s *JSONLStore) journalUnlocked(entries []JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	f, err := os.OpenFile(s.JournalPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// beatEntries journals beats written with the given operation.
+func beatEntries(op string, at time.Time, beats ...*beat.Beat) []JournalEntry {
+	entries := make([]JournalEntry, 0, len(beats))
+	for _, b := range beats {
+		data, err := json.Marshal(b)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, JournalEntry{At: at, Op: op, ID: b.ID, Beat: data})
+	}
+	return entries
+}
+
+// ReadJournal returns the journal entries, oldest first. Lines that do not
+// parse, such as a half-written last entry, are skipped.
+func (s *JSONLStore) ReadJournal() ([]JournalEntry, error) {
+	data, err := os.ReadFile(s.JournalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []JournalEntry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var e JournalEntry
+		if len(bytes.TrimSpace(line)) > 0 && json.Unmarshal(line, &e) == nil && e.ID != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
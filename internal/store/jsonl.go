@@ -1,7 +1,6 @@
 package store
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,28 +12,45 @@ import (
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/config"
 	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/profile"
 )
 
 const (
 	DefaultBeatsDir  = ".beats"
 	DefaultBeatsFile = "beats.jsonl"
 	BeatsDirEnvVar   = "BEATS_DIR"
-	// GlobalBeatsStore is the canonical single store for all beats in werk.
-	// This replaces the scattered per-directory .beats/ stores.
-	GlobalBeatsStore = "/Users/moritzbierling/werk/.beats"
 )
 
+func init() {
+	// Session summaries are written by the hooks package, which cannot
+	// import the store
+	hooks.AppendBeat = func(beatsDir string, b *beat.Beat) error {
+		s, err := NewJSONLStore(beatsDir)
+		if err != nil {
+			return err
+		}
+		return s.AppendBulk([]*beat.Beat{b})
+	}
+}
+
 // JSONLStore manages beats in an append-only JSONL file.
 type JSONLStore struct {
 	dir      string
 	filePath string
 	mu       sync.RWMutex
+	serving  bool // Writes for other processes go through this store
+	files    bool // Beats live in beats.d/, one file each (see BeatFilesDir)
+
+	asOf    time.Time // Set on a read-only view of the past (see AsOf)
+	history []byte    // beats.jsonl as of asOf
 }
 
 // isValidBeatsDir checks if a directory is a valid .beats directory.
 // A valid .beats directory must exist and either:
-// - contain a beats.jsonl file, OR
+// - contain a beats.jsonl file or a beats.d directory, OR
 // - contain a hooks.json file (initialized but empty)
 func isValidBeatsDir(path string) bool {
 	info, err := os.Stat(path)
@@ -45,6 +61,9 @@ func isValidBeatsDir(path string) bool {
 	if _, err := os.Stat(filepath.Join(path, DefaultBeatsFile)); err == nil {
 		return true
 	}
+	if info, err := os.Stat(filepath.Join(path, BeatFilesDir)); err == nil && info.IsDir() {
+		return true
+	}
 	// Check for hooks.json (initialized project)
 	if _, err := os.Stat(filepath.Join(path, "hooks.json")); err == nil {
 		return true
@@ -71,17 +90,13 @@ func findBeatsDir(startDir string) string {
 	}
 }
 
-// GetBeatsDir returns the beats directory path with the following precedence:
+// GetBeatsDir returns the global beats store, the single store all beats go
+// to, with the following precedence:
 // 1. BEATS_DIR environment variable (if set)
-// 2. Global beats store at ~/werk/.beats/ (canonical single store)
+// 2. store in the user config file
+// 3. ~/werk/.beats/
 func GetBeatsDir() (string, error) {
-	// Check BEATS_DIR environment variable first
-	if envDir := os.Getenv(BeatsDirEnvVar); envDir != "" {
-		return envDir, nil
-	}
-
-	// Use the global beats store - all beats go to one place
-	return GlobalBeatsStore, nil
+	return config.Get().Store, nil
 }
 
 // DiscoverBeatsProjects finds all valid .beats directories under the given root.
@@ -175,37 +190,35 @@ func NewJSONLStore(dir string) (*JSONLStore, error) {
 		return nil, fmt.Errorf("failed to create beats directory: %w", err)
 	}
 
-	return &JSONLStore{
+	s := &JSONLStore{
 		dir:      dir,
 		filePath: filepath.Join(dir, DefaultBeatsFile),
-	}, nil
+	}
+	if info, err := os.Stat(s.filesDir()); err == nil && info.IsDir() {
+		s.files = true
+	}
+	return s, nil
 }
 
-// Append adds a new beat to the store.
+// Append adds a new beat to the store. Through a daemon, b.ID is updated
+// when its sequence was taken meanwhile.
 func (s *JSONLStore) Append(b *beat.Beat) error {
-	s.mu.Lock()
-
-	f, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		s.mu.Unlock()
-		return fmt.Errorf("failed to open beats file: %w", err)
+	if err := s.writable(); err != nil {
+		return err
 	}
-	defer f.Close()
-
-	data, err := json.Marshal(b)
-	if err != nil {
-		s.mu.Unlock()
-		return fmt.Errorf("failed to marshal beat: %w", err)
+	if ok, err := s.forwardAppend([]*beat.Beat{b}, true); ok {
+		return err
 	}
 
-	if _, err := f.Write(append(data, '\n')); err != nil {
-		s.mu.Unlock()
-		return fmt.Errorf("failed to write beat: %w", err)
+	unlock := s.lock()
+	if err := s.appendUnlocked([]*beat.Beat{b}); err != nil {
+		unlock()
+		return err
 	}
 
 	// Read all beats while still holding the lock
 	allBeats, _ := s.readAllUnlocked()
-	s.mu.Unlock()
+	unlock()
 
 	// Trigger hooks synchronously (fast enough, goroutine was exiting before completion)
 	s.triggerHooks(b, allBeats)
@@ -215,6 +228,7 @@ func (s *JSONLStore) Append(b *beat.Beat) error {
 
 // triggerHooks runs hook checks after a beat is added.
 func (s *JSONLStore) triggerHooks(newBeat *beat.Beat, allBeats []beat.Beat) {
+	defer profile.Start(profile.Hooks)()
 	hookMgr, err := hooks.NewManager(s.dir)
 	if err != nil {
 		return // Silently ignore hook errors
@@ -224,6 +238,16 @@ func (s *JSONLStore) triggerHooks(newBeat *beat.Beat, allBeats []beat.Beat) {
 	_ = hookMgr.OnBeatAdded(newBeat, allBeats)
 }
 
+// triggerLinked runs the hooks for beads an update linked a beat to.
+func (s *JSONLStore) triggerLinked(b *beat.Beat, beadIDs []string) {
+	defer profile.Start(profile.Hooks)()
+	hookMgr, err := hooks.NewManager(s.dir)
+	if err != nil {
+		return
+	}
+	_ = hookMgr.OnBeatLinked(b, beadIDs, b.UpdatedAt)
+}
+
 // ReadAll reads all beats from the store.
 func (s *JSONLStore) ReadAll() ([]beat.Beat, error) {
 	s.mu.RLock()
@@ -232,46 +256,26 @@ func (s *JSONLStore) ReadAll() ([]beat.Beat, error) {
 	return s.readAllUnlocked()
 }
 
+// readAllUnlocked reads the beats, skipping (and reporting once) lines that
+// do not parse so one interrupted write does not break every command.
 func (s *JSONLStore) readAllUnlocked() ([]beat.Beat, error) {
-	f, err := os.Open(s.filePath)
-	if os.IsNotExist(err) {
-		return []beat.Beat{}, nil
-	}
+	beats, bad, err := s.readTolerantUnlocked()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open beats file: %w", err)
-	}
-	defer f.Close()
-
-	var beats []beat.Beat
-	scanner := bufio.NewScanner(f)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		var b beat.Beat
-		if err := json.Unmarshal([]byte(line), &b); err != nil {
-			return nil, fmt.Errorf("failed to parse beat at line %d: %w", lineNum, err)
-		}
-		beats = append(beats, b)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read beats file: %w", err)
+		return nil, err
 	}
-
+	s.warnBadLines(bad)
 	return beats, nil
 }
 
-// Get retrieves a beat by ID.
+// Get retrieves a beat by ID, or by any short form ResolveID accepts.
 func (s *JSONLStore) Get(id string) (*beat.Beat, error) {
 	beats, err := s.ReadAll()
 	if err != nil {
 		return nil, err
 	}
+	if id, err = resolveID(beats, id); err != nil {
+		return nil, err
+	}
 
 	for i := range beats {
 		if beats[i].ID == id {
@@ -284,7 +288,7 @@ func (s *JSONLStore) Get(id string) (*beat.Beat, error) {
 
 // NextSequence returns the next sequence number for today's beats.
 func (s *JSONLStore) NextSequence() (int, error) {
-	return s.NextSequenceForDate(time.Now().UTC())
+	return s.NextSequenceForDate(clock.Now().UTC())
 }
 
 // NextSequenceForDate returns the next sequence number for beats on a specific date.
@@ -297,7 +301,7 @@ func (s *JSONLStore) NextSequenceForDate(date time.Time) (int, error) {
 		return 1, err
 	}
 
-	dateStr := date.UTC().Format("20060102")
+	dateStr := beat.IDDate(date)
 	prefix := fmt.Sprintf("beat-%s-", dateStr)
 
 	maxSeq := 0
@@ -313,8 +317,18 @@ func (s *JSONLStore) NextSequenceForDate(date time.Time) (int, error) {
 	return maxSeq + 1, nil
 }
 
-// Search performs a simple keyword search across beat content and impetus.
+// Search performs a simple keyword search across beat content and impetus,
+// weighted by the store's scoring.json.
 func (s *JSONLStore) Search(query string, maxResults int) ([]beat.SearchResult, error) {
+	cfg, err := LoadScoringConfig(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.SearchWithScoring(query, maxResults, cfg)
+}
+
+// SearchWithScoring performs a keyword search with explicit scoring weights.
+func (s *JSONLStore) SearchWithScoring(query string, maxResults int, cfg ScoringConfig) ([]beat.SearchResult, error) {
 	beats, err := s.ReadAll()
 	if err != nil {
 		return nil, err
@@ -327,13 +341,7 @@ func (s *JSONLStore) Search(query string, maxResults int) ([]beat.SearchResult,
 		contentLower := strings.ToLower(b.Content)
 		labelLower := strings.ToLower(b.Impetus.Label)
 
-		score := 0.0
-		if strings.Contains(contentLower, query) {
-			score += 0.5
-		}
-		if strings.Contains(labelLower, query) {
-			score += 0.5
-		}
+		score := cfg.KeywordScore(strings.Contains(contentLower, query), strings.Contains(labelLower, query))
 
 		if score > 0 {
 			results = append(results, beat.SearchResult{
@@ -408,11 +416,8 @@ func (s *JSONLStore) GetByLinkedBead(beadID string) ([]beat.Beat, error) {
 
 	var result []beat.Beat
 	for _, b := range beats {
-		for _, linked := range b.LinkedBeads {
-			if linked == beadID {
-				result = append(result, b)
-				break
-			}
+		if b.LinkedBeads.Has(beadID) {
+			result = append(result, b)
 		}
 	}
 
@@ -440,8 +445,12 @@ func (s *JSONLStore) MostRecent() (*beat.Beat, error) {
 	return mostRecent, nil
 }
 
-// Path returns the path to the JSONL file.
+// Path returns the path to the JSONL file, or to beats.d for the files
+// backend.
 func (s *JSONLStore) Path() string {
+	if s.files {
+		return s.filesDir()
+	}
 	return s.filePath
 }
 
@@ -450,25 +459,77 @@ func (s *JSONLStore) Dir() string {
 	return s.dir
 }
 
-// Update modifies a beat in place by rewriting the JSONL file.
-// The updater function receives a pointer to the beat and can modify it.
+// Update modifies a beat in place by rewriting the JSONL file. id may be
+// any short form ResolveID accepts. The updater function receives a pointer to the beat and can modify it.
 func (s *JSONLStore) Update(id string, updater func(*beat.Beat) error) (*beat.Beat, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	id, err := s.ResolveID(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.forwarding() {
+		return s.forwardUpdate(id, updater)
+	}
+	return s.updateLocal(id, updater)
+}
+
+// ConflictError is returned by UpdateIfUnchanged when the beat was changed
+// after the caller read it.
+type ConflictError struct {
+	ID       string
+	Expected time.Time // The updated_at the caller read
+	Current  time.Time // The updated_at stored now
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("beat %s was changed at %s, after the version expected (%s)",
+		e.ID, e.Current.Format(time.RFC3339Nano), e.Expected.Format(time.RFC3339Nano))
+}
+
+// UpdateIfUnchanged is Update for a caller that read the beat earlier and
+// changes it based on what it saw: unless the stored beat's UpdatedAt is
+// still expected, nothing is written and the error is a *ConflictError.
+func (s *JSONLStore) UpdateIfUnchanged(id string, expected time.Time, updater func(*beat.Beat) error) (*beat.Beat, error) {
+	return s.Update(id, func(b *beat.Beat) error {
+		if !b.UpdatedAt.Equal(expected) {
+			return &ConflictError{ID: id, Expected: expected, Current: b.UpdatedAt}
+		}
+		return updater(b)
+	})
+}
 
+// updateLocal is Update on beats.jsonl itself. Links the update added are
+// reported to the hooks once the lock is released.
+func (s *JSONLStore) updateLocal(id string, updater func(*beat.Beat) error) (*beat.Beat, error) {
+	unlock := s.lock()
+	updated, linked, err := s.updateUnlocked(id, updater)
+	unlock()
+	if err == nil && len(linked) > 0 {
+		s.triggerLinked(updated, linked)
+	}
+	return updated, err
+}
+
+// updateUnlocked applies an update and returns the beat with the beads it
+// newly links to. Caller must hold the write lock.
+func (s *JSONLStore) updateUnlocked(id string, updater func(*beat.Beat) error) (*beat.Beat, []string, error) {
 	beats, err := s.readAllUnlocked()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var updated *beat.Beat
+	var before beat.BeadLinks
 	found := false
 	for i := range beats {
 		if beats[i].ID == id {
+			before = append(before, beats[i].LinkedBeads...)
 			if err := updater(&beats[i]); err != nil {
-				return nil, fmt.Errorf("updater failed: %w", err)
+				return nil, nil, fmt.Errorf("updater failed: %w", err)
 			}
-			beats[i].UpdatedAt = time.Now().UTC()
+			beats[i].UpdatedAt = clock.Now().UTC()
 			updated = &beats[i]
 			found = true
 			break
@@ -476,21 +537,54 @@ func (s *JSONLStore) Update(id string, updater func(*beat.Beat) error) (*beat.Be
 	}
 
 	if !found {
-		return nil, fmt.Errorf("beat not found: %s", id)
+		return nil, nil, fmt.Errorf("beat not found: %s", id)
 	}
 
 	// Rewrite the entire file
+	if err := s.startJournalUnlocked(); err != nil {
+		return nil, nil, err
+	}
 	if err := s.rewriteUnlocked(beats); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	entries := beatEntries(JournalUpdate, clock.Now().UTC(), updated)
+	if updated.ID != id {
+		// The beat was renumbered: the old ID is gone
+		entries = append([]JournalEntry{{At: entries[0].At, Op: JournalDelete, ID: id}}, entries...)
+		entries[1].Op = JournalAdd
+	}
+	if err := s.journalUnlocked(entries); err != nil {
+		return nil, nil, err
 	}
 
-	return updated, nil
+	return updated, newLinks(before, updated.LinkedBeads), nil
 }
 
-// Delete removes a beat by ID.
+// newLinks returns the beads linked in after but not in before.
+func newLinks(before, after beat.BeadLinks) []string {
+	var ids []string
+	for _, id := range after.IDs() {
+		if !before.Has(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Delete removes a beat by ID, or by any short form ResolveID accepts.
 func (s *JSONLStore) Delete(id string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	if err := s.writable(); err != nil {
+		return err
+	}
+	id, err := s.ResolveID(id)
+	if err != nil {
+		return err
+	}
+	if _, ok, err := s.forward(writeRequest{Op: "delete", ID: id}); ok {
+		return err
+	}
+
+	defer s.lock()()
 
 	beats, err := s.readAllUnlocked()
 	if err != nil {
@@ -511,7 +605,13 @@ func (s *JSONLStore) Delete(id string) error {
 		return fmt.Errorf("beat not found: %s", id)
 	}
 
-	return s.rewriteUnlocked(filtered)
+	if err := s.startJournalUnlocked(); err != nil {
+		return err
+	}
+	if err := s.rewriteUnlocked(filtered); err != nil {
+		return err
+	}
+	return s.journalUnlocked([]JournalEntry{{At: clock.Now().UTC(), Op: JournalDelete, ID: id}})
 }
 
 // BeatExists checks if a beat with the given ID already exists.
@@ -530,35 +630,121 @@ func (s *JSONLStore) BeatExists(id string) (bool, error) {
 
 // AppendBulk appends multiple beats to the store in a single operation.
 func (s *JSONLStore) AppendBulk(beats []*beat.Beat) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
 	if len(beats) == 0 {
 		return nil
 	}
+	if ok, err := s.forwardAppend(beats, false); ok {
+		return err
+	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.lock()()
+	return s.appendUnlocked(beats)
+}
 
+// appendUnlocked writes beats to the end of the file.
+// Caller must hold the write lock.
+func (s *JSONLStore) appendUnlocked(beats []*beat.Beat) error {
+	lines := make([][]byte, 0, len(beats))
+	for _, b := range beats {
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("failed to marshal beat %s: %w", b.ID, err)
+		}
+		lines = append(lines, data)
+	}
+	return s.appendLinesUnlocked(lines)
+}
+
+// AppendRaw appends beats given as JSON lines, keeping fields the Beat type
+// does not know. Every line must hold a beat with an ID. Raw lines are
+// written directly, never forwarded to a daemon.
+func (s *JSONLStore) AppendRaw(lines [][]byte) error {
+	if err := s.writable(); err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	for i, line := range lines {
+		var b beat.Beat
+		if err := json.Unmarshal(line, &b); err != nil || b.ID == "" {
+			return fmt.Errorf("line %d is not a beat", i+1)
+		}
+	}
+	defer s.lock()()
+	return s.appendLinesUnlocked(lines)
+}
+
+// appendLinesUnlocked writes beat lines to the end of the file and journals
+// them. Caller must hold the write lock.
+func (s *JSONLStore) appendLinesUnlocked(lines [][]byte) error {
+	if err := s.startJournalUnlocked(); err != nil {
+		return err
+	}
+	if s.files {
+		if err := s.appendFilesUnlocked(lines); err != nil {
+			return err
+		}
+		return s.journalUnlocked(addEntries(lines))
+	}
+	partial := s.endsWithoutNewline()
 	f, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open beats file: %w", err)
 	}
 	defer f.Close()
 
-	for _, b := range beats {
-		data, err := json.Marshal(b)
-		if err != nil {
-			return fmt.Errorf("failed to marshal beat %s: %w", b.ID, err)
+	if partial {
+		// Leave the interrupted line on its own so it stays one bad line
+		if _, err := f.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("failed to write beats file: %w", err)
 		}
+	}
+	for _, data := range lines {
 		if _, err := f.Write(append(data, '\n')); err != nil {
+			var b beat.Beat
+			_ = json.Unmarshal(data, &b)
 			return fmt.Errorf("failed to write beat %s: %w", b.ID, err)
 		}
 	}
 
-	return nil
+	return s.journalUnlocked(addEntries(lines))
+}
+
+// addEntries journals beat lines as added.
+func addEntries(lines [][]byte) []JournalEntry {
+	now := clock.Now().UTC()
+	entries := make([]JournalEntry, 0, len(lines))
+	for _, data := range lines {
+		var b beat.Beat
+		_ = json.Unmarshal(data, &b)
+		entries = append(entries, JournalEntry{At: now, Op: JournalAdd, ID: b.ID, Beat: data})
+	}
+	return entries
 }
 
-// rewriteUnlocked rewrites the JSONL file with the given beats.
+// rewriteUnlocked rewrites the JSONL file with the given beats, first moving
+// any malformed lines to the quarantine so they are not lost.
 // Caller must hold the write lock.
 func (s *JSONLStore) rewriteUnlocked(beats []beat.Beat) error {
+	if _, err := s.quarantineUnlocked(); err != nil {
+		return err
+	}
+	if s.files {
+		lines := make([][]byte, 0, len(beats))
+		for _, b := range beats {
+			data, err := json.Marshal(b)
+			if err != nil {
+				return fmt.Errorf("failed to marshal beat %s: %w", b.ID, err)
+			}
+			lines = append(lines, data)
+		}
+		return writeBeatFiles(s.filesDir(), lines)
+	}
+
 	// Write to temp file first for atomicity
 	tmpPath := s.filePath + ".tmp"
 	f, err := os.Create(tmpPath)
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -106,14 +107,14 @@ func TestJSONLStore_Update(t *testing.T) {
 	}
 
 	updated, err := store.Update(b.ID, func(b *beat.Beat) error {
-		b.LinkedBeads = append(b.LinkedBeads, "bead-123")
+		b.LinkedBeads.Add(beat.BeadLink{BeadID: "bead-123"})
 		return nil
 	})
 	if err != nil {
 		t.Fatalf("Update() error = %v", err)
 	}
 
-	if len(updated.LinkedBeads) != 1 || updated.LinkedBeads[0] != "bead-123" {
+	if len(updated.LinkedBeads) != 1 || updated.LinkedBeads[0].BeadID != "bead-123" {
 		t.Errorf("Update() LinkedBeads = %v, want [bead-123]", updated.LinkedBeads)
 	}
 
@@ -124,6 +125,42 @@ func TestJSONLStore_Update(t *testing.T) {
 	}
 }
 
+func TestJSONLStore_UpdateIfUnchanged(t *testing.T) {
+	store, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJSONLStore() error = %v", err)
+	}
+	b := beat.NewBeat("original content", beat.Impetus{Label: "test"})
+	if err := store.Append(b); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	read, _ := store.Get(b.ID)
+
+	first, err := store.UpdateIfUnchanged(b.ID, read.UpdatedAt, func(b *beat.Beat) error {
+		b.Content = "first edit"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateIfUnchanged() error = %v", err)
+	}
+
+	// A second writer that read the same version loses
+	_, err = store.UpdateIfUnchanged(b.ID, read.UpdatedAt, func(b *beat.Beat) error {
+		b.Content = "second edit"
+		return nil
+	})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("UpdateIfUnchanged() with a stale version error = %v, want a ConflictError", err)
+	}
+	if !conflict.Current.Equal(first.UpdatedAt) {
+		t.Errorf("ConflictError.Current = %v, want %v", conflict.Current, first.UpdatedAt)
+	}
+	if got, _ := store.Get(b.ID); got.Content != "first edit" {
+		t.Errorf("Content = %q after a conflict, want %q", got.Content, "first edit")
+	}
+}
+
 func TestJSONLStore_NextSequence(t *testing.T) {
 	dir := t.TempDir()
 	store, err := NewJSONLStore(dir)
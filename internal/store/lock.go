@@ -0,0 +1,30 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LockFile is held, exclusively, by whichever process is writing the store,
+// so separate bt processes do not interleave appends or rewrites.
+const LockFile = "beats.lock"
+
+// lock takes the store's write lock: the in-process mutex, then the lock on
+// .beats/beats.lock. If the lock file cannot be opened the store falls back
+// to the mutex alone. The returned function releases both.
+func (s *JSONLStore) lock() (unlock func()) {
+	s.mu.Lock()
+	f, err := os.OpenFile(filepath.Join(s.dir, LockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return s.mu.Unlock
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return s.mu.Unlock
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// Separate stores on one directory stand in for separate processes: only
+// the lock file keeps their rewrites from losing each other's edits.
+func TestLockSerializesStores(t *testing.T) {
+	dir := t.TempDir()
+	const n = 16
+	seed, _ := NewJSONLStore(dir)
+	beats := make([]*beat.Beat, n)
+	for i := range beats {
+		beats[i] = beat.NewBeat("original", beat.Impetus{Label: "test"})
+		beats[i].ID = fmt.Sprintf("beat-20250101-%03d", i+1)
+	}
+	if err := seed.AppendBulk(beats); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, b := range beats {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			s, _ := NewJSONLStore(dir)
+			if _, err := s.Update(id, func(b *beat.Beat) error {
+				b.Content = "edited " + id
+				return nil
+			}); err != nil {
+				t.Error(err)
+			}
+		}(b.ID)
+	}
+	wg.Wait()
+
+	got, err := seed.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range got {
+		if b.Content != "edited "+b.ID {
+			t.Errorf("%s = %q; a concurrent rewrite lost its edit", b.ID, b.Content)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+const (
+	// RegistryFile lists the known project stores. It lives next to the
+	// config file (e.g. ~/.config/beats/stores.json).
+	RegistryFile = "stores.json"
+	// GlobalStoreName names the global store, which is always registered.
+	GlobalStoreName = "global"
+	// AllStores selects every registered store.
+	AllStores = "all"
+)
+
+// StoreEntry is a store known to the registry.
+type StoreEntry struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`              // The .beats directory
+	Project string `json:"project,omitempty"` // Directory whose captures go to this store
+	Global  bool   `json:"global,omitempty"`
+}
+
+// Registry holds the global store and the registered project stores.
+type Registry struct {
+	Stores []StoreEntry `json:"stores"` // Project stores, sorted by name
+	path   string
+}
+
+// RegistryPath returns the registry file, in the config file's directory.
+func RegistryPath() string {
+	p := config.Path()
+	if p == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(p), RegistryFile)
+}
+
+// LoadRegistry reads the registry. A missing file is an empty registry.
+func LoadRegistry() (*Registry, error) {
+	r := &Registry{path: RegistryPath()}
+	if r.path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", r.path, err)
+	}
+	return r, nil
+}
+
+// Save writes the registry.
+func (r *Registry) Save() error {
+	if r.path == "" {
+		return fmt.Errorf("no config directory for %s", RegistryFile)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// Global returns the global store entry.
+func (r *Registry) Global() StoreEntry {
+	return StoreEntry{Name: GlobalStoreName, Path: config.Get().Store, Global: true}
+}
+
+// All returns the global store followed by the project stores.
+func (r *Registry) All() []StoreEntry {
+	return append([]StoreEntry{r.Global()}, r.Stores...)
+}
+
+// Add registers a project store. path is either a .beats directory or the
+// project directory holding one; name defaults to the project directory's
+// name. The store does not have to exist yet.
+func (r *Registry) Add(name, path string) (*StoreEntry, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	entry := StoreEntry{Path: abs, Project: filepath.Dir(abs)}
+	if filepath.Base(abs) != DefaultBeatsDir {
+		entry.Path = filepath.Join(abs, DefaultBeatsDir)
+		entry.Project = abs
+	}
+	entry.Name = name
+	if entry.Name == "" {
+		entry.Name = filepath.Base(entry.Project)
+	}
+	switch {
+	case entry.Name == GlobalStoreName || entry.Name == AllStores:
+		return nil, fmt.Errorf("store name %q is reserved", entry.Name)
+	case strings.ContainsAny(entry.Name, ", "+string(filepath.Separator)):
+		return nil, fmt.Errorf("store name %q may not contain commas, spaces or path separators", entry.Name)
+	case sameDir(entry.Path, r.Global().Path):
+		return nil, fmt.Errorf("%s is the global store", entry.Path)
+	}
+	for _, s := range r.Stores {
+		if s.Name == entry.Name {
+			return nil, fmt.Errorf("store %q is already registered (%s)", s.Name, s.Path)
+		}
+		if sameDir(s.Path, entry.Path) {
+			return nil, fmt.Errorf("%s is already registered as %q", s.Path, s.Name)
+		}
+	}
+	r.Stores = append(r.Stores, entry)
+	sort.Slice(r.Stores, func(i, j int) bool { return r.Stores[i].Name < r.Stores[j].Name })
+	return &entry, nil
+}
+
+// Remove unregisters a project store by name. Its beats are left in place.
+func (r *Registry) Remove(name string) error {
+	for i, s := range r.Stores {
+		if s.Name == name {
+			r.Stores = append(r.Stores[:i], r.Stores[i+1:]...)
+			return nil
+		}
+	}
+	if name == GlobalStoreName {
+		return fmt.Errorf("the global store cannot be removed")
+	}
+	return fmt.Errorf("unknown store: %s", name)
+}
+
+// Lookup resolves a store by name, or by path to a registered or existing
+// .beats directory.
+func (r *Registry) Lookup(ref string) (*StoreEntry, error) {
+	for _, s := range r.All() {
+		if s.Name == ref {
+			return &s, nil
+		}
+	}
+	abs, err := filepath.Abs(ref)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []string{abs}
+	if filepath.Base(abs) != DefaultBeatsDir {
+		candidates = append(candidates, filepath.Join(abs, DefaultBeatsDir))
+	}
+	for _, c := range candidates {
+		for _, s := range r.All() {
+			if sameDir(s.Path, c) {
+				return &s, nil
+			}
+		}
+	}
+	for _, c := range candidates {
+		if isValidBeatsDir(c) {
+			return &StoreEntry{Name: filepath.Base(filepath.Dir(c)), Path: c, Project: filepath.Dir(c)}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown store: %s (see bt stores list)", ref)
+}
+
+// Select resolves a --store value: a store name or path, a comma-separated
+// list of them, or "all".
+func (r *Registry) Select(spec string) ([]StoreEntry, error) {
+	if strings.TrimSpace(spec) == AllStores {
+		return r.All(), nil
+	}
+	var out []StoreEntry
+	seen := make(map[string]bool)
+	for _, ref := range strings.Split(spec, ",") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		s, err := r.Lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[s.Path] {
+			seen[s.Path] = true
+			out = append(out, *s)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no store selected")
+	}
+	return out, nil
+}
+
+// ForDir returns the project store whose project directory most closely
+// contains dir, or nil when dir is outside every registered project.
+func (r *Registry) ForDir(dir string) *StoreEntry {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	var best *StoreEntry
+	for i, s := range r.Stores {
+		if s.Project == "" || !within(abs, s.Project) {
+			continue
+		}
+		if best == nil || len(s.Project) > len(best.Project) {
+			best = &r.Stores[i]
+		}
+	}
+	return best
+}
+
+// ForWorkspace returns the project store for a Factory session workspace,
+// the session's working directory encoded by hooks.EncodeWorkspace. A
+// workspace under a project directory matches it too; the longest project
+// wins. The encoding turns separators into dashes, so a workspace is only
+// taken to be under a project when the subdirectory it starts with exists:
+// "werk-beats-old" is not under "werk/beats" unless "werk/beats/old" is a
+// directory. It returns nil when no project matches.
+func (r *Registry) ForWorkspace(workspace string) *StoreEntry {
+	if workspace == "" {
+		return nil
+	}
+	var best *StoreEntry
+	for i, s := range r.Stores {
+		if s.Project == "" {
+			continue
+		}
+		encoded := hooks.EncodeWorkspace(s.Project)
+		if workspace != encoded && !(strings.HasPrefix(workspace, encoded+"-") && hasSubdir(s.Project, workspace[len(encoded)+1:])) {
+			continue
+		}
+		if best == nil || len(s.Project) > len(best.Project) {
+			best = &r.Stores[i]
+		}
+	}
+	return best
+}
+
+// RouteDir returns the store new beats captured in dir go to: the project
+// store for dir, else the global store. A store pinned with BEATS_DIR is
+// always used as is.
+func RouteDir(dir string) (string, error) {
+	cfg := config.Get()
+	if pinned() {
+		return cfg.Store, nil
+	}
+	r, err := LoadRegistry()
+	if err != nil {
+		return "", err
+	}
+	if s := r.ForDir(dir); s != nil {
+		return s.Path, nil
+	}
+	return cfg.Store, nil
+}
+
+// hasSubdir reports whether dir has a subdirectory that rest, an encoded
+// relative path, starts with.
+func hasSubdir(dir, rest string) bool {
+	for i := range len(rest) + 1 {
+		if i < len(rest) && rest[i] != '-' {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, rest[:i])); i > 0 && err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteWorkspace returns the project store a session from workspace belongs
+// to, or "" when none matches or the store is pinned with BEATS_DIR.
+func RouteWorkspace(workspace string) (string, error) {
+	if pinned() {
+		return "", nil
+	}
+	r, err := LoadRegistry()
+	if err != nil {
+		return "", err
+	}
+	if s := r.ForWorkspace(workspace); s != nil {
+		return s.Path, nil
+	}
+	return "", nil
+}
+
+// pinned reports whether BEATS_DIR names the store, overriding routing.
+func pinned() bool {
+	return strings.HasPrefix(config.Get().Sources["store"], config.SourceEnv)
+}
+
+// within reports whether path is dir or inside it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func sameDir(a, b string) bool {
+	return filepath.Clean(a) == filepath.Clean(b)
+}
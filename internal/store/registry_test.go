@@ -0,0 +1,111 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/hooks"
+)
+
+func TestRegistry(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("BEATS_CONFIG", filepath.Join(tmp, "config", "config.json"))
+	t.Setenv("BEATS_DIR", "")
+	t.Setenv("HOME", tmp)
+
+	reg, err := LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	project := filepath.Join(tmp, "werk", "beats")
+	nested := filepath.Join(project, "tools", "bt")
+	if _, err := reg.Add("", project); err != nil {
+		t.Fatalf("Add(project) error = %v", err)
+	}
+	if _, err := reg.Add("bt", filepath.Join(nested, DefaultBeatsDir)); err != nil {
+		t.Fatalf("Add(nested) error = %v", err)
+	}
+	if _, err := reg.Add("", project); err == nil {
+		t.Error("Add() accepted a duplicate store")
+	}
+	if _, err := reg.Add(GlobalStoreName, filepath.Join(tmp, "x")); err == nil {
+		t.Error("Add() accepted the reserved name global")
+	}
+	if err := reg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reg, err = LoadRegistry()
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+	if got := len(reg.All()); got != 3 {
+		t.Fatalf("All() = %d stores, want 3 (global + 2)", got)
+	}
+	if s, err := reg.Lookup(project); err != nil || s.Name != "beats" {
+		t.Errorf("Lookup(project dir) = %v, %v; want beats", s, err)
+	}
+	selected, err := reg.Select("beats,global")
+	if err != nil || len(selected) != 2 {
+		t.Errorf("Select(beats,global) = %v, %v", selected, err)
+	}
+	if _, err := reg.Select("nope"); err == nil {
+		t.Error("Select(nope) succeeded")
+	}
+
+	routes := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(project, "internal"), filepath.Join(project, DefaultBeatsDir)},
+		{filepath.Join(nested, "cmd"), filepath.Join(nested, DefaultBeatsDir)},
+		{filepath.Join(tmp, "werk", "beats-old"), filepath.Join(tmp, "werk", DefaultBeatsDir)},
+	}
+	for _, r := range routes {
+		got, err := RouteDir(r.dir)
+		if err != nil || got != r.want {
+			t.Errorf("RouteDir(%s) = %q, %v; want %q", r.dir, got, err, r.want)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(project, "internal", "store"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	encoded := hooks.EncodeWorkspace(project)
+	workspaces := []struct {
+		workspace string
+		want      string
+	}{
+		{encoded, filepath.Join(project, DefaultBeatsDir)},
+		{encoded + "-internal-store", filepath.Join(project, DefaultBeatsDir)},
+		{encoded + "-tools-bt", filepath.Join(nested, DefaultBeatsDir)},
+		{encoded + "-old", ""}, // A sibling such as werk/beats-old
+	}
+	for _, w := range workspaces {
+		got, err := RouteWorkspace(w.workspace)
+		if err != nil || got != w.want {
+			t.Errorf("RouteWorkspace(%s) = %q, %v; want %q", w.workspace, got, err, w.want)
+		}
+	}
+
+	// A store pinned with BEATS_DIR is never rerouted
+	pinned := filepath.Join(tmp, "pinned")
+	t.Setenv("BEATS_DIR", pinned)
+	if got, _ := RouteDir(project); got != pinned {
+		t.Errorf("RouteDir() with BEATS_DIR = %q, want %q", got, pinned)
+	}
+	if got, _ := RouteWorkspace(encoded); got != "" {
+		t.Errorf("RouteWorkspace() with BEATS_DIR = %q, want none", got)
+	}
+
+	if err := reg.Remove("bt"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := reg.Remove(GlobalStoreName); err == nil {
+		t.Error("Remove(global) succeeded")
+	}
+	if _, err := os.Stat(RegistryPath()); err != nil {
+		t.Errorf("registry file missing: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+)
+
+// RemapBead renames a bead across the store, for a bead that moved or was
+// renamed in its tracker: links to from become links to to, keeping their
+// relation and history, and meta naming it follows (see Beat.RenameBead).
+// The beats are rewritten in one pass under the write lock, which a serving
+// daemon also takes, and the journal records the remap followed by the beats
+// it changed. It returns their IDs; with dryRun nothing is written.
+func (s *JSONLStore) RemapBead(from, to string, dryRun bool) ([]string, error) {
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("remap needs the old and the new bead ID")
+	}
+	if from == to {
+		return nil, fmt.Errorf("bead %s would be remapped to itself", from)
+	}
+	if !dryRun {
+		if err := s.writable(); err != nil {
+			return nil, err
+		}
+	}
+	defer s.lock()()
+
+	beats, err := s.readAllUnlocked()
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now().UTC()
+	ids := []string{}
+	var changed []*beat.Beat
+	for i := range beats {
+		if beats[i].RenameBead(from, to) {
+			beats[i].UpdatedAt = now
+			ids = append(ids, beats[i].ID)
+			changed = append(changed, &beats[i])
+		}
+	}
+	if dryRun || len(changed) == 0 {
+		return ids, nil
+	}
+
+	if err := s.startJournalUnlocked(); err != nil {
+		return nil, err
+	}
+	if err := s.rewriteUnlocked(beats); err != nil {
+		return nil, err
+	}
+	entries := append([]JournalEntry{{At: now, Op: JournalRemap, ID: from, To: to}}, beatEntries(JournalUpdate, now, changed...)...)
+	return ids, s.journalUnlocked(entries)
+}
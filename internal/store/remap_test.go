@@ -0,0 +1,69 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestRemapBead(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := beat.NewBeat("linked to the old bead", beat.Impetus{Label: "test", Meta: map[string]string{"bead_closed:bd-1": "2025-01-02T00:00:00Z"}})
+	old.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1", Relation: beat.RelationEvidence})
+	both := beat.NewBeat("linked to both", beat.Impetus{Label: "test"})
+	both.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-1"})
+	both.LinkedBeads.Add(beat.BeadLink{BeadID: "gh-7"})
+	other := beat.NewBeat("linked elsewhere", beat.Impetus{Label: "test"})
+	other.LinkedBeads.Add(beat.BeadLink{BeadID: "bd-2"})
+	for i, b := range []*beat.Beat{old, both, other} {
+		b.ID = beat.GenerateIDWithSequence(b.CreatedAt, i+1)
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := s.RemapBead("bd-1", "gh-7", true)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("RemapBead(dry run) = %v, %v, want two beats", ids, err)
+	}
+	if got, _ := s.Get(old.ID); !got.LinkedBeads.Has("bd-1") {
+		t.Fatal("dry run changed the store")
+	}
+
+	if _, err := s.RemapBead("bd-1", "gh-7", false); err != nil {
+		t.Fatalf("RemapBead() error = %v", err)
+	}
+	got, _ := s.Get(old.ID)
+	if link, ok := got.LinkedBeads.Get("gh-7"); !ok || link.Relation != beat.RelationEvidence || got.LinkedBeads.Has("bd-1") {
+		t.Errorf("remapped links = %+v, want gh-7 as evidence", got.LinkedBeads)
+	}
+	if got.Impetus.Meta["bead_closed:gh-7"] == "" || got.Impetus.Meta["bead_closed:bd-1"] != "" {
+		t.Errorf("meta = %v, want the closure mark moved to gh-7", got.Impetus.Meta)
+	}
+	if got, _ := s.Get(both.ID); len(got.LinkedBeads) != 1 || !got.LinkedBeads.Has("gh-7") {
+		t.Errorf("beat linked to both = %+v, want one link to gh-7", got.LinkedBeads)
+	}
+	if got, _ := s.Get(other.ID); !got.LinkedBeads.Has("bd-2") {
+		t.Errorf("unrelated beat lost its link: %+v", got.LinkedBeads)
+	}
+
+	journal, err := s.ReadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaps, updates int
+	for _, e := range journal {
+		switch {
+		case e.Op == JournalRemap && e.ID == "bd-1" && e.To == "gh-7":
+			remaps++
+		case e.Op == JournalUpdate:
+			updates++
+		}
+	}
+	if remaps != 1 || updates != 2 {
+		t.Errorf("journal has %d remap and %d update entries, want 1 and 2", remaps, updates)
+	}
+}
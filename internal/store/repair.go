@@ -0,0 +1,253 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/profile"
+)
+
+// QuarantineFile holds the lines of beats.jsonl that could not be parsed,
+// moved aside by bt doctor --fix or before the file is rewritten.
+const QuarantineFile = "quarantine.jsonl"
+
+// BadLine is a line of beats.jsonl that is not a valid beat.
+type BadLine struct {
+	Line  int    `json:"line"`
+	Text  string `json:"text"`
+	Error string `json:"error"`
+}
+
+// QuarantinedLine is a BadLine as recorded in quarantine.jsonl.
+type QuarantinedLine struct {
+	BadLine
+	File          string    `json:"file"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// scannedLine is one non-blank line of beats.jsonl, or one beat file.
+type scannedLine struct {
+	raw  []byte
+	beat beat.Beat
+	bad  *BadLine
+	file string // Path under beats.d, for the files backend
+}
+
+// scanLines reads beats.jsonl line by line, keeping lines that do not parse
+// instead of failing on them.
+func (s *JSONLStore) scanLines() ([]scannedLine, error) {
+	var reader *bufio.Reader
+	if s.files && s.asOf.IsZero() {
+		return s.scanFiles()
+	}
+	if !s.asOf.IsZero() {
+		reader = bufio.NewReader(bytes.NewReader(s.history))
+	} else {
+		f, err := os.Open(s.filePath)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open beats file: %w", err)
+		}
+		defer f.Close()
+		reader = bufio.NewReader(f)
+	}
+
+	var lines []scannedLine
+	lineNum := 0
+	for {
+		raw, err := reader.ReadBytes('\n')
+		if len(raw) > 0 {
+			lineNum++
+			raw = bytes.TrimRight(raw, "\r\n")
+			if len(bytes.TrimSpace(raw)) > 0 {
+				l := scannedLine{raw: raw}
+				if perr := json.Unmarshal(raw, &l.beat); perr != nil {
+					l.bad = &BadLine{Line: lineNum, Text: string(raw), Error: perr.Error()}
+				} else if l.beat.ID == "" {
+					l.bad = &BadLine{Line: lineNum, Text: string(raw), Error: "beat has no id"}
+				}
+				lines = append(lines, l)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read beats file: %w", err)
+		}
+	}
+	return lines, nil
+}
+
+// ReadAllTolerant reads every beat that parses and returns the lines that
+// do not, rather than failing the whole store on one bad line.
+func (s *JSONLStore) ReadAllTolerant() ([]beat.Beat, []BadLine, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readTolerantUnlocked()
+}
+
+func (s *JSONLStore) readTolerantUnlocked() ([]beat.Beat, []BadLine, error) {
+	defer profile.Start(profile.JSONLParse)()
+	lines, err := s.scanLines()
+	if err != nil {
+		return nil, nil, err
+	}
+	beats := []beat.Beat{}
+	var bad []BadLine
+	for _, l := range lines {
+		if l.bad != nil {
+			bad = append(bad, *l.bad)
+			continue
+		}
+		beats = append(beats, l.beat)
+	}
+	return beats, bad, nil
+}
+
+// badLineWarnings remembers which files were reported, so a process warns
+// about a damaged store once.
+var badLineWarnings sync.Map
+
+// warnBadLines reports skipped lines on stderr.
+func (s *JSONLStore) warnBadLines(bad []BadLine) {
+	if len(bad) == 0 {
+		return
+	}
+	if _, seen := badLineWarnings.LoadOrStore(s.Path(), true); seen {
+		return
+	}
+	if s.files {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed beat file(s) in %s (first: %s); run 'bt doctor --fix' to quarantine them\n",
+			len(bad), s.Path(), bad[0].Error)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed line(s) in %s (first at line %d); run 'bt doctor --fix' to quarantine them\n",
+		len(bad), s.filePath, bad[0].Line)
+}
+
+// Quarantine moves the lines of beats.jsonl that are not valid beats to
+// quarantine.jsonl and rewrites the file with the rest, unchanged. It
+// returns the lines moved.
+func (s *JSONLStore) Quarantine() ([]BadLine, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	defer s.lock()()
+	return s.quarantineUnlocked()
+}
+
+func (s *JSONLStore) quarantineUnlocked() ([]BadLine, error) {
+	lines, err := s.scanLines()
+	if err != nil {
+		return nil, err
+	}
+	if s.files {
+		return s.quarantineFilesUnlocked(lines)
+	}
+	var bad []BadLine
+	var good bytes.Buffer
+	for _, l := range lines {
+		if l.bad != nil {
+			bad = append(bad, *l.bad)
+			continue
+		}
+		good.Write(l.raw)
+		good.WriteByte('\n')
+	}
+	if len(bad) == 0 {
+		return nil, nil
+	}
+
+	// Save the bad lines before they leave beats.jsonl
+	now := clock.Now().UTC()
+	moved := make([]QuarantinedLine, 0, len(bad))
+	for _, b := range bad {
+		moved = append(moved, QuarantinedLine{BadLine: b, File: DefaultBeatsFile, QuarantinedAt: now})
+	}
+	if err := s.writeQuarantine(moved); err != nil {
+		return nil, err
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, good.Bytes(), 0644); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return bad, nil
+}
+
+// writeQuarantine appends lines to quarantine.jsonl.
+func (s *JSONLStore) writeQuarantine(lines []QuarantinedLine) error {
+	q, err := os.OpenFile(filepath.Join(s.dir, QuarantineFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open quarantine file: %w", err)
+	}
+	for _, l := range lines {
+		data, err := json.Marshal(l)
+		if err == nil {
+			_, err = q.Write(append(data, '\n'))
+		}
+		if err != nil {
+			q.Close()
+			return fmt.Errorf("failed to write quarantine file: %w", err)
+		}
+	}
+	if err := q.Close(); err != nil {
+		return fmt.Errorf("failed to write quarantine file: %w", err)
+	}
+	return nil
+}
+
+// ReadQuarantine returns the lines quarantined so far.
+func (s *JSONLStore) ReadQuarantine() ([]QuarantinedLine, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, QuarantineFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []QuarantinedLine
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var q QuarantinedLine
+		if len(bytes.TrimSpace(line)) > 0 && json.Unmarshal(line, &q) == nil {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+// endsWithoutNewline reports whether beats.jsonl ends in a partial line, as
+// left by an interrupted write, which the next append must not extend.
+func (s *JSONLStore) endsWithoutNewline() bool {
+	f, err := os.Open(s.filePath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false
+	}
+	return last[0] != '\n'
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestTolerantReadAndQuarantine(t *testing.T) {
+	dir := t.TempDir()
+	good := `{"id":"beat-20250101-001","content":"kept"}`
+	// An interrupted write leaves a partial last line without a newline
+	data := good + "\nnot json\n\n" + `{"id":"beat-20250101-002","cont`
+	if err := os.WriteFile(filepath.Join(dir, DefaultBeatsFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	beats, err := s.ReadAll()
+	if err != nil || len(beats) != 1 {
+		t.Fatalf("ReadAll() = %d beats, %v; want the one valid beat", len(beats), err)
+	}
+	_, bad, err := s.ReadAllTolerant()
+	if err != nil || len(bad) != 2 || bad[0].Line != 2 || bad[1].Line != 4 {
+		t.Fatalf("ReadAllTolerant() bad = %+v, %v", bad, err)
+	}
+
+	// Appending after the partial line must not merge into it
+	if err := s.Append(beat.NewBeat("new", beat.Impetus{Label: "test"})); err != nil {
+		t.Fatal(err)
+	}
+	beats, bad, _ = s.ReadAllTolerant()
+	if len(beats) != 2 || len(bad) != 2 {
+		t.Fatalf("after Append: %d beats, %d bad; want 2 and 2", len(beats), len(bad))
+	}
+
+	moved, err := s.Quarantine()
+	if err != nil || len(moved) != 2 {
+		t.Fatalf("Quarantine() = %+v, %v", moved, err)
+	}
+	raw, _ := os.ReadFile(s.Path())
+	if !strings.HasPrefix(string(raw), good+"\n") {
+		t.Errorf("valid lines were not kept verbatim:\n%s", raw)
+	}
+	q, err := s.ReadQuarantine()
+	if err != nil || len(q) != 2 || q[0].Text != "not json" {
+		t.Errorf("ReadQuarantine() = %+v, %v", q, err)
+	}
+	if _, bad, _ = s.ReadAllTolerant(); len(bad) != 0 {
+		t.Errorf("bad lines left after Quarantine: %+v", bad)
+	}
+}
+
+func TestRewriteQuarantinesBadLines(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"id":"beat-20250101-001","content":"a"}` + "\n{broken\n"
+	if err := os.WriteFile(filepath.Join(dir, DefaultBeatsFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := NewJSONLStore(dir)
+	if _, err := s.Update("beat-20250101-001", func(b *beat.Beat) error {
+		b.Content = "b"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	q, _ := s.ReadQuarantine()
+	if len(q) != 1 || q[0].Text != "{broken" {
+		t.Errorf("quarantine = %+v, want the broken line kept", q)
+	}
+}
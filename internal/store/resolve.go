@@ -0,0 +1,95 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// maxCandidates bounds the IDs an AmbiguousIDError lists.
+const maxCandidates = 10
+
+// AmbiguousIDError is returned when a short ID matches more than one beat.
+type AmbiguousIDError struct {
+	Ref        string
+	Candidates []string // Newest first
+}
+
+func (e *AmbiguousIDError) Error() string {
+	list := e.Candidates
+	more := ""
+	if len(list) > maxCandidates {
+		list = list[:maxCandidates]
+		more = fmt.Sprintf(", and %d more", len(e.Candidates)-maxCandidates)
+	}
+	return fmt.Sprintf("%q matches %d beats: %s%s", e.Ref, len(e.Candidates), strings.Join(list, ", "), more)
+}
+
+// ResolveID returns the ID of the beat ref names. Besides a full ID, ref can
+// be:
+//   - a prefix of the ID, with or without "beat-": 20251204-00, beat-202512
+//   - any part of the ID that spans the dash: 1204-001, 1204-00
+//   - @N, the Nth most recently created beat, @1 being the newest
+//
+// A ref that matches several beats is an *AmbiguousIDError listing them.
+func (s *JSONLStore) ResolveID(ref string) (string, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	return resolveID(beats, ref)
+}
+
+// resolveID is ResolveID over beats already read.
+func resolveID(beats []beat.Beat, ref string) (string, error) {
+	for _, b := range beats {
+		if b.ID == ref {
+			return ref, nil
+		}
+	}
+	if n, ok := strings.CutPrefix(ref, "@"); ok {
+		i, err := strconv.Atoi(n)
+		if err != nil || i < 1 {
+			return "", fmt.Errorf("invalid beat reference %s: use @1 for the newest beat", ref)
+		}
+		if i > len(beats) {
+			return "", fmt.Errorf("beat not found: %s (the store has %d beats)", ref, len(beats))
+		}
+		return newestFirst(beats)[i-1].ID, nil
+	}
+
+	short := strings.TrimPrefix(ref, "beat-")
+	var matches []beat.Beat
+	if short != "" {
+		for _, b := range beats {
+			id := strings.TrimPrefix(b.ID, "beat-")
+			// A bare sequence such as "001" would match a beat from every day
+			if strings.HasPrefix(id, short) || strings.Contains(short, "-") && strings.Contains(id, short) {
+				matches = append(matches, b)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("beat not found: %s", ref)
+	case 1:
+		return matches[0].ID, nil
+	}
+	err := &AmbiguousIDError{Ref: ref}
+	for _, b := range newestFirst(matches) {
+		err.Candidates = append(err.Candidates, b.ID)
+	}
+	return "", err
+}
+
+// newestFirst returns beats sorted by creation time, newest first.
+func newestFirst(beats []beat.Beat) []beat.Beat {
+	sorted := append([]beat.Beat(nil), beats...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted
+}
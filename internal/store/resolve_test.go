@@ -0,0 +1,53 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestResolveID(t *testing.T) {
+	day := func(d, seq int) beat.Beat {
+		at := time.Date(2025, 12, d, 9, seq, 0, 0, time.UTC)
+		return beat.Beat{ID: beat.GenerateIDWithSequence(at, seq), CreatedAt: at}
+	}
+	beats := []beat.Beat{day(3, 1), day(4, 1), day(4, 2), day(14, 1)}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"beat-20251204-001", "beat-20251204-001"},
+		{"20251204-002", "beat-20251204-002"},
+		{"beat-20251203", "beat-20251203-001"},
+		{"1204-001", "beat-20251204-001"},
+		{"14-001", "beat-20251214-001"},
+		{"1203-0", "beat-20251203-001"},
+		{"@1", "beat-20251214-001"},
+		{"@3", "beat-20251204-001"},
+	}
+	for _, tt := range tests {
+		got, err := resolveID(beats, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveID(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	_, err := resolveID(beats, "4-001")
+	var ambiguous *AmbiguousIDError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("resolveID(4-001) error = %v, want an AmbiguousIDError", err)
+	}
+	if want := []string{"beat-20251214-001", "beat-20251204-001"}; len(ambiguous.Candidates) != 2 ||
+		ambiguous.Candidates[0] != want[0] || ambiguous.Candidates[1] != want[1] {
+		t.Errorf("Candidates = %v, want %v", ambiguous.Candidates, want)
+	}
+
+	for _, ref := range []string{"001", "@0", "@5", "beat-20260101", ""} {
+		if got, err := resolveID(beats, ref); err == nil {
+			t.Errorf("resolveID(%q) = %q, want an error", ref, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// ScoringConfigFile holds search scoring settings, relative to the beats directory.
+const ScoringConfigFile = "scoring.json"
+
+// ScoringConfig collects the weights and cutoffs used to rank results.
+// Robot commands echo the applied values so results can be reproduced.
+type ScoringConfig struct {
+	KeywordContentWeight float64 `json:"keyword_content_weight"` // Score for a match in beat content
+	KeywordImpetusWeight float64 `json:"keyword_impetus_weight"` // Score for a match in the impetus label
+	SemanticMinScore     float64 `json:"semantic_min_score"`     // Drop semantic/TF-IDF results below this similarity
+	ContextInferenceMin  float64 `json:"context_inference_min"`  // Minimum similarity to infer a WALD directory
+	TopicSimilarity      float64 `json:"topic_similarity"`       // Minimum similarity for a beat to join a topic
+}
+
+// DefaultScoringConfig returns the built-in scoring values.
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		KeywordContentWeight: 0.5,
+		KeywordImpetusWeight: 0.5,
+		SemanticMinScore:     0,
+		ContextInferenceMin:  0.3,
+		TopicSimilarity:      0.65,
+	}
+}
+
+// LoadScoringConfig reads scoring.json, using defaults for missing fields or
+// when the file does not exist.
+func LoadScoringConfig(beatsDir string) (ScoringConfig, error) {
+	cfg := DefaultScoringConfig()
+	data, err := os.ReadFile(filepath.Join(beatsDir, ScoringConfigFile))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DefaultScoringConfig(), fmt.Errorf("invalid %s: %w", ScoringConfigFile, err)
+	}
+	return cfg, nil
+}
+
+// Override applies per-command settings given as a partial JSON object;
+// fields that are absent keep their configured values.
+func (c ScoringConfig) Override(raw json.RawMessage) (ScoringConfig, error) {
+	if len(raw) == 0 {
+		return c, nil
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, fmt.Errorf("invalid scoring override: %w", err)
+	}
+	return c, nil
+}
+
+// KeywordScore scores a keyword match in content and/or impetus label.
+func (c ScoringConfig) KeywordScore(inContent, inImpetus bool) float64 {
+	score := 0.0
+	if inContent {
+		score += c.KeywordContentWeight
+	}
+	if inImpetus {
+		score += c.KeywordImpetusWeight
+	}
+	return score
+}
+
+// filterMinScore drops results scoring below min.
+func filterMinScore(results []beat.SearchResult, min float64) []beat.SearchResult {
+	if min <= 0 {
+		return results
+	}
+	kept := results[:0]
+	for _, r := range results {
+		if r.Score >= min {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
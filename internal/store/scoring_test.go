@@ -0,0 +1,42 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadScoringConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadScoringConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != DefaultScoringConfig() {
+		t.Errorf("missing file: got %+v, want defaults", cfg)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ScoringConfigFile), []byte(`{"semantic_min_score": 0.4}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadScoringConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SemanticMinScore != 0.4 || cfg.KeywordContentWeight != 0.5 {
+		t.Errorf("partial file: got %+v, want min 0.4 and default weights", cfg)
+	}
+
+	over, err := cfg.Override(json.RawMessage(`{"keyword_impetus_weight": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if over.KeywordImpetusWeight != 2 || over.SemanticMinScore != 0.4 {
+		t.Errorf("override: got %+v", over)
+	}
+	if got := over.KeywordScore(true, true); got != 2.5 {
+		t.Errorf("KeywordScore = %v, want 2.5", got)
+	}
+}
@@ -1,130 +1,49 @@
 package store
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"math"
-	"net/http"
-	"os"
-	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/bierlingm/beats/internal/beat"
-)
-
-const (
-	defaultOllamaURL    = "http://localhost:11434"
-	defaultEmbedModel   = "embeddinggemma"
-	embeddingsCacheFile = "embeddings_cache.json"
+	"github.com/bierlingm/beats/internal/config"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/llm"
 )
 
 // SemanticSearcher provides semantic search via Ollama embeddings.
 type SemanticSearcher struct {
-	jsonl     *JSONLStore
-	cacheDir  string
-	ollamaURL string
-	model     string
-	cache     map[string][]float64
+	jsonl  *JSONLStore
+	store  *embeddings.Store
+	ollama *embeddings.OllamaClient
+	cache  *embeddings.TextCache
 }
 
-// NewSemanticSearcher creates a new semantic searcher using Ollama.
+// NewSemanticSearcher creates a new semantic searcher using Ollama and the
+// store's embedding index, in the model that index was built with.
 func NewSemanticSearcher(jsonl *JSONLStore) (*SemanticSearcher, error) {
-	cacheDir := filepath.Join(jsonl.Dir(), ".semantic_cache")
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create cache dir: %w", err)
-	}
-
-	s := &SemanticSearcher{
-		jsonl:     jsonl,
-		cacheDir:  cacheDir,
-		ollamaURL: defaultOllamaURL,
-		model:     defaultEmbedModel,
-		cache:     make(map[string][]float64),
-	}
-
-	s.loadCache()
-	return s, nil
+	embStore, err := embeddings.NewStore(jsonl.Dir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding store: %w", err)
+	}
+	cache := embeddings.OpenDefaultCache(jsonl.Dir())
+	ollama := embeddings.NewOllamaClient()
+	ollama.SetModel(embStore.Model())
+	ollama.SetCache(cache)
+
+	return &SemanticSearcher{
+		jsonl:  jsonl,
+		store:  embStore,
+		ollama: ollama,
+		cache:  cache,
+	}, nil
 }
 
 // Available checks if Ollama is running and has an embedding model.
 func (s *SemanticSearcher) Available() bool {
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Get(s.ollamaURL + "/api/tags")
-	if err != nil {
-		return false
-	}
-	defer func() { _ = resp.Body.Close() }()
-	return resp.StatusCode == 200
-}
-
-func (s *SemanticSearcher) loadCache() {
-	data, err := os.ReadFile(filepath.Join(s.cacheDir, embeddingsCacheFile))
-	if err != nil {
-		return
-	}
-	_ = json.Unmarshal(data, &s.cache)
-}
-
-func (s *SemanticSearcher) saveCache() {
-	data, _ := json.Marshal(s.cache)
-	_ = os.WriteFile(filepath.Join(s.cacheDir, embeddingsCacheFile), data, 0644)
-}
-
-// getEmbedding fetches embedding from Ollama or cache.
-func (s *SemanticSearcher) getEmbedding(text string) ([]float64, error) {
-	cacheKey := fmt.Sprintf("%x", text)[:32]
-	if emb, ok := s.cache[cacheKey]; ok {
-		return emb, nil
-	}
-
-	reqBody := map[string]interface{}{
-		"model":  s.model,
-		"prompt": text,
-	}
-	jsonBody, _ := json.Marshal(reqBody)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Post(s.ollamaURL+"/api/embeddings", "application/json", bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("ollama request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Embedding []float64 `json:"embedding"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	s.cache[cacheKey] = result.Embedding
-	return result.Embedding, nil
-}
-
-// cosineSimilarity calculates similarity between two vectors.
-func cosineSimilarity(a, b []float64) float64 {
-	if len(a) != len(b) || len(a) == 0 {
-		return 0
-	}
-
-	var dotProduct, normA, normB float64
-	for i := range a {
-		dotProduct += a[i] * b[i]
-		normA += a[i] * a[i]
-		normB += b[i] * b[i]
-	}
-
-	if normA == 0 || normB == 0 {
-		return 0
-	}
-	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
+	return s.ollama.IsAvailable()
 }
 
 // formatBeatText creates searchable text from a beat.
@@ -138,53 +57,45 @@ func formatBeatText(b beat.Beat) string {
 
 // Search performs semantic search using Ollama embeddings.
 func (s *SemanticSearcher) Search(query string, maxResults int) ([]beat.SearchResult, error) {
-	queryEmb, err := s.getEmbedding(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to embed query: %w", err)
-	}
+	return s.SearchContext(context.Background(), query, maxResults)
+}
 
+// SearchContext is Search, stopping with the context's cause once it is
+// done. Beats are scored by their best-matching chunk in the embedding
+// store, as bt search --semantic does; beats not embedded yet are embedded
+// and stored first, so an interrupted search keeps what it computed.
+func (s *SemanticSearcher) SearchContext(ctx context.Context, query string, maxResults int) ([]beat.SearchResult, error) {
 	beats, err := s.jsonl.ReadAll()
 	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = s.cache.Save() }()
 
-	type scoredBeat struct {
-		beat  beat.Beat
-		score float64
+	_, err = embeddings.ComputeMissingWithOptions(ctx, beats, s.store, s.ollama, embeddings.ComputeOptions{BatchSize: 32})
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
 	}
-	var scored []scoredBeat
-
-	for _, b := range beats {
-		text := formatBeatText(b)
-		beatEmb, err := s.getEmbedding(text)
-		if err != nil {
-			continue
-		}
-
-		score := cosineSimilarity(queryEmb, beatEmb)
-		scored = append(scored, scoredBeat{beat: b, score: score})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed beats: %w", err)
 	}
 
-	s.saveCache()
-
-	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].score > scored[j].score
-	})
-
-	if len(scored) > maxResults {
-		scored = scored[:maxResults]
+	found, err := embeddings.SemanticSearch(ctx, query, beats, s.store, s.ollama, maxResults)
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
 
 	var results []beat.SearchResult
-	for _, sb := range scored {
+	for _, r := range found {
 		results = append(results, beat.SearchResult{
-			ID:      sb.beat.ID,
-			Score:   sb.score,
-			Content: sb.beat.Content,
-			Impetus: sb.beat.Impetus,
+			ID:      r.ID,
+			Score:   r.Score,
+			Content: r.Content,
+			Impetus: r.Impetus,
 		})
 	}
-
 	return results, nil
 }
 
@@ -202,10 +113,27 @@ type SemanticSearchOutput struct {
 	Fallback bool                `json:"fallback,omitempty"`
 }
 
-// HybridSearch performs semantic search with FTS5 fallback.
+// HybridSearch performs semantic search, falling back to a local TF-IDF
+// model when no embedding provider is available.
 func HybridSearch(jsonl *JSONLStore, query string, maxResults int, semantic bool) (*SemanticSearchOutput, error) {
+	cfg, err := LoadScoringConfig(jsonl.Dir())
+	if err != nil {
+		return nil, err
+	}
+	return HybridSearchWithScoring(jsonl, query, maxResults, semantic, cfg)
+}
+
+// HybridSearchWithScoring is HybridSearch with explicit scoring settings.
+func HybridSearchWithScoring(jsonl *JSONLStore, query string, maxResults int, semantic bool, cfg ScoringConfig) (*SemanticSearchOutput, error) {
+	return HybridSearchWithScoringContext(context.Background(), jsonl, query, maxResults, semantic, cfg)
+}
+
+// HybridSearchWithScoringContext is HybridSearchWithScoring under a context.
+// A semantic search cut short returns the context's cause rather than
+// falling back, since the fallback would only run past the deadline.
+func HybridSearchWithScoringContext(ctx context.Context, jsonl *JSONLStore, query string, maxResults int, semantic bool, cfg ScoringConfig) (*SemanticSearchOutput, error) {
 	if !semantic {
-		results, err := jsonl.Search(query, maxResults)
+		results, err := jsonl.SearchWithScoring(query, maxResults, cfg)
 		if err != nil {
 			return nil, err
 		}
@@ -216,62 +144,41 @@ func HybridSearch(jsonl *JSONLStore, query string, maxResults int, semantic bool
 	}
 
 	searcher, err := NewSemanticSearcher(jsonl)
-	if err != nil {
-		results, err := jsonl.Search(query, maxResults)
-		if err != nil {
-			return nil, err
+	if err == nil && searcher.Available() {
+		results, err := searcher.SearchContext(ctx, query, maxResults)
+		if err == nil {
+			return &SemanticSearchOutput{
+				Results: filterMinScore(results, cfg.SemanticMinScore),
+				Mode:    "semantic",
+			}, nil
 		}
-		return &SemanticSearchOutput{
-			Results:  results,
-			Mode:     "keyword",
-			Fallback: true,
-		}, nil
-	}
-
-	if !searcher.Available() {
-		results, err := jsonl.Search(query, maxResults)
-		if err != nil {
+		if ctx.Err() != nil {
 			return nil, err
 		}
-		return &SemanticSearchOutput{
-			Results:  results,
-			Mode:     "keyword",
-			Fallback: true,
-		}, nil
 	}
 
-	results, err := searcher.Search(query, maxResults)
+	results, err := jsonl.TFIDFSearch(query, maxResults)
 	if err != nil {
-		results, err := jsonl.Search(query, maxResults)
-		if err != nil {
-			return nil, err
-		}
-		return &SemanticSearchOutput{
-			Results:  results,
-			Mode:     "keyword",
-			Fallback: true,
-		}, nil
+		return nil, err
 	}
-
 	return &SemanticSearchOutput{
-		Results: results,
-		Mode:    "semantic",
+		Results:  filterMinScore(results, cfg.SemanticMinScore),
+		Mode:     "tfidf",
+		Fallback: true,
 	}, nil
 }
 
 // Status returns semantic search availability info.
 func SemanticStatus() map[string]interface{} {
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Get(defaultOllamaURL + "/api/tags")
-	available := err == nil && resp != nil && resp.StatusCode == 200
-	if resp != nil {
-		_ = resp.Body.Close()
-	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	available := llm.New(cfg.OllamaURL).Available(ctx)
 
 	return map[string]interface{}{
 		"available":    available,
 		"backend":      "ollama",
-		"model":        defaultEmbedModel,
+		"model":        cfg.EmbedModel,
 		"capabilities": []string{"semantic_search", "embedding_similarity"},
 	}
 }
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/embeddings"
+)
+
+// keywordVector embeds text as which of a few words it contains.
+func keywordVector(text string) []float64 {
+	var v []float64
+	for _, word := range []string{"kafka", "pricing", "filler"} {
+		if strings.Contains(text, word) {
+			v = append(v, 1)
+		} else {
+			v = append(v, 0)
+		}
+	}
+	return v
+}
+
+func TestSemanticSearchChunks(t *testing.T) {
+	var beatRequests atomic.Int32
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			fmt.Fprint(w, `{"models": [{"name": "nomic-embed-text:latest"}]}`)
+		case "/api/embed":
+			beatRequests.Add(1)
+			var req struct{ Input []string }
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			var out struct {
+				Embeddings [][]float64 `json:"embeddings"`
+			}
+			for _, text := range req.Input {
+				out.Embeddings = append(out.Embeddings, keywordVector(text))
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		case "/api/embeddings":
+			var req struct{ Prompt string }
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_ = json.NewEncoder(w).Encode(map[string][]float64{"embedding": keywordVector(req.Prompt)})
+		}
+	}))
+	defer ollama.Close()
+	t.Setenv("BEATS_OLLAMA_URL", ollama.URL)
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Kafka is only in the second chunk; embedded whole, the beat would
+	// score 0.707 against the query
+	long := strings.Repeat("filler ", embeddings.ChunkWords) + "\n\nkafka partitions"
+	for _, b := range []*beat.Beat{
+		{ID: "beat-20260101-001", Content: long},
+		{ID: "beat-20260101-002", Content: "pricing notes"},
+	} {
+		if err := s.Append(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	searcher, err := NewSemanticSearcher(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := searcher.Search("kafka", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].ID != "beat-20260101-001" || math.Abs(results[0].Score-1) > 1e-9 {
+		t.Fatalf("results = %+v, want beat-20260101-001 first with score 1", results)
+	}
+
+	embStore, err := embeddings.NewStore(s.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embStore.Count() != 2 {
+		t.Errorf("embedding store has %d beat(s), want both stored by the search", embStore.Count())
+	}
+
+	// The stored vectors are reused
+	before := beatRequests.Load()
+	searcher, err = NewSemanticSearcher(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := searcher.Search("kafka", 10); err != nil {
+		t.Fatal(err)
+	}
+	if n := beatRequests.Load() - before; n != 0 {
+		t.Errorf("second search embedded beats %d time(s), want 0", n)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	_ "modernc.org/sqlite"
 
 	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/profile"
 )
 
 const DefaultDBFile = "beats.db"
@@ -22,6 +26,12 @@ type SQLiteStore struct {
 	db     *sql.DB
 	dbPath string
 	jsonl  *JSONLStore
+
+	// RawQueries passes search queries to FTS5 MATCH untouched, for users
+	// who write FTS5 syntax (NEAR, OR, column filters, prefixes). A query
+	// that does not parse is then an error. By default each word is quoted
+	// so ':', '"' and '-' are searched for as text.
+	RawQueries bool
 }
 
 // NewSQLiteStore creates a new SQLite store that indexes the given JSONL store.
@@ -47,7 +57,33 @@ func NewSQLiteStore(jsonl *JSONLStore) (*SQLiteStore, error) {
 	return s, nil
 }
 
+// sqliteSchemaVersion is stored as the database's user_version. An index
+// built by an older version is dropped and rebuilt from the JSONL file.
+// Version 2 stores times to the nanosecond and rebuilds full-text indexes
+// left stale by REPLACE upserts.
+const sqliteSchemaVersion = 2
+
+// indexTime is how created_at and updated_at are stored: UTC to the
+// nanosecond at a fixed width, so an edit within the same second is seen
+// and the columns compare as text in time order.
+const indexTime = "2006-01-02T15:04:05.000000000Z07:00"
+
 func (s *SQLiteStore) initSchema() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version < sqliteSchemaVersion {
+		if _, err := s.db.Exec(`
+			DROP TABLE IF EXISTS beats_fts;
+			DROP TABLE IF EXISTS attachments_fts;
+			DROP TABLE IF EXISTS beats;
+			DROP TABLE IF EXISTS sync_state;
+		`); err != nil {
+			return fmt.Errorf("failed to drop outdated index: %w", err)
+		}
+	}
+
 	schema := `
 	CREATE TABLE IF NOT EXISTS beats (
 		id TEXT PRIMARY KEY,
@@ -59,6 +95,7 @@ func (s *SQLiteStore) initSchema() error {
 		impetus_meta TEXT,
 		references_json TEXT,
 		entities_json TEXT,
+		entities_text TEXT,
 		linked_beads_json TEXT
 	);
 
@@ -78,33 +115,44 @@ func (s *SQLiteStore) initSchema() error {
 
 	CREATE TRIGGER IF NOT EXISTS beats_ai AFTER INSERT ON beats BEGIN
 		INSERT INTO beats_fts(rowid, id, content, impetus_label, impetus_raw, entities_text)
-		VALUES (new.rowid, new.id, new.content, new.impetus_label, new.impetus_raw, '');
+		VALUES (new.rowid, new.id, new.content, new.impetus_label, new.impetus_raw, new.entities_text);
 	END;
 
 	CREATE TRIGGER IF NOT EXISTS beats_ad AFTER DELETE ON beats BEGIN
 		INSERT INTO beats_fts(beats_fts, rowid, id, content, impetus_label, impetus_raw, entities_text)
-		VALUES ('delete', old.rowid, old.id, old.content, old.impetus_label, old.impetus_raw, '');
+		VALUES ('delete', old.rowid, old.id, old.content, old.impetus_label, old.impetus_raw, old.entities_text);
 	END;
 
 	CREATE TRIGGER IF NOT EXISTS beats_au AFTER UPDATE ON beats BEGIN
 		INSERT INTO beats_fts(beats_fts, rowid, id, content, impetus_label, impetus_raw, entities_text)
-		VALUES ('delete', old.rowid, old.id, old.content, old.impetus_label, old.impetus_raw, '');
+		VALUES ('delete', old.rowid, old.id, old.content, old.impetus_label, old.impetus_raw, old.entities_text);
 		INSERT INTO beats_fts(rowid, id, content, impetus_label, impetus_raw, entities_text)
-		VALUES (new.rowid, new.id, new.content, new.impetus_label, new.impetus_raw, '');
+		VALUES (new.rowid, new.id, new.content, new.impetus_label, new.impetus_raw, new.entities_text);
 	END;
 
+	CREATE VIRTUAL TABLE IF NOT EXISTS attachments_fts USING fts5(
+		beat_id UNINDEXED,
+		locator UNINDEXED,
+		label UNINDEXED,
+		text
+	);
+
 	CREATE TABLE IF NOT EXISTS sync_state (
 		key TEXT PRIMARY KEY,
 		value TEXT
 	);
 	`
 
-	_, err := s.db.Exec(schema)
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion))
 	return err
 }
 
 // Sync rebuilds the SQLite index from the JSONL file.
 func (s *SQLiteStore) Sync() error {
+	defer profile.Start(profile.SQLiteSync)()
 	beats, err := s.jsonl.ReadAll()
 	if err != nil {
 		return fmt.Errorf("failed to read jsonl: %w", err)
@@ -120,54 +168,324 @@ func (s *SQLiteStore) Sync() error {
 	if _, err := tx.Exec("DELETE FROM beats"); err != nil {
 		return err
 	}
+	if _, err := tx.Exec("DELETE FROM attachments_fts"); err != nil {
+		return err
+	}
 
 	// Insert all beats
-	stmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO beats 
-		(id, created_at, updated_at, content, impetus_label, impetus_raw, impetus_meta, references_json, entities_json, linked_beads_json)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
+	stmt, err := tx.Prepare(upsertBeatSQL)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
 	for _, b := range beats {
-		metaJSON, _ := json.Marshal(b.Impetus.Meta)
-		refsJSON, _ := json.Marshal(b.References)
-		entitiesJSON, _ := json.Marshal(b.Entities)
-		linkedJSON, _ := json.Marshal(b.LinkedBeads)
-
-		_, err := stmt.Exec(
-			b.ID,
-			b.CreatedAt.Format(time.RFC3339),
-			b.UpdatedAt.Format(time.RFC3339),
-			b.Content,
-			b.Impetus.Label,
-			b.Impetus.Raw,
-			string(metaJSON),
-			string(refsJSON),
-			string(entitiesJSON),
-			string(linkedJSON),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert beat %s: %w", b.ID, err)
+		if err := upsertBeat(stmt, b); err != nil {
+			return err
+		}
+		if err := s.indexAttachments(tx, b); err != nil {
+			return err
 		}
 	}
 
 	// Update sync timestamp
 	if _, err := tx.Exec(`INSERT OR REPLACE INTO sync_state (key, value) VALUES ('last_sync', ?)`,
-		time.Now().UTC().Format(time.RFC3339)); err != nil {
+		time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
 		return err
 	}
 
 	return tx.Commit()
 }
 
+// upsertBeatSQL updates a beat already indexed in place, so the beats_au
+// trigger replaces its full-text entry. INSERT OR REPLACE would delete the
+// old row without firing beats_ad and leave its terms in beats_fts.
+const upsertBeatSQL = `
+		INSERT INTO beats
+		(id, created_at, updated_at, content, impetus_label, impetus_raw, impetus_meta, references_json, entities_json, entities_text, linked_beads_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			content = excluded.content,
+			impetus_label = excluded.impetus_label,
+			impetus_raw = excluded.impetus_raw,
+			impetus_meta = excluded.impetus_meta,
+			references_json = excluded.references_json,
+			entities_json = excluded.entities_json,
+			entities_text = excluded.entities_text,
+			linked_beads_json = excluded.linked_beads_json
+	`
+
+func upsertBeat(stmt *sql.Stmt, b beat.Beat) error {
+	metaJSON, _ := json.Marshal(b.Impetus.Meta)
+	refsJSON, _ := json.Marshal(b.References)
+	entitiesJSON, _ := json.Marshal(b.Entities)
+	linkedJSON, _ := json.Marshal(b.LinkedBeads)
+	labels := make([]string, len(b.Entities))
+	for i, e := range b.Entities {
+		labels[i] = e.Label
+	}
+
+	_, err := stmt.Exec(
+		b.ID,
+		b.CreatedAt.UTC().Format(indexTime),
+		b.UpdatedAt.UTC().Format(indexTime),
+		b.Content,
+		b.Impetus.Label,
+		b.Impetus.Raw,
+		string(metaJSON),
+		string(refsJSON),
+		string(entitiesJSON),
+		strings.Join(labels, " "),
+		string(linkedJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert beat %s: %w", b.ID, err)
+	}
+	return nil
+}
+
+// SyncIncremental brings the index up to date by upserting only beats whose
+// updated_at changed and deleting beats no longer in the JSONL file.
+func (s *SQLiteStore) SyncIncremental() (upserted, deleted int, err error) {
+	beats, err := s.jsonl.ReadAll()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read jsonl: %w", err)
+	}
+
+	indexed := make(map[string]string)
+	rows, err := s.db.Query("SELECT id, updated_at FROM beats")
+	if err != nil {
+		return 0, 0, err
+	}
+	for rows.Next() {
+		var id, updatedAt string
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		indexed[id] = updatedAt
+	}
+	rows.Close()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(upsertBeatSQL)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer stmt.Close()
+
+	for _, b := range beats {
+		updatedAt, ok := indexed[b.ID]
+		delete(indexed, b.ID)
+		if ok && updatedAt == b.UpdatedAt.UTC().Format(indexTime) {
+			continue
+		}
+		if err := upsertBeat(stmt, b); err != nil {
+			return 0, 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM attachments_fts WHERE beat_id = ?", b.ID); err != nil {
+			return 0, 0, err
+		}
+		if err := s.indexAttachments(tx, b); err != nil {
+			return 0, 0, err
+		}
+		upserted++
+	}
+
+	// Whatever is left in the index was removed from the JSONL file
+	for id := range indexed {
+		if _, err := tx.Exec("DELETE FROM beats WHERE id = ?", id); err != nil {
+			return 0, 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM attachments_fts WHERE beat_id = ?", id); err != nil {
+			return 0, 0, err
+		}
+		deleted++
+	}
+
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO sync_state (key, value) VALUES ('last_sync', ?)`,
+		time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		return 0, 0, err
+	}
+
+	return upserted, deleted, tx.Commit()
+}
+
+// indexAttachments adds the extracted text a beat has attached (page text,
+// PDF text, transcripts, markdown snapshots) to attachments_fts. Binary
+// attachments and raw HTML are left out, and so are files that are gone.
+func (s *SQLiteStore) indexAttachments(tx *sql.Tx, b beat.Beat) error {
+	for _, r := range b.References {
+		if r.Kind != "attachment" || !IsTextAttachment(r.Subtype) {
+			continue
+		}
+		data, err := s.jsonl.ReadAttachment(r.Locator)
+		if err != nil {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO attachments_fts (beat_id, locator, label, text) VALUES (?, ?, ?, ?)`,
+			b.ID, r.Locator, r.Label, string(data)); err != nil {
+			return fmt.Errorf("failed to index attachment %s: %w", r.Locator, err)
+		}
+	}
+	return nil
+}
+
+// IsTextAttachment reports whether an attachment's subtype is text worth
+// searching. HTML snapshots are skipped; their markdown twin is indexed.
+func IsTextAttachment(subtype string) bool {
+	return strings.HasPrefix(subtype, "text/") && subtype != "text/html"
+}
+
+// AttachmentMatch is an attachment whose text matched a search.
+type AttachmentMatch struct {
+	BeatID  string  `json:"beat_id"`
+	Locator string  `json:"locator"`
+	Label   string  `json:"label,omitempty"`
+	Offset  int     `json:"offset"`  // Byte offset of the first match in the attachment
+	Snippet string  `json:"snippet"` // The text around it
+	Score   float64 `json:"score"`
+}
+
+// attachmentSnippetBytes is how much text a match shows on each side.
+const attachmentSnippetBytes = 80
+
+// SearchAttachments runs a full-text search over attachment text, best
+// first. Every word of the query must appear in a matching attachment.
+func (s *SQLiteStore) SearchAttachments(query string, maxResults int) ([]AttachmentMatch, error) {
+	if err := s.SyncIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	terms := strings.Fields(query)
+	match := EscapeFTS(query)
+	if s.RawQueries {
+		terms, match = ftsTerms(query), query
+	}
+	if len(terms) == 0 {
+		return []AttachmentMatch{}, nil
+	}
+	patterns := make([]string, len(terms))
+	for i, t := range terms {
+		patterns[i] = regexp.QuoteMeta(t)
+	}
+	if maxResults <= 0 {
+		maxResults = -1 // No limit
+	}
+	rows, err := s.db.Query(`
+		SELECT beat_id, locator, label, text, bm25(attachments_fts) AS score
+		FROM attachments_fts
+		WHERE attachments_fts MATCH ?
+		ORDER BY score
+		LIMIT ?
+	`, match, maxResults)
+	if err != nil {
+		if s.RawQueries {
+			return nil, fmt.Errorf("invalid FTS5 query: %w", err)
+		}
+		return nil, fmt.Errorf("attachment search failed: %w", err)
+	}
+	defer rows.Close()
+
+	first := regexp.MustCompile(`(?i)` + strings.Join(patterns, "|"))
+	matches := []AttachmentMatch{}
+	for rows.Next() {
+		var m AttachmentMatch
+		var text string
+		if err := rows.Scan(&m.BeatID, &m.Locator, &m.Label, &text, &m.Score); err != nil {
+			return nil, err
+		}
+		m.Score = -m.Score // bm25 returns negative scores, lower is better
+		if loc := first.FindStringIndex(text); loc != nil {
+			m.Offset = loc[0]
+		}
+		m.Snippet = snippetAt(text, m.Offset)
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// EscapeFTS turns free text into an FTS5 query matching every word in it:
+// each word becomes a quoted string, so FTS5 operators and punctuation such
+// as ':', '"', '-' and '*' are searched for rather than parsed.
+func EscapeFTS(query string) string {
+	words := strings.Fields(query)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// ftsColumnFilter matches the column name in "text: word" or "{a b}: word".
+var ftsColumnFilter = regexp.MustCompile(`(?:\w+|\{[^}]*\})\s*:`)
+
+// ftsTerms returns the words of a raw FTS5 query, without operators,
+// column filters and syntax, for locating matches in text.
+func ftsTerms(query string) []string {
+	var terms []string
+	query = ftsColumnFilter.ReplaceAllString(query, " ")
+	for _, w := range strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		switch w {
+		case "AND", "OR", "NOT", "NEAR":
+			continue
+		}
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// snippetAt returns the text around offset on one line, cut at word and
+// rune boundaries.
+func snippetAt(text string, offset int) string {
+	start, end := max(offset-attachmentSnippetBytes, 0), min(offset+attachmentSnippetBytes, len(text))
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	snippet := text[start:end]
+	if start > 0 {
+		if i := strings.IndexAny(snippet, " \n\t"); i >= 0 && i < offset-start {
+			snippet = snippet[i+1:]
+		}
+		snippet = "..." + snippet
+	}
+	if end < len(text) {
+		if i := strings.LastIndexAny(snippet, " \n\t"); i > len(snippet)-(end-offset) {
+			snippet = snippet[:i]
+		}
+		snippet += "..."
+	}
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// LastSync returns when the index was last brought up to date, or the zero
+// time if it never was.
+func (s *SQLiteStore) LastSync() (time.Time, error) {
+	var lastSync string
+	err := s.db.QueryRow("SELECT value FROM sync_state WHERE key = 'last_sync'").Scan(&lastSync)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, lastSync)
+}
+
 // SyncIfNeeded checks if the JSONL file has been modified and syncs if necessary.
 func (s *SQLiteStore) SyncIfNeeded() error {
-	jsonlPath := s.jsonl.Path()
-	info, err := os.Stat(jsonlPath)
+	info, err := s.jsonl.Stat()
 	if os.IsNotExist(err) {
 		return nil // No JSONL file yet
 	}
@@ -202,13 +520,15 @@ func (s *SQLiteStore) Search(query string, maxResults int) ([]beat.SearchResult,
 		return nil, err
 	}
 
-	// Escape special FTS5 characters and prepare query
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return []beat.SearchResult{}, nil
 	}
+	match := query
+	if !s.RawQueries {
+		match = EscapeFTS(query) + "*" // The last word may be a prefix
+	}
 
-	// Use simple contains match for now
 	rows, err := s.db.Query(`
 		SELECT b.id, b.content, b.impetus_label, b.impetus_raw, b.impetus_meta,
 			   bm25(beats_fts) as score
@@ -217,8 +537,11 @@ func (s *SQLiteStore) Search(query string, maxResults int) ([]beat.SearchResult,
 		WHERE beats_fts MATCH ?
 		ORDER BY score
 		LIMIT ?
-	`, query+"*", maxResults)
+	`, match, maxResults)
 	if err != nil {
+		if s.RawQueries {
+			return nil, fmt.Errorf("invalid FTS5 query: %w", err)
+		}
 		// Fallback to simple LIKE if FTS fails
 		return s.searchLike(query, maxResults)
 	}
@@ -340,7 +663,7 @@ func (s *SQLiteStore) GetSince(since time.Time) (new, modified, linked []beat.Be
 		return nil, nil, nil, err
 	}
 
-	sinceStr := since.Format(time.RFC3339)
+	sinceStr := since.UTC().Format(indexTime)
 
 	// New beats
 	newRows, err := s.db.Query(`
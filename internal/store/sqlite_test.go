@@ -0,0 +1,117 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestSearchEscaping(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []beat.Beat{
+		{ID: "beat-20260101-001", Content: "Follow up: the re-launch plan"},
+		{ID: "beat-20260101-002", Content: `He said "ship it" about pricing`},
+		{ID: "beat-20260101-003", Content: "pricing near the launch date"},
+	} {
+		if err := s.Append(&b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sqlite, err := NewSQLiteStore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	for query, want := range map[string]string{
+		"up: the":        "beat-20260101-001",
+		"re-launch":      "beat-20260101-001",
+		`"ship it`:       "beat-20260101-002",
+		"pricing launch": "beat-20260101-003", // Not adjacent, so FTS rather than the LIKE fallback
+		"pricing NEAR(":  "",                  // Operators are plain words
+	} {
+		results, err := sqlite.Search(query, 10)
+		if err != nil {
+			t.Errorf("Search(%q) = %v", query, err)
+			continue
+		}
+		if want != "" && (len(results) == 0 || results[0].ID != want) {
+			t.Errorf("Search(%q) = %+v, want %s first", query, results, want)
+		}
+	}
+
+	sqlite.RawQueries = true
+	results, err := sqlite.Search("pricing NEAR launch", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "beat-20260101-003" {
+		t.Errorf("raw Search = %+v, want beat-20260101-003", results)
+	}
+	if _, err := sqlite.Search(`"unbalanced`, 10); err == nil {
+		t.Error("raw Search with bad syntax: want an error")
+	}
+}
+
+func TestEscapeFTS(t *testing.T) {
+	if got := EscapeFTS(`a:b  say "hi"`); got != `"a:b" "say" """hi"""` {
+		t.Errorf("EscapeFTS = %s", got)
+	}
+	if got := ftsTerms(`content: pricing OR "cost cutting" NEAR(a b)`); len(got) != 5 || got[0] != "pricing" {
+		t.Errorf("ftsTerms = %q", got)
+	}
+}
+
+func TestSyncIncrementalEdit(t *testing.T) {
+	s, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 1, 10, 0, 0, 100_000_000, time.UTC)
+	for _, b := range []beat.Beat{
+		{ID: "beat-20260101-001", Content: "alpha content", CreatedAt: at, UpdatedAt: at},
+		{ID: "beat-20260101-002", Content: "unrelated note", CreatedAt: at, UpdatedAt: at},
+	} {
+		if err := s.Append(&b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sqlite, err := NewSQLiteStore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+	if err := sqlite.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Edited within the same second it was indexed
+	if _, err := s.Update("beat-20260101-001", func(b *beat.Beat) error {
+		b.Content = "bravo content"
+		b.UpdatedAt = at.Add(300 * time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	upserted, _, err := sqlite.SyncIncremental()
+	if err != nil || upserted != 1 {
+		t.Fatalf("SyncIncremental = %d, %v; want the edited beat upserted", upserted, err)
+	}
+
+	for term, want := range map[string]int{"alpha": 0, "bravo": 1, "content": 1} {
+		var n int
+		if err := sqlite.db.QueryRow("SELECT count(*) FROM beats_fts WHERE beats_fts MATCH ?", term).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != want {
+			t.Errorf("MATCH %q = %d row(s), want %d", term, n, want)
+		}
+	}
+	if _, err := sqlite.db.Exec("INSERT INTO beats_fts(beats_fts) VALUES('integrity-check')"); err != nil {
+		t.Errorf("integrity-check: %v", err)
+	}
+}
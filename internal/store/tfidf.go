@@ -0,0 +1,211 @@
+package store
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// stopwords are dropped before weighting; they carry no topical signal.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "for": true, "from": true, "has": true, "have": true, "i": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "we": true, "were": true,
+	"will": true, "with": true, "you": true, "not": true, "they": true, "their": true,
+}
+
+// tokenize lowercases text, splits on non-alphanumerics, drops stopwords and
+// reduces words to a crude stem so "learning"/"learned"/"learns" match.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if len(f) < 2 || stopwords[f] {
+			continue
+		}
+		tokens = append(tokens, stem(f))
+	}
+	return tokens
+}
+
+func stem(word string) string {
+	for _, suffix := range []string{"ational", "ations", "ation", "ness", "ments", "ment", "ings", "ing", "ies", "ied", "ers", "er", "ed", "ly", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			word = strings.TrimSuffix(word, suffix)
+			if suffix == "ies" || suffix == "ied" {
+				word += "y"
+			}
+			return word
+		}
+	}
+	return word
+}
+
+// TFIDFIndex is a local vector model built from the store itself. It is the
+// offline fallback when no embedding provider is available.
+type TFIDFIndex struct {
+	beats   []beat.Beat
+	vectors []map[string]float64
+	idf     map[string]float64
+}
+
+// NewTFIDFIndex builds sublinear TF-IDF vectors for beats.
+func NewTFIDFIndex(beats []beat.Beat) *TFIDFIndex {
+	idx := &TFIDFIndex{beats: beats, idf: make(map[string]float64)}
+
+	counts := make([]map[string]int, len(beats))
+	df := make(map[string]int)
+	for i, b := range beats {
+		tf := make(map[string]int)
+		for _, t := range tokenize(formatBeatText(b)) {
+			tf[t]++
+		}
+		for t := range tf {
+			df[t]++
+		}
+		counts[i] = tf
+	}
+
+	n := float64(len(beats))
+	for t, d := range df {
+		idx.idf[t] = math.Log(1+n/float64(d)) + 1
+	}
+
+	idx.vectors = make([]map[string]float64, len(beats))
+	for i, tf := range counts {
+		idx.vectors[i] = idx.weigh(tf)
+	}
+	return idx
+}
+
+// weigh turns term counts into a unit-length TF-IDF vector.
+func (idx *TFIDFIndex) weigh(tf map[string]int) map[string]float64 {
+	vec := make(map[string]float64, len(tf))
+	var norm float64
+	for t, c := range tf {
+		idf, ok := idx.idf[t]
+		if !ok {
+			continue // Unknown to the corpus: cannot match anything
+		}
+		w := (1 + math.Log(float64(c))) * idf
+		vec[t] = w
+		norm += w * w
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for t := range vec {
+			vec[t] /= norm
+		}
+	}
+	return vec
+}
+
+// Search ranks beats by cosine similarity to the query.
+func (idx *TFIDFIndex) Search(query string, maxResults int) []beat.SearchResult {
+	tf := make(map[string]int)
+	for _, t := range tokenize(query) {
+		tf[t]++
+	}
+	q := idx.weigh(tf)
+	if len(q) == 0 {
+		return []beat.SearchResult{}
+	}
+
+	results := []beat.SearchResult{}
+	for i, vec := range idx.vectors {
+		var score float64
+		for t, w := range q {
+			score += w * vec[t]
+		}
+		if score > 0 {
+			b := idx.beats[i]
+			results = append(results, beat.SearchResult{
+				ID:      b.ID,
+				Score:   score,
+				Content: b.Content,
+				Impetus: b.Impetus,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results
+}
+
+// TFIDFSearch builds a TF-IDF index over the store and searches it.
+func (s *JSONLStore) TFIDFSearch(query string, maxResults int) ([]beat.SearchResult, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return NewTFIDFIndex(beats).Search(query, maxResults), nil
+}
+
+// Keywords returns the n terms with the highest summed TF-IDF weight across
+// the given beats, each as its most frequent surface word rather than a stem.
+func (idx *TFIDFIndex) Keywords(ids []string, n int) []string {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	weights := make(map[string]float64)
+	surface := make(map[string]map[string]int)
+	for i, b := range idx.beats {
+		if !want[b.ID] {
+			continue
+		}
+		for t, w := range idx.vectors[i] {
+			weights[t] += w
+		}
+		for _, word := range strings.FieldsFunc(strings.ToLower(formatBeatText(b)), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if len(word) < 2 || stopwords[word] {
+				continue
+			}
+			s := stem(word)
+			if surface[s] == nil {
+				surface[s] = make(map[string]int)
+			}
+			surface[s][word]++
+		}
+	}
+
+	terms := make([]string, 0, len(weights))
+	for t := range weights {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if weights[terms[i]] != weights[terms[j]] {
+			return weights[terms[i]] > weights[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+
+	keywords := make([]string, len(terms))
+	for i, t := range terms {
+		best, count := t, 0
+		for word, c := range surface[t] {
+			if c > count || (c == count && word < best) {
+				best, count = word, c
+			}
+		}
+		keywords[i] = best
+	}
+	return keywords
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestTFIDFIndex_MatchesWordVariants(t *testing.T) {
+	beats := []beat.Beat{
+		*beat.NewBeat("Users keep abandoning the checkout when shipping costs appear", beat.Impetus{Label: "Research"}),
+		*beat.NewBeat("Morning walk reflections on coaching sessions", beat.Impetus{Label: "Journal"}),
+		*beat.NewBeat("Shipping estimate should be shown on the product page", beat.Impetus{Label: "Idea"}),
+	}
+	beats[0].ID, beats[1].ID, beats[2].ID = "b1", "b2", "b3"
+
+	results := NewTFIDFIndex(beats).Search("user abandons checkout", 10)
+	if len(results) == 0 {
+		t.Fatal("Search() returned no results")
+	}
+	if results[0].ID != "b1" {
+		t.Errorf("top result = %s, want b1", results[0].ID)
+	}
+	for _, r := range results {
+		if r.ID == "b2" {
+			t.Errorf("unrelated beat b2 matched with score %.3f", r.Score)
+		}
+	}
+}
+
+func TestTFIDFIndex_RareTermsWeighMore(t *testing.T) {
+	beats := []beat.Beat{
+		*beat.NewBeat("shipping shipping notes", beat.Impetus{}),
+		*beat.NewBeat("shipping notes about onboarding", beat.Impetus{}),
+		*beat.NewBeat("shipping notes", beat.Impetus{}),
+	}
+	beats[0].ID, beats[1].ID, beats[2].ID = "b1", "b2", "b3"
+
+	results := NewTFIDFIndex(beats).Search("shipping onboarding", 1)
+	if len(results) != 1 || results[0].ID != "b2" {
+		t.Errorf("Search() = %v, want b2 first", results)
+	}
+}
+
+func TestTFIDFIndex_EmptyQuery(t *testing.T) {
+	idx := NewTFIDFIndex([]beat.Beat{*beat.NewBeat("content", beat.Impetus{})})
+	if got := idx.Search("the and of", 10); len(got) != 0 {
+		t.Errorf("Search(stopwords) returned %d results, want 0", len(got))
+	}
+}
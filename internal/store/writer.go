@@ -0,0 +1,288 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// WriteSocket is the unix socket, inside the .beats directory, on which a
+// daemon started with --socket accepts writes for the store. While it is
+// up, every JSONLStore for that directory forwards its writes there, so one
+// process appends to beats.jsonl and assigns sequence numbers.
+const WriteSocket = "daemon.sock"
+
+// NoForwardEnvVar, when set, makes writes go straight to beats.jsonl even
+// while a daemon is serving the store.
+const NoForwardEnvVar = "BEATS_NO_DAEMON"
+
+// writeTimeout bounds a forwarded write.
+const writeTimeout = 10 * time.Second
+
+// writeRequest is a write forwarded to the daemon.
+type writeRequest struct {
+	Op    string       `json:"op"` // append, put or delete
+	Beats []*beat.Beat `json:"beats,omitempty"`
+	ID    string       `json:"id,omitempty"`    // Beat to put or delete
+	Hooks bool         `json:"hooks,omitempty"` // Run beat hooks after appending
+	Base  time.Time    `json:"base,omitzero"`   // Put only over this updated_at
+}
+
+// writeResponse carries the IDs the daemon stored the beats under, which
+// differ from the requested ones when another writer took the sequence.
+type writeResponse struct {
+	IDs      []string   `json:"ids,omitempty"`
+	Beat     *beat.Beat `json:"beat,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	Conflict *time.Time `json:"conflict,omitempty"` // The stored updated_at, when a put lost the race
+}
+
+// SocketPath returns the write socket of the store.
+func (s *JSONLStore) SocketPath() string {
+	return filepath.Join(s.dir, WriteSocket)
+}
+
+// WriteHandler serves writes forwarded by other processes. The store it is
+// called on writes directly and never forwards.
+func (s *JSONLStore) WriteHandler() http.Handler {
+	s.serving = true
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST only", http.StatusMethodNotAllowed)
+			return
+		}
+		var req writeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeReply(w, http.StatusBadRequest, writeResponse{Error: err.Error()})
+			return
+		}
+		resp, err := s.applyWrite(req)
+		var conflict *ConflictError
+		if errors.As(err, &conflict) {
+			writeReply(w, http.StatusConflict, writeResponse{Error: err.Error(), Conflict: &conflict.Current})
+			return
+		}
+		if err != nil {
+			writeReply(w, http.StatusUnprocessableEntity, writeResponse{Error: err.Error()})
+			return
+		}
+		writeReply(w, http.StatusOK, resp)
+	})
+}
+
+func writeReply(w http.ResponseWriter, status int, resp writeResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// applyWrite performs a forwarded write on this process's store.
+func (s *JSONLStore) applyWrite(req writeRequest) (writeResponse, error) {
+	switch req.Op {
+	case "append":
+		if len(req.Beats) == 0 {
+			return writeResponse{}, nil
+		}
+		if err := s.appendRenumbered(req.Beats, req.Hooks); err != nil {
+			return writeResponse{}, err
+		}
+		ids := make([]string, len(req.Beats))
+		for i, b := range req.Beats {
+			ids[i] = b.ID
+		}
+		return writeResponse{IDs: ids}, nil
+	case "put":
+		if len(req.Beats) != 1 {
+			return writeResponse{}, fmt.Errorf("put takes one beat")
+		}
+		replacement := req.Beats[0]
+		updated, err := s.Update(req.ID, func(b *beat.Beat) error {
+			if !req.Base.IsZero() && !b.UpdatedAt.Equal(req.Base) {
+				return &ConflictError{ID: req.ID, Expected: req.Base, Current: b.UpdatedAt}
+			}
+			*b = *replacement
+			return nil
+		})
+		if err != nil {
+			return writeResponse{}, err
+		}
+		return writeResponse{Beat: updated}, nil
+	case "delete":
+		return writeResponse{}, s.Delete(req.ID)
+	default:
+		return writeResponse{}, fmt.Errorf("unknown write op: %q", req.Op)
+	}
+}
+
+// appendRenumbered appends beats, moving any whose ID is already taken to
+// the next free sequence of its day. The beats' IDs are updated in place.
+func (s *JSONLStore) appendRenumbered(beats []*beat.Beat, runHooks bool) error {
+	unlock := s.lock()
+	existing, err := s.readAllUnlocked()
+	if err != nil {
+		unlock()
+		return err
+	}
+	taken := make(map[string]bool, len(existing)+len(beats))
+	nextSeq := make(map[string]int) // YYYYMMDD -> next free sequence
+	note := func(id string) {
+		taken[id] = true
+		parts := strings.Split(id, "-")
+		if len(parts) == 3 {
+			if seq, err := strconv.Atoi(parts[2]); err == nil && seq >= nextSeq[parts[1]] {
+				nextSeq[parts[1]] = seq + 1
+			}
+		}
+	}
+	for _, b := range existing {
+		note(b.ID)
+	}
+	for _, b := range beats {
+		if taken[b.ID] {
+			day := beat.IDDate(b.CreatedAt)
+			if nextSeq[day] == 0 {
+				nextSeq[day] = 1
+			}
+			b.ID = fmt.Sprintf("beat-%s-%03d", day, nextSeq[day])
+		}
+		note(b.ID)
+	}
+	err = s.appendUnlocked(beats)
+	all := existing
+	if err == nil && runHooks {
+		for _, b := range beats {
+			all = append(all, *b)
+		}
+	}
+	unlock()
+	if err != nil || !runHooks {
+		return err
+	}
+	for _, b := range beats {
+		s.triggerHooks(b, all)
+	}
+	return nil
+}
+
+// forwarding reports whether writes go to a daemon serving this store.
+func (s *JSONLStore) forwarding() bool {
+	if s.serving || os.Getenv(NoForwardEnvVar) != "" {
+		return false
+	}
+	return IsSocket(s.SocketPath())
+}
+
+// IsSocket reports whether path is a unix socket. Windows does not always
+// report AF_UNIX sockets as such, so there any non-directory counts.
+func IsSocket(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return !info.IsDir()
+	}
+	return info.Mode()&os.ModeSocket != 0
+}
+
+// forward sends a write to the daemon. ok is false when no daemon answered,
+// in which case the caller writes directly.
+func (s *JSONLStore) forward(req writeRequest) (resp writeResponse, ok bool, err error) {
+	if !s.forwarding() {
+		return resp, false, nil
+	}
+	socket := s.SocketPath()
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return resp, false, nil // Stale socket from a daemon that died
+	}
+	conn.Close()
+
+	client := &http.Client{
+		Timeout: writeTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return resp, true, err
+	}
+	httpResp, err := client.Post("http://beats/write", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return resp, true, fmt.Errorf("daemon write failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return resp, true, fmt.Errorf("daemon write failed: %w", err)
+	}
+	if resp.Conflict != nil {
+		return resp, true, &ConflictError{ID: req.ID, Expected: req.Base, Current: *resp.Conflict}
+	}
+	if resp.Error != "" {
+		return resp, true, fmt.Errorf("%s", resp.Error)
+	}
+	return resp, true, nil
+}
+
+// forwardAppend appends through the daemon and adopts the IDs it assigned.
+func (s *JSONLStore) forwardAppend(beats []*beat.Beat, runHooks bool) (bool, error) {
+	resp, ok, err := s.forward(writeRequest{Op: "append", Beats: beats, Hooks: runHooks})
+	if !ok || err != nil {
+		return ok, err
+	}
+	for i, id := range resp.IDs {
+		if i < len(beats) {
+			beats[i].ID = id
+		}
+	}
+	return true, nil
+}
+
+// forwardUpdateAttempts bounds how often forwardUpdate re-reads a beat
+// another writer changed between its read and its put.
+const forwardUpdateAttempts = 3
+
+// forwardUpdate applies updater to the current beat and sends the result to
+// the daemon, which replaces the stored beat unless it changed since it was
+// read; then the update is made again on the new version.
+func (s *JSONLStore) forwardUpdate(id string, updater func(*beat.Beat) error) (*beat.Beat, error) {
+	for attempt := 1; ; attempt++ {
+		b, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		base := b.UpdatedAt
+		if err := updater(b); err != nil {
+			return nil, fmt.Errorf("updater failed: %w", err)
+		}
+		resp, ok, err := s.forward(writeRequest{Op: "put", ID: id, Beats: []*beat.Beat{b}, Base: base})
+		if !ok {
+			// The daemon went away; update directly
+			return s.updateLocal(id, updater)
+		}
+		var conflict *ConflictError
+		if errors.As(err, &conflict) && attempt < forwardUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return resp.Beat, nil
+	}
+}
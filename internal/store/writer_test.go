@@ -0,0 +1,79 @@
+package store
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+func TestForwardedWrites(t *testing.T) {
+	dir := t.TempDir()
+	owner, err := NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("unix", owner.SocketPath())
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := &http.Server{Handler: owner.WriteHandler()}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	client, err := NewJSONLStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !client.forwarding() {
+		t.Fatal("client does not forward while the socket is up")
+	}
+
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	first := &beat.Beat{ID: beat.GenerateIDWithSequence(at, 1), CreatedAt: at, Content: "first"}
+	second := &beat.Beat{ID: beat.GenerateIDWithSequence(at, 1), CreatedAt: at, Content: "second"}
+	if err := client.Append(first); err != nil {
+		t.Fatalf("Append(first) error = %v", err)
+	}
+	// A second writer that computed the same sequence gets the next one
+	if err := client.Append(second); err != nil {
+		t.Fatalf("Append(second) error = %v", err)
+	}
+	if second.ID != "beat-20250301-002" {
+		t.Errorf("second.ID = %s, want beat-20250301-002", second.ID)
+	}
+
+	updated, err := client.Update(first.ID, func(b *beat.Beat) error {
+		b.Content = "first, edited"
+		return nil
+	})
+	if err != nil || updated.Content != "first, edited" {
+		t.Fatalf("Update() = %+v, %v", updated, err)
+	}
+	// The daemon refuses a put over a version newer than the one read
+	_, err = client.UpdateIfUnchanged(first.ID, first.UpdatedAt, func(b *beat.Beat) error {
+		b.Content = "stale edit"
+		return nil
+	})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("UpdateIfUnchanged() with a stale version error = %v, want a ConflictError", err)
+	}
+	if err := client.Delete(second.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	beats, err := owner.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beats) != 1 || beats[0].Content != "first, edited" {
+		t.Errorf("store = %+v, want the edited first beat only", beats)
+	}
+	if _, err := client.Update("beat-missing", func(*beat.Beat) error { return nil }); err == nil {
+		t.Error("Update(missing) succeeded")
+	}
+}
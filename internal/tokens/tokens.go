@@ -0,0 +1,80 @@
+// Package tokens estimates how many LLM tokens a prompt takes and fits lists
+// of beats into a token budget.
+package tokens
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// minTrimmed is the fewest tokens worth keeping of a line cut to fit.
+const minTrimmed = 40
+
+// Estimate approximates the number of tokens in s at about four bytes per
+// token, close enough for English text across the usual tokenizers.
+func Estimate(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// Selection is the outcome of fitting lines into a budget.
+type Selection struct {
+	Lines   []string // Kept lines in their original order, some cut short
+	Kept    []int    // Indexes of the kept lines, ascending
+	Omitted []int    // Indexes of the lines left out, ascending
+	Trimmed int      // Kept lines that were cut short
+	Tokens  int      // Estimated tokens of the kept lines, newlines included
+}
+
+// Fit keeps lines, most important first, while they fit in budget tokens.
+// priority lists line indexes in order of importance; nil means the lines'
+// own order. The first line that does not fit is cut short if enough budget
+// is left for it to be useful, and every line after it is left out. A budget
+// of zero or less keeps everything.
+func Fit(lines []string, priority []int, budget int) Selection {
+	if priority == nil {
+		priority = make([]int, len(lines))
+		for i := range lines {
+			priority[i] = i
+		}
+	}
+	kept := make(map[int]string, len(lines))
+	var sel Selection
+	full := false
+	for _, i := range priority {
+		if full {
+			continue
+		}
+		cost := Estimate(lines[i]) + 1
+		if budget <= 0 || sel.Tokens+cost <= budget {
+			kept[i] = lines[i]
+			sel.Tokens += cost
+			continue
+		}
+		full = true
+		if left := budget - sel.Tokens - 1; left >= minTrimmed {
+			cut := trim(lines[i], left)
+			kept[i] = cut
+			sel.Tokens += Estimate(cut) + 1
+			sel.Trimmed++
+		}
+	}
+	for i := range lines {
+		if line, ok := kept[i]; ok {
+			sel.Lines = append(sel.Lines, line)
+			sel.Kept = append(sel.Kept, i)
+		} else {
+			sel.Omitted = append(sel.Omitted, i)
+		}
+	}
+	return sel
+}
+
+// trim cuts s to at most budget tokens on a character boundary, marking the
+// cut with "...".
+func trim(s string, budget int) string {
+	n := max(budget*4-3, 0)
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimRight(s[:min(n, len(s))], " ") + "..."
+}
@@ -0,0 +1,38 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrim(t *testing.T) {
+	got := trim("héllo wörld and more", 3)
+	if got != "héllo w..." || Estimate(got) > 3 || !utf8.ValidString(got) {
+		t.Errorf("trim() = %q", got)
+	}
+}
+
+func TestFit(t *testing.T) {
+	line := strings.Repeat("x", 196) // 49 tokens, 50 with its newline
+	lines := []string{line + "0", line + "1", line + "2", line + "3"}
+
+	all := Fit(lines, nil, 0)
+	if len(all.Lines) != 4 || len(all.Omitted) != 0 || all.Tokens != 4*51 {
+		t.Errorf("Fit(no budget) = %+v", all)
+	}
+
+	// Newest first: the last two lines fit, the next is cut, the first left out
+	sel := Fit(lines, []int{3, 2, 1, 0}, 150)
+	if len(sel.Kept) != 3 || sel.Kept[0] != 1 || sel.Trimmed != 1 || len(sel.Omitted) != 1 || sel.Omitted[0] != 0 {
+		t.Fatalf("Fit() = %+v; want lines 1-3 kept, 1 trimmed", sel)
+	}
+	if sel.Tokens > 150 || !strings.HasSuffix(sel.Lines[0], "...") || sel.Lines[2] != lines[3] {
+		t.Errorf("Fit() used %d tokens, lines %q", sel.Tokens, sel.Lines)
+	}
+
+	// Too little left to be worth trimming
+	if sel := Fit(lines, nil, 80); len(sel.Kept) != 1 || sel.Trimmed != 0 {
+		t.Errorf("Fit(80) = %+v; want one whole line", sel)
+	}
+}
@@ -0,0 +1,268 @@
+// Package topics clusters beat embeddings into themes and tracks how each
+// theme's activity changes between consecutive time windows.
+package topics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/clock"
+	"github.com/bierlingm/beats/internal/embeddings"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Trends a topic can show between the previous and current window.
+const (
+	Emerging = "emerging"
+	Steady   = "steady"
+	Fading   = "fading"
+)
+
+const (
+	DefaultWindow    = 30 * 24 * time.Hour
+	DefaultMinBeats  = 2
+	keywordsPerTopic = 3
+	representatives  = 3
+)
+
+// Options controls topic analysis.
+type Options struct {
+	Window    time.Duration // Length of the current (and previous) window
+	Threshold float64       // Minimum cosine similarity to join a topic
+	MinBeats  int           // Smallest topic reported
+	Now       time.Time     // End of the current window (default: now)
+}
+
+// Representative is a beat close to the centre of its topic.
+type Representative struct {
+	ID         string  `json:"id"`
+	Preview    string  `json:"preview"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Topic is one cluster of related beats.
+type Topic struct {
+	Label           string           `json:"label"`
+	Keywords        []string         `json:"keywords"`
+	Trend           string           `json:"trend"`
+	Current         int              `json:"current"`  // Beats in the current window
+	Previous        int              `json:"previous"` // Beats in the previous window
+	Representatives []Representative `json:"representatives"`
+}
+
+// Report is the result of a topic analysis.
+type Report struct {
+	Window        string    `json:"window"`
+	WindowStart   time.Time `json:"window_start"`
+	PreviousStart time.Time `json:"previous_start"`
+	Threshold     float64   `json:"threshold"`
+	Topics        []Topic   `json:"topics"`
+	Clustered     int       `json:"clustered"`  // Beats with embeddings in either window
+	Unembedded    int       `json:"unembedded"` // Beats in either window without embeddings
+}
+
+type cluster struct {
+	centroid []float64
+	members  []int
+}
+
+// Analyze clusters beats from the current and previous windows and
+// classifies each topic as emerging, steady or fading.
+func Analyze(beats []beat.Beat, embStore *embeddings.Store, opts Options) (*Report, error) {
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.MinBeats <= 0 {
+		opts.MinBeats = DefaultMinBeats
+	}
+	if opts.Now.IsZero() {
+		opts.Now = clock.Now().UTC()
+	}
+	windowStart := opts.Now.Add(-opts.Window)
+	previousStart := windowStart.Add(-opts.Window)
+
+	report := &Report{
+		Window:        FormatWindow(opts.Window),
+		WindowStart:   windowStart,
+		PreviousStart: previousStart,
+		Threshold:     opts.Threshold,
+		Topics:        []Topic{},
+	}
+
+	var inRange []beat.Beat
+	var vectors [][]float64
+	for _, b := range beats {
+		if b.CreatedAt.Before(previousStart) || b.CreatedAt.After(opts.Now) {
+			continue
+		}
+		v, err := embStore.Get(b.ID)
+		if err != nil {
+			report.Unembedded++
+			continue
+		}
+		inRange = append(inRange, b)
+		vectors = append(vectors, v)
+	}
+	report.Clustered = len(inRange)
+	if len(inRange) == 0 {
+		return report, nil
+	}
+
+	// Oldest first, so a theme's centroid starts from where it began
+	order := make([]int, len(inRange))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return inRange[order[a]].CreatedAt.Before(inRange[order[b]].CreatedAt)
+	})
+
+	clusters := assign(order, vectors, opts.Threshold, nil)
+	// One refinement pass against the final centroids
+	clusters = assign(order, vectors, opts.Threshold, clusters)
+
+	// Label by content alone: impetus labels like "Manual entry" repeat across topics
+	contentOnly := make([]beat.Beat, len(inRange))
+	for i, b := range inRange {
+		contentOnly[i] = beat.Beat{ID: b.ID, Content: b.Content}
+	}
+	index := store.NewTFIDFIndex(contentOnly)
+	for _, c := range clusters {
+		if len(c.members) < opts.MinBeats {
+			continue
+		}
+		t := Topic{Representatives: []Representative{}}
+		ids := make([]string, 0, len(c.members))
+		for _, m := range c.members {
+			ids = append(ids, inRange[m].ID)
+			if inRange[m].CreatedAt.Before(windowStart) {
+				t.Previous++
+			} else {
+				t.Current++
+			}
+		}
+		t.Trend = Trend(t.Current, t.Previous)
+		t.Keywords = index.Keywords(ids, keywordsPerTopic)
+		t.Label = strings.Join(t.Keywords, ", ")
+
+		sort.SliceStable(c.members, func(a, b int) bool {
+			return embeddings.CosineSimilarity(vectors[c.members[a]], c.centroid) >
+				embeddings.CosineSimilarity(vectors[c.members[b]], c.centroid)
+		})
+		for _, m := range c.members {
+			if len(t.Representatives) == representatives {
+				break
+			}
+			t.Representatives = append(t.Representatives, Representative{
+				ID:         inRange[m].ID,
+				Preview:    preview(inRange[m].Content, 80),
+				Similarity: embeddings.CosineSimilarity(vectors[m], c.centroid),
+			})
+		}
+		report.Topics = append(report.Topics, t)
+	}
+
+	rank := map[string]int{Emerging: 0, Steady: 1, Fading: 2}
+	sort.SliceStable(report.Topics, func(i, j int) bool {
+		a, b := report.Topics[i], report.Topics[j]
+		if rank[a.Trend] != rank[b.Trend] {
+			return rank[a.Trend] < rank[b.Trend]
+		}
+		return a.Current+a.Previous > b.Current+b.Previous
+	})
+	return report, nil
+}
+
+// assign groups vectors by greedy leader clustering: each joins the most
+// similar cluster above threshold or starts a new one. Given seed clusters,
+// their centroids are used as fixed starting points instead.
+func assign(order []int, vectors [][]float64, threshold float64, seeds []*cluster) []*cluster {
+	var clusters []*cluster
+	for _, s := range seeds {
+		clusters = append(clusters, &cluster{centroid: s.centroid})
+	}
+	for _, i := range order {
+		best, bestScore := -1, threshold
+		for ci, c := range clusters {
+			if score := embeddings.CosineSimilarity(vectors[i], c.centroid); score >= bestScore {
+				best, bestScore = ci, score
+			}
+		}
+		if best < 0 {
+			clusters = append(clusters, &cluster{centroid: append([]float64(nil), vectors[i]...), members: []int{i}})
+			continue
+		}
+		c := clusters[best]
+		c.members = append(c.members, i)
+		if seeds == nil {
+			// Running mean keeps the centroid at the cluster's centre
+			n := float64(len(c.members))
+			for d := range c.centroid {
+				c.centroid[d] += (vectors[i][d] - c.centroid[d]) / n
+			}
+		}
+	}
+
+	kept := clusters[:0]
+	for _, c := range clusters {
+		if len(c.members) > 0 {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// Trend compares activity in the current window against the previous one.
+func Trend(current, previous int) string {
+	switch {
+	case current >= 2*previous && current > previous:
+		return Emerging
+	case 2*current <= previous:
+		return Fading
+	default:
+		return Steady
+	}
+}
+
+// FormatWindow renders a window as days when it is a whole number of days.
+func FormatWindow(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
+
+func preview(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
+
+// ForStore runs Analyze over a store's beats and embeddings, taking the
+// similarity threshold from scoring.json when opts.Threshold is unset.
+func ForStore(s *store.JSONLStore, opts Options) (*Report, error) {
+	beats, err := s.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read beats: %w", err)
+	}
+	embStore, err := embeddings.NewStore(s.Dir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding store: %w", err)
+	}
+	if embStore.Count() == 0 {
+		return nil, fmt.Errorf("no embeddings found (run 'bt embed' first)")
+	}
+	if opts.Threshold <= 0 {
+		scoring, err := store.LoadScoringConfig(s.Dir())
+		if err != nil {
+			return nil, err
+		}
+		opts.Threshold = scoring.TopicSimilarity
+	}
+	return Analyze(beats, embStore, opts)
+}
@@ -0,0 +1,78 @@
+package topics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/embeddings"
+)
+
+func TestAnalyze(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	store, err := embeddings.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var beats []beat.Beat
+	add := func(id, content string, age time.Duration, vec []float64) {
+		beats = append(beats, beat.Beat{ID: id, Content: content, CreatedAt: now.Add(-age)})
+		if err := store.Store(id, vec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := 24 * time.Hour
+	// Shipping: only in the current window
+	add("b1", "shipping costs surprise buyers", 2*day, []float64{1, 0, 0})
+	add("b2", "shipping estimate on product page", 3*day, []float64{0.95, 0.05, 0})
+	// Onboarding: only in the previous window
+	add("b3", "onboarding drops at step two", 10*day, []float64{0, 1, 0})
+	add("b4", "onboarding email confuses users", 11*day, []float64{0.05, 0.95, 0})
+	// Outside both windows
+	add("b5", "old note", 40*day, []float64{0, 0, 1})
+
+	report, err := Analyze(beats, store, Options{Window: 7 * day, Threshold: 0.8, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Clustered != 4 {
+		t.Errorf("Clustered = %d, want 4", report.Clustered)
+	}
+	if len(report.Topics) != 2 {
+		t.Fatalf("got %d topics, want 2: %+v", len(report.Topics), report.Topics)
+	}
+
+	emerging, fading := report.Topics[0], report.Topics[1]
+	if emerging.Trend != Emerging || emerging.Current != 2 || emerging.Previous != 0 {
+		t.Errorf("first topic = %+v, want emerging 2/0", emerging)
+	}
+	if fading.Trend != Fading || fading.Current != 0 || fading.Previous != 2 {
+		t.Errorf("second topic = %+v, want fading 0/2", fading)
+	}
+	if emerging.Keywords[0] != "shipping" {
+		t.Errorf("emerging keywords = %v, want shipping first", emerging.Keywords)
+	}
+	if len(emerging.Representatives) != 2 {
+		t.Errorf("got %d representatives, want 2", len(emerging.Representatives))
+	}
+}
+
+func TestTrend(t *testing.T) {
+	cases := []struct {
+		current, previous int
+		want              string
+	}{
+		{3, 0, Emerging},
+		{4, 2, Emerging},
+		{3, 2, Steady},
+		{1, 1, Steady},
+		{1, 2, Fading},
+		{0, 3, Fading},
+	}
+	for _, c := range cases {
+		if got := Trend(c.current, c.previous); got != c.want {
+			t.Errorf("Trend(%d, %d) = %s, want %s", c.current, c.previous, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package wald reads WALD.yaml, the manifest at the root of a werk
+// workspace that lists its directories, their purposes and the beats they
+// claim.
+package wald
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bierlingm/beats/internal/config"
+)
+
+// FileName is the manifest at the werk root.
+const FileName = "WALD.yaml"
+
+// Config is the WALD.yaml structure.
+type Config struct {
+	Directories []Directory `yaml:"directories"`
+}
+
+// Directory is a directory entry in WALD.yaml.
+type Directory struct {
+	Path    string  `yaml:"path"` // Relative to the werk root
+	Purpose string  `yaml:"purpose"`
+	State   string  `yaml:"state"` // e.g. active, dormant, archived
+	Gravity string  `yaml:"gravity"`
+	Claims  *Claims `yaml:"claims,omitempty"`
+}
+
+// Claims are the beats a directory claims, by cluster, topic, keyword or
+// cooperator.
+type Claims struct {
+	Clusters    []string `yaml:"clusters,omitempty"`
+	Topics      []string `yaml:"topics,omitempty"`
+	Keywords    []string `yaml:"keywords,omitempty"`
+	Cooperators []string `yaml:"cooperators,omitempty"`
+}
+
+// Parse decodes a WALD.yaml document.
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Load reads WALD.yaml from the werk root.
+func Load(werkRoot string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Join(werkRoot, FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+	return cfg, nil
+}
+
+// FindRoot returns the nearest directory at or above dir that holds a
+// WALD.yaml, or "" if there is none.
+func FindRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, FileName)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Directory returns the entry for a path relative to the werk root, or nil.
+func (c *Config) Directory(path string) *Directory {
+	path = filepath.Clean(path)
+	for i := range c.Directories {
+		if filepath.Clean(c.Directories[i].Path) == path {
+			return &c.Directories[i]
+		}
+	}
+	return nil
+}
+
+// FindWerkRoot locates the werk root for a working directory: the nearest
+// WALD.yaml at or above cwd, else the configured root (BEATS_ROOT), else
+// ~/werk, when they hold one. Returns "" when none does.
+func FindWerkRoot(cwd string) string {
+	if cwd != "" {
+		if root := FindRoot(cwd); root != "" {
+			return root
+		}
+	}
+	candidates := []string{config.Get().Root}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, "werk"))
+	}
+	for _, root := range candidates {
+		if root == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, FileName)); err == nil {
+			return root
+		}
+	}
+	return ""
+}
+
+// Owner returns the most specific directory entry containing path, which is
+// relative to the werk root, or nil when no entry does.
+func (c *Config) Owner(path string) *Directory {
+	path = filepath.ToSlash(filepath.Clean(path))
+	var best *Directory
+	for i := range c.Directories {
+		d := &c.Directories[i]
+		dir := filepath.ToSlash(filepath.Clean(d.Path))
+		if dir == "." || dir == "" {
+			continue
+		}
+		if path == dir || strings.HasPrefix(path, dir+"/") {
+			if best == nil || len(dir) > len(filepath.ToSlash(filepath.Clean(best.Path))) {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// ResolveDir turns a --context argument into a WALD directory: a path on
+// disk inside the werk root, or a directory as listed in WALD.yaml.
+func ResolveDir(dir string) string {
+	if abs, err := filepath.Abs(config.ExpandHome(dir)); err == nil {
+		if info, err := os.Stat(abs); err == nil && info.IsDir() {
+			if root := FindRoot(abs); root != "" {
+				if rel, ok := RelativeTo(root, abs); ok {
+					return rel
+				}
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(dir))
+}
+
+// RelativeTo returns path relative to root, with forward slashes, when it
+// lies strictly inside root.
+func RelativeTo(root, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
@@ -0,0 +1,75 @@
+package wald
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	// Block and flow style, nested claims, comments and multi-line purposes
+	doc := `# werk manifest
+version: 2
+directories:
+  - path: projects/beats
+    purpose: "Narrative substrate: beats, beads and syntheses"
+    state: active
+    claims:
+      topics: [narrative, capture]
+      keywords:
+        - beat
+        - "bead: link"
+  - {path: cooperators/jane-doe, purpose: Coaching with Jane, state: active}
+  - path: archive/old
+    purpose: >
+      Folded purpose
+      over two lines
+    state: archived
+`
+	cfg, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Directories) != 3 {
+		t.Fatalf("directories = %+v, want 3", cfg.Directories)
+	}
+	beats := cfg.Directories[0]
+	if beats.Purpose != "Narrative substrate: beats, beads and syntheses" || beats.Claims == nil ||
+		len(beats.Claims.Topics) != 2 || beats.Claims.Keywords[1] != "bead: link" {
+		t.Errorf("beats = %+v", beats)
+	}
+	if d := cfg.Directory("cooperators/jane-doe/"); d == nil || d.Purpose != "Coaching with Jane" {
+		t.Errorf("Directory(cooperators/jane-doe) = %+v", d)
+	}
+	if d := cfg.Directories[2]; d.Purpose != "Folded purpose over two lines\n" || d.State != "archived" {
+		t.Errorf("archive = %+v", d)
+	}
+
+	if _, err := Parse([]byte("directories: [path: x")); err == nil {
+		t.Error("Parse accepted malformed YAML")
+	}
+}
+
+func TestFindRootAndLoad(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("directories:\n  - path: a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRoot(nested); got != root {
+		t.Errorf("FindRoot = %q, want %q", got, root)
+	}
+	cfg, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Directories) != 1 || cfg.Directories[0].Path != "a" {
+		t.Errorf("Load = %+v", cfg)
+	}
+	if _, err := Load(nested); err == nil {
+		t.Error("Load without a WALD.yaml succeeded")
+	}
+}
@@ -0,0 +1,333 @@
+// Package webhook maps arbitrary JSON payloads from automation services
+// (Zapier, IFTTT, n8n, ...) onto proposed beats using per-endpoint templates.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+)
+
+// ConfigFile holds webhook endpoints, relative to the beats directory.
+const ConfigFile = "webhooks.json"
+
+// Config maps endpoint names (the <name> in /capture/webhook/<name>) to their settings.
+type Config struct {
+	Webhooks map[string]Endpoint `json:"webhooks"`
+}
+
+// Endpoint configures one webhook. Content, Impetus, URL, ID, CreatedAt and
+// Meta values are templates: {{ $.path }} expressions are replaced with the
+// value at that JSONPath in the payload. Tags and Beads are plain JSONPaths
+// to a list or a comma separated string.
+type Endpoint struct {
+	Secret    string            `json:"secret"`               // Required shared secret
+	Content   string            `json:"content,omitempty"`    // Default: the payload as indented JSON
+	Impetus   string            `json:"impetus,omitempty"`    // Default: inferred from content
+	URL       string            `json:"url,omitempty"`        // Adds a url reference
+	ID        string            `json:"id,omitempty"`         // Stable item ID; repeated deliveries are ignored
+	CreatedAt string            `json:"created_at,omitempty"` // RFC3339 or YYYY-MM-DD; default now
+	Tags      string            `json:"tags,omitempty"`       // JSONPath to topic labels
+	Beads     string            `json:"beads,omitempty"`      // JSONPath to bead IDs to link
+	Meta      map[string]string `json:"meta,omitempty"`       // Extra impetus.meta fields
+}
+
+// LoadConfig reads webhooks.json. A missing file yields no endpoints.
+func LoadConfig(beatsDir string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(beatsDir, ConfigFile))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid %s: %w", ConfigFile, err)
+	}
+	return cfg, nil
+}
+
+// Verify checks a delivery against the endpoint secret. Services that can set
+// headers send it as X-Beats-Secret or a bearer token; those that cannot use
+// ?secret=; GitHub-style X-Hub-Signature-256 HMACs of the body are accepted too.
+func (e Endpoint) Verify(r *http.Request, body []byte) bool {
+	if e.Secret == "" {
+		return false // Never accept unauthenticated deliveries
+	}
+	if sig := r.Header.Get("X-Hub-Signature-256"); sig != "" {
+		mac := hmac.New(sha256.New, []byte(e.Secret))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(sig), []byte(want))
+	}
+	got := r.Header.Get("X-Beats-Secret")
+	if got == "" {
+		got = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if got == "" {
+		got = r.URL.Query().Get("secret")
+	}
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(e.Secret)) == 1
+}
+
+// Apply transforms a JSON payload into a proposed beat. The impetus label is
+// left empty when the endpoint has no impetus template, for the caller to infer.
+func (e Endpoint) Apply(payload interface{}) (*beat.ProposedBeat, error) {
+	content := strings.TrimSpace(Render(e.Content, payload))
+	if e.Content == "" {
+		pretty, _ := json.MarshalIndent(payload, "", "  ")
+		content = string(pretty)
+	}
+	if content == "" {
+		return nil, fmt.Errorf("mapping produced empty content")
+	}
+
+	meta := map[string]string{}
+	for k, tmpl := range e.Meta {
+		if v := strings.TrimSpace(Render(tmpl, payload)); v != "" {
+			meta[k] = v
+		}
+	}
+	if id := strings.TrimSpace(Render(e.ID, payload)); id != "" {
+		meta["source_id"] = id
+	}
+
+	p := &beat.ProposedBeat{
+		Content:     content,
+		Impetus:     beat.Impetus{Label: strings.TrimSpace(Render(e.Impetus, payload)), Meta: meta},
+		References:  []beat.Reference{},
+		Entities:    []beat.Entity{},
+		LinkedBeads: []string{},
+	}
+
+	if u := strings.TrimSpace(Render(e.URL, payload)); u != "" {
+		p.Impetus.Raw = u
+		p.References = append(p.References, beat.Reference{Kind: "url", Locator: u})
+	}
+	if raw := strings.TrimSpace(Render(e.CreatedAt, payload)); raw != "" {
+		t, err := parseTime(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.CreatedAt = &t
+	}
+	for _, tag := range Strings(e.Tags, payload) {
+		p.Entities = append(p.Entities, beat.Entity{
+			Label:    strings.TrimPrefix(tag, "#"),
+			Category: "topic",
+			Meta:     map[string]string{"confidence": "1.0", "source": "tag"},
+		})
+	}
+	p.LinkedBeads = append(p.LinkedBeads, Strings(e.Beads, payload)...)
+	return p, nil
+}
+
+var timeFormats = []string{time.RFC3339, time.RFC1123Z, time.RFC1123, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+func parseTime(s string) (time.Time, error) {
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized created_at: %q", s)
+}
+
+var templateExpr = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+
+// Render replaces each {{ path }} in tmpl with the payload value at that
+// JSONPath; "a || b" falls back to b when a is missing or empty. Lists are
+// joined with ", " and objects rendered as JSON.
+func Render(tmpl string, payload interface{}) string {
+	return templateExpr.ReplaceAllStringFunc(tmpl, func(m string) string {
+		expr := templateExpr.FindStringSubmatch(m)[1]
+		for _, alt := range strings.Split(expr, "||") {
+			alt = strings.TrimSpace(alt)
+			if strings.HasPrefix(alt, `"`) && strings.HasSuffix(alt, `"`) && len(alt) >= 2 {
+				return alt[1 : len(alt)-1] // Literal fallback
+			}
+			if v, ok := Lookup(payload, alt); ok {
+				if s := stringify(v); s != "" {
+					return s
+				}
+			}
+		}
+		return ""
+	})
+}
+
+// Strings evaluates a JSONPath to a list of strings; a string value is split
+// on commas.
+func Strings(path string, payload interface{}) []string {
+	if path == "" {
+		return nil
+	}
+	v, ok := Lookup(payload, path)
+	if !ok {
+		return nil
+	}
+	var out []string
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if s := strings.TrimSpace(stringify(item)); s != "" {
+				out = append(out, s)
+			}
+		}
+	default:
+		for _, s := range strings.Split(stringify(val), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func stringify(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64, bool:
+		b, _ := json.Marshal(val)
+		return string(b)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := stringify(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+// Lookup evaluates a JSONPath subset: $ (root), .key, ['key'], [n], [-n] and
+// [*] / .* (which collect values from every element).
+func Lookup(payload interface{}, path string) (interface{}, bool) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, false
+	}
+	steps, err := parsePath(path[1:])
+	if err != nil {
+		return nil, false
+	}
+
+	current := []interface{}{payload}
+	wildcard := false
+	for _, step := range steps {
+		var next []interface{}
+		for _, v := range current {
+			switch {
+			case step.wildcard:
+				switch val := v.(type) {
+				case []interface{}:
+					next = append(next, val...)
+				case map[string]interface{}:
+					for _, item := range val {
+						next = append(next, item)
+					}
+				}
+			case step.isIndex:
+				if arr, ok := v.([]interface{}); ok {
+					i := step.index
+					if i < 0 {
+						i += len(arr)
+					}
+					if i >= 0 && i < len(arr) {
+						next = append(next, arr[i])
+					}
+				}
+			default:
+				if obj, ok := v.(map[string]interface{}); ok {
+					if item, ok := obj[step.key]; ok {
+						next = append(next, item)
+					}
+				}
+			}
+		}
+		if step.wildcard {
+			wildcard = true
+		}
+		current = next
+	}
+
+	if wildcard {
+		return current, len(current) > 0
+	}
+	if len(current) != 1 {
+		return nil, false
+	}
+	return current[0], true
+}
+
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func parsePath(s string) ([]pathStep, error) {
+	var steps []pathStep
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			key := s[:end]
+			s = s[end:]
+			if key == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+			if key == "*" {
+				steps = append(steps, pathStep{wildcard: true})
+			} else {
+				steps = append(steps, pathStep{key: key})
+			}
+		case '[':
+			end := strings.Index(s, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [")
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"'):
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				var n int
+				if _, err := fmt.Sscanf(inner, "%d", &n); err != nil {
+					return nil, fmt.Errorf("invalid index %q", inner)
+				}
+				steps = append(steps, pathStep{index: n, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q", s[0])
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestLookup(t *testing.T) {
+	payload := decode(t, `{"item": {"title": "Hi", "tags": ["a", "b"], "links": [{"href": "x"}, {"href": "y"}]}, "odd.key": 3}`)
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"$.item.title", "Hi", true},
+		{"$['item']['title']", "Hi", true},
+		{"$.item.tags[1]", "b", true},
+		{"$.item.tags[-1]", "b", true},
+		{"$.item.links[*].href", "x, y", true},
+		{"$['odd.key']", "3", true},
+		{"$.item.missing", "", false},
+		{"item.title", "", false},
+	}
+	for _, tt := range tests {
+		v, ok := Lookup(payload, tt.path)
+		if ok != tt.ok || stringify(v) != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tt.path, stringify(v), ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	payload := decode(t, `{"title": "Read later", "note": "", "n": 2}`)
+	got := Render(`{{ $.title }} ({{$.n}}) {{ $.note || $.missing || "no note" }}`, payload)
+	if got != "Read later (2) no note" {
+		t.Errorf("Render = %q", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	e := Endpoint{
+		Secret:    "s",
+		Content:   "{{ $.entry.title }}\n\n{{ $.entry.summary }}",
+		Impetus:   "Feed item",
+		URL:       "{{ $.entry.link }}",
+		ID:        "{{ $.entry.id }}",
+		CreatedAt: "{{ $.entry.published }}",
+		Tags:      "$.entry.categories",
+		Beads:     "$.bead",
+		Meta:      map[string]string{"feed": "{{ $.feed }}"},
+	}
+	payload := decode(t, `{"feed": "Blog", "bead": "bd-1, bd-2", "entry": {"id": "e1", "title": "Post", "summary": "Body",
+		"link": "https://example.com/p", "published": "2024-05-01T08:00:00Z", "categories": ["#go", "design"]}}`)
+
+	p, err := e.Apply(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Content != "Post\n\nBody" || p.Impetus.Label != "Feed item" || p.Impetus.Raw != "https://example.com/p" {
+		t.Errorf("beat = %+v", p)
+	}
+	if p.Impetus.Meta["source_id"] != "e1" || p.Impetus.Meta["feed"] != "Blog" {
+		t.Errorf("meta = %v", p.Impetus.Meta)
+	}
+	if p.CreatedAt == nil || p.CreatedAt.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("created_at = %v", p.CreatedAt)
+	}
+	if len(p.Entities) != 2 || p.Entities[0].Label != "go" || p.Entities[0].Category != "topic" {
+		t.Errorf("entities = %+v", p.Entities)
+	}
+	if strings.Join(p.LinkedBeads, " ") != "bd-1 bd-2" || len(p.References) != 1 {
+		t.Errorf("beads = %v, refs = %+v", p.LinkedBeads, p.References)
+	}
+
+	if _, err := (Endpoint{Content: "{{ $.nothing }}"}).Apply(payload); err == nil {
+		t.Error("Apply with empty content succeeded, want error")
+	}
+	raw, err := (Endpoint{}).Apply(decode(t, `{"a": 1}`))
+	if err != nil || raw.Content != "{\n  \"a\": 1\n}" {
+		t.Errorf("default content = %q, %v", raw.Content, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	e := Endpoint{Secret: "s3cret"}
+	body := []byte(`{"a":1}`)
+
+	r := httptest.NewRequest("POST", "/capture/webhook/x?secret=s3cret", nil)
+	if !e.Verify(r, body) {
+		t.Error("query secret rejected")
+	}
+	r = httptest.NewRequest("POST", "/capture/webhook/x", nil)
+	r.Header.Set("X-Beats-Secret", "wrong")
+	if e.Verify(r, body) {
+		t.Error("wrong secret accepted")
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	r = httptest.NewRequest("POST", "/capture/webhook/x", nil)
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	if !e.Verify(r, body) {
+		t.Error("valid signature rejected")
+	}
+	if e.Verify(r, []byte(`{"a":2}`)) {
+		t.Error("signature over a different body accepted")
+	}
+
+	r = httptest.NewRequest("POST", "/capture/webhook/x", nil)
+	if (Endpoint{}).Verify(r, body) {
+		t.Error("endpoint without secret accepted a delivery")
+	}
+}
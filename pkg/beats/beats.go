@@ -0,0 +1,157 @@
+// Package beats lets other Go programs read and write a beats store
+// directly instead of running bt and parsing its JSON.
+//
+// A Store is a .beats directory. Reads go straight to its files; Commit runs
+// the same capture pipeline as bt add (hooks, quota, secrets, enrichment), so
+// beats written here are indistinguishable from ones captured by the CLI
+// apart from their "api" channel. Settings come from the same places as for
+// bt: BEATS_* variables, then the user config file.
+//
+//	s, err := beats.Open("")
+//	if err != nil {
+//		return err
+//	}
+//	results, err := s.Search("queue sharding", 10)
+//
+// The types are the ones beats.jsonl is written in, so their JSON matches
+// the store and the --robot commands.
+package beats
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bierlingm/beats/internal/beat"
+	"github.com/bierlingm/beats/internal/brief"
+	"github.com/bierlingm/beats/internal/hooks"
+	"github.com/bierlingm/beats/internal/pipeline"
+	"github.com/bierlingm/beats/internal/redact"
+	"github.com/bierlingm/beats/internal/store"
+)
+
+// Beat types, as stored in beats.jsonl.
+type (
+	Beat         = beat.Beat
+	Impetus      = beat.Impetus
+	Reference    = beat.Reference
+	Entity       = beat.Entity
+	Relation     = beat.Relation
+	BeadLink     = beat.BeadLink
+	BeadLinks    = beat.BeadLinks
+	Annotation   = beat.Annotation
+	Context      = beat.Context
+	ProposedBeat = beat.ProposedBeat
+	SearchResult = beat.SearchResult
+)
+
+// BriefRequest and Brief are the input and output of --robot-brief;
+// RedactionInfo is what a brief reports about its redaction.
+type (
+	BriefRequest  = brief.Input
+	Brief         = brief.Output
+	RedactionInfo = redact.Info
+)
+
+// Errors Commit returns when a beat is not stored.
+type (
+	PendingError   = pipeline.PendingError   // Held for review; see PendingBeat
+	SecretsError   = pipeline.SecretsError   // The secrets hook blocked it
+	DuplicateError = pipeline.DuplicateError // The duplicates hook rejected it
+	PreCommitError = hooks.PreCommitError    // The pre_commit script rejected it
+)
+
+// PendingBeat is a capture held for review, as bt review --pending lists it.
+type PendingBeat = pipeline.PendingBeat
+
+// AmbiguousIDError is returned by Get when a short ID matches several beats.
+type AmbiguousIDError = store.AmbiguousIDError
+
+// Store is an open beats store.
+type Store struct {
+	s *store.JSONLStore
+}
+
+// Open opens the store in dir, a .beats directory, or the configured store
+// (BEATS_DIR, else "store" in the config file) when dir is "". The
+// directory must exist; bt init creates one.
+func Open(dir string) (*Store, error) {
+	if dir == "" {
+		var err error
+		if dir, err = store.GetBeatsDir(); err != nil {
+			return nil, err
+		}
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no beats store at %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("no beats store at %s: not a directory", dir)
+	}
+	s, err := store.NewJSONLStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{s: s}, nil
+}
+
+// Dir returns the store's .beats directory.
+func (s *Store) Dir() string {
+	return s.s.Dir()
+}
+
+// All returns every beat, oldest first.
+func (s *Store) All() ([]Beat, error) {
+	return s.s.ReadAll()
+}
+
+// Get returns the beat ref names: a full ID, an unambiguous part of one, or
+// @N for the Nth newest beat.
+func (s *Store) Get(ref string) (*Beat, error) {
+	return s.s.Get(ref)
+}
+
+// Changes are the beats created, modified and newly linked to a bead since
+// a time.
+type Changes struct {
+	New      []Beat
+	Modified []Beat
+	Linked   []Beat
+}
+
+// Since returns what changed in the store since t.
+func (s *Store) Since(t time.Time) (*Changes, error) {
+	created, modified, linked, err := s.s.GetSince(t)
+	if err != nil {
+		return nil, err
+	}
+	return &Changes{New: created, Modified: modified, Linked: linked}, nil
+}
+
+// LinkedTo returns the beats linked to a bead.
+func (s *Store) LinkedTo(beadID string) ([]Beat, error) {
+	return s.s.GetByLinkedBead(beadID)
+}
+
+// Search runs the keyword search bt search does, weighted by the store's
+// scoring.json, and returns at most limit results, best first (all of them
+// when limit is 0).
+func (s *Store) Search(query string, limit int) ([]SearchResult, error) {
+	return s.s.Search(query, limit)
+}
+
+// Brief gathers the beats on a topic and renders the synthesis prompt for
+// them, as --robot-brief does.
+func (s *Store) Brief(req BriefRequest) (*Brief, error) {
+	return brief.Build(s.s, req)
+}
+
+// Commit stores a proposed beat through the capture pipeline. The warnings
+// are what did not stop the capture, such as possible secrets or
+// duplicates. A capture held for review returns a *PendingError; one the
+// hooks reject, a *SecretsError, *DuplicateError or *PreCommitError.
+func (s *Store) Commit(ctx context.Context, p *ProposedBeat) (*Beat, []string, error) {
+	return pipeline.Commit(ctx, s.s, p, beat.ChannelAPI)
+}
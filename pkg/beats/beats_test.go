@@ -0,0 +1,61 @@
+package beats_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bierlingm/beats/pkg/beats"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv("BEATS_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv("BEATS_OLLAMA_URL", "http://127.0.0.1:1")
+	dir := t.TempDir()
+	t.Setenv("BEATS_DIR", dir)
+
+	if _, err := beats.Open(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Open(missing) succeeded, want an error")
+	}
+	s, err := beats.Open("")
+	if err != nil || s.Dir() != dir {
+		t.Fatalf("Open(\"\") = %v, %v; want the BEATS_DIR store", s, err)
+	}
+
+	b, _, err := s.Commit(context.Background(), &beats.ProposedBeat{
+		Content:     "Shard the job queue by tenant",
+		Impetus:     beats.Impetus{Label: "Design"},
+		LinkedBeads: []string{"bd-12"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Channel() != "api" {
+		t.Errorf("channel = %q, want api", b.Channel())
+	}
+
+	got, err := s.Get("@1")
+	if err != nil || got.Content != b.Content {
+		t.Fatalf("Get(@1) = %+v, %v", got, err)
+	}
+	var links beats.BeadLinks = got.LinkedBeads
+	if ids := links.IDs(); len(ids) != 1 || ids[0] != "bd-12" {
+		t.Errorf("linked beads = %v", ids)
+	}
+	results, err := s.Search("queue", 5)
+	if err != nil || len(results) != 1 || results[0].ID != b.ID {
+		t.Errorf("Search = %+v, %v", results, err)
+	}
+	linked, err := s.LinkedTo("bd-12")
+	if err != nil || len(linked) != 1 {
+		t.Errorf("LinkedTo = %+v, %v", linked, err)
+	}
+	changes, err := s.Since(b.CreatedAt.Add(-1))
+	if err != nil || len(changes.New) != 1 {
+		t.Errorf("Since = %+v, %v", changes, err)
+	}
+	brief, err := s.Brief(beats.BriefRequest{Topic: "queue"})
+	if err != nil || len(brief.BeatsUsed) != 1 || brief.BriefPrompt == "" {
+		t.Errorf("Brief = %+v, %v", brief, err)
+	}
+}